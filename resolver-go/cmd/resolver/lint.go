@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"gopkg.in/yaml.v3"
+)
+
+// runLint implements `resolver lint [flags] <path>...`: load the catalogs
+// without starting the server and print the lint report. It returns the exit
+// status: 0 when the catalog is valid, 1 when it has errors (or warnings with
+// -strict), 2 on bad usage.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "text", "Report format: text or json")
+	conflictPolicy := fs.String("conflict-policy", "error", "How to resolve paths defined in several catalogs: error, first-wins, last-wins, merge-fields")
+	strict := fs.Bool("strict", false, "Exit non-zero on warnings as well as errors")
+	csvDefaultsPath := fs.String("csv-defaults", "", "YAML node definition (e.g. ownership, classification) applied under every row of .csv and .xlsx catalogs")
+	overlay := fs.String("overlay", "", "Environment overlay file applied on top of the merged catalogs")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: resolver lint [flags] <catalog file, directory, URL, .csv or .xlsx>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format %q: expected text or json\n", *format)
+		return 2
+	}
+	policy, err := catalog.ParseConflictPolicy(*conflictPolicy)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	var csvDefaults *catalog.CatalogNodeYAML
+	if *csvDefaultsPath != "" {
+		data, err := os.ReadFile(*csvDefaultsPath)
+		if err == nil {
+			err = yaml.Unmarshal(data, &csvDefaults)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "read CSV defaults: %v\n", err)
+			return 2
+		}
+	}
+
+	var report *catalog.LintReport
+	nodes, err := loadLintCatalogs(policy, csvDefaults, fs.Args())
+	if err == nil && *overlay != "" {
+		nodes, err = catalog.ApplyOverlayFile(nodes, *overlay)
+	}
+	if err != nil {
+		report = catalog.LintLoadError(err)
+	} else {
+		report = catalog.Lint(nodes)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if !report.Valid || (*strict && report.Warnings > 0) {
+		return 1
+	}
+	return 0
+}
+
+// loadLintCatalogs is catalog.LoadCatalogs with .csv and .xlsx files loaded
+// through catalog.LoadCSVFile and catalog.LoadXLSXFile. Unresolved conflicts
+// are listed in the error.
+func loadLintCatalogs(policy catalog.ConflictPolicy, csvDefaults *catalog.CatalogNodeYAML, paths []string) ([]*catalog.CatalogNode, error) {
+	catalogs := make([][]*catalog.CatalogNode, 0, len(paths))
+	for _, p := range paths {
+		var nodes []*catalog.CatalogNode
+		var err error
+		switch ext := filepath.Ext(p); {
+		case strings.EqualFold(ext, ".csv"):
+			nodes, err = catalog.LoadCSVFile(p, csvDefaults)
+		case strings.EqualFold(ext, ".xlsx"):
+			nodes, err = catalog.LoadXLSXFile(p, csvDefaults)
+		default:
+			nodes, err = catalog.Load(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		catalogs = append(catalogs, nodes)
+	}
+
+	nodes, conflicts, err := catalog.Merge(policy, catalogs...)
+	if err != nil {
+		where := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			for _, idx := range c.Catalogs {
+				c.Sources = append(c.Sources, paths[idx])
+			}
+			where[i] = c.String()
+		}
+		return nil, fmt.Errorf("%w: %s", err, strings.Join(where, "; "))
+	}
+	return nodes, nil
+}
@@ -2,28 +2,46 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters/sqladapter"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/auth"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/handlers"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/metrics"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/ratelimit"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/telemetry"
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Parse command-line flags
 	configPath := flag.String("config", "../config.yaml", "Path to config file")
 	port := flag.Int("port", 0, "Port to listen on (overrides config)")
+	var catalogFlags stringList
+	flag.Var(&catalogFlags, "catalog", "Catalog file or directory to load (repeatable; overrides config)")
+	conflictPolicy := flag.String("conflict-policy", "", "How to resolve paths defined in several catalogs: error, first-wins, last-wins, merge-fields (overrides config)")
+	strictCatalog := flag.Bool("strict-catalog", true, "Reject catalogs containing unknown YAML keys (set to false to only warn)")
+	env := flag.String("env", "", "Environment whose catalog overlay (catalog.overlays) to apply (overrides config)")
 	flag.Parse()
 
 	// Load configuration
@@ -48,9 +66,56 @@ func main() {
 	log.Printf("==============================================")
 	log.Printf("  %s - Go Resolver", cfg.ProjectName)
 	log.Printf("  Port: %d", cfg.Server.Port)
-	log.Printf("  Catalog: %s", cfg.Catalog.DefinitionFile)
+	// Resolve catalog paths: --catalog flags win over config
+	catalogPaths := []string(catalogFlags)
+	if len(catalogPaths) == 0 {
+		for _, p := range append([]string{cfg.Catalog.DefinitionFile}, cfg.Catalog.DefinitionFiles...) {
+			if p != "" {
+				catalogPaths = append(catalogPaths, resolveConfigPath(p))
+			}
+		}
+	}
+	if *conflictPolicy != "" {
+		cfg.Catalog.ConflictPolicy = *conflictPolicy
+	}
+	policy, err := catalog.ParseConflictPolicy(cfg.Catalog.ConflictPolicy)
+	if err != nil {
+		log.Fatalf("Invalid catalog config: %v", err)
+	}
+
+	if *env != "" {
+		cfg.Catalog.Env = *env
+	}
+	overlayPath := ""
+	if cfg.Catalog.Env != "" {
+		p, ok := cfg.Catalog.Overlays[cfg.Catalog.Env]
+		if !ok {
+			log.Fatalf("Invalid catalog config: no overlay configured for env %q", cfg.Catalog.Env)
+		}
+		overlayPath = resolveConfigPath(p)
+	}
+
+	log.Printf("  Catalog: %s", strings.Join(catalogPaths, ", "))
+	if overlayPath != "" {
+		log.Printf("  Env: %s (overlay %s)", cfg.Catalog.Env, overlayPath)
+	}
 	log.Printf("==============================================")
 
+	sunsetWarningDays := cfg.Deprecation.SunsetWarningDays
+	if sunsetWarningDays <= 0 {
+		sunsetWarningDays = 30
+	}
+	sunsetWindow := time.Duration(sunsetWarningDays) * 24 * time.Hour
+
+	ownerPattern := cfg.Governance.OwnerPattern
+	if ownerPattern == "" {
+		ownerPattern = catalog.DefaultOwnerPattern
+	}
+	ownerRegexp, err := regexp.Compile(ownerPattern)
+	if err != nil {
+		log.Fatalf("Invalid governance config: owner_pattern: %v", err)
+	}
+
 	// Initialize components
 	registry := catalog.NewRegistry()
 	cacheInst := cache.NewInMemory(time.Duration(cfg.Cache.DefaultTTLSeconds) * time.Second)
@@ -60,45 +125,274 @@ func main() {
 		cacheInst.StartCleanup(1 * time.Minute)
 	}
 
-	// Load catalog from YAML
-	catalogPath := cfg.Catalog.DefinitionFile
-	// If relative path, resolve relative to config file location (repo root)
-	if !strings.HasPrefix(catalogPath, "/") {
-		// Strip leading ./ if present
-		catalogPath = strings.TrimPrefix(catalogPath, "./")
-		// Make relative to config file (../catalogPath from resolver-go/)
-		catalogPath = "../" + catalogPath
+	// Remote catalogs (http/https URLs) authenticate with an optional bearer token
+	if cfg.Catalog.RemoteToken != "" {
+		fetcher := &catalog.HTTPFetcher{BearerToken: cfg.Catalog.RemoteToken}
+		catalog.DefaultRemoteLoader.SetFetcher("http", fetcher)
+		catalog.DefaultRemoteLoader.SetFetcher("https", fetcher)
 	}
 
-	nodes, err := catalog.LoadCatalog(catalogPath)
+	// Load and merge catalogs from YAML
+	nodes, conflicts, err := catalog.LoadCatalogs(policy, catalogPaths...)
+	for _, c := range conflicts {
+		log.Printf("Catalog conflict [%s]: %s", policy, c)
+	}
+	if err == nil && overlayPath != "" {
+		nodes, err = catalog.ApplyOverlayFile(nodes, overlayPath)
+	}
+	if err == nil {
+		if err := checkUnknownFields(nodes, *strictCatalog); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 	if err != nil {
+		// A catalog that fails its manifest was not approved; never serve around it
+		var manifestErr *catalog.ManifestError
+		if errors.As(err, &manifestErr) {
+			log.Fatalf("Refusing to start: %v", err)
+		}
+		// A container fetching its catalog remotely has nothing to fall back on
+		for _, p := range catalogPaths {
+			if catalog.IsRemoteCatalog(p) {
+				log.Fatalf("Failed to load catalog: %v", err)
+			}
+		}
 		log.Printf("Warning: Failed to load catalog: %v - running with empty catalog", err)
 	} else {
 		registry.RegisterMany(nodes)
 		log.Printf("Loaded %d catalog nodes", len(nodes))
+
+		// Validate successor chains
+		for _, issue := range registry.ValidateSuccessors() {
+			log.Printf("Catalog validation [%s]: %s", issue.Kind, issue.Message)
+		}
+
+		// Validate foreign keys and related monikers (fatal in strict mode)
+		brokenRefs := 0
+		for _, issue := range registry.ValidateReferences() {
+			log.Printf("Catalog validation [%s/%s]: %s", issue.Severity, issue.Kind, issue.Message)
+			if issue.Severity == catalog.SeverityError {
+				brokenRefs++
+			}
+		}
+		if brokenRefs > 0 && cfg.Catalog.Strict {
+			log.Fatalf("Catalog has %d broken references (strict mode)", brokenRefs)
+		}
+
+		// Report nodes with no owned ancestor (fatal in strict mode)
+		hierarchyIssues := registry.ValidateHierarchy()
+		for _, issue := range hierarchyIssues {
+			log.Printf("Catalog validation [%s]: %s", issue.Kind, issue.Message)
+		}
+		if len(hierarchyIssues) > 0 && cfg.Catalog.Strict {
+			log.Fatalf("Catalog has %d nodes with dangling parents (strict mode)", len(hierarchyIssues))
+		}
+
+		// Report malformed and expired sunset deadlines
+		for _, issue := range registry.ValidateSunsets() {
+			log.Printf("Catalog validation [sunset]: %s", issue.Message)
+		}
+		now := time.Now().UTC()
+		for _, node := range registry.ExpiredSunsets(now) {
+			log.Printf("Warning: %s is past its sunset deadline (%s)", node.Path, *node.SunsetDeadline)
+		}
+		for _, node := range registry.ExpiringSunsets(now, sunsetWindow) {
+			log.Printf("Sunset approaching: %s (%s)", node.Path, *node.SunsetDeadline)
+		}
+	}
+
+	// Hot reload: watch catalog files, poll on the reload interval, and accept
+	// POST /admin/reload. Rejected catalogs leave the live catalog in place.
+	reloader := catalog.NewReloadManager(registry, policy, catalogPaths...)
+	reloader.Overlay = overlayPath
+	reloader.StrictKeys = *strictCatalog
+	reloader.Strict = cfg.Catalog.Strict
+	reloader.PollInterval = time.Duration(cfg.Catalog.ReloadIntervalSeconds) * time.Second
+	reloader.OnReload = func(diff *catalog.CatalogDiff, err error) {
+		if err != nil {
+			log.Printf("ERROR: Catalog reload rejected: %v - keeping current catalog", err)
+			return
+		}
+		if !diff.IsEmpty() {
+			log.Printf("Catalog reloaded: %s", diff.Summary())
+			for _, p := range diff.BreakingChanges() {
+				log.Printf("  contract change: %s", p)
+			}
+		}
+	}
+	if err == nil {
+		reloader.Loaded(nodes)
+		for _, m := range reloader.Status().Manifests {
+			log.Printf("Catalog verified against %s (sha256 %s)", m.Path, m.Digest)
+		}
+		log.Printf("Catalog version: %s", registry.Version())
 	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		if err := reloader.Watch(watchCtx); err != nil {
+			log.Printf("Warning: Catalog hot reload disabled: %v", err)
+		}
+	}()
 
 	// Initialize telemetry
+	if p, ok := cfg.Telemetry.SinkConfig["path"].(string); ok && p != "" {
+		cfg.Telemetry.SinkConfig["path"] = resolveConfigPath(p)
+	}
+	sinkType := cfg.Telemetry.SinkType
+	if sinkType == "" {
+		sinkType = telemetry.SinkTypeMemory
+	}
 	emitter, err := telemetry.NewFromConfig(&cfg.Telemetry)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize telemetry: %v", err)
-		emitter = telemetry.NewNoOpEmitter()
+		log.Printf("Warning: Failed to initialize telemetry, keeping events in memory: %v", err)
+		emitter = telemetry.NewRecorder(telemetry.NewRingSink(cfg.Telemetry.MaxQueueSize))
+		sinkType = telemetry.SinkTypeMemory
 	}
 	defer emitter.Stop()
 
 	if cfg.Telemetry.Enabled {
 		log.Printf("Telemetry enabled: sink=%s, batch_size=%d, flush_interval=%.3fs",
-			cfg.Telemetry.SinkType, cfg.Telemetry.BatchSize, cfg.Telemetry.FlushIntervalSeconds)
+			sinkType, cfg.Telemetry.BatchSize, cfg.Telemetry.FlushIntervalSeconds)
 	}
 
 	// Create service
 	svc := service.NewMonikerService(registry, cacheInst, cfg)
+	sqladapter.RegisterAvailable(svc.Adapters())
+	defer func() {
+		if err := svc.Adapters().Close(); err != nil {
+			log.Printf("Warning: Closing data adapters: %v", err)
+		}
+	}()
+	// Close the connection pools of bindings removed from the catalog, and
+	// drop the fetched data of a reloaded one
+	changes, stopChanges := registry.Watch()
+	defer stopChanges()
+	go func() {
+		for event := range changes {
+			if event.Kind == catalog.ChangeBatch {
+				svc.InvalidateData()
+			}
+			if err := svc.Adapters().Release(event.RemovedPaths()...); err != nil {
+				log.Printf("Warning: Closing the connections of removed bindings: %v", err)
+			}
+		}
+	}()
+	resolveMetrics := metrics.NewRegistry()
+	svc.SetMetrics(resolveMetrics)
+
+	if cfg.Audit.Enabled {
+		auditor, err := service.NewFileAuditor(resolveConfigPath(cfg.Audit.File), cfg.Audit.BufferSize)
+		if err != nil {
+			log.Fatalf("Invalid audit config: %v", err)
+		}
+		defer func() {
+			if err := auditor.Close(); err != nil {
+				log.Printf("Warning: Closing audit log: %v", err)
+			}
+			if dropped := auditor.Dropped(); dropped > 0 {
+				log.Printf("Warning: %d audit events dropped (queue full)", dropped)
+			}
+		}()
+		svc.SetAuditor(auditor)
+		log.Printf("Access audit log: %s (sampling %.0f%% of allowed resolves)", cfg.Audit.File, cfg.Audit.SampleAllowed*100)
+	}
+
+	// Authentication: bearer JWTs when auth.okta is enabled, otherwise the
+	// X-User-ID header is trusted (the resolver runs behind a gateway)
+	var tokenValidator handlers.TokenValidator
+	if cfg.Auth.Enabled && cfg.Auth.Okta.Enabled {
+		okta := cfg.Auth.Okta
+		for _, key := range []*string{&okta.TestSecret, &okta.PublicKey} {
+			if service.IsSecretRef(*key) {
+				value, err := svc.Secrets().Resolve(*key)
+				if err != nil {
+					log.Fatalf("Invalid auth config: %v", err)
+				}
+				*key = value
+			}
+		}
+		jwtValidator, err := auth.NewJWTValidator(okta)
+		if err != nil {
+			log.Fatalf("Invalid auth config: %v", err)
+		}
+		tokenValidator = jwtValidator
+		log.Printf("JWT authentication enabled (issuer %q, enforce %t)", okta.Issuer, cfg.Auth.Enforce)
+	}
+	var apiKeys *auth.APIKeySet
+	if cfg.Auth.Enabled && cfg.Auth.APIKeys.Enabled {
+		keysCfg := cfg.Auth.APIKeys
+		if keysCfg.File != "" {
+			keysCfg.File = resolveConfigPath(keysCfg.File)
+		}
+		apiKeys, err = auth.LoadAPIKeys(keysCfg)
+		if err != nil {
+			log.Fatalf("Invalid auth config: %v", err)
+		}
+		log.Printf("API key authentication enabled (%d keys, enforce %t)", apiKeys.Len(), cfg.Auth.Enforce)
+	}
+
+	// Namespace catalogs, hot-reloaded like the default catalog
+	for name, nsPath := range cfg.Catalog.Namespaces {
+		if !moniker.ValidateNamespace(name) {
+			log.Fatalf("Invalid catalog config: %q is not a valid namespace name", name)
+		}
+		nsNodes, _, err := catalog.LoadCatalogs(policy, nsPath)
+		if err == nil {
+			err = checkUnknownFields(nsNodes, *strictCatalog)
+		}
+		if err != nil {
+			log.Fatalf("Failed to load catalog for namespace %s: %v", name, err)
+		}
+		nsRegistry := catalog.NewRegistry()
+		nsRegistry.RegisterMany(nsNodes)
+		log.Printf("Namespace %s@: %d catalog nodes from %s", name, len(nsNodes), nsPath)
+
+		nsReloader := catalog.NewReloadManager(nsRegistry, policy, nsPath)
+		nsReloader.StrictKeys = *strictCatalog
+		nsReloader.Strict = cfg.Catalog.Strict
+		nsReloader.PollInterval = reloader.PollInterval
+		nsReloader.OnReload = func(diff *catalog.CatalogDiff, err error) {
+			if err != nil {
+				log.Printf("ERROR: Namespace %s@ catalog reload rejected: %v - keeping current catalog", name, err)
+			} else if !diff.IsEmpty() {
+				log.Printf("Namespace %s@ catalog reloaded: %s", name, diff.Summary())
+			}
+		}
+		nsReloader.Loaded(nsNodes)
+		go func() {
+			if err := nsReloader.Watch(watchCtx); err != nil {
+				log.Printf("Warning: Namespace %s@ catalog hot reload disabled: %v", name, err)
+			}
+		}()
+		svc.SetNamespace(name, nsRegistry)
+	}
+
+	// Per-caller rate limiting of resolve and fetch routes
+	rateLimited := func(h http.Handler) http.Handler { return h }
+	if cfg.RateLimit.Enabled {
+		limiter := ratelimit.New(cfg.RateLimit)
+		proxies, err := handlers.ParseTrustedProxies(cfg.Server.TrustedProxies)
+		if err != nil {
+			log.Fatalf("Invalid server.trusted_proxies: %v", err)
+		}
+		rateLimited = func(h http.Handler) http.Handler { return handlers.NewRateLimitMiddleware(h, limiter, proxies) }
+		log.Printf("Rate limiting enabled (%d path overrides)", len(cfg.RateLimit.Overrides))
+	}
+
+	// Dependencies that must be reachable for GET /health/ready
+	probes, err := handlers.NewProbes(cfg.Health, svc.Secrets())
+	if err != nil {
+		log.Fatalf("Invalid health config: %v", err)
+	}
 
 	// Set up HTTP routes
-	mux := http.NewServeMux()
+	mux := handlers.NewMux()
 
 	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+	health := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		counts := registry.Count()
@@ -113,10 +407,11 @@ func main() {
 		fmt.Fprintf(w, `{
 			"status": "healthy",
 			"service": "%s",
-			"version": "0.1.0-beta",
+			"version": %q,
 			"catalog": {
 				"total_nodes": %d,
-				"active_nodes": %d
+				"active_nodes": %d,
+				"version": %q
 			},
 			"cache": {
 				"size": %d,
@@ -130,99 +425,82 @@ func main() {
 				"queue_depth": %d,
 				"drop_rate": %.2f
 			}
-		}`, cfg.ProjectName, counts["total"], counts["active"], cacheInst.Size(), cfg.Cache.Enabled,
+		}`, cfg.ProjectName, handlers.APIVersion, counts["total"], counts["active"], registry.Version(), cacheInst.Size(), cfg.Cache.Enabled,
 			cfg.Telemetry.Enabled, emitted, dropped, errors, queueDepth, dropRate)
 	})
 
-	// Resolution endpoints
-	resolveHandler := handlers.NewResolveHandler(svc)
-	describeHandler := handlers.NewDescribeHandler(svc)
-	listHandler := handlers.NewListHandler(svc)
-	lineageHandler := handlers.NewLineageHandler(svc, registry)
-
-	// Catalog endpoints
-	catalogListHandler := handlers.NewCatalogListHandler(svc, registry)
-	searchHandler := handlers.NewSearchCatalogHandler(registry)
-	statsHandler := handlers.NewCatalogStatsHandler(registry)
-	batchHandler := handlers.NewBatchResolveHandler(svc)
-	metadataHandler := handlers.NewMetadataHandler(svc, registry)
-	treeHandler := handlers.NewTreeHandler(registry)
-
-	// Admin endpoints
-	updateStatusHandler := handlers.NewUpdateStatusHandler(registry)
-	auditHandler := handlers.NewAuditLogHandler(registry)
-	fetchHandler := handlers.NewFetchDataHandler(registry)
-
-	// Cache endpoints
-	cacheStatusHandler := handlers.NewCacheStatusHandler()
-	refreshCacheHandler := handlers.NewRefreshCacheHandler(registry)
-
-	// Telemetry endpoints
-	telemetryHandler := handlers.NewTelemetryAccessHandler()
-
-	// UI endpoint
-	uiHandler := handlers.NewUIHandler()
-
-	// Register all routes
-	mux.Handle("/resolve/", resolveHandler)
-	mux.Handle("/describe/", describeHandler)
-	mux.Handle("/list/", listHandler)
-	mux.Handle("/lineage/", lineageHandler)
-
-	// Catalog routes
-	mux.Handle("/catalog/search", searchHandler)
-	mux.Handle("/catalog/stats", statsHandler)
-	mux.HandleFunc("/catalog", func(w http.ResponseWriter, r *http.Request) {
-		catalogListHandler.ServeHTTP(w, r)
-	})
-	mux.HandleFunc("/catalog/", func(w http.ResponseWriter, r *http.Request) {
-		// Route to specific handlers based on path
-		path := r.URL.Path
-		if strings.HasSuffix(path, "/status") && r.Method == "PUT" {
-			updateStatusHandler.ServeHTTP(w, r)
-		} else if strings.HasSuffix(path, "/audit") {
-			auditHandler.ServeHTTP(w, r)
-		} else {
-			catalogListHandler.ServeHTTP(w, r)
+	var draining atomic.Bool // Set once a shutdown signal arrives
+	routes := &handlers.Routes{
+		Title:        cfg.ProjectName,
+		Service:      svc,
+		Catalog:      registry,
+		Reloader:     reloader,
+		Cache:        cacheInst,
+		Metrics:      resolveMetrics,
+		OwnerPattern: ownerRegexp,
+		SunsetWindow: sunsetWindow,
+		TreeMaxDepth: cfg.Catalog.TreeMaxDepth,
+		TreeMaxNodes: cfg.Catalog.TreeMaxNodes,
+		Health:       health,
+		Probes:       probes,
+		RateLimit:    rateLimited,
+		Telemetry:    emitter,
+		Draining:     draining.Load,
+		CORS:         handlers.CORSPolicy{Origins: cfg.Server.CORSOrigins},
+		BodyLimits: handlers.BodyLimits{
+			Default:   cfg.Server.BodyLimits.DefaultBytes,
+			Batch:     cfg.Server.BodyLimits.BatchBytes,
+			Telemetry: cfg.Server.BodyLimits.TelemetryBytes,
+			Import:    cfg.Server.BodyLimits.ImportBytes,
+		},
+	}
+	routes.Register(mux)
+	if cfg.Server.LegacyErrors {
+		log.Println("Warning: server.legacy_errors is set; error responses use the old format, which the next release drops")
+	}
+	handlers.UseLegacyErrors(cfg.Server.LegacyErrors)
+
+	// /health and its liveness and readiness probes stay open; every other
+	// route is authenticated
+	root := http.NewServeMux()
+	root.Handle("/health", mux)
+	root.Handle("/health/", mux)
+	var protected http.Handler = mux
+	var adminMiddleware *handlers.AdminMiddleware
+	if len(cfg.Auth.AdminRoles) > 0 {
+		adminMiddleware, err = handlers.NewAdminMiddleware(mux, cfg.Auth.AdminRoles, svc)
+		if err != nil {
+			log.Fatalf("Invalid auth config: %v", err)
 		}
-	})
-
-	// Batch resolve
-	mux.Handle("/resolve/batch", batchHandler)
-
-	// Metadata and tree
-	mux.Handle("/metadata/", metadataHandler)
-	mux.Handle("/tree/", treeHandler)
-	mux.HandleFunc("/tree", func(w http.ResponseWriter, r *http.Request) {
-		treeHandler.ServeHTTP(w, r)
-	})
-
-	// Fetch data
-	mux.Handle("/fetch/", fetchHandler)
-
-	// Cache
-	mux.Handle("/cache/status", cacheStatusHandler)
-	mux.Handle("/cache/refresh/", refreshCacheHandler)
-
-	// Telemetry
-	mux.Handle("/telemetry/access", telemetryHandler)
-
-	// UI
-	mux.Handle("/ui", uiHandler)
+		protected = adminMiddleware
+		if tokenValidator == nil && apiKeys == nil {
+			log.Printf("Warning: auth.admin_roles checks roles from the %s header, which callers set themselves; enable auth to trust them", svc.RolesHeader())
+		}
+	}
+	authMiddleware := handlers.NewAuthMiddleware(protected, tokenValidator, cfg.Auth.Enforce, svc.RolesHeader())
+	if apiKeys != nil {
+		authMiddleware.WithAPIKeys(apiKeys)
+	}
+	if adminMiddleware != nil {
+		adminMiddleware.WithChallenge(authMiddleware.Challenge())
+	}
+	root.Handle("/", authMiddleware)
 
 	// Create server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	server := &http.Server{
-		Addr:         addr,
-		Handler:      mux,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Addr:              addr,
+		Handler:           handlers.RequestID(root),
+		ReadTimeout:       seconds(cfg.Server.ReadTimeoutSeconds, 30*time.Second),
+		ReadHeaderTimeout: seconds(cfg.Server.ReadHeaderTimeoutSeconds, 10*time.Second),
+		WriteTimeout:      seconds(cfg.Server.WriteTimeoutSeconds, 30*time.Second),
+		IdleTimeout:       seconds(cfg.Server.IdleTimeoutSeconds, 120*time.Second),
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting Go resolver on %s", addr)
+		log.Printf("Starting Go resolver on %s (read timeout %s, write timeout %s, idle timeout %s)",
+			addr, server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server error: %v", err)
 		}
@@ -233,15 +511,76 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	// Fail readiness first so load balancers stop routing here, and close
+	// connections as their requests finish
+	draining.Store(true)
+	server.SetKeepAlivesEnabled(false)
+	if delay := seconds(cfg.Server.ShutdownDelaySeconds, 0); delay > 0 {
+		log.Printf("Shutting down: not ready, still serving for %s", delay)
+		time.Sleep(delay)
+	}
 
-	// Graceful shutdown with 30s timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	grace := seconds(cfg.Server.ShutdownGraceSeconds, 30*time.Second)
+	log.Printf("Shutting down server, draining in-flight requests for up to %s...", grace)
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
 	defer cancel()
-
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Printf("Warning: Requests still running after %s, closing their connections: %v", grace, err)
+		server.Close()
 	}
 
+	// Stop the background work; the deferred closes then flush the audit log
+	// and telemetry sink and close the data adapters
+	stopWatch()
+	<-watchDone
+	cacheInst.StopCleanup()
+
 	log.Println("Server stopped")
 }
+
+// seconds converts a config value in seconds to a duration, or returns def
+// when it is not positive
+func seconds(v float64, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return time.Duration(v * float64(time.Second))
+}
+
+// stringList is a flag.Value collecting repeated string flags
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// checkUnknownFields logs every unknown catalog key. In strict mode any
+// unknown key is an error; otherwise the keys are ignored with a warning.
+func checkUnknownFields(nodes []*catalog.CatalogNode, strict bool) error {
+	unknown := catalog.UnknownFields(nodes)
+	for _, u := range unknown {
+		if strict {
+			log.Printf("Catalog validation [unknown-key]: %s", u)
+		} else {
+			log.Printf("Warning: ignoring unknown catalog key: %s", u)
+		}
+	}
+	if strict && len(unknown) > 0 {
+		return fmt.Errorf("catalog has %d unknown keys (strict mode; use --strict-catalog=false to ignore them)", len(unknown))
+	}
+	return nil
+}
+
+// resolveConfigPath resolves a path from config relative to the config file
+// location (repo root, one level above resolver-go/). URLs are returned unchanged.
+func resolveConfigPath(path string) string {
+	if strings.HasPrefix(path, "/") || catalog.IsRemoteCatalog(path) {
+		return path
+	}
+	return "../" + strings.TrimPrefix(path, "./")
+}
@@ -0,0 +1,360 @@
+// Package adapters fetches data for resolved monikers. An Adapter per source
+// type turns a Request, built by the service from a resolve result, into a
+// DataResult of uniformly typed rows.
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// Request is one fetch of a resolved moniker
+type Request struct {
+	Path        string   // Moniker path being fetched
+	BindingPath string   // Node whose source binding is used
+	Segments    []string // Path segments below BindingPath
+	Version     string   // date@ value of the moniker, such as 20260101 or all; empty without one
+
+	Binding *catalog.SourceBinding
+	// Config is the binding config with any sub-resource entry applied and
+	// secret references resolved
+	Config map[string]interface{}
+	// SecretKeys are the Config keys whose values came from secret://
+	// references, sorted
+	SecretKeys []string
+
+	Query      string  // Bound query, when the binding has one
+	ParamStyle string  // Bind marker style of Query
+	Params     []Param // Bind values of Query, in marker order
+	// Placeholders are the values of the placeholders in config.query, by
+	// name such as segments[2] or params.region, for adapters that
+	// substitute them into the template themselves
+	Placeholders map[string]string
+
+	Schema  *catalog.DataSchema // Columns and types of the node; nil when undeclared
+	MaxRows int                 // access_policy.max_rows_block; 0 is unlimited
+}
+
+// FromSecret reports whether the Config value of key came from a secret://
+// reference
+func (r *Request) FromSecret(key string) bool {
+	i := sort.SearchStrings(r.SecretKeys, key)
+	return i < len(r.SecretKeys) && r.SecretKeys[i] == key
+}
+
+// Param is a bind value of a query
+type Param struct {
+	Name  string
+	Value string
+}
+
+// DataResult is the uniform result of a fetch. Cells are string, int64,
+// float64, bool or nil.
+type DataResult struct {
+	Path       string   `json:"path"`
+	SourceType string   `json:"source_type"`
+	Columns    []string `json:"columns"`
+	// ColumnMetadata describes the columns the node schema declares, in
+	// Columns order; the service sets it after the adapter returns
+	ColumnMetadata []catalog.ColumnSchema   `json:"column_metadata,omitempty"`
+	Rows           []map[string]interface{} `json:"rows"`
+	RowCount       int                      `json:"row_count"`
+	Truncated      bool                     `json:"truncated,omitempty"` // Rows beyond MaxRows were dropped
+}
+
+// Adapter fetches the data of one source type
+type Adapter interface {
+	Fetch(ctx context.Context, req *Request) (*DataResult, error)
+}
+
+// SelfCaching is implemented by adapters that cache the data they read and
+// check it against its source, which the service's data cache would hide:
+// Excel reads a changed workbook again before its TTL passes
+type SelfCaching interface {
+	CachesData() bool
+}
+
+// RequestError is a fetch the moniker itself makes invalid, such as more
+// segments than the binding has key columns
+type RequestError struct {
+	Path    string
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Registry holds an Adapter per source type
+type Registry struct {
+	mu       sync.RWMutex
+	adapters map[catalog.SourceType]Adapter
+}
+
+// NewRegistry creates a registry with the adapters that need no driver:
+// static, excel and opensearch. Others, such as the relational adapters of
+// package sqladapter, are registered with Register.
+func NewRegistry() *Registry {
+	return &Registry{
+		adapters: map[catalog.SourceType]Adapter{
+			catalog.SourceTypeStatic:     Static{},
+			catalog.SourceTypeExcel:      NewExcel(),
+			catalog.SourceTypeOpenSearch: NewOpenSearch(),
+		},
+	}
+}
+
+// Register sets the adapter for a source type, replacing any existing one;
+// nil removes it
+func (r *Registry) Register(sourceType catalog.SourceType, a Adapter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if a == nil {
+		delete(r.adapters, sourceType)
+		return
+	}
+	r.adapters[sourceType] = a
+}
+
+// Get returns the adapter for a source type
+func (r *Registry) Get(sourceType catalog.SourceType) (Adapter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.adapters[sourceType]
+	return a, ok
+}
+
+// Close closes the adapters holding connections, such as SQL pools
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for _, a := range r.adapters {
+		if c, ok := a.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BindingReleaser is implemented by adapters holding resources per binding,
+// such as the connection pools of package sqladapter, which they free when
+// the binding is removed from the catalog
+type BindingReleaser interface {
+	Release(bindingPath string) error
+}
+
+// Release frees what every adapter holds for the bindings at paths
+func (r *Registry) Release(paths ...string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []error
+	for _, a := range r.adapters {
+		if releaser, ok := a.(BindingReleaser); ok {
+			for _, p := range paths {
+				errs = append(errs, releaser.Release(p))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// IsAll reports whether a segment is the ALL keyword, which matches every
+// value of its key column
+func IsAll(segment string) bool {
+	return strings.EqualFold(segment, "ALL")
+}
+
+// FilterRows returns the rows whose key columns (config.key_columns) match
+// the segments of req in order, case-insensitively; ALL matches every value.
+// More segments than key columns is a *RequestError.
+func FilterRows(req *Request, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	keys, err := catalog.KeyColumns(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Segments) > len(keys) {
+		return nil, &RequestError{
+			Path:    req.Path,
+			Message: fmt.Sprintf("%d segments below %s but the binding has %d key columns (%s)", len(req.Segments), req.BindingPath, len(keys), strings.Join(keys, ", ")),
+		}
+	}
+
+	matched := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if matchesSegments(row, keys, req.Segments) {
+			matched = append(matched, row)
+		}
+	}
+	return matched, nil
+}
+
+// matchesSegments reports whether the key columns of row equal segments
+func matchesSegments(row map[string]interface{}, keys, segments []string) bool {
+	for i, segment := range segments {
+		if IsAll(segment) {
+			continue
+		}
+		value, ok := row[keys[i]]
+		if !ok || value == nil || !strings.EqualFold(fmt.Sprint(value), segment) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewResult builds a result from rows, typing cells per schema and capping
+// the rows at maxRows. Columns follow the schema when it declares any,
+// otherwise they are the sorted union of the row keys.
+func NewResult(req *Request, rows []map[string]interface{}) (*DataResult, error) {
+	result := &DataResult{
+		Path:       req.Path,
+		SourceType: string(req.Binding.SourceType),
+		Columns:    resultColumns(rows, req.Schema),
+	}
+	if req.MaxRows > 0 && len(rows) > req.MaxRows {
+		rows, result.Truncated = rows[:req.MaxRows], true
+	}
+	types := make(map[string]string)
+	if req.Schema != nil {
+		for _, c := range req.Schema.Columns {
+			types[c.Name] = c.DataType
+		}
+	}
+
+	result.Rows = make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		typed := make(map[string]interface{}, len(result.Columns))
+		for _, name := range result.Columns {
+			value, err := Coerce(row[name], types[name])
+			if err != nil {
+				return nil, fmt.Errorf("row %d column %s: %w", i+1, name, err)
+			}
+			typed[name] = value
+		}
+		result.Rows[i] = typed
+	}
+	result.RowCount = len(result.Rows)
+	return result, nil
+}
+
+func resultColumns(rows []map[string]interface{}, schema *catalog.DataSchema) []string {
+	if schema != nil && len(schema.Columns) > 0 {
+		columns := make([]string, len(schema.Columns))
+		for i, c := range schema.Columns {
+			columns[i] = c.Name
+		}
+		return columns
+	}
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// Coerce converts a cell to a schema data type: string, integer (int64),
+// float (float64), boolean, or date (YYYY-MM-DD string). Exact numbers
+// arrive as json.Number and are only rounded when the schema asks for float. Empty strings
+// become nil; an unknown or empty type leaves the value as is.
+func Coerce(v interface{}, dataType string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if s, ok := v.(string); ok && strings.TrimSpace(s) == "" && dataType != "" && dataType != "string" {
+		return nil, nil
+	}
+	switch dataType {
+	case "string":
+		return fmt.Sprint(v), nil
+	case "integer":
+		switch n := v.(type) {
+		case int:
+			return int64(n), nil
+		case int64:
+			return n, nil
+		case float64:
+			if n != float64(int64(n)) {
+				return nil, fmt.Errorf("%v is not an integer", n)
+			}
+			return int64(n), nil
+		case json.Number:
+			i, err := n.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%s is not an integer", n)
+			}
+			return i, nil
+		case string:
+			i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not an integer", n)
+			}
+			return i, nil
+		}
+	case "float":
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		case json.Number:
+			return n.Float64()
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", n)
+			}
+			return f, nil
+		}
+	case "boolean":
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(b))
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a boolean", b)
+			}
+			return parsed, nil
+		}
+	case "date":
+		switch d := v.(type) {
+		case time.Time:
+			return d.Format("2006-01-02"), nil
+		case string:
+			s := strings.TrimSpace(d)
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				return nil, fmt.Errorf("%q is not a YYYY-MM-DD date", d)
+			}
+			return s, nil
+		}
+	default:
+		switch n := v.(type) {
+		case int:
+			return int64(n), nil
+		case time.Time:
+			return n.Format(time.RFC3339), nil
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to %s", v, dataType)
+}
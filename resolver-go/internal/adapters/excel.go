@@ -0,0 +1,231 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// filePatternPlaceholder matches the placeholders of an excel file_pattern
+var filePatternPlaceholder = regexp.MustCompile(`\{(path|segments\[(\d+)\])\}`)
+
+// Excel serves a worksheet of an .xlsx workbook. The workbook is
+// config.base_path, or config.file_pattern below it, whose {segments[N]} and
+// {path} placeholders take the moniker segments below the binding; the
+// segments after those used by the pattern filter config.key_columns as in
+// Static. config.sheet names the worksheet (the first by default) and
+// config.header_row the 1-based row of column names (1 by default). Versions
+// are the values of config.version_column in the matching rows.
+//
+// Parsed sheets are kept for the binding's cache.ttl_seconds when its cache
+// is enabled, and read again sooner when the file's modification time or size
+// changes.
+type Excel struct {
+	mu     sync.Mutex
+	sheets map[excelSheetKey]*excelSheet
+	now    func() time.Time
+}
+
+type excelSheetKey struct {
+	file      string
+	sheet     string
+	headerRow int
+}
+
+// excelSheet is a parsed worksheet and the file state it was read from
+type excelSheet struct {
+	rows    []map[string]interface{}
+	modTime time.Time
+	size    int64
+	expires time.Time
+}
+
+// NewExcel creates an Excel adapter with an empty sheet cache
+func NewExcel() *Excel {
+	return &Excel{
+		sheets: make(map[excelSheetKey]*excelSheet),
+		now:    time.Now,
+	}
+}
+
+// SetClock replaces the clock used for cache expiry, for tests
+func (e *Excel) SetClock(now func() time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.now = now
+}
+
+// Fetch implements Adapter
+func (e *Excel) Fetch(_ context.Context, req *Request) (*DataResult, error) {
+	file, used, err := excelWorkbookPath(req)
+	if err != nil {
+		return nil, err
+	}
+	sheet, _ := req.Config["sheet"].(string)
+	headerRow := 1
+	if n, ok := configInt(req.Config["header_row"]); ok && n > 0 {
+		headerRow = n
+	}
+
+	rows, err := e.readSheet(excelSheetKey{file: file, sheet: sheet, headerRow: headerRow}, excelWorkbookName(req, file), req.Binding.Cache)
+	if err != nil {
+		return nil, err
+	}
+	filter := *req
+	filter.Segments = req.Segments[used:]
+	matched, err := FilterRows(&filter, rows)
+	if err != nil {
+		return nil, err
+	}
+	return NewResult(req, matched)
+}
+
+// CachesData implements SelfCaching
+func (e *Excel) CachesData() bool {
+	return true
+}
+
+// ListVersions implements VersionLister
+func (e *Excel) ListVersions(ctx context.Context, req *Request) ([]string, error) {
+	return listColumnVersions(ctx, e, req)
+}
+
+// readSheet returns the rows of a worksheet, from the cache when the entry is
+// live and the file unchanged. Errors name the workbook by name.
+func (e *Excel) readSheet(key excelSheetKey, name string, cacheCfg *catalog.QueryCacheConfig) ([]map[string]interface{}, error) {
+	info, err := os.Stat(key.file)
+	if err != nil {
+		return nil, workbookError(name, key.file, err)
+	}
+	ttl := time.Duration(0)
+	if cacheCfg != nil && cacheCfg.Enabled && cacheCfg.TTLSeconds > 0 {
+		ttl = time.Duration(cacheCfg.TTLSeconds) * time.Second
+	}
+
+	e.mu.Lock()
+	now := e.now()
+	cached, ok := e.sheets[key]
+	e.mu.Unlock()
+	if ok && ttl > 0 && now.Before(cached.expires) && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.rows, nil
+	}
+
+	data, err := os.ReadFile(key.file)
+	if err != nil {
+		return nil, workbookError(name, key.file, err)
+	}
+	rows, err := catalog.ReadXLSXTable(bytes.NewReader(data), int64(len(data)), key.sheet, key.headerRow)
+	if err != nil {
+		return nil, workbookError(name, key.file, err)
+	}
+	if ttl <= 0 {
+		return rows, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for k, s := range e.sheets {
+		if !now.Before(s.expires) {
+			delete(e.sheets, k)
+		}
+	}
+	e.sheets[key] = &excelSheet{rows: rows, modTime: info.ModTime(), size: info.Size(), expires: now.Add(ttl)}
+	return rows, nil
+}
+
+// workbookError logs a failure to read the workbook at file and returns it
+// naming the workbook by name instead: fetch errors reach clients, which must
+// not learn the server's directories
+func workbookError(name, file string, err error) error {
+	log.Printf("excel: workbook %s: %v", file, err)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("workbook %s not found", name)
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return fmt.Errorf("workbook %s: %w", name, err)
+}
+
+// excelWorkbookName names a workbook by its path below base_path, or by its
+// file name when base_path is the workbook
+func excelWorkbookName(req *Request, file string) string {
+	base, _ := req.Config["base_path"].(string)
+	if rel, err := filepath.Rel(base, file); err == nil && rel != "." {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.Base(file)
+}
+
+// excelWorkbookPath returns the workbook file of a request and the number of
+// leading segments its file_pattern used
+func excelWorkbookPath(req *Request) (string, int, error) {
+	base, _ := req.Config["base_path"].(string)
+	pattern, _ := req.Config["file_pattern"].(string)
+	if pattern == "" {
+		return base, 0, nil
+	}
+
+	used := 0
+	var missing error
+	name := filePatternPlaceholder.ReplaceAllStringFunc(pattern, func(token string) string {
+		match := filePatternPlaceholder.FindStringSubmatch(token)
+		if match[1] == "path" {
+			used = len(req.Segments)
+			return strings.Join(req.Segments, "/")
+		}
+		i, _ := strconv.Atoi(match[2])
+		if i >= len(req.Segments) {
+			if missing == nil {
+				missing = &RequestError{Path: req.Path, Message: fmt.Sprintf("file_pattern %s needs segment %d below %s", pattern, i, req.BindingPath)}
+			}
+			return ""
+		}
+		if i+1 > used {
+			used = i + 1
+		}
+		return req.Segments[i]
+	})
+	if missing != nil {
+		return "", 0, missing
+	}
+	for _, segment := range req.Segments[:used] {
+		if IsAll(segment) || segment == ".." || segment == "." {
+			return "", 0, &RequestError{Path: req.Path, Message: fmt.Sprintf("segment %q cannot select a workbook", segment)}
+		}
+	}
+
+	file := filepath.Join(base, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(base, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", 0, &RequestError{Path: req.Path, Message: fmt.Sprintf("file_pattern %s leaves base_path", pattern)}
+	}
+	return file, used, nil
+}
+
+// configInt returns a whole number decoded from YAML or JSON
+func configInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		return int(n), n == float64(int(n))
+	}
+	return 0, false
+}
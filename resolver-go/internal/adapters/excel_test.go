@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// writeWorkbook writes a one-sheet workbook of inline string cells
+func writeWorkbook(t *testing.T, file, sheet string, rows ...[]string) {
+	t.Helper()
+	var data strings.Builder
+	for i, cells := range rows {
+		fmt.Fprintf(&data, `<row r="%d">`, i+1)
+		for col, value := range cells {
+			fmt.Fprintf(&data, `<c r="%c%d" t="inlineStr"><is><t>%s</t></is></c>`, 'A'+col, i+1, value)
+		}
+		data.WriteString(`</row>`)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range [][2]string{
+		{"xl/workbook.xml", `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + sheet + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`},
+		{"xl/worksheets/sheet1.xml", `<worksheet><sheetData>` + data.String() + `</sheetData></worksheet>`},
+	} {
+		f, err := zw.Create(part[0])
+		if err == nil {
+			_, err = f.Write([]byte(part[1]))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExcelCachesSheetUntilExpiryOrChange(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pools.xlsx")
+	writeWorkbook(t, file, "PoolData", []string{"pool_id"}, []string{"31418A"})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := NewExcel()
+	e.SetClock(func() time.Time { return now })
+	req := &Request{
+		Path:        "mbs/pools",
+		BindingPath: "mbs/pools",
+		Binding: &catalog.SourceBinding{
+			SourceType: catalog.SourceTypeExcel,
+			Cache:      &catalog.QueryCacheConfig{Enabled: true, TTLSeconds: 60},
+		},
+		Config: map[string]interface{}{"base_path": file, "sheet": "PoolData"},
+	}
+	fetchPool := func() interface{} {
+		t.Helper()
+		result, err := e.Fetch(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.Rows[0]["pool_id"]
+	}
+
+	if id := fetchPool(); id != "31418A" {
+		t.Fatalf("expected 31418A, got %v", id)
+	}
+	// Replace the cached rows; an unchanged file within the TTL serves them
+	key := excelSheetKey{file: file, sheet: "PoolData", headerRow: 1}
+	e.sheets[key].rows = []map[string]interface{}{{"pool_id": "cached"}}
+	if id := fetchPool(); id != "cached" {
+		t.Errorf("expected the cached sheet, got %v", id)
+	}
+
+	now = now.Add(61 * time.Second)
+	if id := fetchPool(); id != "31418A" {
+		t.Errorf("expected an expired entry to be read again, got %v", id)
+	}
+
+	e.sheets[key].rows = []map[string]interface{}{{"pool_id": "cached"}}
+	later := e.sheets[key].modTime.Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if id := fetchPool(); id != "31418A" {
+		t.Errorf("expected a modified file to be read again, got %v", id)
+	}
+}
+
+func TestExcelWorkbookPath(t *testing.T) {
+	req := &Request{
+		Path:        "reports/regulatory/2026/q1/US",
+		BindingPath: "reports/regulatory",
+		Segments:    []string{"2026", "q1", "US"},
+		Config:      map[string]interface{}{"base_path": "/data/reports", "file_pattern": "{segments[0]}/{segments[1]}.xlsx"},
+	}
+	file, used, err := excelWorkbookPath(req)
+	if err != nil || file != filepath.FromSlash("/data/reports/2026/q1.xlsx") || used != 2 {
+		t.Errorf("unexpected workbook %s (%d segments used, %v)", file, used, err)
+	}
+
+	req.Segments = []string{"2026"}
+	if _, _, err := excelWorkbookPath(req); err == nil || !strings.Contains(err.Error(), "needs segment 1") {
+		t.Errorf("expected a missing segment error, got %v", err)
+	}
+	req.Segments = []string{"..", "secrets"}
+	if _, _, err := excelWorkbookPath(req); err == nil {
+		t.Error("expected .. to be rejected")
+	}
+}
@@ -0,0 +1,409 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenSearch defaults; size and max_hits in the binding config override them
+const (
+	openSearchDefaultSize     = 1000
+	openSearchMaxSize         = 10000 // index.max_result_window default
+	openSearchDefaultMaxHits  = 100000
+	openSearchScrollKeepAlive = "1m"
+	openSearchDefaultTimeout  = 30 * time.Second
+)
+
+// openSearchPlaceholder matches a placeholder at the start of a query
+// template fragment
+var openSearchPlaceholder = regexp.MustCompile(`^\{(segments\[\d+\]|segment_id\[\d+\]|segment_id_value|segment_id_index|has_segment_id|lookback_start|lookback_end|frequency|params\.[^{}"\s]+)\}`)
+
+// OpenSearch searches the index of an opensearch binding. config.query is a
+// JSON query DSL template whose placeholders are substituted as JSON values:
+// inside a string they are escaped, elsewhere they become a number, boolean
+// or string. Hits are returned as rows of their _source fields, limited to the
+// node's schema columns when it declares any (dotted names reach into
+// objects).
+//
+// A search returns at most config.size hits (1000 by default). For a date@all
+// moniker, or when a segment below the binding is ALL, every hit is read with
+// the scroll API, up to access_policy.max_rows_block or config.max_hits
+// (100000 by default).
+// config.hosts are tried in order. Authentication is config.username and
+// config.password (basic) or config.api_key, which must be secret://
+// references; config.tls_ca_file and config.tls_insecure_skip_verify set TLS.
+type OpenSearch struct {
+	mu      sync.Mutex
+	clients map[openSearchTLS]*http.Client
+}
+
+type openSearchTLS struct {
+	caFile             string
+	insecureSkipVerify bool
+	timeout            time.Duration
+}
+
+// NewOpenSearch creates an OpenSearch adapter
+func NewOpenSearch() *OpenSearch {
+	return &OpenSearch{clients: make(map[openSearchTLS]*http.Client)}
+}
+
+// openSearchSearch is one search or scroll page of an OpenSearch response
+type openSearchSearch struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []struct {
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Fetch implements Adapter
+func (o *OpenSearch) Fetch(ctx context.Context, req *Request) (*DataResult, error) {
+	for _, key := range []string{"password", "api_key"} {
+		if _, ok := req.Config[key]; ok && !req.FromSecret(key) {
+			return nil, fmt.Errorf("connection %s must be a secret:// reference, not plain config", key)
+		}
+	}
+	hosts, err := openSearchHosts(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	index, _ := req.Config["index"].(string)
+	if index == "" {
+		return nil, fmt.Errorf("binding has no index")
+	}
+	body, err := openSearchBody(req)
+	if err != nil {
+		return nil, err
+	}
+	client, err := o.client(req.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	size := openSearchDefaultSize
+	if n, ok := configInt(req.Config["size"]); ok && n > 0 {
+		size = n
+	}
+	size = min(size, openSearchMaxSize)
+	scroll := IsAll(req.Version)
+	for _, segment := range req.Segments {
+		scroll = scroll || IsAll(segment)
+	}
+	limit := size
+	if scroll {
+		limit = openSearchDefaultMaxHits
+		if n, ok := configInt(req.Config["max_hits"]); ok && n > 0 {
+			limit = n
+		}
+	}
+	if req.MaxRows > 0 && req.MaxRows < limit {
+		limit = req.MaxRows
+	}
+	body["size"] = min(size, limit+1)
+
+	c := &openSearchCall{client: client, hosts: hosts, config: req.Config}
+	path := "/" + strings.Trim(index, "/") + "/_search"
+	if scroll {
+		path += "?scroll=" + openSearchScrollKeepAlive
+	}
+	var page openSearchSearch
+	if err := c.do(ctx, http.MethodPost, path, body, &page); err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	truncated := false
+	for {
+		for _, hit := range page.Hits.Hits {
+			if len(rows) == limit {
+				truncated = true
+				break
+			}
+			rows = append(rows, openSearchRow(hit.Source, req))
+		}
+		if !scroll || truncated || len(page.Hits.Hits) == 0 || page.ScrollID == "" {
+			break
+		}
+		scrollID := page.ScrollID
+		page = openSearchSearch{}
+		if err := c.do(ctx, http.MethodPost, "/_search/scroll", map[string]interface{}{"scroll": openSearchScrollKeepAlive, "scroll_id": scrollID}, &page); err != nil {
+			c.clearScroll(scrollID)
+			return nil, err
+		}
+		if page.ScrollID == "" {
+			page.ScrollID = scrollID
+		}
+	}
+	if scroll && page.ScrollID != "" {
+		c.clearScroll(page.ScrollID)
+	}
+
+	result, err := NewResult(req, rows)
+	if err != nil {
+		return nil, err
+	}
+	result.Truncated = result.Truncated || truncated
+	return result, nil
+}
+
+// client returns the HTTP client for the TLS options of a binding
+func (o *OpenSearch) client(config map[string]interface{}) (*http.Client, error) {
+	key := openSearchTLS{timeout: openSearchDefaultTimeout}
+	key.caFile, _ = config["tls_ca_file"].(string)
+	key.insecureSkipVerify, _ = config["tls_insecure_skip_verify"].(bool)
+	if n, ok := configInt(config["timeout_seconds"]); ok && n > 0 {
+		key.timeout = time.Duration(n) * time.Second
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if c, ok := o.clients[key]; ok {
+		return c, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: key.insecureSkipVerify}
+	if key.caFile != "" {
+		pem, err := os.ReadFile(key.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls_ca_file %s has no PEM certificates", key.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c := &http.Client{Transport: transport, Timeout: key.timeout}
+	o.clients[key] = c
+	return c, nil
+}
+
+// openSearchCall sends requests to the first host of a binding that answers
+type openSearchCall struct {
+	client *http.Client
+	hosts  []string
+	config map[string]interface{}
+}
+
+// do sends a JSON request and decodes the JSON response into out. Hosts are
+// tried in order while they cannot be reached; an error response from a
+// host is returned as it is.
+func (c *openSearchCall) do(ctx context.Context, method, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, host := range c.hosts {
+		req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(host, "/")+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.authorize(req)
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("opensearch: %w", err)
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("opensearch %s: %w", host, err)
+		}
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("opensearch %s: %s: %s", host, resp.Status, openSearchErrorReason(data))
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("opensearch %s: bad response: %w", host, err)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// authorize sets basic or API key authentication from the binding config
+func (c *openSearchCall) authorize(req *http.Request) {
+	if key, _ := c.config["api_key"].(string); key != "" {
+		req.Header.Set("Authorization", "ApiKey "+key)
+		return
+	}
+	if user, _ := c.config["username"].(string); user != "" {
+		password, _ := c.config["password"].(string)
+		req.SetBasicAuth(user, password)
+	}
+}
+
+// clearScroll releases a scroll context; failures only leave it to expire
+func (c *openSearchCall) clearScroll(scrollID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var ignored map[string]interface{}
+	_ = c.do(ctx, http.MethodDelete, "/_search/scroll", map[string]interface{}{"scroll_id": scrollID}, &ignored)
+}
+
+// openSearchErrorReason returns the reason of an OpenSearch error body, or
+// the start of the body
+func openSearchErrorReason(data []byte) string {
+	var body struct {
+		Error struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error.Reason != "" {
+		return body.Error.Reason
+	}
+	if len(data) > 200 {
+		data = data[:200]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// openSearchHosts returns config.hosts as base URLs
+func openSearchHosts(config map[string]interface{}) ([]string, error) {
+	list, _ := config["hosts"].([]interface{})
+	hosts := make([]string, 0, len(list))
+	for i, item := range list {
+		host, ok := item.(string)
+		if !ok || host == "" {
+			return nil, fmt.Errorf("hosts[%d] must be a URL", i)
+		}
+		if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+			host = "https://" + host
+		}
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("binding has no hosts")
+	}
+	return hosts, nil
+}
+
+// openSearchBody returns the search body: config.query with placeholders
+// substituted, or match_all
+func openSearchBody(req *Request) (map[string]interface{}, error) {
+	template, _ := req.Config["query"].(string)
+	if strings.TrimSpace(template) == "" {
+		return map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}, nil
+	}
+	query, err := substituteJSON(template, req.Placeholders)
+	if err != nil {
+		return nil, &RequestError{Path: req.Path, Message: err.Error()}
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(query), &body); err != nil {
+		return nil, fmt.Errorf("query is not a JSON object once placeholders are substituted: %w", err)
+	}
+	return body, nil
+}
+
+// substituteJSON replaces the placeholders of a JSON template with values.
+// Within a string a value is escaped; outside one it is written as a JSON
+// number or boolean when it is one and as a JSON string otherwise, so a
+// value can never change the structure of the document.
+func substituteJSON(template string, values map[string]string) (string, error) {
+	var out strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(template); i++ {
+		ch := template[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+		} else if ch == '"' {
+			inString = true
+		}
+		if ch != '{' {
+			out.WriteByte(ch)
+			continue
+		}
+		match := openSearchPlaceholder.FindStringSubmatch(template[i:])
+		if match == nil {
+			out.WriteByte(ch)
+			continue
+		}
+		value, ok := values[match[1]]
+		if !ok {
+			return "", fmt.Errorf("query placeholder {%s} has no value", match[1])
+		}
+		encoded, _ := json.Marshal(value)
+		switch {
+		case inString:
+			out.Write(encoded[1 : len(encoded)-1])
+		case isJSONScalar(value):
+			out.WriteString(value)
+		default:
+			out.Write(encoded)
+		}
+		i += len(match[0]) - 1
+	}
+	return out.String(), nil
+}
+
+// isJSONScalar reports whether s is a JSON number or boolean literal
+func isJSONScalar(s string) bool {
+	if s == "true" || s == "false" {
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return false
+	}
+	var n json.Number
+	return json.Unmarshal([]byte(s), &n) == nil
+}
+
+// openSearchRow returns the row of a hit: the schema columns of _source, or
+// all of it when the node declares no columns
+func openSearchRow(source map[string]interface{}, req *Request) map[string]interface{} {
+	if req.Schema == nil || len(req.Schema.Columns) == 0 {
+		return source
+	}
+	row := make(map[string]interface{}, len(req.Schema.Columns))
+	for _, c := range req.Schema.Columns {
+		if v, ok := sourceField(source, c.Name); ok {
+			row[c.Name] = v
+		}
+	}
+	return row
+}
+
+// sourceField looks up a field of _source, following dots into objects when
+// there is no field with the dotted name itself
+func sourceField(source map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := source[name]; ok {
+		return v, true
+	}
+	head, rest, found := strings.Cut(name, ".")
+	if !found {
+		return nil, false
+	}
+	nested, ok := source[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return sourceField(nested, rest)
+}
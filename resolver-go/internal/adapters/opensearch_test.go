@@ -0,0 +1,223 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+func TestSubstituteJSONKeepsValuesAsValues(t *testing.T) {
+	values := map[string]string{
+		"segments[2]":    `AAPL", "boost": "99`,
+		"params.limit":   "25",
+		"has_segment_id": "false",
+		"params.region":  "EMEA",
+	}
+	out, err := substituteJSON(`{"size": {params.limit}, "query": {"bool": {"filter": [`+
+		`{"term": {"symbol": "{segments[2]}"}}, {"term": {"has_id": {has_segment_id}}}, `+
+		`{"prefix": {"desk": "{params.region}-"}}, {"term": {"region": {params.region}}}]}}}`, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &body); err != nil {
+		t.Fatalf("substituted query is not JSON: %v\n%s", err, out)
+	}
+	if body["size"] != float64(25) || len(body) != 2 {
+		t.Errorf("expected a numeric size and no injected keys, got %v", body)
+	}
+	if !strings.Contains(out, `"symbol": "AAPL\", \"boost\": \"99"`) || !strings.Contains(out, `"has_id": false`) ||
+		!strings.Contains(out, `"desk": "EMEA-"`) || !strings.Contains(out, `"region": "EMEA"`) {
+		t.Errorf("unexpected substitution: %s", out)
+	}
+
+	if _, err := substituteJSON(`{"term": {"x": "{segments[9]}"}}`, values); err == nil {
+		t.Error("expected an error for a placeholder without a value")
+	}
+}
+
+// fakeOpenSearch serves searches from docs, paging scrolls two hits at a time
+type fakeOpenSearch struct {
+	mu       sync.Mutex
+	docs     []map[string]interface{}
+	bodies   []map[string]interface{}
+	auth     []string
+	cleared  int
+	requests []string
+}
+
+func (f *fakeOpenSearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+
+	from := 0
+	size := 2
+	switch {
+	case r.Method == http.MethodDelete:
+		f.cleared++
+		w.Write([]byte(`{"succeeded": true}`))
+		return
+	case r.URL.Path == "/_search/scroll":
+		fmt.Sscanf(body["scroll_id"].(string), "scroll-%d", &from)
+	case r.URL.Query().Get("scroll") == "":
+		size = int(body["size"].(float64))
+	}
+	hits := make([]map[string]interface{}, 0)
+	for i := from; i < from+size && i < len(f.docs); i++ {
+		hits = append(hits, map[string]interface{}{"_source": f.docs[i]})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"_scroll_id": fmt.Sprintf("scroll-%d", from+size),
+		"hits":       map[string]interface{}{"hits": hits},
+	})
+}
+
+func newFakeOpenSearch(t *testing.T, docs int) (*fakeOpenSearch, *httptest.Server) {
+	f := &fakeOpenSearch{}
+	for i := 0; i < docs; i++ {
+		f.docs = append(f.docs, map[string]interface{}{
+			"symbol": fmt.Sprintf("SYM%d", i),
+			"quote":  map[string]interface{}{"price": float64(i) + 0.5},
+			"desk":   "rates",
+		})
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func openSearchRequest(host string, segments ...string) *Request {
+	return &Request{
+		Path:        "news/articles",
+		BindingPath: "news/articles",
+		Segments:    segments,
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeOpenSearch},
+		Config: map[string]interface{}{
+			"hosts":   []interface{}{"http://127.0.0.1:1", host},
+			"index":   "quotes",
+			"query":   `{"query": {"term": {"desk": "{params.desk}"}}}`,
+			"size":    3,
+			"api_key": "c2VjcmV0",
+		},
+		SecretKeys:   []string{"api_key"},
+		Placeholders: map[string]string{"params.desk": "rates"},
+		Schema: &catalog.DataSchema{Columns: []catalog.ColumnSchema{
+			{Name: "symbol", DataType: "string"},
+			{Name: "quote.price", DataType: "float"},
+		}},
+	}
+}
+
+func TestOpenSearchSearchesWithSizeCap(t *testing.T) {
+	f, srv := newFakeOpenSearch(t, 5)
+
+	result, err := NewOpenSearch().Fetch(context.Background(), openSearchRequest(srv.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RowCount != 3 || result.Rows[1]["symbol"] != "SYM1" || result.Rows[1]["quote.price"] != 1.5 {
+		t.Errorf("expected 3 rows of schema columns, got %+v", result.Rows)
+	}
+	if _, ok := result.Rows[0]["desk"]; ok {
+		t.Error("expected fields outside the schema to be dropped")
+	}
+	if len(f.requests) != 1 || f.requests[0] != "POST /quotes/_search" || f.auth[0] != "ApiKey c2VjcmV0" {
+		t.Errorf("unexpected requests %v (auth %v)", f.requests, f.auth)
+	}
+	if query, _ := json.Marshal(f.bodies[0]["query"]); string(query) != `{"term":{"desk":"rates"}}` {
+		t.Errorf("unexpected query %s", query)
+	}
+}
+
+func TestOpenSearchScrollsForAll(t *testing.T) {
+	f, srv := newFakeOpenSearch(t, 5)
+	req := openSearchRequest(srv.URL, "ALL")
+	req.MaxRows = 4
+
+	result, err := NewOpenSearch().Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RowCount != 4 || !result.Truncated {
+		t.Errorf("expected 4 rows, truncated at max_rows_block, got %d (truncated %t)", result.RowCount, result.Truncated)
+	}
+	if f.requests[0] != "POST /quotes/_search?scroll=1m" || f.requests[1] != "POST /_search/scroll" || f.cleared != 1 {
+		t.Errorf("expected a scroll that is cleared, got %v", f.requests)
+	}
+
+	req.MaxRows = 0
+	if result, err := NewOpenSearch().Fetch(context.Background(), req); err != nil || result.RowCount != 5 || result.Truncated {
+		t.Errorf("expected every hit, got %+v (%v)", result, err)
+	}
+}
+
+func TestOpenSearchScrollsForDateAll(t *testing.T) {
+	f, srv := newFakeOpenSearch(t, 5)
+	req := openSearchRequest(srv.URL)
+	req.Version = "all"
+
+	result, err := NewOpenSearch().Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RowCount != 5 || result.Truncated {
+		t.Errorf("expected every hit past config.size, got %d (truncated %t)", result.RowCount, result.Truncated)
+	}
+	if f.requests[0] != "POST /quotes/_search?scroll=1m" || f.cleared != 1 {
+		t.Errorf("expected a scroll, got %v", f.requests)
+	}
+}
+
+func TestOpenSearchRequiresSecretCredentials(t *testing.T) {
+	_, srv := newFakeOpenSearch(t, 1)
+	req := openSearchRequest(srv.URL)
+	req.SecretKeys = nil
+
+	if _, err := NewOpenSearch().Fetch(context.Background(), req); err == nil || !strings.Contains(err.Error(), "api_key must be a secret:// reference") {
+		t.Errorf("expected a plain api_key to be refused, got %v", err)
+	}
+}
+
+func TestOpenSearchTrustsConfiguredCA(t *testing.T) {
+	f := &fakeOpenSearch{docs: []map[string]interface{}{{"symbol": "SYM0"}}}
+	srv := httptest.NewTLSServer(f)
+	defer srv.Close()
+	req := openSearchRequest(srv.URL)
+	req.Config["hosts"] = []interface{}{srv.URL}
+	delete(req.Config, "api_key")
+	req.Config["username"], req.Config["password"] = "reader", "pw"
+	req.SecretKeys = []string{"password"}
+
+	if _, err := NewOpenSearch().Fetch(context.Background(), req); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req.Config["tls_ca_file"] = caFile
+	result, err := NewOpenSearch().Fetch(context.Background(), req)
+	if err != nil || result.RowCount != 1 {
+		t.Fatalf("expected a row over TLS, got %+v (%v)", result, err)
+	}
+	if !strings.HasPrefix(f.auth[len(f.auth)-1], "Basic ") {
+		t.Errorf("expected basic auth, got %q", f.auth[len(f.auth)-1])
+	}
+}
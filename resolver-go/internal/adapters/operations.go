@@ -0,0 +1,274 @@
+package adapters
+
+import (
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// Operations a fetch can perform, as listed in a binding's
+// allowed_operations. SQL statements are select, insert, update, delete,
+// merge, ddl (CREATE, ALTER, DROP, TRUNCATE, GRANT...), execute (CALL, EXEC,
+// anonymous blocks) or other; REST requests are their HTTP method.
+const (
+	OperationSelect  = "select"
+	OperationInsert  = "insert"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+	OperationMerge   = "merge"
+	OperationDDL     = "ddl"
+	OperationExecute = "execute"
+	OperationOther   = "other"
+)
+
+// sqlSourceTypes are the source types whose queries are SQL
+var sqlSourceTypes = map[catalog.SourceType]bool{
+	catalog.SourceTypeSnowflake: true,
+	catalog.SourceTypeOracle:    true,
+	catalog.SourceTypeMSSQL:     true,
+}
+
+// backslashEscapeTypes are the source types whose string literals treat a
+// backslash as an escape, so 'it\'s' is one literal (Snowflake, as MySQL)
+var backslashEscapeTypes = map[catalog.SourceType]bool{
+	catalog.SourceTypeSnowflake: true,
+}
+
+// sqlStatements maps the first keyword of a statement to its operation
+var sqlStatements = map[string]string{
+	"SELECT": OperationSelect, "VALUES": OperationSelect, "SHOW": OperationSelect,
+	"DESCRIBE": OperationSelect, "DESC": OperationSelect, "EXPLAIN": OperationSelect,
+	"INSERT": OperationInsert, "UPSERT": OperationInsert, "REPLACE": OperationInsert, "COPY": OperationInsert,
+	"UPDATE": OperationUpdate,
+	"DELETE": OperationDelete,
+	"MERGE":  OperationMerge,
+	"CREATE": OperationDDL, "ALTER": OperationDDL, "DROP": OperationDDL, "TRUNCATE": OperationDDL,
+	"RENAME": OperationDDL, "COMMENT": OperationDDL, "GRANT": OperationDDL, "REVOKE": OperationDDL, "UNDROP": OperationDDL,
+	"CALL": OperationExecute, "EXEC": OperationExecute, "EXECUTE": OperationExecute,
+	"BEGIN": OperationExecute, "DECLARE": OperationExecute, "DO": OperationExecute,
+}
+
+// Operations returns the operations a fetch of a binding performs, in the
+// order they appear. REST bindings perform their config.method (GET by
+// default) and SQL bindings every statement of their query; other source
+// types only read, as do SQL bindings without a query.
+func Operations(sourceType catalog.SourceType, query string, config map[string]interface{}) []string {
+	if sourceType == catalog.SourceTypeREST {
+		method, _ := config["method"].(string)
+		if method == "" {
+			method = "GET"
+		}
+		return []string{strings.ToUpper(method)}
+	}
+	if !sqlSourceTypes[sourceType] || strings.TrimSpace(query) == "" {
+		return []string{OperationSelect}
+	}
+	return classifySQL(query, backslashEscapeTypes[sourceType])
+}
+
+// ClassifySQL returns the operations of every statement in a SQL text,
+// without duplicates. Statements are found at the start of the text, after
+// each semicolon and inside parentheses, so DML in a CTE body or subquery is
+// reported, as is the statement a WITH clause, EXPLAIN or BEGIN introduces.
+// SELECT ... INTO, which writes a table, is an insert. Unknown statements are
+// other, as is a text with an unterminated literal, quoted identifier or
+// block comment. Without a dialect, string literals are read both with and
+// without backslash escapes and the operations of both readings returned.
+func ClassifySQL(query string) []string {
+	ops := classifySQL(query, false)
+	for _, op := range classifySQL(query, true) {
+		ops = addOperation(ops, op)
+	}
+	return ops
+}
+
+// addOperation appends op to ops unless it is already there
+func addOperation(ops []string, op string) []string {
+	for _, seen := range ops {
+		if seen == op {
+			return ops
+		}
+	}
+	return append(ops, op)
+}
+
+// classifySQL is ClassifySQL for one dialect: backslash is whether string
+// literals treat a backslash as an escape
+func classifySQL(query string, backslash bool) []string {
+	tokens, ok := sqlTokens(query, backslash)
+	ops := make([]string, 0, 1)
+	add := func(op string) {
+		ops = addOperation(ops, op)
+	}
+
+	// withDepth holds the depth of each open WITH clause, whose main
+	// statement keyword follows its CTE bodies at the same depth
+	depth := 0
+	withDepth := make(map[int]bool)
+	selectDepth := make(map[int]bool)
+	start := true    // The next token starts a top-level statement
+	nested := false  // The next token follows an opening parenthesis
+	explain := false // The next statement keyword follows EXPLAIN or BEGIN
+	for i, tok := range tokens {
+		switch tok {
+		case ";":
+			start, nested, explain = true, false, false
+			withDepth, selectDepth = make(map[int]bool), make(map[int]bool)
+			depth = 0
+			continue
+		case "(":
+			depth++
+			nested = true
+			continue
+		case ")":
+			delete(withDepth, depth)
+			delete(selectDepth, depth)
+			if depth > 0 {
+				depth--
+			}
+			nested = false
+			continue
+		}
+
+		op, keyword := sqlStatements[tok]
+		// A keyword followed by ( is a function call, such as REPLACE(...)
+		if keyword && tok != "VALUES" && i+1 < len(tokens) && tokens[i+1] == "(" {
+			keyword = false
+		}
+		switch {
+		case tok == "WITH" && (start || nested || explain):
+			withDepth[depth] = true
+		case keyword && (start || nested || explain || withDepth[depth]):
+			add(op)
+			delete(withDepth, depth)
+			explain = tok == "EXPLAIN" || tok == "BEGIN"
+			selectDepth[depth] = op == OperationSelect && tok != "EXPLAIN"
+		case start && !withDepth[depth]:
+			add(OperationOther)
+		case tok == "INTO" && selectDepth[depth]:
+			add(OperationInsert)
+		default:
+			if tok != "RECURSIVE" && tok != "ANALYZE" && tok != "VERBOSE" {
+				explain = false
+			}
+		}
+		start, nested = false, false
+	}
+	if !ok {
+		// What follows the unterminated part can't be read reliably
+		add(OperationOther)
+	}
+	if len(ops) == 0 {
+		ops = append(ops, OperationSelect)
+	}
+	return ops
+}
+
+// sqlTokens splits SQL into upper-cased words and the punctuation ( ) ;,
+// dropping comments, string literals, quoted identifiers and other symbols.
+// With backslash, a backslash in a string literal escapes the next byte. ok
+// is false when a literal, quoted identifier or block comment isn't closed;
+// tokens then holds those before it.
+func sqlTokens(query string, backslash bool) (tokens []string, ok bool) {
+	tokens = make([]string, 0)
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens, true
+			}
+			i += end + 1
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens, false
+			}
+			i += end + 4
+		case strings.HasPrefix(query[i:], "$$"):
+			end := strings.Index(query[i+2:], "$$")
+			if end < 0 {
+				return tokens, false
+			}
+			i += end + 4
+		case ch == '\'' || ch == '"' || ch == '`' || ch == '[':
+			closing := ch
+			if ch == '[' {
+				closing = ']'
+			}
+			i++
+			for {
+				if i >= len(query) {
+					return tokens, false
+				}
+				if backslash && ch == '\'' && query[i] == '\\' {
+					i += 2
+					continue
+				}
+				if query[i] == closing {
+					// A doubled quote is an escaped quote
+					if i+1 < len(query) && query[i+1] == closing && closing != ']' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i++
+		case ch == '(' || ch == ')' || ch == ';':
+			tokens = append(tokens, string(ch))
+			i++
+		case isWordByte(ch):
+			end := i
+			for end < len(query) && isWordByte(query[end]) {
+				end++
+			}
+			tokens = append(tokens, strings.ToUpper(query[i:end]))
+			i = end
+		default:
+			i++
+		}
+	}
+	return tokens, true
+}
+
+func isWordByte(ch byte) bool {
+	return ch == '_' || ch == '$' || ch == '#' || ch == '@' || ch == '.' ||
+		ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// AllowedOperations returns the operations a binding permits: its
+// allowed_operations when it lists any, otherwise only reads (select, or GET
+// and HEAD for REST) when it is read-only. A nil result permits everything.
+func AllowedOperations(binding *catalog.SourceBinding) []string {
+	if len(binding.AllowedOperations) > 0 {
+		return binding.AllowedOperations
+	}
+	if !binding.ReadOnly {
+		return nil
+	}
+	if binding.SourceType == catalog.SourceTypeREST {
+		return []string{"GET", "HEAD"}
+	}
+	return []string{OperationSelect}
+}
+
+// Disallowed returns the first of ops that allowed does not permit, compared
+// case-insensitively, or "" when allowed permits them all
+func Disallowed(ops, allowed []string) string {
+	if allowed == nil {
+		return ""
+	}
+	for _, op := range ops {
+		permitted := false
+		for _, a := range allowed {
+			permitted = permitted || strings.EqualFold(op, a)
+		}
+		if !permitted {
+			return op
+		}
+	}
+	return ""
+}
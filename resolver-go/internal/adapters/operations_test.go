@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+func TestClassifySQL(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM prices WHERE symbol = ?", "select"},
+		{"  select price from prices", "select"},
+		{"WITH latest AS (SELECT * FROM prices) SELECT * FROM latest", "select"},
+		{"WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM t) SELECT n FROM t", "select"},
+		{"SELECT REPLACE(name, 'a', 'b'), TRUNCATE(price, 2) FROM prices", "select"},
+		{"SELECT * FROM prices WHERE note = 'DROP TABLE prices; DELETE'", "select"},
+		{"SELECT * FROM \"delete\" -- ; DROP TABLE prices\nWHERE x = 1", "select"},
+		{"SELECT * FROM prices /* ; UPDATE prices SET x = 1 */", "select"},
+		{"SELECT * FROM prices FOR UPDATE", "select"},
+		{"SELECT * FROM prices;", "select"},
+		// CTEs wrapping DML
+		{"WITH x AS (SELECT id FROM stale) DELETE FROM prices WHERE id IN (SELECT id FROM x)", "select,delete"},
+		{"WITH a AS (SELECT 1), b AS (SELECT 2) UPDATE prices SET price = 0", "select,update"},
+		{"WITH gone AS (DELETE FROM prices RETURNING *) SELECT * FROM gone", "delete,select"},
+		{"WITH t AS (SELECT 1) INSERT INTO audit SELECT * FROM t", "select,insert"},
+		// Statements hidden after a read
+		{"SELECT 1; DROP TABLE prices", "select,ddl"},
+		{"SELECT 1;\nTRUNCATE TABLE prices", "select,ddl"},
+		{"SELECT * FROM (DELETE FROM prices RETURNING *) d", "select,delete"},
+		{"SELECT * INTO backup FROM prices", "select,insert"},
+		{"EXPLAIN ANALYZE DELETE FROM prices", "select,delete"},
+		{"MERGE INTO prices USING staging ON (prices.id = staging.id) WHEN MATCHED THEN UPDATE SET price = 1", "merge"},
+		{"INSERT INTO prices VALUES (1, 2)", "insert"},
+		{"EXEC sp_purge_prices", "execute"},
+		{"BEGIN DELETE FROM prices; END;", "execute,delete,other"},
+		{"GRANT SELECT ON prices TO PUBLIC", "ddl"},
+		{"USE WAREHOUSE big", "other"},
+		// Literals that read differently with backslash escapes report both readings
+		{`SELECT '\'' AS q; DELETE FROM t; SELECT 'x'`, "select,other,delete"},
+		{`SELECT 'a\' AS q, ';DROP TABLE t; --' FROM prices`, "select,ddl"},
+		// Unterminated literals, identifiers and comments fail closed
+		{"SELECT 'open; DROP TABLE t", "select,other"},
+		{"SELECT \"open FROM t", "select,other"},
+		{"SELECT 1 /* ; DELETE FROM t", "select,other"},
+		{"SELECT $$ open", "select,other"},
+		{"'open", "other"},
+	} {
+		if got := strings.Join(ClassifySQL(tc.query), ","); got != tc.want {
+			t.Errorf("ClassifySQL(%q) = %s, want %s", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestOperationsUseDialectEscapes(t *testing.T) {
+	for _, tc := range []struct {
+		sourceType catalog.SourceType
+		query      string
+		want       string
+	}{
+		// Snowflake reads '\'' as one literal, so the DELETE is a statement
+		{catalog.SourceTypeSnowflake, `SELECT '\'' AS q; DELETE FROM t; SELECT 'x'`, "select,delete"},
+		{catalog.SourceTypeSnowflake, `SELECT 'it\'s' FROM prices`, "select"},
+		// Oracle and SQL Server don't, so the same text has an open literal
+		{catalog.SourceTypeOracle, `SELECT '\'' AS q; DELETE FROM t; SELECT 'x'`, "select,other"},
+		{catalog.SourceTypeMSSQL, `SELECT 'a\' AS q, ';DROP TABLE t; --' FROM prices`, "select"},
+		{catalog.SourceTypeSnowflake, `SELECT 'a\' AS q, ';DROP TABLE t; --' FROM prices`, "select,ddl"},
+		{catalog.SourceTypeOracle, `SELECT 'C:\data\' FROM prices`, "select"},
+	} {
+		if got := strings.Join(Operations(tc.sourceType, tc.query, nil), ","); got != tc.want {
+			t.Errorf("Operations(%s, %q) = %s, want %s", tc.sourceType, tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestOperationsAgainstBinding(t *testing.T) {
+	readOnly := &catalog.SourceBinding{SourceType: catalog.SourceTypeOracle, ReadOnly: true}
+	if op := Disallowed(Operations(readOnly.SourceType, "SELECT 1", nil), AllowedOperations(readOnly)); op != "" {
+		t.Errorf("expected a select to be allowed on a read-only binding, got %s", op)
+	}
+	if op := Disallowed(Operations(readOnly.SourceType, "UPDATE t SET x = 1", nil), AllowedOperations(readOnly)); op != "update" {
+		t.Errorf("expected the update to be refused, got %q", op)
+	}
+
+	writable := &catalog.SourceBinding{SourceType: catalog.SourceTypeOracle}
+	if op := Disallowed(Operations(writable.SourceType, "DROP TABLE t", nil), AllowedOperations(writable)); op != "" {
+		t.Errorf("expected a writable binding without allowed_operations to allow anything, got %s", op)
+	}
+	writable.AllowedOperations = []string{"Select", "Insert"}
+	if op := Disallowed(Operations(writable.SourceType, "insert into t values (1)", nil), AllowedOperations(writable)); op != "" {
+		t.Errorf("expected listed operations to match case-insensitively, got %s", op)
+	}
+
+	rest := &catalog.SourceBinding{SourceType: catalog.SourceTypeREST, ReadOnly: true}
+	if op := Disallowed(Operations(rest.SourceType, "", map[string]interface{}{}), AllowedOperations(rest)); op != "" {
+		t.Errorf("expected a GET to be allowed, got %s", op)
+	}
+	if op := Disallowed(Operations(rest.SourceType, "", map[string]interface{}{"method": "post"}), AllowedOperations(rest)); op != "POST" {
+		t.Errorf("expected the POST to be refused, got %q", op)
+	}
+	rest.AllowedOperations = []string{"GET", "POST"}
+	if op := Disallowed(Operations(rest.SourceType, "", map[string]interface{}{"method": "post"}), AllowedOperations(rest)); op != "" {
+		t.Errorf("expected a listed POST to be allowed, got %s", op)
+	}
+}
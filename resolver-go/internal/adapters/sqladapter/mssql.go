@@ -0,0 +1,43 @@
+package sqladapter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+)
+
+// MSSQLDriver is the database/sql driver name of go-mssqldb, linked in by
+// building with -tags mssql
+const MSSQLDriver = "sqlserver"
+
+// MSSQL is the adapter config of mssql bindings: the sqlserver:// URL of
+// adapters.MSSQLDSN, whose password must be a secret:// reference. Binds are
+// @p1, @p2...; max_rows_block is applied with SET ROWCOUNT, which unlike TOP
+// keeps any ORDER BY of the query valid. Every query sets it, so a pooled
+// session never carries another query's limit.
+func MSSQL() Config {
+	return Config{
+		Name:   "mssql",
+		Driver: MSSQLDriver,
+		DSN:    mssqlDSN,
+		Dialect: Dialect{
+			Marker: func(i int) string { return fmt.Sprintf("@p%d", i) },
+			Limit: func(query string, n int) string {
+				return fmt.Sprintf("SET ROWCOUNT %d;\n%s", n, strings.TrimSpace(query))
+			},
+		},
+		CredentialKeys: []string{"password"},
+	}
+}
+
+// mssqlDSN requires credentials; adapters.MSSQLDSN leaves them optional for
+// resolve results
+func mssqlDSN(config map[string]interface{}) (string, error) {
+	user, _ := config["user"].(string)
+	password, _ := config["password"].(string)
+	if user == "" || password == "" {
+		return "", fmt.Errorf("mssql connection needs user and password")
+	}
+	return adapters.MSSQLDSN(config)
+}
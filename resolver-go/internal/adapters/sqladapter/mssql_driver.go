@@ -0,0 +1,7 @@
+//go:build mssql
+
+package sqladapter
+
+// The SQL Server driver is optional: build with -tags mssql after
+// go get github.com/microsoft/go-mssqldb to serve mssql bindings
+import _ "github.com/microsoft/go-mssqldb"
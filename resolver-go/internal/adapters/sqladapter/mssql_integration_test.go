@@ -0,0 +1,59 @@
+//go:build mssql
+
+package sqladapter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// TestMSSQLIntegration runs against the SQL Server container of
+// QUICKSTART.md: go test -tags mssql ./internal/adapters/sqladapter -integration
+func TestMSSQLIntegration(t *testing.T) {
+	if !*integration {
+		t.Skip("needs -integration and a running SQL Server container")
+	}
+	server := os.Getenv("MSSQL_TEST_SERVER")
+	if server == "" {
+		server = "localhost"
+	}
+	a := New(MSSQL())
+	defer a.Close()
+	req := &adapters.Request{
+		Path:        "test/values/X",
+		BindingPath: "test/values",
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeMSSQL},
+		Config:      map[string]interface{}{"server": server, "database": "master", "user": "sa", "password": os.Getenv("MSSQL_SA_PASSWORD")},
+		SecretKeys:  []string{"password"},
+		Query: "SELECT ? AS code, CAST(12.5 AS DECIMAL(9,2)) AS amount, SYSDATETIME() AS as_of " +
+			"FROM (VALUES (1), (2), (3), (4), (5)) AS v(n) ORDER BY n",
+		ParamStyle: "qmark",
+		Params:     []adapters.Param{{Name: "segment_2", Value: "X"}},
+		MaxRows:    3,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := a.Fetch(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RowCount != 3 || !result.Truncated {
+		t.Errorf("expected 3 rows, truncated, got %d (truncated %t)", result.RowCount, result.Truncated)
+	}
+	if row := result.Rows[0]; row["code"] != "X" || row["amount"] != json.Number("12.50") {
+		t.Errorf("unexpected row %v", row)
+	}
+
+	// The limit must not stay on the pooled session
+	req.MaxRows = 0
+	if result, err := a.Fetch(ctx, req); err != nil || result.RowCount != 5 {
+		t.Errorf("expected every row without a limit, got %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,53 @@
+package sqladapter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+)
+
+// OracleDriver is the database/sql driver name of godror, linked in by
+// building with -tags oracle
+const OracleDriver = "godror"
+
+// Oracle is the adapter config of oracle bindings: user and password, which
+// must be a secret:// reference, with the dsn or host/port/service_name of
+// the resolve result. Binds are positional (:1, :2...); max_rows_block
+// becomes a ROWNUM filter.
+func Oracle() Config {
+	return Config{
+		Name:   "oracle",
+		Driver: OracleDriver,
+		DSN:    oracleDSN,
+		Dialect: Dialect{
+			Marker: func(i int) string { return fmt.Sprintf(":%d", i) },
+			Limit: func(query string, n int) string {
+				if n == 0 {
+					return query
+				}
+				return fmt.Sprintf("SELECT * FROM (\n%s\n) WHERE ROWNUM <= %d", strings.TrimRight(strings.TrimSpace(query), ";"), n)
+			},
+		},
+		CredentialKeys: []string{"password"},
+	}
+}
+
+// oracleDSN builds a godror connection string,
+// user="..." password="..." connectString="...", around the connect string
+// of adapters.OracleDSN
+func oracleDSN(config map[string]interface{}) (string, error) {
+	connect, err := adapters.OracleDSN(config)
+	if err != nil {
+		return "", err
+	}
+	user, _ := config["user"].(string)
+	password, _ := config["password"].(string)
+	if user == "" || password == "" {
+		return "", fmt.Errorf("oracle connection needs user and password")
+	}
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+	return fmt.Sprintf("user=%s password=%s connectString=%s", quote(user), quote(password), quote(connect)), nil
+}
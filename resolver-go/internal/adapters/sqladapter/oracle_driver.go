@@ -0,0 +1,7 @@
+//go:build oracle
+
+package sqladapter
+
+// The Oracle driver is optional: build with -tags oracle after
+// go get github.com/godror/godror to serve oracle bindings
+import _ "github.com/godror/godror"
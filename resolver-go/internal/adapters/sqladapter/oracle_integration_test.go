@@ -0,0 +1,52 @@
+//go:build oracle
+
+package sqladapter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// TestOracleIntegration runs against the gvenzl/oracle-free container of
+// QUICKSTART.md: go test -tags oracle ./internal/adapters/sqladapter -integration
+func TestOracleIntegration(t *testing.T) {
+	if !*integration {
+		t.Skip("needs -integration and a running Oracle container")
+	}
+	dsn := os.Getenv("ORACLE_TEST_DSN")
+	if dsn == "" {
+		dsn = "localhost:1521/FREEPDB1"
+	}
+	a := New(Oracle())
+	defer a.Close()
+	req := &adapters.Request{
+		Path:        "test/dual/X",
+		BindingPath: "test/dual",
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeOracle},
+		Config:      map[string]interface{}{"dsn": dsn, "user": "system", "password": os.Getenv("ORACLE_PASSWORD")},
+		SecretKeys:  []string{"password"},
+		Query:       "SELECT :segment_2 AS code, 12.5 AS amount, SYSTIMESTAMP AS as_of FROM dual CONNECT BY LEVEL <= 5",
+		ParamStyle:  "named",
+		Params:      []adapters.Param{{Name: "segment_2", Value: "X"}},
+		MaxRows:     3,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := a.Fetch(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RowCount != 3 || !result.Truncated {
+		t.Errorf("expected 3 rows, truncated, got %d (truncated %t)", result.RowCount, result.Truncated)
+	}
+	if row := result.Rows[0]; row["CODE"] != "X" || row["AMOUNT"] != json.Number("12.5") {
+		t.Errorf("unexpected row %v", row)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build snowflake
+
+package sqladapter
+
+// The Snowflake driver is optional: build with -tags snowflake after
+// go get github.com/snowflakedb/gosnowflake to serve snowflake bindings
+import _ "github.com/snowflakedb/gosnowflake"
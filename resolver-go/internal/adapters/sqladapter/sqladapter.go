@@ -0,0 +1,465 @@
+// Package sqladapter runs the bound queries of relational bindings through
+// database/sql. One Adapter per source type pairs a driver with a DSN
+// builder and a Dialect; drivers are optional and linked in with build tags
+// (snowflake, oracle, mssql), and RegisterAvailable registers the adapters
+// whose driver is present.
+package sqladapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// Dialect is how a database writes bind markers and row limits
+type Dialect struct {
+	// Marker returns the bind marker of the i-th (1-based) parameter
+	Marker func(i int) string
+	// Limit returns a query returning at most n rows of query; n is 0 when
+	// there is no limit
+	Limit func(query string, n int) string
+}
+
+// Config describes the adapter of one relational source type
+type Config struct {
+	Name    string              // Source name for errors, e.g. "oracle"
+	Driver  string              // database/sql driver name
+	DSN     adapters.DSNBuilder // Builds the driver DSN from a config whose secrets are resolved
+	Dialect Dialect
+	// CredentialKeys are config keys that must come from secret://
+	// references
+	CredentialKeys []string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+}
+
+// Pool limits used when a Config leaves them zero
+const (
+	defaultMaxOpenConns    = 8
+	defaultMaxIdleConns    = 2
+	defaultConnMaxIdleTime = 10 * time.Minute
+)
+
+// Adapter runs the bound query of a binding and returns its rows. Each
+// distinct DSN shares one pooled *sql.DB, kept while a binding uses it: a
+// pool is closed when its last binding is released or moves to another DSN.
+// access_policy.max_rows_block is applied with the dialect's limit and
+// checked while reading.
+type Adapter struct {
+	cfg      Config
+	mu       sync.Mutex
+	pools    map[string]*sql.DB // By dsnKey, so no password is held as a key
+	bindings map[string]string  // Binding path -> dsnKey of the pool it uses
+}
+
+// New creates an adapter for a relational source type
+func New(cfg Config) *Adapter {
+	if cfg.MaxOpenConns == 0 {
+		cfg.MaxOpenConns = defaultMaxOpenConns
+	}
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns = defaultMaxIdleConns
+	}
+	if cfg.ConnMaxIdleTime == 0 {
+		cfg.ConnMaxIdleTime = defaultConnMaxIdleTime
+	}
+	return &Adapter{cfg: cfg, pools: make(map[string]*sql.DB), bindings: make(map[string]string)}
+}
+
+// RegisterAvailable registers the adapter of each relational source type
+// whose driver is linked in
+func RegisterAvailable(reg *adapters.Registry) {
+	configs := map[catalog.SourceType]Config{
+		catalog.SourceTypeSnowflake: Snowflake(),
+		catalog.SourceTypeOracle:    Oracle(),
+		catalog.SourceTypeMSSQL:     MSSQL(),
+	}
+	drivers := make(map[string]bool)
+	for _, name := range sql.Drivers() {
+		drivers[name] = true
+	}
+	for sourceType, cfg := range configs {
+		if drivers[cfg.Driver] {
+			reg.Register(sourceType, New(cfg))
+		}
+	}
+}
+
+// Fetch implements adapters.Adapter
+func (a *Adapter) Fetch(ctx context.Context, req *adapters.Request) (*adapters.DataResult, error) {
+	if req.Query == "" {
+		return nil, fmt.Errorf("binding has no query")
+	}
+	for _, key := range a.cfg.CredentialKeys {
+		if _, ok := req.Config[key]; ok && !req.FromSecret(key) {
+			return nil, fmt.Errorf("connection %s must be a secret:// reference, not plain config", key)
+		}
+	}
+	dsn, err := a.cfg.DSN(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := bindMarkers(req.Query, req.ParamStyle, req.Params, a.cfg.Dialect.Marker)
+	if err != nil {
+		return nil, err
+	}
+	if a.cfg.Dialect.Limit != nil {
+		limit := 0
+		if req.MaxRows > 0 {
+			limit = req.MaxRows + 1
+		}
+		query = a.cfg.Dialect.Limit(query, limit)
+	}
+
+	columns, rows, truncated, err := queryRows(ctx, a.pool(req.BindingPath, dsn), query, args, req.MaxRows)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, scrubError(a.cfg.Name, err, dsn, secretValues(req))
+	}
+	result, err := adapters.NewResult(req, rows)
+	if err != nil {
+		return nil, err
+	}
+	if req.Schema == nil || len(req.Schema.Columns) == 0 {
+		result.Columns = columns
+	}
+	result.Truncated = result.Truncated || truncated
+	return result, nil
+}
+
+// ListVersions implements adapters.VersionLister, running the bound
+// versions_query the service sets as req.Query and returning its first
+// column
+func (a *Adapter) ListVersions(ctx context.Context, req *adapters.Request) ([]string, error) {
+	if req.Query == "" {
+		return nil, &adapters.RequestError{Path: req.Path, Message: fmt.Sprintf("the binding at %s has no %s", req.BindingPath, catalog.VersionsQueryKey)}
+	}
+	all := *req
+	all.Schema, all.MaxRows = nil, 0
+	result, err := a.Fetch(ctx, &all)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(result.Rows))
+	if len(result.Columns) == 0 {
+		return versions, nil
+	}
+	for _, row := range result.Rows {
+		if v := row[result.Columns[0]]; v != nil {
+			versions = append(versions, fmt.Sprint(v))
+		}
+	}
+	return versions, nil
+}
+
+// dsnKey identifies the pool of a DSN by its SHA-256
+func dsnKey(dsn string) string {
+	sum := sha256.Sum256([]byte(dsn))
+	return hex.EncodeToString(sum[:])
+}
+
+// pool returns the *sql.DB of a DSN, opening it on first use, and records
+// that the binding at bindingPath uses it. A pool the binding used before
+// and no other binding uses is closed.
+func (a *Adapter) pool(bindingPath, dsn string) *sql.DB {
+	key := dsnKey(dsn)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if previous, ok := a.bindings[bindingPath]; ok && previous != key {
+		a.bindings[bindingPath] = key
+		if db := a.unused(previous); db != nil {
+			// Queries still running on it finish first
+			go db.Close()
+		}
+	}
+	a.bindings[bindingPath] = key
+	if db, ok := a.pools[key]; ok {
+		return db
+	}
+	// sql.Open only fails for an unknown driver, which RegisterAvailable
+	// rules out; connection errors surface on the first query
+	db, _ := sql.Open(a.cfg.Driver, dsn)
+	db.SetMaxOpenConns(a.cfg.MaxOpenConns)
+	db.SetMaxIdleConns(a.cfg.MaxIdleConns)
+	db.SetConnMaxIdleTime(a.cfg.ConnMaxIdleTime)
+	a.pools[key] = db
+	return db
+}
+
+// unused removes and returns the pool of key when no binding uses it, or
+// returns nil. It is called with a.mu held.
+func (a *Adapter) unused(key string) *sql.DB {
+	for _, k := range a.bindings {
+		if k == key {
+			return nil
+		}
+	}
+	db := a.pools[key]
+	delete(a.pools, key)
+	return db
+}
+
+// Release implements adapters.BindingReleaser, closing the pool of a removed
+// binding unless another binding uses it
+func (a *Adapter) Release(bindingPath string) error {
+	a.mu.Lock()
+	key, ok := a.bindings[bindingPath]
+	delete(a.bindings, bindingPath)
+	var db *sql.DB
+	if ok {
+		db = a.unused(key)
+	}
+	a.mu.Unlock()
+	if db == nil {
+		return nil
+	}
+	return db.Close()
+}
+
+// Close closes every connection pool
+func (a *Adapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var errs []error
+	for key, db := range a.pools {
+		errs = append(errs, db.Close())
+		delete(a.pools, key)
+	}
+	a.bindings = make(map[string]string)
+	return errors.Join(errs...)
+}
+
+// bindMarkers rewrites the bind markers of a query in a resolve param style
+// (qmark ?, named :name or pyformat %(name)s) to the dialect's markers and
+// returns the values in marker order. Markers inside quoted literals and
+// identifiers are left alone.
+func bindMarkers(query, style string, params []adapters.Param, marker func(int) string) (string, []interface{}, error) {
+	if style == "" || len(params) == 0 {
+		return query, nil, nil
+	}
+	if style != "qmark" && style != "named" && style != "pyformat" {
+		return "", nil, fmt.Errorf("unsupported param style %q", style)
+	}
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		values[p.Name] = p.Value
+	}
+
+	var out strings.Builder
+	args := make([]interface{}, 0, len(params))
+	bind := func(value string) {
+		args = append(args, value)
+		out.WriteString(marker(len(args)))
+	}
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case style == "pyformat" && strings.HasPrefix(query[i:], "%%"):
+			// Literal % was doubled throughout for pyformat drivers
+			out.WriteByte('%')
+			i++
+			continue
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case style == "qmark" && ch == '?':
+			if len(args) == len(params) {
+				return "", nil, fmt.Errorf("query has more ? markers than parameters")
+			}
+			bind(params[len(args)].Value)
+			continue
+		case style == "named" && ch == ':' && i+1 < len(query) && isNameByte(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			end := i + 1
+			for end < len(query) && isNameByte(query[end]) {
+				end++
+			}
+			value, ok := values[query[i+1:end]]
+			if !ok {
+				return "", nil, fmt.Errorf("query has no value for :%s", query[i+1:end])
+			}
+			bind(value)
+			i = end - 1
+			continue
+		case style == "pyformat" && ch == '%' && strings.HasPrefix(query[i:], "%("):
+			end := strings.Index(query[i:], ")s")
+			if end < 0 {
+				return "", nil, fmt.Errorf("unterminated %%( marker")
+			}
+			name := query[i+2 : i+end]
+			value, ok := values[name]
+			if !ok {
+				return "", nil, fmt.Errorf("query has no value for %%(%s)s", name)
+			}
+			bind(value)
+			i += end + 1
+			continue
+		}
+		out.WriteByte(ch)
+	}
+	return out.String(), args, nil
+}
+
+func isNameByte(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// queryRows runs a query and reads its rows. With maxRows set, reading stops
+// after maxRows rows and truncated reports whether more were available.
+// Cancelling ctx aborts the query.
+func queryRows(ctx context.Context, db *sql.DB, query string, args []interface{}, maxRows int) (columns []string, rows []map[string]interface{}, truncated bool, err error) {
+	result, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer result.Close()
+
+	types, err := result.ColumnTypes()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	columns = make([]string, len(types))
+	for i, t := range types {
+		columns[i] = t.Name()
+	}
+	rows = make([]map[string]interface{}, 0)
+	for result.Next() {
+		if maxRows > 0 && len(rows) == maxRows {
+			truncated = true
+			break
+		}
+		cells := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range cells {
+			ptrs[i] = &cells[i]
+		}
+		if err := result.Scan(ptrs...); err != nil {
+			return nil, nil, false, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, name := range columns {
+			row[name] = Cell(cells[i], types[i].DatabaseTypeName())
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	return columns, rows, truncated, nil
+}
+
+// numericTypes are the database types of exact numbers, which drivers
+// return as text to keep their precision
+var numericTypes = map[string]bool{
+	"NUMBER": true, "NUMERIC": true, "DECIMAL": true, "FIXED": true,
+	"MONEY": true, "SMALLMONEY": true,
+}
+
+// Cell converts a scanned driver value to a DataResult cell: string, int64,
+// float64, json.Number, bool or nil. Exact numbers (NUMBER, DECIMAL...)
+// become int64 when whole and within range, and otherwise a json.Number of
+// the driver's text, which encodes as a JSON number without rounding it
+// through float64; DATE columns become YYYY-MM-DD and other times
+// (DATETIME2, TIMESTAMP...) RFC 3339.
+func Cell(v interface{}, dbType string) interface{} {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			v = value
+		}
+	}
+	dbType = strings.ToUpper(dbType)
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return textCell(string(value), dbType)
+	case string:
+		return textCell(value, dbType)
+	case time.Time:
+		if dbType == "DATE" {
+			return value.Format("2006-01-02")
+		}
+		return value.Format(time.RFC3339Nano)
+	case bool, int64, float64:
+		return value
+	}
+	// Named and sized types, such as a driver's Number string type
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return textCell(rv.String(), dbType)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return fmt.Sprint(v)
+}
+
+// textCell parses the text of an exact number column; other text is kept
+func textCell(s, dbType string) interface{} {
+	if !numericTypes[dbType] {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal([]byte(s), &n) != nil {
+		return s
+	}
+	return n
+}
+
+// secretValues returns the config values that came from secret references
+func secretValues(req *adapters.Request) []string {
+	values := make([]string, 0, len(req.SecretKeys))
+	for _, key := range req.SecretKeys {
+		if s, ok := req.Config[key].(string); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// scrubError returns a driver error with the DSN and secret values masked,
+// as drivers may echo their connection string
+func scrubError(name string, err error, dsn string, secrets []string) error {
+	message := err.Error()
+	for _, s := range append([]string{dsn}, secrets...) {
+		if s != "" {
+			message = strings.ReplaceAll(message, s, "***")
+		}
+	}
+	return fmt.Errorf("%s query: %s", name, message)
+}
@@ -0,0 +1,369 @@
+package sqladapter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// integration runs the tests against the dockerized databases of
+// QUICKSTART.md; they also need the driver's build tag
+var integration = flag.Bool("integration", false, "run against dockerized databases")
+
+// fakeDriver is a database/sql driver recording the DSNs and queries it is
+// given and answering every query with fakeRows
+type fakeDriver struct {
+	mu      sync.Mutex
+	dsns    []string
+	queries []string
+	args    [][]driver.NamedValue
+}
+
+var testDriver = &fakeDriver{}
+
+// fakeColumns and fakeRows answer every fake query
+var (
+	fakeColumns = []struct{ name, dbType string }{
+		{"SYMBOL", "VARCHAR"}, {"PRICE", "NUMBER"}, {"LOT", "NUMBER"}, {"AS_OF", "DATETIME2"},
+	}
+	fakeRows = [][]driver.Value{
+		{"AAPL", []byte("189.50"), []byte("100"), time.Date(2026, 1, 2, 16, 30, 0, 0, time.UTC)},
+		{"MSFT", []byte("410.25"), []byte("50"), time.Date(2026, 1, 2, 16, 30, 0, 0, time.UTC)},
+		{"VOD", []byte("0.72"), []byte("1000"), nil},
+	}
+)
+
+func init() {
+	sql.Register("sqladaptertest", testDriver)
+}
+
+func (d *fakeDriver) Open(dsn string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dsns = append(d.dsns, dsn)
+	return fakeConn{d: d, dsn: dsn}, nil
+}
+
+func (d *fakeDriver) last() (query string, args []driver.NamedValue, dsn string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries[len(d.queries)-1], d.args[len(d.args)-1], d.dsns[len(d.dsns)-1]
+}
+
+type fakeConn struct {
+	d   *fakeDriver
+	dsn string
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.queries = append(c.d.queries, query)
+	c.d.args = append(c.d.args, args)
+	if strings.Contains(query, "missing_table") {
+		// Drivers may echo their connection string in errors
+		return nil, fmt.Errorf("table not found (connection %s)", c.dsn)
+	}
+	return &fakeResult{}, nil
+}
+
+type fakeResult struct{ next int }
+
+func (r *fakeResult) Close() error { return nil }
+
+func (r *fakeResult) Columns() []string {
+	names := make([]string, len(fakeColumns))
+	for i, c := range fakeColumns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (r *fakeResult) ColumnTypeDatabaseTypeName(i int) string { return fakeColumns[i].dbType }
+
+func (r *fakeResult) Next(dest []driver.Value) error {
+	if r.next == len(fakeRows) {
+		return io.EOF
+	}
+	copy(dest, fakeRows[r.next])
+	r.next++
+	return nil
+}
+
+// newTestAdapter creates an adapter for a source type's config on the fake
+// driver
+func newTestAdapter(t *testing.T, cfg Config) *Adapter {
+	cfg.Driver = "sqladaptertest"
+	a := New(cfg)
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func snowflakeRequest() *adapters.Request {
+	return &adapters.Request{
+		Path:        "prices/equity/AAPL",
+		BindingPath: "prices/equity",
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeSnowflake},
+		Config: map[string]interface{}{
+			"account": "acme", "database": "MARKET_DATA", "schema": "PRICES",
+			"warehouse": "WH_S", "user": "svc_resolver", "password": "hunter2",
+		},
+		SecretKeys: []string{"password"},
+		Query:      "SELECT symbol, price, lot, as_of FROM equity WHERE symbol = %(segment_2)s AND note LIKE 'x%%'",
+		ParamStyle: "pyformat",
+		Params:     []adapters.Param{{Name: "segment_2", Value: "AAPL"}},
+	}
+}
+
+func TestSnowflakeRunsBoundQuery(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	req := snowflakeRequest()
+	req.MaxRows = 2
+
+	result, err := a.Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(result.Columns, ",") != "SYMBOL,PRICE,LOT,AS_OF" {
+		t.Errorf("expected the query's column order, got %v", result.Columns)
+	}
+	if result.RowCount != 2 || !result.Truncated {
+		t.Errorf("expected 2 rows of 3 and truncated, got %+v", result)
+	}
+	if row := result.Rows[0]; row["PRICE"] != json.Number("189.50") || row["LOT"] != int64(100) || row["AS_OF"] != "2026-01-02T16:30:00Z" {
+		t.Errorf("expected NUMBER and DATETIME2 cells converted, got %v", row)
+	}
+
+	query, args, dsn := testDriver.last()
+	if !strings.Contains(query, "WHERE symbol = ? AND note LIKE 'x%'") || !strings.HasSuffix(query, ") LIMIT 3") {
+		t.Errorf("expected a qmark query limited to max_rows_block+1, got %q", query)
+	}
+	if len(args) != 1 || args[0].Value != "AAPL" {
+		t.Errorf("expected the bound segment, got %v", args)
+	}
+	if dsn != "svc_resolver:hunter2@acme/MARKET_DATA/PRICES?warehouse=WH_S" {
+		t.Errorf("unexpected DSN %q", dsn)
+	}
+}
+
+func TestAdapterPoolsPerConfig(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+
+	for i := 0; i < 3; i++ {
+		if _, err := a.Fetch(context.Background(), snowflakeRequest()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	other := snowflakeRequest()
+	other.BindingPath = "prices/equity_large"
+	other.Config["warehouse"] = "WH_L"
+	if _, err := a.Fetch(context.Background(), other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.pools) != 2 {
+		t.Errorf("expected a pool per warehouse, got %d", len(a.pools))
+	}
+	for key := range a.pools {
+		if strings.Contains(key, "hunter2") {
+			t.Errorf("expected pools keyed without the password, got %q", key)
+		}
+	}
+}
+
+func TestAdapterClosesPoolsNoBindingUses(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	if _, err := a.Fetch(context.Background(), snowflakeRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := a.pools[a.bindings["prices/equity"]]
+
+	// The binding moving to another warehouse leaves its old pool unused
+	moved := snowflakeRequest()
+	moved.Config["warehouse"] = "WH_L"
+	if _, err := a.Fetch(context.Background(), moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.pools) != 1 || a.pools[a.bindings["prices/equity"]] == first {
+		t.Errorf("expected only the new warehouse's pool, got %d", len(a.pools))
+	}
+	deadline := time.Now().Add(time.Second)
+	for first.Ping() == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := first.Ping(); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected the old pool closed, got %v", err)
+	}
+
+	shared := snowflakeRequest()
+	shared.BindingPath = "prices/equity_copy"
+	shared.Config["warehouse"] = "WH_L"
+	if _, err := a.Fetch(context.Background(), shared); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := a.Release("prices/equity"); err != nil || len(a.pools) != 1 {
+		t.Errorf("expected the pool kept for the binding still using it, got %d (%v)", len(a.pools), err)
+	}
+	if err := a.Release("prices/equity_copy"); err != nil || len(a.pools) != 0 || len(a.bindings) != 0 {
+		t.Errorf("expected the pool closed with its last binding, got %d (%v)", len(a.pools), err)
+	}
+}
+
+func TestAdapterRequiresSecretCredentials(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	req := snowflakeRequest()
+	req.SecretKeys = nil
+
+	_, err := a.Fetch(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "password must be a secret:// reference") {
+		t.Errorf("expected plain config credentials to be refused, got %v", err)
+	}
+	if len(a.pools) != 0 {
+		t.Error("expected no connection to be opened")
+	}
+}
+
+func TestAdapterStopsOnCancel(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := a.Fetch(ctx, snowflakeRequest()); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestOracleBindsPositionallyWithRownum(t *testing.T) {
+	a := newTestAdapter(t, Oracle())
+	req := &adapters.Request{
+		Path:        "prices/fx/EURUSD",
+		BindingPath: "prices/fx",
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeOracle},
+		Config:      map[string]interface{}{"dsn": "db.internal:1521/FX", "user": "fx_reader", "password": "pw"},
+		SecretKeys:  []string{"password"},
+		Query:       "SELECT * FROM fx WHERE pair = :segment_2 AND note <> ':segment_2' OR alias = :segment_2",
+		ParamStyle:  "named",
+		Params:      []adapters.Param{{Name: "segment_2", Value: "EURUSD"}},
+		MaxRows:     10,
+	}
+
+	if _, err := a.Fetch(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query, args, dsn := testDriver.last()
+	if !strings.Contains(query, "WHERE pair = :1 AND note <> ':segment_2' OR alias = :2") || !strings.HasSuffix(query, "WHERE ROWNUM <= 11") {
+		t.Errorf("unexpected query %q", query)
+	}
+	if len(args) != 2 || args[1].Value != "EURUSD" {
+		t.Errorf("expected the value bound at each marker, got %v", args)
+	}
+	if dsn != `user="fx_reader" password="pw" connectString="db.internal:1521/FX"` {
+		t.Errorf("unexpected DSN %q", dsn)
+	}
+}
+
+func TestMSSQLBindsWithRowcount(t *testing.T) {
+	a := newTestAdapter(t, MSSQL())
+	req := &adapters.Request{
+		Path:        "risk/var/desk1",
+		BindingPath: "risk/var",
+		Binding:     &catalog.SourceBinding{SourceType: catalog.SourceTypeMSSQL},
+		Config:      map[string]interface{}{"server": "sql01", "database": "RISK", "user": "risk_ro", "password": "pw"},
+		SecretKeys:  []string{"password"},
+		Query:       "SELECT * FROM var WHERE desk = ? AND label <> '?' ORDER BY as_of DESC",
+		ParamStyle:  "qmark",
+		Params:      []adapters.Param{{Name: "segment_2", Value: "desk1"}},
+	}
+
+	if _, err := a.Fetch(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query, args, dsn := testDriver.last()
+	if query != "SET ROWCOUNT 0;\nSELECT * FROM var WHERE desk = @p1 AND label <> '?' ORDER BY as_of DESC" {
+		t.Errorf("unexpected query %q", query)
+	}
+	if len(args) != 1 || args[0].Value != "desk1" || dsn != "sqlserver://risk_ro:pw@sql01:1433?database=RISK" {
+		t.Errorf("unexpected args %v or DSN %q", args, dsn)
+	}
+}
+
+func TestListVersionsReturnsFirstColumn(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	req := snowflakeRequest()
+	req.Query = "SELECT DISTINCT symbol FROM equity"
+	req.ParamStyle, req.Params = "", nil
+	req.MaxRows = 1
+
+	versions, err := a.ListVersions(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(versions, ",") != "AAPL,MSFT,VOD" {
+		t.Errorf("expected every value of the first column, got %v", versions)
+	}
+	if query, _, _ := testDriver.last(); strings.Contains(query, "LIMIT") {
+		t.Errorf("expected versions_query not to be limited to max_rows_block, got %q", query)
+	}
+
+	req.Query = ""
+	if _, err := a.ListVersions(context.Background(), req); err == nil || !strings.Contains(err.Error(), "has no versions_query") {
+		t.Errorf("expected a binding without versions_query to be refused, got %v", err)
+	}
+}
+
+func TestDriverErrorsHideConnection(t *testing.T) {
+	a := newTestAdapter(t, Snowflake())
+	req := snowflakeRequest()
+	req.Query = "SELECT * FROM missing_table"
+	req.ParamStyle, req.Params = "", nil
+
+	_, err := a.Fetch(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "snowflake query: table not found") {
+		t.Fatalf("expected the driver error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "hunter2") || strings.Contains(err.Error(), "svc_resolver@") {
+		t.Errorf("expected the DSN to be masked, got %v", err)
+	}
+}
+
+func TestCell(t *testing.T) {
+	type number string
+	day := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value  interface{}
+		dbType string
+		want   interface{}
+	}{
+		{number("12.5"), "NUMBER", json.Number("12.5")},
+		{[]byte("12345678901234567.89"), "DECIMAL", json.Number("12345678901234567.89")},
+		{[]byte("NaN"), "NUMBER", "NaN"},
+		{[]byte("12"), "DECIMAL", int64(12)},
+		{[]byte("12"), "VARCHAR", "12"},
+		{int32(7), "INT", int64(7)},
+		{day, "DATE", "2026-03-31"},
+		{day, "TIMESTAMP_NTZ", "2026-03-31T00:00:00Z"},
+		{nil, "NUMBER", nil},
+	} {
+		if got := Cell(tc.value, tc.dbType); got != tc.want {
+			t.Errorf("Cell(%v, %s) = %#v, want %#v", tc.value, tc.dbType, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package adapters
+
+import (
+	"context"
+)
+
+// Static serves the rows held in a static binding: config.rows, or the
+// config.data_file read when the catalog loaded. Each moniker segment below
+// the binding filters the key column at its position (config.key_columns),
+// case-insensitively; ALL matches every value. Versions are the values of
+// config.version_column in the matching rows.
+type Static struct{}
+
+// Fetch implements Adapter
+func (Static) Fetch(_ context.Context, req *Request) (*DataResult, error) {
+	rows, err := req.Binding.StaticRows()
+	if err != nil {
+		return nil, err
+	}
+	matched, err := FilterRows(req, rows)
+	if err != nil {
+		return nil, err
+	}
+	return NewResult(req, matched)
+}
+
+// ListVersions implements VersionLister
+func (s Static) ListVersions(ctx context.Context, req *Request) ([]string, error) {
+	return listColumnVersions(ctx, s, req)
+}
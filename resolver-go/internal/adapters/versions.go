@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// VersionLister is implemented by adapters that can list the versions of a
+// binding's data. For SQL bindings the service sets Request.Query to the
+// bound versions_query; other adapters read the binding config.
+type VersionLister interface {
+	ListVersions(ctx context.Context, req *Request) ([]string, error)
+}
+
+// versionLayouts are the date forms normalized to YYYYMMDD versions
+var versionLayouts = []string{"20060102", "2006-01-02", time.RFC3339Nano, "2006-01-02 15:04:05"}
+
+// SortVersions returns versions without empty values or duplicates, oldest
+// first. Dates are normalized to YYYYMMDD, the form of date@ values, so they
+// sort chronologically; other versions sort as text.
+func SortVersions(versions []string) []string {
+	seen := make(map[string]bool, len(versions))
+	sorted := make([]string, 0, len(versions))
+	for _, v := range versions {
+		for _, layout := range versionLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				v = t.Format("20060102")
+				break
+			}
+		}
+		if v != "" && !seen[v] {
+			seen[v] = true
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// listColumnVersions fetches every row of req through a and returns the
+// values of its config.version_column
+func listColumnVersions(ctx context.Context, a Adapter, req *Request) ([]string, error) {
+	column, _ := req.Config[catalog.VersionColumnKey].(string)
+	if column == "" {
+		return nil, &RequestError{Path: req.Path, Message: fmt.Sprintf("the binding at %s has no %s", req.BindingPath, catalog.VersionColumnKey)}
+	}
+	all := *req
+	all.MaxRows = 0
+	result, err := a.Fetch(ctx, &all)
+	if err != nil {
+		return nil, err
+	}
+	return columnValues(result, column)
+}
+
+// columnValues returns the non-null values of a result column as text
+func columnValues(result *DataResult, column string) ([]string, error) {
+	found := false
+	for _, c := range result.Columns {
+		found = found || c == column
+	}
+	if !found && len(result.Rows) > 0 {
+		return nil, fmt.Errorf("version column %s is not in the data (columns: %v)", column, result.Columns)
+	}
+	values := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if v := row[column]; v != nil {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	return values, nil
+}
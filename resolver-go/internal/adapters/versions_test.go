@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+func TestSortVersions(t *testing.T) {
+	got := SortVersions([]string{"2026-01-05", "20260102", "2026-01-05T00:00:00Z", "", "2026-01-07 00:00:00", "v2", "v10"})
+	if strings.Join(got, ",") != "20260102,20260105,20260107,v10,v2" {
+		t.Errorf("expected normalized, distinct, sorted versions, got %v", got)
+	}
+}
+
+func TestStaticListsVersionColumn(t *testing.T) {
+	req := &Request{
+		Path:        "risk/pnl/fx",
+		BindingPath: "risk/pnl",
+		Segments:    []string{"fx"},
+		Binding: &catalog.SourceBinding{SourceType: catalog.SourceTypeStatic, Config: map[string]interface{}{
+			"rows": []interface{}{
+				map[string]interface{}{"desk": "fx", "as_of": "2026-01-07"},
+				map[string]interface{}{"desk": "fx", "as_of": nil},
+				map[string]interface{}{"desk": "rates", "as_of": "2026-01-08"},
+			},
+		}},
+		Config:  map[string]interface{}{"key_columns": []interface{}{"desk"}, "version_column": "as_of"},
+		MaxRows: 1,
+	}
+
+	versions, err := Static{}.ListVersions(context.Background(), req)
+	if err != nil || len(versions) != 1 || versions[0] != "2026-01-07" {
+		t.Errorf("expected the fx version, ignoring nulls and max_rows_block, got %v (%v)", versions, err)
+	}
+
+	delete(req.Config, "version_column")
+	if _, err := (Static{}).ListVersions(context.Background(), req); err == nil || !strings.Contains(err.Error(), "has no version_column") {
+		t.Errorf("expected a missing version_column to be reported, got %v", err)
+	}
+}
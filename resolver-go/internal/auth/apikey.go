@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// API key scopes. Each route requires one, and a key only reaches the routes
+// whose scope it holds: admin does not imply read or resolve.
+const (
+	ScopeRead    = "read"    // Catalog browsing, search and metadata
+	ScopeResolve = "resolve" // Resolving and fetching monikers
+	ScopeAdmin   = "admin"   // Catalog changes, imports and reloads
+)
+
+// Scopes lists the valid API key scopes
+func Scopes() []string {
+	return []string{ScopeRead, ScopeResolve, ScopeAdmin}
+}
+
+// APIKey is an entry of an API key file: the key itself or its SHA-256 in
+// hex, so files need not hold key material in the clear. Roles are given to
+// the caller like the roles of a JWT, e.g. for auth.admin_roles.
+type APIKey struct {
+	ID        string   `yaml:"id"`
+	Name      string   `yaml:"name"`
+	Scopes    []string `yaml:"scopes"`
+	Roles     []string `yaml:"roles"`
+	Key       string   `yaml:"key"`
+	KeySHA256 string   `yaml:"key_sha256"`
+}
+
+// apiKeyEntry is a validated APIKey, holding only the key's digest
+type apiKeyEntry struct {
+	id     string
+	name   string
+	scopes []string
+	roles  []string
+	digest [sha256.Size]byte
+}
+
+// APIKeySet validates the keys callers present in X-API-Key or an
+// Authorization: ApiKey header. It implements the same Validate as
+// JWTValidator, turning a key into the caller identity of its ID.
+type APIKeySet struct {
+	keys []apiKeyEntry
+}
+
+// ParseAPIKeys parses a YAML (or JSON) list of API keys
+func ParseAPIKeys(data []byte) ([]APIKey, error) {
+	var keys []APIKey
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// NewAPIKeySet checks keys and creates a set of them. Every key needs a
+// unique ID, exactly one of key and key_sha256, and at least one scope.
+func NewAPIKeySet(keys []APIKey) (*APIKeySet, error) {
+	set := &APIKeySet{keys: make([]apiKeyEntry, 0, len(keys))}
+	ids := make(map[string]bool)
+	digests := make(map[[sha256.Size]byte]string)
+	for i, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("API key %d has no id", i)
+		}
+		if ids[key.ID] {
+			return nil, fmt.Errorf("API key %q is defined twice", key.ID)
+		}
+		ids[key.ID] = true
+
+		entry := apiKeyEntry{id: key.ID, name: key.Name}
+		switch {
+		case key.Key != "" && key.KeySHA256 != "":
+			return nil, fmt.Errorf("API key %q sets both key and key_sha256", key.ID)
+		case key.Key != "":
+			entry.digest = sha256.Sum256([]byte(key.Key))
+		case key.KeySHA256 != "":
+			digest, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(digest) != sha256.Size {
+				return nil, fmt.Errorf("API key %q: key_sha256 must be 64 hex digits", key.ID)
+			}
+			copy(entry.digest[:], digest)
+		default:
+			return nil, fmt.Errorf("API key %q needs a key or key_sha256", key.ID)
+		}
+		if other, dup := digests[entry.digest]; dup {
+			return nil, fmt.Errorf("API keys %q and %q have the same key", other, key.ID)
+		}
+		digests[entry.digest] = key.ID
+
+		if len(key.Scopes) == 0 {
+			return nil, fmt.Errorf("API key %q has no scopes", key.ID)
+		}
+		for _, scope := range key.Scopes {
+			if scope != ScopeRead && scope != ScopeResolve && scope != ScopeAdmin {
+				return nil, fmt.Errorf("API key %q: unknown scope %q (want one of %s)", key.ID, scope, strings.Join(Scopes(), ", "))
+			}
+		}
+		entry.scopes = append([]string(nil), key.Scopes...)
+		entry.roles = append([]string(nil), key.Roles...)
+		set.keys = append(set.keys, entry)
+	}
+	return set, nil
+}
+
+// LoadAPIKeys loads the keys of cfg from its file and its environment
+// variable, each a YAML or JSON list of keys. Neither may be empty.
+func LoadAPIKeys(cfg config.APIKeysConfig) (*APIKeySet, error) {
+	if cfg.File == "" && cfg.Env == "" {
+		return nil, fmt.Errorf("auth.api_keys needs a file or env")
+	}
+	var keys []APIKey
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("auth.api_keys.file: %w", err)
+		}
+		fileKeys, err := ParseAPIKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("auth.api_keys.file: %w", err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	if cfg.Env != "" {
+		value, ok := os.LookupEnv(cfg.Env)
+		if !ok {
+			return nil, fmt.Errorf("auth.api_keys.env: environment variable %s is not set", cfg.Env)
+		}
+		envKeys, err := ParseAPIKeys([]byte(value))
+		if err != nil {
+			return nil, fmt.Errorf("auth.api_keys.env: %w", err)
+		}
+		keys = append(keys, envKeys...)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("auth.api_keys: no keys configured")
+	}
+	return NewAPIKeySet(keys)
+}
+
+// Len returns the number of keys in the set
+func (s *APIKeySet) Len() int {
+	return len(s.keys)
+}
+
+// Validate returns the caller a key belongs to: its ID as user ID, with the
+// key's scopes and roles. Keys are compared by digest in constant time, and every key
+// is compared, so timing does not reveal which or how much of a key matched.
+// An unknown key is a *TokenError.
+func (s *APIKeySet) Validate(_ context.Context, key string) (*service.CallerIdentity, error) {
+	digest := sha256.Sum256([]byte(key))
+	match := -1
+	for i := range s.keys {
+		if subtle.ConstantTimeCompare(digest[:], s.keys[i].digest[:]) == 1 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, tokenError("unknown API key")
+	}
+
+	entry := s.keys[match]
+	caller := &service.CallerIdentity{
+		UserID: entry.id,
+		Source: "api_key",
+		Scopes: append([]string(nil), entry.scopes...),
+		Roles:  append([]string(nil), entry.roles...),
+	}
+	if entry.name != "" {
+		name := entry.name
+		caller.Username = &name
+	}
+	return caller, nil
+}
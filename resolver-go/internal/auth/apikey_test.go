@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+)
+
+func TestAPIKeySetValidate(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed-key"))
+	set, err := NewAPIKeySet([]APIKey{
+		{ID: "ci-bot", Name: "CI pipeline", Scopes: []string{ScopeRead, ScopeResolve}, Key: "plain-key"},
+		{ID: "ops", Scopes: []string{ScopeAdmin}, Roles: []string{"catalog-admin"}, KeySHA256: hex.EncodeToString(digest[:])},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caller, err := set.Validate(context.Background(), "plain-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caller.UserID != "ci-bot" || caller.Source != "api_key" || *caller.Username != "CI pipeline" || len(caller.Scopes) != 2 {
+		t.Errorf("unexpected caller %+v", caller)
+	}
+	if caller, err := set.Validate(context.Background(), "hashed-key"); err != nil || caller.UserID != "ops" || caller.Username != nil || len(caller.Roles) != 1 {
+		t.Errorf("expected the key_sha256 key to match ops, got %+v, %v", caller, err)
+	}
+
+	var tokenErr *TokenError
+	if _, err := set.Validate(context.Background(), "plain-ke"); !errors.As(err, &tokenErr) {
+		t.Errorf("expected a TokenError for an unknown key, got %v", err)
+	}
+}
+
+func TestNewAPIKeySetRejectsBadKeys(t *testing.T) {
+	for name, keys := range map[string][]APIKey{
+		"no id":          {{Scopes: []string{ScopeRead}, Key: "k"}},
+		"duplicate id":   {{ID: "a", Scopes: []string{ScopeRead}, Key: "k1"}, {ID: "a", Scopes: []string{ScopeRead}, Key: "k2"}},
+		"duplicate key":  {{ID: "a", Scopes: []string{ScopeRead}, Key: "k"}, {ID: "b", Scopes: []string{ScopeRead}, Key: "k"}},
+		"no key":         {{ID: "a", Scopes: []string{ScopeRead}}},
+		"both keys":      {{ID: "a", Scopes: []string{ScopeRead}, Key: "k", KeySHA256: strings.Repeat("0", 64)}},
+		"bad digest":     {{ID: "a", Scopes: []string{ScopeRead}, KeySHA256: "abc"}},
+		"no scopes":      {{ID: "a", Key: "k"}},
+		"unknown scopes": {{ID: "a", Scopes: []string{"write"}, Key: "k"}},
+	} {
+		if _, err := NewAPIKeySet(keys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadAPIKeysFromFileAndEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(file, []byte("- id: ci-bot\n  scopes: [read]\n  key: file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_API_KEYS", `[{"id": "ops", "scopes": ["admin"], "key": "env-key"}]`)
+
+	set, err := LoadAPIKeys(config.APIKeysConfig{File: file, Env: "TEST_API_KEYS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Len() != 2 {
+		t.Fatalf("expected keys from both sources, got %d", set.Len())
+	}
+	for key, id := range map[string]string{"file-key": "ci-bot", "env-key": "ops"} {
+		if caller, err := set.Validate(context.Background(), key); err != nil || caller.UserID != id {
+			t.Errorf("expected %s to authenticate %s, got %+v, %v", key, id, caller, err)
+		}
+	}
+
+	if _, err := LoadAPIKeys(config.APIKeysConfig{Env: "TEST_API_KEYS_UNSET"}); err == nil {
+		t.Error("expected an error for an unset variable")
+	}
+	if _, err := LoadAPIKeys(config.APIKeysConfig{}); err == nil {
+		t.Error("expected an error without a file or env")
+	}
+}
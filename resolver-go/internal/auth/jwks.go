@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksMinRefresh bounds how often an unknown key ID triggers a refetch, so
+// tokens with made-up kids cannot hammer the provider
+const jwksMinRefresh = 30 * time.Second
+
+// jwksMaxBytes caps the size of a key set response
+const jwksMaxBytes = 1 << 20
+
+// jwksCache holds a provider's signing keys by key ID, refetched when they
+// are older than ttl or a token names an unknown key. One fetch runs at a
+// time, outside the lock, and the callers that need it wait for it.
+type jwksCache struct {
+	urls   []string // Tried in order until one answers
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	fetching  *jwksFetch // The fetch in progress, if any
+}
+
+// jwksFetch is a key set download shared by the callers waiting on it; err
+// is set before done is closed
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newJWKSCache(urls []string, ttl time.Duration) *jwksCache {
+	return &jwksCache{urls: urls, ttl: ttl, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// key returns the signing key with ID kid
+func (c *jwksCache) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	age := time.Since(c.fetchedAt)
+	key, ok := c.keys[kid]
+	if ok && age < c.ttl {
+		c.mu.Unlock()
+		return key, nil
+	}
+	if c.keys != nil && age < c.ttl && age < jwksMinRefresh {
+		c.mu.Unlock()
+		return nil, tokenError("unknown signing key %q", kid)
+	}
+	f := c.fetching
+	if f == nil {
+		f = &jwksFetch{done: make(chan struct{})}
+		c.fetching = f
+		// Shared by every waiting caller, so not cancelled with this one
+		go c.refresh(context.WithoutCancel(ctx), f)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.keys[kid]; ok {
+		return key, nil // A stale key is kept while the provider is down
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, tokenError("unknown signing key %q", kid)
+}
+
+// refresh runs fetch f and swaps in the keys it downloads
+func (c *jwksCache) refresh(ctx context.Context, f *jwksFetch) {
+	keys, err := c.fetch(ctx)
+	c.mu.Lock()
+	if err == nil {
+		c.keys, c.fetchedAt = keys, time.Now()
+	}
+	f.err = err
+	c.fetching = nil
+	c.mu.Unlock()
+	close(f.done)
+}
+
+// jsonWebKey is the subset of RFC 7517 fields used for RSA and EC keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch downloads the key set from the first URL that answers
+func (c *jwksCache) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var lastErr error
+	for _, url := range c.urls {
+		keys, err := c.fetchURL(ctx, url)
+		if err == nil {
+			return keys, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("fetch JWKS: %w", lastErr)
+}
+
+func (c *jwksCache) fetchURL(ctx context.Context, url string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxBytes)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		// Keys of other types or curves are skipped, not fatal
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA or P-256/P-384 EC key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
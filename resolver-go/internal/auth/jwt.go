@@ -0,0 +1,323 @@
+// Package auth authenticates API callers. JWTValidator checks bearer tokens
+// against the auth.okta config and turns their claims into a caller
+// identity; APIKeySet does the same for the keys of auth.api_keys.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// Leeway is the clock skew tolerated when checking exp, nbf and iat
+const Leeway = time.Minute
+
+// Claim defaults when auth.okta leaves them empty
+const (
+	DefaultUserClaim  = "sub"
+	DefaultNameClaim  = "name"
+	DefaultRolesClaim = "roles"
+)
+
+// TokenError is a bearer token that was rejected. Reason is safe to return
+// to the caller.
+type TokenError struct {
+	Reason string
+}
+
+func (e *TokenError) Error() string {
+	return "invalid token: " + e.Reason
+}
+
+func tokenError(format string, args ...interface{}) error {
+	return &TokenError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// JWTValidator validates bearer JWTs signed with HS256 (auth.okta.test_secret),
+// RS256/384/512 or ES256/384 (auth.okta.public_key or the provider's JWKS)
+type JWTValidator struct {
+	cfg       config.OktaConfig
+	secret    []byte
+	publicKey crypto.PublicKey
+	jwks      *jwksCache
+	now       func() time.Time
+}
+
+// NewJWTValidator creates a validator for cfg. Secret references in
+// test_secret and public_key must already be resolved.
+func NewJWTValidator(cfg config.OktaConfig) (*JWTValidator, error) {
+	v := &JWTValidator{cfg: cfg, now: time.Now}
+	switch {
+	case cfg.TestSecret != "":
+		v.secret = []byte(cfg.TestSecret)
+	case cfg.PublicKey != "":
+		key, err := parsePublicKey(cfg.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("auth.okta.public_key: %w", err)
+		}
+		v.publicKey = key
+	default:
+		urls := []string{cfg.JWKSURL}
+		if cfg.JWKSURL == "" {
+			if cfg.Issuer == "" {
+				return nil, fmt.Errorf("auth.okta needs an issuer, jwks_url, public_key or test_secret")
+			}
+			issuer := strings.TrimRight(cfg.Issuer, "/")
+			urls = []string{issuer + "/.well-known/jwks.json", issuer + "/v1/keys"}
+		}
+		ttl := time.Duration(cfg.JWKSCacheTTL) * time.Second
+		if ttl <= 0 {
+			ttl = time.Hour
+		}
+		v.jwks = newJWKSCache(urls, ttl)
+	}
+	return v, nil
+}
+
+// SetClock replaces the clock used to check token lifetimes, for tests
+func (v *JWTValidator) SetClock(now func() time.Time) {
+	v.now = now
+}
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Validate checks the signature and claims of a compact JWT and returns the
+// caller it identifies. Rejected tokens return a *TokenError; failures to
+// fetch signing keys return other errors.
+func (v *JWTValidator) Validate(ctx context.Context, token string) (*service.CallerIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, tokenError("malformed token")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, tokenError("malformed header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, tokenError("malformed signature")
+	}
+	if err := v.verify(ctx, header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, tokenError("malformed claims")
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return v.identity(claims)
+}
+
+// verify checks the signature over signingInput with the key for header
+func (v *JWTValidator) verify(ctx context.Context, header jwtHeader, signingInput string, signature []byte) error {
+	if v.secret != nil {
+		if header.Alg != "HS256" {
+			return tokenError("algorithm %q is not accepted", header.Alg)
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return tokenError("bad signature")
+		}
+		return nil
+	}
+
+	hashID, newHash, ok := signingHash(header.Alg)
+	if !ok {
+		return tokenError("algorithm %q is not accepted", header.Alg)
+	}
+	key := v.publicKey
+	if v.jwks != nil {
+		if header.Kid == "" {
+			return tokenError("missing key ID (kid)")
+		}
+		var err error
+		if key, err = v.jwks.key(ctx, header.Kid); err != nil {
+			return err
+		}
+	}
+	h := newHash()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(header.Alg, "RS") || rsa.VerifyPKCS1v15(k, hashID, digest, signature) != nil {
+			return tokenError("bad signature")
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(header.Alg, "ES") || len(signature) != 2*size {
+			return tokenError("bad signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return tokenError("bad signature")
+		}
+	default:
+		return tokenError("unsupported key type for %q", header.Alg)
+	}
+	return nil
+}
+
+// signingHash returns the hash of an asymmetric JWS algorithm
+func signingHash(alg string) (crypto.Hash, func() hash.Hash, bool) {
+	switch alg {
+	case "RS256", "ES256":
+		return crypto.SHA256, sha256.New, true
+	case "RS384", "ES384":
+		return crypto.SHA384, sha512.New384, true
+	case "RS512":
+		return crypto.SHA512, sha512.New, true
+	}
+	return 0, nil, false
+}
+
+// checkClaims checks the lifetime, issuer and audience of a token. exp is
+// required.
+func (v *JWTValidator) checkClaims(claims map[string]interface{}) error {
+	now := v.now()
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return tokenError("missing exp claim")
+	}
+	if now.After(exp.Add(Leeway)) {
+		return tokenError("token expired at %s", exp.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(Leeway).Before(nbf) {
+		return tokenError("token not valid before %s", nbf.UTC().Format(time.RFC3339))
+	}
+	if iat, ok := numericClaim(claims, "iat"); ok && now.Add(Leeway).Before(iat) {
+		return tokenError("token issued in the future")
+	}
+	if v.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); strings.TrimRight(iss, "/") != strings.TrimRight(v.cfg.Issuer, "/") {
+			return tokenError("unexpected issuer %q", iss)
+		}
+	}
+	if v.cfg.Audience != "" && !containsString(stringsClaim(claims["aud"]), v.cfg.Audience) {
+		return tokenError("token is not for audience %q", v.cfg.Audience)
+	}
+	return nil
+}
+
+// identity builds the caller from the configured user, name and roles claims
+func (v *JWTValidator) identity(claims map[string]interface{}) (*service.CallerIdentity, error) {
+	userClaim := firstNonEmpty(v.cfg.UserClaim, DefaultUserClaim)
+	userID, _ := claims[userClaim].(string)
+	if userID == "" {
+		return nil, tokenError("missing %s claim", userClaim)
+	}
+	caller := &service.CallerIdentity{UserID: userID, Source: "jwt"}
+	if name, _ := claims[firstNonEmpty(v.cfg.NameClaim, DefaultNameClaim)].(string); name != "" {
+		caller.Username = &name
+	}
+	caller.Roles = stringsClaim(claims[firstNonEmpty(v.cfg.RolesClaim, DefaultRolesClaim)])
+	if v.cfg.GroupsClaim != "" {
+		for _, group := range stringsClaim(claims[v.cfg.GroupsClaim]) {
+			if !containsString(caller.Roles, group) {
+				caller.Roles = append(caller.Roles, group)
+			}
+		}
+	}
+	return caller, nil
+}
+
+// decodeSegment decodes a base64url JSON token segment into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericClaim reads a NumericDate claim
+func numericClaim(claims map[string]interface{}, name string) (time.Time, bool) {
+	seconds, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// stringsClaim reads a claim that is a list of strings, or a single string of
+// space- or comma-separated values
+func stringsClaim(v interface{}) []string {
+	var out []string
+	switch value := v.(type) {
+	case string:
+		for _, s := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' }) {
+			out = append(out, s)
+		}
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parsePublicKey parses a PEM public key or certificate
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("not PEM encoded")
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+)
+
+var testNow = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+func segment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func hsToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	input := segment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func rsToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	input := segment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func claims(extra map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{
+		"sub": "alice",
+		"iss": "https://idp.example.com/",
+		"aud": "api://moniker-svc",
+		"exp": testNow.Add(time.Hour).Unix(),
+		"iat": testNow.Add(-time.Minute).Unix(),
+	}
+	for k, v := range extra {
+		c[k] = v
+	}
+	return c
+}
+
+func newHSValidator(t *testing.T) *JWTValidator {
+	t.Helper()
+	v, err := NewJWTValidator(config.OktaConfig{
+		Issuer:     "https://idp.example.com",
+		Audience:   "api://moniker-svc",
+		TestSecret: "test-secret-at-least-32-characters!!",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetClock(func() time.Time { return testNow })
+	return v
+}
+
+func TestValidateHS256(t *testing.T) {
+	v := newHSValidator(t)
+	token := hsToken(t, "test-secret-at-least-32-characters!!", claims(map[string]interface{}{
+		"name":  "Alice Analyst",
+		"roles": []interface{}{"analyst", "ops"},
+	}))
+
+	caller, err := v.Validate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if caller.UserID != "alice" || caller.Source != "jwt" || caller.Username == nil || *caller.Username != "Alice Analyst" {
+		t.Errorf("unexpected caller: %+v", caller)
+	}
+	if strings.Join(caller.Roles, ",") != "analyst,ops" {
+		t.Errorf("expected roles from the roles claim, got %v", caller.Roles)
+	}
+}
+
+func TestValidateRejectsBadTokens(t *testing.T) {
+	v := newHSValidator(t)
+	secret := "test-secret-at-least-32-characters!!"
+
+	tests := []struct {
+		name   string
+		token  string
+		reason string
+	}{
+		{"malformed", "not-a-jwt", "malformed token"},
+		{"wrong secret", hsToken(t, "another-secret", claims(nil)), "bad signature"},
+		{"expired", hsToken(t, secret, claims(map[string]interface{}{"exp": testNow.Add(-2 * time.Minute).Unix()})), "token expired"},
+		{"no exp", hsToken(t, secret, map[string]interface{}{"sub": "alice", "iss": "https://idp.example.com", "aud": "api://moniker-svc"}), "missing exp"},
+		{"not yet valid", hsToken(t, secret, claims(map[string]interface{}{"nbf": testNow.Add(time.Hour).Unix()})), "not valid before"},
+		{"wrong issuer", hsToken(t, secret, claims(map[string]interface{}{"iss": "https://evil.example.com"})), "unexpected issuer"},
+		{"wrong audience", hsToken(t, secret, claims(map[string]interface{}{"aud": []interface{}{"api://other"}})), "audience"},
+		{"no subject", hsToken(t, secret, claims(map[string]interface{}{"sub": ""})), "missing sub"},
+	}
+	for _, tt := range tests {
+		_, err := v.Validate(context.Background(), tt.token)
+		var tokenErr *TokenError
+		if !errors.As(err, &tokenErr) {
+			t.Errorf("%s: expected a TokenError, got %v", tt.name, err)
+			continue
+		}
+		if !strings.Contains(tokenErr.Reason, tt.reason) {
+			t.Errorf("%s: expected reason containing %q, got %q", tt.name, tt.reason, tokenErr.Reason)
+		}
+	}
+}
+
+func TestValidateRejectsAlgorithmSwitch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	v, err := NewJWTValidator(config.OktaConfig{PublicKey: publicPEM})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetClock(func() time.Time { return testNow })
+
+	if _, err := v.Validate(context.Background(), rsToken(t, key, "", claims(nil))); err != nil {
+		t.Fatalf("expected the static public key to verify RS256, got %v", err)
+	}
+	// An HS256 token "signed" with the public key must not verify
+	if _, err := v.Validate(context.Background(), hsToken(t, publicPEM, claims(nil))); err == nil {
+		t.Error("expected HS256 to be refused with a public key")
+	}
+}
+
+func TestValidateES256WithStaticKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewJWTValidator(config.OktaConfig{PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetClock(func() time.Time { return testNow })
+
+	input := segment(t, map[string]string{"alg": "ES256"}) + "." + segment(t, claims(nil))
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	if _, err := v.Validate(context.Background(), input+"."+base64.RawURLEncoding.EncodeToString(sig)); err != nil {
+		t.Errorf("expected ES256 token to verify, got %v", err)
+	}
+}
+
+func TestValidateFetchesJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/jwks.json" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWTValidator(config.OktaConfig{Issuer: srv.URL + "/", Audience: "api://moniker-svc", GroupsClaim: "groups"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetClock(func() time.Time { return testNow })
+
+	token := rsToken(t, key, "key-1", claims(map[string]interface{}{
+		"iss":    srv.URL,
+		"roles":  "analyst",
+		"groups": []interface{}{"ops", "analyst"},
+	}))
+	for i := 0; i < 2; i++ {
+		caller, err := v.Validate(context.Background(), token)
+		if err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+		if strings.Join(caller.Roles, ",") != "analyst,ops" {
+			t.Errorf("expected roles and groups merged, got %v", caller.Roles)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected the key set to be fetched once and cached, got %d fetches", n)
+	}
+
+	_, err = v.Validate(context.Background(), rsToken(t, key, "key-2", claims(map[string]interface{}{"iss": srv.URL})))
+	var tokenErr *TokenError
+	if !errors.As(err, &tokenErr) || !strings.Contains(tokenErr.Reason, "unknown signing key") {
+		t.Errorf("expected an unknown key error, got %v", err)
+	}
+}
+
+func TestJWKSCacheSharesOneFetchOutsideTheLock(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+	c := newJWKSCache([]string{srv.URL}, time.Hour)
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := c.key(context.Background(), "key-1")
+			errs <- err
+		}()
+	}
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A caller that gives up is not held by the slow provider
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.key(ctx, "key-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller's deadline, got %v", err)
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("key: %v", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected one shared fetch, got %d", n)
+	}
+}
+
+func TestJWKSCacheLimitsTheResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys": [`))
+		w.Write([]byte(strings.Repeat(" ", 2*jwksMaxBytes)))
+		w.Write([]byte(`]}`))
+	}))
+	defer srv.Close()
+
+	if _, err := newJWKSCache([]string{srv.URL}, time.Hour).key(context.Background(), "key-1"); err == nil {
+		t.Error("expected an oversized key set to be refused")
+	}
+}
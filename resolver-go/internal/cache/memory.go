@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -9,6 +11,48 @@ import (
 type Entry struct {
 	Value     interface{}
 	ExpiresAt time.Time
+
+	size int64 // Estimated bytes of the key and value
+}
+
+// Stats reports cache usage. Hits and Misses count Get calls since the
+// cache was created; a Get finding only an expired entry is a miss and also
+// counts in ExpiredOnRead. Evictions are the expired entries Cleanup has
+// removed. EstimatedBytes is a rough upper bound of the memory the keys and
+// values hold, counting memory they share with the rest of the process.
+type Stats struct {
+	Size           int     `json:"size"`
+	Hits           uint64  `json:"hits"`
+	Misses         uint64  `json:"misses"`
+	HitRatio       float64 `json:"hit_ratio"` // Hits over all Gets; 0 before any Get
+	Evictions      uint64  `json:"evictions"`
+	ExpiredOnRead  uint64  `json:"expired_on_read"`
+	EstimatedBytes int64   `json:"estimated_bytes"`
+
+	DefaultTTL      time.Duration `json:"-"`
+	LastCleared     *time.Time    `json:"last_cleared,omitempty"` // Last Clear, nil if never
+	CleanupInterval time.Duration `json:"-"`                      // 0 unless StartCleanup was called
+
+	// Namespaces break the counts down by the key prefix before the first
+	// ":", e.g. resolve for resolve:..., for every namespace seen
+	Namespaces map[string]NamespaceStats `json:"namespaces"`
+}
+
+// NamespaceStats is the share of one key namespace in the cache's Stats
+type NamespaceStats struct {
+	Size           int     `json:"size"`
+	Hits           uint64  `json:"hits"`
+	Misses         uint64  `json:"misses"`
+	HitRatio       float64 `json:"hit_ratio"`
+	ExpiredOnRead  uint64  `json:"expired_on_read"`
+	EstimatedBytes int64   `json:"estimated_bytes"`
+}
+
+// counters are the Get outcomes of the whole cache or one namespace
+type counters struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	expiredOnRead atomic.Uint64
 }
 
 // InMemory is a simple thread-safe in-memory cache
@@ -16,6 +60,14 @@ type InMemory struct {
 	entries map[string]*Entry
 	mu      sync.RWMutex
 	ttl     time.Duration
+	bytes   int64         // Sum of the entries' sizes; guarded by mu
+	cleared time.Time     // Time of the last Clear; guarded by mu
+	stop    chan struct{} // Closed to stop the cleanup goroutine; guarded by mu
+
+	counters
+	evictions       atomic.Uint64
+	namespaces      sync.Map // Namespace -> *counters
+	cleanupInterval atomic.Int64
 }
 
 // NewInMemory creates a new in-memory cache
@@ -26,43 +78,78 @@ func NewInMemory(ttl time.Duration) *InMemory {
 	}
 }
 
+// namespace returns the namespace of key: the text before its first ":", or
+// "" when it has none
+func namespace(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// namespaceCounters returns the counters of the namespace of key
+func (c *InMemory) namespaceCounters(key string) *counters {
+	ns := namespace(key)
+	if n, ok := c.namespaces.Load(ns); ok {
+		return n.(*counters)
+	}
+	n, _ := c.namespaces.LoadOrStore(ns, &counters{})
+	return n.(*counters)
+}
+
 // Get retrieves a value from the cache
 func (c *InMemory) Get(key string) (interface{}, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	ns := c.namespaceCounters(key)
 	entry, ok := c.entries[key]
 	if !ok {
+		c.misses.Add(1)
+		ns.misses.Add(1)
 		return nil, false
 	}
 
 	// Check expiration
 	if time.Now().After(entry.ExpiresAt) {
+		c.misses.Add(1)
+		c.expiredOnRead.Add(1)
+		ns.misses.Add(1)
+		ns.expiredOnRead.Add(1)
 		return nil, false
 	}
 
+	c.hits.Add(1)
+	ns.hits.Add(1)
 	return entry.Value, true
 }
 
 // Set stores a value in the cache
 func (c *InMemory) Set(key string, value interface{}) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.entries[key] = &Entry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(c.ttl),
-	}
+	c.SetWithTTL(key, value, c.ttl)
 }
 
 // SetWithTTL stores a value with a custom TTL
 func (c *InMemory) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+	entry := &Entry{
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl),
+		size:      int64(len(key)) + estimateSize(value),
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries[key] = &Entry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(ttl),
+	c.remove(key)
+	c.entries[key] = entry
+	c.bytes += entry.size
+}
+
+// remove deletes key with mu held
+func (c *InMemory) remove(key string) {
+	if old, ok := c.entries[key]; ok {
+		c.bytes -= old.size
+		delete(c.entries, key)
 	}
 }
 
@@ -71,15 +158,38 @@ func (c *InMemory) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.entries, key)
+	c.remove(key)
 }
 
-// Clear clears all entries
-func (c *InMemory) Clear() {
+// DeletePrefix removes the entries whose keys start with any of prefixes
+// and returns how many it removed
+func (c *InMemory) DeletePrefix(prefixes ...string) int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	removed := 0
+	for key := range c.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				c.remove(key)
+				removed++
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// Clear clears all entries and returns how many there were
+func (c *InMemory) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := len(c.entries)
 	c.entries = make(map[string]*Entry)
+	c.bytes = 0
+	c.cleared = time.Now()
+	return removed
 }
 
 // Size returns the number of entries in the cache
@@ -90,6 +200,56 @@ func (c *InMemory) Size() int {
 	return len(c.entries)
 }
 
+// DefaultTTL returns the TTL of entries stored with Set
+func (c *InMemory) DefaultTTL() time.Duration {
+	return c.ttl
+}
+
+// Stats returns the current size, counters and namespace breakdown
+func (c *InMemory) Stats() Stats {
+	c.mu.RLock()
+	stats := Stats{
+		Size:            len(c.entries),
+		EstimatedBytes:  c.bytes,
+		DefaultTTL:      c.ttl,
+		CleanupInterval: time.Duration(c.cleanupInterval.Load()),
+		Namespaces:      make(map[string]NamespaceStats),
+	}
+	if !c.cleared.IsZero() {
+		cleared := c.cleared
+		stats.LastCleared = &cleared
+	}
+	for key, entry := range c.entries {
+		ns := stats.Namespaces[namespace(key)]
+		ns.Size++
+		ns.EstimatedBytes += entry.size
+		stats.Namespaces[namespace(key)] = ns
+	}
+	c.mu.RUnlock()
+
+	stats.Hits, stats.Misses = c.hits.Load(), c.misses.Load()
+	stats.HitRatio = hitRatio(stats.Hits, stats.Misses)
+	stats.ExpiredOnRead = c.expiredOnRead.Load()
+	stats.Evictions = c.evictions.Load()
+	c.namespaces.Range(func(key, value interface{}) bool {
+		n := value.(*counters)
+		ns := stats.Namespaces[key.(string)]
+		ns.Hits, ns.Misses = n.hits.Load(), n.misses.Load()
+		ns.HitRatio = hitRatio(ns.Hits, ns.Misses)
+		ns.ExpiredOnRead = n.expiredOnRead.Load()
+		stats.Namespaces[key.(string)] = ns
+		return true
+	})
+	return stats
+}
+
+func hitRatio(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
 // Cleanup removes expired entries
 func (c *InMemory) Cleanup() {
 	c.mu.Lock()
@@ -98,19 +258,48 @@ func (c *InMemory) Cleanup() {
 	now := time.Now()
 	for key, entry := range c.entries {
 		if now.After(entry.ExpiresAt) {
-			delete(c.entries, key)
+			c.remove(key)
+			c.evictions.Add(1)
 		}
 	}
 }
 
-// StartCleanup starts a background goroutine that periodically cleans up expired entries
+// StartCleanup starts a background goroutine that periodically cleans up
+// expired entries until StopCleanup is called. A goroutine already running
+// is stopped first.
 func (c *InMemory) StartCleanup(interval time.Duration) {
+	stop := make(chan struct{})
+	c.mu.Lock()
+	if c.stop != nil {
+		close(c.stop)
+	}
+	c.stop = stop
+	c.mu.Unlock()
+	c.cleanupInterval.Store(int64(interval))
+
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			c.Cleanup()
+		for {
+			select {
+			case <-ticker.C:
+				c.Cleanup()
+			case <-stop:
+				return
+			}
 		}
 	}()
 }
+
+// StopCleanup stops the goroutine StartCleanup started, if any
+func (c *InMemory) StopCleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+		c.cleanupInterval.Store(0)
+	}
+}
@@ -153,7 +153,9 @@ func TestClear(t *testing.T) {
 	c.Set("b", 2)
 	c.Set("c", 3)
 
-	c.Clear()
+	if removed := c.Clear(); removed != 3 {
+		t.Errorf("expected Clear to report 3 entries, got %d", removed)
+	}
 
 	if c.Size() != 0 {
 		t.Errorf("expected size 0 after clear, got %d", c.Size())
@@ -164,6 +166,24 @@ func TestClear(t *testing.T) {
 	}
 }
 
+func TestDeletePrefix(t *testing.T) {
+	c := NewInMemory(5 * time.Second)
+	c.Set("resolve:prices:1", 1)
+	c.Set("resolve:prices/equity:1", 2)
+	c.Set("resolve:pricesx:1", 3)
+	c.Set("versions:prices/equity:1", 4)
+
+	if removed := c.DeletePrefix("resolve:prices:", "resolve:prices/"); removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+	if _, found := c.Get("resolve:pricesx:1"); !found {
+		t.Error("expected a sibling sharing the text prefix to stay")
+	}
+	if c.Size() != 2 {
+		t.Errorf("expected 2 entries left, got %d", c.Size())
+	}
+}
+
 // --- Size ---
 
 func TestSize(t *testing.T) {
@@ -184,6 +204,98 @@ func TestSize(t *testing.T) {
 	}
 }
 
+// --- Stats ---
+
+func TestStatsCountsHitsAndMisses(t *testing.T) {
+	c := NewInMemory(5 * time.Second)
+	c.Set("a", 1)
+	c.SetWithTTL("expired", 2, -1*time.Second)
+
+	c.Get("a")
+	c.Get("a")
+	c.Get("missing")
+	c.Get("expired")
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Size != 2 {
+		t.Errorf("expected 2 hits, 2 misses, size 2, got %+v", stats)
+	}
+}
+
+func TestStatsCountExpiryEvictionsAndNamespaces(t *testing.T) {
+	c := NewInMemory(5 * time.Second)
+	c.Set("resolve:1:prices/equity", "snowflake")
+	c.SetWithTTL("versions:1:prices/fx", []string{"20260101", "20260102"}, -1*time.Second)
+	c.Set("plain", 1)
+
+	c.Get("resolve:1:prices/equity")
+	c.Get("resolve:1:prices/missing")
+	c.Get("versions:1:prices/fx")
+
+	stats := c.Stats()
+	if stats.ExpiredOnRead != 1 || stats.Misses != 2 || stats.HitRatio != 1.0/3 {
+		t.Errorf("expected the expired read counted as a miss, got %+v", stats)
+	}
+	resolve := stats.Namespaces["resolve"]
+	if resolve.Size != 1 || resolve.Hits != 1 || resolve.Misses != 1 || resolve.HitRatio != 0.5 {
+		t.Errorf("unexpected resolve namespace %+v", resolve)
+	}
+	if versions := stats.Namespaces["versions"]; versions.ExpiredOnRead != 1 || versions.EstimatedBytes <= int64(len("versions:1:prices/fx")+16) {
+		t.Errorf("unexpected versions namespace %+v", versions)
+	}
+	if stats.Namespaces[""].Size != 1 {
+		t.Errorf("expected keys without a namespace under \"\", got %+v", stats.Namespaces)
+	}
+
+	c.Cleanup()
+	stats = c.Stats()
+	if stats.Evictions != 1 || stats.Size != 2 {
+		t.Errorf("expected the expired entry evicted, got %+v", stats)
+	}
+
+	before := stats.EstimatedBytes
+	c.Set("plain", 2)
+	if after := c.Stats().EstimatedBytes; after != before {
+		t.Errorf("expected overwriting an entry to keep the estimate, got %d then %d", before, after)
+	}
+	c.Delete("resolve:1:prices/equity")
+	if stats.LastCleared != nil {
+		t.Error("expected no last clear yet")
+	}
+	c.Clear()
+	if stats = c.Stats(); stats.EstimatedBytes != 0 || stats.LastCleared == nil {
+		t.Errorf("expected Clear to reset the estimate and be recorded, got %+v", stats)
+	}
+}
+
+func TestStatsReportCleanupInterval(t *testing.T) {
+	c := NewInMemory(time.Minute)
+	if c.Stats().CleanupInterval != 0 {
+		t.Error("expected no cleanup before StartCleanup")
+	}
+	c.StartCleanup(time.Hour)
+	if got := c.Stats().CleanupInterval; got != time.Hour {
+		t.Errorf("expected a 1h cleanup interval, got %s", got)
+	}
+	c.StopCleanup()
+	if c.Stats().CleanupInterval != 0 {
+		t.Error("expected no cleanup after StopCleanup")
+	}
+	c.StopCleanup() // Stopping twice is harmless
+}
+
+func TestStopCleanupStopsEvicting(t *testing.T) {
+	c := NewInMemory(time.Millisecond)
+	c.StartCleanup(time.Millisecond)
+	c.StopCleanup()
+	time.Sleep(5 * time.Millisecond) // Let a tick that raced the stop finish
+	c.Set("k", "v")
+	time.Sleep(20 * time.Millisecond)
+	if c.Size() != 1 || c.Stats().Evictions != 0 {
+		t.Error("expected the expired entry to stay once cleanup stopped")
+	}
+}
+
 // --- Cleanup ---
 
 func TestCleanup(t *testing.T) {
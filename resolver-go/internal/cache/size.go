@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"reflect"
+)
+
+// maxSizeDepth bounds how deep estimateSize follows pointers, slices, maps
+// and interfaces
+const maxSizeDepth = 16
+
+// estimateSize estimates the bytes v holds: its own size plus what it
+// reaches through pointers, slices, maps, strings and interfaces, each
+// pointer followed once
+func estimateSize(v interface{}) int64 {
+	if v == nil {
+		return 0
+	}
+	value := reflect.ValueOf(v)
+	return int64(value.Type().Size()) + referencedSize(value, make(map[uintptr]bool), 0)
+}
+
+// referencedSize returns the bytes v refers to beyond its own size
+func referencedSize(v reflect.Value, seen map[uintptr]bool, depth int) int64 {
+	if depth > maxSizeDepth {
+		return 0
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + referencedSize(elem, seen, depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + referencedSize(elem, seen, depth+1)
+	case reflect.Slice:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen, depth+1)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen, depth+1)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		entry := int64(v.Type().Key().Size() + v.Type().Elem().Size())
+		size := int64(v.Len()) * entry
+		iter := v.MapRange()
+		for iter.Next() {
+			size += referencedSize(iter.Key(), seen, depth+1) + referencedSize(iter.Value(), seen, depth+1)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSize(v.Field(i), seen, depth+1)
+		}
+		return size
+	}
+	return 0
+}
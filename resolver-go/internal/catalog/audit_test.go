@@ -0,0 +1,77 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditRegisterRecordsCreateAndUpdate(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	r.Register(makeNode("prices", "Prices v2", "", NodeStatusActive, false))
+
+	entries := r.AuditEntries("prices", 0, nil)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	// Newest first
+	if entries[0].Action != "updated" || entries[1].Action != "created" {
+		t.Errorf("expected [updated, created], got [%s, %s]", entries[0].Action, entries[1].Action)
+	}
+}
+
+func TestAuditAtomicReplaceRecordsPerNodeChanges(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("keep", "Keep", "", NodeStatusActive, false))
+	r.Register(makeNode("edit", "Edit", "old", NodeStatusActive, false))
+	r.Register(makeNode("gone", "Gone", "", NodeStatusActive, false))
+
+	r.AtomicReplace([]*CatalogNode{
+		makeNode("keep", "Keep", "", NodeStatusActive, false),
+		makeNode("edit", "Edit", "new", NodeStatusActive, false),
+		makeNode("added", "Added", "", NodeStatusActive, false),
+	})
+
+	cases := map[string]string{"edit": "updated", "gone": "removed", "added": "created"}
+	for path, action := range cases {
+		entries := r.AuditEntries(path, 1, nil)
+		if len(entries) != 1 || entries[0].Action != action {
+			t.Errorf("%s: expected latest action %q, got %v", path, action, entries)
+		}
+	}
+
+	// Unchanged node only has its original registration
+	if entries := r.AuditEntries("keep", 0, nil); len(entries) != 1 {
+		t.Errorf("expected 1 entry for unchanged node, got %d", len(entries))
+	}
+}
+
+func TestAuditEntriesLimitAndSince(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < 5; i++ {
+		r.RecordAudit("prices", "status_changed", "alice", nil, nil, nil)
+	}
+
+	if entries := r.AuditEntries("prices", 3, nil); len(entries) != 3 {
+		t.Errorf("expected limit of 3, got %d", len(entries))
+	}
+
+	future := time.Now().Add(time.Hour)
+	if entries := r.AuditEntries("prices", 0, &future); len(entries) != 0 {
+		t.Errorf("expected no entries after future since, got %d", len(entries))
+	}
+
+	if entries := r.AuditEntries("", 0, nil); len(entries) != 5 {
+		t.Errorf("expected 5 entries across all paths, got %d", len(entries))
+	}
+}
+
+func TestAuditLogBounded(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < maxAuditEntries+10; i++ {
+		r.RecordAudit("p", "updated", "bot", nil, nil, nil)
+	}
+	if n := len(r.AuditEntries("", 0, nil)); n != maxAuditEntries {
+		t.Errorf("expected audit log capped at %d, got %d", maxAuditEntries, n)
+	}
+}
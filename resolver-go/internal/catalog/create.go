@@ -0,0 +1,83 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// NodeRequest is the JSON body of a node written through the API: one
+// catalog entry in the JSON catalog format, with its path
+type NodeRequest struct {
+	Path string `json:"path"`
+	CatalogNodeYAML
+}
+
+// NodeExistsError is a node created at a path that is already registered
+type NodeExistsError struct {
+	Path string
+}
+
+func (e *NodeExistsError) Error() string {
+	return fmt.Sprintf("catalog path '%s' already exists", e.Path)
+}
+
+// ParentNotFoundError is a node created below a parent that is not registered
+type ParentNotFoundError struct {
+	Path   string
+	Parent string
+}
+
+func (e *ParentNotFoundError) Error() string {
+	return fmt.Sprintf("parent '%s' of '%s' is not registered", e.Parent, e.Path)
+}
+
+// Node converts the request to a catalog node, checking it as the loader
+// checks a catalog entry: the path must parse as a moniker path, and the
+// fields and source binding config must be valid for the node's source type
+func (req *NodeRequest) Node() (*CatalogNode, error) {
+	path := strings.Trim(req.Path, "/")
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if _, err := moniker.ParsePath(path, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validateNodeYAML(path, &req.CatalogNodeYAML); err != nil {
+		return nil, err
+	}
+	node := convertYAMLToNode(path, &req.CatalogNodeYAML)
+	if err := validateSourceConfigs([]*CatalogNode{node}); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Create registers a node at a path that is not yet registered, recording
+// actor in its audit entry. It fails with *NodeExistsError for a registered
+// path and, unless virtualParents, with *ParentNotFoundError when the parent
+// is not registered; with virtualParents the parent is left virtual, as it
+// is for a catalog file that omits it.
+func (r *Registry) Create(node *CatalogNode, actor string, virtualParents bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+	if _, exists := old.nodes[node.Path]; exists {
+		return &NodeExistsError{Path: node.Path}
+	}
+	if parent := parentPath(node.Path); parent != nil && !virtualParents {
+		if _, ok := old.nodes[*parent]; !ok {
+			return &ParentNotFoundError{Path: node.Path, Parent: *parent}
+		}
+	}
+
+	next := old.clone()
+	next.put(node)
+	r.publish(next)
+
+	r.appendAudit(newAuditEntry(node.Path, "created", actor, nil, nil, nil))
+	r.watchers.emit(ChangeEvent{Kind: ChangeAdded, Path: node.Path, NewStatus: node.Status})
+	return nil
+}
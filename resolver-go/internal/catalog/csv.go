@@ -0,0 +1,270 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+	"gopkg.in/yaml.v3"
+)
+
+// Spreadsheet import columns, shared by CSV and XLSX imports. owner sets
+// ownership.accountable_owner and table sets config.table; any column named
+// config.<key> sets that key of the row's source binding config.
+const (
+	csvColumnPath        = "path"
+	csvColumnDisplayName = "display_name"
+	csvColumnDescription = "description"
+	csvColumnOwner       = "owner"
+	csvColumnSourceType  = "source_type"
+	csvColumnTable       = "table"
+	csvConfigPrefix      = "config."
+)
+
+// csvListSeparator splits list-valued config cells, e.g. opensearch hosts
+const csvListSeparator = ";"
+
+// CSVRowError describes a CSV row that could not be imported
+type CSVRowError struct {
+	File    string `json:"file,omitempty"` // Set by LoadCSVFile
+	Line    int    `json:"line"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// CSVImportError aggregates every rejected row of a CSV import
+type CSVImportError struct {
+	Rows []CSVRowError
+}
+
+func (e *CSVImportError) Error() string {
+	lines := make([]string, len(e.Rows))
+	for i, row := range e.Rows {
+		lines[i] = fmt.Sprintf("  line %d: %s", row.Line, row.Message)
+	}
+	return fmt.Sprintf("%d invalid CSV rows:\n%s", len(e.Rows), strings.Join(lines, "\n"))
+}
+
+// LoadCSV reads leaf nodes from CSV with a header row. The path and
+// source_type columns are required; display_name, description, owner, table
+// and config.<key> columns are optional. defaults, if not nil, is applied under every row: the
+// row's own columns win, and config keys are merged. Rows are validated like
+// YAML nodes, and every rejected row is reported with its line number in a
+// *CSVImportError.
+func LoadCSV(r io.Reader, defaults *CatalogNodeYAML) ([]*CatalogNode, error) {
+	if defaults != nil && defaults.SourceBinding != nil && defaults.SourceBinding.Template != "" {
+		return nil, fmt.Errorf("CSV defaults cannot use source_binding.template; binding templates apply within the catalog file that defines them")
+	}
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1 // Trailing empty cells may be omitted
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []*CatalogNode{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{csvColumnPath, csvColumnSourceType} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", required)
+		}
+	}
+
+	nodes := make([]*CatalogNode, 0)
+	rowErrors := make([]CSVRowError, 0)
+	firstLine := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		node, rowErr := csvRowToNode(record, header, columns, defaults)
+		if rowErr != nil {
+			rowErr.Line = line
+			rowErrors = append(rowErrors, *rowErr)
+			continue
+		}
+		if first, dup := firstLine[node.Path]; dup {
+			rowErrors = append(rowErrors, CSVRowError{Line: line, Path: node.Path,
+				Message: fmt.Sprintf("duplicate catalog path '%s' (first defined at line %d)", node.Path, first)})
+			continue
+		}
+		firstLine[node.Path] = line
+		node.SourceLine = line
+		nodes = append(nodes, node)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &CSVImportError{Rows: rowErrors}
+	}
+	return nodes, nil
+}
+
+// LoadCSVFile loads leaf nodes from a CSV file; see LoadCSV
+func LoadCSVFile(path string, defaults *CatalogNodeYAML) ([]*CatalogNode, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog file: %w", err)
+	}
+	defer f.Close()
+
+	nodes, err := LoadCSV(f, defaults)
+	var csvErr *CSVImportError
+	if errors.As(err, &csvErr) {
+		for i := range csvErr.Rows {
+			csvErr.Rows[i].File = path
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		node.SourceFile = path
+	}
+	return nodes, nil
+}
+
+// csvRowToNode builds a leaf node from one CSV record or worksheet row. The
+// returned error has no line number; the caller fills it in.
+func csvRowToNode(record, header []string, columns map[string]int, defaults *CatalogNodeYAML) (*CatalogNode, *CSVRowError) {
+	cell := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	path := strings.Trim(cell(csvColumnPath), "/")
+	if path == "" {
+		return nil, &CSVRowError{Message: "path is empty"}
+	}
+	if _, err := moniker.ParsePath(path, true); err != nil {
+		return nil, &CSVRowError{Path: path, Message: fmt.Sprintf("%s: %v", path, err)}
+	}
+
+	nodeYAML := &CatalogNodeYAML{}
+	if defaults != nil {
+		*nodeYAML = *defaults
+		nodeYAML.Extends = ""
+	}
+	nodeYAML.IsLeaf = true
+	if v := cell(csvColumnDisplayName); v != "" {
+		nodeYAML.DisplayName = v
+	}
+	if v := cell(csvColumnDescription); v != "" {
+		nodeYAML.Description = v
+	}
+	if v := cell(csvColumnOwner); v != "" {
+		ownership := &OwnershipYAML{}
+		if nodeYAML.Ownership != nil {
+			*ownership = *nodeYAML.Ownership
+		}
+		ownership.AccountableOwner = &v
+		nodeYAML.Ownership = ownership
+	}
+
+	binding := &SourceBindingYAML{}
+	if defaults != nil && defaults.SourceBinding != nil {
+		*binding = *defaults.SourceBinding
+	}
+	if v := cell(csvColumnSourceType); v != "" {
+		binding.Type = v
+	}
+	if binding.Type == "" {
+		return nil, &CSVRowError{Path: path, Message: fmt.Sprintf("%s: source_type is empty", path)}
+	}
+	config := make(map[string]interface{}, len(binding.Config))
+	for k, v := range binding.Config {
+		config[k] = v
+	}
+	if v := cell(csvColumnTable); v != "" {
+		config["table"] = v
+	}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !strings.HasPrefix(name, csvConfigPrefix) || i >= len(record) {
+			continue
+		}
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+		key := strings.TrimPrefix(name, csvConfigPrefix)
+		parsed, err := parseCSVConfigValue(SourceType(binding.Type), key, value)
+		if err != nil {
+			return nil, &CSVRowError{Path: path, Message: fmt.Sprintf("%s: %s: %v", path, name, err)}
+		}
+		config[key] = parsed
+	}
+	binding.Config = config
+	nodeYAML.SourceBinding = binding
+
+	if err := validateNodeYAML(path, nodeYAML); err != nil {
+		return nil, &CSVRowError{Path: path, Message: err.Error()}
+	}
+	node := convertYAMLToNode(path, nodeYAML)
+	if violations := checkSourceConfig(path, node.SourceBinding); len(violations) > 0 {
+		messages := make([]string, len(violations))
+		for i, v := range violations {
+			messages[i] = v.Message
+		}
+		sort.Strings(messages)
+		return nil, &CSVRowError{Path: path, Message: strings.Join(messages, "; ")}
+	}
+	return node, nil
+}
+
+// parseCSVConfigValue converts a config cell to the kind the source type's
+// spec expects for key. Keys outside the spec stay strings.
+func parseCSVConfigValue(sourceType SourceType, key, value string) (interface{}, error) {
+	spec := sourceConfigSpecs[sourceType]
+	kind, ok := spec.Required[key]
+	if !ok {
+		kind = spec.Optional[key]
+	}
+
+	switch kind {
+	case configNumber:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return f, nil
+	case configList:
+		items := strings.Split(value, csvListSeparator)
+		list := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	case configMap:
+		var m map[string]interface{}
+		if err := yaml.Unmarshal([]byte(value), &m); err != nil {
+			return nil, fmt.Errorf("expected an inline mapping such as {a: b}, got %q", value)
+		}
+		return m, nil
+	}
+	return value, nil
+}
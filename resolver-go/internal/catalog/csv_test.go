@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const securitiesCSV = `path,display_name,description,source_type,config.account,config.database,config.table
+refdata/securities/isin,ISIN map,ISIN to security id,snowflake,acme,REF,ISIN_MAP
+refdata/securities/cusip,CUSIP map,,snowflake,acme,REF,CUSIP_MAP
+`
+
+func TestLoadCSV(t *testing.T) {
+	defaults := &CatalogNodeYAML{
+		Classification: "internal",
+		Ownership:      &OwnershipYAML{AccountableOwner: strPtr("refdata@example.com")},
+	}
+	nodes, err := LoadCSV(strings.NewReader(securitiesCSV), defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(nodes))
+	}
+
+	isin := nodes[0]
+	if isin.Path != "refdata/securities/isin" || isin.DisplayName != "ISIN map" || !isin.IsLeaf {
+		t.Errorf("unexpected node: %+v", isin)
+	}
+	if isin.SourceLine != 2 {
+		t.Errorf("expected source line 2, got %d", isin.SourceLine)
+	}
+	if isin.Classification != "internal" || isin.Ownership == nil || *isin.Ownership.AccountableOwner != "refdata@example.com" {
+		t.Errorf("defaults not applied: classification=%q ownership=%+v", isin.Classification, isin.Ownership)
+	}
+	sb := isin.SourceBinding
+	if sb.SourceType != SourceTypeSnowflake || sb.Config["table"] != "ISIN_MAP" || sb.Config["account"] != "acme" {
+		t.Errorf("unexpected binding: %+v", sb)
+	}
+	if nodes[1].Description != "" {
+		t.Errorf("empty description cell should stay empty, got %q", nodes[1].Description)
+	}
+}
+
+func TestLoadCSVConfigKinds(t *testing.T) {
+	data := "path,source_type,config.server,config.database,config.port\nrisk/positions,mssql,db01,RISK,1433\n"
+	nodes, err := LoadCSV(strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port, ok := nodes[0].SourceBinding.Config["port"].(int); !ok || port != 1433 {
+		t.Errorf("expected numeric port, got %#v", nodes[0].SourceBinding.Config["port"])
+	}
+
+	data = "path,source_type,config.hosts,config.index\nlogs/trades,opensearch,es1:9200; es2:9200,trades\n"
+	nodes, err = LoadCSV(strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hosts, ok := nodes[0].SourceBinding.Config["hosts"].([]interface{}); !ok || len(hosts) != 2 {
+		t.Errorf("expected 2 hosts, got %#v", nodes[0].SourceBinding.Config["hosts"])
+	}
+}
+
+func TestLoadCSVDefaultBindingConfigMerged(t *testing.T) {
+	defaults := &CatalogNodeYAML{SourceBinding: &SourceBindingYAML{
+		Type:   "snowflake",
+		Config: map[string]interface{}{"account": "acme", "database": "REF"},
+	}}
+	data := "path,source_type,config.table\nrefdata/isin,,ISIN_MAP\nrefdata/cusip,,CUSIP_MAP\n"
+	nodes, err := LoadCSV(strings.NewReader(data), defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes[0].SourceBinding.Config["table"] != "ISIN_MAP" || nodes[1].SourceBinding.Config["table"] != "CUSIP_MAP" {
+		t.Errorf("rows should not share config maps: %v / %v", nodes[0].SourceBinding.Config, nodes[1].SourceBinding.Config)
+	}
+	if _, ok := defaults.SourceBinding.Config["table"]; ok {
+		t.Error("defaults were modified")
+	}
+}
+
+func TestLoadCSVReportsRowLines(t *testing.T) {
+	data := `path,source_type,config.account,config.database
+refdata/ok,snowflake,acme,REF
+refdata/bad segment,snowflake,acme,REF
+refdata/no-db,snowflake,acme,
+refdata/ok,snowflake,acme,REF
+,snowflake,acme,REF
+`
+	_, err := LoadCSV(strings.NewReader(data), nil)
+	var csvErr *CSVImportError
+	if !errors.As(err, &csvErr) {
+		t.Fatalf("expected *CSVImportError, got %v", err)
+	}
+
+	lines := make([]int, len(csvErr.Rows))
+	for i, row := range csvErr.Rows {
+		lines[i] = row.Line
+	}
+	want := []int{3, 4, 5, 6}
+	if len(lines) != len(want) {
+		t.Fatalf("expected rejected lines %v, got %v (%v)", want, lines, err)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("expected rejected lines %v, got %v", want, lines)
+			break
+		}
+	}
+	if !strings.Contains(csvErr.Rows[0].Message, "Invalid path segment") {
+		t.Errorf("expected segment error, got %q", csvErr.Rows[0].Message)
+	}
+}
+
+func TestLoadCSVMissingColumn(t *testing.T) {
+	if _, err := LoadCSV(strings.NewReader("path,display_name\nprices,Prices\n"), nil); err == nil {
+		t.Error("expected error for missing source_type column")
+	}
+}
+
+func TestLoadCSVFileSetsSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "securities.csv")
+	writeFile(t, path, securitiesCSV)
+
+	nodes, err := LoadCSVFile(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes[0].SourceFile != path {
+		t.Errorf("expected source file %s, got %s", path, nodes[0].SourceFile)
+	}
+
+	writeFile(t, path, "path,source_type\nbad path,bloomberg\n")
+	_, err = LoadCSVFile(path, nil)
+	var csvErr *CSVImportError
+	if !errors.As(err, &csvErr) || csvErr.Rows[0].File != path {
+		t.Errorf("expected row error naming %s, got %v", path, err)
+	}
+}
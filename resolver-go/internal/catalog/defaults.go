@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultsKey is the reserved top-level key holding named node fragments that
+// nodes pull in with extends. Defaults apply within the file that defines them.
+const defaultsKey = "_defaults"
+
+// CatalogDefaultsYAML is a named fragment under _defaults
+type CatalogDefaultsYAML struct {
+	Ownership     *OwnershipYAML     `json:"ownership,omitempty" yaml:"ownership,omitempty"`
+	AccessPolicy  *AccessPolicyYAML  `json:"access_policy,omitempty" yaml:"access_policy,omitempty"`
+	SourceBinding *SourceBindingYAML `json:"source_binding,omitempty" yaml:"source_binding,omitempty"`
+}
+
+// defaultsBlocks are the node fields a default may supply
+var defaultsBlocks = []string{"ownership", "access_policy", "source_binding"}
+
+// defaultsDocYAML decodes only the _defaults section, for strict key checks
+type defaultsDocYAML struct {
+	Defaults map[string]*CatalogDefaultsYAML `yaml:"_defaults"`
+}
+
+// parseYAMLDefaults decodes and migrates the _defaults section, returning the
+// fragments by name
+func parseYAMLDefaults(raw *yaml.Node, version int) (map[string]*yaml.Node, error) {
+	var typed map[string]*CatalogDefaultsYAML
+	if err := raw.Decode(&typed); err != nil {
+		return nil, fmt.Errorf("%s must be a mapping of name to ownership, access_policy and source_binding: %w", defaultsKey, err)
+	}
+
+	defaults := make(map[string]*yaml.Node, len(raw.Content)/2)
+	for i := 0; i+1 < len(raw.Content); i += 2 {
+		name, fragment := raw.Content[i].Value, raw.Content[i+1]
+		if err := migrateYAMLNode(defaultsKey+"."+name, fragment, version); err != nil {
+			return nil, err
+		}
+		if def := typed[name]; def != nil {
+			if err := validateNodeYAML(defaultsKey+"."+name, &CatalogNodeYAML{
+				Ownership: def.Ownership, AccessPolicy: def.AccessPolicy, SourceBinding: def.SourceBinding,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		defaults[name] = fragment
+	}
+	return defaults, nil
+}
+
+// applyYAMLDefaults merges the default named by the node's extends key under
+// the node. The node wins on every field it sets; nested mappings such as
+// source_binding.config are merged key by key.
+func applyYAMLDefaults(path string, node *yaml.Node, defaults map[string]*yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	extends := yamlMappingValue(node, "extends")
+	if extends == nil {
+		return nil
+	}
+	if extends.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s: line %d: extends must be the name of a default", path, extends.Line)
+	}
+	fragment, ok := defaults[extends.Value]
+	if !ok {
+		return fmt.Errorf("%s: line %d: extends unknown default '%s'%s", path, extends.Line, extends.Value, availableNames(defaults, defaultsKey))
+	}
+	if fragment.Kind != yaml.MappingNode {
+		return nil
+	}
+	for _, block := range defaultsBlocks {
+		if key := yamlMappingKey(fragment, block); key != nil {
+			mergeYAMLMapping(node, key, yamlMappingValue(fragment, block))
+		}
+	}
+	return nil
+}
+
+// mergeYAMLMapping sets key to value in dst unless dst already has it; when
+// both values are mappings they are merged recursively. value is copied, so
+// later merges into dst never modify the fragment it came from.
+func mergeYAMLMapping(dst, key, value *yaml.Node) {
+	existing := yamlMappingValue(dst, key.Value)
+	if existing == nil {
+		dst.Content = append(dst.Content, key, copyYAMLNode(value))
+		return
+	}
+	if existing.Kind != yaml.MappingNode || value.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		mergeYAMLMapping(existing, value.Content[i], value.Content[i+1])
+	}
+}
+
+// copyYAMLNode returns a deep copy of n
+func copyYAMLNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	if n.Content != nil {
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = copyYAMLNode(child)
+		}
+	}
+	return &c
+}
+
+// parseJSONDefaults decodes and migrates the _defaults section of a JSON catalog
+func parseJSONDefaults(msg json.RawMessage, version int) (map[string]json.RawMessage, error) {
+	var defaults map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &defaults); err != nil {
+		return nil, fmt.Errorf("$.%s must be an object of name to ownership, access_policy and source_binding", defaultsKey)
+	}
+	for name, fragment := range defaults {
+		fragment, err := migrateJSONNode(defaultsKey+"."+name, fragment, version)
+		if err != nil {
+			return nil, err
+		}
+		var def *CatalogDefaultsYAML
+		if err := json.Unmarshal(fragment, &def); err != nil {
+			return nil, fmt.Errorf("%s", describeJSONError(fragment, fmt.Sprintf("$.%s[%q]", defaultsKey, name), err))
+		}
+		if def != nil {
+			if err := validateNodeYAML(defaultsKey+"."+name, &CatalogNodeYAML{
+				Ownership: def.Ownership, AccessPolicy: def.AccessPolicy, SourceBinding: def.SourceBinding,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		defaults[name] = fragment
+	}
+	return defaults, nil
+}
+
+// applyJSONDefaults is applyYAMLDefaults for a JSON node definition
+func applyJSONDefaults(path string, msg json.RawMessage, defaults map[string]json.RawMessage) (json.RawMessage, error) {
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &node); err != nil || node == nil {
+		return msg, nil // Reported with its JSON path by the regular decode
+	}
+	raw, ok := node["extends"]
+	if !ok {
+		return msg, nil
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return nil, fmt.Errorf("%s: extends must be the name of a default", path)
+	}
+	fragmentMsg, ok := defaults[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: extends unknown default '%s'%s", path, name, availableNames(defaults, defaultsKey))
+	}
+
+	var fragment map[string]json.RawMessage
+	if err := json.Unmarshal(fragmentMsg, &fragment); err != nil || fragment == nil {
+		return msg, nil
+	}
+	for _, block := range defaultsBlocks {
+		if value, ok := fragment[block]; ok {
+			node[block] = mergeJSONValue(node[block], value)
+		}
+	}
+	return json.Marshal(node)
+}
+
+// mergeJSONValue returns own, with keys from def filled in where own lacks
+// them; when both are objects they are merged recursively
+func mergeJSONValue(own, def json.RawMessage) json.RawMessage {
+	if own == nil {
+		return def
+	}
+	var ownObj, defObj map[string]json.RawMessage
+	if json.Unmarshal(own, &ownObj) != nil || json.Unmarshal(def, &defObj) != nil || ownObj == nil || defObj == nil {
+		return own
+	}
+	for key, value := range defObj {
+		ownObj[key] = mergeJSONValue(ownObj[key], value)
+	}
+	merged, err := json.Marshal(ownObj)
+	if err != nil {
+		return own
+	}
+	return merged
+}
+
+// availableNames lists the names defined in a section such as _defaults, for
+// error messages
+func availableNames[T any](defined map[string]T, section string) string {
+	if len(defined) == 0 {
+		return fmt.Sprintf(" (no %s defined)", section)
+	}
+	names := make([]string, 0, len(defined))
+	for name := range defined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(" (defined: %v)", names)
+}
@@ -0,0 +1,163 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+const defaultsCatalog = `
+_defaults:
+  rates_team:
+    ownership:
+      accountable_owner: rates-owner@example.com
+      support_channel: "#rates"
+    access_policy:
+      min_filters: 1
+      denial_message: Filter by currency
+    source_binding:
+      type: snowflake
+      read_only: true
+      config:
+        account: acme
+        database: RATES
+        warehouse: RATES_WH
+
+rates/swaps:
+  extends: rates_team
+  display_name: Swaps
+  ownership:
+    support_channel: "#swaps"
+  source_binding:
+    type: snowflake
+    read_only: false
+    config:
+      warehouse: SWAPS_WH
+      table: SWAPS
+
+rates/bonds:
+  extends: rates_team
+`
+
+func TestDefaultsMergedUnderNode(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(defaultsCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	swaps := reg.Get("rates/swaps")
+	if swaps == nil {
+		t.Fatal("rates/swaps not loaded")
+	}
+	if got := *swaps.Ownership.AccountableOwner; got != "rates-owner@example.com" {
+		t.Errorf("accountable_owner should come from the default, got %q", got)
+	}
+	if got := *swaps.Ownership.SupportChannel; got != "#swaps" {
+		t.Errorf("node's support_channel should win, got %q", got)
+	}
+	if swaps.AccessPolicy == nil || swaps.AccessPolicy.MinFilters != 1 {
+		t.Errorf("access_policy should come from the default, got %+v", swaps.AccessPolicy)
+	}
+	sb := swaps.SourceBinding
+	if sb.ReadOnly {
+		t.Error("node's read_only: false should win over the default")
+	}
+	want := map[string]interface{}{"account": "acme", "database": "RATES", "warehouse": "SWAPS_WH", "table": "SWAPS"}
+	for k, v := range want {
+		if sb.Config[k] != v {
+			t.Errorf("config[%s] = %v, want %v", k, sb.Config[k], v)
+		}
+	}
+
+	bonds := reg.Get("rates/bonds")
+	if bonds == nil || bonds.SourceBinding == nil || !bonds.SourceBinding.ReadOnly {
+		t.Errorf("rates/bonds should take the whole default, got %+v", bonds)
+	}
+	if unknown := UnknownFields(nodes); len(unknown) != 0 {
+		t.Errorf("expected no unknown fields, got %v", unknown)
+	}
+}
+
+func TestDefaultsJSONMatchesYAML(t *testing.T) {
+	yamlNodes, err := ParseCatalog([]byte(defaultsCatalog))
+	if err != nil {
+		t.Fatalf("parse YAML: %v", err)
+	}
+	jsonNodes, err := ParseCatalogJSON([]byte(`{
+  "_defaults": {"rates_team": {
+    "ownership": {"accountable_owner": "rates-owner@example.com", "support_channel": "#rates"},
+    "access_policy": {"min_filters": 1, "denial_message": "Filter by currency"},
+    "source_binding": {"type": "snowflake", "read_only": true,
+      "config": {"account": "acme", "database": "RATES", "warehouse": "RATES_WH"}}
+  }},
+  "rates/swaps": {"extends": "rates_team", "display_name": "Swaps",
+    "ownership": {"support_channel": "#swaps"},
+    "source_binding": {"type": "snowflake", "read_only": false, "config": {"warehouse": "SWAPS_WH", "table": "SWAPS"}}},
+  "rates/bonds": {"extends": "rates_team"}
+}`))
+	if err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+
+	reg := NewRegistry()
+	reg.RegisterMany(yamlNodes)
+	if diff := reg.Diff(jsonNodes); !diff.IsEmpty() {
+		t.Errorf("JSON and YAML catalogs differ: %+v", diff)
+	}
+}
+
+func TestDefaultsUnknownName(t *testing.T) {
+	_, err := ParseCatalog([]byte("_defaults:\n  a:\n    ownership:\n      adop: x\nprices:\n  extends: b\n"))
+	if err == nil || !strings.Contains(err.Error(), "extends unknown default 'b'") {
+		t.Errorf("expected unknown default error, got %v", err)
+	}
+
+	_, err = ParseCatalogJSON([]byte(`{"prices": {"extends": "b"}}`))
+	if err == nil || !strings.Contains(err.Error(), "no _defaults defined") {
+		t.Errorf("expected unknown default error for JSON, got %v", err)
+	}
+}
+
+func TestDefaultsValidated(t *testing.T) {
+	_, err := ParseCatalog([]byte("_defaults:\n  a:\n    access_policy:\n      allowed_hours: [9]\n"))
+	if err == nil || !strings.Contains(err.Error(), "_defaults.a") {
+		t.Errorf("expected validation error naming the default, got %v", err)
+	}
+}
+
+func TestDefaultsUnknownFieldsReportedOnce(t *testing.T) {
+	data := "_defaults:\n  a:\n    ownership:\n      acountable_owner: x\nprices:\n  extends: a\nrates:\n  extends: a\n"
+	nodes, err := ParseCatalog([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unknown := UnknownFields(nodes)
+	if len(unknown) != 1 || unknown[0].Path != "_defaults.a" || unknown[0].Line != 4 || unknown[0].Field != "acountable_owner" {
+		t.Errorf("expected one unknown field in _defaults.a at line 4, got %v", unknown)
+	}
+}
+
+func TestExportExpandsDefaults(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(defaultsCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	data, err := ExportYAML(reg, ExportFlat)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if strings.Contains(string(data), "extends") || strings.Contains(string(data), defaultsKey) {
+		t.Errorf("export should emit expanded nodes:\n%s", data)
+	}
+	reloaded, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("reparse export: %v", err)
+	}
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("expanded export differs from the loaded catalog: %+v", diff)
+	}
+}
@@ -0,0 +1,158 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Successor problems a deprecation report flags besides SuccessorIssueDangling
+// and SuccessorIssueArchived
+const (
+	SuccessorIssueNone       SuccessorIssueKind = "no_successor"         // Deprecated without a successor
+	SuccessorIssueDeprecated SuccessorIssueKind = "deprecated_successor" // Successor is itself deprecated
+)
+
+// DeprecationEntry is a deprecated node of a deprecation report. Owner is
+// the accountable owner after inheritance and Domain the nearest one set.
+// DaysRemaining counts calendar days (UTC) to the sunset deadline, negative
+// once it has passed; it is nil without a valid deadline.
+type DeprecationEntry struct {
+	Path              string             `json:"path"`
+	DisplayName       string             `json:"display_name"`
+	Domain            string             `json:"domain,omitempty"`
+	Owner             string             `json:"owner,omitempty"`
+	Successor         string             `json:"successor,omitempty"`
+	SuccessorIssue    SuccessorIssueKind `json:"successor_issue,omitempty"`
+	SunsetDeadline    string             `json:"sunset_deadline,omitempty"`
+	DaysRemaining     *int               `json:"days_remaining,omitempty"`
+	MigrationGuideURL string             `json:"migration_guide_url,omitempty"`
+
+	deadline *time.Time
+}
+
+// DeprecationFilter narrows a deprecation report. Domain matches case-
+// insensitively; Owner matches any of the accountable owner, ADOP, ADS and
+// ADAL after inheritance; Within, if set, keeps only entries whose deadline
+// is at most that far from now, including those already past.
+type DeprecationFilter struct {
+	Domain string
+	Owner  string
+	Within *time.Duration
+}
+
+// DeprecationReport returns the deprecated nodes matching filter, by sunset
+// deadline then path, with nodes lacking a valid deadline last
+func (r *Registry) DeprecationReport(now time.Time, filter DeprecationFilter) []DeprecationEntry {
+	snap := r.load()
+	today := startOfDay(now)
+
+	entries := make([]DeprecationEntry, 0)
+	for _, p := range snap.sortedPaths() {
+		node := snap.nodes[p]
+		if node.Status != NodeStatusDeprecated {
+			continue
+		}
+		domain := snap.resolveDomain(p)
+		if filter.Domain != "" && !strings.EqualFold(domain, filter.Domain) {
+			continue
+		}
+		ownership := snap.resolveOwnership(p)
+		if filter.Owner != "" && !holdsOwnerRole(ownership, filter.Owner) {
+			continue
+		}
+
+		entry := DeprecationEntry{Path: p, DisplayName: node.DisplayName, Domain: domain}
+		if ownership.AccountableOwner != nil {
+			entry.Owner = *ownership.AccountableOwner
+		}
+		if node.MigrationGuideURL != nil {
+			entry.MigrationGuideURL = *node.MigrationGuideURL
+		}
+		entry.Successor, entry.SuccessorIssue = snap.successorIssue(node)
+		if node.SunsetDeadline != nil {
+			entry.SunsetDeadline = *node.SunsetDeadline
+			if deadline, err := ParseSunsetDeadline(*node.SunsetDeadline); err == nil {
+				days := int(startOfDay(deadline).Sub(today) / (24 * time.Hour))
+				entry.deadline, entry.DaysRemaining = &deadline, &days
+			}
+		}
+		if filter.Within != nil && (entry.deadline == nil || entry.deadline.After(now.Add(*filter.Within))) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].deadline, entries[j].deadline
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+	return entries
+}
+
+// successorIssue returns node's successor and what is wrong with it, if
+// anything
+func (s *snapshot) successorIssue(node *CatalogNode) (string, SuccessorIssueKind) {
+	if node.Successor == nil || *node.Successor == "" {
+		return "", SuccessorIssueNone
+	}
+	successor := *node.Successor
+	next, ok := s.nodes[successor]
+	switch {
+	case !ok:
+		return successor, SuccessorIssueDangling
+	case next.Status == NodeStatusDeprecated:
+		return successor, SuccessorIssueDeprecated
+	case next.Status == NodeStatusArchived:
+		return successor, SuccessorIssueArchived
+	}
+	return successor, ""
+}
+
+// holdsOwnerRole reports whether id holds any owner role of ownership
+func holdsOwnerRole(ownership *ResolvedOwnership, id string) bool {
+	for _, held := range ownerRoles(ownership) {
+		if held.owner != nil && *held.owner == id {
+			return true
+		}
+	}
+	return false
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// deprecationColumns are the CSV columns of WriteDeprecationsCSV
+var deprecationColumns = []string{
+	"path", "display_name", "domain", "owner", "successor", "successor_issue",
+	"sunset_deadline", "days_remaining", "migration_guide_url",
+}
+
+// WriteDeprecationsCSV writes entries as CSV with a header row
+func WriteDeprecationsCSV(w io.Writer, entries []DeprecationEntry) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(deprecationColumns); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		days := ""
+		if e.DaysRemaining != nil {
+			days = strconv.Itoa(*e.DaysRemaining)
+		}
+		row := []string{e.Path, e.DisplayName, e.Domain, e.Owner, e.Successor, string(e.SuccessorIssue),
+			e.SunsetDeadline, days, e.MigrationGuideURL}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
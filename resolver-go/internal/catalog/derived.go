@@ -0,0 +1,234 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// Config keys of a derived source binding. inputs names the monikers the
+// value is computed from; exactly one of expression (arithmetic over the
+// inputs' fields, e.g. "a.value - b.value") or transform (the name of a
+// server-side transform) says how.
+const (
+	DerivedInputsKey     = "inputs"
+	DerivedExpressionKey = "expression"
+	DerivedTransformKey  = "transform"
+)
+
+// DerivedInputs returns the inputs of a derived binding config, by name
+func DerivedInputs(config map[string]interface{}) map[string]string {
+	raw, _ := config[DerivedInputsKey].(map[string]interface{})
+	inputs := make(map[string]string, len(raw))
+	for name, v := range raw {
+		if ref, ok := v.(string); ok {
+			inputs[name] = ref
+		}
+	}
+	return inputs
+}
+
+// checkDerived validates the config of a derived binding: every input is a
+// named moniker, and the expression parses and uses only declared inputs
+func checkDerived(path string, sb *SourceBinding) []SourceConfigViolation {
+	if sb.SourceType != SourceTypeDerived {
+		return nil
+	}
+	var violations []SourceConfigViolation
+	violation := func(msg string) {
+		violations = append(violations, SourceConfigViolation{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): %s", path, sb.SourceType, msg),
+		})
+	}
+
+	raw, isMap := sb.Config[DerivedInputsKey].(map[string]interface{})
+	if isMap && len(raw) == 0 {
+		violation(fmt.Sprintf("%s must name at least one input moniker", DerivedInputsKey))
+	}
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !paramNamePattern.MatchString(name) {
+			violation(fmt.Sprintf("%s: '%s' is not a valid input name", DerivedInputsKey, name))
+		}
+		ref, ok := raw[name].(string)
+		if !ok {
+			violation(fmt.Sprintf("%s.%s must be a moniker, got %T", DerivedInputsKey, name, raw[name]))
+			continue
+		}
+		if _, err := moniker.ParseMoniker(ref); err != nil {
+			violation(fmt.Sprintf("%s.%s is not a valid moniker: %v", DerivedInputsKey, name, err))
+		}
+	}
+
+	expression, hasExpression := sb.Config[DerivedExpressionKey].(string)
+	transform, hasTransform := sb.Config[DerivedTransformKey].(string)
+	switch {
+	case hasExpression == hasTransform:
+		violation(fmt.Sprintf("set exactly one of %s and %s", DerivedExpressionKey, DerivedTransformKey))
+	case hasTransform && !paramNamePattern.MatchString(transform):
+		violation(fmt.Sprintf("%s: '%s' is not a valid transform name", DerivedTransformKey, transform))
+	case hasExpression:
+		refs, err := ParseDerivedExpression(expression)
+		if err != nil {
+			violation(fmt.Sprintf("%s: %v", DerivedExpressionKey, err))
+			break
+		}
+		undeclared := make([]string, 0)
+		for _, ref := range refs {
+			if _, ok := raw[ref]; !ok {
+				undeclared = append(undeclared, ref)
+			}
+		}
+		if len(undeclared) > 0 {
+			violation(fmt.Sprintf("%s references inputs not in %s: %s", DerivedExpressionKey, DerivedInputsKey, strings.Join(undeclared, ", ")))
+		}
+	}
+	return violations
+}
+
+// ParseDerivedExpression checks the syntax of a derived expression and
+// returns the inputs it references, sorted. Expressions are numbers and
+// input references (name or name.field) combined with + - * /, unary minus
+// and parentheses.
+func ParseDerivedExpression(expr string) ([]string, error) {
+	tokens, err := tokenizeExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{tokens: tokens, refs: make(map[string]bool)}
+	if err := p.parseSum(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	refs := make([]string, 0, len(p.refs))
+	for ref := range p.refs {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// tokenizeExpression splits an expression into numbers, references,
+// operators and parentheses
+func tokenizeExpression(expr string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/()", r):
+			tokens = append(tokens, string(r))
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '.' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return tokens, nil
+}
+
+// expressionParser is a recursive-descent parser for derived expressions
+type expressionParser struct {
+	tokens []string
+	pos    int
+	refs   map[string]bool
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// parseSum parses term (('+' | '-') term)*
+func (p *expressionParser) parseSum() error {
+	if err := p.parseProduct(); err != nil {
+		return err
+	}
+	for p.peek() == "+" || p.peek() == "-" {
+		p.pos++
+		if err := p.parseProduct(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseProduct parses factor (('*' | '/') factor)*
+func (p *expressionParser) parseProduct() error {
+	if err := p.parseFactor(); err != nil {
+		return err
+	}
+	for p.peek() == "*" || p.peek() == "/" {
+		p.pos++
+		if err := p.parseFactor(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFactor parses a number, a reference, a negated factor or a
+// parenthesized sum
+func (p *expressionParser) parseFactor() error {
+	tok := p.peek()
+	p.pos++
+	switch {
+	case tok == "":
+		return fmt.Errorf("unexpected end of expression")
+	case tok == "-":
+		return p.parseFactor()
+	case tok == "(":
+		if err := p.parseSum(); err != nil {
+			return err
+		}
+		if p.peek() != ")" {
+			return fmt.Errorf("missing )")
+		}
+		p.pos++
+		return nil
+	case unicode.IsDigit(rune(tok[0])) || tok[0] == '.':
+		if strings.Count(tok, ".") > 1 || tok == "." {
+			return fmt.Errorf("bad number %q", tok)
+		}
+		return nil
+	case tok[0] == '_' || unicode.IsLetter(rune(tok[0])):
+		name, field, hasField := strings.Cut(tok, ".")
+		if hasField && (field == "" || strings.Contains(field, ".")) {
+			return fmt.Errorf("bad reference %q (want input or input.field)", tok)
+		}
+		p.refs[name] = true
+		return nil
+	}
+	return fmt.Errorf("unexpected %q", tok)
+}
@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseDerivedExpression(t *testing.T) {
+	valid := map[string]string{
+		"a.value - b.value":              "a,b",
+		"(spot.mid + fwd.points) * 1.5":  "fwd,spot",
+		"-a / (b - 2)":                   "a,b",
+		"notional * rate.value * rate.x": "notional,rate",
+	}
+	for expr, want := range valid {
+		refs, err := ParseDerivedExpression(expr)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", expr, err)
+			continue
+		}
+		if got := strings.Join(refs, ","); got != want {
+			t.Errorf("%q: expected refs %s, got %s", expr, want, got)
+		}
+	}
+
+	invalid := map[string]string{
+		"":              "empty expression",
+		"a.value -":     "unexpected end",
+		"(a + b":        "missing )",
+		"a.b.c":         "bad reference",
+		"a; DROP TABLE": "unexpected character",
+		"a b":           `unexpected "b"`,
+	}
+	for expr, want := range invalid {
+		if _, err := ParseDerivedExpression(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", expr, want, err)
+		}
+	}
+}
+
+func TestDerivedConfigValidation(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+analytics/spread:
+  source_binding:
+    type: derived
+    config:
+      inputs: {a: prices/equity/AAPL, b: prices/equity/MSFT}
+      expression: a.value - c.value
+analytics/both:
+  source_binding:
+    type: derived
+    config:
+      inputs: {a: prices/equity/AAPL}
+      expression: a.value
+      transform: zscore
+analytics/bad:
+  source_binding:
+    type: derived
+    config:
+      inputs: {a: "prices/AAPL@3M"}
+      transform: zscore
+analytics/none:
+  source_binding:
+    type: derived
+    config:
+      expression: "1"
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *SourceConfigError, got %v", err)
+	}
+	for _, want := range []string{
+		"analytics/spread (derived): expression references inputs not in inputs: c",
+		"analytics/both (derived): set exactly one of expression and transform",
+		"analytics/bad (derived): inputs.a is not a valid moniker",
+		"analytics/none (derived): missing required config keys: inputs",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
+
+func TestValidateReferencesDerivedInputs(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, true))
+	spread := makeNode("analytics/spread", "Spread", "", NodeStatusActive, true)
+	spread.SourceBinding = &SourceBinding{
+		SourceType: SourceTypeDerived,
+		Config: map[string]interface{}{
+			"inputs":     map[string]interface{}{"a": "prices/AAPL", "b": "rates/curve"},
+			"expression": "a.value - b.value",
+		},
+	}
+	r.Register(spread)
+
+	issues := r.ValidateReferences()
+	if len(issues) != 1 || issues[0].Kind != ReferenceIssueBroken || issues[0].Field != "source_binding.config.inputs.b" {
+		t.Errorf("expected one broken reference for input b, got %v", issues)
+	}
+}
@@ -0,0 +1,175 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// FieldChange describes a single changed field on a modified node
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// NodeChange describes a node present in both catalogs whose definition changed
+type NodeChange struct {
+	Path    string        `json:"path"`
+	Changes []FieldChange `json:"changes"`
+
+	// BreakingContract is set when the source binding fingerprint changed
+	BreakingContract bool    `json:"breaking_contract"`
+	OldFingerprint   *string `json:"old_fingerprint,omitempty"`
+	NewFingerprint   *string `json:"new_fingerprint,omitempty"`
+}
+
+// CatalogDiff describes the difference between the live registry and a new snapshot
+type CatalogDiff struct {
+	Added    []string     `json:"added"`
+	Removed  []string     `json:"removed"`
+	Modified []NodeChange `json:"modified"`
+}
+
+// IsEmpty returns true if the snapshots are identical
+func (d *CatalogDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// BreakingChanges returns the paths whose binding contract changed
+func (d *CatalogDiff) BreakingChanges() []string {
+	result := make([]string, 0)
+	for _, m := range d.Modified {
+		if m.BreakingContract {
+			result = append(result, m.Path)
+		}
+	}
+	return result
+}
+
+// Summary returns a one-line human readable summary
+func (d *CatalogDiff) Summary() string {
+	return fmt.Sprintf("%d added, %d removed, %d modified (%d contract-breaking)",
+		len(d.Added), len(d.Removed), len(d.Modified), len(d.BreakingChanges()))
+}
+
+// Diff compares the live registry against a new set of nodes
+func (r *Registry) Diff(newNodes []*CatalogNode) *CatalogDiff {
+	return diffNodes(r.load().nodes, newNodes)
+}
+
+func diffNodes(current map[string]*CatalogNode, newNodes []*CatalogNode) *CatalogDiff {
+	diff := &CatalogDiff{
+		Added:    make([]string, 0),
+		Removed:  make([]string, 0),
+		Modified: make([]NodeChange, 0),
+	}
+
+	incoming := make(map[string]*CatalogNode, len(newNodes))
+	for _, node := range newNodes {
+		incoming[node.Path] = node
+	}
+
+	for path, newNode := range incoming {
+		oldNode, ok := current[path]
+		if !ok {
+			diff.Added = append(diff.Added, path)
+			continue
+		}
+		if change := diffNode(oldNode, newNode); change != nil {
+			diff.Modified = append(diff.Modified, *change)
+		}
+	}
+	for path := range current {
+		if _, ok := incoming[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Modified, func(i, j int) bool {
+		return diff.Modified[i].Path < diff.Modified[j].Path
+	})
+
+	return diff
+}
+
+// diffNode compares two definitions of the same path field by field, using
+// the JSON representation so every serialized field participates.
+func diffNode(oldNode, newNode *CatalogNode) *NodeChange {
+	oldFields := nodeFields(oldNode)
+	newFields := nodeFields(newNode)
+
+	keys := make(map[string]bool)
+	for k := range oldFields {
+		keys[k] = true
+	}
+	for k := range newFields {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	change := &NodeChange{Path: newNode.Path}
+	for _, k := range sorted {
+		if bytes.Equal(oldFields[k], newFields[k]) {
+			continue
+		}
+		change.Changes = append(change.Changes, FieldChange{
+			Field: k,
+			Old:   decodeRaw(oldFields[k]),
+			New:   decodeRaw(newFields[k]),
+		})
+	}
+
+	oldFP := bindingFingerprint(oldNode)
+	newFP := bindingFingerprint(newNode)
+	if !equalStringPtr(oldFP, newFP) {
+		change.BreakingContract = true
+		change.OldFingerprint = oldFP
+		change.NewFingerprint = newFP
+	}
+
+	if len(change.Changes) == 0 && !change.BreakingContract {
+		return nil
+	}
+	return change
+}
+
+func nodeFields(node *CatalogNode) map[string]json.RawMessage {
+	raw, _ := json.Marshal(node)
+	fields := make(map[string]json.RawMessage)
+	_ = json.Unmarshal(raw, &fields)
+	return fields
+}
+
+func decodeRaw(raw json.RawMessage) interface{} {
+	if raw == nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func bindingFingerprint(node *CatalogNode) *string {
+	if node.SourceBinding == nil {
+		return nil
+	}
+	fp := node.SourceBinding.Fingerprint()
+	return &fp
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
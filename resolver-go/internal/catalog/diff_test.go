@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func makeBoundNode(path, table string) *CatalogNode {
+	node := makeNode(path, path, "", NodeStatusActive, true)
+	node.SourceBinding = &SourceBinding{
+		SourceType: SourceTypeSnowflake,
+		Config:     map[string]interface{}{"table": table},
+		ReadOnly:   true,
+	}
+	return node
+}
+
+func TestDiffAddedRemovedModified(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("keep", "Keep", "same", NodeStatusActive, false))
+	r.Register(makeNode("gone", "Gone", "", NodeStatusActive, false))
+	r.Register(makeNode("edit", "Edit", "old description", NodeStatusActive, false))
+
+	diff := r.Diff([]*CatalogNode{
+		makeNode("keep", "Keep", "same", NodeStatusActive, false),
+		makeNode("edit", "Edit", "new description", NodeStatusActive, false),
+		makeNode("new", "New", "", NodeStatusActive, false),
+	})
+
+	if len(diff.Added) != 1 || diff.Added[0] != "new" {
+		t.Errorf("expected added [new], got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "gone" {
+		t.Errorf("expected removed [gone], got %v", diff.Removed)
+	}
+	if len(diff.Modified) != 1 {
+		t.Fatalf("expected 1 modified node, got %d", len(diff.Modified))
+	}
+	mod := diff.Modified[0]
+	if mod.Path != "edit" || len(mod.Changes) != 1 || mod.Changes[0].Field != "description" {
+		t.Errorf("expected description change on 'edit', got %+v", mod)
+	}
+	if mod.BreakingContract {
+		t.Error("description change should not be contract-breaking")
+	}
+}
+
+func TestDiffFlagsBindingFingerprintChange(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeBoundNode("prices/equity", "EQUITY"))
+
+	diff := r.Diff([]*CatalogNode{makeBoundNode("prices/equity", "EQUITY_V2")})
+
+	breaking := diff.BreakingChanges()
+	if len(breaking) != 1 || breaking[0] != "prices/equity" {
+		t.Fatalf("expected contract change on prices/equity, got %v", breaking)
+	}
+	if diff.Modified[0].OldFingerprint == nil || diff.Modified[0].NewFingerprint == nil {
+		t.Error("expected both fingerprints to be reported")
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeBoundNode("prices/equity", "EQUITY"))
+
+	diff := r.Diff([]*CatalogNode{makeBoundNode("prices/equity", "EQUITY")})
+	if !diff.IsEmpty() {
+		t.Errorf("expected empty diff, got %s", diff.Summary())
+	}
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"strings"
+)
+
+// UnassignedDomain groups the nodes whose top-level node declares no domain
+const UnassignedDomain = "unassigned"
+
+// DomainRoot is a top-level path of a domain, with its resolved ownership
+// when it is registered
+type DomainRoot struct {
+	Path      string             `json:"path"`
+	Ownership *ResolvedOwnership `json:"ownership,omitempty"`
+}
+
+// DomainSummary aggregates the nodes under the top-level nodes that declare
+// a domain. A node is missing an owner when it has no accountable owner
+// after inheritance.
+type DomainSummary struct {
+	Name             string         `json:"name"`
+	Roots            []DomainRoot   `json:"roots"`
+	Nodes            int            `json:"nodes"`
+	ByStatus         map[string]int `json:"by_status"`
+	BySourceType     map[string]int `json:"by_source_type"`
+	HasDeprecated    bool           `json:"has_deprecated"`
+	MissingOwners    int            `json:"missing_owners"`
+	HasMissingOwners bool           `json:"has_missing_owners"`
+}
+
+// Domains summarizes each declared domain in order of name, then
+// UnassignedDomain if any node falls in no domain. Nodes belong to the domain
+// their first segment's node declares.
+func (r *Registry) Domains() []DomainSummary {
+	snap := r.load()
+
+	byName := make(map[string]*DomainSummary)
+	rootSeen := make(map[string]bool)
+	for _, p := range snap.sortedPaths() {
+		root := p
+		if i := strings.Index(p, "/"); i >= 0 {
+			root = p[:i]
+		}
+		name := UnassignedDomain
+		if node, ok := snap.nodes[root]; ok && node.Domain != nil && *node.Domain != "" {
+			name = *node.Domain
+		}
+
+		domain := byName[name]
+		if domain == nil {
+			domain = &DomainSummary{
+				Name:         name,
+				Roots:        make([]DomainRoot, 0),
+				ByStatus:     make(map[string]int),
+				BySourceType: make(map[string]int),
+			}
+			byName[name] = domain
+		}
+		if !rootSeen[root] {
+			rootSeen[root] = true
+			entry := DomainRoot{Path: root}
+			if _, ok := snap.nodes[root]; ok {
+				entry.Ownership = snap.resolveOwnership(root)
+			}
+			domain.Roots = append(domain.Roots, entry)
+		}
+
+		node := snap.nodes[p]
+		domain.Nodes++
+		domain.ByStatus[string(node.Status)]++
+		if node.SourceBinding != nil {
+			domain.BySourceType[string(node.SourceBinding.SourceType)]++
+		}
+		if node.Status == NodeStatusDeprecated {
+			domain.HasDeprecated = true
+		}
+		if snap.resolveOwnership(p).AccountableOwner == nil {
+			domain.MissingOwners++
+			domain.HasMissingOwners = true
+		}
+	}
+
+	names := sortedKeys(byName)
+	result := make([]DomainSummary, 0, len(names))
+	for _, name := range names {
+		if name != UnassignedDomain {
+			result = append(result, *byName[name])
+		}
+	}
+	if unassigned := byName[UnassignedDomain]; unassigned != nil {
+		result = append(result, *unassigned)
+	}
+	return result
+}
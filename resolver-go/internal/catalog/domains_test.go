@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestDomainsGroupsByTopLevelNode(t *testing.T) {
+	r := NewRegistry()
+	prices := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	prices.Domain = strPtr("market-data")
+	prices.Ownership = &Ownership{AccountableOwner: strPtr("team-prices")}
+	r.Register(prices)
+	equity := makeNode("prices/equity", "Equity", "", NodeStatusActive, true)
+	equity.SourceBinding = &SourceBinding{SourceType: SourceTypeSnowflake}
+	r.Register(equity)
+	r.Register(makeNode("prices/old", "Old", "", NodeStatusDeprecated, true))
+
+	curves := makeNode("curves", "Curves", "", NodeStatusActive, false)
+	curves.Domain = strPtr("market-data")
+	r.Register(curves)
+
+	// No top-level node, and a top-level node declaring no domain
+	r.Register(makeNode("scratch/tmp", "Tmp", "", NodeStatusDraft, true))
+	r.Register(makeNode("misc", "Misc", "", NodeStatusActive, false))
+
+	domains := r.Domains()
+	if len(domains) != 2 || domains[0].Name != "market-data" || domains[1].Name != UnassignedDomain {
+		t.Fatalf("unexpected domains %+v", domains)
+	}
+
+	market := domains[0]
+	if len(market.Roots) != 2 || market.Roots[0].Path != "curves" || market.Roots[1].Path != "prices" {
+		t.Errorf("unexpected roots %+v", market.Roots)
+	}
+	if owner := market.Roots[1].Ownership; owner == nil || *owner.AccountableOwner != "team-prices" {
+		t.Errorf("expected prices' ownership, got %+v", owner)
+	}
+	if market.Nodes != 4 || market.ByStatus[string(NodeStatusDeprecated)] != 1 || market.BySourceType[string(SourceTypeSnowflake)] != 1 {
+		t.Errorf("unexpected counts %+v", market)
+	}
+	if !market.HasDeprecated || market.MissingOwners != 1 || !market.HasMissingOwners {
+		t.Errorf("expected a deprecated node and curves missing an owner, got %+v", market)
+	}
+
+	unassigned := domains[1]
+	if unassigned.Nodes != 2 || len(unassigned.Roots) != 2 || unassigned.Roots[1].Ownership != nil {
+		t.Errorf("unexpected unassigned bucket %+v", unassigned)
+	}
+}
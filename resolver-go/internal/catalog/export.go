@@ -0,0 +1,267 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportYAML serializes the registry in the format read by LoadCatalog, with
+// every node keyed by its full path (ExportFlat) or nested under its parent's
+// children (ExportNested). Virtual nodes are never exported since they are
+// not registered.
+func ExportYAML(reg *Registry, style ExportStyle) ([]byte, error) {
+	return ExportNodesYAML(reg.AllNodes(), style)
+}
+
+// ExportJSON serializes the registry as JSON using the same structure and
+// field names as the YAML format.
+func ExportJSON(reg *Registry, style ExportStyle) ([]byte, error) {
+	return ExportNodesJSON(reg.AllNodes(), style)
+}
+
+// ExportNodesYAML is ExportYAML for a subset of the catalog
+func ExportNodesYAML(nodes []*CatalogNode, style ExportStyle) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteNodesYAML(&buf, nodes, style); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportNodesJSON is ExportJSON for a subset of the catalog
+func ExportNodesJSON(nodes []*CatalogNode, style ExportStyle) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteNodesJSON(&buf, nodes, style); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// WriteNodesYAML streams ExportNodesYAML to w
+func WriteNodesYAML(w io.Writer, nodes []*CatalogNode, style ExportStyle) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(exportDocument(nodes, style)); err != nil {
+		return fmt.Errorf("marshal catalog YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// WriteNodesJSON streams ExportNodesJSON to w
+func WriteNodesJSON(w io.Writer, nodes []*CatalogNode, style ExportStyle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(exportDocument(nodes, style)); err != nil {
+		return fmt.Errorf("marshal catalog JSON: %w", err)
+	}
+	return nil
+}
+
+// ExportNodes returns the registered nodes at and beneath path ("" for the
+// whole catalog), sorted by path, with the digest of the catalog they were
+// read from: its Version, or when it has been changed since it was loaded
+// (status changes, admin edits) the checksum of the catalog served now. Both
+// come from one snapshot. Virtual paths are not registered and so are never
+// exported; the loader recreates them from the paths beneath.
+func (r *Registry) ExportNodes(path string) ([]*CatalogNode, string) {
+	snap := r.load()
+	digest := snap.version
+	if digest == "" {
+		all := make([]*CatalogNode, 0, len(snap.nodes))
+		for _, node := range snap.nodes {
+			all = append(all, node)
+		}
+		digest = catalogChecksum(all)
+	}
+
+	nodes := make([]*CatalogNode, 0)
+	for _, p := range snap.sortedPaths() {
+		under := path == "" || p == path
+		for _, a := range ancestorPaths(p) {
+			under = under || a == path
+		}
+		if under {
+			nodes = append(nodes, snap.nodes[p])
+		}
+	}
+	return nodes, digest
+}
+
+func exportDocument(nodes []*CatalogNode, style ExportStyle) interface{} {
+	if style == ExportNested {
+		return nestCatalog(nodes)
+	}
+	return exportCatalog(nodes)
+}
+
+func exportCatalog(nodes []*CatalogNode) CatalogYAML {
+	doc := make(CatalogYAML, len(nodes))
+	for _, node := range nodes {
+		doc[node.Path] = convertNodeToYAML(node)
+	}
+	return doc
+}
+
+// convertNodeToYAML is the inverse of convertYAMLToNode. Only fields the
+// loader reads are written, so exporting and reloading is lossless.
+func convertNodeToYAML(node *CatalogNode) *CatalogNodeYAML {
+	out := &CatalogNodeYAML{
+		DisplayName:        node.DisplayName,
+		Description:        node.Description,
+		AssetClass:         node.AssetClass,
+		UpdateFrequency:    node.UpdateFrequency,
+		Domain:             node.Domain,
+		Vendor:             node.Vendor,
+		Maturity:           node.Maturity,
+		Classification:     node.Classification,
+		Tags:               node.Tags,
+		Status:             string(node.Status),
+		IsLeaf:             node.IsLeaf,
+		CreatedAt:          node.CreatedAt,
+		UpdatedAt:          node.UpdatedAt,
+		CreatedBy:          node.CreatedBy,
+		ApprovedBy:         node.ApprovedBy,
+		Successor:          node.Successor,
+		DeprecationMessage: node.DeprecationMessage,
+		Metadata:           node.Metadata,
+	}
+
+	// The loader only accepts these on deprecated nodes; a node reactivated
+	// through the admin API may still carry them
+	if node.Status == NodeStatusDeprecated {
+		out.MigrationGuideURL = node.MigrationGuideURL
+		out.SunsetDeadline = node.SunsetDeadline
+	}
+
+	if node.TechnicalDescription != nil {
+		out.TechnicalDescription = *node.TechnicalDescription
+	}
+
+	if o := node.Ownership; o != nil {
+		out.Ownership = &OwnershipYAML{
+			AccountableOwner: o.AccountableOwner,
+			DataSpecialist:   o.DataSpecialist,
+			SupportChannel:   o.SupportChannel,
+			ADOP:             o.ADOP,
+			ADS:              o.ADS,
+			ADAL:             o.ADAL,
+			ADOPName:         o.ADOPName,
+			ADSName:          o.ADSName,
+			ADALName:         o.ADALName,
+			UI:               o.UI,
+		}
+	}
+
+	if sb := node.SourceBinding; sb != nil {
+		readOnly := sb.ReadOnly
+		out.SourceBinding = &SourceBindingYAML{
+			Type:              string(sb.SourceType),
+			Config:            sb.Config,
+			AllowedOperations: sb.AllowedOperations,
+			Schema:            sb.Schema,
+			ReadOnly:          &readOnly,
+		}
+		if c := sb.Cache; c != nil {
+			out.SourceBinding.Cache = &QueryCacheConfigYAML{
+				Enabled:                c.Enabled,
+				TTLSeconds:             c.TTLSeconds,
+				RefreshIntervalSeconds: c.RefreshIntervalSeconds,
+				RefreshOnStartup:       c.RefreshOnStartup,
+			}
+		}
+	}
+
+	if ap := node.AccessPolicy; ap != nil {
+		baseRowCount := ap.BaseRowCount
+		out.AccessPolicy = &AccessPolicyYAML{
+			RequiredSegments:       ap.RequiredSegments,
+			BlockedPatterns:        ap.BlockedPatterns,
+			MaxRowsWarn:            ap.MaxRowsWarn,
+			MaxRowsBlock:           ap.MaxRowsBlock,
+			CardinalityMultipliers: ap.CardinalityMultipliers,
+			BaseRowCount:           &baseRowCount,
+			DenialMessage:          ap.DenialMessage,
+
+			RequireConfirmationAbove: ap.RequireConfirmationAbove,
+			AllowedRoles:             ap.AllowedRoles,
+		}
+		if ap.AllowedHours != nil {
+			out.AccessPolicy.AllowedHours = ap.AllowedHours[:]
+		}
+		if ap.MinFilters != 0 {
+			minFilters := ap.MinFilters
+			out.AccessPolicy.MinFilters = &minFilters
+		}
+	}
+
+	if d := node.Documentation; d != nil {
+		out.Documentation = &DocumentationYAML{
+			Glossary:       d.GlossaryURL,
+			Runbook:        d.RunbookURL,
+			Onboarding:     d.OnboardingURL,
+			DataDictionary: d.DataDictionaryURL,
+			APIDocs:        d.APIDocsURL,
+			Architecture:   d.ArchitectureURL,
+			Changelog:      d.ChangelogURL,
+			Contact:        d.ContactURL,
+			Additional:     d.AdditionalLinks,
+		}
+	}
+
+	if s := node.DataSchema; s != nil {
+		out.Schema = &DataSchemaYAML{
+			Description:     s.Description,
+			SemanticTags:    s.SemanticTags,
+			PrimaryKey:      s.PrimaryKey,
+			UseCases:        s.UseCases,
+			Examples:        s.Examples,
+			RelatedMonikers: s.RelatedMonikers,
+			Granularity:     s.Granularity,
+			TypicalRowCount: s.TypicalRowCount,
+			UpdateFrequency: s.UpdateFrequency,
+		}
+		for _, c := range s.Columns {
+			out.Schema.Columns = append(out.Schema.Columns, ColumnSchemaYAML{
+				Name:         c.Name,
+				Type:         c.DataType,
+				Description:  c.Description,
+				SemanticType: c.SemanticType,
+				Example:      c.Example,
+				Nullable:     c.Nullable,
+				PrimaryKey:   c.PrimaryKey,
+				ForeignKey:   c.ForeignKey,
+			})
+		}
+	}
+
+	if dq := node.DataQuality; dq != nil {
+		out.DataQuality = &DataQualityYAML{
+			DQOwner:         dq.DQOwner,
+			QualityScore:    dq.QualityScore,
+			ValidationRules: dq.ValidationRules,
+			KnownIssues:     dq.KnownIssues,
+			LastValidated:   dq.LastValidated,
+		}
+	}
+	if sla := node.SLA; sla != nil {
+		out.SLA = &SLAYAML{
+			Freshness:         sla.Freshness,
+			Availability:      sla.Availability,
+			SupportHours:      sla.SupportHours,
+			EscalationContact: sla.EscalationContact,
+		}
+	}
+	if f := node.Freshness; f != nil {
+		out.Freshness = &FreshnessYAML{
+			LastLoaded:           f.LastLoaded,
+			RefreshSchedule:      f.RefreshSchedule,
+			SourceSystem:         f.SourceSystem,
+			UpstreamDependencies: f.UpstreamDependencies,
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,183 @@
+package catalog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const exportFixture = `
+prices:
+  display_name: Prices
+  description: Market prices
+  classification: internal
+  tags: [market]
+  ownership:
+    accountable_owner: prices-owner
+    adop: jane
+    adop_name: Jane Doe
+  sla:
+    freshness: T+1
+    support_hours: 24x7
+  data_quality:
+    dq_owner: dq-team
+    quality_score: 0.97
+    validation_rules: ["not_null(price)"]
+
+prices/equity:
+  display_name: Equity Prices
+  description: Equity closing prices
+  technical_description: Sourced from the EOD snapshot
+  domain: markets
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: PRICES
+      port: 443
+    allowed_operations: [read]
+    read_only: true
+  access_policy:
+    required_segments: [0]
+    min_filters: 1
+    blocked_patterns: ["^ALL/ALL$"]
+    max_rows_block: 100000
+    cardinality_multipliers: [500, 250]
+    base_row_count: 10
+    denial_message: Too broad
+  schema:
+    columns:
+      - name: ticker
+        type: string
+        semantic_type: identifier
+        primary_key: true
+      - name: cusip
+        type: string
+        foreign_key: reference/securities
+    semantic_tags: [pricing]
+  freshness:
+    refresh_schedule: daily
+    upstream_dependencies: [vendor/feed]
+
+prices/legacy:
+  display_name: Legacy Prices
+  status: deprecated
+  created_at: "2021-05-04"
+  created_by: alice
+  approved_by: governance
+  successor: prices/equity
+  deprecation_message: Use prices/equity
+  migration_guide_url: https://wiki/migrate
+  sunset_deadline: "2027-01-01"
+  is_leaf: true
+`
+
+func loadExportFixture(t *testing.T) *Registry {
+	t.Helper()
+	nodes, err := ParseCatalog([]byte(exportFixture))
+	if err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+	return reg
+}
+
+func TestExportYAMLRoundTrip(t *testing.T) {
+	reg := loadExportFixture(t)
+
+	data, err := ExportYAML(reg, ExportFlat)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	reloaded, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("reload exported YAML: %v\n%s", err, data)
+	}
+
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("round trip changed the catalog: %s\n%+v", diff.Summary(), diff.Modified)
+	}
+}
+
+func TestExportJSONRoundTrip(t *testing.T) {
+	reg := loadExportFixture(t)
+
+	data, err := ExportJSON(reg, ExportFlat)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var doc CatalogYAML
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode exported JSON: %v", err)
+	}
+	reloaded := make([]*CatalogNode, 0, len(doc))
+	for path, n := range doc {
+		reloaded = append(reloaded, convertYAMLToNode(path, n))
+	}
+
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("round trip changed the catalog: %s\n%+v", diff.Summary(), diff.Modified)
+	}
+}
+
+func TestExportIncludesRuntimeStatus(t *testing.T) {
+	reg := loadExportFixture(t)
+	reg.Get("prices/equity").Status = NodeStatusDeprecated
+
+	data, err := ExportYAML(reg, ExportFlat)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	reloaded, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	for _, n := range reloaded {
+		if n.Path == "prices/equity" && n.Status != NodeStatusDeprecated {
+			t.Errorf("expected runtime status change to be exported, got %s", n.Status)
+		}
+	}
+}
+
+func TestExportNodesLoadsWithLoadCatalog(t *testing.T) {
+	reg := loadExportFixture(t)
+	reg.Register(makeNode("pricing", "Not under prices", "", NodeStatusActive, true))
+	if _, err := reg.UpdateStatus("prices/equity", NodeStatusDeprecated, "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, digest := reg.ExportNodes("prices")
+	if len(nodes) != 3 || nodes[0].Path != "prices" || nodes[2].Path != "prices/legacy" {
+		t.Fatalf("expected the three prices nodes in order, got %d", len(nodes))
+	}
+	if len(digest) != 64 {
+		t.Errorf("expected a checksum digest for a modified catalog, got %q", digest)
+	}
+
+	file := filepath.Join(t.TempDir(), "export.yaml")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteNodesYAML(f, nodes, ExportFlat); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	reloaded, err := LoadCatalog(file)
+	if err != nil {
+		t.Fatalf("LoadCatalog rejected the export: %v", err)
+	}
+	subtree := NewRegistry()
+	subtree.RegisterMany(nodes)
+	if diff := subtree.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("round trip changed the catalog: %s\n%+v", diff.Summary(), diff.Modified)
+	}
+
+	reg.SetVersion("v1")
+	if _, digest := reg.ExportNodes(""); digest != "v1" {
+		t.Errorf("expected the loaded version as digest, got %q", digest)
+	}
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FrequenciesKey is the source binding config key listing the data
+// frequencies a source supports, e.g. [daily]. The first is the default
+// when a moniker does not ask for one.
+const FrequenciesKey = "frequencies"
+
+// Frequencies are the frequency names a binding may declare
+var Frequencies = []string{"daily", "weekly", "monthly", "quarterly", "yearly"}
+
+// IsFrequency reports whether name is one of Frequencies
+func IsFrequency(name string) bool {
+	for _, f := range Frequencies {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedFrequencies returns the frequencies declared in a binding config,
+// in declaration order, or nil if it declares none
+func SupportedFrequencies(config map[string]interface{}) []string {
+	list, _ := config[FrequenciesKey].([]interface{})
+	if len(list) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		if name, ok := item.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkFrequencies validates the frequencies config key: a non-empty list of
+// known frequency names
+func checkFrequencies(path string, sb *SourceBinding) []SourceConfigViolation {
+	raw, ok := sb.Config[FrequenciesKey]
+	if !ok {
+		return nil
+	}
+	violation := func(msg string) []SourceConfigViolation {
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): %s", path, sb.SourceType, msg),
+		}}
+	}
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return violation(fmt.Sprintf("config key '%s' must be a non-empty list of frequencies", FrequenciesKey))
+	}
+	for _, item := range list {
+		name, _ := item.(string)
+		if !IsFrequency(name) {
+			return violation(fmt.Sprintf("%s: unknown frequency %v (want one of %s)", FrequenciesKey, item, strings.Join(Frequencies, ", ")))
+		}
+	}
+	return nil
+}
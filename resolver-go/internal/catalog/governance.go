@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"sort"
+)
+
+// NodeGovernance describes the governance gaps of a single node
+type NodeGovernance struct {
+	Path   string     `json:"path"`
+	Status NodeStatus `json:"status"`
+	Domain string     `json:"domain,omitempty"` // Nearest domain in the hierarchy
+
+	OwnershipComplete     bool `json:"ownership_complete"`
+	HasGovernanceRoles    bool `json:"has_governance_roles"`
+	MissingClassification bool `json:"missing_classification"`
+	MissingSchema         bool `json:"missing_schema"`    // Leaf with a binding but no schema
+	MissingSuccessor      bool `json:"missing_successor"` // Deprecated without a successor
+}
+
+// HasGaps returns true if the node fails any governance check
+func (n NodeGovernance) HasGaps() bool {
+	return !n.OwnershipComplete || !n.HasGovernanceRoles || n.MissingClassification ||
+		n.MissingSchema || n.MissingSuccessor
+}
+
+// GovernanceReport summarizes governance gaps across the catalog.
+// Active nodes are checked for ownership, governance roles, classification,
+// and schema; deprecated nodes are checked for a successor.
+type GovernanceReport struct {
+	Nodes []NodeGovernance `json:"nodes"` // Only nodes with at least one gap
+	all   []NodeGovernance // Every checked node, kept for filtering
+
+	ActiveNodes                int      `json:"active_nodes"`
+	IncompleteOwnership        []string `json:"incomplete_ownership"`
+	MissingGovernanceRoles     []string `json:"missing_governance_roles"`
+	MissingClassification      []string `json:"missing_classification"`
+	MissingSchema              []string `json:"missing_schema"`
+	DeprecatedWithoutSuccessor []string `json:"deprecated_without_successor"`
+}
+
+// GovernanceReport checks every active and deprecated node after inheritance
+func (r *Registry) GovernanceReport() *GovernanceReport {
+	snap := r.load()
+
+	paths := make([]string, 0, len(snap.nodes))
+	for p := range snap.nodes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	entries := make([]NodeGovernance, 0)
+	for _, p := range paths {
+		node := snap.nodes[p]
+		entry := NodeGovernance{
+			Path:               p,
+			Status:             node.Status,
+			Domain:             snap.resolveDomain(p),
+			OwnershipComplete:  true,
+			HasGovernanceRoles: true,
+		}
+
+		switch node.Status {
+		case NodeStatusActive:
+			ownership := r.ResolveOwnership(p).ToOwnership()
+			entry.OwnershipComplete = ownership.IsComplete()
+			entry.HasGovernanceRoles = ownership.HasGovernanceRoles()
+			_, definedAt := r.ResolveClassification(p)
+			entry.MissingClassification = definedAt == ""
+			entry.MissingSchema = node.IsLeaf && node.SourceBinding != nil && node.DataSchema == nil
+		case NodeStatusDeprecated:
+			entry.MissingSuccessor = node.Successor == nil || *node.Successor == ""
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return buildGovernanceReport(entries)
+}
+
+// FilterDomain returns a report restricted to nodes in the given domain
+func (g *GovernanceReport) FilterDomain(domain string) *GovernanceReport {
+	entries := make([]NodeGovernance, 0)
+	for _, n := range g.all {
+		if n.Domain == domain {
+			entries = append(entries, n)
+		}
+	}
+	return buildGovernanceReport(entries)
+}
+
+func buildGovernanceReport(entries []NodeGovernance) *GovernanceReport {
+	report := &GovernanceReport{
+		Nodes:                      make([]NodeGovernance, 0),
+		IncompleteOwnership:        make([]string, 0),
+		MissingGovernanceRoles:     make([]string, 0),
+		MissingClassification:      make([]string, 0),
+		MissingSchema:              make([]string, 0),
+		DeprecatedWithoutSuccessor: make([]string, 0),
+		all:                        entries,
+	}
+	for _, n := range entries {
+		if n.Status == NodeStatusActive {
+			report.ActiveNodes++
+		}
+		if !n.HasGaps() {
+			continue
+		}
+		report.Nodes = append(report.Nodes, n)
+		if !n.OwnershipComplete {
+			report.IncompleteOwnership = append(report.IncompleteOwnership, n.Path)
+		}
+		if !n.HasGovernanceRoles {
+			report.MissingGovernanceRoles = append(report.MissingGovernanceRoles, n.Path)
+		}
+		if n.MissingClassification {
+			report.MissingClassification = append(report.MissingClassification, n.Path)
+		}
+		if n.MissingSchema {
+			report.MissingSchema = append(report.MissingSchema, n.Path)
+		}
+		if n.MissingSuccessor {
+			report.DeprecatedWithoutSuccessor = append(report.DeprecatedWithoutSuccessor, n.Path)
+		}
+	}
+	return report
+}
+
+// resolveDomain returns the domain of the nearest node (self first) that sets one
+func (s *snapshot) resolveDomain(path string) string {
+	paths := append(ancestorPaths(path), path)
+	for i := len(paths) - 1; i >= 0; i-- {
+		if node, ok := s.nodes[paths[i]]; ok && node.Domain != nil {
+			return *node.Domain
+		}
+	}
+	return ""
+}
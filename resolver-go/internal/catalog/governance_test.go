@@ -0,0 +1,83 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func governanceFixture() *Registry {
+	r := NewRegistry()
+
+	markets := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	markets.Domain = strPtr("markets")
+	markets.Classification = "internal"
+	markets.Ownership = &Ownership{
+		AccountableOwner: strPtr("owner"),
+		DataSpecialist:   strPtr("specialist"),
+		SupportChannel:   strPtr("#prices"),
+		ADOP:             strPtr("adop"),
+	}
+	r.Register(markets)
+
+	equity := makeNode("prices/equity", "Equity", "", NodeStatusActive, true)
+	equity.SourceBinding = &SourceBinding{SourceType: SourceTypeSnowflake}
+	r.Register(equity)
+
+	schema := makeNode("prices/fx", "FX", "", NodeStatusActive, true)
+	schema.SourceBinding = &SourceBinding{SourceType: SourceTypeOracle}
+	schema.DataSchema = &DataSchema{}
+	r.Register(schema)
+
+	risk := makeNode("risk", "Risk", "", NodeStatusActive, false)
+	risk.Domain = strPtr("risk")
+	r.Register(risk)
+
+	r.Register(makeNode("risk/old", "Old", "", NodeStatusDeprecated, true))
+	r.Register(makeNode("risk/draft", "Draft", "", NodeStatusDraft, true))
+
+	return r
+}
+
+func TestGovernanceReport(t *testing.T) {
+	report := governanceFixture().GovernanceReport()
+
+	if report.ActiveNodes != 4 {
+		t.Errorf("expected 4 active nodes, got %d", report.ActiveNodes)
+	}
+	assertPaths(t, "incomplete_ownership", report.IncompleteOwnership, "risk")
+	assertPaths(t, "missing_governance_roles", report.MissingGovernanceRoles, "risk")
+	assertPaths(t, "missing_classification", report.MissingClassification, "risk")
+	assertPaths(t, "missing_schema", report.MissingSchema, "prices/equity")
+	assertPaths(t, "deprecated_without_successor", report.DeprecatedWithoutSuccessor, "risk/old")
+
+	for _, n := range report.Nodes {
+		if n.Path == "risk/draft" {
+			t.Error("draft nodes should not be reported")
+		}
+	}
+}
+
+func TestGovernanceReportFilterDomain(t *testing.T) {
+	report := governanceFixture().GovernanceReport().FilterDomain("markets")
+
+	if report.ActiveNodes != 3 {
+		t.Errorf("expected 3 active nodes in markets, got %d", report.ActiveNodes)
+	}
+	assertPaths(t, "missing_schema", report.MissingSchema, "prices/equity")
+	if len(report.IncompleteOwnership) != 0 || len(report.DeprecatedWithoutSuccessor) != 0 {
+		t.Errorf("risk nodes leaked into markets report: %+v", report)
+	}
+}
+
+func assertPaths(t *testing.T, name string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: expected %v, got %v", name, want, got)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+			return
+		}
+	}
+}
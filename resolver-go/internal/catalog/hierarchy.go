@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// HierarchyIssue describes a node whose place in the tree looks wrong
+type HierarchyIssue struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// HierarchyIssueDanglingParent marks a node with no owned ancestor in the catalog
+const HierarchyIssueDanglingParent = "dangling_parent"
+
+// ValidateHierarchy reports nodes below the top level whose parent chain has
+// no registered ancestor with ownership. Such nodes usually sit under a parent
+// path that was never defined, leaving a hole in the tree. Issues are warnings;
+// callers in strict mode may treat them as errors.
+func (r *Registry) ValidateHierarchy() []HierarchyIssue {
+	snap := r.load()
+
+	issues := make([]HierarchyIssue, 0)
+	for p, node := range snap.nodes {
+		ancestors := ancestorPaths(p)
+		if len(ancestors) == 0 {
+			continue
+		}
+
+		owned := false
+		for _, a := range ancestors {
+			if parent, ok := snap.nodes[a]; ok && parent.Ownership != nil {
+				owned = true
+				break
+			}
+		}
+		if owned {
+			continue
+		}
+
+		parent := ancestors[len(ancestors)-1]
+		msg := fmt.Sprintf("'%s' has no registered ancestor with ownership (parent '%s' is %s)", p, parent, parentState(snap, parent))
+		if loc := sourceLocation(node); loc != "" {
+			msg = loc + ": " + msg
+		}
+		issues = append(issues, HierarchyIssue{
+			Kind:     HierarchyIssueDanglingParent,
+			Severity: SeverityWarning,
+			Path:     p,
+			File:     node.SourceFile,
+			Line:     node.SourceLine,
+			Message:  msg,
+		})
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
+
+func parentState(s *snapshot, parent string) string {
+	if _, ok := s.nodes[parent]; ok {
+		return "defined without ownership"
+	}
+	return "not defined"
+}
+
+// sourceLocation renders where a node was defined as file:line, or whatever
+// part of that is known. It is empty for nodes not loaded from a file.
+func sourceLocation(node *CatalogNode) string {
+	switch {
+	case node.SourceFile != "" && node.SourceLine > 0:
+		return fmt.Sprintf("%s:%d", node.SourceFile, node.SourceLine)
+	case node.SourceFile != "":
+		return node.SourceFile
+	case node.SourceLine > 0:
+		return fmt.Sprintf("line %d", node.SourceLine)
+	default:
+		return ""
+	}
+}
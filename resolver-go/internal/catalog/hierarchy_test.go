@@ -0,0 +1,36 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateHierarchyDanglingParent(t *testing.T) {
+	reg := NewRegistry()
+	owned := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	owned.Ownership = &Ownership{AccountableOwner: strPtr("team-prices")}
+	reg.Register(owned)
+	reg.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+	reg.Register(makeNode("risk", "Risk", "", NodeStatusActive, false))
+
+	orphan := makeNode("risk/var/daily", "Daily VaR", "", NodeStatusActive, true)
+	orphan.SourceFile = "risk.yaml"
+	orphan.SourceLine = 12
+	reg.Register(orphan)
+	reg.Register(makeNode("rates/swaps", "Swaps", "", NodeStatusActive, true))
+
+	issues := reg.ValidateHierarchy()
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %v", issues)
+	}
+	if issues[0].Path != "rates/swaps" || !strings.Contains(issues[0].Message, "parent 'rates' is not defined") {
+		t.Errorf("unexpected first issue: %+v", issues[0])
+	}
+	risk := issues[1]
+	if risk.Path != "risk/var/daily" || risk.File != "risk.yaml" || risk.Line != 12 {
+		t.Errorf("expected file and line on issue, got %+v", risk)
+	}
+	if !strings.HasPrefix(risk.Message, "risk.yaml:12: ") || risk.Severity != SeverityWarning {
+		t.Errorf("unexpected message: %q", risk.Message)
+	}
+}
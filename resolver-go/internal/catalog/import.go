@@ -0,0 +1,38 @@
+package catalog
+
+// ImportResult is the outcome of Registry.Import
+type ImportResult struct {
+	Validation *LintReport  `json:"validation"`
+	Diff       *CatalogDiff `json:"diff"`
+	Digest     string       `json:"digest"` // Checksum of the imported catalog
+	Applied    bool         `json:"applied"`
+}
+
+// Import validates nodes with Lint as a replacement for the whole catalog and
+// compares them with the live one. Unless dryRun is set or validation found
+// errors, the nodes are then swapped in as AtomicReplaceVersion does, with
+// their checksum as version: per-node audit entries are attributed to actor,
+// and a "catalog_imported" entry records the old and new digest. The diff of
+// an applied import is taken under the same lock as the swap, so concurrent
+// imports and edits serialize and each result describes the change it made.
+// An import that is not applied changes nothing.
+//
+// A later reload only replaces the imported catalog when the catalog files
+// change, since reloads skip files whose checksum has not moved.
+func (r *Registry) Import(nodes []*CatalogNode, actor string, dryRun bool) *ImportResult {
+	result := &ImportResult{Validation: Lint(nodes), Digest: catalogChecksum(nodes)}
+	if dryRun || !result.Validation.Valid {
+		result.Diff = r.Diff(nodes)
+		return result
+	}
+
+	result.Diff = r.replace(nodes, result.Digest, actor, "catalog import", func(oldVersion string, diff *CatalogDiff) {
+		var old *string
+		if oldVersion != "" {
+			old = &oldVersion
+		}
+		r.appendAudit(newAuditEntry("", "catalog_imported", actor, old, &result.Digest, strPtrOf(diff.Summary())))
+	})
+	result.Applied = true
+	return result
+}
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestImportAppliesOnlyValidCatalogs(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	reg.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+	reg.Register(makeNode("prices/legacy", "Legacy", "", NodeStatusDeprecated, true))
+	reg.SetVersion("v1")
+
+	invalid, err := ParseCatalog([]byte("prices:\n  dispay_name: Prices\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := reg.Import(invalid, "alice", false)
+	if result.Applied || result.Validation.Valid || len(result.Diff.Removed) != 2 {
+		t.Fatalf("expected an unapplied import with errors and a diff, got %+v", result)
+	}
+	if !reg.Exists("prices/equity") || reg.Version() != "v1" {
+		t.Fatal("a failed import changed the live catalog")
+	}
+
+	replacement := []*CatalogNode{reg.Get("prices"), reg.Get("prices/equity"), makeNode("rates", "Rates", "", NodeStatusActive, false)}
+	if result := reg.Import(replacement, "alice", true); result.Applied || reg.Exists("rates") {
+		t.Fatal("a dry run changed the live catalog")
+	}
+	result = reg.Import(replacement, "alice", false)
+	if !result.Applied || !reg.Exists("rates") || reg.Exists("prices/legacy") || reg.Version() != result.Digest {
+		t.Fatalf("expected the import applied with its digest as version, got %+v", result)
+	}
+
+	entry := reg.AuditEntries("", 1, nil)[0]
+	if entry.Action != "catalog_imported" || entry.Actor != "alice" || *entry.OldValue != "v1" || *entry.NewValue != result.Digest {
+		t.Errorf("unexpected import audit entry %+v", entry)
+	}
+	if removed := reg.AuditEntries("prices/legacy", 1, nil)[0]; removed.Action != "removed" || removed.Actor != "alice" {
+		t.Errorf("expected the removal attributed to alice, got %+v", removed)
+	}
+}
+
+func TestImportSerializesConcurrentImports(t *testing.T) {
+	reg := NewRegistry()
+	reg.SetVersion("v0")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			node := makeNode(fmt.Sprintf("import%d", i), "Imported", "", NodeStatusActive, false)
+			if result := reg.Import([]*CatalogNode{node}, "alice", false); !result.Applied {
+				t.Errorf("import %d not applied: %+v", i, result.Validation.Issues)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Each import replaced the catalog the previous one left, so the digests
+	// form one chain from v0 to the version served
+	next := make(map[string]string)
+	for _, e := range reg.AuditEntries("", 0, nil) {
+		if e.Action == "catalog_imported" {
+			next[*e.OldValue] = *e.NewValue
+		}
+	}
+	version, steps := "v0", 0
+	for next[version] != "" {
+		version, steps = next[version], steps+1
+	}
+	if steps != 20 || version != reg.Version() || len(reg.AllPaths()) != 1 {
+		t.Errorf("expected a chain of 20 imports ending at the live version, got %d steps to %s", steps, version)
+	}
+}
@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestResolveClassificationInherits(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("risk", "Risk", "", NodeStatusActive, false)
+	root.Classification = "restricted"
+	r.Register(root)
+	r.Register(makeNode("risk/var", "VaR", "", NodeStatusActive, true))
+
+	value, definedAt := r.ResolveClassification("risk/var")
+	if value != "restricted" || definedAt != "risk" {
+		t.Errorf("expected restricted from 'risk', got %q from %q", value, definedAt)
+	}
+}
+
+func TestResolveClassificationChildOverrides(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("risk", "Risk", "", NodeStatusActive, false)
+	root.Classification = "restricted"
+	r.Register(root)
+	child := makeNode("risk/public", "Public", "", NodeStatusActive, true)
+	child.Classification = "public"
+	r.Register(child)
+
+	value, definedAt := r.ResolveClassification("risk/public")
+	if value != "public" || definedAt != "risk/public" {
+		t.Errorf("expected public from 'risk/public', got %q from %q", value, definedAt)
+	}
+}
+
+func TestResolveClassificationDefault(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+
+	value, definedAt := r.ResolveClassification("prices/equity")
+	if value != DefaultClassification || definedAt != "" {
+		t.Errorf("expected default classification, got %q from %q", value, definedAt)
+	}
+}
+
+func TestResolveTagsMerges(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.Tags = []string{"market-data", "eod"}
+	r.Register(root)
+	leaf := makeNode("prices/equity", "Equity", "", NodeStatusActive, true)
+	leaf.Tags = []string{"equities", "eod"}
+	r.Register(leaf)
+
+	tags := r.ResolveTags("prices/equity")
+	got := make(map[string]string)
+	for _, t := range tags {
+		got[t.Tag] = t.DefinedAt
+	}
+	if len(tags) != 3 {
+		t.Fatalf("expected 3 merged tags, got %v", tags)
+	}
+	if got["market-data"] != "prices" {
+		t.Errorf("expected market-data from 'prices', got %q", got["market-data"])
+	}
+	if got["eod"] != "prices/equity" {
+		t.Errorf("expected eod to report nearest definition, got %q", got["eod"])
+	}
+}
+
+func TestSearchIncludeInheritedTags(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.Tags = []string{"golden-source"}
+	r.Register(root)
+	r.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+
+	own := r.SearchWithOptions("golden", SearchOptions{Limit: 10})
+	if len(own) != 1 {
+		t.Errorf("expected only the tagged node without inheritance, got %d", len(own))
+	}
+
+	inherited := r.SearchWithOptions("golden", SearchOptions{Limit: 10, IncludeInheritedTags: true})
+	if len(inherited) != 2 {
+		t.Errorf("expected 2 nodes with inherited tags, got %d", len(inherited))
+	}
+}
@@ -0,0 +1,124 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NodeRow is a node flattened for spreadsheets: its own fields with the
+// ownership, domain and classification it resolves to through its
+// ancestors. Tags are the node's own.
+type NodeRow struct {
+	Path             string   `json:"path"`
+	DisplayName      string   `json:"display_name"`
+	Status           string   `json:"status"`
+	SourceType       string   `json:"source_type,omitempty"`
+	Domain           string   `json:"domain,omitempty"`
+	Classification   string   `json:"classification,omitempty"`
+	AccountableOwner string   `json:"accountable_owner,omitempty"`
+	DataSpecialist   string   `json:"data_specialist,omitempty"`
+	SupportChannel   string   `json:"support_channel,omitempty"`
+	ADOP             string   `json:"adop,omitempty"`
+	ADS              string   `json:"ads,omitempty"`
+	ADAL             string   `json:"adal,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	IsLeaf           bool     `json:"is_leaf"`
+	Description      string   `json:"description,omitempty"`
+}
+
+// NodeRowColumns are the CSV columns of WriteNodeRowsCSV. tags are joined
+// with ";".
+var NodeRowColumns = []string{
+	"path", "display_name", "status", "source_type", "domain", "classification",
+	"accountable_owner", "data_specialist", "support_channel", "adop", "ads", "adal",
+	"tags", "is_leaf", "description",
+}
+
+// NodeRows returns the rows of the nodes at paths, in that order, skipping
+// paths that are not registered
+func (r *Registry) NodeRows(paths []string) []NodeRow {
+	snap := r.load()
+	rows := make([]NodeRow, 0, len(paths))
+	for _, p := range paths {
+		node, ok := snap.nodes[p]
+		if !ok {
+			continue
+		}
+		row := NodeRow{
+			Path:        p,
+			DisplayName: node.DisplayName,
+			Status:      string(node.Status),
+			Domain:      snap.resolveDomain(p),
+			Tags:        node.Tags,
+			IsLeaf:      node.IsLeaf,
+			Description: node.Description,
+		}
+		row.Classification, _ = snap.resolveClassification(p)
+		if node.SourceBinding != nil {
+			row.SourceType = string(node.SourceBinding.SourceType)
+		}
+		ownership := snap.resolveOwnership(p)
+		for _, f := range []struct {
+			dst *string
+			src *string
+		}{
+			{&row.AccountableOwner, ownership.AccountableOwner},
+			{&row.DataSpecialist, ownership.DataSpecialist},
+			{&row.SupportChannel, ownership.SupportChannel},
+			{&row.ADOP, ownership.ADOP},
+			{&row.ADS, ownership.ADS},
+			{&row.ADAL, ownership.ADAL},
+		} {
+			if f.src != nil {
+				*f.dst = *f.src
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteNodeRowsCSV writes rows as CSV with a header row of NodeRowColumns
+func WriteNodeRowsCSV(w io.Writer, rows []NodeRow) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(NodeRowColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{row.Path, row.DisplayName, row.Status, row.SourceType, row.Domain, row.Classification,
+			row.AccountableOwner, row.DataSpecialist, row.SupportChannel, row.ADOP, row.ADS, row.ADAL,
+			strings.Join(row.Tags, ";"), strconv.FormatBool(row.IsLeaf), row.Description}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// governanceColumns are the CSV columns of WriteGovernanceCSV
+var governanceColumns = []string{
+	"path", "status", "domain", "ownership_complete", "has_governance_roles",
+	"missing_classification", "missing_schema", "missing_successor",
+}
+
+// WriteGovernanceCSV writes the nodes of a governance report as CSV with a
+// header row
+func WriteGovernanceCSV(w io.Writer, nodes []NodeGovernance) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(governanceColumns); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		record := []string{n.Path, string(n.Status), n.Domain, strconv.FormatBool(n.OwnershipComplete),
+			strconv.FormatBool(n.HasGovernanceRoles), strconv.FormatBool(n.MissingClassification),
+			strconv.FormatBool(n.MissingSchema), strconv.FormatBool(n.MissingSuccessor)}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestNodeRowsFlattenInheritedFields(t *testing.T) {
+	r := NewRegistry()
+	prices := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	prices.Domain = strPtr("market-data")
+	prices.Classification = "internal"
+	prices.Ownership = &Ownership{AccountableOwner: strPtr("team-prices"), ADS: strPtr("ads-prices")}
+	r.Register(prices)
+	equity := makeNode("prices/equity", "Equity", "Close, open and \"adjusted\" prices\nper listing", NodeStatusActive, true)
+	equity.SourceBinding = &SourceBinding{SourceType: SourceTypeSnowflake}
+	equity.Tags = []string{"equities", "eod"}
+	r.Register(equity)
+
+	rows := r.NodeRows([]string{"prices/equity", "prices/missing", "prices"})
+	if len(rows) != 2 || rows[0].Path != "prices/equity" || rows[1].Path != "prices" {
+		t.Fatalf("expected the registered paths in order, got %+v", rows)
+	}
+	row := rows[0]
+	if row.Domain != "market-data" || row.Classification != "internal" || row.AccountableOwner != "team-prices" ||
+		row.ADS != "ads-prices" || row.SourceType != string(SourceTypeSnowflake) || !row.IsLeaf {
+		t.Errorf("expected inherited fields, got %+v", row)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNodeRowsCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	// Commas, quotes and newlines in descriptions survive a round trip
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV does not parse: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != len(NodeRowColumns) {
+		t.Fatalf("unexpected records %q", records)
+	}
+	column := func(name string) int {
+		for i, c := range NodeRowColumns {
+			if c == name {
+				return i
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return -1
+	}
+	if got := records[1][column("description")]; got != equity.Description {
+		t.Errorf("description did not round trip: %q", got)
+	}
+	if got := records[1][column("tags")]; got != "equities;eod" {
+		t.Errorf("expected tags joined with ';', got %q", got)
+	}
+	if got := records[2][column("is_leaf")]; got != "false" {
+		t.Errorf("expected is_leaf false, got %q", got)
+	}
+}
+
+func TestWriteGovernanceCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteGovernanceCSV(&buf, []NodeGovernance{
+		{Path: "prices/equity", Status: NodeStatusActive, Domain: "market-data", HasGovernanceRoles: true, MissingSchema: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "path,status,domain,ownership_complete,has_governance_roles,missing_classification,missing_schema,missing_successor\n" +
+		"prices/equity,active,market-data,false,true,false,true,false\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,275 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// LineageKind is the relationship a lineage edge comes from
+type LineageKind string
+
+const (
+	LineageUpstreamDependency LineageKind = "upstream_dependency" // freshness.upstream_dependencies
+	LineageRelatedMoniker     LineageKind = "related_moniker"     // schema.related_monikers
+	LineageForeignKey         LineageKind = "foreign_key"         // schema.columns[].foreign_key
+	LineageInput              LineageKind = "input"               // Inputs of a derived or composite binding
+)
+
+// Depths of a lineage graph
+const (
+	DefaultLineageDepth = 1
+	MaxLineageDepth     = 10
+)
+
+// LineageEdge says that From depends on To, through the reference in Field
+type LineageEdge struct {
+	From      string      `json:"from"`
+	To        string      `json:"to"` // Canonical path of the reference, or the reference when it does not parse
+	Kind      LineageKind `json:"kind"`
+	Field     string      `json:"field"`     // e.g. schema.columns[cusip].foreign_key
+	Reference string      `json:"reference"` // As written in the catalog
+	Depth     int         `json:"depth"`     // Hops from the path the graph is of
+
+	// Broken is set when the reference does not parse (Unparseable), or
+	// neither its path nor an ancestor of it is in the catalog
+	Broken      bool `json:"broken,omitempty"`
+	Unparseable bool `json:"unparseable,omitempty"`
+}
+
+// LineageNode is a path of a lineage graph
+type LineageNode struct {
+	Path    string     `json:"path"`
+	Status  NodeStatus `json:"status,omitempty"`
+	Missing bool       `json:"missing,omitempty"` // Neither the path nor an ancestor is in the catalog
+}
+
+// LineageGraph is the lineage of a path: the edges to what it depends on
+// (upstream) and from what depends on it (downstream), followed Depth hops
+// each way. Cycles lists each cycle among the edges once, as a path that
+// starts and ends at the same node.
+type LineageGraph struct {
+	Path       string        `json:"path"`
+	Depth      int           `json:"depth"`
+	Nodes      []LineageNode `json:"nodes"`
+	Upstream   []LineageEdge `json:"upstream"`
+	Downstream []LineageEdge `json:"downstream"`
+	Cycles     [][]string    `json:"cycles,omitempty"`
+}
+
+// lineageRefs returns the references node makes to other monikers, as edges
+// from it
+func lineageRefs(node *CatalogNode) []LineageEdge {
+	if node == nil {
+		return nil
+	}
+	var edges []LineageEdge
+	add := func(kind LineageKind, field, ref string) {
+		edge := LineageEdge{From: node.Path, To: ref, Kind: kind, Field: field, Reference: ref}
+		if m, err := moniker.Parse(ref, true); err == nil {
+			edge.To = m.CanonicalPath()
+		} else {
+			edge.Broken, edge.Unparseable = true, true
+		}
+		edges = append(edges, edge)
+	}
+
+	if node.Freshness != nil {
+		for i, ref := range node.Freshness.UpstreamDependencies {
+			add(LineageUpstreamDependency, fmt.Sprintf("freshness.upstream_dependencies[%d]", i), ref)
+		}
+	}
+	if schema := node.DataSchema; schema != nil {
+		for i, ref := range schema.RelatedMonikers {
+			add(LineageRelatedMoniker, fmt.Sprintf("schema.related_monikers[%d]", i), ref)
+		}
+		for _, col := range schema.Columns {
+			if col.ForeignKey != nil && *col.ForeignKey != "" {
+				add(LineageForeignKey, fmt.Sprintf("schema.columns[%s].foreign_key", col.Name), *col.ForeignKey)
+			}
+		}
+	}
+	if sb := node.SourceBinding; sb != nil && (sb.SourceType == SourceTypeDerived || sb.SourceType == SourceTypeComposite) {
+		inputs := DerivedInputs(sb.Config)
+		names := make([]string, 0, len(inputs))
+		for name := range inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			add(LineageInput, fmt.Sprintf("source_binding.config.%s.%s", DerivedInputsKey, name), inputs[name])
+		}
+	}
+	return edges
+}
+
+// lineageTargets returns the paths node references
+func lineageTargets(node *CatalogNode) map[string]bool {
+	targets := make(map[string]bool)
+	for _, edge := range lineageRefs(node) {
+		if !edge.Broken {
+			targets[edge.To] = true
+		}
+	}
+	return targets
+}
+
+// Lineage returns the lineage graph of path, depth hops each way (clamped
+// to 1..MaxLineageDepth). Downstream edges come from the index of
+// references the registry keeps as nodes are registered.
+func (r *Registry) Lineage(path string, depth int) *LineageGraph {
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > MaxLineageDepth {
+		depth = MaxLineageDepth
+	}
+	snap := r.load()
+	graph := &LineageGraph{
+		Path:       path,
+		Depth:      depth,
+		Upstream:   make([]LineageEdge, 0),
+		Downstream: make([]LineageEdge, 0),
+	}
+
+	// Upstream: follow each node's own references
+	seen := map[string]bool{path: true}
+	frontier := []string{path}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []string
+		for _, p := range frontier {
+			for _, edge := range lineageRefs(snap.nodes[p]) {
+				edge.Depth = d
+				edge.Broken = edge.Broken || snap.registeredOrAncestor(edge.To) == nil
+				graph.Upstream = append(graph.Upstream, edge)
+				if !edge.Broken && !seen[edge.To] {
+					seen[edge.To] = true
+					next = append(next, edge.To)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// Downstream: follow the reverse index
+	seenDown := map[string]bool{path: true}
+	frontier = []string{path}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var next []string
+		for _, p := range frontier {
+			for _, from := range sortedKeys(snap.downstream[p]) {
+				for _, edge := range lineageRefs(snap.nodes[from]) {
+					if edge.Broken || edge.To != p {
+						continue
+					}
+					edge.Depth = d
+					graph.Downstream = append(graph.Downstream, edge)
+				}
+				if !seenDown[from] {
+					seenDown[from] = true
+					next = append(next, from)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	paths := make(map[string]bool)
+	for p := range seen {
+		paths[p] = true
+	}
+	for p := range seenDown {
+		paths[p] = true
+	}
+	for _, edge := range graph.Upstream {
+		if edge.Broken && !edge.Unparseable {
+			paths[edge.To] = true
+		}
+	}
+	for _, p := range sortedKeys(paths) {
+		node := LineageNode{Path: p}
+		if n := snap.nodes[p]; n != nil {
+			node.Status = n.Status
+		} else if snap.registeredOrAncestor(p) == nil {
+			node.Missing = true
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	graph.Cycles = lineageCycles(append(append([]LineageEdge(nil), graph.Upstream...), graph.Downstream...))
+	return graph
+}
+
+// registeredOrAncestor returns the node at path, or at its nearest
+// registered ancestor, or nil
+func (s *snapshot) registeredOrAncestor(path string) *CatalogNode {
+	if node, ok := s.nodes[path]; ok {
+		return node
+	}
+	ancestors := ancestorPaths(path)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if node, ok := s.nodes[ancestors[i]]; ok {
+			return node
+		}
+	}
+	return nil
+}
+
+// lineageCycles finds the cycles among edges, each once
+func lineageCycles(edges []LineageEdge) [][]string {
+	adjacent := make(map[string]map[string]bool)
+	for _, edge := range edges {
+		if edge.Broken {
+			continue
+		}
+		if adjacent[edge.From] == nil {
+			adjacent[edge.From] = make(map[string]bool)
+		}
+		adjacent[edge.From][edge.To] = true
+	}
+
+	cycles := make([][]string, 0)
+	found := make(map[string]bool)
+	done := make(map[string]bool)
+	var stack []string
+	onStack := make(map[string]int)
+	var visit func(p string)
+	visit = func(p string) {
+		onStack[p] = len(stack)
+		stack = append(stack, p)
+		for _, next := range sortedKeys(adjacent[p]) {
+			if i, ok := onStack[next]; ok {
+				cycle := append(append([]string(nil), stack[i:]...), next)
+				if key := cycleKey(cycle); !found[key] {
+					found[key] = true
+					cycles = append(cycles, cycle)
+				}
+			} else if !done[next] {
+				visit(next)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		delete(onStack, p)
+		done[p] = true
+	}
+	for _, p := range sortedKeys(adjacent) {
+		if !done[p] {
+			visit(p)
+		}
+	}
+	if len(cycles) == 0 {
+		return nil
+	}
+	return cycles
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
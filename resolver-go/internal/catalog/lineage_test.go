@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func makeLineageRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(makeNode("reference", "Reference", "", NodeStatusActive, false))
+	r.Register(makeNode("reference/security", "Security", "", NodeStatusActive, true))
+
+	holdings := makeSchemaNode("holdings", NodeStatusActive,
+		map[string]string{"security_id": "reference/security"}, "risk/exposure")
+	holdings.Freshness = &Freshness{UpstreamDependencies: []string{"feeds/custody"}}
+	r.Register(holdings)
+
+	r.Register(makeDerivedNode("risk/exposure", map[string]interface{}{"h": "holdings"}))
+	return r
+}
+
+func makeDerivedNode(path string, inputs map[string]interface{}) *CatalogNode {
+	node := makeNode(path, path, "", NodeStatusActive, true)
+	node.SourceBinding = &SourceBinding{
+		SourceType: SourceTypeDerived,
+		Config:     map[string]interface{}{DerivedInputsKey: inputs, DerivedExpressionKey: "h.value"},
+	}
+	return node
+}
+
+func edgeTargets(edges []LineageEdge) map[LineageKind]string {
+	targets := make(map[LineageKind]string)
+	for _, edge := range edges {
+		targets[edge.Kind] = edge.To
+	}
+	return targets
+}
+
+func TestLineageEdgesAndMissingNodes(t *testing.T) {
+	graph := makeLineageRegistry().Lineage("holdings", 1)
+
+	want := map[LineageKind]string{
+		LineageUpstreamDependency: "feeds/custody",
+		LineageRelatedMoniker:     "risk/exposure",
+		LineageForeignKey:         "reference/security",
+	}
+	if got := edgeTargets(graph.Upstream); !reflect.DeepEqual(got, want) {
+		t.Errorf("upstream = %v, want %v", got, want)
+	}
+	for _, edge := range graph.Upstream {
+		if broken := edge.To == "feeds/custody"; edge.Broken != broken {
+			t.Errorf("edge to %s: broken = %v, want %v", edge.To, edge.Broken, broken)
+		}
+		if edge.Kind == LineageForeignKey && edge.Field != "schema.columns[security_id].foreign_key" {
+			t.Errorf("unexpected field %q", edge.Field)
+		}
+	}
+
+	if len(graph.Downstream) != 1 {
+		t.Fatalf("expected 1 downstream edge, got %v", graph.Downstream)
+	}
+	down := graph.Downstream[0]
+	if down.From != "risk/exposure" || down.Kind != LineageInput || down.Field != "source_binding.config.inputs.h" {
+		t.Errorf("unexpected downstream edge %+v", down)
+	}
+
+	missing := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		missing[node.Path] = node.Missing
+	}
+	if !missing["feeds/custody"] || missing["reference/security"] || missing["holdings"] {
+		t.Errorf("unexpected missing nodes %v", missing)
+	}
+
+	wantCycles := [][]string{{"holdings", "risk/exposure", "holdings"}}
+	if !reflect.DeepEqual(graph.Cycles, wantCycles) {
+		t.Errorf("cycles = %v, want %v", graph.Cycles, wantCycles)
+	}
+}
+
+func TestLineageDepth(t *testing.T) {
+	r := makeLineageRegistry()
+
+	if graph := r.Lineage("reference/security", 1); len(graph.Downstream) != 1 {
+		t.Errorf("depth 1: expected 1 downstream edge, got %v", graph.Downstream)
+	}
+
+	graph := r.Lineage("reference/security", 2)
+	depths := make(map[string]int)
+	for _, edge := range graph.Downstream {
+		depths[edge.From] = edge.Depth
+	}
+	if depths["holdings"] != 1 || depths["risk/exposure"] != 2 {
+		t.Errorf("unexpected downstream depths %v", depths)
+	}
+
+	if graph := r.Lineage("holdings", MaxLineageDepth+5); graph.Depth != MaxLineageDepth {
+		t.Errorf("depth = %d, want it capped at %d", graph.Depth, MaxLineageDepth)
+	}
+}
+
+func TestLineageIndexFollowsChanges(t *testing.T) {
+	r := makeLineageRegistry()
+
+	// Dropping the input removes the downstream edge
+	r.Register(makeDerivedNode("risk/exposure", map[string]interface{}{"s": "reference/security"}))
+	if graph := r.Lineage("holdings", 1); len(graph.Downstream) != 0 || graph.Cycles != nil {
+		t.Errorf("expected no downstream edges or cycles, got %v %v", graph.Downstream, graph.Cycles)
+	}
+	if graph := r.Lineage("reference/security", 1); len(graph.Downstream) != 2 {
+		t.Errorf("expected 2 downstream edges, got %v", graph.Downstream)
+	}
+
+	// Deregistering a node removes its edges
+	r.Deregister("holdings")
+	graph := r.Lineage("reference/security", 1)
+	if len(graph.Downstream) != 1 || graph.Downstream[0].From != "risk/exposure" {
+		t.Errorf("expected only risk/exposure downstream, got %v", graph.Downstream)
+	}
+
+	// A reload rebuilds the index
+	r.AtomicReplace(makeLineageRegistry().AllNodes())
+	if graph := r.Lineage("holdings", 1); len(graph.Downstream) != 1 {
+		t.Errorf("expected 1 downstream edge after reload, got %v", graph.Downstream)
+	}
+}
+
+func TestLineageUnparseableReference(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeSchemaNode("holdings", NodeStatusActive, nil, "bad segment!"))
+
+	graph := r.Lineage("holdings", 1)
+	if len(graph.Upstream) != 1 || !graph.Upstream[0].Broken || graph.Upstream[0].To != "bad segment!" {
+		t.Errorf("expected one broken edge, got %v", graph.Upstream)
+	}
+	if len(graph.Nodes) != 1 {
+		t.Errorf("expected only the path itself as a node, got %v", graph.Nodes)
+	}
+}
@@ -0,0 +1,210 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Lint checks, one per validator feeding the report
+const (
+	LintCheckLoad           = "load"            // Catalog failed to load at all
+	LintCheckUnknownField   = "unknown_field"   // Key that matches no catalog field
+	LintCheckSourceConfig   = "source_config"   // Binding config does not match its source type
+	LintCheckBlockedPattern = "blocked_pattern" // access_policy.blocked_patterns entry is not a valid regex
+	LintCheckStatus         = "status"          // Status is not a known lifecycle state
+	LintCheckSuccessor      = "successor"       // Successor cycle, dangling or archived successor
+	LintCheckReference      = "reference"       // Broken or suspicious foreign key / related moniker
+	LintCheckSunset         = "sunset"          // Unparseable sunset deadline
+	LintCheckHierarchy      = "hierarchy"       // Node with no owned ancestor
+	LintCheckOwnership      = "ownership"       // Leaf with no accountable owner after inheritance
+)
+
+// LintIssue is a single finding in a LintReport
+type LintIssue struct {
+	Check    string `json:"check"`
+	Kind     string `json:"kind,omitempty"` // Check-specific classification, e.g. cycle or broken
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintReport aggregates every catalog check. The per-check lists keep the
+// detail of the underlying validators; Issues is the flat, sorted view.
+type LintReport struct {
+	Valid    bool        `json:"valid"` // No issue has error severity
+	Count    int         `json:"count"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+	Issues   []LintIssue `json:"issues"`
+
+	UnknownFields   []UnknownField          `json:"unknown_fields"`
+	SourceConfig    []SourceConfigViolation `json:"source_config_issues"`
+	SuccessorIssues []SuccessorIssue        `json:"successor_issues"`
+	ReferenceIssues []ReferenceIssue        `json:"reference_issues"`
+	SunsetIssues    []SunsetIssue           `json:"sunset_issues"`
+	HierarchyIssues []HierarchyIssue        `json:"hierarchy_issues"`
+}
+
+// Lint runs every catalog check against nodes. Unknown fields, invalid
+// binding config, invalid blocked patterns, unknown statuses, successor
+// problems, broken references and bad sunset deadlines are errors; hierarchy
+// holes, leaves without an accountable owner and reference warnings are
+// warnings.
+func Lint(nodes []*CatalogNode) *LintReport {
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+	snap := reg.load()
+
+	report := newLintReport()
+	add := func(issue LintIssue) {
+		if node, ok := snap.nodes[issue.Path]; ok && issue.File == "" {
+			issue.File, issue.Line = node.SourceFile, node.SourceLine
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	report.UnknownFields = UnknownFields(nodes)
+	for _, u := range report.UnknownFields {
+		add(LintIssue{Check: LintCheckUnknownField, Severity: SeverityError, Path: u.Path, File: u.File, Line: u.Line, Message: u.String()})
+	}
+
+	paths := reg.AllPaths()
+	sort.Strings(paths)
+	for _, p := range paths {
+		node := snap.nodes[p]
+		if node.SourceBinding != nil {
+			for _, v := range checkSourceConfig(p, node.SourceBinding) {
+				report.SourceConfig = append(report.SourceConfig, v)
+				add(LintIssue{Check: LintCheckSourceConfig, Severity: SeverityError, Path: p, Message: v.Message})
+			}
+		}
+		if node.AccessPolicy != nil {
+			if _, err := compileBlockedPatterns(node.AccessPolicy.BlockedPatterns); err != nil {
+				add(LintIssue{Check: LintCheckBlockedPattern, Severity: SeverityError, Path: p,
+					Message: fmt.Sprintf("%s: access_policy.%v", p, err)})
+			}
+		}
+		if node.Status != "" && !node.Status.IsValid() {
+			add(LintIssue{Check: LintCheckStatus, Kind: "invalid_status", Severity: SeverityError, Path: p,
+				Message: fmt.Sprintf("Status of '%s' is not a known lifecycle state: %q", p, node.Status)})
+		}
+		if node.IsLeaf && node.Status != NodeStatusArchived && reg.ResolveOwnership(p).AccountableOwner == nil {
+			add(LintIssue{Check: LintCheckOwnership, Kind: "missing_owner", Severity: SeverityWarning, Path: p,
+				Message: fmt.Sprintf("Leaf '%s' has no accountable owner, even after inheritance", p)})
+		}
+	}
+
+	report.SuccessorIssues = reg.ValidateSuccessors()
+	for _, issue := range report.SuccessorIssues {
+		add(LintIssue{Check: LintCheckSuccessor, Kind: string(issue.Kind), Severity: SeverityError, Path: issue.Path, Message: issue.Message})
+	}
+	report.ReferenceIssues = reg.ValidateReferences()
+	for _, issue := range report.ReferenceIssues {
+		add(LintIssue{Check: LintCheckReference, Kind: string(issue.Kind), Severity: issue.Severity, Path: issue.Path, Message: issue.Message})
+	}
+	report.SunsetIssues = reg.ValidateSunsets()
+	for _, issue := range report.SunsetIssues {
+		add(LintIssue{Check: LintCheckSunset, Severity: SeverityError, Path: issue.Path, Message: issue.Message})
+	}
+	report.HierarchyIssues = reg.ValidateHierarchy()
+	for _, issue := range report.HierarchyIssues {
+		add(LintIssue{Check: LintCheckHierarchy, Kind: issue.Kind, Severity: issue.Severity, Path: issue.Path, File: issue.File, Line: issue.Line, Message: issue.Message})
+	}
+
+	report.finish()
+	return report
+}
+
+// LintLoadError reports a catalog that failed to load. Source config and CSV
+// import errors are expanded into one issue per binding or row; anything else
+// is a single issue.
+func LintLoadError(err error) *LintReport {
+	report := newLintReport()
+	var configErr *SourceConfigError
+	var csvErr *CSVImportError
+	if errors.As(err, &csvErr) {
+		for _, row := range csvErr.Rows {
+			report.Issues = append(report.Issues, LintIssue{Check: LintCheckLoad, Severity: SeverityError, Path: row.Path, File: row.File, Line: row.Line, Message: row.Message})
+		}
+	} else if errors.As(err, &configErr) {
+		report.SourceConfig = configErr.Violations
+		for _, v := range configErr.Violations {
+			report.Issues = append(report.Issues, LintIssue{Check: LintCheckSourceConfig, Severity: SeverityError, Path: v.Path, Message: v.Message})
+		}
+	} else {
+		report.Issues = append(report.Issues, LintIssue{Check: LintCheckLoad, Severity: SeverityError, Message: err.Error()})
+	}
+	report.finish()
+	return report
+}
+
+func newLintReport() *LintReport {
+	return &LintReport{
+		Issues:          make([]LintIssue, 0),
+		UnknownFields:   make([]UnknownField, 0),
+		SourceConfig:    make([]SourceConfigViolation, 0),
+		SuccessorIssues: make([]SuccessorIssue, 0),
+		ReferenceIssues: make([]ReferenceIssue, 0),
+		SunsetIssues:    make([]SunsetIssue, 0),
+		HierarchyIssues: make([]HierarchyIssue, 0),
+	}
+}
+
+// finish sorts the issues (errors first, then by location) and fills in the counts
+func (r *LintReport) finish() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity == SeverityError
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Path < b.Path
+	})
+	r.Count = len(r.Issues)
+	r.Errors, r.Warnings = 0, 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	r.Valid = r.Errors == 0
+}
+
+// WriteText writes the report one issue per line, followed by a summary
+func (r *LintReport) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		where := ""
+		switch {
+		case issue.File != "" && issue.Line > 0:
+			where = fmt.Sprintf("%s:%d: ", issue.File, issue.Line)
+		case issue.File != "":
+			where = issue.File + ": "
+		case issue.Line > 0:
+			where = fmt.Sprintf("line %d: ", issue.Line)
+		}
+		if strings.HasPrefix(issue.Message, where) {
+			where = "" // Message already names its location
+		}
+		check := issue.Check
+		if issue.Kind != "" {
+			check += "/" + issue.Kind
+		}
+		if _, err := fmt.Fprintf(w, "%-7s %s[%s] %s\n", issue.Severity, where, check, issue.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings\n", r.Errors, r.Warnings)
+	return err
+}
@@ -0,0 +1,91 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLintReportsEveryCheck(t *testing.T) {
+	nodes := []*CatalogNode{
+		{Path: "prices", Status: NodeStatusActive, Ownership: &Ownership{AccountableOwner: strPtr("owner@example.com")}},
+		{Path: "prices/equity", Status: NodeStatusActive, IsLeaf: true,
+			SourceBinding: &SourceBinding{SourceType: SourceTypeSnowflake, Config: map[string]interface{}{"account": "acme"}},
+			AccessPolicy:  &AccessPolicy{BlockedPatterns: []string{"("}}},
+		{Path: "prices/old", Status: NodeStatus("retired"), Successor: strPtr("prices/gone")},
+		{Path: "rates/swaps", Status: NodeStatusActive, IsLeaf: true},
+	}
+	nodes[1].UnknownFields = []UnknownField{{Path: "prices/equity", File: "a.yaml", Line: 3, Field: "dispay_name"}}
+
+	report := Lint(nodes)
+
+	checks := make(map[string]string)
+	for _, issue := range report.Issues {
+		checks[issue.Check] = issue.Severity
+	}
+	want := map[string]string{
+		LintCheckUnknownField:   SeverityError,
+		LintCheckSourceConfig:   SeverityError,
+		LintCheckBlockedPattern: SeverityError,
+		LintCheckStatus:         SeverityError,
+		LintCheckSuccessor:      SeverityError,
+		LintCheckHierarchy:      SeverityWarning,
+		LintCheckOwnership:      SeverityWarning,
+	}
+	for check, severity := range want {
+		if checks[check] != severity {
+			t.Errorf("check %s: expected severity %q, got %q", check, severity, checks[check])
+		}
+	}
+	if report.Valid {
+		t.Error("report with errors should not be valid")
+	}
+	if report.Errors+report.Warnings != report.Count || report.Count != len(report.Issues) {
+		t.Errorf("inconsistent counts: %+v", report)
+	}
+	if report.Issues[0].Severity != SeverityError || report.Issues[len(report.Issues)-1].Severity != SeverityWarning {
+		t.Error("errors should sort before warnings")
+	}
+	if len(report.SuccessorIssues) != 1 || len(report.SourceConfig) != 1 || len(report.HierarchyIssues) != 1 {
+		t.Errorf("per-check lists not populated: %+v", report)
+	}
+}
+
+func TestLintCleanCatalog(t *testing.T) {
+	report := Lint([]*CatalogNode{
+		{Path: "prices", Status: NodeStatusActive, Ownership: &Ownership{AccountableOwner: strPtr("owner@example.com")}},
+		{Path: "prices/equity", Status: NodeStatusActive, IsLeaf: true},
+	})
+	if !report.Valid || report.Count != 0 {
+		t.Errorf("expected a clean report, got %+v", report.Issues)
+	}
+}
+
+func TestLintLoadError(t *testing.T) {
+	report := LintLoadError(&SourceConfigError{Violations: []SourceConfigViolation{
+		{Path: "a", Message: "a: bad"}, {Path: "b", Message: "b: bad"},
+	}})
+	if report.Valid || report.Errors != 2 || report.Issues[0].Check != LintCheckSourceConfig {
+		t.Errorf("expected 2 source config errors, got %+v", report.Issues)
+	}
+
+	report = LintLoadError(errors.New("read catalog file: no such file"))
+	if report.Errors != 1 || report.Issues[0].Check != LintCheckLoad {
+		t.Errorf("expected a load error, got %+v", report.Issues)
+	}
+}
+
+func TestLintReportWriteText(t *testing.T) {
+	nodes := []*CatalogNode{{Path: "prices", Status: NodeStatus("live"), SourceFile: "catalog.yaml", SourceLine: 4}}
+	var buf bytes.Buffer
+	if err := Lint(nodes).WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"error   catalog.yaml:4: [status/invalid_status]", "1 errors, 0 warnings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
@@ -1,8 +1,15 @@
 package catalog
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,91 +18,704 @@ import (
 // The catalog YAML is a flat map of path -> node (no "nodes" wrapper)
 type CatalogYAML map[string]*CatalogNodeYAML
 
-// CatalogNodeYAML represents a node in the YAML file
+// CatalogNodeYAML represents a node in the YAML file. JSON catalogs decode
+// into the same structs using the json tags.
 type CatalogNodeYAML struct {
-	DisplayName          string                 `yaml:"display_name"`
-	Description          string                 `yaml:"description"`
-	TechnicalDescription string                 `yaml:"technical_description"`
-	AssetClass           string                 `yaml:"asset_class"`
-	UpdateFrequency      string                 `yaml:"update_frequency"`
-	Domain               *string                `yaml:"domain"`
-	Vendor               *string                `yaml:"vendor"`
-	Maturity             *string                `yaml:"maturity"`
-	Ownership            *OwnershipYAML         `yaml:"ownership"`
-	SourceBinding        *SourceBindingYAML     `yaml:"source_binding"`
-	AccessPolicy         *AccessPolicyYAML      `yaml:"access_policy"`
-	Documentation        *Documentation         `yaml:"documentation"`
-	Schema               map[string]interface{} `yaml:"schema"`
-	Classification       string                 `yaml:"classification"`
-	Tags                 []string               `yaml:"tags"`
-	Status               string                 `yaml:"status"`
-	IsLeaf               bool                   `yaml:"is_leaf"`
-	Successor            *string                `yaml:"successor"`
-	DeprecationMessage   *string                `yaml:"deprecation_message"`
-	MigrationGuideURL    *string                `yaml:"migration_guide_url"`
-	SunsetDeadline       *string                `yaml:"sunset_deadline"`
-	Metadata             map[string]interface{} `yaml:"metadata"`
-	DataQuality          map[string]interface{} `yaml:"data_quality"`
-	SLAData              map[string]interface{} `yaml:"sla"`
-	FreshnessData        map[string]interface{} `yaml:"freshness"`
+	DisplayName          string                 `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	Description          string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	TechnicalDescription string                 `json:"technical_description,omitempty" yaml:"technical_description,omitempty"`
+	AssetClass           string                 `json:"asset_class,omitempty" yaml:"asset_class,omitempty"`
+	UpdateFrequency      string                 `json:"update_frequency,omitempty" yaml:"update_frequency,omitempty"`
+	Domain               *string                `json:"domain,omitempty" yaml:"domain,omitempty"`
+	Vendor               *string                `json:"vendor,omitempty" yaml:"vendor,omitempty"`
+	Maturity             *string                `json:"maturity,omitempty" yaml:"maturity,omitempty"`
+	Ownership            *OwnershipYAML         `json:"ownership,omitempty" yaml:"ownership,omitempty"`
+	SourceBinding        *SourceBindingYAML     `json:"source_binding,omitempty" yaml:"source_binding,omitempty"`
+	AccessPolicy         *AccessPolicyYAML      `json:"access_policy,omitempty" yaml:"access_policy,omitempty"`
+	Documentation        *DocumentationYAML     `json:"documentation,omitempty" yaml:"documentation,omitempty"`
+	Schema               *DataSchemaYAML        `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Classification       string                 `json:"classification,omitempty" yaml:"classification,omitempty"`
+	Tags                 []string               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Status               string                 `json:"status,omitempty" yaml:"status,omitempty"`
+	IsLeaf               bool                   `json:"is_leaf,omitempty" yaml:"is_leaf,omitempty"`
+	CreatedAt            *string                `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt            *string                `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	CreatedBy            *string                `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	ApprovedBy           *string                `json:"approved_by,omitempty" yaml:"approved_by,omitempty"`
+	Successor            *string                `json:"successor,omitempty" yaml:"successor,omitempty"`
+	DeprecationMessage   *string                `json:"deprecation_message,omitempty" yaml:"deprecation_message,omitempty"`
+	MigrationGuideURL    *string                `json:"migration_guide_url,omitempty" yaml:"migration_guide_url,omitempty"`
+	SunsetDeadline       *string                `json:"sunset_deadline,omitempty" yaml:"sunset_deadline,omitempty"`
+	Metadata             map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	DataQuality          *DataQualityYAML       `json:"data_quality,omitempty" yaml:"data_quality,omitempty"`
+	SLA                  *SLAYAML               `json:"sla,omitempty" yaml:"sla,omitempty"`
+	Freshness            *FreshnessYAML         `json:"freshness,omitempty" yaml:"freshness,omitempty"`
+	Extends              string                 `json:"extends,omitempty" yaml:"extends,omitempty"` // Name of a _defaults entry merged under this node
 }
 
 // OwnershipYAML represents ownership in YAML
 type OwnershipYAML struct {
-	AccountableOwner *string `yaml:"accountable_owner"`
-	DataSpecialist   *string `yaml:"data_specialist"`
-	SupportChannel   *string `yaml:"support_channel"`
-	ADOP             *string `yaml:"adop"`
-	ADS              *string `yaml:"ads"`
-	ADAL             *string `yaml:"adal"`
-	ADOPName         *string `yaml:"adop_name"`
-	ADSName          *string `yaml:"ads_name"`
-	ADALName         *string `yaml:"adal_name"`
-	UI               *string `yaml:"ui"`
+	AccountableOwner *string `json:"accountable_owner,omitempty" yaml:"accountable_owner,omitempty"`
+	DataSpecialist   *string `json:"data_specialist,omitempty" yaml:"data_specialist,omitempty"`
+	SupportChannel   *string `json:"support_channel,omitempty" yaml:"support_channel,omitempty"`
+	ADOP             *string `json:"adop,omitempty" yaml:"adop,omitempty"`
+	ADS              *string `json:"ads,omitempty" yaml:"ads,omitempty"`
+	ADAL             *string `json:"adal,omitempty" yaml:"adal,omitempty"`
+	ADOPName         *string `json:"adop_name,omitempty" yaml:"adop_name,omitempty"`
+	ADSName          *string `json:"ads_name,omitempty" yaml:"ads_name,omitempty"`
+	ADALName         *string `json:"adal_name,omitempty" yaml:"adal_name,omitempty"`
+	UI               *string `json:"ui,omitempty" yaml:"ui,omitempty"`
+}
+
+// SLAYAML represents an SLA block in YAML
+type SLAYAML struct {
+	Freshness         *string `json:"freshness,omitempty" yaml:"freshness,omitempty"`
+	Availability      *string `json:"availability,omitempty" yaml:"availability,omitempty"`
+	SupportHours      *string `json:"support_hours,omitempty" yaml:"support_hours,omitempty"`
+	EscalationContact *string `json:"escalation_contact,omitempty" yaml:"escalation_contact,omitempty"`
+}
+
+// DataQualityYAML represents a data quality block in YAML
+type DataQualityYAML struct {
+	DQOwner         *string  `json:"dq_owner,omitempty" yaml:"dq_owner,omitempty"`
+	QualityScore    *float64 `json:"quality_score,omitempty" yaml:"quality_score,omitempty"`
+	ValidationRules []string `json:"validation_rules,omitempty" yaml:"validation_rules,omitempty"`
+	KnownIssues     []string `json:"known_issues,omitempty" yaml:"known_issues,omitempty"`
+	LastValidated   *string  `json:"last_validated,omitempty" yaml:"last_validated,omitempty"`
+}
+
+// FreshnessYAML represents a freshness block in YAML
+type FreshnessYAML struct {
+	LastLoaded           *string  `json:"last_loaded,omitempty" yaml:"last_loaded,omitempty"`
+	RefreshSchedule      *string  `json:"refresh_schedule,omitempty" yaml:"refresh_schedule,omitempty"`
+	SourceSystem         *string  `json:"source_system,omitempty" yaml:"source_system,omitempty"`
+	UpstreamDependencies []string `json:"upstream_dependencies,omitempty" yaml:"upstream_dependencies,omitempty"`
+}
+
+// DocumentationYAML represents documentation links in YAML
+type DocumentationYAML struct {
+	Glossary       *string           `json:"glossary,omitempty" yaml:"glossary,omitempty"`
+	Runbook        *string           `json:"runbook,omitempty" yaml:"runbook,omitempty"`
+	Onboarding     *string           `json:"onboarding,omitempty" yaml:"onboarding,omitempty"`
+	DataDictionary *string           `json:"data_dictionary,omitempty" yaml:"data_dictionary,omitempty"`
+	APIDocs        *string           `json:"api_docs,omitempty" yaml:"api_docs,omitempty"`
+	Architecture   *string           `json:"architecture,omitempty" yaml:"architecture,omitempty"`
+	Changelog      *string           `json:"changelog,omitempty" yaml:"changelog,omitempty"`
+	Contact        *string           `json:"contact,omitempty" yaml:"contact,omitempty"`
+	Additional     map[string]string `json:"additional,omitempty" yaml:"additional,omitempty"`
+}
+
+// ColumnSchemaYAML represents a column definition in YAML
+type ColumnSchemaYAML struct {
+	Name         string  `json:"name" yaml:"name"`
+	Type         string  `json:"type,omitempty" yaml:"type,omitempty"`
+	Description  string  `json:"description,omitempty" yaml:"description,omitempty"`
+	SemanticType *string `json:"semantic_type,omitempty" yaml:"semantic_type,omitempty"`
+	Example      *string `json:"example,omitempty" yaml:"example,omitempty"`
+	Nullable     bool    `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	PrimaryKey   bool    `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	ForeignKey   *string `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+}
+
+// DataSchemaYAML represents the node-level schema block in YAML
+// (separate from the source binding schema)
+type DataSchemaYAML struct {
+	Columns         []ColumnSchemaYAML `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Description     string             `json:"description,omitempty" yaml:"description,omitempty"`
+	SemanticTags    []string           `json:"semantic_tags,omitempty" yaml:"semantic_tags,omitempty"`
+	PrimaryKey      []string           `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	UseCases        []string           `json:"use_cases,omitempty" yaml:"use_cases,omitempty"`
+	Examples        []string           `json:"examples,omitempty" yaml:"examples,omitempty"`
+	RelatedMonikers []string           `json:"related_monikers,omitempty" yaml:"related_monikers,omitempty"`
+	Granularity     *string            `json:"granularity,omitempty" yaml:"granularity,omitempty"`
+	TypicalRowCount *string            `json:"typical_row_count,omitempty" yaml:"typical_row_count,omitempty"`
+	UpdateFrequency *string            `json:"update_frequency,omitempty" yaml:"update_frequency,omitempty"`
 }
 
 // SourceBindingYAML represents a source binding in YAML
 type SourceBindingYAML struct {
-	Type              string                 `yaml:"type"`
-	Config            map[string]interface{} `yaml:"config"`
-	AllowedOperations []string               `yaml:"allowed_operations"`
-	Schema            map[string]interface{} `yaml:"schema"`
-	ReadOnly          *bool                  `yaml:"read_only"`
+	Type              string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Config            map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	AllowedOperations []string               `json:"allowed_operations,omitempty" yaml:"allowed_operations,omitempty"`
+	Schema            map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+	ReadOnly          *bool                  `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+	Cache             *QueryCacheConfigYAML  `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Template          string                 `json:"template,omitempty" yaml:"template,omitempty"` // Name of a _binding_templates entry merged under this binding
+}
+
+// QueryCacheConfigYAML represents a source binding's query cache block in YAML
+type QueryCacheConfigYAML struct {
+	Enabled                bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	TTLSeconds             int  `json:"ttl_seconds,omitempty" yaml:"ttl_seconds,omitempty"`
+	RefreshIntervalSeconds int  `json:"refresh_interval_seconds,omitempty" yaml:"refresh_interval_seconds,omitempty"`
+	RefreshOnStartup       bool `json:"refresh_on_startup,omitempty" yaml:"refresh_on_startup,omitempty"`
 }
 
 // AccessPolicyYAML represents access policy in YAML
 type AccessPolicyYAML struct {
-	RequiredSegments       []int    `yaml:"required_segments"`
-	MinFilters             *int     `yaml:"min_filters"`
-	BlockedPatterns        []string `yaml:"blocked_patterns"`
-	MaxRowsWarn            *int     `yaml:"max_rows_warn"`
-	MaxRowsBlock           *int     `yaml:"max_rows_block"`
-	CardinalityMultipliers []int    `yaml:"cardinality_multipliers"`
-	BaseRowCount           *int     `yaml:"base_row_count"`
-	DenialMessage          *string  `yaml:"denial_message"`
+	RequiredSegments       []int    `json:"required_segments,omitempty" yaml:"required_segments,omitempty"`
+	MinFilters             *int     `json:"min_filters,omitempty" yaml:"min_filters,omitempty"`
+	BlockedPatterns        []string `json:"blocked_patterns,omitempty" yaml:"blocked_patterns,omitempty"`
+	MaxRowsWarn            *int     `json:"max_rows_warn,omitempty" yaml:"max_rows_warn,omitempty"`
+	MaxRowsBlock           *int     `json:"max_rows_block,omitempty" yaml:"max_rows_block,omitempty"`
+	CardinalityMultipliers []int    `json:"cardinality_multipliers,omitempty" yaml:"cardinality_multipliers,omitempty"`
+	BaseRowCount           *int     `json:"base_row_count,omitempty" yaml:"base_row_count,omitempty"`
+	DenialMessage          *string  `json:"denial_message,omitempty" yaml:"denial_message,omitempty"`
+
+	RequireConfirmationAbove *int     `json:"require_confirmation_above,omitempty" yaml:"require_confirmation_above,omitempty"`
+	AllowedRoles             []string `json:"allowed_roles,omitempty" yaml:"allowed_roles,omitempty"`
+	AllowedHours             []int    `json:"allowed_hours,omitempty" yaml:"allowed_hours,omitempty"` // [start_hour, end_hour] in UTC
 }
 
-// LoadCatalog loads a catalog from a YAML file
+// includeKey is the reserved top-level key listing catalog files to include
+const includeKey = "_include"
+
+// maxIncludeDepth bounds how deeply _include directives may nest
+const maxIncludeDepth = 10
+
+// LoadCatalog loads a catalog from a YAML file, following _include directives.
+// Included paths resolve relative to the including file.
 func LoadCatalog(path string) ([]*CatalogNode, error) {
-	data, err := os.ReadFile(path)
+	return loadCatalogFile(path, nil, os.ReadFile)
+}
+
+// readFileFunc reads a catalog or data file. Loads verified against a
+// manifest read through it so the bytes parsed are the bytes hashed.
+type readFileFunc func(path string) ([]byte, error)
+
+// loadCatalogFile loads a YAML catalog and, recursively, the files it includes.
+// stack holds the absolute paths of the including files, for cycle detection.
+func loadCatalogFile(path string, stack []string, read readFileFunc) ([]*CatalogNode, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve catalog path: %w", err)
+	}
+	for i, s := range stack {
+		if s == abs {
+			cycle := append(append([]string{}, stack[i:]...), abs)
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	if len(stack) > maxIncludeDepth {
+		return nil, fmt.Errorf("includes nested deeper than %d levels at %s", maxIncludeDepth, path)
+	}
+
+	data, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog file: %w", err)
+	}
+
+	nodes, includes, err := parseCatalogYAML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	definedIn := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		node.SourceFile = path
+		for i := range node.UnknownFields {
+			node.UnknownFields[i].File = path
+		}
+		definedIn[node.Path] = sourceLocation(node)
+	}
+	if err := loadStaticData(nodes, read); err != nil {
+		return nil, err
+	}
+
+	stack = append(append([]string{}, stack...), abs)
+	for _, include := range includes {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(filepath.Dir(path), include)
+		}
+
+		var included []*CatalogNode
+		if strings.EqualFold(filepath.Ext(includePath), ".json") {
+			included, err = loadCatalogJSON(includePath, read)
+		} else {
+			included, err = loadCatalogFile(includePath, stack, read)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s (included from %s): %w", includePath, path, err)
+		}
+
+		for _, node := range included {
+			if first, dup := definedIn[node.Path]; dup {
+				return nil, fmt.Errorf("duplicate catalog path '%s' defined in %s and %s", node.Path, first, sourceLocation(node))
+			}
+			definedIn[node.Path] = sourceLocation(node)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+// LoadCatalogJSON loads a catalog from a JSON file with the same flat
+// path-keyed structure as the YAML format
+func LoadCatalogJSON(path string) ([]*CatalogNode, error) {
+	return loadCatalogJSON(path, os.ReadFile)
+}
+
+func loadCatalogJSON(path string, read readFileFunc) ([]*CatalogNode, error) {
+	data, err := read(path)
 	if err != nil {
 		return nil, fmt.Errorf("read catalog file: %w", err)
 	}
 
-	var catalogYAML CatalogYAML
-	if err := yaml.Unmarshal(data, &catalogYAML); err != nil {
-		return nil, fmt.Errorf("parse catalog YAML: %w", err)
+	nodes, err := ParseCatalogJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		node.SourceFile = path
+	}
+	if err := loadStaticData(nodes, read); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ParseCatalogJSON parses catalog nodes from JSON content. Errors name the
+// JSON path of the offending field, e.g. $["prices/equity"].access_policy.min_filters
+func ParseCatalogJSON(data []byte) ([]*CatalogNode, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse catalog JSON: %s", describeJSONError(data, "$", err))
+	}
+
+	version := 1
+	if msg, ok := raw[schemaVersionKey]; ok {
+		if err := json.Unmarshal(msg, &version); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: $.%s must be an integer", schemaVersionKey)
+		}
+		if err := checkSchemaVersion(version); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		delete(raw, schemaVersionKey)
+	}
+
+	defaults := map[string]json.RawMessage{}
+	if msg, ok := raw[defaultsKey]; ok {
+		var err error
+		if defaults, err = parseJSONDefaults(msg, version); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		delete(raw, defaultsKey)
+	}
+
+	templates := map[string]json.RawMessage{}
+	if msg, ok := raw[bindingTemplatesKey]; ok {
+		var err error
+		if templates, err = parseJSONBindingTemplates(msg); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		delete(raw, bindingTemplatesKey)
+	}
+
+	raw, err := flattenJSONChildren(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse catalog JSON: %w", err)
+	}
+
+	nodes := make([]*CatalogNode, 0, len(raw))
+	for path, msg := range raw {
+		msg, err := migrateJSONNode(path, msg, version)
+		if err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		if msg, err = applyJSONDefaults(path, msg, defaults); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		if msg, err = applyJSONBindingTemplate(path, msg, templates); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %w", err)
+		}
+		var nodeJSON *CatalogNodeYAML
+		if err := json.Unmarshal(msg, &nodeJSON); err != nil {
+			return nil, fmt.Errorf("parse catalog JSON: %s", describeJSONError(msg, fmt.Sprintf("$[%q]", path), err))
+		}
+		if nodeJSON != nil {
+			if err := validateNodeYAML(path, nodeJSON); err != nil {
+				return nil, fmt.Errorf("parse catalog JSON: %w", err)
+			}
+			nodes = append(nodes, convertYAMLToNode(path, nodeJSON))
+		}
+	}
+
+	if err := validateSourceConfigs(nodes); err != nil {
+		return nil, fmt.Errorf("parse catalog JSON: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// describeJSONError renders a decode error with the JSON path (or position)
+// of the failure. base is the JSON path of data within the document.
+func describeJSONError(data []byte, base string, err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		where := base
+		if typeErr.Field != "" {
+			where += "." + typeErr.Field
+		}
+		return fmt.Sprintf("%s: expected %s, got %s", where, typeErr.Type, typeErr.Value)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := 1, 1
+		for _, b := range data[:min(int(syntaxErr.Offset), len(data))] {
+			if b == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+		}
+		return fmt.Sprintf("%s: %v (line %d, column %d)", base, err, line, col)
+	}
+
+	return fmt.Sprintf("%s: %v", base, err)
+}
+
+// Load loads a catalog file, directory, or URL, choosing the format from the
+// file extension: .json is JSON, anything else is YAML. Directories are loaded
+// with LoadCatalogDir and URLs with LoadCatalogURL.
+func Load(path string) ([]*CatalogNode, error) {
+	return load(path, os.ReadFile)
+}
+
+func load(path string, read readFileFunc) ([]*CatalogNode, error) {
+	if IsRemoteCatalog(path) {
+		return LoadCatalogURL(context.Background(), path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+	if info.IsDir() {
+		return loadCatalogDir(path, read)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return loadCatalogJSON(path, read)
+	}
+	return loadCatalogFile(path, nil, read)
+}
+
+// LoadCatalogDir loads every *.yaml, *.yml, and *.json file under dir,
+// recursively. A path defined in more than one file is an error naming both files.
+func LoadCatalogDir(dir string) ([]*CatalogNode, error) {
+	return loadCatalogDir(dir, os.ReadFile)
+}
+
+func loadCatalogDir(dir string, read readFileFunc) ([]*CatalogNode, error) {
+	files := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" || ext == ".json" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read catalog directory: %w", err)
+	}
+	sort.Strings(files)
+
+	nodes := make([]*CatalogNode, 0)
+	definedIn := make(map[string]string)
+	for _, file := range files {
+		fileNodes, err := load(file, read)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, node := range fileNodes {
+			if first, dup := definedIn[node.Path]; dup {
+				return nil, fmt.Errorf("duplicate catalog path '%s' defined in %s and %s", node.Path, first, sourceLocation(node))
+			}
+			definedIn[node.Path] = sourceLocation(node)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+// ParseCatalog parses catalog nodes from YAML content. Content with an
+// _include directive must be loaded with LoadCatalog so includes can be resolved.
+func ParseCatalog(data []byte) ([]*CatalogNode, error) {
+	nodes, includes, err := parseCatalogYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(includes) > 0 {
+		return nil, fmt.Errorf("%s requires loading the catalog from a file", includeKey)
+	}
+	return nodes, nil
+}
+
+// parseCatalogYAML parses catalog nodes and the _include list from YAML content.
+// The document is walked key by key, so a path defined twice is reported with
+// both line numbers rather than collapsed by a map decode. Nodes nested under a
+// children key get their full path from the structure. Unknown keys are
+// ignored but recorded on each node's UnknownFields.
+func parseCatalogYAML(data []byte) ([]*CatalogNode, []string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return []*CatalogNode{}, nil, nil
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("parse catalog YAML: line %d: catalog must be a mapping of path to node", mapping.Line)
+	}
+
+	version := 1
+	if raw := yamlMappingValue(mapping, schemaVersionKey); raw != nil {
+		if err := raw.Decode(&version); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: line %d: %s must be an integer", raw.Line, schemaVersionKey)
+		}
+		if err := checkSchemaVersion(version); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
+	}
+
+	defaults := map[string]*yaml.Node{}
+	if raw := yamlMappingValue(mapping, defaultsKey); raw != nil {
+		var err error
+		if defaults, err = parseYAMLDefaults(raw, version); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
+	}
+
+	templates := map[string]*yaml.Node{}
+	if raw := yamlMappingValue(mapping, bindingTemplatesKey); raw != nil {
+		var err error
+		if templates, err = parseYAMLBindingTemplates(raw); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
 	}
 
-	nodes := make([]*CatalogNode, 0, len(catalogYAML))
-	for path, nodeYAML := range catalogYAML {
+	entries, err := flattenYAMLChildren(mapping)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+	}
+
+	var includes []string
+	nodes := make([]*CatalogNode, 0, len(entries))
+	first := make(map[string]yamlEntry, len(entries))
+	extends := make(map[string]string)
+	templateOf := make(map[string]string)
+	duplicates := make([]string, 0)
+	for _, entry := range entries {
+		key, raw, path := entry.key, entry.value, entry.path
+
+		if prev, dup := first[path]; dup {
+			if entry.parent != "" || prev.parent != "" {
+				duplicates = append(duplicates, fmt.Sprintf("catalog path '%s' at line %d conflicts with its definition at line %d (a nested child and a flat key define the same path)", path, key.Line, prev.key.Line))
+			} else {
+				duplicates = append(duplicates, fmt.Sprintf("duplicate catalog path '%s' at line %d (first defined at line %d)", path, key.Line, prev.key.Line))
+			}
+			continue
+		}
+		first[path] = entry
+
+		if path == includeKey {
+			if err := raw.Decode(&includes); err != nil {
+				return nil, nil, fmt.Errorf("parse catalog YAML: %s must be a list of file paths: %w", includeKey, err)
+			}
+			continue
+		}
+		if isReservedKey(path) {
+			continue
+		}
+
+		if err := migrateYAMLNode(path, raw, version); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
+		if err := applyYAMLDefaults(path, raw, defaults); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
+		if err := applyYAMLBindingTemplate(path, raw, templates); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+		}
+		var nodeYAML *CatalogNodeYAML
+		if err := raw.Decode(&nodeYAML); err != nil {
+			return nil, nil, fmt.Errorf("parse catalog YAML: %s: %w", path, err)
+		}
 		if nodeYAML != nil {
+			if err := validateNodeYAML(path, nodeYAML); err != nil {
+				return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+			}
 			node := convertYAMLToNode(path, nodeYAML)
+			node.SourceLine = key.Line
+			if nodeYAML.Extends != "" {
+				extends[path] = nodeYAML.Extends
+			}
+			if nodeYAML.SourceBinding != nil && nodeYAML.SourceBinding.Template != "" {
+				templateOf[path] = nodeYAML.SourceBinding.Template
+			}
 			nodes = append(nodes, node)
 		}
 	}
+	if len(duplicates) > 0 {
+		return nil, nil, fmt.Errorf("parse catalog YAML: %s", strings.Join(duplicates, "; "))
+	}
 
-	return nodes, nil
+	if err := validateSourceConfigs(nodes); err != nil {
+		return nil, nil, fmt.Errorf("parse catalog YAML: %w", err)
+	}
+
+	if unknown := findUnknownFields(data, yamlEntryLines(entries)); len(unknown) > 0 {
+		byPath := make(map[string]*CatalogNode, len(nodes))
+		for _, node := range nodes {
+			byPath[node.Path] = node
+		}
+		migrated := migratedKeys(version)
+		for _, u := range unknown {
+			if migrated[u.Field] {
+				continue // Old key name rewritten by a schema migration
+			}
+			if node, ok := byPath[u.Path]; ok {
+				node.UnknownFields = append(node.UnknownFields, u)
+			}
+		}
+	}
+	if raw := yamlMappingValue(mapping, defaultsKey); raw != nil {
+		// A typo in a default is reported on every node that extends it
+		unknown := findUnknownSectionFields(data, defaultsKey, raw, &defaultsDocYAML{})
+		for _, node := range nodes {
+			node.UnknownFields = append(node.UnknownFields, unknown[extends[node.Path]]...)
+		}
+	}
+	if raw := yamlMappingValue(mapping, bindingTemplatesKey); raw != nil {
+		// Likewise for a typo in a binding template
+		unknown := findUnknownSectionFields(data, bindingTemplatesKey, raw, &bindingTemplatesDocYAML{})
+		for _, node := range nodes {
+			if node.SourceBinding != nil {
+				node.UnknownFields = append(node.UnknownFields, unknown[templateOf[node.Path]]...)
+			}
+		}
+	}
+
+	return nodes, includes, nil
+}
+
+// LoadCatalogs loads several catalog files or directories and merges them with
+// the given conflict policy. Conflicts carry the names of the defining catalogs.
+// A source with a manifest (see ManifestPath) is verified against it before it
+// is parsed; a mismatch fails the load with a *ManifestError.
+func LoadCatalogs(policy ConflictPolicy, paths ...string) ([]*CatalogNode, []Conflict, error) {
+	nodes, conflicts, _, err := loadCatalogs(policy, paths...)
+	return nodes, conflicts, err
+}
+
+// loadCatalogs is LoadCatalogs, also returning the manifests the sources were
+// verified against
+func loadCatalogs(policy ConflictPolicy, paths ...string) ([]*CatalogNode, []Conflict, []*Manifest, error) {
+	catalogs := make([][]*CatalogNode, 0, len(paths))
+	manifests := make([]*Manifest, 0)
+	for _, path := range paths {
+		nodes, manifest, err := loadVerified(path)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		catalogs = append(catalogs, nodes)
+		if manifest != nil {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	nodes, conflicts, err := Merge(policy, catalogs...)
+	for i := range conflicts {
+		for _, idx := range conflicts[i].Catalogs {
+			conflicts[i].Sources = append(conflicts[i].Sources, paths[idx])
+		}
+	}
+	return nodes, conflicts, manifests, err
+}
+
+// ReloadCatalog loads the catalog file, computes the diff against the live
+// registry, and swaps the new nodes in. The diff is returned so callers can
+// log or report what changed.
+func ReloadCatalog(reg *Registry, path string) (*CatalogDiff, error) {
+	diff, _, err := ReloadCatalogs(reg, ConflictError, path)
+	return diff, err
+}
+
+// ReloadCatalogs is ReloadCatalog for several merged catalog files. The
+// registry is only replaced if the policy resolves every conflict.
+func ReloadCatalogs(reg *Registry, policy ConflictPolicy, paths ...string) (*CatalogDiff, []Conflict, error) {
+	nodes, conflicts, err := LoadCatalogs(policy, paths...)
+	if err != nil {
+		return nil, conflicts, err
+	}
+
+	diff := reg.Diff(nodes)
+	reg.AtomicReplace(nodes)
+	return diff, conflicts, nil
+}
+
+// validateNodeYAML checks field constraints that the YAML types cannot express
+func validateNodeYAML(path string, node *CatalogNodeYAML) error {
+	if sb := node.SourceBinding; sb != nil && sb.Cache != nil {
+		if sb.Cache.Enabled && sb.Cache.TTLSeconds <= 0 {
+			return fmt.Errorf("%s: source_binding.cache.ttl_seconds must be > 0 when cache is enabled", path)
+		}
+		if sb.Cache.RefreshIntervalSeconds < 0 {
+			return fmt.Errorf("%s: source_binding.cache.refresh_interval_seconds must not be negative", path)
+		}
+	}
+	if ap := node.AccessPolicy; ap != nil {
+		for i, role := range ap.AllowedRoles {
+			if strings.TrimSpace(role) == "" {
+				return fmt.Errorf("%s: access_policy.allowed_roles[%d] must be a non-empty string", path, i)
+			}
+		}
+		if ap.AllowedHours != nil {
+			if len(ap.AllowedHours) != 2 {
+				return fmt.Errorf("%s: access_policy.allowed_hours must be [start_hour, end_hour], got %d values", path, len(ap.AllowedHours))
+			}
+			for i, h := range ap.AllowedHours {
+				if h < 0 || h > 23 {
+					return fmt.Errorf("%s: access_policy.allowed_hours[%d] must be between 0 and 23, got %d", path, i, h)
+				}
+			}
+		}
+		if ap.RequireConfirmationAbove != nil && *ap.RequireConfirmationAbove < 0 {
+			return fmt.Errorf("%s: access_policy.require_confirmation_above must not be negative", path)
+		}
+		if _, err := compileBlockedPatterns(ap.BlockedPatterns); err != nil {
+			return fmt.Errorf("%s: access_policy.%w", path, err)
+		}
+	}
+	for _, date := range []struct {
+		field string
+		value *string
+	}{{"created_at", node.CreatedAt}, {"updated_at", node.UpdatedAt}, {"sunset_deadline", node.SunsetDeadline}} {
+		if date.value == nil {
+			continue
+		}
+		if _, err := ParseISODate(*date.value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, date.field, err)
+		}
+	}
+	if node.Status != string(NodeStatusDeprecated) {
+		if node.SunsetDeadline != nil {
+			return fmt.Errorf("%s: sunset_deadline is only allowed when status is deprecated", path)
+		}
+		if node.MigrationGuideURL != nil {
+			return fmt.Errorf("%s: migration_guide_url is only allowed when status is deprecated", path)
+		}
+	}
+	return nil
 }
 
 func convertYAMLToNode(path string, yaml *CatalogNodeYAML) *CatalogNode {
@@ -122,60 +742,15 @@ func convertYAMLToNode(path string, yaml *CatalogNodeYAML) *CatalogNode {
 
 	// Set documentation
 	if yaml.Documentation != nil {
-		node.Documentation = yaml.Documentation
+		node.Documentation = convertDocumentationYAML(yaml.Documentation)
 	}
 
 	// Set node-level schema (separate from source_binding schema)
 	if yaml.Schema != nil {
-		node.DataSchema = &DataSchema{}
-		// Parse columns if present
-		if cols, ok := yaml.Schema["columns"]; ok {
-			if colList, ok := cols.([]interface{}); ok {
-				for _, c := range colList {
-					if cm, ok := c.(map[string]interface{}); ok {
-						col := ColumnSchema{
-							Name:        stringFromMap(cm, "name"),
-							DataType:    stringFromMap(cm, "type"),
-							Description: stringFromMap(cm, "description"),
-						}
-						if st, ok := cm["semantic_type"].(string); ok {
-							col.SemanticType = &st
-						}
-						if pk, ok := cm["primary_key"].(bool); ok {
-							col.PrimaryKey = pk
-						}
-						if fk, ok := cm["foreign_key"].(string); ok {
-							col.ForeignKey = &fk
-						}
-						node.DataSchema.Columns = append(node.DataSchema.Columns, col)
-					}
-				}
-			}
-		}
-		if tags, ok := yaml.Schema["semantic_tags"]; ok {
-			if tagList, ok := tags.([]interface{}); ok {
-				for _, t := range tagList {
-					if s, ok := t.(string); ok {
-						node.DataSchema.SemanticTags = append(node.DataSchema.SemanticTags, s)
-					}
-				}
-			}
-		}
-		if uc, ok := yaml.Schema["use_cases"]; ok {
-			if ucList, ok := uc.([]interface{}); ok {
-				for _, u := range ucList {
-					if s, ok := u.(string); ok {
-						node.DataSchema.UseCases = append(node.DataSchema.UseCases, s)
-					}
-				}
-			}
-		}
+		node.DataSchema = convertDataSchemaYAML(yaml.Schema)
 	}
 
-	// Set default classification
-	if node.Classification == "" {
-		node.Classification = "internal"
-	}
+	// Classification left empty inherits from ancestors (see Registry.ResolveClassification)
 
 	// Parse status
 	if yaml.Status != "" {
@@ -214,6 +789,14 @@ func convertYAMLToNode(path string, yaml *CatalogNodeYAML) *CatalogNode {
 			Schema:            yaml.SourceBinding.Schema,
 			ReadOnly:          readOnly,
 		}
+		if c := yaml.SourceBinding.Cache; c != nil {
+			node.SourceBinding.Cache = &QueryCacheConfig{
+				Enabled:                c.Enabled,
+				TTLSeconds:             c.TTLSeconds,
+				RefreshIntervalSeconds: c.RefreshIntervalSeconds,
+				RefreshOnStartup:       c.RefreshOnStartup,
+			}
+		}
 		// Auto-detect leaf node when source_binding is present
 		node.IsLeaf = true
 	}
@@ -234,14 +817,25 @@ func convertYAMLToNode(path string, yaml *CatalogNodeYAML) *CatalogNode {
 			CardinalityMultipliers: yaml.AccessPolicy.CardinalityMultipliers,
 			BaseRowCount:           baseRowCount,
 			DenialMessage:          yaml.AccessPolicy.DenialMessage,
+
+			RequireConfirmationAbove: yaml.AccessPolicy.RequireConfirmationAbove,
+			AllowedRoles:             yaml.AccessPolicy.AllowedRoles,
 		}
 
 		if yaml.AccessPolicy.MinFilters != nil {
 			node.AccessPolicy.MinFilters = *yaml.AccessPolicy.MinFilters
 		}
+		if h := yaml.AccessPolicy.AllowedHours; len(h) == 2 {
+			node.AccessPolicy.AllowedHours = &[2]int{h[0], h[1]}
+		}
+		_ = node.AccessPolicy.CompilePatterns() // Checked by validateNodeYAML
 	}
 
-	// Copy deprecation fields
+	// Copy lifecycle and deprecation fields
+	node.CreatedAt = yaml.CreatedAt
+	node.UpdatedAt = yaml.UpdatedAt
+	node.CreatedBy = yaml.CreatedBy
+	node.ApprovedBy = yaml.ApprovedBy
 	if yaml.DeprecationMessage != nil {
 		node.DeprecationMessage = yaml.DeprecationMessage
 	}
@@ -257,101 +851,73 @@ func convertYAMLToNode(path string, yaml *CatalogNodeYAML) *CatalogNode {
 		node.Metadata = yaml.Metadata
 	}
 
-	// Parse data quality
-	if yaml.DataQuality != nil {
-		node.DataQuality = parseDataQuality(yaml.DataQuality)
-	}
-
-	// Parse SLA
-	if yaml.SLAData != nil {
-		node.SLA = parseSLA(yaml.SLAData)
-	}
-
-	// Parse freshness
-	if yaml.FreshnessData != nil {
-		node.Freshness = parseFreshness(yaml.FreshnessData)
-	}
-
-	return node
-}
-
-func parseDataQuality(data map[string]interface{}) *DataQuality {
-	dq := &DataQuality{}
-	if v, ok := data["dq_owner"].(string); ok {
-		dq.DQOwner = &v
-	}
-	if v, ok := data["quality_score"].(float64); ok {
-		dq.QualityScore = &v
-	}
-	// yaml.v3 may decode integers as int, so handle both
-	if v, ok := data["quality_score"].(int); ok {
-		f := float64(v)
-		dq.QualityScore = &f
-	}
-	if v, ok := data["last_validated"].(string); ok {
-		dq.LastValidated = &v
+	// Copy data quality, SLA, and freshness
+	if dq := yaml.DataQuality; dq != nil {
+		node.DataQuality = &DataQuality{
+			DQOwner:         dq.DQOwner,
+			QualityScore:    dq.QualityScore,
+			ValidationRules: dq.ValidationRules,
+			KnownIssues:     dq.KnownIssues,
+			LastValidated:   dq.LastValidated,
+		}
 	}
-	if rules, ok := data["validation_rules"].([]interface{}); ok {
-		for _, r := range rules {
-			if s, ok := r.(string); ok {
-				dq.ValidationRules = append(dq.ValidationRules, s)
-			}
+	if sla := yaml.SLA; sla != nil {
+		node.SLA = &SLA{
+			Freshness:         sla.Freshness,
+			Availability:      sla.Availability,
+			SupportHours:      sla.SupportHours,
+			EscalationContact: sla.EscalationContact,
 		}
 	}
-	if issues, ok := data["known_issues"].([]interface{}); ok {
-		for _, i := range issues {
-			if s, ok := i.(string); ok {
-				dq.KnownIssues = append(dq.KnownIssues, s)
-			}
+	if f := yaml.Freshness; f != nil {
+		node.Freshness = &Freshness{
+			LastLoaded:           f.LastLoaded,
+			RefreshSchedule:      f.RefreshSchedule,
+			SourceSystem:         f.SourceSystem,
+			UpstreamDependencies: f.UpstreamDependencies,
 		}
 	}
-	return dq
-}
 
-func parseSLA(data map[string]interface{}) *SLA {
-	s := &SLA{}
-	if v, ok := data["freshness"].(string); ok {
-		s.Freshness = &v
-	}
-	if v, ok := data["availability"].(string); ok {
-		s.Availability = &v
-	}
-	if v, ok := data["support_hours"].(string); ok {
-		s.SupportHours = &v
-	}
-	if v, ok := data["escalation_contact"].(string); ok {
-		s.EscalationContact = &v
-	}
-	return s
+	return node
 }
 
-func parseFreshness(data map[string]interface{}) *Freshness {
-	f := &Freshness{}
-	if v, ok := data["last_loaded"].(string); ok {
-		f.LastLoaded = &v
+func convertDocumentationYAML(d *DocumentationYAML) *Documentation {
+	return &Documentation{
+		GlossaryURL:       d.Glossary,
+		RunbookURL:        d.Runbook,
+		OnboardingURL:     d.Onboarding,
+		DataDictionaryURL: d.DataDictionary,
+		APIDocsURL:        d.APIDocs,
+		ArchitectureURL:   d.Architecture,
+		ChangelogURL:      d.Changelog,
+		ContactURL:        d.Contact,
+		AdditionalLinks:   d.Additional,
 	}
-	if v, ok := data["refresh_schedule"].(string); ok {
-		f.RefreshSchedule = &v
-	}
-	if v, ok := data["source_system"].(string); ok {
-		f.SourceSystem = &v
-	}
-	if deps, ok := data["upstream_dependencies"].([]interface{}); ok {
-		for _, d := range deps {
-			if s, ok := d.(string); ok {
-				f.UpstreamDependencies = append(f.UpstreamDependencies, s)
-			}
-		}
-	}
-	return f
 }
 
-// stringFromMap safely extracts a string value from a map
-func stringFromMap(m map[string]interface{}, key string) string {
-	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
+func convertDataSchemaYAML(s *DataSchemaYAML) *DataSchema {
+	schema := &DataSchema{
+		Description:     s.Description,
+		SemanticTags:    s.SemanticTags,
+		PrimaryKey:      s.PrimaryKey,
+		UseCases:        s.UseCases,
+		Examples:        s.Examples,
+		RelatedMonikers: s.RelatedMonikers,
+		Granularity:     s.Granularity,
+		TypicalRowCount: s.TypicalRowCount,
+		UpdateFrequency: s.UpdateFrequency,
+	}
+	for _, c := range s.Columns {
+		schema.Columns = append(schema.Columns, ColumnSchema{
+			Name:         c.Name,
+			DataType:     c.Type,
+			Description:  c.Description,
+			SemanticType: c.SemanticType,
+			Example:      c.Example,
+			Nullable:     c.Nullable,
+			PrimaryKey:   c.PrimaryKey,
+			ForeignKey:   c.ForeignKey,
+		})
+	}
+	return schema
 }
@@ -0,0 +1,500 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCatalogDirRecursive(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "pricing.yaml"), "prices:\n  display_name: Prices\n")
+	writeFile(t, filepath.Join(dir, "risk", "var.yml"), "risk/var:\n  display_name: VaR\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "not a catalog")
+
+	nodes, err := LoadCatalogDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(nodes))
+	}
+	for _, n := range nodes {
+		if n.Path == "risk/var" && n.SourceFile != filepath.Join(dir, "risk", "var.yml") {
+			t.Errorf("expected source file annotation, got %q", n.SourceFile)
+		}
+	}
+}
+
+func TestLoadCatalogDirDuplicatePath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "prices:\n  display_name: A\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "prices:\n  display_name: B\n")
+
+	_, err := LoadCatalogDir(dir)
+	if err == nil {
+		t.Fatal("expected duplicate path error")
+	}
+	if !strings.Contains(err.Error(), "a.yaml") || !strings.Contains(err.Error(), "b.yaml") {
+		t.Errorf("error should name both files: %v", err)
+	}
+}
+
+func TestLoadCatalogsAcceptsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "domains", "pricing.yaml"), "prices:\n  display_name: Prices\n")
+
+	nodes, _, err := LoadCatalogs(ConflictError, filepath.Join(dir, "domains"))
+	if err != nil || len(nodes) != 1 {
+		t.Fatalf("expected 1 node from directory, got %d / %v", len(nodes), err)
+	}
+}
+
+const equivalenceYAML = `
+prices:
+  display_name: Prices
+  ownership:
+    accountable_owner: team-prices
+  sla:
+    freshness: T+1
+prices/equity:
+  display_name: Equity
+  tags: [equities]
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET_DATA
+      table: EQUITY
+      port: 443
+  access_policy:
+    min_filters: 1
+    max_rows_block: 1000
+  data_quality:
+    quality_score: 0.9
+`
+
+const equivalenceJSON = `{
+  "prices": {
+    "display_name": "Prices",
+    "ownership": {"accountable_owner": "team-prices"},
+    "sla": {"freshness": "T+1"}
+  },
+  "prices/equity": {
+    "display_name": "Equity",
+    "tags": ["equities"],
+    "source_binding": {
+      "type": "snowflake",
+      "config": {"account": "acme", "database": "MARKET_DATA", "table": "EQUITY", "port": 443}
+    },
+    "access_policy": {"min_filters": 1, "max_rows_block": 1000},
+    "data_quality": {"quality_score": 0.9}
+  }
+}`
+
+func TestYAMLAndJSONCatalogsAreEquivalent(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "catalog.yaml"), equivalenceYAML)
+	writeFile(t, filepath.Join(dir, "catalog.json"), equivalenceJSON)
+
+	fromYAML, err := Load(filepath.Join(dir, "catalog.yaml"))
+	if err != nil {
+		t.Fatalf("load YAML: %v", err)
+	}
+	fromJSON, err := Load(filepath.Join(dir, "catalog.json"))
+	if err != nil {
+		t.Fatalf("load JSON: %v", err)
+	}
+
+	reg := NewRegistry()
+	reg.RegisterMany(fromYAML)
+	if diff := reg.Diff(fromJSON); !diff.IsEmpty() {
+		t.Errorf("YAML and JSON catalogs differ: %s %+v", diff.Summary(), diff.Modified)
+	}
+}
+
+func TestParseCatalogJSONErrorPath(t *testing.T) {
+	_, err := ParseCatalogJSON([]byte(`{"prices/equity": {"access_policy": {"min_filters": "two"}}}`))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `$["prices/equity"].access_policy.min_filters`) {
+		t.Errorf("error should name the JSON path: %v", err)
+	}
+
+	_, err = ParseCatalogJSON([]byte("{\n  \"prices\": {,}\n}"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("syntax error should report the line: %v", err)
+	}
+}
+
+func TestLoadCatalogInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "root.yaml"), "_include:\n  - domains/pricing.yaml\nroot:\n  display_name: Root\n")
+	writeFile(t, filepath.Join(dir, "domains", "pricing.yaml"), "_include: [fx.yaml]\nprices:\n  display_name: Prices\n")
+	writeFile(t, filepath.Join(dir, "domains", "fx.yaml"), "prices/fx:\n  display_name: FX\n")
+
+	// Includes resolve relative to the including file, not the working directory
+	nodes, err := LoadCatalog(filepath.Join(dir, "root.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, n := range nodes {
+		sources[n.Path] = filepath.Base(n.SourceFile)
+	}
+	if len(nodes) != 3 || sources["prices/fx"] != "fx.yaml" || sources["root"] != "root.yaml" {
+		t.Errorf("unexpected nodes/sources: %v", sources)
+	}
+}
+
+func TestLoadCatalogIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "_include: [b.yaml]\na:\n  display_name: A\n")
+	writeFile(t, filepath.Join(dir, "b.yaml"), "_include: [a.yaml]\nb:\n  display_name: B\n")
+
+	_, err := LoadCatalog(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("expected include cycle error, got %v", err)
+	}
+}
+
+func TestLoadCatalogIncludeDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "root.yaml"), "_include: [other.yaml]\nprices:\n  display_name: Root\n")
+	writeFile(t, filepath.Join(dir, "other.yaml"), "prices:\n  display_name: Other\n")
+
+	_, err := LoadCatalog(filepath.Join(dir, "root.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "root.yaml") || !strings.Contains(err.Error(), "other.yaml") {
+		t.Errorf("expected duplicate error naming both files, got %v", err)
+	}
+}
+
+func TestLoadCatalogIncludeDepthLimit(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i <= maxIncludeDepth+1; i++ {
+		writeFile(t, filepath.Join(dir, fmt.Sprintf("level%d.yaml", i)),
+			fmt.Sprintf("_include: [level%d.yaml]\nnode%d:\n  display_name: N\n", i+1, i))
+	}
+
+	_, err := LoadCatalog(filepath.Join(dir, "level0.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("expected depth limit error, got %v", err)
+	}
+}
+
+func TestParseCatalogRejectsInclude(t *testing.T) {
+	if _, err := ParseCatalog([]byte("_include: [x.yaml]\n")); err == nil {
+		t.Error("expected error for _include without a file context")
+	}
+}
+
+const allBlocksYAML = `
+prices/equity:
+  display_name: Equity
+  sla:
+    freshness: T+1
+    availability: "99.9%"
+    support_hours: 24x7
+    escalation_contact: oncall@example.com
+  data_quality:
+    dq_owner: dq-team
+    quality_score: 95
+    validation_rules: ["not_null(price)"]
+    known_issues: ["late on holidays"]
+    last_validated: "2026-01-01"
+  freshness:
+    last_loaded: "2026-01-02T06:00:00Z"
+    refresh_schedule: "0 6 * * *"
+    source_system: bloomberg
+    upstream_dependencies: [vendor/bbg]
+  documentation:
+    glossary: https://wiki/glossary
+    runbook: https://wiki/runbook
+    additional:
+      dashboard: https://grafana/prices
+  schema:
+    description: Daily equity closes
+    granularity: daily
+    primary_key: [ticker, date]
+    related_monikers: [reference/securities]
+    columns:
+      - name: ticker
+        type: string
+        primary_key: true
+        example: AAPL
+      - name: price
+        type: float
+        nullable: true
+  metadata:
+    team: pricing
+    cost_center: 42
+`
+
+func TestParseCatalogAllBlocks(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(allBlocksYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := nodes[0]
+
+	if node.SLA == nil || *node.SLA.Availability != "99.9%" || *node.SLA.EscalationContact != "oncall@example.com" {
+		t.Errorf("SLA not loaded: %+v", node.SLA)
+	}
+	if dq := node.DataQuality; dq == nil || *dq.QualityScore != 95 || len(dq.KnownIssues) != 1 || *dq.LastValidated != "2026-01-01" {
+		t.Errorf("data quality not loaded: %+v", node.DataQuality)
+	}
+	if f := node.Freshness; f == nil || *f.SourceSystem != "bloomberg" || len(f.UpstreamDependencies) != 1 {
+		t.Errorf("freshness not loaded: %+v", node.Freshness)
+	}
+	if d := node.Documentation; d == nil || *d.RunbookURL != "https://wiki/runbook" || d.AdditionalLinks["dashboard"] == "" {
+		t.Errorf("documentation not loaded: %+v", node.Documentation)
+	}
+
+	schema := node.DataSchema
+	if schema == nil || *schema.Granularity != "daily" || len(schema.PrimaryKey) != 2 || len(schema.RelatedMonikers) != 1 {
+		t.Fatalf("schema not loaded: %+v", schema)
+	}
+	if len(schema.Columns) != 2 || schema.Columns[0].DataType != "string" || !schema.Columns[0].PrimaryKey ||
+		*schema.Columns[0].Example != "AAPL" || !schema.Columns[1].Nullable {
+		t.Errorf("columns not loaded: %+v", schema.Columns)
+	}
+
+	if node.Metadata["team"] != "pricing" || node.Metadata["cost_center"] != 42 {
+		t.Errorf("metadata not loaded: %v", node.Metadata)
+	}
+}
+
+func TestParseCatalogBindingCache(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: bloomberg
+    cache:
+      enabled: true
+      ttl_seconds: 300
+      refresh_interval_seconds: 60
+      refresh_on_startup: true
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := nodes[0].SourceBinding.Cache
+	if c == nil {
+		t.Fatal("expected cache config")
+	}
+	if !c.Enabled || c.TTLSeconds != 300 || c.RefreshIntervalSeconds != 60 || !c.RefreshOnStartup {
+		t.Errorf("unexpected cache config: %+v", c)
+	}
+}
+
+func TestParseCatalogBindingCacheRequiresTTL(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: bloomberg
+    cache:
+      enabled: true
+`))
+	if err == nil || !strings.Contains(err.Error(), "ttl_seconds") {
+		t.Fatalf("expected ttl_seconds error, got %v", err)
+	}
+
+	if _, err := ParseCatalog([]byte("prices/equity:\n  source_binding:\n    type: bloomberg\n    cache:\n      enabled: false\n")); err != nil {
+		t.Errorf("disabled cache should not need a TTL: %v", err)
+	}
+}
+
+func TestParseCatalogAccessPolicyExtras(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+prices/equity:
+  access_policy:
+    allowed_roles: [trader, risk]
+    allowed_hours: [8, 18]
+    require_confirmation_above: 5000
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ap := nodes[0].AccessPolicy
+	if len(ap.AllowedRoles) != 2 || ap.AllowedRoles[0] != "trader" {
+		t.Errorf("unexpected allowed_roles: %v", ap.AllowedRoles)
+	}
+	if ap.AllowedHours == nil || *ap.AllowedHours != [2]int{8, 18} {
+		t.Errorf("unexpected allowed_hours: %v", ap.AllowedHours)
+	}
+	if ap.RequireConfirmationAbove == nil || *ap.RequireConfirmationAbove != 5000 {
+		t.Errorf("unexpected require_confirmation_above: %v", ap.RequireConfirmationAbove)
+	}
+}
+
+func TestParseCatalogAccessPolicyValidation(t *testing.T) {
+	tests := map[string]string{
+		"one hour":     "allowed_hours: [8]",
+		"three hours":  "allowed_hours: [8, 12, 18]",
+		"hour too big": "allowed_hours: [8, 24]",
+		"negative":     "allowed_hours: [-1, 18]",
+		"empty role":   `allowed_roles: [trader, ""]`,
+		"blank role":   `allowed_roles: ["  "]`,
+		"bad pattern":  `blocked_patterns: ["^ALL/(", "x"]`,
+	}
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := "prices/equity:\n  access_policy:\n    " + policy + "\n"
+			if _, err := ParseCatalog([]byte(data)); err == nil {
+				t.Errorf("expected validation error for %s", policy)
+			}
+		})
+	}
+}
+
+func TestParseCatalogInvalidBlockedPattern(t *testing.T) {
+	_, err := ParseCatalog([]byte("prices/equity:\n  access_policy:\n    blocked_patterns: [\"^ALL/(\"]\n"))
+	if err == nil {
+		t.Fatal("expected error for invalid blocked pattern")
+	}
+	for _, want := range []string{"prices/equity", `"^ALL/("`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
+
+func TestParseCatalogLifecycleFields(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+prices/legacy:
+  status: deprecated
+  created_at: "2024-03-01"
+  updated_at: "2025-06-30T09:15:00Z"
+  created_by: alice
+  approved_by: governance
+  deprecation_message: Use prices/equity
+  migration_guide_url: https://wiki/migrate
+  sunset_deadline: "2027-01-01"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := nodes[0]
+	for field, got := range map[string]*string{
+		"created_at":          node.CreatedAt,
+		"updated_at":          node.UpdatedAt,
+		"created_by":          node.CreatedBy,
+		"approved_by":         node.ApprovedBy,
+		"migration_guide_url": node.MigrationGuideURL,
+		"sunset_deadline":     node.SunsetDeadline,
+	} {
+		if got == nil {
+			t.Errorf("%s was not loaded", field)
+		}
+	}
+	if *node.CreatedBy != "alice" || *node.UpdatedAt != "2025-06-30T09:15:00Z" {
+		t.Errorf("unexpected lifecycle fields: created_by=%s updated_at=%s", *node.CreatedBy, *node.UpdatedAt)
+	}
+}
+
+func TestParseCatalogLifecycleValidation(t *testing.T) {
+	tests := map[string]string{
+		"bad created_at":        "status: active\n  created_at: last tuesday",
+		"bad updated_at":        "status: active\n  updated_at: 2025-13-01",
+		"bad sunset":            "status: deprecated\n  sunset_deadline: soon",
+		"sunset while active":   "status: active\n  sunset_deadline: \"2027-01-01\"",
+		"sunset without status": "sunset_deadline: \"2027-01-01\"",
+		"guide while active":    "status: active\n  migration_guide_url: https://wiki/migrate",
+	}
+	for name, fields := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := "prices/equity:\n  " + fields + "\n"
+			_, err := ParseCatalog([]byte(data))
+			if err == nil {
+				t.Errorf("expected validation error for %q", fields)
+			} else if !strings.Contains(err.Error(), "prices/equity") {
+				t.Errorf("error %q should name the node", err)
+			}
+		})
+	}
+}
+
+func TestParseCatalogRecordsUnknownFields(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`prices:
+  display_name: Prices
+prices/equity:
+  dispay_name: Equity
+  source_binding:
+    type: bloomberg
+    confg: {ticker: AAPL}
+`))
+	if err != nil {
+		t.Fatalf("unknown keys should not fail parsing: %v", err)
+	}
+
+	unknown := UnknownFields(nodes)
+	if len(unknown) != 2 {
+		t.Fatalf("expected 2 unknown fields, got %v", unknown)
+	}
+	if u := unknown[0]; u.Path != "prices/equity" || u.Line != 4 || u.Field != "dispay_name" {
+		t.Errorf("unexpected first unknown field: %+v", u)
+	}
+	if u := unknown[1]; u.Path != "prices/equity" || u.Line != 7 || u.Field != "confg" {
+		t.Errorf("unexpected second unknown field: %+v", u)
+	}
+}
+
+func TestLoadCatalogUnknownFieldsNameFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.yaml"), "_include: [risk.yaml]\nprices:\n  display_name: Prices\n")
+	writeFile(t, filepath.Join(dir, "risk.yaml"), "risk:\n  source_bindings:\n    type: oracle\n")
+
+	nodes, err := LoadCatalog(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unknown := UnknownFields(nodes)
+	if len(unknown) != 1 || unknown[0].Field != "source_bindings" {
+		t.Fatalf("expected source_bindings to be reported, got %v", unknown)
+	}
+	if !strings.HasPrefix(unknown[0].String(), filepath.Join(dir, "risk.yaml")+":2:") {
+		t.Errorf("expected file and line in message, got %q", unknown[0].String())
+	}
+}
+
+func TestParseCatalogDuplicatePathsReportLines(t *testing.T) {
+	_, err := ParseCatalog([]byte(`prices:
+  display_name: Prices
+prices/equity:
+  display_name: Equity
+prices:
+  display_name: Prices again
+`))
+	if err == nil {
+		t.Fatal("expected duplicate path error")
+	}
+	if !strings.Contains(err.Error(), "'prices' at line 5 (first defined at line 1)") {
+		t.Errorf("expected both line numbers, got %v", err)
+	}
+}
+
+func TestLoadCatalogRecordsSourceLine(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.yaml"), "prices:\n  display_name: Prices\nprices/equity:\n  display_name: Equity\n")
+
+	nodes, err := LoadCatalog(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes[1].Path != "prices/equity" || nodes[1].SourceLine != 3 {
+		t.Errorf("expected prices/equity at line 3, got %s at %d", nodes[1].Path, nodes[1].SourceLine)
+	}
+}
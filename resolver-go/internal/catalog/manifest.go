@@ -0,0 +1,216 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestExt is the extension of a catalog file's sidecar manifest:
+// catalog.yaml is verified against catalog.sha256 in the same directory
+const ManifestExt = ".sha256"
+
+// DirManifestName is the manifest verifying a catalog directory
+const DirManifestName = "catalog" + ManifestExt
+
+// Manifest lists the approved SHA-256 digest of each catalog file, in the
+// format written by sha256sum ("<hex>  <file>"). File names are relative to
+// the manifest's directory.
+type Manifest struct {
+	Path   string            `json:"path"`
+	Digest string            `json:"digest"` // SHA-256 of the manifest itself
+	Files  map[string]string `json:"-"`      // Absolute path -> expected hex digest
+}
+
+// ManifestError reports catalog files that do not match their manifest
+type ManifestError struct {
+	Manifest string
+	Problems []string
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("catalog does not match manifest %s: %s", e.Manifest, strings.Join(e.Problems, "; "))
+}
+
+// ManifestPath returns where the manifest for a catalog file or directory
+// would be. URLs have no manifest and return "".
+func ManifestPath(source string) string {
+	if IsRemoteCatalog(source) {
+		return ""
+	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return filepath.Join(source, DirManifestName)
+	}
+	return strings.TrimSuffix(source, filepath.Ext(source)) + ManifestExt
+}
+
+// FindManifest loads the manifest for a catalog source, or returns nil if it
+// has none
+func FindManifest(source string) (*Manifest, error) {
+	path := ManifestPath(source)
+	if path == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return LoadManifest(path)
+}
+
+// LoadManifest parses a sha256sum-style manifest. Blank lines and lines
+// starting with # are ignored.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	m := &Manifest{Path: path, Digest: hex.EncodeToString(sum[:]), Files: make(map[string]string)}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolve manifest path: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		digest, name, ok := strings.Cut(text, " ")
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*") // "*" marks binary mode
+		if _, err := hex.DecodeString(digest); !ok || err != nil || len(digest) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected \"<sha256>  <file>\"", path, line)
+		}
+		file := filepath.FromSlash(name)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		m.Files[filepath.Clean(file)] = strings.ToLower(digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return m, nil
+}
+
+// VerifyFiles hashes every file listed in the manifest and returns a
+// *ManifestError listing each missing or modified file
+func (m *Manifest) VerifyFiles() error {
+	_, err := m.verifyFiles()
+	return err
+}
+
+// verifiedFiles holds the contents of the files of a manifest, as read and
+// hashed by verifyFiles
+type verifiedFiles map[string][]byte
+
+// verifyFiles reads and hashes every file listed in the manifest, returning
+// their contents so they can be parsed without being read again
+func (m *Manifest) verifyFiles() (verifiedFiles, error) {
+	files := make([]string, 0, len(m.Files))
+	for file := range m.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	contents := make(verifiedFiles, len(files))
+	problems := make([]string, 0)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); got != m.Files[file] {
+			problems = append(problems, fmt.Sprintf("%s: sha256 %s, manifest has %s", file, got, m.Files[file]))
+			continue
+		}
+		contents[file] = data
+	}
+	if len(problems) > 0 {
+		return nil, &ManifestError{Manifest: m.Path, Problems: problems}
+	}
+	return contents, nil
+}
+
+// read returns the verified contents of a listed file. Files the manifest
+// does not list are read from disk; checkCoverage refuses catalog files
+// among them.
+func (v verifiedFiles) read(path string) ([]byte, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		if data, ok := v[abs]; ok {
+			return data, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// checkCoverage returns a *ManifestError if source, or a file that nodes were
+// loaded from, is not listed in the manifest
+func (m *Manifest) checkCoverage(source string, nodes []*CatalogNode) error {
+	files := make([]string, 0)
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		files = append(files, source)
+	}
+	for _, node := range nodes {
+		if node.SourceFile != "" {
+			files = append(files, node.SourceFile)
+		}
+	}
+
+	problems := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		if _, ok := m.Files[abs]; !ok {
+			problems = append(problems, fmt.Sprintf("%s is not listed", file))
+		}
+	}
+	if len(problems) > 0 {
+		return &ManifestError{Manifest: m.Path, Problems: problems}
+	}
+	return nil
+}
+
+// loadVerified loads a catalog source, first verifying it against its
+// manifest if it has one. Listed files are read once: the bytes hashed are
+// the bytes parsed, so a file replaced in between can't be loaded unverified.
+// Every file the catalog is loaded from must be listed.
+func loadVerified(source string) ([]*CatalogNode, *Manifest, error) {
+	manifest, err := FindManifest(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	read := os.ReadFile
+	if manifest != nil {
+		files, err := manifest.verifyFiles()
+		if err != nil {
+			return nil, nil, err
+		}
+		read = files.read
+	}
+
+	nodes, err := load(source, read)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest != nil {
+		if err := manifest.checkCoverage(source, nodes); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nodes, manifest, nil
+}
@@ -0,0 +1,165 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeManifest writes a sha256sum-style manifest for files, named relative to dir
+func writeManifest(t *testing.T, path, dir string, files ...string) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("# approved catalog\n")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, _ := filepath.Rel(dir, file)
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&b, "%s  %s\n", hex.EncodeToString(sum[:]), filepath.ToSlash(rel))
+	}
+	writeFile(t, path, b.String())
+}
+
+func TestLoadCatalogsVerifiesManifest(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+	writeManifest(t, filepath.Join(dir, "catalog.sha256"), dir, file)
+
+	if _, _, err := LoadCatalogs(ConflictError, file); err != nil {
+		t.Fatalf("verified catalog should load: %v", err)
+	}
+
+	writeFile(t, file, "prices:\n  display_name: Tampered\n")
+	_, _, err := LoadCatalogs(ConflictError, file)
+	var manifestErr *ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("expected *ManifestError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "catalog.yaml: sha256") {
+		t.Errorf("error should name the modified file: %v", err)
+	}
+}
+
+func TestVerifiedLoadParsesTheHashedBytes(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+	writeManifest(t, filepath.Join(dir, "catalog.sha256"), dir, file)
+
+	manifest, err := FindManifest(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := manifest.verifyFiles()
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	// Replaced between hashing and parsing
+	writeFile(t, file, "prices:\n  display_name: Tampered\n")
+
+	nodes, err := load(file, files.read)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].DisplayName != "Prices" {
+		t.Errorf("expected the verified contents to be parsed, got %+v", nodes)
+	}
+}
+
+func TestManifestMustListIncludedFiles(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "catalog.yaml")
+	extra := filepath.Join(dir, "extra.yaml")
+	writeFile(t, main, "_include: [extra.yaml]\nprices:\n  display_name: Prices\n")
+	writeFile(t, extra, "rates:\n  display_name: Rates\n")
+	writeManifest(t, filepath.Join(dir, "catalog.sha256"), dir, main)
+
+	_, _, err := LoadCatalogs(ConflictError, main)
+	if err == nil || !strings.Contains(err.Error(), "extra.yaml is not listed") {
+		t.Fatalf("expected unlisted include to fail, got %v", err)
+	}
+
+	writeManifest(t, filepath.Join(dir, "catalog.sha256"), dir, main, extra)
+	if _, _, err := LoadCatalogs(ConflictError, main); err != nil {
+		t.Fatalf("fully listed catalog should load: %v", err)
+	}
+}
+
+func TestDirectoryManifest(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "team", "prices.yaml")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+	writeManifest(t, filepath.Join(dir, DirManifestName), dir, file)
+
+	if _, _, err := LoadCatalogs(ConflictError, dir); err != nil {
+		t.Fatalf("verified directory should load: %v", err)
+	}
+
+	os.Remove(file)
+	if _, _, err := LoadCatalogs(ConflictError, dir); err == nil {
+		t.Error("a missing listed file should fail verification")
+	}
+}
+
+func TestLoadManifestRejectsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.sha256")
+	writeFile(t, path, "not-a-digest  catalog.yaml\n")
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("expected error with line number, got %v", err)
+	}
+}
+
+func TestReloadManagerRecordsManifestAndVersion(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	manifest := filepath.Join(dir, "catalog.sha256")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+	writeManifest(t, manifest, dir, file)
+
+	reg := NewRegistry()
+	m := NewReloadManager(reg, ConflictError, file)
+	if _, err := m.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	status := m.Status()
+	if len(status.Manifests) != 1 || status.Manifests[0].Path != manifest || status.Manifests[0].Digest == "" {
+		t.Errorf("expected the manifest to be recorded, got %+v", status.Manifests)
+	}
+	if reg.Version() == "" || reg.Version() != status.Checksum {
+		t.Errorf("registry version %q should be the catalog checksum %q", reg.Version(), status.Checksum)
+	}
+
+	// An unapproved edit is rejected and the approved catalog keeps serving
+	version := reg.Version()
+	writeFile(t, file, "prices:\n  display_name: Unapproved\n")
+	if _, err := m.Reload(); err == nil {
+		t.Fatal("expected manifest mismatch to reject the reload")
+	}
+	if reg.Get("prices").DisplayName != "Prices" || reg.Version() != version {
+		t.Error("rejected reload changed the live catalog")
+	}
+}
+
+func TestRegistryVersionClearedByModification(t *testing.T) {
+	reg := NewRegistry()
+	reg.AtomicReplaceVersion([]*CatalogNode{{Path: "prices"}}, "abc123")
+	if reg.Version() != "abc123" {
+		t.Fatalf("expected version abc123, got %q", reg.Version())
+	}
+	reg.Register(&CatalogNode{Path: "rates"})
+	if reg.Version() != "" {
+		t.Errorf("a modified catalog should not keep its loaded version, got %q", reg.Version())
+	}
+}
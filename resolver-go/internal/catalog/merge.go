@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConflictPolicy controls how Merge handles a path defined by more than one catalog
+type ConflictPolicy string
+
+const (
+	ConflictError       ConflictPolicy = "error"        // Any conflict fails the merge
+	ConflictFirstWins   ConflictPolicy = "first-wins"   // Keep the earliest definition
+	ConflictLastWins    ConflictPolicy = "last-wins"    // Keep the latest definition
+	ConflictMergeFields ConflictPolicy = "merge-fields" // Later non-empty fields override earlier ones
+)
+
+// ParseConflictPolicy validates a policy name. An empty name means ConflictError.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(name); p {
+	case "":
+		return ConflictError, nil
+	case ConflictError, ConflictFirstWins, ConflictLastWins, ConflictMergeFields:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q (want error, first-wins, last-wins, or merge-fields)", name)
+	}
+}
+
+// Conflict describes a path defined by more than one catalog
+type Conflict struct {
+	Path     string   `json:"path"`
+	Catalogs []int    `json:"catalogs"`          // Indexes of the defining catalogs, in order
+	Sources  []string `json:"sources,omitempty"` // Catalog file names, when known
+
+	// Resolution describes how the policy resolved the conflict; empty if unresolved
+	Resolution string `json:"resolution,omitempty"`
+}
+
+func (c Conflict) String() string {
+	where := make([]string, len(c.Catalogs))
+	for i, idx := range c.Catalogs {
+		if i < len(c.Sources) {
+			where[i] = c.Sources[i]
+		} else {
+			where[i] = fmt.Sprintf("catalog #%d", idx)
+		}
+	}
+	msg := fmt.Sprintf("'%s' defined in %s", c.Path, strings.Join(where, ", "))
+	if c.Resolution != "" {
+		msg += " (" + c.Resolution + ")"
+	}
+	return msg
+}
+
+// Merge combines several catalogs into one node list. Every path defined more
+// than once is reported as a Conflict. Under ConflictError the merge fails if
+// there are any conflicts; the other policies resolve them and return no error.
+// Nodes are returned in order of first definition. Input nodes are not modified.
+func Merge(policy ConflictPolicy, catalogs ...[]*CatalogNode) ([]*CatalogNode, []Conflict, error) {
+	if _, err := ParseConflictPolicy(string(policy)); err != nil {
+		return nil, nil, err
+	}
+
+	merged := make(map[string]*CatalogNode)
+	definedIn := make(map[string][]int)
+	order := make([]string, 0)
+
+	for i, nodes := range catalogs {
+		for _, node := range nodes {
+			existing, ok := merged[node.Path]
+			if !ok {
+				order = append(order, node.Path)
+			}
+			if n := len(definedIn[node.Path]); n == 0 || definedIn[node.Path][n-1] != i {
+				definedIn[node.Path] = append(definedIn[node.Path], i)
+			}
+
+			switch {
+			case !ok:
+				merged[node.Path] = node
+			case policy == ConflictLastWins:
+				merged[node.Path] = node
+			case policy == ConflictMergeFields:
+				merged[node.Path] = mergeNodeFields(existing, node)
+			}
+		}
+	}
+
+	conflicts := make([]Conflict, 0)
+	for _, p := range order {
+		if len(definedIn[p]) < 2 {
+			continue
+		}
+		c := Conflict{Path: p, Catalogs: definedIn[p]}
+		switch policy {
+		case ConflictFirstWins:
+			c.Resolution = fmt.Sprintf("kept definition from catalog #%d", c.Catalogs[0])
+		case ConflictLastWins:
+			c.Resolution = fmt.Sprintf("kept definition from catalog #%d", c.Catalogs[len(c.Catalogs)-1])
+		case ConflictMergeFields:
+			c.Resolution = "merged fields"
+		}
+		conflicts = append(conflicts, c)
+	}
+
+	if policy == ConflictError && len(conflicts) > 0 {
+		return nil, conflicts, fmt.Errorf("%d paths defined in more than one catalog", len(conflicts))
+	}
+
+	result := make([]*CatalogNode, 0, len(order))
+	for _, p := range order {
+		result = append(result, merged[p])
+	}
+	return result, conflicts, nil
+}
+
+// mergeNodeFields returns a copy of base with every non-zero field of overlay
+// applied on top. Fields are replaced whole, not merged recursively.
+func mergeNodeFields(base, overlay *CatalogNode) *CatalogNode {
+	result := *base
+	dst := reflect.ValueOf(&result).Elem()
+	src := reflect.ValueOf(overlay).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if f := src.Field(i); !f.IsZero() {
+			dst.Field(i).Set(f)
+		}
+	}
+	return &result
+}
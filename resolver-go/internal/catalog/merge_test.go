@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func mergeFixture() ([]*CatalogNode, []*CatalogNode) {
+	a := makeNode("prices", "Prices", "From A", NodeStatusActive, false)
+	a.Ownership = &Ownership{AccountableOwner: strPtr("team-a")}
+	b := makeNode("prices", "", "From B", NodeStatusActive, false)
+	b.Tags = []string{"market"}
+
+	return []*CatalogNode{a, makeNode("prices/equity", "Equity", "", NodeStatusActive, true)},
+		[]*CatalogNode{b, makeNode("risk", "Risk", "", NodeStatusActive, false)}
+}
+
+func TestMergeNoConflicts(t *testing.T) {
+	nodes, conflicts, err := Merge(ConflictError,
+		[]*CatalogNode{makeNode("prices", "Prices", "", NodeStatusActive, false)},
+		[]*CatalogNode{makeNode("risk", "Risk", "", NodeStatusActive, false)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 || len(conflicts) != 0 {
+		t.Errorf("expected 2 nodes and no conflicts, got %d/%d", len(nodes), len(conflicts))
+	}
+}
+
+func TestMergeErrorPolicy(t *testing.T) {
+	a, b := mergeFixture()
+	nodes, conflicts, err := Merge(ConflictError, a, b)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if nodes != nil {
+		t.Error("expected no nodes on error")
+	}
+	if len(conflicts) != 1 || conflicts[0].Path != "prices" || conflicts[0].Resolution != "" {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+}
+
+func TestMergeFirstAndLastWins(t *testing.T) {
+	a, b := mergeFixture()
+
+	nodes, conflicts, err := Merge(ConflictFirstWins, a, b)
+	if err != nil || len(conflicts) != 1 {
+		t.Fatalf("expected resolved conflict, got %v / %v", conflicts, err)
+	}
+	if nodes[0].Description != "From A" {
+		t.Errorf("first-wins kept %q", nodes[0].Description)
+	}
+
+	nodes, _, _ = Merge(ConflictLastWins, a, b)
+	if nodes[0].Description != "From B" || nodes[0].Ownership != nil {
+		t.Errorf("last-wins should keep B whole, got %+v", nodes[0])
+	}
+	if len(nodes) != 3 {
+		t.Errorf("expected 3 nodes, got %d", len(nodes))
+	}
+}
+
+func TestMergeFields(t *testing.T) {
+	a, b := mergeFixture()
+	nodes, _, err := Merge(ConflictMergeFields, a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	merged := nodes[0]
+	if merged.DisplayName != "Prices" {
+		t.Errorf("empty display name in B should not override, got %q", merged.DisplayName)
+	}
+	if merged.Description != "From B" || len(merged.Tags) != 1 {
+		t.Errorf("B's fields should override: %+v", merged)
+	}
+	if merged.Ownership == nil || *merged.Ownership.AccountableOwner != "team-a" {
+		t.Error("A's ownership should survive")
+	}
+	if a[0].Description != "From A" {
+		t.Error("inputs must not be modified")
+	}
+}
+
+func TestMergeUnknownPolicy(t *testing.T) {
+	if _, _, err := Merge("newest"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestLoadCatalogsReportsFileNames(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.yaml")
+	second := filepath.Join(dir, "b.yaml")
+	writeFile(t, first, "prices:\n  display_name: A\n")
+	writeFile(t, second, "prices:\n  display_name: B\n")
+
+	_, conflicts, err := LoadCatalogs(ConflictError, first, second)
+	if err == nil || len(conflicts) != 1 {
+		t.Fatalf("expected one unresolved conflict, got %v / %v", conflicts, err)
+	}
+	if got := conflicts[0].Sources; len(got) != 2 || got[0] != first || got[1] != second {
+		t.Errorf("expected both file names, got %v", got)
+	}
+
+	reg := NewRegistry()
+	reg.Register(makeNode("existing", "Existing", "", NodeStatusActive, false))
+	if _, _, err := ReloadCatalogs(reg, ConflictError, first, second); err == nil {
+		t.Fatal("expected reload to fail")
+	}
+	if !reg.Exists("existing") {
+		t.Error("registry replaced despite unresolved conflicts")
+	}
+}
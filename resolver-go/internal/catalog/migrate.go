@@ -0,0 +1,246 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSchemaVersion is the catalog format version this binary reads natively.
+// Older catalogs are upgraded by schemaMigrations as they are parsed.
+const CurrentSchemaVersion = 2
+
+// schemaVersionKey is the reserved top-level key holding a catalog's format version.
+// Catalogs without it are version 1.
+const schemaVersionKey = "schema_version"
+
+// keyRename renames a key inside a node's block ("" is the node itself)
+type keyRename struct {
+	Block string
+	Old   string
+	New   string
+}
+
+// placeholderRename renames a {placeholder} in the queries of a node's
+// source binding config
+type placeholderRename struct {
+	Old string
+	New string
+}
+
+// queryConfigKeys are the source binding config keys whose values are
+// queries with placeholders
+var queryConfigKeys = []string{"query", VersionsQueryKey}
+
+// schemaMigration upgrades a catalog node from version From to From+1
+type schemaMigration struct {
+	From         int
+	Description  string
+	Renames      []keyRename
+	Placeholders []placeholderRename
+}
+
+// schemaMigrations holds one entry per format version below CurrentSchemaVersion, in order
+var schemaMigrations = []schemaMigration{
+	{
+		From:        1,
+		Description: "documentation links drop the _url suffix of the model field names, and the tenor placeholders of date@ versions become lookback",
+		Renames: []keyRename{
+			{"documentation", "glossary_url", "glossary"},
+			{"documentation", "runbook_url", "runbook"},
+			{"documentation", "onboarding_url", "onboarding"},
+			{"documentation", "data_dictionary_url", "data_dictionary"},
+			{"documentation", "api_docs_url", "api_docs"},
+			{"documentation", "architecture_url", "architecture"},
+			{"documentation", "changelog_url", "changelog"},
+			{"documentation", "contact_url", "contact"},
+		},
+		Placeholders: []placeholderRename{
+			{"tenor_start", "lookback_start"},
+			{"tenor_end", "lookback_end"},
+		},
+	},
+}
+
+// checkSchemaVersion validates a declared catalog format version
+func checkSchemaVersion(version int) error {
+	if version < 1 {
+		return fmt.Errorf("%s must be at least 1, got %d", schemaVersionKey, version)
+	}
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf("%s %d is newer than this resolver supports (max %d); upgrade the resolver",
+			schemaVersionKey, version, CurrentSchemaVersion)
+	}
+	return nil
+}
+
+// pendingMigrations returns the migrations that upgrade a catalog of the given version
+func pendingMigrations(version int) []schemaMigration {
+	pending := make([]schemaMigration, 0)
+	for _, m := range schemaMigrations {
+		if m.From >= version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// migratedKeys returns the old key names rewritten by the pending migrations,
+// so strict mode does not report them as unknown
+func migratedKeys(version int) map[string]bool {
+	keys := make(map[string]bool)
+	for _, m := range pendingMigrations(version) {
+		for _, r := range m.Renames {
+			keys[r.Old] = true
+		}
+	}
+	return keys
+}
+
+// renamePlaceholders applies the placeholder renames of m to a query
+func (m schemaMigration) renamePlaceholders(query string) string {
+	for _, r := range m.Placeholders {
+		query = strings.ReplaceAll(query, "{"+r.Old+"}", "{"+r.New+"}")
+	}
+	return query
+}
+
+// migrateYAMLNode upgrades a node's YAML mapping in place. Renamed keys keep
+// their line numbers.
+func migrateYAMLNode(path string, node *yaml.Node, version int) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var config *yaml.Node
+	if binding := yamlMappingValue(node, "source_binding"); binding != nil && binding.Kind == yaml.MappingNode {
+		config = yamlMappingValue(binding, "config")
+	}
+	for _, m := range pendingMigrations(version) {
+		if config != nil && config.Kind == yaml.MappingNode {
+			for _, key := range queryConfigKeys {
+				if query := yamlMappingValue(config, key); query != nil && query.Kind == yaml.ScalarNode {
+					query.Value = m.renamePlaceholders(query.Value)
+				}
+			}
+		}
+		for _, r := range m.Renames {
+			block := node
+			if r.Block != "" {
+				block = yamlMappingValue(node, r.Block)
+			}
+			if block == nil || block.Kind != yaml.MappingNode {
+				continue
+			}
+			old := yamlMappingKey(block, r.Old)
+			if old == nil {
+				continue
+			}
+			if yamlMappingKey(block, r.New) != nil {
+				return fmt.Errorf("%s: %s sets both %s and %s", path, r.Block, r.Old, r.New)
+			}
+			old.Value = r.New
+		}
+	}
+	return nil
+}
+
+// migrateJSONNode upgrades a node's JSON definition, returning the rewritten JSON
+func migrateJSONNode(path string, msg json.RawMessage, version int) (json.RawMessage, error) {
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &node); err != nil || node == nil {
+		return msg, nil // Reported with its JSON path by the regular decode
+	}
+
+	changed := false
+	for _, m := range pendingMigrations(version) {
+		if migrated, ok := migrateJSONQueries(node["source_binding"], m); ok {
+			node["source_binding"] = migrated
+			changed = true
+		}
+		for _, r := range m.Renames {
+			block := node
+			if r.Block != "" {
+				block = nil
+				if err := json.Unmarshal(node[r.Block], &block); err != nil || block == nil {
+					continue
+				}
+			}
+			value, ok := block[r.Old]
+			if !ok {
+				continue
+			}
+			if _, dup := block[r.New]; dup {
+				return nil, fmt.Errorf("%s: %s sets both %s and %s", path, r.Block, r.Old, r.New)
+			}
+			delete(block, r.Old)
+			block[r.New] = value
+			if r.Block != "" {
+				raw, err := json.Marshal(block)
+				if err != nil {
+					return nil, err
+				}
+				node[r.Block] = raw
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return msg, nil
+	}
+	return json.Marshal(node)
+}
+
+// migrateJSONQueries applies the placeholder renames of m to the queries of
+// a source binding's JSON, reporting whether any changed
+func migrateJSONQueries(msg json.RawMessage, m schemaMigration) (json.RawMessage, bool) {
+	var binding map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &binding); err != nil || binding == nil {
+		return msg, false
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(binding["config"], &config); err != nil || config == nil {
+		return msg, false
+	}
+	changed := false
+	for _, key := range queryConfigKeys {
+		var query string
+		if err := json.Unmarshal(config[key], &query); err != nil {
+			continue
+		}
+		if renamed := m.renamePlaceholders(query); renamed != query {
+			config[key], _ = json.Marshal(renamed)
+			changed = true
+		}
+	}
+	if !changed {
+		return msg, false
+	}
+	binding["config"], _ = json.Marshal(config)
+	migrated, err := json.Marshal(binding)
+	if err != nil {
+		return msg, false
+	}
+	return migrated, true
+}
+
+// yamlMappingKey returns the key node for name in a mapping, or nil
+func yamlMappingKey(mapping *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i]
+		}
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value node for name in a mapping, or nil
+func yamlMappingValue(mapping *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
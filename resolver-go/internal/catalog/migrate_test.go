@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const v1Catalog = `
+prices/equity:
+  display_name: Equity
+  documentation:
+    glossary_url: https://wiki.example.com/glossary
+    runbook_url: https://wiki.example.com/runbook
+    additional:
+      dashboard: https://grafana.example.com/prices
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET
+      query: "SELECT * FROM PRICES WHERE asof BETWEEN '{tenor_start}' AND '{tenor_end}'"
+      versions_query: "SELECT DISTINCT asof FROM PRICES WHERE asof >= '{tenor_start}'"
+`
+
+const v2Catalog = `
+schema_version: 2
+prices/equity:
+  display_name: Equity
+  documentation:
+    glossary: https://wiki.example.com/glossary
+    runbook: https://wiki.example.com/runbook
+    additional:
+      dashboard: https://grafana.example.com/prices
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET
+      query: "SELECT * FROM PRICES WHERE asof BETWEEN '{lookback_start}' AND '{lookback_end}'"
+      versions_query: "SELECT DISTINCT asof FROM PRICES WHERE asof >= '{lookback_start}'"
+`
+
+func TestMigrateV1CatalogMatchesV2(t *testing.T) {
+	v1, err := ParseCatalog([]byte(v1Catalog))
+	if err != nil {
+		t.Fatalf("parse v1: %v", err)
+	}
+	v2, err := ParseCatalog([]byte(v2Catalog))
+	if err != nil {
+		t.Fatalf("parse v2: %v", err)
+	}
+
+	reg := NewRegistry()
+	reg.RegisterMany(v1)
+	if diff := reg.Diff(v2); !diff.IsEmpty() {
+		t.Errorf("v1 and v2 catalogs differ: %+v", diff)
+	}
+	if doc := v1[0].Documentation; doc == nil || doc.RunbookURL == nil || *doc.RunbookURL != "https://wiki.example.com/runbook" {
+		t.Errorf("expected migrated runbook link, got %+v", doc)
+	}
+	if query := v1[0].SourceBinding.Config["query"]; query != "SELECT * FROM PRICES WHERE asof BETWEEN '{lookback_start}' AND '{lookback_end}'" {
+		t.Errorf("expected tenor placeholders renamed to lookback, got %v", query)
+	}
+	if unknown := UnknownFields(v1); len(unknown) != 0 {
+		t.Errorf("migrated keys should not be reported as unknown: %v", unknown)
+	}
+}
+
+func TestMigrateRepoCatalogAsV1(t *testing.T) {
+	// The repository catalog has no schema_version, so it loads as v1; its
+	// nodes must be those of the same file declared v2
+	data, err := os.ReadFile("../../../catalog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("parse as v1: %v", err)
+	}
+	v2, err := ParseCatalog(append([]byte("schema_version: 2\n"), data...))
+	if err != nil {
+		t.Fatalf("parse as v2: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(v1)
+	if diff := reg.Diff(v2); !diff.IsEmpty() {
+		t.Errorf("the catalog reads differently as v1 and v2: %+v", diff)
+	}
+	if unknown := UnknownFields(v1); len(unknown) != 0 {
+		t.Errorf("expected no unknown keys, got %v", unknown)
+	}
+}
+
+func TestMigrateV1JSONCatalog(t *testing.T) {
+	nodes, err := ParseCatalogJSON([]byte(`{
+  "schema_version": 1,
+  "prices/equity": {"documentation": {"contact_url": "mailto:prices@example.com"}}
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc := nodes[0].Documentation; doc == nil || doc.ContactURL == nil {
+		t.Errorf("expected migrated contact link, got %+v", doc)
+	}
+
+	nodes, err = ParseCatalogJSON([]byte(`{
+  "prices/equity": {"source_binding": {"type": "oracle", "config": {"dsn": "secret://env/DSN", "query": "SELECT * FROM p WHERE d >= '{tenor_start}'"}}}
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query := nodes[0].SourceBinding.Config["query"]; query != "SELECT * FROM p WHERE d >= '{lookback_start}'" {
+		t.Errorf("expected the tenor placeholder renamed, got %v", query)
+	}
+}
+
+func TestSchemaVersionErrors(t *testing.T) {
+	tests := map[string]string{
+		"newer than supported": "schema_version: 99\nprices:\n  display_name: Prices\n",
+		"not an integer":       "schema_version: two\nprices:\n  display_name: Prices\n",
+		"both old and new key": "prices:\n  documentation:\n    glossary: a\n    glossary_url: b\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCatalog([]byte(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	_, err := ParseCatalogJSON([]byte(`{"schema_version": 3}`))
+	if err == nil || !strings.Contains(err.Error(), "newer than this resolver supports") {
+		t.Errorf("expected newer-version error for JSON, got %v", err)
+	}
+}
+
+func TestV2CatalogKeepsTenorPlaceholders(t *testing.T) {
+	nodes, err := ParseCatalog([]byte("schema_version: 2\nprices:\n  source_binding:\n    type: oracle\n    config: {dsn: x, query: \"SELECT '{tenor_start}'\"}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query := nodes[0].SourceBinding.Config["query"]; query != "SELECT '{tenor_start}'" {
+		t.Errorf("expected a v2 query to be left alone, got %v", query)
+	}
+}
+
+func TestV2CatalogKeepsOldKeysUnknown(t *testing.T) {
+	nodes, err := ParseCatalog([]byte("schema_version: 2\nprices:\n  documentation:\n    glossary_url: a\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unknown := UnknownFields(nodes); len(unknown) != 1 || unknown[0].Field != "glossary_url" {
+		t.Errorf("expected glossary_url to be unknown in a v2 catalog, got %v", unknown)
+	}
+}
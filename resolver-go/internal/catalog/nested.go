@@ -0,0 +1,230 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// childrenKey nests child nodes under a parent, keyed by the child's segments
+// relative to the parent. Nested and flat path keys may be mixed in one file.
+const childrenKey = "children"
+
+// catalogTreeNodeYAML is a node in the nested format: the node's own fields
+// plus its children
+type catalogTreeNodeYAML struct {
+	CatalogNodeYAML `yaml:",inline"`
+	Children        map[string]*catalogTreeNodeYAML `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// ExportStyle selects how an exported catalog lays out paths
+type ExportStyle string
+
+const (
+	ExportFlat   ExportStyle = "flat"   // Every node keyed by its full path
+	ExportNested ExportStyle = "nested" // Nodes nested under their nearest exported ancestor
+)
+
+// ParseExportStyle validates a style name. An empty name means ExportFlat.
+func ParseExportStyle(name string) (ExportStyle, error) {
+	switch s := ExportStyle(name); s {
+	case "":
+		return ExportFlat, nil
+	case ExportFlat, ExportNested:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown export style %q (want flat or nested)", name)
+	}
+}
+
+// yamlEntry is a node definition found in a YAML catalog, flat or nested
+type yamlEntry struct {
+	path   string
+	key    *yaml.Node
+	value  *yaml.Node
+	parent string // Path the entry is nested under, "" for a top-level key
+}
+
+// flattenYAMLChildren lists the node definitions of a catalog mapping in
+// document order, detaching each children block from its parent and turning
+// it into entries with full paths. Reserved top-level keys are listed as-is.
+func flattenYAMLChildren(mapping *yaml.Node) ([]yamlEntry, error) {
+	entries := make([]yamlEntry, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if isReservedKey(key.Value) {
+			entries = append(entries, yamlEntry{path: key.Value, key: key, value: value})
+			continue
+		}
+		var err error
+		if entries, err = appendYAMLEntry(entries, key.Value, key, value, ""); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// appendYAMLEntry appends the entry for path, followed by its nested children
+func appendYAMLEntry(entries []yamlEntry, path string, key, value *yaml.Node, parent string) ([]yamlEntry, error) {
+	entries = append(entries, yamlEntry{path: path, key: key, value: value, parent: parent})
+	if value.Kind != yaml.MappingNode {
+		return entries, nil
+	}
+
+	var children *yaml.Node
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value == childrenKey {
+			children = value.Content[i+1]
+			value.Content = append(value.Content[:i:i], value.Content[i+2:]...)
+			break
+		}
+	}
+	if children == nil || children.Tag == "!!null" {
+		return entries, nil
+	}
+	if children.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s.%s must be a mapping of child segment to node", children.Line, path, childrenKey)
+	}
+
+	for i := 0; i+1 < len(children.Content); i += 2 {
+		childKey, childValue := children.Content[i], children.Content[i+1]
+		segment := strings.Trim(childKey.Value, "/")
+		if segment == "" {
+			return nil, fmt.Errorf("line %d: %s.%s has an empty child segment", childKey.Line, path, childrenKey)
+		}
+		var err error
+		if entries, err = appendYAMLEntry(entries, path+"/"+segment, childKey, childValue, path); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// yamlEntryLines maps every line of each node definition to its path, so a
+// line reported by the YAML decoder can be traced back to the node it is in.
+// Children are already detached, so a parent's fields after its children
+// block still map to the parent.
+func yamlEntryLines(entries []yamlEntry) map[int]string {
+	lines := make(map[int]string)
+	var walk func(n *yaml.Node, path string)
+	walk = func(n *yaml.Node, path string) {
+		lines[n.Line] = path
+		for _, c := range n.Content {
+			walk(c, path)
+		}
+	}
+	for _, e := range entries {
+		if isReservedKey(e.path) {
+			continue
+		}
+		walk(e.key, e.path)
+		walk(e.value, e.path)
+	}
+	return lines
+}
+
+// flattenJSONChildren is flattenYAMLChildren for a JSON catalog, returning
+// every node definition keyed by its full path. A path defined both nested
+// and flat, or nested twice, is an error naming both JSON locations.
+func flattenJSONChildren(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	flat := make(map[string]json.RawMessage, len(raw))
+	defined := make(map[string]string, len(raw))
+	paths := make([]string, 0, len(raw))
+	for path := range raw {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := addJSONEntry(flat, defined, path, fmt.Sprintf("$[%q]", path), raw[path]); err != nil {
+			return nil, err
+		}
+	}
+	return flat, nil
+}
+
+// addJSONEntry adds the definition of path at JSON location where, followed by its children
+func addJSONEntry(flat map[string]json.RawMessage, defined map[string]string, path, where string, msg json.RawMessage) error {
+	if first, dup := defined[path]; dup {
+		return fmt.Errorf("%s defines '%s', which is also defined at %s", where, path, first)
+	}
+	defined[path] = where
+
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &node); err != nil || node == nil {
+		flat[path] = msg // Reported with its JSON path by the regular decode
+		return nil
+	}
+	childrenMsg, ok := node[childrenKey]
+	if !ok {
+		flat[path] = msg
+		return nil
+	}
+	delete(node, childrenKey)
+	own, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("%s: %w", where, err)
+	}
+	flat[path] = own
+
+	var children map[string]json.RawMessage
+	if err := json.Unmarshal(childrenMsg, &children); err != nil {
+		return fmt.Errorf("%s.%s must be an object of child segment to node", where, childrenKey)
+	}
+	segments := make([]string, 0, len(children))
+	for segment := range children {
+		segments = append(segments, segment)
+	}
+	sort.Strings(segments)
+	for _, segment := range segments {
+		childWhere := fmt.Sprintf("%s.%s[%q]", where, childrenKey, segment)
+		trimmed := strings.Trim(segment, "/")
+		if trimmed == "" {
+			return fmt.Errorf("%s: empty child segment", childWhere)
+		}
+		if err := addJSONEntry(flat, defined, path+"/"+trimmed, childWhere, children[segment]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestCatalog lays nodes out in the nested format. Each node goes under its
+// nearest ancestor in nodes, keyed by its remaining segments; nodes without
+// one are top-level keys.
+func nestCatalog(nodes []*CatalogNode) map[string]*catalogTreeNodeYAML {
+	sorted := make([]*CatalogNode, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	byPath := make(map[string]*catalogTreeNodeYAML, len(sorted))
+	root := make(map[string]*catalogTreeNodeYAML)
+	for _, node := range sorted {
+		entry := &catalogTreeNodeYAML{CatalogNodeYAML: *convertNodeToYAML(node)}
+		byPath[node.Path] = entry
+
+		parent, rel := nearestNestedAncestor(byPath, node.Path)
+		if parent == nil {
+			root[node.Path] = entry
+			continue
+		}
+		if parent.Children == nil {
+			parent.Children = make(map[string]*catalogTreeNodeYAML)
+		}
+		parent.Children[rel] = entry
+	}
+	return root
+}
+
+// nearestNestedAncestor returns the closest ancestor of path already in
+// byPath and path relative to it, or nil
+func nearestNestedAncestor(byPath map[string]*catalogTreeNodeYAML, path string) (*catalogTreeNodeYAML, string) {
+	for i := strings.LastIndex(path, "/"); i > 0; i = strings.LastIndex(path[:i], "/") {
+		if parent, ok := byPath[path[:i]]; ok {
+			return parent, path[i+1:]
+		}
+	}
+	return nil, ""
+}
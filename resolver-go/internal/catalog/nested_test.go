@@ -0,0 +1,212 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+const nestedFixture = `prices:
+  display_name: Prices
+  children:
+    equity:
+      display_name: Equity
+      children:
+        us:
+          display_name: US Equity
+          source_binding:
+            type: static
+            config:
+              base_path: /data/us
+    fx/spot:
+      display_name: FX Spot
+  description: Set after the children block
+prices/equity/eu:
+  display_name: EU Equity
+`
+
+func TestParseCatalogNestedChildren(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(nestedFixture))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	tests := map[string]struct {
+		name string
+		line int
+	}{
+		"prices":           {"Prices", 1},
+		"prices/equity":    {"Equity", 4},
+		"prices/equity/us": {"US Equity", 7},
+		"prices/fx/spot":   {"FX Spot", 13},
+		"prices/equity/eu": {"EU Equity", 16},
+	}
+	for path, want := range tests {
+		node := reg.Get(path)
+		if node == nil {
+			t.Errorf("%s was not loaded", path)
+			continue
+		}
+		if node.DisplayName != want.name || node.SourceLine != want.line {
+			t.Errorf("%s: got %q at line %d, want %q at line %d", path, node.DisplayName, node.SourceLine, want.name, want.line)
+		}
+	}
+	if len(nodes) != len(tests) {
+		t.Errorf("expected %d nodes, got %d", len(tests), len(nodes))
+	}
+	if reg.Get("prices").Description != "Set after the children block" {
+		t.Error("parent fields after the children block should stay on the parent")
+	}
+	if !reg.Get("prices/equity/us").IsLeaf {
+		t.Error("nested node with a source binding should be a leaf")
+	}
+}
+
+func TestParseCatalogNestedConflictsWithFlatKey(t *testing.T) {
+	_, err := ParseCatalog([]byte(`prices:
+  children:
+    equity:
+      display_name: Nested
+prices/equity:
+  display_name: Flat
+`))
+	if err == nil {
+		t.Fatal("expected a conflict between nested and flat definitions")
+	}
+	for _, want := range []string{"'prices/equity'", "line 5", "line 3"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
+
+func TestParseCatalogNestedChildrenMustBeMapping(t *testing.T) {
+	_, err := ParseCatalog([]byte("prices:\n  children: [equity]\n"))
+	if err == nil || !strings.Contains(err.Error(), "prices.children") {
+		t.Errorf("expected children type error, got %v", err)
+	}
+}
+
+func TestParseCatalogNestedUnknownFields(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`prices:
+  children:
+    equity:
+      dispay_name: Equity
+  descripton: typo after children
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, u := range UnknownFields(nodes) {
+		got[u.Field] = u.Path
+	}
+	if got["dispay_name"] != "prices/equity" {
+		t.Errorf("dispay_name should belong to prices/equity, got %q", got["dispay_name"])
+	}
+	if got["descripton"] != "prices" {
+		t.Errorf("descripton should belong to prices, got %q", got["descripton"])
+	}
+}
+
+func TestParseCatalogNestedWithDefaults(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`_defaults:
+  desk:
+    ownership:
+      accountable_owner: desk@example.com
+prices:
+  children:
+    equity:
+      extends: desk
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+	owner := reg.Get("prices/equity").Ownership
+	if owner == nil || owner.AccountableOwner == nil || *owner.AccountableOwner != "desk@example.com" {
+		t.Errorf("nested child should pick up its default, got %+v", owner)
+	}
+}
+
+func TestParseCatalogJSONNestedChildren(t *testing.T) {
+	nodes, err := ParseCatalogJSON([]byte(`{
+  "prices": {"display_name": "Prices", "children": {"equity": {"display_name": "Equity"}}},
+  "prices/fx": {"display_name": "FX"}
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+	if node := reg.Get("prices/equity"); node == nil || node.DisplayName != "Equity" {
+		t.Errorf("expected nested prices/equity, got %+v", node)
+	}
+
+	_, err = ParseCatalogJSON([]byte(`{
+  "prices": {"children": {"equity": {}}},
+  "prices/equity": {}
+}`))
+	if err == nil || !strings.Contains(err.Error(), `$["prices"].children["equity"]`) {
+		t.Errorf("expected conflict naming both locations, got %v", err)
+	}
+}
+
+func TestExportNestedRoundTrip(t *testing.T) {
+	reg := loadExportFixture(t)
+
+	data, err := ExportYAML(reg, ExportNested)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.Contains(string(data), childrenKey+":") {
+		t.Errorf("nested export has no children blocks:\n%s", data)
+	}
+	reloaded, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("reload nested YAML: %v\n%s", err, data)
+	}
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("round trip changed the catalog: %s\n%+v", diff.Summary(), diff.Modified)
+	}
+
+	data, err = ExportJSON(reg, ExportNested)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	reloaded, err = ParseCatalogJSON(data)
+	if err != nil {
+		t.Fatalf("reload nested JSON: %v\n%s", err, data)
+	}
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("JSON round trip changed the catalog: %s", diff.Summary())
+	}
+}
+
+func TestNestCatalogSkipsMissingAncestors(t *testing.T) {
+	tree := nestCatalog([]*CatalogNode{
+		{Path: "prices"},
+		{Path: "prices/equity/us"},
+		{Path: "rates/swaps"},
+	})
+	if len(tree) != 2 || tree["prices"] == nil || tree["rates/swaps"] == nil {
+		t.Fatalf("unexpected top-level keys: %v", tree)
+	}
+	if tree["prices"].Children["equity/us"] == nil {
+		t.Errorf("prices/equity/us should nest under prices as equity/us, got %v", tree["prices"].Children)
+	}
+}
+
+func TestParseExportStyle(t *testing.T) {
+	if s, err := ParseExportStyle(""); err != nil || s != ExportFlat {
+		t.Errorf("empty style should be flat, got %q, %v", s, err)
+	}
+	if s, err := ParseExportStyle("nested"); err != nil || s != ExportNested {
+		t.Errorf("expected nested, got %q, %v", s, err)
+	}
+	if _, err := ParseExportStyle("tree"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+}
@@ -0,0 +1,199 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay overrides fields of nodes in a base catalog, e.g. the hosts and
+// warehouses of one environment. It uses the catalog file format (flat or
+// nested, YAML or JSON) but may only name paths the base defines, and only
+// the fields it sets are changed: mappings such as source_binding.config are
+// merged key by key, while scalars and lists are replaced.
+type Overlay struct {
+	Path    string // File the overlay was loaded from, if any
+	entries []yamlEntry
+}
+
+// LoadOverlay reads an overlay file. If the file has a manifest (see
+// ManifestPath) it is verified first.
+func LoadOverlay(path string) (*Overlay, error) {
+	manifest, err := FindManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		if err := manifest.VerifyFiles(); err != nil {
+			return nil, err
+		}
+		if err := manifest.checkCoverage(path, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read overlay: %w", err)
+	}
+	overlay, err := ParseOverlay(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	overlay.Path = path
+	return overlay, nil
+}
+
+// ApplyOverlayFile loads the overlay at path and applies it to nodes
+func ApplyOverlayFile(nodes []*CatalogNode, path string) ([]*CatalogNode, error) {
+	overlay, err := LoadOverlay(path)
+	if err != nil {
+		return nil, err
+	}
+	return overlay.Apply(nodes)
+}
+
+// ParseOverlay parses overlay content. Keys that match no catalog field are
+// errors, since a mistyped override would otherwise silently do nothing.
+func ParseOverlay(data []byte) (*Overlay, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse overlay: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return &Overlay{}, nil
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse overlay: line %d: overlay must be a mapping of path to node fields", mapping.Line)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var doc map[string]*catalogTreeNodeYAML
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse overlay: %w", err)
+	}
+
+	entries, err := flattenYAMLChildren(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("parse overlay: %w", err)
+	}
+	seen := make(map[string]int, len(entries))
+	for _, e := range entries {
+		if isReservedKey(e.path) {
+			return nil, fmt.Errorf("parse overlay: line %d: %s is not supported in overlays", e.key.Line, e.path)
+		}
+		if first, dup := seen[e.path]; dup {
+			return nil, fmt.Errorf("parse overlay: line %d: '%s' is already overridden at line %d", e.key.Line, e.path, first)
+		}
+		seen[e.path] = e.key.Line
+		if e.value.Kind != yaml.MappingNode && e.value.Tag != "!!null" {
+			return nil, fmt.Errorf("parse overlay: line %d: '%s' must be a mapping of fields to override", e.value.Line, e.path)
+		}
+		if extends := yamlMappingKey(e.value, "extends"); extends != nil {
+			return nil, fmt.Errorf("parse overlay: line %d: extends is not supported in overlays", extends.Line)
+		}
+		if binding := yamlMappingValue(e.value, "source_binding"); binding != nil && binding.Kind == yaml.MappingNode {
+			if template := yamlMappingKey(binding, "template"); template != nil {
+				return nil, fmt.Errorf("parse overlay: line %d: source_binding.template is not supported in overlays", template.Line)
+			}
+		}
+	}
+	return &Overlay{entries: entries}, nil
+}
+
+// Apply returns nodes with the overlay's overrides applied. Overridden nodes
+// are copies; the input nodes are not modified. Every overridden path must be
+// in nodes, and the effective node must pass the same validation as a node
+// loaded from a file.
+func (o *Overlay) Apply(nodes []*CatalogNode) ([]*CatalogNode, error) {
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node.Path] = i
+	}
+
+	missing := make([]string, 0)
+	for _, e := range o.entries {
+		if _, ok := index[e.path]; !ok {
+			missing = append(missing, fmt.Sprintf("'%s' (line %d)", e.path, e.key.Line))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("overlay %soverrides paths not in the base catalog: %s", o.where(), strings.Join(missing, ", "))
+	}
+
+	result := make([]*CatalogNode, len(nodes))
+	copy(result, nodes)
+	for _, e := range o.entries {
+		i := index[e.path]
+		node, err := overlayNode(result[i], e.value)
+		if err != nil {
+			return nil, fmt.Errorf("overlay %sline %d: %w", o.where(), e.key.Line, err)
+		}
+		result[i] = node
+	}
+	if err := validateSourceConfigs(result); err != nil {
+		return nil, fmt.Errorf("overlay %s%w", o.where(), err)
+	}
+	return result, nil
+}
+
+// where names the overlay file in errors, with a trailing separator
+func (o *Overlay) where() string {
+	if o.Path == "" {
+		return ""
+	}
+	return o.Path + ": "
+}
+
+// overlayNode applies the override mapping to a copy of base
+func overlayNode(base *CatalogNode, override *yaml.Node) (*CatalogNode, error) {
+	var merged yaml.Node
+	if err := merged.Encode(convertNodeToYAML(base)); err != nil {
+		return nil, fmt.Errorf("%s: %w", base.Path, err)
+	}
+	if override.Kind == yaml.MappingNode {
+		overrideYAMLMapping(&merged, override)
+	}
+
+	var nodeYAML CatalogNodeYAML
+	if err := merged.Decode(&nodeYAML); err != nil {
+		return nil, fmt.Errorf("%s: %w", base.Path, err)
+	}
+	if err := validateNodeYAML(base.Path, &nodeYAML); err != nil {
+		return nil, err
+	}
+	node := convertYAMLToNode(base.Path, &nodeYAML)
+	node.SourceFile = base.SourceFile
+	node.SourceLine = base.SourceLine
+	node.UnknownFields = base.UnknownFields
+	return node, nil
+}
+
+// overrideYAMLMapping sets every key of src in dst. When both values are
+// mappings they are merged recursively; anything else is replaced.
+func overrideYAMLMapping(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value != key.Value {
+				continue
+			}
+			found = true
+			if existing := dst.Content[j+1]; existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+				overrideYAMLMapping(existing, value)
+			} else {
+				dst.Content[j+1] = value
+			}
+			break
+		}
+		if !found {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const overlayBase = `prices:
+  display_name: Prices
+  ownership:
+    accountable_owner: prices@example.com
+    support_channel: "#prices"
+prices/equity:
+  display_name: Equity
+  tags: [equity, dev]
+  source_binding:
+    type: snowflake
+    config:
+      account: dev-account
+      database: PRICES
+      warehouse: DEV_WH
+`
+
+func parseOverlayBase(t *testing.T) []*CatalogNode {
+	t.Helper()
+	nodes, err := ParseCatalog([]byte(overlayBase))
+	if err != nil {
+		t.Fatalf("parse base: %v", err)
+	}
+	return nodes
+}
+
+func TestOverlayOverridesOnlyGivenFields(t *testing.T) {
+	base := parseOverlayBase(t)
+	overlay, err := ParseOverlay([]byte(`prices:
+  ownership:
+    support_channel: "#prices-prod"
+prices/equity:
+  tags: [equity]
+  source_binding:
+    config:
+      account: prod-account
+      warehouse: PROD_WH
+`))
+	if err != nil {
+		t.Fatalf("parse overlay: %v", err)
+	}
+	nodes, err := overlay.Apply(base)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	prices := reg.Get("prices")
+	if *prices.Ownership.SupportChannel != "#prices-prod" || *prices.Ownership.AccountableOwner != "prices@example.com" {
+		t.Errorf("ownership should be merged key by key, got %+v", prices.Ownership)
+	}
+	equity := reg.Get("prices/equity")
+	config := equity.SourceBinding.Config
+	if config["account"] != "prod-account" || config["warehouse"] != "PROD_WH" || config["database"] != "PRICES" {
+		t.Errorf("source_binding.config should be deep-merged, got %v", config)
+	}
+	if equity.SourceBinding.SourceType != SourceTypeSnowflake || equity.DisplayName != "Equity" {
+		t.Errorf("fields absent from the overlay should be kept, got %+v", equity)
+	}
+	if len(equity.Tags) != 1 || equity.Tags[0] != "equity" {
+		t.Errorf("lists should be replaced, got %v", equity.Tags)
+	}
+	if equity.SourceLine != base[1].SourceLine {
+		t.Errorf("overlaid node should keep its base source line")
+	}
+
+	// The base nodes are untouched
+	if base[1].SourceBinding.Config["account"] != "dev-account" {
+		t.Error("Apply modified the base catalog")
+	}
+}
+
+func TestOverlayRejectsPathsMissingFromBase(t *testing.T) {
+	overlay, err := ParseOverlay([]byte("prices/fx:\n  display_name: FX\nrates:\n  display_name: Rates\n"))
+	if err != nil {
+		t.Fatalf("parse overlay: %v", err)
+	}
+	_, err = overlay.Apply(parseOverlayBase(t))
+	if err == nil {
+		t.Fatal("expected error for paths not in the base catalog")
+	}
+	for _, want := range []string{"'prices/fx' (line 1)", "'rates' (line 3)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
+
+func TestOverlayValidatesEffectiveNode(t *testing.T) {
+	overlay, err := ParseOverlay([]byte("prices/equity:\n  source_binding:\n    type: oracle\n"))
+	if err != nil {
+		t.Fatalf("parse overlay: %v", err)
+	}
+	if _, err := overlay.Apply(parseOverlayBase(t)); err == nil || !strings.Contains(err.Error(), "dsn") {
+		t.Errorf("expected source config error for the merged binding, got %v", err)
+	}
+}
+
+func TestParseOverlayErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":  "prices:\n  dispay_name: Prices\n",
+		"include":        "_include: [other.yaml]\n",
+		"extends":        "prices:\n  extends: desk\n",
+		"duplicate path": "prices:\n  children:\n    equity: {}\nprices/equity: {}\n",
+		"not a mapping":  "prices: Prices\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseOverlay([]byte(data)); err == nil {
+				t.Errorf("expected error for %q", data)
+			}
+		})
+	}
+}
+
+func TestReloadManagerAppliesOverlay(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	overlayFile := filepath.Join(dir, "prod.yaml")
+	writeFile(t, file, overlayBase)
+	writeFile(t, overlayFile, "prices/equity:\n  source_binding:\n    config:\n      warehouse: PROD_WH\n")
+
+	reg := NewRegistry()
+	m := NewReloadManager(reg, ConflictError, file)
+	m.Overlay = overlayFile
+	if _, err := m.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if wh := reg.Get("prices/equity").SourceBinding.Config["warehouse"]; wh != "PROD_WH" {
+		t.Errorf("expected overlaid warehouse, got %v", wh)
+	}
+	if m.Status().Overlay != overlayFile {
+		t.Errorf("status should report the overlay, got %q", m.Status().Overlay)
+	}
+
+	// An overlay that no longer matches the base is rejected like any bad catalog
+	writeFile(t, overlayFile, "prices/gone:\n  display_name: Gone\n")
+	if _, err := m.Reload(); err == nil {
+		t.Fatal("expected reload to fail")
+	}
+	if wh := reg.Get("prices/equity").SourceBinding.Config["warehouse"]; wh != "PROD_WH" {
+		t.Errorf("rejected reload changed the live catalog: warehouse %v", wh)
+	}
+}
@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"sort"
+)
+
+// Owner roles: the ownership fields an owner directory entry is built from
+const (
+	OwnerRoleAccountableOwner = "accountable_owner"
+	OwnerRoleADOP             = "adop"
+	OwnerRoleADS              = "ads"
+	OwnerRoleADAL             = "adal"
+)
+
+// OwnerSummary is an entry of the owner directory: an identity appearing in
+// any node's ownership after inheritance, and how many nodes it holds each
+// role on. Node counts are of distinct nodes, whatever the roles.
+type OwnerSummary struct {
+	ID              string         `json:"id"`
+	Names           []string       `json:"names,omitempty"` // From adop_name, ads_name and adal_name
+	Roles           map[string]int `json:"roles"`           // Role -> nodes held on
+	ActiveNodes     int            `json:"active_nodes"`
+	DeprecatedNodes int            `json:"deprecated_nodes"`
+	TotalNodes      int            `json:"total_nodes"`
+}
+
+// OwnedNode is a role an owner holds on a node. Source is the path the role
+// is defined at, which is an ancestor when it is inherited.
+type OwnedNode struct {
+	Path      string     `json:"path"`
+	Status    NodeStatus `json:"status"`
+	Role      string     `json:"role"`
+	Source    string     `json:"source"`
+	Inherited bool       `json:"inherited"`
+}
+
+// OwnerDetail is an owner with every role it holds, by path then role
+type OwnerDetail struct {
+	OwnerSummary
+	Nodes []OwnedNode `json:"nodes"`
+}
+
+// heldRole is the owner of one role of a resolved ownership
+type heldRole struct {
+	role                string
+	owner, name, source *string
+}
+
+// ownerRoles returns the owner, name and source of each role of resolved
+func ownerRoles(resolved *ResolvedOwnership) []heldRole {
+	return []heldRole{
+		{OwnerRoleAccountableOwner, resolved.AccountableOwner, nil, resolved.AccountableOwnerSource},
+		{OwnerRoleADOP, resolved.ADOP, resolved.ADOPName, resolved.ADOPSource},
+		{OwnerRoleADS, resolved.ADS, resolved.ADSName, resolved.ADSSource},
+		{OwnerRoleADAL, resolved.ADAL, resolved.ADALName, resolved.ADALSource},
+	}
+}
+
+// ownerDirectory builds every owner's detail from one snapshot
+func (s *snapshot) ownerDirectory() map[string]*OwnerDetail {
+	owners := make(map[string]*OwnerDetail)
+	names := make(map[string]map[string]bool)
+	for _, p := range s.sortedPaths() {
+		node := s.nodes[p]
+		counted := make(map[string]bool)
+		for _, held := range ownerRoles(s.resolveOwnership(p)) {
+			if held.owner == nil || *held.owner == "" {
+				continue
+			}
+			id := *held.owner
+			owner := owners[id]
+			if owner == nil {
+				owner = &OwnerDetail{OwnerSummary: OwnerSummary{ID: id, Roles: make(map[string]int)}}
+				owners[id] = owner
+				names[id] = make(map[string]bool)
+			}
+			source := p
+			if held.source != nil {
+				source = *held.source
+			}
+			owner.Nodes = append(owner.Nodes, OwnedNode{
+				Path:      p,
+				Status:    node.Status,
+				Role:      held.role,
+				Source:    source,
+				Inherited: source != p,
+			})
+			owner.Roles[held.role]++
+			if held.name != nil && *held.name != "" {
+				names[id][*held.name] = true
+			}
+
+			if counted[id] {
+				continue
+			}
+			counted[id] = true
+			owner.TotalNodes++
+			switch node.Status {
+			case NodeStatusActive:
+				owner.ActiveNodes++
+			case NodeStatusDeprecated:
+				owner.DeprecatedNodes++
+			}
+		}
+	}
+	for id, owner := range owners {
+		if len(names[id]) > 0 {
+			owner.Names = sortedKeys(names[id])
+		}
+	}
+	return owners
+}
+
+// Owners returns the owner directory in order of ID
+func (r *Registry) Owners() []OwnerSummary {
+	owners := r.load().ownerDirectory()
+	result := make([]OwnerSummary, 0, len(owners))
+	for _, id := range sortedKeys(owners) {
+		result = append(result, owners[id].OwnerSummary)
+	}
+	return result
+}
+
+// Owner returns an owner with the roles it holds, or nil if no node names it
+func (r *Registry) Owner(id string) *OwnerDetail {
+	return r.load().ownerDirectory()[id]
+}
+
+// OwnersAfter returns up to limit owners with IDs strictly after after, the
+// number of owners in all, and whether more follow
+func (r *Registry) OwnersAfter(after string, limit int) (owners []OwnerSummary, total int, more bool) {
+	all := r.Owners()
+	start := sort.Search(len(all), func(i int) bool { return all[i].ID > after })
+	end := start + limit
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[start:end], len(all), end < len(all)
+}
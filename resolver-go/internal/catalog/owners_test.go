@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func makeOwnersRegistry() *Registry {
+	r := NewRegistry()
+	root := makeNode("rates", "Rates", "", NodeStatusActive, false)
+	root.Ownership = &Ownership{AccountableOwner: strPtr("team-rates"), ADOP: strPtr("jdoe"), ADOPName: strPtr("Jane Doe")}
+	r.Register(root)
+
+	curves := makeNode("rates/curves", "Curves", "", NodeStatusActive, true)
+	curves.Ownership = &Ownership{ADS: strPtr("jdoe")}
+	r.Register(curves)
+
+	legacy := makeNode("rates/legacy", "Legacy", "", NodeStatusDeprecated, true)
+	legacy.Ownership = &Ownership{AccountableOwner: strPtr("team-archive")}
+	r.Register(legacy)
+	return r
+}
+
+func TestOwnersDirectory(t *testing.T) {
+	owners := makeOwnersRegistry().Owners()
+
+	ids := make([]string, 0, len(owners))
+	for _, o := range owners {
+		ids = append(ids, o.ID)
+	}
+	if want := []string{"jdoe", "team-archive", "team-rates"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("owners = %v, want %v", ids, want)
+	}
+
+	jdoe := owners[0]
+	if want := map[string]int{OwnerRoleADOP: 3, OwnerRoleADS: 1}; !reflect.DeepEqual(jdoe.Roles, want) {
+		t.Errorf("jdoe roles = %v, want %v", jdoe.Roles, want)
+	}
+	if jdoe.TotalNodes != 3 || jdoe.ActiveNodes != 2 || jdoe.DeprecatedNodes != 1 {
+		t.Errorf("unexpected jdoe counts %+v", jdoe)
+	}
+	if !reflect.DeepEqual(jdoe.Names, []string{"Jane Doe"}) {
+		t.Errorf("jdoe names = %v", jdoe.Names)
+	}
+
+	// The deprecated node overrides its accountable owner
+	if rates := owners[2]; rates.TotalNodes != 2 || rates.DeprecatedNodes != 0 {
+		t.Errorf("unexpected team-rates counts %+v", rates)
+	}
+}
+
+func TestOwnerProvenance(t *testing.T) {
+	r := makeOwnersRegistry()
+	if r.Owner("nobody") != nil {
+		t.Error("expected no owner for an unknown id")
+	}
+
+	owner := r.Owner("jdoe")
+	var curves []OwnedNode
+	for _, n := range owner.Nodes {
+		if n.Path == "rates/curves" {
+			curves = append(curves, n)
+		}
+	}
+	want := []OwnedNode{
+		{Path: "rates/curves", Status: NodeStatusActive, Role: OwnerRoleADOP, Source: "rates", Inherited: true},
+		{Path: "rates/curves", Status: NodeStatusActive, Role: OwnerRoleADS, Source: "rates/curves"},
+	}
+	if !reflect.DeepEqual(curves, want) {
+		t.Errorf("rates/curves roles = %+v, want %+v", curves, want)
+	}
+}
+
+func TestOwnersAfter(t *testing.T) {
+	r := makeOwnersRegistry()
+	page, total, more := r.OwnersAfter("", 2)
+	if len(page) != 2 || total != 3 || !more {
+		t.Fatalf("first page: %d owners of %d, more %v", len(page), total, more)
+	}
+	page, _, more = r.OwnersAfter(page[1].ID, 2)
+	if len(page) != 1 || page[0].ID != "team-rates" || more {
+		t.Errorf("second page: %+v, more %v", page, more)
+	}
+}
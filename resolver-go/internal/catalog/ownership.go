@@ -0,0 +1,158 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultOwnerPattern matches an identifier (team-rates, jsmith) or an email
+// address; governance role fields must match it unless configured otherwise
+const DefaultOwnerPattern = `^[A-Za-z0-9][A-Za-z0-9._-]*(@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+)?$`
+
+// ownershipFields maps the JSON key of each ownership field to the field
+var ownershipFields = map[string]func(*Ownership) **string{
+	"accountable_owner": func(o *Ownership) **string { return &o.AccountableOwner },
+	"data_specialist":   func(o *Ownership) **string { return &o.DataSpecialist },
+	"support_channel":   func(o *Ownership) **string { return &o.SupportChannel },
+	"adop":              func(o *Ownership) **string { return &o.ADOP },
+	"ads":               func(o *Ownership) **string { return &o.ADS },
+	"adal":              func(o *Ownership) **string { return &o.ADAL },
+	"adop_name":         func(o *Ownership) **string { return &o.ADOPName },
+	"ads_name":          func(o *Ownership) **string { return &o.ADSName },
+	"adal_name":         func(o *Ownership) **string { return &o.ADALName },
+	"ui":                func(o *Ownership) **string { return &o.UI },
+}
+
+// ownerIdentityFields are the ownership fields that name a person or team
+// and are checked against the owner pattern
+var ownerIdentityFields = []string{"accountable_owner", "data_specialist", "adop", "ads", "adal"}
+
+// OwnershipEdit is a change to some fields of a node's ownership. A field
+// given as null is cleared, so the node inherits it again; fields the edit
+// does not mention are left as they are.
+type OwnershipEdit struct {
+	fields map[string]*string // JSON key -> new value, nil to clear
+}
+
+// ParseOwnershipEdit parses a JSON Ownership object as an OwnershipEdit,
+// checking the fields that name an owner against pattern
+func ParseOwnershipEdit(data []byte, pattern *regexp.Regexp) (*OwnershipEdit, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse ownership JSON: %s", describeJSONError(data, "$", err))
+	}
+	edit := &OwnershipEdit{fields: make(map[string]*string, len(raw))}
+	for key, msg := range raw {
+		if ownershipFields[key] == nil {
+			return nil, fmt.Errorf("unknown ownership field %s", key)
+		}
+		var value *string
+		if err := json.Unmarshal(msg, &value); err != nil {
+			return nil, fmt.Errorf("%s must be a string or null", key)
+		}
+		if value != nil && strings.TrimSpace(*value) == "" {
+			return nil, fmt.Errorf("%s must not be empty; use null to clear it", key)
+		}
+		edit.fields[key] = value
+	}
+	for _, key := range ownerIdentityFields {
+		if v := edit.fields[key]; v != nil && !pattern.MatchString(*v) {
+			return nil, fmt.Errorf("%s %q does not look like an identifier or email (pattern %s)", key, *v, pattern)
+		}
+	}
+	return edit, nil
+}
+
+// Apply returns ownership with the edit applied, or nil when no field is left
+// set. ownership itself is not modified.
+func (e *OwnershipEdit) Apply(ownership *Ownership) *Ownership {
+	edited := &Ownership{}
+	if ownership != nil {
+		*edited = *ownership
+	}
+	empty := true
+	for key, field := range ownershipFields {
+		if value, ok := e.fields[key]; ok {
+			*field(edited) = value
+		}
+		empty = empty && *field(edited) == nil
+	}
+	if empty {
+		return nil
+	}
+	return edited
+}
+
+// ownershipDiff describes the fields that differ between two ownerships as
+// "field: old -> new", sorted by field
+func ownershipDiff(old, new *Ownership) string {
+	if old == nil {
+		old = &Ownership{}
+	}
+	if new == nil {
+		new = &Ownership{}
+	}
+	show := func(v *string) string {
+		if v == nil {
+			return "(unset)"
+		}
+		return *v
+	}
+	changes := make([]string, 0)
+	for key, field := range ownershipFields {
+		if o, n := *field(old), *field(new); !equalStringPtr(o, n) {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, show(o), show(n)))
+		}
+	}
+	sort.Strings(changes)
+	return strings.Join(changes, "; ")
+}
+
+// UpdateOwnership applies edit to the ownership of the node at path as Update
+// does, recording one "ownership_changed" audit entry by actor with the old
+// and new ownership and the changed fields
+func (r *Registry) UpdateOwnership(path, actor string, edit *OwnershipEdit) (*CatalogNode, error) {
+	mutate := func(node *CatalogNode) error {
+		node.Ownership = edit.Apply(node.Ownership)
+		return nil
+	}
+	return r.update(path, mutate, func(current, updated *CatalogNode, _ *NodeChange) {
+		details := ownershipDiff(current.Ownership, updated.Ownership)
+		r.appendAudit(newAuditEntry(path, "ownership_changed", actor,
+			auditValue(current.Ownership), auditValue(updated.Ownership), &details))
+	})
+}
+
+// OwnershipInheritors returns the registered descendants of path, sorted,
+// whose resolved ownership takes at least one field from path
+func (r *Registry) OwnershipInheritors(path string) []string {
+	inheritors := make([]string, 0)
+	for _, p := range r.load().sortedPaths() {
+		isDescendant := false
+		for _, a := range ancestorPaths(p) {
+			isDescendant = isDescendant || a == path
+		}
+		if !isDescendant {
+			continue
+		}
+		for _, source := range r.ResolveOwnership(p).sources() {
+			if source != nil && *source == path {
+				inheritors = append(inheritors, p)
+				break
+			}
+		}
+	}
+	return inheritors
+}
+
+// sources lists the provenance of every resolved field, nil when unset
+func (ro *ResolvedOwnership) sources() []*string {
+	return []*string{
+		ro.AccountableOwnerSource, ro.DataSpecialistSource, ro.SupportChannelSource,
+		ro.ADOPSource, ro.ADSSource, ro.ADALSource,
+		ro.ADOPNameSource, ro.ADSNameSource, ro.ADALNameSource, ro.UISource,
+	}
+}
@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestOwnershipEditDistinguishesNullFromAbsent(t *testing.T) {
+	pattern := regexp.MustCompile(DefaultOwnerPattern)
+	edit, err := ParseOwnershipEdit([]byte(`{"accountable_owner": "team-rates", "data_specialist": null}`), pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := &Ownership{AccountableOwner: strPtr("team-prices"), DataSpecialist: strPtr("jsmith"), SupportChannel: strPtr("#prices")}
+	edited := edit.Apply(original)
+	if *edited.AccountableOwner != "team-rates" || edited.DataSpecialist != nil || edited.SupportChannel == nil || *edited.SupportChannel != "#prices" {
+		t.Errorf("expected owner set, specialist cleared and channel kept, got %+v", edited)
+	}
+	if *original.AccountableOwner != "team-prices" || original.DataSpecialist == nil {
+		t.Errorf("expected the original to be left alone, got %+v", original)
+	}
+
+	clear, _ := ParseOwnershipEdit([]byte(`{"accountable_owner": null, "data_specialist": null, "support_channel": null}`), pattern)
+	if got := clear.Apply(original); got != nil {
+		t.Errorf("expected clearing every field to leave no ownership, got %+v", got)
+	}
+}
+
+func TestOwnershipEditValidatesOwners(t *testing.T) {
+	pattern := regexp.MustCompile(DefaultOwnerPattern)
+	for _, body := range []string{
+		`{"adop": "jane.doe@example.com"}`,
+		`{"accountable_owner": "team_rates"}`,
+		`{"support_channel": "#rates help"}`, // Not an owner identity
+	} {
+		if _, err := ParseOwnershipEdit([]byte(body), pattern); err != nil {
+			t.Errorf("%s: unexpected error %v", body, err)
+		}
+	}
+	for _, body := range []string{
+		`{"adop": "Jane Doe"}`,
+		`{"ads": "jane@"}`,
+		`{"accountable_owner": ""}`,
+		`{"owner": "team-rates"}`,
+		`{"adal": 42}`,
+	} {
+		if _, err := ParseOwnershipEdit([]byte(body), pattern); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AllowedParamsKey is the source binding config key declaring the moniker
+// query params (?name=value) a binding accepts, mapped to their defaults. A
+// param whose default is null is required. Queries reference params as
+// {params.NAME}.
+const AllowedParamsKey = "allowed_params"
+
+var (
+	paramNamePattern        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	paramPlaceholderPattern = regexp.MustCompile(`\{params\.([^{}]*)\}`)
+)
+
+// ParamPlaceholders returns the param names referenced as {params.NAME} in
+// a query, sorted and without duplicates
+func ParamPlaceholders(query string) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, match := range paramPlaceholderPattern.FindAllStringSubmatch(query, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkAllowedParams validates the allowed_params config key and that every
+// {params.NAME} in the binding's queries, including those of its
+// sub-resources, is declared
+func checkAllowedParams(path string, sb *SourceBinding) []SourceConfigViolation {
+	violation := func(msg string) SourceConfigViolation {
+		return SourceConfigViolation{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): %s", path, sb.SourceType, msg),
+		}
+	}
+
+	var violations []SourceConfigViolation
+	declared := func(config map[string]interface{}, where string) map[string]bool {
+		raw, ok := config[AllowedParamsKey]
+		if !ok {
+			return nil
+		}
+		params, ok := raw.(map[string]interface{})
+		if !ok {
+			violations = append(violations, violation(fmt.Sprintf("%s'%s' must be a map of param name to default, got %T", where, AllowedParamsKey, raw)))
+			return nil
+		}
+		names := make(map[string]bool, len(params))
+		for name, def := range params {
+			names[name] = true
+			if !paramNamePattern.MatchString(name) {
+				violations = append(violations, violation(fmt.Sprintf("%s%s: '%s' is not a valid param name", where, AllowedParamsKey, name)))
+			}
+			switch def.(type) {
+			case nil, string, bool, int, int64, uint64, float64:
+			default:
+				violations = append(violations, violation(fmt.Sprintf("%s%s.%s default must be a scalar or null, got %T", where, AllowedParamsKey, name, def)))
+			}
+		}
+		return names
+	}
+	checkQuery := func(config map[string]interface{}, names map[string]bool, where string) {
+		query, _ := config["query"].(string)
+		undeclared := make([]string, 0)
+		for _, name := range ParamPlaceholders(query) {
+			if !names[name] {
+				undeclared = append(undeclared, name)
+			}
+		}
+		if len(undeclared) > 0 {
+			violations = append(violations, violation(fmt.Sprintf("%squery references params not in %s: %s", where, AllowedParamsKey, strings.Join(undeclared, ", "))))
+		}
+	}
+
+	base := declared(sb.Config, "")
+	checkQuery(sb.Config, base, "")
+	for _, name := range sb.SubResourceNames() {
+		entry, ok := sb.SubResources()[name].(map[string]interface{})
+		if !ok {
+			continue // Reported by checkSubResources
+		}
+		where := fmt.Sprintf("%s.%s: ", SubResourcesKey, name)
+		names := make(map[string]bool, len(base))
+		for n := range base {
+			names[n] = true
+		}
+		if _, ok := entry[AllowedParamsKey]; ok {
+			names = declared(entry, where)
+		}
+		checkQuery(entry, names, where)
+	}
+	return violations
+}
@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParamPlaceholders(t *testing.T) {
+	got := ParamPlaceholders("SELECT * FROM T WHERE region = '{params.region}' AND ccy IN ({params.ccy}, {params.region})")
+	if strings.Join(got, ",") != "ccy,region" {
+		t.Errorf("unexpected placeholders: %v", got)
+	}
+}
+
+func TestAllowedParamsValidation(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET_DATA
+      query: "SELECT * FROM EQUITY WHERE region = '{params.region}' AND desk = '{params.desk}'"
+      allowed_params: {region: null, currency: USD}
+prices/fx:
+  source_binding:
+    type: oracle
+    config:
+      dsn: "oracle://localhost/fx"
+      allowed_params: {2fast: null, window: [1, 2]}
+prices/bonds:
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET_DATA
+      allowed_params: {region: EMEA}
+      sub_resources:
+        details: {query: "SELECT * FROM BOND_DETAILS WHERE region = '{params.region}' AND tenor = '{params.tenor}'"}
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *SourceConfigError, got %v", err)
+	}
+	if len(cfgErr.Violations) != 4 {
+		t.Fatalf("expected 4 violations, got %d: %v", len(cfgErr.Violations), err)
+	}
+	for _, want := range []string{
+		"prices/equity (snowflake): query references params not in allowed_params: desk",
+		"'2fast' is not a valid param name",
+		"allowed_params.window default must be a scalar or null",
+		"sub_resources.details: query references params not in allowed_params: tenor",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QualityEdit is a change to a node's own data quality: a new score between
+// 0 and 1, known issues to add and remove, and a new last_validated, either
+// given or stamped with the time of the change (mark_validated). The node's
+// known issues, once set, replace those it inherits.
+type QualityEdit struct {
+	QualityScore      *float64 `json:"quality_score,omitempty"`
+	AddKnownIssues    []string `json:"add_known_issues,omitempty"`
+	RemoveKnownIssues []string `json:"remove_known_issues,omitempty"`
+	LastValidated     *string  `json:"last_validated,omitempty"`
+	MarkValidated     bool     `json:"mark_validated,omitempty"`
+}
+
+// QualityEditError reports an edit that does not apply to the node, such as
+// removing a known issue it does not list
+type QualityEditError struct {
+	Path    string
+	Message string
+}
+
+func (e *QualityEditError) Error() string {
+	return e.Message
+}
+
+// ParseQualityEdit parses and checks a JSON QualityEdit
+func ParseQualityEdit(data []byte) (*QualityEdit, error) {
+	var edit QualityEdit
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&edit); err != nil {
+		return nil, fmt.Errorf("parse quality JSON: %s", describeJSONError(data, "$", err))
+	}
+	if s := edit.QualityScore; s != nil && (math.IsNaN(*s) || *s < 0 || *s > 1) {
+		return nil, fmt.Errorf("quality_score must be between 0 and 1, got %v", *s)
+	}
+	for _, issues := range [][]string{edit.AddKnownIssues, edit.RemoveKnownIssues} {
+		for _, issue := range issues {
+			if strings.TrimSpace(issue) == "" {
+				return nil, fmt.Errorf("known issues must not be empty")
+			}
+		}
+	}
+	if edit.LastValidated != nil {
+		if edit.MarkValidated {
+			return nil, fmt.Errorf("set last_validated or mark_validated, not both")
+		}
+		if _, err := ParseISODate(*edit.LastValidated); err != nil {
+			return nil, fmt.Errorf("last_validated: %v", err)
+		}
+	}
+	if edit.QualityScore == nil && len(edit.AddKnownIssues) == 0 && len(edit.RemoveKnownIssues) == 0 &&
+		edit.LastValidated == nil && !edit.MarkValidated {
+		return nil, fmt.Errorf("nothing to change: set quality_score, add_known_issues, remove_known_issues, last_validated or mark_validated")
+	}
+	return &edit, nil
+}
+
+// apply returns dq with the edit applied at now. dq itself is not modified.
+func (e *QualityEdit) apply(path string, dq *DataQuality, now time.Time) (*DataQuality, error) {
+	edited := &DataQuality{}
+	if dq != nil {
+		*edited = *dq
+	}
+	if e.QualityScore != nil {
+		score := *e.QualityScore
+		edited.QualityScore = &score
+	}
+
+	if len(e.AddKnownIssues) > 0 || len(e.RemoveKnownIssues) > 0 {
+		issues := append([]string(nil), edited.KnownIssues...)
+		for _, remove := range e.RemoveKnownIssues {
+			i := indexOf(issues, remove)
+			if i < 0 {
+				return nil, &QualityEditError{Path: path, Message: fmt.Sprintf("known issue %q is not listed on '%s'", remove, path)}
+			}
+			issues = append(issues[:i], issues[i+1:]...)
+		}
+		for _, add := range e.AddKnownIssues {
+			if indexOf(issues, add) < 0 {
+				issues = append(issues, add)
+			}
+		}
+		if len(issues) == 0 {
+			issues = nil
+		}
+		edited.KnownIssues = issues
+	}
+
+	switch {
+	case e.LastValidated != nil:
+		validated := *e.LastValidated
+		edited.LastValidated = &validated
+	case e.MarkValidated:
+		validated := now.UTC().Format(time.RFC3339)
+		edited.LastValidated = &validated
+	}
+	return edited, nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// qualityDiff describes what changed between two data quality blocks
+func qualityDiff(old, new *DataQuality) string {
+	if old == nil {
+		old = &DataQuality{}
+	}
+	changes := make([]string, 0)
+	if o, n := old.QualityScore, new.QualityScore; (o == nil) != (n == nil) || (o != nil && *o != *n) {
+		show := func(v *float64) string {
+			if v == nil {
+				return "(unset)"
+			}
+			return fmt.Sprint(*v)
+		}
+		changes = append(changes, fmt.Sprintf("quality_score: %s -> %s", show(o), show(n)))
+	}
+	for _, issue := range new.KnownIssues {
+		if indexOf(old.KnownIssues, issue) < 0 {
+			changes = append(changes, "known issue added: "+issue)
+		}
+	}
+	for _, issue := range old.KnownIssues {
+		if indexOf(new.KnownIssues, issue) < 0 {
+			changes = append(changes, "known issue removed: "+issue)
+		}
+	}
+	if !equalStringPtr(old.LastValidated, new.LastValidated) {
+		changes = append(changes, "last_validated: "+*new.LastValidated)
+	}
+	sort.Strings(changes)
+	return strings.Join(changes, "; ")
+}
+
+// UpdateQuality applies edit to the data quality of the node at path as
+// Update does, recording one "quality_changed" audit entry by actor with the
+// old and new data quality and what changed. An edit that does not apply is
+// a *QualityEditError.
+func (r *Registry) UpdateQuality(path, actor string, edit *QualityEdit) (*CatalogNode, error) {
+	now := time.Now()
+	mutate := func(node *CatalogNode) error {
+		dq, err := edit.apply(path, node.DataQuality, now)
+		if err != nil {
+			return err
+		}
+		node.DataQuality = dq
+		return nil
+	}
+	return r.update(path, mutate, func(current, updated *CatalogNode, _ *NodeChange) {
+		details := qualityDiff(current.DataQuality, updated.DataQuality)
+		r.appendAudit(newAuditEntry(path, "quality_changed", actor,
+			auditValue(current.DataQuality), auditValue(updated.DataQuality), &details))
+	})
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQualityEditValidates(t *testing.T) {
+	for _, body := range []string{
+		`{"quality_score": 1.5}`,
+		`{"quality_score": -0.1}`,
+		`{"add_known_issues": [" "]}`,
+		`{"last_validated": "yesterday"}`,
+		`{"last_validated": "2026-01-01", "mark_validated": true}`,
+		`{"dq_owner": "someone"}`,
+		`{}`,
+	} {
+		if _, err := ParseQualityEdit([]byte(body)); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+	if _, err := ParseQualityEdit([]byte(`{"quality_score": 0, "mark_validated": true}`)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestUpdateQualityEditsIssuesAndAudits(t *testing.T) {
+	r := NewRegistry()
+	node := makeNode("prices", "Prices", "", NodeStatusActive, true)
+	node.DataQuality = &DataQuality{DQOwner: strPtr("dq-team"), KnownIssues: []string{"late on holidays"}}
+	r.Register(node)
+
+	edit, _ := ParseQualityEdit([]byte(`{"quality_score": 0.8, "add_known_issues": ["gaps in 2019"],
+		"remove_known_issues": ["late on holidays"], "mark_validated": true}`))
+	updated, err := r.UpdateQuality("prices", "dq-team", edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dq := updated.DataQuality
+	if *dq.QualityScore != 0.8 || len(dq.KnownIssues) != 1 || dq.KnownIssues[0] != "gaps in 2019" || dq.LastValidated == nil {
+		t.Errorf("unexpected data quality %+v", dq)
+	}
+	if len(node.DataQuality.KnownIssues) != 1 || node.DataQuality.KnownIssues[0] != "late on holidays" {
+		t.Errorf("expected the original node left alone, got %+v", node.DataQuality)
+	}
+
+	entries := r.AuditEntries("prices", 1, nil)
+	if len(entries) != 1 || entries[0].Action != "quality_changed" || entries[0].Actor != "dq-team" ||
+		!strings.Contains(*entries[0].Details, "known issue removed: late on holidays") {
+		t.Errorf("unexpected audit entries %+v", entries)
+	}
+
+	remove, _ := ParseQualityEdit([]byte(`{"remove_known_issues": ["never listed"]}`))
+	if _, err := r.UpdateQuality("prices", "dq-team", remove); err == nil {
+		t.Error("expected an error removing an unlisted issue")
+	} else if _, ok := err.(*QualityEditError); !ok {
+		t.Errorf("expected *QualityEditError, got %T", err)
+	}
+}
+
+func TestSearchMinQualityScore(t *testing.T) {
+	r := NewRegistry()
+	parent := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	parent.DataQuality = &DataQuality{QualityScore: float64Ptr(0.9)}
+	r.Register(parent)
+	r.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+	low := makeNode("prices/fx", "FX", "", NodeStatusActive, true)
+	low.DataQuality = &DataQuality{QualityScore: float64Ptr(0.4)}
+	r.Register(low)
+	r.Register(makeNode("rates", "Rates", "", NodeStatusActive, false))
+
+	results := r.SearchWithOptions("", SearchOptions{Limit: 10, MinQualityScore: float64Ptr(0.5)})
+	if paths := nodePaths(results); strings.Join(paths, " ") != "prices prices/equity" {
+		t.Errorf("expected prices and its inheriting child, got %v", paths)
+	}
+}
@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// ReferenceIssueKind classifies a problem with a moniker reference held in a node's schema
+type ReferenceIssueKind string
+
+const (
+	ReferenceIssueUnparseable      ReferenceIssueKind = "unparseable"       // Not a valid moniker
+	ReferenceIssueBroken           ReferenceIssueKind = "broken"            // Path (and every ancestor) is unregistered
+	ReferenceIssueSelf             ReferenceIssueKind = "self_reference"    // Node references itself
+	ReferenceIssueDeprecatedTarget ReferenceIssueKind = "deprecated_target" // Target is deprecated or archived
+)
+
+// Issue severities shared by catalog validation checks
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ReferenceIssue describes a broken or suspicious moniker reference
+type ReferenceIssue struct {
+	Kind      ReferenceIssueKind `json:"kind"`
+	Severity  string             `json:"severity"`
+	Path      string             `json:"path"`  // Node defining the reference
+	Field     string             `json:"field"` // e.g. schema.columns[cusip].foreign_key
+	Reference string             `json:"reference"`
+	Message   string             `json:"message"`
+}
+
+// ValidateReferences checks every ColumnSchema.ForeignKey and DataSchema.RelatedMonikers
+// entry, and the inputs of derived source bindings. A reference is valid when it
+// parses as a moniker and its path, or one of its ancestors, is registered.
+// Self-references and references to deprecated or archived nodes are reported
+// as warnings.
+func (r *Registry) ValidateReferences() []ReferenceIssue {
+	snap := r.load()
+
+	paths := make([]string, 0, len(snap.nodes))
+	for p, node := range snap.nodes {
+		if node.DataSchema != nil || (node.SourceBinding != nil && node.SourceBinding.SourceType == SourceTypeDerived) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	issues := make([]ReferenceIssue, 0)
+	for _, p := range paths {
+		if binding := snap.nodes[p].SourceBinding; binding != nil && binding.SourceType == SourceTypeDerived {
+			inputs := DerivedInputs(binding.Config)
+			names := make([]string, 0, len(inputs))
+			for name := range inputs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				field := fmt.Sprintf("source_binding.config.%s.%s", DerivedInputsKey, name)
+				if issue := snap.checkReference(p, field, inputs[name]); issue != nil {
+					issues = append(issues, *issue)
+				}
+			}
+		}
+		schema := snap.nodes[p].DataSchema
+		if schema == nil {
+			continue
+		}
+		for _, col := range schema.Columns {
+			if col.ForeignKey == nil || *col.ForeignKey == "" {
+				continue
+			}
+			field := fmt.Sprintf("schema.columns[%s].foreign_key", col.Name)
+			if issue := snap.checkReference(p, field, *col.ForeignKey); issue != nil {
+				issues = append(issues, *issue)
+			}
+		}
+		for i, ref := range schema.RelatedMonikers {
+			field := fmt.Sprintf("schema.related_monikers[%d]", i)
+			if issue := snap.checkReference(p, field, ref); issue != nil {
+				issues = append(issues, *issue)
+			}
+		}
+	}
+
+	return issues
+}
+
+// checkReference validates a single reference against the snapshot
+func (s *snapshot) checkReference(path, field, ref string) *ReferenceIssue {
+	issue := &ReferenceIssue{Path: path, Field: field, Reference: ref}
+
+	m, err := moniker.Parse(ref, true)
+	if err != nil {
+		issue.Kind = ReferenceIssueUnparseable
+		issue.Severity = SeverityError
+		issue.Message = fmt.Sprintf("%s on '%s' is not a valid moniker: %v", field, path, err)
+		return issue
+	}
+
+	target := m.CanonicalPath()
+	if target == path {
+		issue.Kind = ReferenceIssueSelf
+		issue.Severity = SeverityWarning
+		issue.Message = fmt.Sprintf("%s on '%s' references the node itself", field, path)
+		return issue
+	}
+
+	// Exact match, or fall back to the nearest registered ancestor
+	node, ok := s.nodes[target]
+	if !ok {
+		ancestors := ancestorPaths(target)
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if n, found := s.nodes[ancestors[i]]; found {
+				node = n
+				ok = true
+				break
+			}
+		}
+	}
+	if !ok {
+		issue.Kind = ReferenceIssueBroken
+		issue.Severity = SeverityError
+		issue.Message = fmt.Sprintf("%s on '%s' references unknown path '%s'", field, path, target)
+		return issue
+	}
+
+	if node.Status == NodeStatusDeprecated || node.Status == NodeStatusArchived {
+		issue.Kind = ReferenceIssueDeprecatedTarget
+		issue.Severity = SeverityWarning
+		issue.Message = fmt.Sprintf("%s on '%s' references %s node '%s'", field, path, node.Status, node.Path)
+		return issue
+	}
+
+	return nil
+}
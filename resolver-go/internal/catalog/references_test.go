@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func makeSchemaNode(path string, status NodeStatus, fks map[string]string, related ...string) *CatalogNode {
+	node := makeNode(path, path, "", status, true)
+	node.DataSchema = &DataSchema{RelatedMonikers: related}
+	for col, fk := range fks {
+		node.DataSchema.Columns = append(node.DataSchema.Columns, ColumnSchema{
+			Name:       col,
+			DataType:   "string",
+			ForeignKey: strPtr(fk),
+		})
+	}
+	return node
+}
+
+func issuesByKind(issues []ReferenceIssue) map[ReferenceIssueKind][]ReferenceIssue {
+	result := make(map[ReferenceIssueKind][]ReferenceIssue)
+	for _, issue := range issues {
+		result[issue.Kind] = append(result[issue.Kind], issue)
+	}
+	return result
+}
+
+func TestValidateReferencesValid(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("reference", "Reference", "", NodeStatusActive, false))
+	r.Register(makeNode("reference/security", "Security", "", NodeStatusActive, true))
+	r.Register(makeSchemaNode("holdings", NodeStatusActive,
+		map[string]string{"security_id": "reference/security"},
+		// Prefix match: ancestor 'reference/security' is registered
+		"reference/security/ALL"))
+
+	if issues := r.ValidateReferences(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateReferencesBrokenAndUnparseable(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeSchemaNode("holdings", NodeStatusActive,
+		map[string]string{"security_id": "refrence/security"},
+		"bad segment!"))
+
+	byKind := issuesByKind(r.ValidateReferences())
+
+	broken := byKind[ReferenceIssueBroken]
+	if len(broken) != 1 {
+		t.Fatalf("expected 1 broken reference, got %d", len(broken))
+	}
+	if broken[0].Field != "schema.columns[security_id].foreign_key" {
+		t.Errorf("unexpected field %q", broken[0].Field)
+	}
+	if broken[0].Severity != SeverityError {
+		t.Errorf("expected error severity, got %q", broken[0].Severity)
+	}
+
+	unparseable := byKind[ReferenceIssueUnparseable]
+	if len(unparseable) != 1 || unparseable[0].Field != "schema.related_monikers[0]" {
+		t.Errorf("expected unparseable related_monikers[0], got %v", unparseable)
+	}
+}
+
+func TestValidateReferencesSelfAndDeprecated(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("old/prices", "Old", "", NodeStatusDeprecated, true))
+	r.Register(makeSchemaNode("prices", NodeStatusActive, nil, "prices", "old/prices"))
+
+	byKind := issuesByKind(r.ValidateReferences())
+
+	if len(byKind[ReferenceIssueSelf]) != 1 {
+		t.Errorf("expected 1 self reference, got %d", len(byKind[ReferenceIssueSelf]))
+	}
+	deprecated := byKind[ReferenceIssueDeprecatedTarget]
+	if len(deprecated) != 1 {
+		t.Fatalf("expected 1 deprecated target, got %d", len(deprecated))
+	}
+	if deprecated[0].Severity != SeverityWarning {
+		t.Errorf("expected warning severity, got %q", deprecated[0].Severity)
+	}
+}
@@ -1,25 +1,66 @@
 package catalog
 
 import (
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Registry is a thread-safe registry of catalog nodes
+// maxAuditEntries bounds the in-memory audit log; oldest entries are dropped first
+const maxAuditEntries = 10000
+
+// systemActor is recorded for changes made by the loader or reload rather than a caller
+const systemActor = "system"
+
+// Registry is a thread-safe registry of catalog nodes.
+// Reads are lock-free: they load an immutable snapshot. Writers serialize on
+// mu, build a new snapshot (copy-on-write), and swap it in.
 type Registry struct {
-	nodes    map[string]*CatalogNode
-	children map[string]map[string]bool // parent -> children paths
-	mu       sync.RWMutex                // Read-heavy workload
-	auditLog []AuditEntry
+	current    atomic.Pointer[snapshot]
+	generation atomic.Uint64
+	mu         sync.Mutex // Serializes writers and guards auditLog
+	auditLog   []AuditEntry
+	watchers   watchers
 }
 
 // NewRegistry creates a new empty catalog registry
 func NewRegistry() *Registry {
-	return &Registry{
-		nodes:    make(map[string]*CatalogNode),
-		children: make(map[string]map[string]bool),
+	r := &Registry{
 		auditLog: make([]AuditEntry, 0),
 	}
+	r.current.Store(emptySnapshot())
+	return r
+}
+
+// load returns the current snapshot
+func (r *Registry) load() *snapshot {
+	return r.current.Load()
+}
+
+// publish swaps in a new snapshot. Callers hold mu.
+func (r *Registry) publish(next *snapshot) {
+	r.current.Store(next)
+	r.generation.Add(1)
+}
+
+// Generation returns a counter that increases with every change to the
+// registry, including edits made with Update and UpdateStatus.
+// Caches of results derived from the catalog mix it into their keys, so a
+// change makes every earlier entry unreachable; HTTP ETags do the same.
+func (r *Registry) Generation() uint64 {
+	return r.generation.Load()
+}
+
+// Frozen returns a read-only registry of the current catalog: later changes
+// to r do not reach it, so a series of reads from it sees one consistent
+// catalog. Nothing may be written to it.
+func (r *Registry) Frozen() *Registry {
+	f := &Registry{}
+	f.current.Store(r.load())
+	f.generation.Store(r.Generation())
+	return f
 }
 
 // Register registers a catalog node
@@ -27,15 +68,18 @@ func (r *Registry) Register(node *CatalogNode) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.nodes[node.Path] = node
-	// Update parent's children set
-	parentPath := parentPath(node.Path)
-	if parentPath != nil {
-		if r.children[*parentPath] == nil {
-			r.children[*parentPath] = make(map[string]bool)
-		}
-		r.children[*parentPath][node.Path] = true
+	old := r.load()
+	action, kind := "created", ChangeAdded
+	if _, exists := old.nodes[node.Path]; exists {
+		action, kind = "updated", ChangeUpdated
 	}
+
+	next := old.clone()
+	next.put(node)
+	r.publish(next)
+
+	r.appendAudit(newAuditEntry(node.Path, action, systemActor, nil, nil, nil))
+	r.watchers.emit(ChangeEvent{Kind: kind, Path: node.Path, NewStatus: node.Status})
 }
 
 // RegisterMany registers multiple nodes atomically
@@ -43,33 +87,55 @@ func (r *Registry) RegisterMany(nodes []*CatalogNode) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	next := r.load().clone()
+	events := make([]ChangeEvent, 0, len(nodes))
 	for _, node := range nodes {
-		r.nodes[node.Path] = node
-		parentPath := parentPath(node.Path)
-		if parentPath != nil {
-			if r.children[*parentPath] == nil {
-				r.children[*parentPath] = make(map[string]bool)
-			}
-			r.children[*parentPath][node.Path] = true
+		action, kind := "created", ChangeAdded
+		if _, exists := next.nodes[node.Path]; exists {
+			action, kind = "updated", ChangeUpdated
 		}
+		r.appendAudit(newAuditEntry(node.Path, action, systemActor, nil, nil, nil))
+		events = append(events, ChangeEvent{Kind: kind, Path: node.Path, NewStatus: node.Status})
+
+		next.put(node)
+	}
+	r.publish(next)
+
+	for _, event := range events {
+		r.watchers.emit(event)
 	}
 }
 
-// Get returns a node by path
-func (r *Registry) Get(path string) *CatalogNode {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// Deregister removes a node by path. Its descendants are left in place.
+// Returns false if the path was not registered.
+func (r *Registry) Deregister(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+	node, ok := old.nodes[path]
+	if !ok {
+		return false
+	}
+
+	next := old.clone()
+	next.remove(path)
+	r.publish(next)
 
-	return r.nodes[path]
+	r.appendAudit(newAuditEntry(path, "removed", systemActor, nil, nil, nil))
+	r.watchers.emit(ChangeEvent{Kind: ChangeRemoved, Path: path, OldStatus: node.Status})
+	return true
+}
+
+// Get returns a node by path. Registered nodes are shared by concurrent
+// readers and must not be modified; change them with Update or UpdateStatus.
+func (r *Registry) Get(path string) *CatalogNode {
+	return r.load().nodes[path]
 }
 
 // GetOrVirtual returns a node, or creates a virtual node if it doesn't exist
 func (r *Registry) GetOrVirtual(path string) *CatalogNode {
-	r.mu.RLock()
-	node := r.nodes[path]
-	r.mu.RUnlock()
-
-	if node != nil {
+	if node := r.load().nodes[path]; node != nil {
 		return node
 	}
 
@@ -82,22 +148,17 @@ func (r *Registry) GetOrVirtual(path string) *CatalogNode {
 
 // Exists checks if a path exists in the catalog
 func (r *Registry) Exists(path string) bool {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	_, exists := r.nodes[path]
+	_, exists := r.load().nodes[path]
 	return exists
 }
 
 // Children returns direct children of a path
 func (r *Registry) Children(path string) []*CatalogNode {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	childPaths := r.children[path]
+	snap := r.load()
+	childPaths := snap.children[path]
 	result := make([]*CatalogNode, 0, len(childPaths))
 	for p := range childPaths {
-		if node, ok := r.nodes[p]; ok {
+		if node, ok := snap.nodes[p]; ok {
 			result = append(result, node)
 		}
 	}
@@ -106,10 +167,7 @@ func (r *Registry) Children(path string) []*CatalogNode {
 
 // ChildrenPaths returns paths of direct children
 func (r *Registry) ChildrenPaths(path string) []string {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	childPaths := r.children[path]
+	childPaths := r.load().children[path]
 	result := make([]string, 0, len(childPaths))
 	for p := range childPaths {
 		result = append(result, p)
@@ -120,8 +178,12 @@ func (r *Registry) ChildrenPaths(path string) []string {
 // ResolveOwnership resolves effective ownership for a path by walking up the hierarchy
 // Each ownership field inherits independently from the nearest ancestor that defines it
 func (r *Registry) ResolveOwnership(path string) *ResolvedOwnership {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	return r.load().resolveOwnership(path)
+}
+
+// resolveOwnership is ResolveOwnership within one snapshot
+func (s *snapshot) resolveOwnership(path string) *ResolvedOwnership {
+	nodes := s.nodes
 
 	// Collect all paths from root to this node
 	paths := append(ancestorPaths(path), path)
@@ -131,7 +193,7 @@ func (r *Registry) ResolveOwnership(path string) *ResolvedOwnership {
 
 	// Walk from root to leaf, each level can override
 	for _, p := range paths {
-		node, ok := r.nodes[p]
+		node, ok := nodes[p]
 		if !ok || node.Ownership == nil {
 			continue
 		}
@@ -189,102 +251,394 @@ func (r *Registry) ResolveOwnership(path string) *ResolvedOwnership {
 	return result
 }
 
+// ResolveSLA resolves the effective SLA for a path by walking up the hierarchy.
+// Each field inherits independently from the nearest node that defines it.
+// Returns nil if no node in the hierarchy defines any SLA field.
+func (r *Registry) ResolveSLA(path string) *ResolvedSLA {
+	nodes := r.load().nodes
+
+	result := &ResolvedSLA{}
+	found := false
+
+	for _, p := range append(ancestorPaths(path), path) {
+		node, ok := nodes[p]
+		if !ok || node.SLA == nil {
+			continue
+		}
+		sla := node.SLA
+		p := p
+
+		if sla.Freshness != nil {
+			result.Freshness, result.FreshnessSource = sla.Freshness, &p
+			found = true
+		}
+		if sla.Availability != nil {
+			result.Availability, result.AvailabilitySource = sla.Availability, &p
+			found = true
+		}
+		if sla.SupportHours != nil {
+			result.SupportHours, result.SupportHoursSource = sla.SupportHours, &p
+			found = true
+		}
+		if sla.EscalationContact != nil {
+			result.EscalationContact, result.EscalationContactSource = sla.EscalationContact, &p
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return result
+}
+
+// ResolveDataQuality resolves effective data quality for a path by walking up the hierarchy.
+// Each field inherits independently from the nearest node that defines it; list fields
+// are taken whole from the nearest node with a non-empty list.
+// Returns nil if no node in the hierarchy defines any data quality field.
+func (r *Registry) ResolveDataQuality(path string) *ResolvedDataQuality {
+	return r.load().resolveDataQuality(path)
+}
+
+// resolveDataQuality is ResolveDataQuality within one snapshot
+func (s *snapshot) resolveDataQuality(path string) *ResolvedDataQuality {
+	nodes := s.nodes
+
+	result := &ResolvedDataQuality{}
+	found := false
+
+	for _, p := range append(ancestorPaths(path), path) {
+		node, ok := nodes[p]
+		if !ok || node.DataQuality == nil {
+			continue
+		}
+		dq := node.DataQuality
+		p := p
+
+		if dq.DQOwner != nil {
+			result.DQOwner, result.DQOwnerSource = dq.DQOwner, &p
+			found = true
+		}
+		if dq.QualityScore != nil {
+			result.QualityScore, result.QualityScoreSource = dq.QualityScore, &p
+			found = true
+		}
+		if len(dq.ValidationRules) > 0 {
+			result.ValidationRules, result.ValidationRulesSource = dq.ValidationRules, &p
+			found = true
+		}
+		if len(dq.KnownIssues) > 0 {
+			result.KnownIssues, result.KnownIssuesSource = dq.KnownIssues, &p
+			found = true
+		}
+		if dq.LastValidated != nil {
+			result.LastValidated, result.LastValidatedSource = dq.LastValidated, &p
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return result
+}
+
+// ResolveClassification resolves the effective classification for a path.
+// The nearest node (self first, then ancestors) with a classification wins.
+// If none is set, DefaultClassification is returned with an empty definedAt.
+func (r *Registry) ResolveClassification(path string) (value, definedAt string) {
+	return r.load().resolveClassification(path)
+}
+
+func (s *snapshot) resolveClassification(path string) (value, definedAt string) {
+	paths := append(ancestorPaths(path), path)
+	for i := len(paths) - 1; i >= 0; i-- {
+		if node, ok := s.nodes[paths[i]]; ok && node.Classification != "" {
+			return node.Classification, paths[i]
+		}
+	}
+	return DefaultClassification, ""
+}
+
+// ResolveTags resolves the effective tags for a path, merging tags from all
+// ancestors. A tag defined at several levels reports the nearest definition.
+func (r *Registry) ResolveTags(path string) []TagWithSource {
+	return r.load().resolveTags(path)
+}
+
+// resolveTags merges tags root to leaf
+func (s *snapshot) resolveTags(path string) []TagWithSource {
+	result := make([]TagWithSource, 0)
+	index := make(map[string]int)
+
+	for _, p := range append(ancestorPaths(path), path) {
+		node, ok := s.nodes[p]
+		if !ok {
+			continue
+		}
+		for _, tag := range node.Tags {
+			if i, seen := index[tag]; seen {
+				result[i].DefinedAt = p
+				continue
+			}
+			index[tag] = len(result)
+			result = append(result, TagWithSource{Tag: tag, DefinedAt: p})
+		}
+	}
+	return result
+}
+
 // FindSourceBinding finds the source binding for a path
 // Returns the binding and the path where it was defined
 // If the exact path doesn't have a binding, walks up to find a parent with a binding
 func (r *Registry) FindSourceBinding(path string) (*SourceBinding, string) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	// First check exact match
-	if node, ok := r.nodes[path]; ok && node.SourceBinding != nil {
-		// Skip non-resolvable statuses
-		if node.Status == NodeStatusArchived || node.Status == NodeStatusDraft || node.Status == NodeStatusPendingReview {
-			// Fall through to ancestor check
-		} else {
-			return node.SourceBinding, path
+	return r.findSourceBinding(path, nil)
+}
+
+// BindingCandidate is a path FindSourceBinding checked, for explaining a
+// resolution
+type BindingCandidate struct {
+	Path string `json:"path"`
+	// Result is "selected", "no_node", "no_binding", or the status (archived,
+	// draft, pending_review) that skipped the node's binding
+	Result string `json:"result"`
+}
+
+// TraceSourceBinding is FindSourceBinding, also returning every path checked,
+// exact path first, up to the one selected
+func (r *Registry) TraceSourceBinding(path string) (*SourceBinding, string, []BindingCandidate) {
+	trace := make([]BindingCandidate, 0)
+	binding, bindingPath := r.findSourceBinding(path, &trace)
+	return binding, bindingPath, trace
+}
+
+// findSourceBinding implements FindSourceBinding, appending each path checked
+// to trace when it is non-nil
+func (r *Registry) findSourceBinding(path string, trace *[]BindingCandidate) (*SourceBinding, string) {
+	nodes := r.load().nodes
+
+	check := func(p string) bool {
+		node, ok := nodes[p]
+		result := "selected"
+		switch {
+		case !ok:
+			result = "no_node"
+		case node.SourceBinding == nil:
+			result = "no_binding"
+		case node.Status == NodeStatusArchived || node.Status == NodeStatusDraft || node.Status == NodeStatusPendingReview:
+			// Skip non-resolvable statuses
+			result = string(node.Status)
+		}
+		if trace != nil {
+			*trace = append(*trace, BindingCandidate{Path: p, Result: result})
 		}
+		return result == "selected"
 	}
 
-	// Walk up hierarchy
+	// First check exact match, then walk up hierarchy
+	if check(path) {
+		return nodes[path].SourceBinding, path
+	}
 	ancestors := ancestorPaths(path)
 	for i := len(ancestors) - 1; i >= 0; i-- {
-		ancestor := ancestors[i]
-		if node, ok := r.nodes[ancestor]; ok && node.SourceBinding != nil {
-			if node.Status == NodeStatusArchived || node.Status == NodeStatusDraft || node.Status == NodeStatusPendingReview {
-				continue
-			}
-			return node.SourceBinding, ancestor
+		if check(ancestors[i]) {
+			return nodes[ancestors[i]].SourceBinding, ancestors[i]
 		}
 	}
 
 	return nil, ""
 }
 
-// AllPaths returns all registered paths
+// AllPaths returns all registered paths, sorted
 func (r *Registry) AllPaths() []string {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	sorted := r.load().sortedPaths()
+	return append(make([]string, 0, len(sorted)), sorted...)
+}
 
-	paths := make([]string, 0, len(r.nodes))
-	for p := range r.nodes {
-		paths = append(paths, p)
+// PathsAfter returns up to limit registered paths, in order, starting
+// strictly after the path after ("" starts at the first), the number of
+// registered paths and whether more follow the page. All come from one
+// snapshot of the registry, so paging with the last path returned as the
+// next after visits every path once.
+func (r *Registry) PathsAfter(after string, limit int) (paths []string, total int, more bool) {
+	sorted := r.load().sortedPaths()
+	start := sort.SearchStrings(sorted, after)
+	if start < len(sorted) && sorted[start] == after {
+		start++
+	}
+	end := len(sorted)
+	if limit < end-start {
+		end = start + limit
 	}
-	return paths
+	return append(make([]string, 0, end-start), sorted[start:end]...), len(sorted), end < len(sorted)
 }
 
 // AllNodes returns all registered nodes
 func (r *Registry) AllNodes() []*CatalogNode {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	snap := r.load()
 
-	nodes := make([]*CatalogNode, 0, len(r.nodes))
-	for _, node := range r.nodes {
+	nodes := make([]*CatalogNode, 0, len(snap.nodes))
+	for _, node := range snap.nodes {
 		nodes = append(nodes, node)
 	}
 	return nodes
 }
 
+// Version returns the digest of the catalog as loaded, so results can be
+// correlated with a catalog state. It is empty if the catalog was never
+// versioned or has been modified since it was loaded.
+func (r *Registry) Version() string {
+	return r.load().version
+}
+
+// SetVersion records the digest of the catalog currently registered
+func (r *Registry) SetVersion(version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := r.load().clone()
+	next.version = version
+	r.publish(next)
+}
+
 // Clear clears all nodes
 func (r *Registry) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.nodes = make(map[string]*CatalogNode)
-	r.children = make(map[string]map[string]bool)
+	r.publish(emptySnapshot())
 }
 
 // AtomicReplace atomically replaces all nodes with a new set
 // This is for hot reload - build the new catalog, then swap
 func (r *Registry) AtomicReplace(newNodes []*CatalogNode) {
-	newNodesDict := make(map[string]*CatalogNode)
-	newChildren := make(map[string]map[string]bool)
-
-	for _, node := range newNodes {
-		newNodesDict[node.Path] = node
-		parentPath := parentPath(node.Path)
-		if parentPath != nil {
-			if newChildren[*parentPath] == nil {
-				newChildren[*parentPath] = make(map[string]bool)
-			}
-			newChildren[*parentPath][node.Path] = true
+	r.AtomicReplaceVersion(newNodes, "")
+}
+
+// AtomicReplaceVersion is AtomicReplace, publishing version together with the
+// new nodes so readers never see one without the other
+func (r *Registry) AtomicReplaceVersion(newNodes []*CatalogNode, version string) {
+	r.replace(newNodes, version, systemActor, "catalog reload", nil)
+}
+
+// replace swaps in newNodes under the write lock, recording per-node audit
+// entries by actor with reason as details. record, if set, is called under
+// the lock with the version replaced and the diff applied.
+func (r *Registry) replace(newNodes []*CatalogNode, version, actor, reason string, record func(oldVersion string, diff *CatalogDiff)) *CatalogDiff {
+	next := buildSnapshot(newNodes)
+	next.version = version
+	newNodesDict := next.nodes
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+
+	// Record per-node audit entries and change events for the bulk replace
+	diff := diffNodes(old.nodes, newNodes)
+	changes := make([]ChangeEvent, 0, len(diff.Added)+len(diff.Removed)+len(diff.Modified))
+	for _, p := range diff.Added {
+		r.appendAudit(newAuditEntry(p, "created", actor, nil, nil, &reason))
+		changes = append(changes, ChangeEvent{Kind: ChangeAdded, Path: p, NewStatus: newNodesDict[p].Status})
+	}
+	for _, p := range diff.Removed {
+		r.appendAudit(newAuditEntry(p, "removed", actor, nil, nil, &reason))
+		changes = append(changes, ChangeEvent{Kind: ChangeRemoved, Path: p, OldStatus: old.nodes[p].Status})
+	}
+	for _, m := range diff.Modified {
+		fields := make([]string, 0, len(m.Changes))
+		for _, c := range m.Changes {
+			fields = append(fields, c.Field)
+		}
+		details := reason + ": " + strings.Join(fields, ", ")
+		r.appendAudit(newAuditEntry(m.Path, "updated", actor, m.OldFingerprint, m.NewFingerprint, &details))
+
+		kind := ChangeUpdated
+		oldStatus, newStatus := old.nodes[m.Path].Status, newNodesDict[m.Path].Status
+		if oldStatus != newStatus {
+			kind = ChangeStatusChanged
 		}
+		changes = append(changes, ChangeEvent{Kind: kind, Path: m.Path, OldStatus: oldStatus, NewStatus: newStatus})
 	}
 
+	if record != nil {
+		record(old.version, diff)
+	}
+	r.publish(next)
+
+	if len(changes) > 0 {
+		r.watchers.emit(ChangeEvent{Kind: ChangeBatch, Changes: changes})
+	}
+	return diff
+}
+
+// RecordAudit records an audit entry for a change made outside the registry's
+// own mutation methods (e.g. by an admin handler)
+func (r *Registry) RecordAudit(path, action, actor string, oldValue, newValue, details *string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.nodes = newNodesDict
-	r.children = newChildren
+	r.appendAudit(newAuditEntry(path, action, actor, oldValue, newValue, details))
+}
+
+// AuditEntries returns audit entries for a path, newest first.
+// An empty path returns entries for all paths; limit <= 0 means no limit;
+// since, if set, excludes entries recorded before that time.
+func (r *Registry) AuditEntries(path string, limit int, since *time.Time) []AuditEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]AuditEntry, 0)
+	for i := len(r.auditLog) - 1; i >= 0; i-- {
+		entry := r.auditLog[i]
+		if path != "" && entry.Path != path {
+			continue
+		}
+		if since != nil {
+			ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+			if err == nil && ts.Before(*since) {
+				continue
+			}
+		}
+		result = append(result, entry)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// appendAudit appends entries, trimming the oldest beyond maxAuditEntries.
+// Caller must hold mu.
+func (r *Registry) appendAudit(entries ...AuditEntry) {
+	r.auditLog = append(r.auditLog, entries...)
+	if over := len(r.auditLog) - maxAuditEntries; over > 0 {
+		r.auditLog = append(make([]AuditEntry, 0, maxAuditEntries), r.auditLog[over:]...)
+	}
+}
+
+func newAuditEntry(path, action, actor string, oldValue, newValue, details *string) AuditEntry {
+	return AuditEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Path:      path,
+		Action:    action,
+		Actor:     actor,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Details:   details,
+	}
+}
+
+func strPtrOf(s string) *string {
+	return &s
 }
 
 // FindByStatus returns all nodes with a given lifecycle status
 func (r *Registry) FindByStatus(status NodeStatus) []*CatalogNode {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	nodes := r.load().nodes
 
 	result := make([]*CatalogNode, 0)
-	for _, node := range r.nodes {
+	for _, node := range nodes {
 		if node.Status == status {
 			result = append(result, node)
 		}
@@ -302,16 +656,54 @@ func (r *Registry) FindDeprecated() []*CatalogNode {
 	return r.FindByStatus(NodeStatusDeprecated)
 }
 
+// SearchOptions controls catalog search. Nodes must pass every filter set;
+// the limit applies to the nodes that do.
+type SearchOptions struct {
+	Status *NodeStatus
+	Limit  int
+
+	// IncludeInheritedTags matches tags inherited from ancestors as well as the node's own
+	IncludeInheritedTags bool
+
+	SourceType     *SourceType // Type of the node's own source binding
+	Tags           []string    // Tags the node must all carry, case-insensitively
+	Classification string      // Resolved classification, case-insensitively
+	Domain         string      // Resolved domain, case-insensitively
+	IsLeaf         *bool
+
+	// MinQualityScore excludes nodes whose resolved quality score is lower
+	// or unset
+	MinQualityScore *float64
+}
+
 // Search searches catalog nodes by path, display_name, description, or tags
 func (r *Registry) Search(query string, status *NodeStatus, limit int) []*CatalogNode {
-	queryLower := strings.ToLower(query)
+	return r.SearchWithOptions(query, SearchOptions{Status: status, Limit: limit})
+}
 
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// SearchWithOptions searches catalog nodes by path, display_name, description,
+// or tags, in path order. An empty query matches every node that passes the
+// filters.
+func (r *Registry) SearchWithOptions(query string, opts SearchOptions) []*CatalogNode {
+	queryLower := strings.ToLower(query)
+	limit := opts.Limit
 
+	snap := r.load()
 	results := make([]*CatalogNode, 0, limit)
-	for _, node := range r.nodes {
-		if status != nil && node.Status != *status {
+	for _, p := range snap.sortedPaths() {
+		if len(results) >= limit {
+			break
+		}
+		node := snap.nodes[p]
+		tags := node.Tags
+		if opts.IncludeInheritedTags {
+			inherited := snap.resolveTags(node.Path)
+			tags = make([]string, len(inherited))
+			for i, t := range inherited {
+				tags[i] = t.Tag
+			}
+		}
+		if !snap.passesFilters(node, tags, opts) {
 			continue
 		}
 
@@ -320,42 +712,66 @@ func (r *Registry) Search(query string, status *NodeStatus, limit int) []*Catalo
 			strings.Contains(strings.ToLower(node.DisplayName), queryLower) ||
 			strings.Contains(strings.ToLower(node.Description), queryLower) {
 			results = append(results, node)
-			if len(results) >= limit {
-				break
-			}
 			continue
 		}
-
-		// Check tags
-		for _, tag := range node.Tags {
+		for _, tag := range tags {
 			if strings.Contains(strings.ToLower(tag), queryLower) {
 				results = append(results, node)
-				if len(results) >= limit {
-					break
-				}
 				break
 			}
 		}
-
-		if len(results) >= limit {
-			break
-		}
 	}
 
 	return results
 }
 
+// passesFilters reports whether node, carrying tags, passes every filter of opts
+func (s *snapshot) passesFilters(node *CatalogNode, tags []string, opts SearchOptions) bool {
+	if opts.Status != nil && node.Status != *opts.Status {
+		return false
+	}
+	if opts.IsLeaf != nil && node.IsLeaf != *opts.IsLeaf {
+		return false
+	}
+	if opts.SourceType != nil && (node.SourceBinding == nil || node.SourceBinding.SourceType != *opts.SourceType) {
+		return false
+	}
+	if opts.Classification != "" {
+		if classification, _ := s.resolveClassification(node.Path); !strings.EqualFold(classification, opts.Classification) {
+			return false
+		}
+	}
+	if opts.Domain != "" && !strings.EqualFold(s.resolveDomain(node.Path), opts.Domain) {
+		return false
+	}
+	if opts.MinQualityScore != nil {
+		dq := s.resolveDataQuality(node.Path)
+		if dq == nil || dq.QualityScore == nil || *dq.QualityScore < *opts.MinQualityScore {
+			return false
+		}
+	}
+	for _, want := range opts.Tags {
+		found := false
+		for _, tag := range tags {
+			found = found || strings.EqualFold(tag, want)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // Count returns counts by status
 func (r *Registry) Count() map[string]int {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	nodes := r.load().nodes
 
 	counts := make(map[string]int)
-	for _, node := range r.nodes {
+	for _, node := range nodes {
 		key := string(node.Status)
 		counts[key] = counts[key] + 1
 	}
-	counts["total"] = len(r.nodes)
+	counts["total"] = len(nodes)
 	return counts
 }
 
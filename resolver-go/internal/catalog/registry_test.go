@@ -1,6 +1,7 @@
 package catalog
 
 import (
+	"fmt"
 	"sort"
 	"testing"
 )
@@ -288,6 +289,34 @@ func TestFindSourceBindingSkipsDraft(t *testing.T) {
 	}
 }
 
+func TestTraceSourceBinding(t *testing.T) {
+	r := NewRegistry()
+	parent := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	parent.SourceBinding = &SourceBinding{SourceType: SourceTypeOracle, Config: map[string]interface{}{}}
+	r.Register(parent)
+	child := makeNode("prices/equity", "Equity", "", NodeStatusDraft, true)
+	child.SourceBinding = &SourceBinding{SourceType: SourceTypeSnowflake, Config: map[string]interface{}{}}
+	r.Register(child)
+
+	binding, path, trace := r.TraceSourceBinding("prices/equity/us")
+	if binding == nil || path != "prices" {
+		t.Fatalf("expected the prices binding, got %v at %q", binding, path)
+	}
+	want := []BindingCandidate{
+		{Path: "prices/equity/us", Result: "no_node"},
+		{Path: "prices/equity", Result: "draft"},
+		{Path: "prices", Result: "selected"},
+	}
+	if len(trace) != len(want) {
+		t.Fatalf("expected %v, got %v", want, trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Errorf("step %d: expected %v, got %v", i, want[i], trace[i])
+		}
+	}
+}
+
 // --- AllPaths ---
 
 func TestAllPaths(t *testing.T) {
@@ -376,6 +405,40 @@ func TestSearchLimitHonored(t *testing.T) {
 	}
 }
 
+func TestSearchFiltersBeforeLimit(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.Classification, root.Domain = "confidential", strPtr("markets")
+	r.Register(root)
+	for i := 0; i < 10; i++ {
+		node := makeNode(fmt.Sprintf("prices/item%d", i), "Item", "", NodeStatusActive, true)
+		if i%2 == 1 {
+			node.Tags = []string{"EOD", "golden"}
+			node.SourceBinding = &SourceBinding{SourceType: SourceTypeOracle}
+		}
+		r.Register(node)
+	}
+	r.Register(makeNode("rates/item", "Item", "", NodeStatusActive, true))
+
+	oracle := SourceTypeOracle
+	results := r.SearchWithOptions("item", SearchOptions{Limit: 3, SourceType: &oracle, Tags: []string{"eod", "golden"}})
+	if len(results) != 3 || results[0].Path != "prices/item1" || results[2].Path != "prices/item5" {
+		t.Errorf("expected the first three matching items in path order, got %v", nodePaths(results))
+	}
+	if results := r.SearchWithOptions("item", SearchOptions{Limit: 10, Tags: []string{"eod", "missing"}}); len(results) != 0 {
+		t.Errorf("expected every tag to be required, got %v", nodePaths(results))
+	}
+
+	// An empty query browses by facet; classification and domain are inherited
+	leaf := false
+	if results := r.SearchWithOptions("", SearchOptions{Limit: 50, Classification: "Confidential", Domain: "markets", IsLeaf: &leaf}); len(results) != 1 || results[0].Path != "prices" {
+		t.Errorf("expected only the prices branch, got %v", nodePaths(results))
+	}
+	if results := r.SearchWithOptions("", SearchOptions{Limit: 50, Domain: "markets"}); len(results) != 11 {
+		t.Errorf("expected prices and its items in the markets domain, got %d", len(results))
+	}
+}
+
 // --- Count ---
 
 func TestCount(t *testing.T) {
@@ -524,3 +587,52 @@ func TestDotSeparatedHierarchy(t *testing.T) {
 		t.Errorf("expected child 'analytics.risk/var', got %v", children2)
 	}
 }
+
+// --- Generation ---
+
+func TestGenerationAdvancesOnEveryChange(t *testing.T) {
+	r := NewRegistry()
+	last := r.Generation()
+	advanced := func(what string) {
+		t.Helper()
+		if g := r.Generation(); g <= last {
+			t.Errorf("%s should advance the generation (still %d)", what, g)
+		} else {
+			last = g
+		}
+	}
+
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	advanced("Register")
+	r.AtomicReplace([]*CatalogNode{makeNode("prices", "Prices", "", NodeStatusActive, false)})
+	advanced("AtomicReplace")
+	r.UpdateStatus("prices", NodeStatusDeprecated, "alice", "")
+	advanced("UpdateStatus")
+	r.Deregister("prices")
+	advanced("Deregister")
+
+	r.Get("prices")
+	r.AllNodes()
+	if r.Generation() != last {
+		t.Error("reads should not advance the generation")
+	}
+}
+
+func TestFrozenRegistryIgnoresLaterChanges(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&CatalogNode{Path: "prices", Status: NodeStatusActive})
+	frozen := r.Frozen()
+
+	r.Register(&CatalogNode{Path: "prices/fx", Status: NodeStatusActive})
+	r.UpdateStatus("prices", NodeStatusDeprecated, "alice", "")
+
+	if frozen.Get("prices/fx") != nil || len(frozen.ChildrenPaths("prices")) != 0 {
+		t.Error("expected the frozen registry not to see the new node")
+	}
+	if got := frozen.Get("prices").Status; got != NodeStatusActive {
+		t.Errorf("expected the frozen registry to keep the old status, got %s", got)
+	}
+	if r.Get("prices/fx") == nil {
+		t.Error("expected the registry itself to change")
+	}
+}
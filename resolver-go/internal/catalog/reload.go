@@ -0,0 +1,325 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultReloadDebounce coalesces the burst of events an editor or deploy
+// produces when rewriting catalog files
+const defaultReloadDebounce = 500 * time.Millisecond
+
+// ReloadStatus describes the outcome of the most recent reloads
+type ReloadStatus struct {
+	Sources     []string   `json:"sources"`
+	Overlay     string     `json:"overlay,omitempty"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	LastReload  *time.Time `json:"last_reload,omitempty"` // Last time a catalog was accepted
+	Checksum    string     `json:"checksum,omitempty"`    // SHA-256 of the accepted catalog
+	LastError   string     `json:"last_error,omitempty"`  // Cleared by the next successful reload
+	Reloads     int        `json:"reloads"`
+	Failures    int        `json:"failures"`
+
+	// Manifests the accepted catalog was verified against
+	Manifests []*Manifest `json:"manifests,omitempty"`
+}
+
+// ReloadManager reloads catalogs when their files change, on a periodic poll,
+// or on demand. A new catalog is validated before it replaces the live one;
+// a catalog that fails to load or validate leaves the live catalog in place.
+type ReloadManager struct {
+	registry *Registry
+	policy   ConflictPolicy
+	sources  []string
+
+	// Overlay, if set, is an overlay file applied on top of the merged sources
+	Overlay string
+	// StrictKeys rejects catalogs with unknown YAML keys
+	StrictKeys bool
+	// Strict also rejects catalogs with hierarchy warnings
+	Strict bool
+	// Debounce is how long to wait after the last file event before reloading
+	Debounce time.Duration
+	// PollInterval, if positive, reloads periodically regardless of file events.
+	// This is the only trigger for remote catalogs.
+	PollInterval time.Duration
+	// OnReload, if set, is called after every reload attempt. diff is nil when
+	// the attempt failed and empty when the catalog was unchanged.
+	OnReload func(diff *CatalogDiff, err error)
+
+	mu     sync.Mutex // Serializes reloads and guards status
+	status ReloadStatus
+}
+
+// NewReloadManager creates a manager for the given catalog files, directories,
+// or URLs, merged with policy
+func NewReloadManager(reg *Registry, policy ConflictPolicy, sources ...string) *ReloadManager {
+	return &ReloadManager{
+		registry: reg,
+		policy:   policy,
+		sources:  sources,
+		Debounce: defaultReloadDebounce,
+		status:   ReloadStatus{Sources: sources},
+	}
+}
+
+// Status returns a copy of the current reload status
+func (m *ReloadManager) Status() ReloadStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status
+	status.Overlay = m.Overlay
+	return status
+}
+
+// Loaded records nodes loaded outside the manager, e.g. at startup, as the
+// accepted catalog and sets the registry's version to its checksum
+func (m *ReloadManager) Loaded(nodes []*CatalogNode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now().UTC()
+	m.status.LastReload = &now
+	m.status.Checksum = catalogChecksum(nodes)
+	m.status.Manifests = make([]*Manifest, 0)
+	for _, source := range m.watched() {
+		if manifest, err := FindManifest(source); err == nil && manifest != nil {
+			m.status.Manifests = append(m.status.Manifests, manifest)
+		}
+	}
+	m.registry.SetVersion(m.status.Checksum)
+}
+
+// Reload loads and validates the catalog, then swaps it in with AtomicReplace.
+// An unchanged catalog (same checksum) is not swapped and returns an empty diff.
+func (m *ReloadManager) Reload() (*CatalogDiff, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	m.status.LastAttempt = &now
+
+	diff, checksum, manifests, err := m.reload()
+	if err != nil {
+		m.status.Failures++
+		m.status.LastError = err.Error()
+	} else {
+		m.status.LastReload = &now
+		m.status.LastError = ""
+		m.status.Manifests = manifests
+		if checksum != m.status.Checksum {
+			m.status.Reloads++
+			m.status.Checksum = checksum
+		}
+	}
+
+	if m.OnReload != nil {
+		m.OnReload(diff, err)
+	}
+	return diff, err
+}
+
+func (m *ReloadManager) reload() (*CatalogDiff, string, []*Manifest, error) {
+	nodes, _, manifests, err := loadCatalogs(m.policy, m.sources...)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if m.Overlay != "" {
+		if nodes, err = ApplyOverlayFile(nodes, m.Overlay); err != nil {
+			return nil, "", nil, err
+		}
+		if manifest, _ := FindManifest(m.Overlay); manifest != nil {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	checksum := catalogChecksum(nodes)
+	if checksum == m.status.Checksum {
+		return &CatalogDiff{}, checksum, manifests, nil
+	}
+
+	if err := m.validate(nodes); err != nil {
+		return nil, "", nil, err
+	}
+
+	diff := m.registry.Diff(nodes)
+	m.registry.AtomicReplaceVersion(nodes, checksum)
+	return diff, checksum, manifests, nil
+}
+
+// validate rejects a candidate catalog with Lint errors, the same errors that
+// make /catalog/validate report it invalid. Unknown fields only count with
+// StrictKeys; hierarchy warnings count with Strict.
+func (m *ReloadManager) validate(nodes []*CatalogNode) error {
+	problems := make([]string, 0)
+	for _, issue := range Lint(nodes).Issues {
+		switch {
+		case issue.Check == LintCheckUnknownField && !m.StrictKeys:
+		case issue.Severity == SeverityError, issue.Check == LintCheckHierarchy && m.Strict:
+			problems = append(problems, issue.Message)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("catalog failed validation with %d issues: %s", len(problems), strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Watch reloads on file changes (debounced) and on the poll interval until
+// ctx is cancelled. Remote sources, and local sources that cannot be watched,
+// are only polled; the latter are reported in the status's LastError.
+func (m *ReloadManager) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create catalog watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Files are watched through their directory so editors that replace the
+	// file (write to temp, rename) are still seen. Any catalog file in that
+	// directory counts, which covers includes kept next to the main file;
+	// includes elsewhere are only picked up by the poll.
+	dirs := make(map[string]bool)
+	trees := make([]string, 0)
+	for _, source := range m.watched() {
+		if IsRemoteCatalog(source) {
+			continue
+		}
+		if err := addWatch(watcher, source, dirs, &trees); err != nil {
+			m.mu.Lock()
+			m.status.LastError = err.Error()
+			m.mu.Unlock()
+		}
+	}
+
+	var poll <-chan time.Time
+	if m.PollInterval > 0 {
+		ticker := time.NewTicker(m.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	debounce := time.NewTimer(m.Debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !relevantEvent(event, dirs, trees) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = watchTree(watcher, event.Name)
+				}
+			}
+			debounce.Reset(m.Debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			m.mu.Lock()
+			m.status.LastError = fmt.Sprintf("watch: %v", err)
+			m.mu.Unlock()
+		case <-debounce.C:
+			m.Reload()
+		case <-poll:
+			m.Reload()
+		}
+	}
+}
+
+// watched returns the sources followed by the overlay, if any
+func (m *ReloadManager) watched() []string {
+	if m.Overlay == "" {
+		return m.sources
+	}
+	return append(append([]string{}, m.sources...), m.Overlay)
+}
+
+// relevantEvent reports whether a file event can affect the catalog: a change
+// to a catalog file in a watched directory, or a directory added or removed
+// inside a watched tree
+func relevantEvent(event fsnotify.Event, dirs map[string]bool, trees []string) bool {
+	inTree := false
+	for _, tree := range trees {
+		if strings.HasPrefix(event.Name, tree+string(filepath.Separator)) {
+			inTree = true
+			break
+		}
+	}
+	if !inTree && !dirs[filepath.Dir(event.Name)] {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(event.Name)) {
+	case ".yaml", ".yml", ".json", ManifestExt:
+		return true
+	case "":
+		return inTree && (event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename))
+	}
+	return false
+}
+
+// addWatch watches a local catalog source: a directory recursively, a file
+// through its parent directory
+func addWatch(watcher *fsnotify.Watcher, source string, dirs map[string]bool, trees *[]string) error {
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", source, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", source, err)
+	}
+	if info.IsDir() {
+		if err := watchTree(watcher, abs); err != nil {
+			return err
+		}
+		*trees = append(*trees, abs)
+		return nil
+	}
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		return fmt.Errorf("watch %s: %w", source, err)
+	}
+	dirs[filepath.Dir(abs)] = true
+	return nil
+}
+
+// watchTree adds dir and every directory below it to the watcher
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := watcher.Add(p); err != nil {
+				return fmt.Errorf("watch catalog: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// catalogChecksum returns the SHA-256 of the catalog's canonical JSON export
+func catalogChecksum(nodes []*CatalogNode) string {
+	data, _ := json.Marshal(exportCatalog(nodes))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
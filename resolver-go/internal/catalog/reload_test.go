@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReloadManagerSwapsValidCatalog(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+
+	reg := NewRegistry()
+	m := NewReloadManager(reg, ConflictError, file)
+	if _, err := m.Reload(); err != nil {
+		t.Fatalf("initial reload: %v", err)
+	}
+	first := m.Status().Checksum
+	if reg.Get("prices") == nil || first == "" {
+		t.Fatal("expected catalog loaded with a checksum")
+	}
+
+	diff, err := m.Reload()
+	if err != nil || !diff.IsEmpty() {
+		t.Fatalf("unchanged catalog should be a no-op, got %v / %v", diff, err)
+	}
+
+	writeFile(t, file, "prices:\n  display_name: Prices\nrates:\n  display_name: Rates\n")
+	diff, err = m.Reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(diff.Added) != 1 || reg.Get("rates") == nil {
+		t.Errorf("expected rates to be added, got %+v", diff)
+	}
+	if status := m.Status(); status.Checksum == first || status.Reloads != 2 || status.LastError != "" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestReloadManagerKeepsCatalogOnValidationFailure(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+
+	reg := NewRegistry()
+	m := NewReloadManager(reg, ConflictError, file)
+	if _, err := m.Reload(); err != nil {
+		t.Fatalf("initial reload: %v", err)
+	}
+
+	writeFile(t, file, "prices:\n  display_name: Prices\n  status: deprecated\n  successor: prices/missing\n")
+	if _, err := m.Reload(); err == nil {
+		t.Fatal("expected validation failure")
+	}
+	if reg.Get("prices").Status == NodeStatusDeprecated {
+		t.Error("invalid catalog must not replace the live one")
+	}
+	if status := m.Status(); status.Failures != 1 || status.LastError == "" {
+		t.Errorf("expected failure recorded in status, got %+v", status)
+	}
+
+	writeFile(t, file, "prices:\n  display_name: Prices\nrates:\n  dispay_name: Rates\n")
+	m.StrictKeys = true
+	if _, err := m.Reload(); err == nil || !strings.Contains(err.Error(), "dispay_name") {
+		t.Errorf("expected unknown key failure in strict mode, got %v", err)
+	}
+}
+
+func TestReloadManagerWatchesFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.yaml")
+	writeFile(t, file, "prices:\n  display_name: Prices\n")
+
+	reg := NewRegistry()
+	m := NewReloadManager(reg, ConflictError, file)
+	m.Debounce = 20 * time.Millisecond
+	reloaded := make(chan struct{}, 10)
+	m.OnReload = func(diff *CatalogDiff, err error) {
+		if err == nil && !diff.IsEmpty() {
+			reloaded <- struct{}{}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Watch(ctx)
+	time.Sleep(50 * time.Millisecond) // Let the watcher start
+
+	writeFile(t, file, "prices:\n  display_name: Prices\nrates:\n  display_name: Rates\n")
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("file change did not trigger a reload")
+	}
+	if reg.Get("rates") == nil {
+		t.Error("expected rates after watched reload")
+	}
+}
@@ -0,0 +1,185 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteVersion identifies a fetched catalog revision. It is sent back on the
+// next fetch so unchanged catalogs are not downloaded again.
+type RemoteVersion struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// FetchResult is the outcome of fetching a remote catalog
+type FetchResult struct {
+	Data        []byte
+	Version     RemoteVersion
+	NotModified bool // The catalog still matches the version passed to Fetch
+}
+
+// Fetcher retrieves catalog content for one URL scheme. Implementations
+// should honour prev for conditional requests where the store supports it.
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string, prev RemoteVersion) (*FetchResult, error)
+}
+
+// HTTPFetcher fetches catalogs over http:// and https:// using conditional
+// GETs (If-None-Match / If-Modified-Since)
+type HTTPFetcher struct {
+	Client      *http.Client // Defaults to a client with a 30s timeout
+	BearerToken string       // Sent as Authorization: Bearer when set
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Fetch implements Fetcher
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string, prev RemoteVersion) (*FetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if f.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+f.BearerToken)
+	}
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch catalog: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return &FetchResult{Version: prev, NotModified: true}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetch catalog: %s returned %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog response: %w", err)
+	}
+	return &FetchResult{
+		Data: data,
+		Version: RemoteVersion{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}, nil
+}
+
+// RemoteLoader loads catalogs from URLs. It remembers the version and nodes of
+// each URL's last successful load, so polling an unchanged catalog costs one
+// conditional request and no parsing.
+type RemoteLoader struct {
+	mu       sync.Mutex
+	fetchers map[string]Fetcher
+	last     map[string]remoteEntry
+}
+
+type remoteEntry struct {
+	version RemoteVersion
+	nodes   []*CatalogNode
+}
+
+// NewRemoteLoader creates a loader with an HTTPFetcher for http and https.
+// Other schemes, such as s3, need a fetcher registered with SetFetcher.
+func NewRemoteLoader() *RemoteLoader {
+	f := &HTTPFetcher{}
+	return &RemoteLoader{
+		fetchers: map[string]Fetcher{"http": f, "https": f},
+		last:     make(map[string]remoteEntry),
+	}
+}
+
+// SetFetcher registers the fetcher for a URL scheme, replacing any existing one
+func (l *RemoteLoader) SetFetcher(scheme string, f Fetcher) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fetchers[strings.ToLower(scheme)] = f
+}
+
+// Version returns the version recorded by the last successful load of rawURL
+func (l *RemoteLoader) Version(rawURL string) (RemoteVersion, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.last[rawURL]
+	return entry.version, ok
+}
+
+// Load fetches and parses the catalog at rawURL. The format follows the URL
+// path's extension: .json is JSON, anything else is YAML. If the catalog is
+// unchanged since the last load, the previously parsed nodes are returned.
+func (l *RemoteLoader) Load(ctx context.Context, rawURL string) ([]*CatalogNode, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse catalog URL: %w", err)
+	}
+
+	l.mu.Lock()
+	fetcher, ok := l.fetchers[strings.ToLower(u.Scheme)]
+	prev := l.last[rawURL]
+	l.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no catalog fetcher registered for %s:// URLs", u.Scheme)
+	}
+
+	result, err := fetcher.Fetch(ctx, rawURL, prev.version)
+	if err != nil {
+		return nil, err
+	}
+	if result.NotModified && prev.nodes != nil {
+		return prev.nodes, nil
+	}
+
+	var nodes []*CatalogNode
+	if strings.EqualFold(path.Ext(u.Path), ".json") {
+		nodes, err = ParseCatalogJSON(result.Data)
+	} else {
+		nodes, err = ParseCatalog(result.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		node.SourceFile = rawURL
+	}
+
+	l.mu.Lock()
+	l.last[rawURL] = remoteEntry{version: result.Version, nodes: nodes}
+	l.mu.Unlock()
+	return nodes, nil
+}
+
+// DefaultRemoteLoader is used by Load and LoadCatalogURL
+var DefaultRemoteLoader = NewRemoteLoader()
+
+// LoadCatalogURL loads a catalog from an http(s):// URL, or any other scheme
+// with a fetcher registered on DefaultRemoteLoader (e.g. s3://)
+func LoadCatalogURL(ctx context.Context, rawURL string) ([]*CatalogNode, error) {
+	return DefaultRemoteLoader.Load(ctx, rawURL)
+}
+
+// IsRemoteCatalog returns true if the catalog location is a URL rather than a path
+func IsRemoteCatalog(location string) bool {
+	return strings.Contains(location, "://")
+}
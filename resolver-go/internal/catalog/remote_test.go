@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const remoteCatalog = "prices:\n  display_name: Prices\nprices/equity:\n  display_name: Equity\n"
+
+func TestRemoteLoaderConditionalFetch(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(remoteCatalog))
+	}))
+	defer srv.Close()
+
+	loader := NewRemoteLoader()
+	loader.SetFetcher("http", &HTTPFetcher{BearerToken: "secret"})
+	url := srv.URL + "/catalog.yaml"
+
+	first, err := loader.Load(context.Background(), url)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("expected 2 nodes, got %d / %v", len(first), err)
+	}
+	if v, _ := loader.Version(url); v.ETag != `"v1"` {
+		t.Errorf("expected ETag to be captured, got %+v", v)
+	}
+	if first[0].SourceFile != url {
+		t.Errorf("expected nodes annotated with the URL, got %q", first[0].SourceFile)
+	}
+
+	second, err := loader.Load(context.Background(), url)
+	if err != nil || len(second) != 2 {
+		t.Fatalf("expected cached nodes on 304, got %d / %v", len(second), err)
+	}
+	if fetches.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("expected a conditional second fetch, got %d fetches / %d not modified", fetches.Load(), notModified.Load())
+	}
+}
+
+func TestRemoteLoaderErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	loader := NewRemoteLoader()
+	if _, err := loader.Load(context.Background(), srv.URL+"/catalog.yaml"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+	if _, err := loader.Load(context.Background(), "s3://bucket/catalog.yaml"); err == nil {
+		t.Error("expected error for s3:// without a registered fetcher")
+	}
+}
+
+type fakeS3Fetcher struct {
+	objects map[string]string
+}
+
+func (f *fakeS3Fetcher) Fetch(ctx context.Context, rawURL string, prev RemoteVersion) (*FetchResult, error) {
+	return &FetchResult{Data: []byte(f.objects[rawURL]), Version: RemoteVersion{ETag: "etag-1"}}, nil
+}
+
+func TestRemoteLoaderPluggableScheme(t *testing.T) {
+	loader := NewRemoteLoader()
+	loader.SetFetcher("s3", &fakeS3Fetcher{objects: map[string]string{
+		"s3://catalogs/prod/catalog.json": `{"prices": {"display_name": "Prices"}}`,
+	}})
+
+	nodes, err := loader.Load(context.Background(), "s3://catalogs/prod/catalog.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].DisplayName != "Prices" {
+		t.Errorf("expected JSON catalog from s3 fetcher, got %v", nodes)
+	}
+}
@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// SchemaColumn is a column of a node's schema with its keys worked out: it is
+// a primary key when it says so or the schema lists it, and ForeignKeyPath is
+// the canonical path of its foreign key, empty when that does not parse.
+// ForeignKeyRegistered is set when the path or an ancestor of it is in the
+// catalog.
+type SchemaColumn struct {
+	ColumnSchema
+	ForeignKeyPath       string `json:"foreign_key_path,omitempty"`
+	ForeignKeyRegistered bool   `json:"foreign_key_registered,omitempty"`
+}
+
+// RetypedColumn is a column whose data type differs between two schemas
+type RetypedColumn struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SchemaDiff is what changes from one schema to another, by column name:
+// columns only the second has, only the first has, and in both with another
+// data type. Each list is in column order of the schema it comes from.
+type SchemaDiff struct {
+	Added   []ColumnSchema  `json:"added"`
+	Removed []ColumnSchema  `json:"removed"`
+	Retyped []RetypedColumn `json:"retyped"`
+}
+
+// Empty reports whether the schemas have the same columns and types
+func (d *SchemaDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retyped) == 0
+}
+
+// DiffSchemas compares the columns of from and to
+func DiffSchemas(from, to *DataSchema) *SchemaDiff {
+	diff := &SchemaDiff{
+		Added:   make([]ColumnSchema, 0),
+		Removed: make([]ColumnSchema, 0),
+		Retyped: make([]RetypedColumn, 0),
+	}
+	before := make(map[string]ColumnSchema, len(from.Columns))
+	for _, col := range from.Columns {
+		before[col.Name] = col
+	}
+	after := make(map[string]bool, len(to.Columns))
+	for _, col := range to.Columns {
+		after[col.Name] = true
+		old, ok := before[col.Name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, col)
+		case old.DataType != col.DataType:
+			diff.Retyped = append(diff.Retyped, RetypedColumn{Name: col.Name, From: old.DataType, To: col.DataType})
+		}
+	}
+	for _, col := range from.Columns {
+		if !after[col.Name] {
+			diff.Removed = append(diff.Removed, col)
+		}
+	}
+	return diff
+}
+
+// SchemaColumns returns the columns of the schema of the node at path, or
+// nil when it has none
+func (r *Registry) SchemaColumns(path string) []SchemaColumn {
+	snap := r.load()
+	node, ok := snap.nodes[path]
+	if !ok || node.DataSchema == nil {
+		return nil
+	}
+
+	primary := make(map[string]bool, len(node.DataSchema.PrimaryKey))
+	for _, name := range node.DataSchema.PrimaryKey {
+		primary[name] = true
+	}
+	columns := make([]SchemaColumn, 0, len(node.DataSchema.Columns))
+	for _, col := range node.DataSchema.Columns {
+		column := SchemaColumn{ColumnSchema: col}
+		column.PrimaryKey = col.PrimaryKey || primary[col.Name]
+		if col.ForeignKey != nil && *col.ForeignKey != "" {
+			if m, err := moniker.Parse(*col.ForeignKey, true); err == nil {
+				column.ForeignKeyPath = m.CanonicalPath()
+				column.ForeignKeyRegistered = snap.registeredOrAncestor(column.ForeignKeyPath) != nil
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// NearestSchemaAncestor returns the nearest ancestor of path that has a
+// schema, or "" when none does
+func (r *Registry) NearestSchemaAncestor(path string) string {
+	nodes := r.load().nodes
+	ancestors := ancestorPaths(path)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if node, ok := nodes[ancestors[i]]; ok && node.DataSchema != nil {
+			return ancestors[i]
+		}
+	}
+	return ""
+}
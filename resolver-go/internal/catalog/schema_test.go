@@ -0,0 +1,65 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	from := &DataSchema{Columns: []ColumnSchema{
+		{Name: "id", DataType: "string"},
+		{Name: "price", DataType: "float"},
+		{Name: "legacy_code", DataType: "string"},
+	}}
+	to := &DataSchema{Columns: []ColumnSchema{
+		{Name: "id", DataType: "string"},
+		{Name: "price", DataType: "integer"},
+		{Name: "currency", DataType: "string"},
+	}}
+
+	diff := DiffSchemas(from, to)
+	if len(diff.Added) != 1 || diff.Added[0].Name != "currency" {
+		t.Errorf("unexpected added %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "legacy_code" {
+		t.Errorf("unexpected removed %v", diff.Removed)
+	}
+	if len(diff.Retyped) != 1 || diff.Retyped[0] != (RetypedColumn{Name: "price", From: "float", To: "integer"}) {
+		t.Errorf("unexpected retyped %v", diff.Retyped)
+	}
+	if !DiffSchemas(from, from).Empty() {
+		t.Error("expected a schema to match itself")
+	}
+}
+
+func TestSchemaColumnsResolveKeys(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("reference/security", "Security", "", NodeStatusActive, true))
+	holdings := makeSchemaNode("holdings", NodeStatusActive, map[string]string{"security_id": "reference/security/ALL"})
+	holdings.DataSchema.Columns = append(holdings.DataSchema.Columns,
+		ColumnSchema{Name: "account", DataType: "string", ForeignKey: strPtr("accounts")},
+		ColumnSchema{Name: "as_of", DataType: "date"})
+	holdings.DataSchema.PrimaryKey = []string{"as_of"}
+	r.Register(holdings)
+	r.Register(makeNode("holdings/daily", "Daily", "", NodeStatusActive, true))
+
+	columns := r.SchemaColumns("holdings")
+	if len(columns) != 3 {
+		t.Fatalf("expected 3 columns, got %v", columns)
+	}
+	if c := columns[0]; c.ForeignKeyPath != "reference/security/ALL" || !c.ForeignKeyRegistered {
+		t.Errorf("expected security_id to point at a registered ancestor, got %+v", c)
+	}
+	if c := columns[1]; c.ForeignKeyPath != "accounts" || c.ForeignKeyRegistered {
+		t.Errorf("expected account to point at an unregistered path, got %+v", c)
+	}
+	if !columns[2].PrimaryKey {
+		t.Error("expected as_of to be a primary key from the schema's list")
+	}
+
+	if r.SchemaColumns("holdings/daily") != nil {
+		t.Error("expected no columns for a node without a schema")
+	}
+	if got := r.NearestSchemaAncestor("holdings/daily"); got != "holdings" {
+		t.Errorf("nearest schema ancestor = %q, want holdings", got)
+	}
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import "testing"
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func TestResolveSLA_MergesFieldByField(t *testing.T) {
+	reg := NewRegistry()
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.SLA = &SLA{Freshness: strPtr("T+1"), SupportHours: strPtr("24x7")}
+	leaf := makeNode("prices/equity", "Equity", "", NodeStatusActive, true)
+	leaf.SLA = &SLA{Freshness: strPtr("T+0")}
+	reg.RegisterMany([]*CatalogNode{root, leaf})
+
+	sla := reg.ResolveSLA("prices/equity")
+	if sla == nil {
+		t.Fatal("expected resolved SLA")
+	}
+	if *sla.Freshness != "T+0" || *sla.FreshnessSource != "prices/equity" {
+		t.Errorf("freshness = %v from %v", *sla.Freshness, *sla.FreshnessSource)
+	}
+	if *sla.SupportHours != "24x7" || *sla.SupportHoursSource != "prices" {
+		t.Errorf("support_hours = %v from %v", *sla.SupportHours, *sla.SupportHoursSource)
+	}
+	if sla.Availability != nil || sla.AvailabilitySource != nil {
+		t.Error("expected availability to stay unset")
+	}
+}
+
+func TestResolveSLA_NilWhenUndefined(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterMany([]*CatalogNode{
+		makeNode("prices", "Prices", "", NodeStatusActive, false),
+		makeNode("prices/equity", "Equity", "", NodeStatusActive, true),
+	})
+
+	if sla := reg.ResolveSLA("prices/equity"); sla != nil {
+		t.Errorf("expected nil SLA, got %+v", sla)
+	}
+	if dq := reg.ResolveDataQuality("prices/equity"); dq != nil {
+		t.Errorf("expected nil data quality, got %+v", dq)
+	}
+}
+
+func TestResolveDataQuality_MergesFieldByField(t *testing.T) {
+	reg := NewRegistry()
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.DataQuality = &DataQuality{
+		DQOwner:         strPtr("dq-team"),
+		ValidationRules: []string{"not_null(price)"},
+	}
+	leaf := makeNode("prices/equity", "Equity", "", NodeStatusActive, true)
+	leaf.DataQuality = &DataQuality{QualityScore: float64Ptr(0.97)}
+	reg.RegisterMany([]*CatalogNode{root, leaf})
+
+	dq := reg.ResolveDataQuality("prices/equity")
+	if dq == nil {
+		t.Fatal("expected resolved data quality")
+	}
+	if *dq.DQOwner != "dq-team" || *dq.DQOwnerSource != "prices" {
+		t.Errorf("dq_owner = %v from %v", *dq.DQOwner, *dq.DQOwnerSource)
+	}
+	if *dq.QualityScore != 0.97 || *dq.QualityScoreSource != "prices/equity" {
+		t.Errorf("quality_score = %v from %v", *dq.QualityScore, *dq.QualityScoreSource)
+	}
+	if len(dq.ValidationRules) != 1 || *dq.ValidationRulesSource != "prices" {
+		t.Errorf("validation_rules = %v", dq.ValidationRules)
+	}
+}
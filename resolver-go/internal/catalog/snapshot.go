@@ -0,0 +1,213 @@
+package catalog
+
+import (
+	"sort"
+	"sync"
+)
+
+// snapshot is an immutable view of the registry contents. Readers load the
+// current snapshot without locking; writers build a new snapshot and swap it
+// in. A snapshot must never be modified once published.
+type snapshot struct {
+	nodes    map[string]*CatalogNode
+	children map[string]map[string]bool // parent -> children paths
+	version  string                     // Digest of the loaded catalog; see Registry.Version
+
+	// downstream indexes lineage references: path -> paths of the nodes that
+	// reference it. Kept up to date by put and remove.
+	downstream map[string]map[string]bool
+
+	// sorted is the index of node paths in order, built on first use by
+	// sortedPaths
+	sortOnce sync.Once
+	sorted   []string
+
+	// tree is the index Tree walks, built on first use by treeIndex
+	treeOnce sync.Once
+	tree     *treeIndex
+}
+
+// treeIndex links every path, the root "" and virtual paths included, to its
+// children, with counts of the registered nodes beneath it
+type treeIndex struct {
+	children    map[string][]string // Sorted
+	descendants map[string]int
+	live        map[string]int // Descendants with no archived node above them, below the path
+}
+
+func emptySnapshot() *snapshot {
+	return &snapshot{
+		nodes:      make(map[string]*CatalogNode),
+		children:   make(map[string]map[string]bool),
+		downstream: make(map[string]map[string]bool),
+	}
+}
+
+// buildSnapshot indexes a full set of nodes
+func buildSnapshot(nodes []*CatalogNode) *snapshot {
+	s := &snapshot{
+		nodes:      make(map[string]*CatalogNode, len(nodes)),
+		children:   make(map[string]map[string]bool),
+		downstream: make(map[string]map[string]bool),
+	}
+	for _, node := range nodes {
+		s.nodes[node.Path] = node
+		if parent := parentPath(node.Path); parent != nil {
+			if s.children[*parent] == nil {
+				s.children[*parent] = make(map[string]bool)
+			}
+			s.children[*parent][node.Path] = true
+		}
+		for target := range lineageTargets(node) {
+			if s.downstream[target] == nil {
+				s.downstream[target] = make(map[string]bool)
+			}
+			s.downstream[target][node.Path] = true
+		}
+	}
+	return s
+}
+
+// sortedPaths returns the node paths in order. The slice is shared; callers
+// must not modify it.
+func (s *snapshot) sortedPaths() []string {
+	s.sortOnce.Do(func() {
+		s.sorted = make([]string, 0, len(s.nodes))
+		for p := range s.nodes {
+			s.sorted = append(s.sorted, p)
+		}
+		sort.Strings(s.sorted)
+	})
+	return s.sorted
+}
+
+// treeIndex returns the tree index of the snapshot, building it on first
+// use. It is shared; callers must not modify it.
+func (s *snapshot) treeIndex() *treeIndex {
+	s.treeOnce.Do(func() {
+		t := &treeIndex{
+			children:    make(map[string][]string),
+			descendants: make(map[string]int),
+			live:        make(map[string]int),
+		}
+		archived := func(p string) bool {
+			node := s.nodes[p]
+			return node != nil && node.Status == NodeStatusArchived
+		}
+		linked := make(map[string]bool)
+		for _, p := range s.sortedPaths() {
+			lineage := append(append([]string{""}, ancestorPaths(p)...), p)
+			for i := 0; i < len(lineage)-1; i++ {
+				if child := lineage[i+1]; !linked[child] {
+					linked[child] = true
+					t.children[lineage[i]] = append(t.children[lineage[i]], child)
+				}
+			}
+			hidden := archived(p)
+			for i := len(lineage) - 2; i >= 0; i-- {
+				t.descendants[lineage[i]]++
+				if !hidden {
+					t.live[lineage[i]]++
+				}
+				hidden = hidden || archived(lineage[i])
+			}
+		}
+		for _, list := range t.children {
+			sort.Strings(list)
+		}
+		s.tree = t
+	})
+	return s.tree
+}
+
+// clone returns a copy that can be modified with put and remove. Child and
+// downstream sets are shared with the original and copied on first write.
+// The version is not copied: a modified catalog no longer matches the digest
+// it was loaded with.
+func (s *snapshot) clone() *snapshot {
+	c := &snapshot{
+		nodes:      make(map[string]*CatalogNode, len(s.nodes)+1),
+		children:   make(map[string]map[string]bool, len(s.children)+1),
+		downstream: make(map[string]map[string]bool, len(s.downstream)),
+	}
+	for p, node := range s.nodes {
+		c.nodes[p] = node
+	}
+	for p, set := range s.children {
+		c.children[p] = set
+	}
+	for p, set := range s.downstream {
+		c.downstream[p] = set
+	}
+	return c
+}
+
+// put adds or replaces a node. Only valid on an unpublished clone.
+func (s *snapshot) put(node *CatalogNode) {
+	s.relink(node.Path, s.nodes[node.Path], node)
+	s.nodes[node.Path] = node
+	parent := parentPath(node.Path)
+	if parent == nil || s.children[*parent][node.Path] {
+		return
+	}
+	set := make(map[string]bool, len(s.children[*parent])+1)
+	for p := range s.children[*parent] {
+		set[p] = true
+	}
+	set[node.Path] = true
+	s.children[*parent] = set
+}
+
+// remove deletes a node. Only valid on an unpublished clone.
+func (s *snapshot) remove(path string) {
+	s.relink(path, s.nodes[path], nil)
+	delete(s.nodes, path)
+	parent := parentPath(path)
+	if parent == nil || !s.children[*parent][path] {
+		return
+	}
+	if len(s.children[*parent]) == 1 {
+		delete(s.children, *parent)
+		return
+	}
+	set := make(map[string]bool, len(s.children[*parent])-1)
+	for p := range s.children[*parent] {
+		if p != path {
+			set[p] = true
+		}
+	}
+	s.children[*parent] = set
+}
+
+// relink updates the downstream index for the node at path changing from old
+// to node, either of which may be nil. Only valid on an unpublished clone.
+func (s *snapshot) relink(path string, old, node *CatalogNode) {
+	before, after := lineageTargets(old), lineageTargets(node)
+	for target := range before {
+		if after[target] {
+			continue
+		}
+		set := make(map[string]bool, len(s.downstream[target]))
+		for p := range s.downstream[target] {
+			if p != path {
+				set[p] = true
+			}
+		}
+		if len(set) == 0 {
+			delete(s.downstream, target)
+		} else {
+			s.downstream[target] = set
+		}
+	}
+	for target := range after {
+		if before[target] {
+			continue
+		}
+		set := make(map[string]bool, len(s.downstream[target])+1)
+		for p := range s.downstream[target] {
+			set[p] = true
+		}
+		set[path] = true
+		s.downstream[target] = set
+	}
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func snapshotFixture(n int, status NodeStatus) []*CatalogNode {
+	nodes := make([]*CatalogNode, 0, n+1)
+	root := makeNode("prices", "Prices", "", NodeStatusActive, false)
+	root.Ownership = &Ownership{AccountableOwner: strPtr("owner")}
+	root.SourceBinding = &SourceBinding{SourceType: SourceTypeSnowflake, Config: map[string]interface{}{}}
+	nodes = append(nodes, root)
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, makeNode(fmt.Sprintf("prices/n%d", i), "Node", "", status, true))
+	}
+	return nodes
+}
+
+func TestConcurrentReadsDuringAtomicReplace(t *testing.T) {
+	reg := NewRegistry()
+	reg.AtomicReplace(snapshotFixture(20, NodeStatusActive))
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("prices/n%d", i)
+			for !stop.Load() {
+				if binding, at := reg.FindSourceBinding(path); binding == nil || at != "prices" {
+					t.Errorf("binding lost during replace: %v at %q", binding, at)
+					return
+				}
+				if o := reg.ResolveOwnership(path); o.AccountableOwner == nil {
+					t.Error("ownership lost during replace")
+					return
+				}
+				if len(reg.ChildrenPaths("prices")) != 20 {
+					t.Error("observed a partially built snapshot")
+					return
+				}
+				reg.Get(path)
+				reg.Count()
+			}
+		}(i)
+	}
+
+	for i := 0; i < 40; i++ {
+		status := NodeStatusActive
+		if i%2 == 1 {
+			status = NodeStatusDeprecated
+		}
+		reg.AtomicReplace(snapshotFixture(20, status))
+	}
+	stop.Store(true)
+	wg.Wait()
+}
+
+func TestRegisterDoesNotMutatePublishedSnapshot(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	reg.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+
+	before := reg.load()
+	reg.Register(makeNode("prices/fx", "FX", "", NodeStatusActive, true))
+	reg.Deregister("prices/equity")
+
+	if len(before.children["prices"]) != 1 || !before.children["prices"]["prices/equity"] {
+		t.Errorf("old snapshot children changed: %v", before.children["prices"])
+	}
+	if _, ok := before.nodes["prices/fx"]; ok {
+		t.Error("old snapshot saw a later registration")
+	}
+
+	paths := reg.ChildrenPaths("prices")
+	if len(paths) != 1 || paths[0] != "prices/fx" {
+		t.Errorf("expected [prices/fx], got %v", paths)
+	}
+}
+
+// rwMutexRegistry is the previous locking scheme, kept as a benchmark baseline
+type rwMutexRegistry struct {
+	mu    sync.RWMutex
+	nodes map[string]*CatalogNode
+}
+
+func (r *rwMutexRegistry) get(path string) *CatalogNode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.nodes[path]
+}
+
+func (r *rwMutexRegistry) replace(nodes []*CatalogNode) {
+	m := make(map[string]*CatalogNode, len(nodes))
+	for _, n := range nodes {
+		m[n.Path] = n
+	}
+	r.mu.Lock()
+	r.nodes = m
+	r.mu.Unlock()
+}
+
+// replaceInBackground runs replace in a loop until the returned stop function is called
+func replaceInBackground(replace func([]*CatalogNode)) func() {
+	nodes := snapshotFixture(1000, NodeStatusActive)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				replace(nodes)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func BenchmarkGetDuringReplace_Snapshot(b *testing.B) {
+	reg := NewRegistry()
+	reg.AtomicReplace(snapshotFixture(1000, NodeStatusActive))
+	stop := replaceInBackground(reg.AtomicReplace)
+	defer stop()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			reg.Get("prices/n500")
+		}
+	})
+}
+
+func BenchmarkGetDuringReplace_RWMutex(b *testing.B) {
+	reg := &rwMutexRegistry{}
+	reg.replace(snapshotFixture(1000, NodeStatusActive))
+	stop := replaceInBackground(reg.replace)
+	defer stop()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			reg.get("prices/n500")
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// configKind is the expected type of a source binding config value
+type configKind string
+
+const (
+	configString configKind = "string"
+	configNumber configKind = "number"
+	configList   configKind = "list"
+	configMap    configKind = "map"
+	configBool   configKind = "boolean"
+	configAny    configKind = "any"
+)
+
+// sourceConfigSpec lists the config keys a source type requires or understands.
+// Keys outside the spec are allowed and not type-checked.
+type sourceConfigSpec struct {
+	Required map[string]configKind
+	Optional map[string]configKind
+}
+
+// sourceConfigSpecs maps every supported source type to its config keys
+var sourceConfigSpecs = map[SourceType]sourceConfigSpec{
+	SourceTypeSnowflake: {
+		Required: map[string]configKind{"account": configString, "database": configString},
+		Optional: map[string]configKind{
+			"warehouse": configString, "schema": configString, "table": configString,
+			"role": configString, "query": configString, "segment_names": configAny,
+			"user": configString, "password": configString, "private_key": configString,
+			VersionsQueryKey: configString,
+		},
+	},
+	SourceTypeOracle: {
+		Required: map[string]configKind{"dsn": configString},
+		Optional: map[string]configKind{
+			"query": configString, "table": configString, "user": configString, "password": configString,
+			VersionsQueryKey: configString,
+		},
+	},
+	SourceTypeMSSQL: {
+		Required: map[string]configKind{"server": configString, "database": configString},
+		Optional: map[string]configKind{
+			"port": configNumber, "driver": configString, "query": configString,
+			"user": configString, "password": configString, VersionsQueryKey: configString,
+		},
+	},
+	SourceTypeREST: {
+		Required: map[string]configKind{"base_url": configString},
+		Optional: map[string]configKind{
+			"path_template": configString, "method": configString, "auth_type": configString,
+			"headers": configMap, "timeout_seconds": configNumber,
+		},
+	},
+	SourceTypeStatic: {
+		// One of rows, data_file or base_path is required; see checkStatic
+		Optional: map[string]configKind{
+			"base_path": configString, "file_pattern": configString, "format": configString,
+			StaticRowsKey: configList, StaticDataFileKey: configString, KeyColumnsKey: configList,
+			VersionColumnKey: configString,
+		},
+	},
+	SourceTypeExcel: {
+		Required: map[string]configKind{"base_path": configString},
+		Optional: map[string]configKind{
+			"file_pattern": configString, "sheet": configString, "header_row": configNumber,
+			KeyColumnsKey: configList, VersionColumnKey: configString,
+		},
+	},
+	SourceTypeOpenSearch: {
+		Required: map[string]configKind{"hosts": configList, "index": configString},
+		Optional: map[string]configKind{
+			"query": configString, "size": configNumber, "max_hits": configNumber,
+			"username": configString, "password": configString, "api_key": configString,
+			"tls_ca_file": configString, "tls_insecure_skip_verify": configBool, "timeout_seconds": configNumber,
+		},
+	},
+	SourceTypeBloomberg: {},
+	SourceTypeRefinitiv: {},
+	SourceTypeFRED: {
+		Optional: map[string]configKind{"base_url": configString, "series_ids": configAny},
+	},
+	SourceTypeYFinance: {
+		Optional: map[string]configKind{"tickers": configAny, "period": configString},
+	},
+	SourceTypeComposite: {},
+	SourceTypeDerived: {
+		Required: map[string]configKind{DerivedInputsKey: configMap},
+		Optional: map[string]configKind{DerivedExpressionKey: configString, DerivedTransformKey: configString},
+	},
+}
+
+// SourceConfigViolation describes a source binding whose config does not
+// match its source type
+type SourceConfigViolation struct {
+	Path       string     `json:"path"`
+	SourceType SourceType `json:"source_type"`
+	Missing    []string   `json:"missing,omitempty"`
+	Message    string     `json:"message"`
+}
+
+// SourceConfigError aggregates every source binding config violation in a catalog
+type SourceConfigError struct {
+	Violations []SourceConfigViolation
+}
+
+func (e *SourceConfigError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = "  " + v.Message
+	}
+	return fmt.Sprintf("%d invalid source bindings:\n%s", len(e.Violations), strings.Join(lines, "\n"))
+}
+
+// validateSourceConfigs checks every node's source binding against the spec
+// for its type and returns a *SourceConfigError listing all violations
+func validateSourceConfigs(nodes []*CatalogNode) error {
+	violations := make([]SourceConfigViolation, 0)
+	for _, node := range nodes {
+		if node.SourceBinding == nil {
+			continue
+		}
+		violations = append(violations, checkSourceConfig(node.Path, node.SourceBinding)...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return &SourceConfigError{Violations: violations}
+}
+
+// SourceTypes returns the supported source types, sorted
+func SourceTypes() []SourceType {
+	types := make([]SourceType, 0, len(sourceConfigSpecs))
+	for t := range sourceConfigSpecs {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func checkSourceConfig(path string, sb *SourceBinding) []SourceConfigViolation {
+	spec, ok := sourceConfigSpecs[sb.SourceType]
+	if !ok {
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s: unknown source type %q", path, sb.SourceType),
+		}}
+	}
+
+	var violations []SourceConfigViolation
+	missing := make([]string, 0)
+	for key := range spec.Required {
+		if _, ok := sb.Config[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		violations = append(violations, SourceConfigViolation{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Missing:    missing,
+			Message:    fmt.Sprintf("%s (%s): missing required config keys: %s", path, sb.SourceType, strings.Join(missing, ", ")),
+		})
+	}
+
+	keys := make([]string, 0, len(sb.Config))
+	for key := range sb.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		kind, ok := spec.Required[key]
+		if !ok {
+			kind, ok = spec.Optional[key]
+		}
+		if !ok || matchesConfigKind(sb.Config[key], kind) {
+			continue
+		}
+		violations = append(violations, SourceConfigViolation{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): config key '%s' must be a %s, got %T", path, sb.SourceType, key, kind, sb.Config[key]),
+		})
+	}
+	violations = append(violations, checkSubResources(path, sb)...)
+	violations = append(violations, checkAllowedParams(path, sb)...)
+	violations = append(violations, checkFrequencies(path, sb)...)
+	violations = append(violations, checkStatic(path, sb)...)
+	violations = append(violations, checkKeyColumns(path, sb)...)
+	return append(violations, checkDerived(path, sb)...)
+}
+
+// KeyColumnsKey names the columns of a static or excel binding that the
+// moniker segments below the binding filter, in order
+const KeyColumnsKey = "key_columns"
+
+// Config keys listing the versions of a binding's data, for date@latest and
+// /versions: a query returning one version per row for SQL bindings, and the
+// column holding each row's version for static and excel bindings
+const (
+	VersionsQueryKey = "versions_query"
+	VersionColumnKey = "version_column"
+)
+
+// KeyColumns returns the key_columns of a binding config
+func KeyColumns(config map[string]interface{}) ([]string, error) {
+	v, ok := config[KeyColumnsKey]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config key '%s' must be a list of column names, got %T", KeyColumnsKey, v)
+	}
+	columns := make([]string, len(list))
+	for i, item := range list {
+		name, ok := item.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s[%d] must be a column name", KeyColumnsKey, i)
+		}
+		columns[i] = name
+	}
+	return columns, nil
+}
+
+// checkKeyColumns checks the names in key_columns; a value that is not a list
+// is reported by checkSourceConfig
+func checkKeyColumns(path string, sb *SourceBinding) []SourceConfigViolation {
+	if _, ok := sb.Config[KeyColumnsKey].([]interface{}); !ok {
+		return nil
+	}
+	if _, err := KeyColumns(sb.Config); err != nil {
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): %v", path, sb.SourceType, err),
+		}}
+	}
+	return nil
+}
+
+// matchesConfigKind reports whether a decoded YAML or JSON value has the expected kind
+func matchesConfigKind(value interface{}, kind configKind) bool {
+	switch kind {
+	case configString:
+		_, ok := value.(string)
+		return ok
+	case configNumber:
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+		return false
+	case configList:
+		_, ok := value.([]interface{})
+		return ok
+	case configMap:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case configBool:
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSourceConfigValid(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config: {account: acme, database: MARKET_DATA, query: "SELECT 1"}
+prices/api:
+  source_binding:
+    type: rest
+    config: {base_url: "https://api.example.com", headers: {Accept: json}}
+prices/search:
+  source_binding:
+    type: opensearch
+    config: {hosts: ["localhost:9200"], index: prices}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSourceConfigAggregatesViolations(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config: {warehouse: WH}
+prices/api:
+  source_binding:
+    type: rest
+    config: {path_template: /prices}
+prices/sheet:
+  source_binding:
+    type: excel
+    config: {base_path: /data, header_row: first}
+prices/unknown:
+  source_binding:
+    type: mongodb
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *SourceConfigError, got %v", err)
+	}
+	if len(cfgErr.Violations) != 4 {
+		t.Fatalf("expected 4 violations, got %d: %v", len(cfgErr.Violations), err)
+	}
+
+	byPath := make(map[string]SourceConfigViolation)
+	for _, v := range cfgErr.Violations {
+		byPath[v.Path] = v
+	}
+	if v := byPath["prices/equity"]; strings.Join(v.Missing, ",") != "account,database" || v.SourceType != SourceTypeSnowflake {
+		t.Errorf("unexpected snowflake violation: %+v", v)
+	}
+	if v := byPath["prices/api"]; strings.Join(v.Missing, ",") != "base_url" {
+		t.Errorf("unexpected rest violation: %+v", v)
+	}
+	if v := byPath["prices/sheet"]; !strings.Contains(v.Message, "header_row") {
+		t.Errorf("expected header_row type error, got %+v", v)
+	}
+	if v := byPath["prices/unknown"]; !strings.Contains(v.Message, `unknown source type "mongodb"`) {
+		t.Errorf("expected unknown source type error, got %+v", v)
+	}
+}
+
+func TestSourceConfigJSONNumbers(t *testing.T) {
+	_, err := ParseCatalogJSON([]byte(`{
+  "db/trades": {"source_binding": {"type": "mssql", "config": {"server": "sql01", "database": "TRADES", "port": 1433}}}
+}`))
+	if err != nil {
+		t.Fatalf("JSON numbers should satisfy number keys: %v", err)
+	}
+}
+
+func TestSourceConfigFrequencies(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config: {account: acme, database: MARKET_DATA, frequencies: [daily, monthly]}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := SupportedFrequencies(nodes[0].SourceBinding.Config); strings.Join(got, ",") != "daily,monthly" {
+		t.Errorf("unexpected frequencies: %v", got)
+	}
+
+	_, err = ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config: {account: acme, database: MARKET_DATA, frequencies: [daily, hourly]}
+prices/fx:
+  source_binding:
+    type: oracle
+    config: {dsn: "oracle://localhost/fx", frequencies: daily}
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) || len(cfgErr.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "unknown frequency hourly") || !strings.Contains(err.Error(), "must be a non-empty list") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Static binding config keys. Rows are given inline with rows, or in a CSV or
+// JSON file named by data_file (relative to the catalog file) that is read
+// when the catalog loads.
+const (
+	StaticRowsKey     = "rows"
+	StaticDataFileKey = "data_file"
+)
+
+// checkStatic checks the rows, data_file and key_columns of a static binding
+func checkStatic(path string, sb *SourceBinding) []SourceConfigViolation {
+	if sb.SourceType != SourceTypeStatic {
+		return nil
+	}
+	violation := func(format string, args ...interface{}) []SourceConfigViolation {
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): ", path, sb.SourceType) + fmt.Sprintf(format, args...),
+		}}
+	}
+
+	_, hasRows := sb.Config[StaticRowsKey]
+	_, hasFile := sb.Config[StaticDataFileKey]
+	_, hasBase := sb.Config["base_path"]
+	switch {
+	case hasRows && hasFile:
+		return violation("config sets both %s and %s", StaticRowsKey, StaticDataFileKey)
+	case !hasRows && !hasFile && !hasBase:
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Missing:    []string{StaticRowsKey, StaticDataFileKey, "base_path"},
+			Message:    fmt.Sprintf("%s (%s): config needs one of %s, %s or base_path", path, sb.SourceType, StaticRowsKey, StaticDataFileKey),
+		}}
+	}
+	// Values of the wrong kind are reported by checkSourceConfig
+	if rows, ok := sb.Config[StaticRowsKey].([]interface{}); ok {
+		if _, err := staticRowsConfig(rows); err != nil {
+			return violation("%v", err)
+		}
+	}
+	return nil
+}
+
+// staticRowsConfig converts an inline rows value to a list of maps
+func staticRowsConfig(v interface{}) ([]map[string]interface{}, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config key '%s' must be a list of rows, got %T", StaticRowsKey, v)
+	}
+	rows := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a map of column to value, got %T", StaticRowsKey, i, item)
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// StaticRows returns the rows of a static binding: those read from its
+// data_file at load, or its inline rows
+func (sb *SourceBinding) StaticRows() ([]map[string]interface{}, error) {
+	if sb.staticRows != nil {
+		return sb.staticRows, nil
+	}
+	if v, ok := sb.Config[StaticRowsKey]; ok {
+		return staticRowsConfig(v)
+	}
+	if file, ok := sb.Config[StaticDataFileKey]; ok {
+		return nil, fmt.Errorf("%s %v was not loaded", StaticDataFileKey, file)
+	}
+	return nil, fmt.Errorf("binding has no %s or %s", StaticRowsKey, StaticDataFileKey)
+}
+
+// loadStaticData reads the data_file of every static binding, resolved
+// against the directory of the node's catalog file
+func loadStaticData(nodes []*CatalogNode, read readFileFunc) error {
+	for _, node := range nodes {
+		sb := node.SourceBinding
+		if sb == nil || sb.SourceType != SourceTypeStatic {
+			continue
+		}
+		file, ok := sb.Config[StaticDataFileKey].(string)
+		if !ok || file == "" {
+			continue
+		}
+		if !filepath.IsAbs(file) && node.SourceFile != "" {
+			file = filepath.Join(filepath.Dir(node.SourceFile), file)
+		}
+		rows, err := readStaticDataFile(file, read)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", node.Path, StaticDataFileKey, err)
+		}
+		sb.staticRows = rows
+	}
+	return nil
+}
+
+// checkEditedDataFile checks the data_file of a binding set through the API,
+// which must name a file inside the directory of the node's catalog file so
+// an edit can't read other files on the resolver's host
+func checkEditedDataFile(sb *SourceBinding, sourceFile string) error {
+	if sb == nil || sb.SourceType != SourceTypeStatic {
+		return nil
+	}
+	file, ok := sb.Config[StaticDataFileKey].(string)
+	if !ok || file == "" {
+		return nil
+	}
+	if sourceFile == "" {
+		return fmt.Errorf("%s can't be set on a node without a catalog file", StaticDataFileKey)
+	}
+	if !filepath.IsLocal(file) {
+		return fmt.Errorf("%s %q must be a relative path inside the catalog file's directory", StaticDataFileKey, file)
+	}
+	return nil
+}
+
+// ReadStaticDataFile reads rows from a .csv file with a header row, or a
+// .json file holding a list of objects. CSV values are strings; they are
+// typed by the node's schema when fetched.
+func ReadStaticDataFile(path string) ([]map[string]interface{}, error) {
+	return readStaticDataFile(path, os.ReadFile)
+}
+
+func readStaticDataFile(path string, read readFileFunc) ([]map[string]interface{}, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, fmt.Errorf("%s: expected a JSON list of objects: %w", path, err)
+		}
+		return rows, nil
+	case ".csv":
+		r := csv.NewReader(bytes.NewReader(data))
+		header, err := r.Read()
+		if err == io.EOF {
+			return []map[string]interface{}{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rows := make([]map[string]interface{}, 0)
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			row := make(map[string]interface{}, len(header))
+			for i, name := range header {
+				row[strings.TrimSpace(name)] = record[i]
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("%s: data files must be .csv or .json", path)
+}
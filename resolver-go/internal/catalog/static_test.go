@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadStaticDataFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "data", "currencies.csv"), "code,name,decimals\nUSD,US Dollar,2\nJPY,Japanese Yen,0\n")
+	writeFile(t, filepath.Join(dir, "data", "countries.json"), `[{"code": "US", "region": "AMER"}]`)
+	writeFile(t, filepath.Join(dir, "reference.yaml"), `
+reference/currencies:
+  source_binding:
+    type: static
+    config: {data_file: data/currencies.csv, key_columns: [code]}
+reference/countries:
+  source_binding:
+    type: static
+    config: {data_file: data/countries.json}
+`)
+
+	nodes, err := LoadCatalog(filepath.Join(dir, "reference.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byPath := make(map[string]*CatalogNode)
+	for _, n := range nodes {
+		byPath[n.Path] = n
+	}
+	rows, err := byPath["reference/currencies"].SourceBinding.StaticRows()
+	if err != nil || len(rows) != 2 || rows[1]["name"] != "Japanese Yen" || rows[1]["decimals"] != "0" {
+		t.Errorf("unexpected CSV rows: %v (%v)", rows, err)
+	}
+	rows, err = byPath["reference/countries"].SourceBinding.StaticRows()
+	if err != nil || len(rows) != 1 || rows[0]["region"] != "AMER" {
+		t.Errorf("unexpected JSON rows: %v (%v)", rows, err)
+	}
+}
+
+func TestLoadStaticDataFileMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "reference.yaml"), `
+reference/currencies:
+  source_binding:
+    type: static
+    config: {data_file: missing.csv}
+`)
+	_, err := LoadCatalog(filepath.Join(dir, "reference.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "reference/currencies: data_file") {
+		t.Errorf("expected the node and data_file named, got %v", err)
+	}
+}
+
+func TestStaticConfigViolations(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+ref/empty:
+  source_binding:
+    type: static
+    config: {key_columns: [code]}
+ref/both:
+  source_binding:
+    type: static
+    config: {rows: [{code: USD}], data_file: currencies.csv}
+ref/scalar-rows:
+  source_binding:
+    type: static
+    config: {rows: [USD, EUR]}
+ref/keys:
+  source_binding:
+    type: static
+    config: {rows: [{code: USD}], key_columns: [code, 7]}
+ref/inline:
+  source_binding:
+    type: static
+    config: {rows: [{code: USD}], key_columns: [code]}
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *SourceConfigError, got %v", err)
+	}
+	if len(cfgErr.Violations) != 4 {
+		t.Fatalf("expected 4 violations, got %d: %v", len(cfgErr.Violations), err)
+	}
+	byPath := make(map[string]SourceConfigViolation)
+	for _, v := range cfgErr.Violations {
+		byPath[v.Path] = v
+	}
+	if v := byPath["ref/empty"]; !strings.Contains(v.Message, "needs one of rows, data_file or base_path") {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	if v := byPath["ref/both"]; !strings.Contains(v.Message, "both rows and data_file") {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	if v := byPath["ref/scalar-rows"]; !strings.Contains(v.Message, "rows[0] must be a map") {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+	if v := byPath["ref/keys"]; !strings.Contains(v.Message, "key_columns[1]") {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+}
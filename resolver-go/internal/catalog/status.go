@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// statusTransitions lists the statuses each status may move to: forward
+// through the lifecycle, back to draft from review, and an undeprecation
+var statusTransitions = map[NodeStatus][]NodeStatus{
+	NodeStatusDraft:         {NodeStatusPendingReview},
+	NodeStatusPendingReview: {NodeStatusApproved, NodeStatusDraft},
+	NodeStatusApproved:      {NodeStatusActive, NodeStatusDraft},
+	NodeStatusActive:        {NodeStatusDeprecated},
+	NodeStatusDeprecated:    {NodeStatusArchived, NodeStatusActive},
+	NodeStatusArchived:      {},
+}
+
+// NextStatuses returns the statuses a node in status s may move to
+func (s NodeStatus) NextStatuses() []NodeStatus {
+	return append([]NodeStatus{}, statusTransitions[s]...)
+}
+
+// CanTransitionTo reports whether a node may move from s to next
+func (s NodeStatus) CanTransitionTo(next NodeStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransitionError is a status change the lifecycle does not allow:
+// either not a transition from the current status, or one whose requirement
+// the node does not meet (Reason)
+type StatusTransitionError struct {
+	Path    string
+	From    NodeStatus
+	To      NodeStatus
+	Allowed []NodeStatus // Statuses the node may move to
+	Reason  string
+}
+
+func (e *StatusTransitionError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("cannot move '%s' from %s to %s: %s", e.Path, e.From, e.To, e.Reason)
+	}
+	return fmt.Sprintf("cannot move '%s' from %s to %s", e.Path, e.From, e.To)
+}
+
+// StatusChange is the outcome of UpdateStatus. Warnings list requirements of
+// the new status the node does not fully meet but that do not block it.
+type StatusChange struct {
+	Path       string     `json:"path"`
+	OldStatus  NodeStatus `json:"old_status"`
+	NewStatus  NodeStatus `json:"new_status"`
+	Warnings   []string   `json:"warnings,omitempty"`
+	Overridden bool       `json:"overridden,omitempty"`
+}
+
+// UpdateStatus moves the node at path to status, as Update does, when the
+// lifecycle allows it: the transition must be in the table of NextStatuses,
+// a node becoming active must have complete ownership (inherited fields
+// count), and one becoming deprecated without a successor gets a warning.
+// Otherwise it fails with *StatusTransitionError. A non-empty override
+// skips the checks and is recorded as the reason in the "status_changed"
+// audit entry by actor. Approving a node records actor as its ApprovedBy.
+// Setting the current status is a no-op.
+func (r *Registry) UpdateStatus(path string, status NodeStatus, actor, override string) (*StatusChange, error) {
+	change := &StatusChange{Path: path, NewStatus: status, Overridden: override != ""}
+	mutate := func(node *CatalogNode) error {
+		change.OldStatus = node.Status
+		if node.Status == status {
+			return nil
+		}
+		if override == "" {
+			if err := r.checkTransition(node, status); err != nil {
+				return err
+			}
+		}
+		if status == NodeStatusDeprecated && node.Successor == nil {
+			change.Warnings = append(change.Warnings, "deprecated without a successor; clients have nothing to migrate to")
+		}
+		node.Status = status
+		if status == NodeStatusApproved && actor != "" {
+			approver := actor
+			node.ApprovedBy = &approver
+		}
+		return nil
+	}
+	_, err := r.update(path, mutate, func(current, updated *CatalogNode, _ *NodeChange) {
+		oldValue, newValue := string(current.Status), string(updated.Status)
+		var details *string
+		if notes := statusNotes(override, change.Warnings); notes != "" {
+			details = &notes
+		}
+		r.appendAudit(newAuditEntry(path, "status_changed", actor, &oldValue, &newValue, details))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// checkTransition returns a *StatusTransitionError when node may not move
+// to status
+func (r *Registry) checkTransition(node *CatalogNode, status NodeStatus) error {
+	refuse := func(reason string) error {
+		return &StatusTransitionError{Path: node.Path, From: node.Status, To: status, Allowed: node.Status.NextStatuses(), Reason: reason}
+	}
+	if !node.Status.CanTransitionTo(status) {
+		return refuse("")
+	}
+	if status == NodeStatusActive {
+		ro := r.ResolveOwnership(node.Path)
+		owned := &Ownership{AccountableOwner: ro.AccountableOwner, DataSpecialist: ro.DataSpecialist, SupportChannel: ro.SupportChannel}
+		if !owned.IsComplete() {
+			return refuse("active nodes need accountable_owner, data_specialist and support_channel, set or inherited")
+		}
+	}
+	return nil
+}
+
+// statusNotes renders the override reason and warnings of a status change
+// for its audit entry
+func statusNotes(override string, warnings []string) string {
+	notes := make([]string, 0, len(warnings)+1)
+	if override != "" {
+		notes = append(notes, "override: "+override)
+	}
+	return strings.Join(append(notes, warnings...), "; ")
+}
@@ -0,0 +1,82 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	for _, tc := range []struct {
+		from, to NodeStatus
+		allowed  bool
+	}{
+		{NodeStatusDraft, NodeStatusPendingReview, true},
+		{NodeStatusPendingReview, NodeStatusApproved, true},
+		{NodeStatusApproved, NodeStatusDraft, true},
+		{NodeStatusActive, NodeStatusDeprecated, true},
+		{NodeStatusDeprecated, NodeStatusArchived, true},
+		{NodeStatusDraft, NodeStatusDeprecated, false},
+		{NodeStatusDraft, NodeStatusActive, false},
+		{NodeStatusArchived, NodeStatusActive, false},
+		{NodeStatusActive, NodeStatusDraft, false},
+	} {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
+
+func TestUpdateStatusEnforcesLifecycle(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&CatalogNode{Path: "prices", Status: NodeStatusActive, Ownership: &Ownership{
+		AccountableOwner: strPtr("team-prices"), DataSpecialist: strPtr("jsmith"),
+	}})
+	r.Register(makeNode("prices/bonds", "Bonds", "", NodeStatusApproved, true))
+
+	_, err := r.UpdateStatus("prices/bonds", NodeStatusActive, "alice", "")
+	terr, ok := err.(*StatusTransitionError)
+	if !ok || !strings.Contains(terr.Reason, "support_channel") {
+		t.Fatalf("expected incomplete ownership to block activation, got %v", err)
+	}
+
+	r.Get("prices").Ownership.SupportChannel = strPtr("#prices")
+	change, err := r.UpdateStatus("prices/bonds", NodeStatusActive, "alice", "")
+	if err != nil || change.OldStatus != NodeStatusApproved || r.Get("prices/bonds").Status != NodeStatusActive {
+		t.Fatalf("expected inherited ownership to allow activation, got %+v, %v", change, err)
+	}
+
+	change, err = r.UpdateStatus("prices/bonds", NodeStatusDeprecated, "alice", "")
+	if err != nil || len(change.Warnings) != 1 {
+		t.Fatalf("expected a warning deprecating without a successor, got %+v, %v", change, err)
+	}
+	if _, err := r.UpdateStatus("prices/bonds", NodeStatusDraft, "alice", ""); err == nil {
+		t.Error("expected deprecated -> draft to be refused")
+	} else if allowed := err.(*StatusTransitionError).Allowed; len(allowed) != 2 {
+		t.Errorf("expected archived and active to be allowed, got %v", allowed)
+	}
+
+	change, err = r.UpdateStatus("prices/bonds", NodeStatusDraft, "alice", "re-scoping the dataset")
+	if err != nil || !change.Overridden || r.Get("prices/bonds").Status != NodeStatusDraft {
+		t.Fatalf("expected the override to apply, got %+v, %v", change, err)
+	}
+	entry := r.AuditEntries("prices/bonds", 1, nil)[0]
+	if entry.Action != "status_changed" || entry.Details == nil || *entry.Details != "override: re-scoping the dataset" {
+		t.Errorf("expected the override reason in the audit entry, got %+v", entry)
+	}
+}
+
+func TestUpdateStatusRecordsApprover(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("prices/bonds", "Bonds", "", NodeStatusPendingReview, true))
+
+	if _, err := r.UpdateStatus("prices/bonds", NodeStatusApproved, "carol", ""); err != nil {
+		t.Fatal(err)
+	}
+	node := r.Get("prices/bonds")
+	if node.ApprovedBy == nil || *node.ApprovedBy != "carol" {
+		t.Fatalf("expected carol to be recorded as approver, got %v", node.ApprovedBy)
+	}
+	if entry := r.AuditEntries("prices/bonds", 1, nil)[0]; entry.Actor != "carol" {
+		t.Errorf("expected carol in the audit entry, got %+v", entry)
+	}
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownField is a YAML key that matches no field of the catalog structs,
+// usually a typo such as dispay_name or source_bindings
+type UnknownField struct {
+	Path  string `json:"path"` // Catalog path whose definition contains the key
+	File  string `json:"file,omitempty"`
+	Line  int    `json:"line"`
+	Field string `json:"field"`
+}
+
+func (u UnknownField) String() string {
+	where := fmt.Sprintf("line %d", u.Line)
+	if u.File != "" {
+		where = fmt.Sprintf("%s:%d", u.File, u.Line)
+	}
+	return fmt.Sprintf("%s: '%s' has unknown field '%s'", where, u.Path, u.Field)
+}
+
+// UnknownFields collects the unknown keys recorded on nodes by the YAML
+// loader, sorted by file and line. A key in a default shared by several nodes
+// is listed once.
+func UnknownFields(nodes []*CatalogNode) []UnknownField {
+	result := make([]UnknownField, 0)
+	seen := make(map[UnknownField]bool)
+	for _, node := range nodes {
+		for _, u := range node.UnknownFields {
+			if !seen[u] {
+				seen[u] = true
+				result = append(result, u)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+// unknownFieldPattern matches the error yaml.v3 reports for each unknown key
+// when decoding with KnownFields(true)
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type (\S+)`)
+
+// findUnknownFields decodes the document strictly and returns every unknown
+// key, attributed to the catalog path whose definition it appears in. lines
+// maps each line of a node definition to its path (see yamlEntryLines).
+func findUnknownFields(data []byte, lines map[int]string) []UnknownField {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var doc map[string]*catalogTreeNodeYAML
+	var typeErr *yaml.TypeError
+	if err := dec.Decode(&doc); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var result []UnknownField
+	for _, msg := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(m[1])
+		path, ok := lines[line]
+		if !ok {
+			continue // _include, schema_version or _defaults
+		}
+		result = append(result, UnknownField{Path: path, Line: line, Field: m[2]})
+	}
+	return result
+}
+
+// findUnknownSectionFields is findUnknownFields for a named section such as
+// _defaults, returning the unknown keys of each entry by name. mapping is the
+// section's mapping node and doc a pointer to a struct decoding only that
+// section.
+func findUnknownSectionFields(data []byte, section string, mapping *yaml.Node, doc interface{}) map[string][]UnknownField {
+	keys := mappingKeyLines(mapping)
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var typeErr *yaml.TypeError
+	if err := dec.Decode(doc); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	docType := strings.TrimPrefix(fmt.Sprintf("%T", doc), "*")
+	result := make(map[string][]UnknownField)
+	for _, msg := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(msg)
+		if m == nil || m[3] == docType {
+			continue // Top-level catalog paths are not part of the section
+		}
+		line, _ := strconv.Atoi(m[1])
+		name := keys.owner(line)
+		if name == "" {
+			continue
+		}
+		result[name] = append(result[name], UnknownField{Path: section + "." + name, Line: line, Field: m[2]})
+	}
+	return result
+}
+
+type keyLine struct {
+	key  string
+	line int
+}
+
+// keyLines lists a mapping's keys in document order, to map a line back to
+// the key it falls under
+type keyLines []keyLine
+
+func mappingKeyLines(mapping *yaml.Node) keyLines {
+	keys := make(keyLines, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, keyLine{mapping.Content[i].Value, mapping.Content[i].Line})
+	}
+	return keys
+}
+
+// owner returns the last key starting at or before line, or ""
+func (k keyLines) owner(line int) string {
+	idx := sort.Search(len(k), func(i int) bool { return k[i].line > line }) - 1
+	if idx < 0 {
+		return ""
+	}
+	return k[idx].key
+}
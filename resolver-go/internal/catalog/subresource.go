@@ -0,0 +1,80 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubResourcesKey is the source binding config key holding sub-resources:
+// named config fragments (e.g. their own query or endpoint) selected by the
+// final path segment below the binding, such as details.corporate.actions.
+// Names are dotted; a name with no entry of its own falls back to its
+// longest dotted prefix that has one.
+const SubResourcesKey = "sub_resources"
+
+// SubResources returns the binding's sub-resource entries, or nil if it has none
+func (sb *SourceBinding) SubResources() map[string]interface{} {
+	entries, _ := sb.Config[SubResourcesKey].(map[string]interface{})
+	return entries
+}
+
+// SubResource returns the entry selected by a sub-resource name: the exact
+// entry, else the one for the longest dotted prefix of name. matched is the
+// name of the entry used.
+func (sb *SourceBinding) SubResource(name string) (matched string, entry map[string]interface{}, ok bool) {
+	entries := sb.SubResources()
+	for candidate := name; candidate != ""; {
+		if entry, ok := entries[candidate].(map[string]interface{}); ok {
+			return candidate, entry, true
+		}
+		i := strings.LastIndex(candidate, ".")
+		if i < 0 {
+			break
+		}
+		candidate = candidate[:i]
+	}
+	return "", nil, false
+}
+
+// SubResourceNames returns the names of the binding's sub-resources, sorted
+func (sb *SourceBinding) SubResourceNames() []string {
+	entries := sb.SubResources()
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkSubResources validates the sub_resources config key: a mapping of
+// sub-resource name to a mapping of config overrides
+func checkSubResources(path string, sb *SourceBinding) []SourceConfigViolation {
+	raw, ok := sb.Config[SubResourcesKey]
+	if !ok {
+		return nil
+	}
+	violation := func(msg string) []SourceConfigViolation {
+		return []SourceConfigViolation{{
+			Path:       path,
+			SourceType: sb.SourceType,
+			Message:    fmt.Sprintf("%s (%s): %s", path, sb.SourceType, msg),
+		}}
+	}
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return violation(fmt.Sprintf("config key '%s' must be a map of sub-resource name to config, got %T", SubResourcesKey, raw))
+	}
+	var violations []SourceConfigViolation
+	for _, name := range sb.SubResourceNames() {
+		if _, ok := entries[name].(map[string]interface{}); !ok {
+			violations = append(violations, violation(fmt.Sprintf("%s.%s must be a map of config overrides, got %T", SubResourcesKey, name, entries[name]))...)
+			continue
+		}
+		if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") || strings.Contains(name, "/") {
+			violations = append(violations, violation(fmt.Sprintf("%s: '%s' is not a valid sub-resource name", SubResourcesKey, name))...)
+		}
+	}
+	return violations
+}
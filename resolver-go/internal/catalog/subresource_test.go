@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSubResourcePrefixFallback(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+securities:
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: REFDATA
+      query: "SELECT * FROM SECURITIES"
+      sub_resources:
+        details: {query: "SELECT * FROM SECURITY_DETAILS"}
+        details.pricing: {query: "SELECT * FROM SECURITY_PRICING"}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	binding := nodes[0].SourceBinding
+
+	tests := []struct {
+		name, matched string
+		ok            bool
+	}{
+		{"details.pricing", "details.pricing", true},
+		{"details.pricing.intraday", "details.pricing", true},
+		{"details.corporate.actions", "details", true},
+		{"history", "", false},
+		{"detailsx", "", false},
+	}
+	for _, tt := range tests {
+		matched, entry, ok := binding.SubResource(tt.name)
+		if ok != tt.ok || matched != tt.matched {
+			t.Errorf("SubResource(%q) = %q, %v; want %q, %v", tt.name, matched, ok, tt.matched, tt.ok)
+		}
+		if ok && entry["query"] == nil {
+			t.Errorf("SubResource(%q) returned an entry without its query", tt.name)
+		}
+	}
+	if names := strings.Join(binding.SubResourceNames(), ","); names != "details,details.pricing" {
+		t.Errorf("unexpected names: %s", names)
+	}
+}
+
+func TestSubResourceValidation(t *testing.T) {
+	_, err := ParseCatalog([]byte(`
+prices/equity:
+  source_binding:
+    type: snowflake
+    config:
+      account: acme
+      database: MARKET_DATA
+      sub_resources:
+        details..corporate: {query: "SELECT 1"}
+        history: "SELECT 2"
+prices/fx:
+  source_binding:
+    type: rest
+    config:
+      base_url: "https://fx.example.com"
+      sub_resources: [rates]
+`))
+	var cfgErr *SourceConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *SourceConfigError, got %v", err)
+	}
+	if len(cfgErr.Violations) != 3 {
+		t.Fatalf("expected 3 violations, got %d: %v", len(cfgErr.Violations), err)
+	}
+	for _, want := range []string{"'details..corporate' is not a valid sub-resource name", "sub_resources.history must be a map", "'sub_resources' must be a map"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %v", want, err)
+		}
+	}
+}
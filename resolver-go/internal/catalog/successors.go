@@ -0,0 +1,182 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SuccessorIssueKind classifies a problem found in a successor chain
+type SuccessorIssueKind string
+
+const (
+	SuccessorIssueCycle    SuccessorIssueKind = "cycle"              // Chain loops back on itself
+	SuccessorIssueDangling SuccessorIssueKind = "dangling"           // Successor path is not registered
+	SuccessorIssueArchived SuccessorIssueKind = "archived_successor" // Successor is archived (not resolvable)
+)
+
+// SuccessorIssue describes a broken successor relationship.
+// It also implements error so SuccessorChain can return it directly.
+type SuccessorIssue struct {
+	Kind      SuccessorIssueKind `json:"kind"`
+	Path      string             `json:"path"`
+	Successor string             `json:"successor"`
+	Chain     []string           `json:"chain,omitempty"`
+	Message   string             `json:"message"`
+}
+
+func (i *SuccessorIssue) Error() string {
+	return i.Message
+}
+
+// SuccessorChain returns the successor chain starting at path, including path itself.
+// The chain follows Successor links from the starting node, and through any
+// deprecated successors, stopping at the first successor that is not deprecated
+// or has no successor of its own. A cycle or a dangling successor returns the
+// chain walked so far together with a *SuccessorIssue.
+func (r *Registry) SuccessorChain(path string) ([]string, error) {
+	return successorChain(r.load().nodes, path)
+}
+
+func successorChain(nodes map[string]*CatalogNode, path string) ([]string, error) {
+	chain := []string{path}
+	seen := map[string]bool{path: true}
+
+	current := path
+	for {
+		node, ok := nodes[current]
+		if !ok {
+			if current == path {
+				return nil, fmt.Errorf("path not found: %s", path)
+			}
+			break
+		}
+		if node.Successor == nil || *node.Successor == "" {
+			break
+		}
+		// Only the starting node and deprecated successors are followed further
+		if current != path && node.Status != NodeStatusDeprecated {
+			break
+		}
+
+		next := *node.Successor
+		if seen[next] {
+			return append(chain, next), &SuccessorIssue{
+				Kind:      SuccessorIssueCycle,
+				Path:      path,
+				Successor: next,
+				Chain:     append(append([]string{}, chain...), next),
+				Message: fmt.Sprintf("Successor cycle detected: %s",
+					strings.Join(append(append([]string{}, chain...), next), " -> ")),
+			}
+		}
+		if _, exists := nodes[next]; !exists {
+			return chain, &SuccessorIssue{
+				Kind:      SuccessorIssueDangling,
+				Path:      current,
+				Successor: next,
+				Chain:     append([]string{}, chain...),
+				Message:   fmt.Sprintf("Successor '%s' of '%s' is not registered", next, current),
+			}
+		}
+
+		seen[next] = true
+		chain = append(chain, next)
+		current = next
+	}
+
+	return chain, nil
+}
+
+// ValidateSuccessors checks every successor link in the catalog and reports
+// cycles, dangling successors, and successors that are archived.
+// Each cycle is reported once, anchored at its lexicographically smallest path.
+func (r *Registry) ValidateSuccessors() []SuccessorIssue {
+	nodes := r.load().nodes
+
+	issues := make([]SuccessorIssue, 0)
+	reportedCycles := make(map[string]bool)
+
+	paths := make([]string, 0, len(nodes))
+	for p, node := range nodes {
+		if node.Successor != nil && *node.Successor != "" {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		node := nodes[p]
+		succ := *node.Successor
+
+		succNode, ok := nodes[succ]
+		if !ok {
+			issues = append(issues, SuccessorIssue{
+				Kind:      SuccessorIssueDangling,
+				Path:      p,
+				Successor: succ,
+				Message:   fmt.Sprintf("Successor '%s' of '%s' is not registered", succ, p),
+			})
+			continue
+		}
+		if succNode.Status == NodeStatusArchived {
+			issues = append(issues, SuccessorIssue{
+				Kind:      SuccessorIssueArchived,
+				Path:      p,
+				Successor: succ,
+				Message:   fmt.Sprintf("Successor '%s' of '%s' is archived", succ, p),
+			})
+		}
+
+		if cycle := findSuccessorCycle(nodes, p); cycle != nil {
+			key := cycleKey(cycle)
+			if reportedCycles[key] {
+				continue
+			}
+			reportedCycles[key] = true
+			issues = append(issues, SuccessorIssue{
+				Kind:      SuccessorIssueCycle,
+				Path:      p,
+				Successor: succ,
+				Chain:     cycle,
+				Message:   fmt.Sprintf("Successor cycle detected: %s", strings.Join(cycle, " -> ")),
+			})
+		}
+	}
+
+	return issues
+}
+
+// findSuccessorCycle follows raw successor links (regardless of status) from
+// start and returns the cycle members closed back on the first member, or nil
+// if start is not part of a cycle.
+func findSuccessorCycle(nodes map[string]*CatalogNode, start string) []string {
+	chain := []string{start}
+	current := start
+	for i := 0; i <= len(nodes); i++ {
+		node, ok := nodes[current]
+		if !ok || node.Successor == nil || *node.Successor == "" {
+			return nil
+		}
+		next := *node.Successor
+		if next == start {
+			return append(chain, start)
+		}
+		for _, c := range chain {
+			if c == next {
+				// start leads into a cycle it is not part of
+				return nil
+			}
+		}
+		chain = append(chain, next)
+		current = next
+	}
+	return nil
+}
+
+// cycleKey returns a stable key for a cycle independent of its starting member
+func cycleKey(cycle []string) string {
+	members := append([]string{}, cycle[:len(cycle)-1]...)
+	sort.Strings(members)
+	return strings.Join(members, "\x00")
+}
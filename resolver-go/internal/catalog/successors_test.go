@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func makeDeprecated(path, successor string) *CatalogNode {
+	node := makeNode(path, path, "", NodeStatusDeprecated, true)
+	if successor != "" {
+		node.Successor = strPtr(successor)
+	}
+	return node
+}
+
+// --- SuccessorChain ---
+
+func TestSuccessorChainFollowsDeprecatedHops(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeDeprecated("a", "b"))
+	r.Register(makeDeprecated("b", "c"))
+	r.Register(makeNode("c", "C", "", NodeStatusActive, true))
+
+	chain, err := r.SuccessorChain("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"a", "b", "c"}
+	if len(chain) != len(expected) {
+		t.Fatalf("expected chain %v, got %v", expected, chain)
+	}
+	for i := range expected {
+		if chain[i] != expected[i] {
+			t.Errorf("chain[%d]: expected %q, got %q", i, expected[i], chain[i])
+		}
+	}
+}
+
+func TestSuccessorChainStopsAtActiveSuccessor(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeDeprecated("a", "b"))
+	b := makeNode("b", "B", "", NodeStatusActive, true)
+	b.Successor = strPtr("c")
+	r.Register(b)
+	r.Register(makeNode("c", "C", "", NodeStatusActive, true))
+
+	chain, err := r.SuccessorChain("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) != 2 || chain[1] != "b" {
+		t.Errorf("expected chain to stop at active 'b', got %v", chain)
+	}
+}
+
+func TestSuccessorChainNoSuccessor(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("a", "A", "", NodeStatusActive, true))
+
+	chain, err := r.SuccessorChain("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) != 1 || chain[0] != "a" {
+		t.Errorf("expected chain [a], got %v", chain)
+	}
+}
+
+func TestSuccessorChainCycle(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeDeprecated("a", "b"))
+	r.Register(makeDeprecated("b", "a"))
+
+	_, err := r.SuccessorChain("a")
+	issue, ok := err.(*SuccessorIssue)
+	if !ok {
+		t.Fatalf("expected *SuccessorIssue, got %T (%v)", err, err)
+	}
+	if issue.Kind != SuccessorIssueCycle {
+		t.Errorf("expected cycle issue, got %s", issue.Kind)
+	}
+}
+
+func TestSuccessorChainDangling(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeDeprecated("a", "missing"))
+
+	chain, err := r.SuccessorChain("a")
+	issue, ok := err.(*SuccessorIssue)
+	if !ok {
+		t.Fatalf("expected *SuccessorIssue, got %T (%v)", err, err)
+	}
+	if issue.Kind != SuccessorIssueDangling {
+		t.Errorf("expected dangling issue, got %s", issue.Kind)
+	}
+	if len(chain) != 1 || chain[0] != "a" {
+		t.Errorf("expected partial chain [a], got %v", chain)
+	}
+}
+
+// --- ValidateSuccessors ---
+
+func TestValidateSuccessorsClean(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeDeprecated("old", "new"))
+	r.Register(makeNode("new", "New", "", NodeStatusActive, true))
+
+	if issues := r.ValidateSuccessors(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidateSuccessorsReportsEachProblem(t *testing.T) {
+	r := NewRegistry()
+	// Three-node cycle, reported once
+	r.Register(makeDeprecated("x", "y"))
+	r.Register(makeDeprecated("y", "z"))
+	r.Register(makeDeprecated("z", "x"))
+	// Dangling
+	r.Register(makeDeprecated("lost", "nowhere"))
+	// Archived successor
+	r.Register(makeDeprecated("legacy", "retired"))
+	r.Register(makeNode("retired", "Retired", "", NodeStatusArchived, true))
+
+	issues := r.ValidateSuccessors()
+
+	counts := make(map[SuccessorIssueKind]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+	if counts[SuccessorIssueCycle] != 1 {
+		t.Errorf("expected 1 cycle issue, got %d", counts[SuccessorIssueCycle])
+	}
+	if counts[SuccessorIssueDangling] != 1 {
+		t.Errorf("expected 1 dangling issue, got %d", counts[SuccessorIssueDangling])
+	}
+	if counts[SuccessorIssueArchived] != 1 {
+		t.Errorf("expected 1 archived successor issue, got %d", counts[SuccessorIssueArchived])
+	}
+
+	for _, issue := range issues {
+		if issue.Kind == SuccessorIssueCycle && issue.Path != "x" {
+			t.Errorf("expected cycle anchored at 'x', got %q", issue.Path)
+		}
+	}
+}
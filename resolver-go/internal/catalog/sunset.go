@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SunsetIssue reports a sunset deadline that cannot be parsed
+type SunsetIssue struct {
+	Path     string `json:"path"`
+	Deadline string `json:"deadline"`
+	Message  string `json:"message"`
+}
+
+func (i SunsetIssue) Error() string {
+	return i.Message
+}
+
+// ParseISODate parses an ISO date (2006-01-02) or RFC 3339 timestamp, as
+// used by the lifecycle date fields. A bare date means midnight UTC at the
+// start of that day.
+func ParseISODate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: expected ISO date (YYYY-MM-DD)", s)
+}
+
+// ParseSunsetDeadline parses a sunset deadline; see ParseISODate
+func ParseSunsetDeadline(s string) (time.Time, error) {
+	t, err := ParseISODate(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sunset deadline %q: expected ISO date (YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+// ExpiredSunsets returns deprecated nodes whose sunset deadline is at or before now,
+// sorted by deadline. Nodes with malformed deadlines are skipped; see ValidateSunsets.
+func (r *Registry) ExpiredSunsets(now time.Time) []*CatalogNode {
+	return r.sunsetsBetween(time.Time{}, now)
+}
+
+// ExpiringSunsets returns deprecated nodes whose sunset deadline falls after now
+// but within the given window, sorted by deadline.
+func (r *Registry) ExpiringSunsets(now time.Time, within time.Duration) []*CatalogNode {
+	return r.sunsetsBetween(now.Add(time.Nanosecond), now.Add(within))
+}
+
+// sunsetsBetween returns deprecated nodes with a deadline in [from, to]
+func (r *Registry) sunsetsBetween(from, to time.Time) []*CatalogNode {
+	type dated struct {
+		node     *CatalogNode
+		deadline time.Time
+	}
+
+	matches := make([]dated, 0)
+	for _, node := range r.load().nodes {
+		if node.Status != NodeStatusDeprecated || node.SunsetDeadline == nil {
+			continue
+		}
+		deadline, err := ParseSunsetDeadline(*node.SunsetDeadline)
+		if err != nil {
+			continue
+		}
+		if deadline.Before(from) || deadline.After(to) {
+			continue
+		}
+		matches = append(matches, dated{node, deadline})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].deadline.Equal(matches[j].deadline) {
+			return matches[i].deadline.Before(matches[j].deadline)
+		}
+		return matches[i].node.Path < matches[j].node.Path
+	})
+
+	result := make([]*CatalogNode, len(matches))
+	for i, m := range matches {
+		result[i] = m.node
+	}
+	return result
+}
+
+// ValidateSunsets reports every node whose sunset deadline cannot be parsed
+func (r *Registry) ValidateSunsets() []SunsetIssue {
+	issues := make([]SunsetIssue, 0)
+	for _, node := range r.load().nodes {
+		if node.SunsetDeadline == nil {
+			continue
+		}
+		if _, err := ParseSunsetDeadline(*node.SunsetDeadline); err != nil {
+			issues = append(issues, SunsetIssue{
+				Path:     node.Path,
+				Deadline: *node.SunsetDeadline,
+				Message:  fmt.Sprintf("Sunset deadline of '%s' is not an ISO date: %q", node.Path, *node.SunsetDeadline),
+			})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sunsetNode(path, deadline string, status NodeStatus) *CatalogNode {
+	node := makeNode(path, path, "", status, true)
+	node.SunsetDeadline = strPtr(deadline)
+	return node
+}
+
+func TestExpiredAndExpiringSunsets(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterMany([]*CatalogNode{
+		sunsetNode("old/b", "2026-01-15", NodeStatusDeprecated),
+		sunsetNode("old/a", "2025-06-01", NodeStatusDeprecated),
+		sunsetNode("soon", "2026-03-10", NodeStatusDeprecated),
+		sunsetNode("later", "2026-09-01", NodeStatusDeprecated),
+		sunsetNode("active", "2025-01-01", NodeStatusActive),
+		sunsetNode("bad", "next tuesday", NodeStatusDeprecated),
+	})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expired := r.ExpiredSunsets(now)
+	if len(expired) != 2 || expired[0].Path != "old/a" || expired[1].Path != "old/b" {
+		t.Errorf("expected [old/a old/b], got %v", nodePaths(expired))
+	}
+
+	expiring := r.ExpiringSunsets(now, 30*24*time.Hour)
+	if len(expiring) != 1 || expiring[0].Path != "soon" {
+		t.Errorf("expected [soon], got %v", nodePaths(expiring))
+	}
+}
+
+func TestSunsetDeadlineIsInclusive(t *testing.T) {
+	r := NewRegistry()
+	r.Register(sunsetNode("today", "2026-03-01", NodeStatusDeprecated))
+
+	if got := r.ExpiredSunsets(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)); len(got) != 1 {
+		t.Errorf("node should be expired on its deadline, got %v", nodePaths(got))
+	}
+}
+
+func TestValidateSunsetsReportsMalformed(t *testing.T) {
+	r := NewRegistry()
+	r.Register(sunsetNode("good", "2026-01-01", NodeStatusDeprecated))
+	r.Register(sunsetNode("rfc", "2026-01-01T10:00:00Z", NodeStatusDeprecated))
+	r.Register(sunsetNode("bad", "01/02/2026", NodeStatusDeprecated))
+
+	issues := r.ValidateSunsets()
+	if len(issues) != 1 || issues[0].Path != "bad" {
+		t.Errorf("expected one issue for 'bad', got %+v", issues)
+	}
+}
+
+func nodePaths(nodes []*CatalogNode) []string {
+	paths := make([]string, len(nodes))
+	for i, n := range nodes {
+		paths[i] = n.Path
+	}
+	return paths
+}
+
+func TestDeprecationReport(t *testing.T) {
+	r := NewRegistry()
+	root := makeNode("rates", "Rates", "", NodeStatusActive, false)
+	root.Domain = strPtr("Markets")
+	root.Ownership = &Ownership{AccountableOwner: strPtr("team-rates")}
+	legacy := sunsetNode("rates/legacy", "2026-03-11", NodeStatusDeprecated)
+	legacy.Successor = strPtr("rates/old")
+	legacy.MigrationGuideURL = strPtr("https://wiki.example.com/rates")
+	old := sunsetNode("rates/old", "2026-02-01", NodeStatusDeprecated)
+	old.Successor = strPtr("rates/gone")
+	undated := makeNode("undated", "Undated", "", NodeStatusDeprecated, true)
+	r.RegisterMany([]*CatalogNode{root, legacy, old, undated, sunsetNode("bad", "next tuesday", NodeStatusDeprecated)})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := r.DeprecationReport(now, DeprecationFilter{})
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.Path
+	}
+	if strings.Join(paths, " ") != "rates/old rates/legacy bad undated" {
+		t.Fatalf("unexpected order %v", paths)
+	}
+	if e := entries[0]; e.SuccessorIssue != SuccessorIssueDangling || *e.DaysRemaining != -28 || e.Owner != "team-rates" || e.Domain != "Markets" {
+		t.Errorf("unexpected rates/old entry %+v", e)
+	}
+	if e := entries[1]; e.SuccessorIssue != SuccessorIssueDeprecated || *e.DaysRemaining != 10 || e.MigrationGuideURL == "" {
+		t.Errorf("unexpected rates/legacy entry %+v", e)
+	}
+	if e := entries[3]; e.SuccessorIssue != SuccessorIssueNone || e.DaysRemaining != nil {
+		t.Errorf("unexpected undated entry %+v", e)
+	}
+
+	if got := r.DeprecationReport(now, DeprecationFilter{Domain: "markets", Owner: "team-rates"}); len(got) != 2 {
+		t.Errorf("expected both rates nodes, got %+v", got)
+	}
+	week := 7 * 24 * time.Hour
+	if got := r.DeprecationReport(now, DeprecationFilter{Within: &week}); len(got) != 1 || got[0].Path != "rates/old" {
+		t.Errorf("expected only the passed deadline within a week, got %+v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDeprecationsCSV(&buf, entries[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := "path,display_name,domain,owner,successor,successor_issue,sunset_deadline,days_remaining,migration_guide_url\n" +
+		"rates/old,rates/old,Markets,team-rates,rates/gone,dangling,2026-02-01,-28,\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bindingTemplatesKey is the reserved top-level key holding named source
+// binding fragments that nodes pull in with source_binding.template.
+// Templates apply within the file that defines them.
+const bindingTemplatesKey = "_binding_templates"
+
+// bindingTemplatesDocYAML decodes only the _binding_templates section, for
+// strict key checks
+type bindingTemplatesDocYAML struct {
+	Templates map[string]*SourceBindingYAML `yaml:"_binding_templates"`
+}
+
+// isReservedKey reports whether a top-level catalog key is a directive
+// rather than a catalog path
+func isReservedKey(key string) bool {
+	switch key {
+	case includeKey, schemaVersionKey, defaultsKey, bindingTemplatesKey:
+		return true
+	}
+	return false
+}
+
+// parseYAMLBindingTemplates decodes and validates the _binding_templates
+// section, returning the fragments by name
+func parseYAMLBindingTemplates(raw *yaml.Node) (map[string]*yaml.Node, error) {
+	var typed map[string]*SourceBindingYAML
+	if err := raw.Decode(&typed); err != nil {
+		return nil, fmt.Errorf("%s must be a mapping of name to source binding: %w", bindingTemplatesKey, err)
+	}
+
+	templates := make(map[string]*yaml.Node, len(raw.Content)/2)
+	for i := 0; i+1 < len(raw.Content); i += 2 {
+		name, fragment := raw.Content[i].Value, raw.Content[i+1]
+		if err := validateBindingTemplate(name, typed[name]); err != nil {
+			return nil, err
+		}
+		templates[name] = fragment
+	}
+	return templates, nil
+}
+
+// validateBindingTemplate checks a template the way a node's binding is
+// checked. Templates may not themselves use a template.
+func validateBindingTemplate(name string, binding *SourceBindingYAML) error {
+	if binding == nil {
+		return nil
+	}
+	where := bindingTemplatesKey + "." + name
+	if binding.Template != "" {
+		return fmt.Errorf("%s: a binding template cannot use another template", where)
+	}
+	return validateNodeYAML(where, &CatalogNodeYAML{SourceBinding: binding})
+}
+
+// applyYAMLBindingTemplate merges the template named by the node's
+// source_binding.template under its binding. The node wins on every key it
+// sets; config and other mappings are merged key by key.
+func applyYAMLBindingTemplate(path string, node *yaml.Node, templates map[string]*yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	binding := yamlMappingValue(node, "source_binding")
+	if binding == nil || binding.Kind != yaml.MappingNode {
+		return nil
+	}
+	name := yamlMappingValue(binding, "template")
+	if name == nil {
+		return nil
+	}
+	if name.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s: line %d: source_binding.template must be the name of a binding template", path, name.Line)
+	}
+	fragment, ok := templates[name.Value]
+	if !ok {
+		return fmt.Errorf("%s: line %d: source_binding.template references unknown binding template '%s'%s",
+			path, name.Line, name.Value, availableNames(templates, bindingTemplatesKey))
+	}
+	if fragment.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(fragment.Content); i += 2 {
+		mergeYAMLMapping(binding, fragment.Content[i], fragment.Content[i+1])
+	}
+	return nil
+}
+
+// parseJSONBindingTemplates decodes and validates the _binding_templates
+// section of a JSON catalog
+func parseJSONBindingTemplates(msg json.RawMessage) (map[string]json.RawMessage, error) {
+	var templates map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &templates); err != nil {
+		return nil, fmt.Errorf("$.%s must be an object of name to source binding", bindingTemplatesKey)
+	}
+	for name, fragment := range templates {
+		var binding *SourceBindingYAML
+		if err := json.Unmarshal(fragment, &binding); err != nil {
+			return nil, fmt.Errorf("%s", describeJSONError(fragment, fmt.Sprintf("$.%s[%q]", bindingTemplatesKey, name), err))
+		}
+		if err := validateBindingTemplate(name, binding); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// applyJSONBindingTemplate is applyYAMLBindingTemplate for a JSON node definition
+func applyJSONBindingTemplate(path string, msg json.RawMessage, templates map[string]json.RawMessage) (json.RawMessage, error) {
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &node); err != nil || node == nil {
+		return msg, nil // Reported with its JSON path by the regular decode
+	}
+	var binding map[string]json.RawMessage
+	if err := json.Unmarshal(node["source_binding"], &binding); err != nil || binding == nil {
+		return msg, nil
+	}
+	raw, ok := binding["template"]
+	if !ok {
+		return msg, nil
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return nil, fmt.Errorf("%s: source_binding.template must be the name of a binding template", path)
+	}
+	fragmentMsg, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: source_binding.template references unknown binding template '%s'%s",
+			path, name, availableNames(templates, bindingTemplatesKey))
+	}
+
+	var fragment map[string]json.RawMessage
+	if err := json.Unmarshal(fragmentMsg, &fragment); err != nil || fragment == nil {
+		return msg, nil
+	}
+	for key, value := range fragment {
+		binding[key] = mergeJSONValue(binding[key], value)
+	}
+	merged, err := json.Marshal(binding)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	node["source_binding"] = merged
+	return json.Marshal(node)
+}
@@ -0,0 +1,228 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+const templatesCatalog = `
+_binding_templates:
+  snowflake_core:
+    type: snowflake
+    read_only: true
+    config:
+      account: acme
+      database: CORE
+      warehouse: CORE_WH
+
+risk/positions:
+  display_name: Positions
+  source_binding:
+    template: snowflake_core
+    config:
+      table: POSITIONS
+      warehouse: RISK_WH
+
+risk/trades:
+  source_binding:
+    template: snowflake_core
+    read_only: false
+    config:
+      table: TRADES
+
+risk/limits:
+  source_binding:
+    type: oracle
+    config:
+      dsn: oracle://risk
+      query: SELECT * FROM limits
+`
+
+func TestBindingTemplateMergedUnderNode(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(templatesCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	positions := reg.Get("risk/positions").SourceBinding
+	if positions.SourceType != SourceTypeSnowflake || !positions.ReadOnly {
+		t.Errorf("type and read_only should come from the template, got %+v", positions)
+	}
+	want := map[string]interface{}{"account": "acme", "database": "CORE", "warehouse": "RISK_WH", "table": "POSITIONS"}
+	for k, v := range want {
+		if positions.Config[k] != v {
+			t.Errorf("config[%s] = %v, want %v", k, positions.Config[k], v)
+		}
+	}
+
+	trades := reg.Get("risk/trades").SourceBinding
+	if trades.ReadOnly {
+		t.Error("node's read_only: false should win over the template")
+	}
+	if trades.Config["warehouse"] != "CORE_WH" || trades.Config["table"] != "TRADES" {
+		t.Errorf("config should be deep-merged, got %v", trades.Config)
+	}
+	if reg.Get("risk/limits").SourceBinding.SourceType != SourceTypeOracle {
+		t.Error("nodes without a template should be untouched")
+	}
+	if unknown := UnknownFields(nodes); len(unknown) != 0 {
+		t.Errorf("expected no unknown fields, got %v", unknown)
+	}
+}
+
+func TestBindingTemplateJSONMatchesYAML(t *testing.T) {
+	yamlNodes, err := ParseCatalog([]byte(templatesCatalog))
+	if err != nil {
+		t.Fatalf("parse YAML: %v", err)
+	}
+	jsonNodes, err := ParseCatalogJSON([]byte(`{
+  "_binding_templates": {"snowflake_core": {"type": "snowflake", "read_only": true,
+    "config": {"account": "acme", "database": "CORE", "warehouse": "CORE_WH"}}},
+  "risk/positions": {"display_name": "Positions",
+    "source_binding": {"template": "snowflake_core", "config": {"table": "POSITIONS", "warehouse": "RISK_WH"}}},
+  "risk/trades": {"source_binding": {"template": "snowflake_core", "read_only": false, "config": {"table": "TRADES"}}},
+  "risk/limits": {"source_binding": {"type": "oracle", "config": {"dsn": "oracle://risk", "query": "SELECT * FROM limits"}}}
+}`))
+	if err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+
+	reg := NewRegistry()
+	reg.RegisterMany(yamlNodes)
+	if diff := reg.Diff(jsonNodes); !diff.IsEmpty() {
+		t.Errorf("JSON and YAML catalogs differ: %+v", diff)
+	}
+}
+
+func TestBindingTemplateUnknownName(t *testing.T) {
+	_, err := ParseCatalog([]byte("prices:\n  source_binding:\n    template: snowflake_core\n"))
+	if err == nil || !strings.Contains(err.Error(), "unknown binding template 'snowflake_core'") {
+		t.Errorf("expected unknown template error, got %v", err)
+	}
+
+	_, err = ParseCatalogJSON([]byte(`{"_binding_templates": {"a": {"type": "snowflake"}}, "prices": {"source_binding": {"template": "b"}}}`))
+	if err == nil || !strings.Contains(err.Error(), "defined: [a]") {
+		t.Errorf("expected unknown template error listing the templates, got %v", err)
+	}
+}
+
+func TestBindingTemplateErrors(t *testing.T) {
+	tests := map[string]string{
+		"nested template": "_binding_templates:\n  a:\n    template: b\n  b:\n    type: snowflake\n",
+		"invalid cache":   "_binding_templates:\n  a:\n    cache:\n      enabled: true\n",
+		"not a mapping":   "_binding_templates: [a]\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCatalog([]byte(data)); err == nil {
+				t.Errorf("expected error for %q", data)
+			}
+		})
+	}
+}
+
+func TestBindingTemplateFromDefaults(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(`
+_binding_templates:
+  core:
+    type: snowflake
+    config:
+      account: acme
+      database: RISK
+_defaults:
+  risk_team:
+    source_binding:
+      template: core
+risk:
+  extends: risk_team
+  children:
+    positions:
+      extends: risk_team
+      source_binding:
+        config:
+          table: POSITIONS
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+	config := reg.Get("risk/positions").SourceBinding.Config
+	if config["account"] != "acme" || config["table"] != "POSITIONS" {
+		t.Errorf("template named by a default should apply, got %v", config)
+	}
+	if _, ok := reg.Get("risk").SourceBinding.Config["table"]; ok {
+		t.Error("one node's config leaked into another node sharing the template")
+	}
+}
+
+func TestBindingTemplateChangeAffectsAllDependents(t *testing.T) {
+	before, err := ParseCatalog([]byte(templatesCatalog))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	after, err := ParseCatalog([]byte(strings.Replace(templatesCatalog, "database: CORE", "database: CORE_V2", 1)))
+	if err != nil {
+		t.Fatalf("parse changed catalog: %v", err)
+	}
+
+	reg := NewRegistry()
+	reg.RegisterMany(before)
+	diff := reg.Diff(after)
+	breaking := diff.BreakingChanges()
+	sort.Strings(breaking)
+	if len(breaking) != 2 || breaking[0] != "risk/positions" || breaking[1] != "risk/trades" {
+		t.Errorf("expected both nodes using the template to change, got %v", breaking)
+	}
+	if len(diff.Modified) != 2 {
+		t.Errorf("nodes without the template should be unchanged, got %+v", diff.Modified)
+	}
+}
+
+func TestBindingTemplateUnknownFieldsReportedOnce(t *testing.T) {
+	data := "_binding_templates:\n  a:\n    type: snowflake\n    read_onyl: true\n    config: {account: acme, database: CORE}\nprices:\n  source_binding:\n    template: a\nrates:\n  source_binding:\n    template: a\n"
+	nodes, err := ParseCatalog([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unknown := UnknownFields(nodes)
+	if len(unknown) != 1 || unknown[0].Path != "_binding_templates.a" || unknown[0].Line != 4 || unknown[0].Field != "read_onyl" {
+		t.Errorf("expected one unknown field in _binding_templates.a at line 4, got %v", unknown)
+	}
+}
+
+func TestExportExpandsBindingTemplates(t *testing.T) {
+	nodes, err := ParseCatalog([]byte(templatesCatalog))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg := NewRegistry()
+	reg.RegisterMany(nodes)
+
+	data, err := ExportYAML(reg, ExportFlat)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if strings.Contains(string(data), "template") {
+		t.Errorf("export should emit expanded bindings:\n%s", data)
+	}
+	reloaded, err := ParseCatalog(data)
+	if err != nil {
+		t.Fatalf("reparse export: %v", err)
+	}
+	if diff := reg.Diff(reloaded); !diff.IsEmpty() {
+		t.Errorf("exported catalog differs: %+v", diff)
+	}
+}
+
+func TestOverlayRejectsBindingTemplate(t *testing.T) {
+	if _, err := ParseOverlay([]byte("prices:\n  source_binding:\n    template: core\n")); err == nil {
+		t.Error("expected error for source_binding.template in an overlay")
+	}
+	if _, err := ParseOverlay([]byte("_binding_templates:\n  core:\n    type: snowflake\n")); err == nil {
+		t.Error("expected error for _binding_templates in an overlay")
+	}
+}
@@ -0,0 +1,118 @@
+package catalog
+
+// Defaults for the limits on GET /tree responses
+const (
+	DefaultTreeMaxDepth = 10
+	DefaultTreeMaxNodes = 5000
+)
+
+// TreeOptions controls which part of a subtree Tree lists
+type TreeOptions struct {
+	Depth           int  // Levels of children listed below the root
+	MaxNodes        int  // Budget of nodes listed below the root; 0 for no limit
+	IncludeArchived bool // List archived nodes and the nodes beneath them
+	Counts          bool // Set DescendantCount on every node
+}
+
+// TreeNode is one node of a subtree. A path with registered descendants but
+// no node of its own appears as a virtual node. Children is only set on nodes
+// that were expanded; ChildCount tells whether there is more beneath.
+type TreeNode struct {
+	Path            string      `json:"path"`
+	DisplayName     string      `json:"display_name"`
+	IsLeaf          bool        `json:"is_leaf"`
+	Status          NodeStatus  `json:"status"`
+	Virtual         bool        `json:"virtual,omitempty"`
+	ChildCount      int         `json:"child_count"`
+	DescendantCount *int        `json:"descendant_count,omitempty"` // Registered nodes beneath
+	Children        []*TreeNode `json:"children,omitempty"`
+}
+
+// Tree is the subtree at a path, expanded level by level
+type Tree struct {
+	Root      *TreeNode
+	NodeCount int  // Nodes listed below the root
+	Truncated bool // The node budget stopped expansion before Depth
+}
+
+// Tree returns the subtree at path ("" for the whole catalog) down to
+// opts.Depth levels, from one snapshot of the registry. It walks down from
+// path through the snapshot's tree index. Levels are expanded breadth first
+// and a node's children are listed all or none, so when the next set of
+// children would take the listed nodes, the root's children included, past
+// opts.MaxNodes the tree stops there and is marked Truncated. Archived nodes
+// are left out with everything beneath them unless opts.IncludeArchived is
+// set.
+func (r *Registry) Tree(path string, opts TreeOptions) *Tree {
+	snap := r.load()
+	index := snap.treeIndex()
+
+	// children lists the children of p shown in the tree, in order
+	children := func(p string) []string {
+		if opts.IncludeArchived {
+			return index.children[p]
+		}
+		shown := make([]string, 0, len(index.children[p]))
+		for _, c := range index.children[p] {
+			node := snap.nodes[c]
+			if node != nil && node.Status == NodeStatusArchived {
+				continue
+			}
+			if node != nil || index.live[c] > 0 {
+				shown = append(shown, c)
+			}
+		}
+		return shown
+	}
+	makeNode := func(p string, childPaths []string) *TreeNode {
+		tn := &TreeNode{Path: p, ChildCount: len(childPaths)}
+		if node := snap.nodes[p]; node != nil {
+			tn.DisplayName, tn.IsLeaf, tn.Status = node.DisplayName, node.IsLeaf, node.Status
+		} else {
+			tn.Virtual = true
+		}
+		if opts.Counts {
+			count := index.live[p]
+			if opts.IncludeArchived {
+				count = index.descendants[p]
+			}
+			tn.DescendantCount = &count
+		}
+		return tn
+	}
+
+	type entry struct {
+		node     *TreeNode
+		children []string
+	}
+	rootChildren := children(path)
+	tree := &Tree{Root: makeNode(path, rootChildren)}
+	level := []entry{{tree.Root, rootChildren}}
+	for depth := 1; depth <= opts.Depth && len(level) > 0; depth++ {
+		next := make([]entry, 0)
+		for _, parent := range level {
+			if len(parent.children) == 0 {
+				continue
+			}
+			if opts.MaxNodes > 0 && tree.NodeCount+len(parent.children) > opts.MaxNodes {
+				tree.Truncated = true
+				return tree
+			}
+			parent.node.Children = make([]*TreeNode, len(parent.children))
+			for i, p := range parent.children {
+				grandchildren := children(p)
+				parent.node.Children[i] = makeNode(p, grandchildren)
+				next = append(next, entry{parent.node.Children[i], grandchildren})
+			}
+			tree.NodeCount += len(parent.children)
+		}
+		level = next
+	}
+	return tree
+}
+
+// Ancestors returns the paths above path, root first, the way Tree nests
+// them
+func Ancestors(path string) []string {
+	return ancestorPaths(path)
+}
@@ -0,0 +1,77 @@
+package catalog
+
+import "testing"
+
+func newTreeFixture() *Registry {
+	r := NewRegistry()
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	r.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+	r.Register(makeNode("prices/fx/spot", "FX Spot", "", NodeStatusActive, true))
+	r.Register(makeNode("prices/fx/forward", "FX Forwards", "", NodeStatusActive, true))
+	r.Register(makeNode("prices/legacy", "Legacy", "", NodeStatusArchived, false))
+	r.Register(makeNode("prices/legacy/eod", "EOD", "", NodeStatusActive, true))
+	r.Register(makeNode("rates", "Rates", "", NodeStatusActive, false))
+	return r
+}
+
+func TestTreeListsVirtualPathsAndCounts(t *testing.T) {
+	tree := newTreeFixture().Tree("prices", TreeOptions{Depth: 2, Counts: true})
+
+	root := tree.Root
+	if root.Virtual || *root.DescendantCount != 3 || len(root.Children) != 2 {
+		t.Fatalf("expected equity and fx with archived legacy left out, got %+v", root)
+	}
+	fx := root.Children[1]
+	if fx.Path != "prices/fx" || !fx.Virtual || fx.ChildCount != 2 || *fx.DescendantCount != 2 {
+		t.Errorf("expected a virtual prices/fx over two nodes, got %+v", fx)
+	}
+	if len(fx.Children) != 2 || fx.Children[0].Path != "prices/fx/forward" || fx.Children[0].DisplayName != "FX Forwards" {
+		t.Errorf("expected the second level listed in order, got %+v", fx.Children)
+	}
+	if tree.NodeCount != 4 || tree.Truncated {
+		t.Errorf("expected 4 nodes listed, got %d (truncated %v)", tree.NodeCount, tree.Truncated)
+	}
+
+	shallow := newTreeFixture().Tree("prices", TreeOptions{Depth: 1})
+	if fx := shallow.Root.Children[1]; fx.Children != nil || fx.ChildCount != 2 || fx.DescendantCount != nil {
+		t.Errorf("expected fx collapsed at depth 1 without counts, got %+v", fx)
+	}
+
+	archived := newTreeFixture().Tree("prices", TreeOptions{Depth: 2, IncludeArchived: true})
+	if len(archived.Root.Children) != 3 || len(archived.Root.Children[2].Children) != 1 {
+		t.Errorf("expected legacy and its child with include_archived, got %+v", archived.Root.Children)
+	}
+}
+
+func TestTreeStopsAtNodeBudget(t *testing.T) {
+	r := newTreeFixture()
+	whole := r.Tree("", TreeOptions{Depth: 5, MaxNodes: 3})
+	if !whole.Truncated || whole.NodeCount != 2 || len(whole.Root.Children) != 2 {
+		t.Fatalf("expected only the top level within a budget of 3, got %d nodes (truncated %v)", whole.NodeCount, whole.Truncated)
+	}
+	if prices := whole.Root.Children[0]; prices.Children != nil || prices.ChildCount != 2 {
+		t.Errorf("expected prices left collapsed, got %+v", prices)
+	}
+
+	// The root's children count against the budget too
+	if tree := r.Tree("", TreeOptions{Depth: 5, MaxNodes: 1}); !tree.Truncated || tree.NodeCount != 0 || tree.Root.Children != nil {
+		t.Errorf("expected nothing listed within a budget of 1, got %d nodes (truncated %v)", tree.NodeCount, tree.Truncated)
+	}
+	if tree := r.Tree("", TreeOptions{Depth: 5, MaxNodes: 6}); tree.Truncated || tree.NodeCount != 6 {
+		t.Errorf("expected the whole catalog within a budget of 6, got %d nodes (truncated %v)", tree.NodeCount, tree.Truncated)
+	}
+	if tree := r.Tree("missing", TreeOptions{Depth: 2}); !tree.Root.Virtual || tree.Root.Children != nil {
+		t.Errorf("expected an empty virtual root for an unknown path, got %+v", tree.Root)
+	}
+}
+
+func TestTreeListsChildrenInOrder(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("rates/usd-ois", "USD OIS", "", NodeStatusActive, true))
+	r.Register(makeNode("rates/usd/libor", "USD Libor", "", NodeStatusActive, true))
+
+	children := r.Tree("rates", TreeOptions{Depth: 1}).Root.Children
+	if len(children) != 2 || children[0].Path != "rates/usd" || !children[0].Virtual || children[1].Path != "rates/usd-ois" {
+		t.Errorf("expected virtual rates/usd before rates/usd-ois, got %+v %+v", children[0], children[1])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // SourceType represents supported data source types
@@ -41,6 +42,22 @@ const (
 	NodeStatusArchived      NodeStatus = "archived"       // No longer resolvable
 )
 
+// NodeStatuses returns the known lifecycle states in lifecycle order
+func NodeStatuses() []NodeStatus {
+	return []NodeStatus{NodeStatusDraft, NodeStatusPendingReview, NodeStatusApproved,
+		NodeStatusActive, NodeStatusDeprecated, NodeStatusArchived}
+}
+
+// IsValid returns true if the status is one of the known lifecycle states
+func (s NodeStatus) IsValid() bool {
+	switch s {
+	case NodeStatusDraft, NodeStatusPendingReview, NodeStatusApproved,
+		NodeStatusActive, NodeStatusDeprecated, NodeStatusArchived:
+		return true
+	}
+	return false
+}
+
 // Ownership represents ownership for a catalog node with data governance roles
 type Ownership struct {
 	// Simplified ownership fields
@@ -49,9 +66,9 @@ type Ownership struct {
 	SupportChannel   *string `json:"support_channel,omitempty" yaml:"support_channel,omitempty"`
 
 	// Formal data governance roles (BCBS 239 / DAMA style)
-	ADOP     *string `json:"adop,omitempty" yaml:"adop,omitempty"`         // Accountable Data Owner/Principal
-	ADS      *string `json:"ads,omitempty" yaml:"ads,omitempty"`           // Accountable Data Steward
-	ADAL     *string `json:"adal,omitempty" yaml:"adal,omitempty"`         // Accountable Data Access Lead
+	ADOP     *string `json:"adop,omitempty" yaml:"adop,omitempty"`           // Accountable Data Owner/Principal
+	ADS      *string `json:"ads,omitempty" yaml:"ads,omitempty"`             // Accountable Data Steward
+	ADAL     *string `json:"adal,omitempty" yaml:"adal,omitempty"`           // Accountable Data Access Lead
 	ADOPName *string `json:"adop_name,omitempty" yaml:"adop_name,omitempty"` // Human-readable names
 	ADSName  *string `json:"ads_name,omitempty" yaml:"ads_name,omitempty"`
 	ADALName *string `json:"adal_name,omitempty" yaml:"adal_name,omitempty"`
@@ -104,30 +121,32 @@ func firstNonNil(ptrs ...*string) *string {
 
 // QueryCacheConfig represents cache configuration for expensive queries
 type QueryCacheConfig struct {
-	Enabled                 bool `json:"enabled" yaml:"enabled"`
-	TTLSeconds              int  `json:"ttl_seconds" yaml:"ttl_seconds"`
-	RefreshIntervalSeconds  int  `json:"refresh_interval_seconds" yaml:"refresh_interval_seconds"`
-	RefreshOnStartup        bool `json:"refresh_on_startup" yaml:"refresh_on_startup"`
+	Enabled                bool `json:"enabled" yaml:"enabled"`
+	TTLSeconds             int  `json:"ttl_seconds" yaml:"ttl_seconds"`
+	RefreshIntervalSeconds int  `json:"refresh_interval_seconds" yaml:"refresh_interval_seconds"`
+	RefreshOnStartup       bool `json:"refresh_on_startup" yaml:"refresh_on_startup"`
 }
 
 // SourceBinding represents binding to an actual data source
 type SourceBinding struct {
-	SourceType        SourceType                 `json:"type" yaml:"type"`
-	Config            map[string]interface{}     `json:"config" yaml:"config"`
-	AllowedOperations []string                   `json:"allowed_operations,omitempty" yaml:"allowed_operations,omitempty"`
-	Schema            map[string]interface{}     `json:"schema,omitempty" yaml:"schema,omitempty"`
-	ReadOnly          bool                       `json:"read_only" yaml:"read_only"`
-	Cache             *QueryCacheConfig          `json:"cache,omitempty" yaml:"cache,omitempty"`
+	SourceType        SourceType             `json:"type" yaml:"type"`
+	Config            map[string]interface{} `json:"config" yaml:"config"`
+	AllowedOperations []string               `json:"allowed_operations,omitempty" yaml:"allowed_operations,omitempty"`
+	Schema            map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+	ReadOnly          bool                   `json:"read_only" yaml:"read_only"`
+	Cache             *QueryCacheConfig      `json:"cache,omitempty" yaml:"cache,omitempty"`
+
+	staticRows []map[string]interface{} // Read from a static binding's data_file; see StaticRows
 }
 
 // Fingerprint returns SHA-256 fingerprint of the binding contract
 func (sb *SourceBinding) Fingerprint() string {
 	data := map[string]interface{}{
-		"source_type":         string(sb.SourceType),
-		"config":              sb.Config,
-		"allowed_operations":  sb.AllowedOperations,
-		"schema":              sb.Schema,
-		"read_only":           sb.ReadOnly,
+		"source_type":        string(sb.SourceType),
+		"config":             sb.Config,
+		"allowed_operations": sb.AllowedOperations,
+		"schema":             sb.Schema,
+		"read_only":          sb.ReadOnly,
 	}
 	raw, _ := json.Marshal(data)
 	hash := sha256.Sum256(raw)
@@ -147,6 +166,51 @@ type AccessPolicy struct {
 	DenialMessage            *string  `json:"denial_message,omitempty" yaml:"denial_message,omitempty"`
 	AllowedRoles             []string `json:"allowed_roles,omitempty" yaml:"allowed_roles,omitempty"`
 	AllowedHours             *[2]int  `json:"allowed_hours,omitempty" yaml:"allowed_hours,omitempty"` // [start_hour, end_hour] in UTC
+
+	blocked []*regexp.Regexp // BlockedPatterns compiled by CompilePatterns
+}
+
+// CompilePatterns compiles BlockedPatterns (case-insensitive) so Validate does
+// not recompile them on every request. It must be called again if
+// BlockedPatterns changes.
+func (ap *AccessPolicy) CompilePatterns() error {
+	compiled, err := compileBlockedPatterns(ap.BlockedPatterns)
+	if err != nil {
+		return err
+	}
+	ap.blocked = compiled
+	return nil
+}
+
+func compileBlockedPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("blocked_patterns[%d] %q is not a valid regular expression: %w", i, pattern, err)
+		}
+		compiled[i] = re
+	}
+	return compiled, nil
+}
+
+// blockedBy reports whether path matches a blocked pattern. Policies built
+// without CompilePatterns compile on each call, and an invalid pattern blocks
+// rather than allowing everything.
+func (ap *AccessPolicy) blockedBy(path string) bool {
+	compiled := ap.blocked
+	if len(compiled) != len(ap.BlockedPatterns) {
+		var err error
+		if compiled, err = compileBlockedPatterns(ap.BlockedPatterns); err != nil {
+			return true
+		}
+	}
+	for _, re := range compiled {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
 }
 
 // EstimateRows estimates the number of rows that would be returned based on segment values
@@ -168,29 +232,126 @@ func (ap *AccessPolicy) EstimateRows(segments []string) int {
 	return baseCount * multiplier
 }
 
+// CheckRoles reports whether a caller holding roles may use the node: always
+// when AllowedRoles is empty, else when the caller holds at least one of
+// them. The denial message is DenialMessage when set, so the required roles
+// are not disclosed.
+func (ap *AccessPolicy) CheckRoles(roles []string) (bool, *string) {
+	if len(ap.AllowedRoles) == 0 {
+		return true, nil
+	}
+	for _, allowed := range ap.AllowedRoles {
+		for _, role := range roles {
+			if role == allowed {
+				return true, nil
+			}
+		}
+	}
+	msg := fmt.Sprintf("Access policy requires one of the roles: %s", strings.Join(ap.AllowedRoles, ", "))
+	if ap.DenialMessage != nil {
+		msg = *ap.DenialMessage
+	}
+	return false, &msg
+}
+
+// InAllowedHours reports whether hour (0-23, UTC) falls in AllowedHours.
+// The window runs from the start hour up to, not including, the end hour and
+// wraps midnight when end is before start, so [22, 6] allows 22:00-05:59.
+// Equal hours, or no window, allow the whole day.
+func (ap *AccessPolicy) InAllowedHours(hour int) bool {
+	if ap.AllowedHours == nil {
+		return true
+	}
+	start, end := ap.AllowedHours[0], ap.AllowedHours[1]
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return hour >= start && hour < end
+	default:
+		return hour >= start || hour < end
+	}
+}
+
+// CheckHours reports whether the node may be used at now. The denial message
+// is DenialMessage when set.
+func (ap *AccessPolicy) CheckHours(now time.Time) (bool, *string) {
+	hour := now.UTC().Hour()
+	if ap.InAllowedHours(hour) {
+		return true, nil
+	}
+	msg := fmt.Sprintf("Access policy allows this data only between %02d:00 and %02d:00 UTC (now %02d:00 UTC)",
+		ap.AllowedHours[0], ap.AllowedHours[1], hour)
+	if ap.DenialMessage != nil {
+		msg = *ap.DenialMessage
+	}
+	return false, &msg
+}
+
+// Access policy rules reported by Evaluate
+const (
+	PolicyRuleBlockedPatterns  = "blocked_patterns"
+	PolicyRuleRequiredSegments = "required_segments"
+	PolicyRuleMinFilters       = "min_filters"
+	PolicyRuleMaxRowsBlock     = "max_rows_block"
+	PolicyRuleMaxRowsWarn      = "max_rows_warn"
+)
+
+// PolicyEvaluation is the outcome of Evaluate. Rule names the rule that
+// denied the query, or max_rows_warn for an allowed query with a warning;
+// it is empty when no rule applied.
+type PolicyEvaluation struct {
+	Allowed       bool    `json:"allowed"`
+	Rule          string  `json:"rule,omitempty"`
+	Message       *string `json:"message,omitempty"`
+	EstimatedRows int     `json:"estimated_rows"`
+}
+
 // Validate validates if a query pattern is allowed
 // Returns (is_allowed, error_message, estimated_rows)
 func (ap *AccessPolicy) Validate(segments []string) (bool, *string, int) {
+	e := ap.Evaluate(segments)
+	return e.Allowed, e.Message, e.EstimatedRows
+}
+
+// Evaluate is Validate, also naming the rule that decided the outcome
+func (ap *AccessPolicy) Evaluate(segments []string) PolicyEvaluation {
+	return ap.evaluate(segments, ap.EstimateRows(segments))
+}
+
+// EvaluateExpanded is Evaluate for a moniker whose ALL segments were
+// expanded into the fully-specified segment lists of expanded. Rows are
+// estimated as the sum of their estimates, the cardinality the expansion
+// actually has, rather than with cardinality_multipliers; the other rules
+// apply to segments as written.
+func (ap *AccessPolicy) EvaluateExpanded(segments []string, expanded [][]string) PolicyEvaluation {
+	estimatedRows := 0
+	for _, e := range expanded {
+		estimatedRows += ap.EstimateRows(e)
+	}
+	return ap.evaluate(segments, estimatedRows)
+}
+
+// evaluate checks the rules of the policy for segments and an estimate
+func (ap *AccessPolicy) evaluate(segments []string, estimatedRows int) PolicyEvaluation {
 	path := strings.Join(segments, "/")
-	estimatedRows := ap.EstimateRows(segments)
+	deny := func(rule, msg string) PolicyEvaluation {
+		return PolicyEvaluation{Rule: rule, Message: &msg, EstimatedRows: estimatedRows}
+	}
 
 	// Check blocked patterns
-	for _, pattern := range ap.BlockedPatterns {
-		matched, _ := regexp.MatchString("(?i)"+pattern, path)
-		if matched {
-			msg := fmt.Sprintf("Query pattern '%s' is blocked by access policy", path)
-			if ap.DenialMessage != nil {
-				msg = *ap.DenialMessage
-			}
-			return false, &msg, estimatedRows
+	if ap.blockedBy(path) {
+		msg := fmt.Sprintf("Query pattern '%s' is blocked by access policy", path)
+		if ap.DenialMessage != nil {
+			msg = *ap.DenialMessage
 		}
+		return deny(PolicyRuleBlockedPatterns, msg)
 	}
 
 	// Check required segments
 	for _, idx := range ap.RequiredSegments {
 		if idx < len(segments) && strings.ToUpper(segments[idx]) == "ALL" {
-			msg := fmt.Sprintf("Access policy requires segment %d to be specified (cannot use ALL)", idx)
-			return false, &msg, estimatedRows
+			return deny(PolicyRuleRequiredSegments, fmt.Sprintf("Access policy requires segment %d to be specified (cannot use ALL)", idx))
 		}
 	}
 
@@ -203,9 +364,8 @@ func (ap *AccessPolicy) Validate(segments []string) (bool, *string, int) {
 			}
 		}
 		if nonAllCount < ap.MinFilters {
-			msg := fmt.Sprintf("Access policy requires at least %d specific filters, but only %d provided",
-				ap.MinFilters, nonAllCount)
-			return false, &msg, estimatedRows
+			return deny(PolicyRuleMinFilters, fmt.Sprintf("Access policy requires at least %d specific filters, but only %d provided",
+				ap.MinFilters, nonAllCount))
 		}
 	}
 
@@ -216,17 +376,16 @@ func (ap *AccessPolicy) Validate(segments []string) (bool, *string, int) {
 		if ap.DenialMessage != nil {
 			msg = *ap.DenialMessage
 		}
-		return false, &msg, estimatedRows
+		return deny(PolicyRuleMaxRowsBlock, msg)
 	}
 
 	// Warning for large queries (but allowed)
-	var warning *string
 	if ap.MaxRowsWarn != nil && estimatedRows > *ap.MaxRowsWarn {
 		w := fmt.Sprintf("Large query: estimated %d rows", estimatedRows)
-		warning = &w
+		return PolicyEvaluation{Allowed: true, Rule: PolicyRuleMaxRowsWarn, Message: &w, EstimatedRows: estimatedRows}
 	}
 
-	return true, warning, estimatedRows
+	return PolicyEvaluation{Allowed: true, EstimatedRows: estimatedRows}
 }
 
 // DataQuality represents data quality information for a catalog node
@@ -240,10 +399,10 @@ type DataQuality struct {
 
 // SLA represents service level agreement for a data source
 type SLA struct {
-	Freshness          *string `json:"freshness,omitempty" yaml:"freshness,omitempty"`
-	Availability       *string `json:"availability,omitempty" yaml:"availability,omitempty"`
-	SupportHours       *string `json:"support_hours,omitempty" yaml:"support_hours,omitempty"`
-	EscalationContact  *string `json:"escalation_contact,omitempty" yaml:"escalation_contact,omitempty"`
+	Freshness         *string `json:"freshness,omitempty" yaml:"freshness,omitempty"`
+	Availability      *string `json:"availability,omitempty" yaml:"availability,omitempty"`
+	SupportHours      *string `json:"support_hours,omitempty" yaml:"support_hours,omitempty"`
+	EscalationContact *string `json:"escalation_contact,omitempty" yaml:"escalation_contact,omitempty"`
 }
 
 // Freshness represents data freshness information
@@ -282,15 +441,15 @@ type DataSchema struct {
 
 // Documentation represents documentation links for a data source
 type Documentation struct {
-	GlossaryURL        *string           `json:"glossary,omitempty" yaml:"glossary,omitempty"`
-	RunbookURL         *string           `json:"runbook,omitempty" yaml:"runbook,omitempty"`
-	OnboardingURL      *string           `json:"onboarding,omitempty" yaml:"onboarding,omitempty"`
-	DataDictionaryURL  *string           `json:"data_dictionary,omitempty" yaml:"data_dictionary,omitempty"`
-	APIDocsURL         *string           `json:"api_docs,omitempty" yaml:"api_docs,omitempty"`
-	ArchitectureURL    *string           `json:"architecture,omitempty" yaml:"architecture,omitempty"`
-	ChangelogURL       *string           `json:"changelog,omitempty" yaml:"changelog,omitempty"`
-	ContactURL         *string           `json:"contact,omitempty" yaml:"contact,omitempty"`
-	AdditionalLinks    map[string]string `json:"additional,omitempty" yaml:"additional,omitempty"`
+	GlossaryURL       *string           `json:"glossary,omitempty" yaml:"glossary,omitempty"`
+	RunbookURL        *string           `json:"runbook,omitempty" yaml:"runbook,omitempty"`
+	OnboardingURL     *string           `json:"onboarding,omitempty" yaml:"onboarding,omitempty"`
+	DataDictionaryURL *string           `json:"data_dictionary,omitempty" yaml:"data_dictionary,omitempty"`
+	APIDocsURL        *string           `json:"api_docs,omitempty" yaml:"api_docs,omitempty"`
+	ArchitectureURL   *string           `json:"architecture,omitempty" yaml:"architecture,omitempty"`
+	ChangelogURL      *string           `json:"changelog,omitempty" yaml:"changelog,omitempty"`
+	ContactURL        *string           `json:"contact,omitempty" yaml:"contact,omitempty"`
+	AdditionalLinks   map[string]string `json:"additional,omitempty" yaml:"additional,omitempty"`
 }
 
 // ToDict converts documentation to dictionary for API responses
@@ -346,9 +505,9 @@ type AuditEntry struct {
 
 // CatalogNode represents a node in the catalog hierarchy
 type CatalogNode struct {
-	Path        string     `json:"path" yaml:"-"`
-	DisplayName string     `json:"display_name" yaml:"display_name"`
-	Description string     `json:"description" yaml:"description"`
+	Path        string `json:"path" yaml:"-"`
+	DisplayName string `json:"display_name" yaml:"display_name"`
+	Description string `json:"description" yaml:"description"`
 
 	// Asset class (rates, credit, mortgages, macro, risk, fx, equities, commodities, em, fixed.income)
 	AssetClass string `json:"asset_class,omitempty" yaml:"asset_class,omitempty"`
@@ -375,9 +534,9 @@ type CatalogNode struct {
 	SourceBinding *SourceBinding `json:"source_binding,omitempty" yaml:"source_binding,omitempty"`
 
 	// Data governance
-	DataQuality *DataQuality   `json:"data_quality,omitempty" yaml:"data_quality,omitempty"`
-	SLA         *SLA           `json:"sla,omitempty" yaml:"sla,omitempty"`
-	Freshness   *Freshness     `json:"freshness,omitempty" yaml:"freshness,omitempty"`
+	DataQuality *DataQuality `json:"data_quality,omitempty" yaml:"data_quality,omitempty"`
+	SLA         *SLA         `json:"sla,omitempty" yaml:"sla,omitempty"`
+	Freshness   *Freshness   `json:"freshness,omitempty" yaml:"freshness,omitempty"`
 
 	// Machine-readable schema for AI agent discoverability
 	DataSchema *DataSchema `json:"schema,omitempty" yaml:"schema,omitempty"`
@@ -388,7 +547,7 @@ type CatalogNode struct {
 	// Documentation links
 	Documentation *Documentation `json:"documentation,omitempty" yaml:"documentation,omitempty"`
 
-	// Data classification
+	// Data classification (empty inherits from the nearest classified ancestor)
 	Classification string `json:"classification" yaml:"classification"`
 
 	// Tags for searchability
@@ -398,12 +557,12 @@ type CatalogNode struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 
 	// Governance lifecycle
-	Status              NodeStatus `json:"status" yaml:"status"`
-	CreatedAt           *string    `json:"created_at,omitempty" yaml:"created_at,omitempty"`
-	UpdatedAt           *string    `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
-	CreatedBy           *string    `json:"created_by,omitempty" yaml:"created_by,omitempty"`
-	ApprovedBy          *string    `json:"approved_by,omitempty" yaml:"approved_by,omitempty"`
-	DeprecationMessage  *string    `json:"deprecation_message,omitempty" yaml:"deprecation_message,omitempty"`
+	Status             NodeStatus `json:"status" yaml:"status"`
+	CreatedAt          *string    `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt          *string    `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	CreatedBy          *string    `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	ApprovedBy         *string    `json:"approved_by,omitempty" yaml:"approved_by,omitempty"`
+	DeprecationMessage *string    `json:"deprecation_message,omitempty" yaml:"deprecation_message,omitempty"`
 
 	// Successor-based migration
 	Successor         *string `json:"successor,omitempty" yaml:"successor,omitempty"`
@@ -412,6 +571,22 @@ type CatalogNode struct {
 
 	// Is this a leaf node (actual data) or category (contains children)?
 	IsLeaf bool `json:"is_leaf" yaml:"is_leaf"`
+
+	// SourceFile is the catalog file that defined the node, for error reporting
+	SourceFile string `json:"-" yaml:"-"`
+	SourceLine int    `json:"-" yaml:"-"` // Line of the node's path key, when loaded from YAML
+
+	// UnknownFields lists keys in the node's YAML definition that were ignored
+	UnknownFields []UnknownField `json:"-" yaml:"-"`
+}
+
+// DefaultClassification applies when no node in the hierarchy sets a classification
+const DefaultClassification = "internal"
+
+// TagWithSource is an effective tag together with the path that defines it
+type TagWithSource struct {
+	Tag       string `json:"tag"`
+	DefinedAt string `json:"defined_at"`
 }
 
 // ResolvedOwnership represents ownership resolved through the hierarchy, with provenance
@@ -427,19 +602,19 @@ type ResolvedOwnership struct {
 	SupportChannelSource *string `json:"support_channel_source,omitempty"`
 
 	// Formal governance roles with provenance
-	ADOP       *string `json:"adop,omitempty"`
-	ADOPSource *string `json:"adop_source,omitempty"`
-	ADOPName   *string `json:"adop_name,omitempty"`
+	ADOP           *string `json:"adop,omitempty"`
+	ADOPSource     *string `json:"adop_source,omitempty"`
+	ADOPName       *string `json:"adop_name,omitempty"`
 	ADOPNameSource *string `json:"adop_name_source,omitempty"`
 
-	ADS        *string `json:"ads,omitempty"`
-	ADSSource  *string `json:"ads_source,omitempty"`
-	ADSName    *string `json:"ads_name,omitempty"`
+	ADS           *string `json:"ads,omitempty"`
+	ADSSource     *string `json:"ads_source,omitempty"`
+	ADSName       *string `json:"ads_name,omitempty"`
 	ADSNameSource *string `json:"ads_name_source,omitempty"`
 
-	ADAL       *string `json:"adal,omitempty"`
-	ADALSource *string `json:"adal_source,omitempty"`
-	ADALName   *string `json:"adal_name,omitempty"`
+	ADAL           *string `json:"adal,omitempty"`
+	ADALSource     *string `json:"adal_source,omitempty"`
+	ADALName       *string `json:"adal_name,omitempty"`
 	ADALNameSource *string `json:"adal_name_source,omitempty"`
 
 	UI       *string `json:"ui,omitempty"`
@@ -482,3 +657,29 @@ func (ro *ResolvedOwnership) GovernanceRoles() map[string]map[string]*string {
 		},
 	}
 }
+
+// ResolvedSLA represents an SLA merged field-by-field through the hierarchy, with provenance
+type ResolvedSLA struct {
+	Freshness               *string `json:"freshness,omitempty"`
+	FreshnessSource         *string `json:"freshness_source,omitempty"`
+	Availability            *string `json:"availability,omitempty"`
+	AvailabilitySource      *string `json:"availability_source,omitempty"`
+	SupportHours            *string `json:"support_hours,omitempty"`
+	SupportHoursSource      *string `json:"support_hours_source,omitempty"`
+	EscalationContact       *string `json:"escalation_contact,omitempty"`
+	EscalationContactSource *string `json:"escalation_contact_source,omitempty"`
+}
+
+// ResolvedDataQuality represents data quality merged field-by-field through the hierarchy, with provenance
+type ResolvedDataQuality struct {
+	DQOwner               *string  `json:"dq_owner,omitempty"`
+	DQOwnerSource         *string  `json:"dq_owner_source,omitempty"`
+	QualityScore          *float64 `json:"quality_score,omitempty"`
+	QualityScoreSource    *string  `json:"quality_score_source,omitempty"`
+	ValidationRules       []string `json:"validation_rules,omitempty"`
+	ValidationRulesSource *string  `json:"validation_rules_source,omitempty"`
+	KnownIssues           []string `json:"known_issues,omitempty"`
+	KnownIssuesSource     *string  `json:"known_issues_source,omitempty"`
+	LastValidated         *string  `json:"last_validated,omitempty"`
+	LastValidatedSource   *string  `json:"last_validated_source,omitempty"`
+}
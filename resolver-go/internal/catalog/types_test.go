@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccessPolicyBlockedPatterns(t *testing.T) {
+	ap := &AccessPolicy{BlockedPatterns: []string{"^all/", "/all$"}}
+	if err := ap.CompilePatterns(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	tests := []struct {
+		segments []string
+		allowed  bool
+	}{
+		{[]string{"ALL", "USD"}, false}, // Case-insensitive
+		{[]string{"EUR", "ALL"}, false},
+		{[]string{"EUR", "USD"}, true},
+	}
+	for _, tt := range tests {
+		if allowed, _, _ := ap.Validate(tt.segments); allowed != tt.allowed {
+			t.Errorf("Validate(%v) allowed = %v, want %v", tt.segments, allowed, tt.allowed)
+		}
+	}
+}
+
+func TestAccessPolicyUncompiledPatterns(t *testing.T) {
+	ap := &AccessPolicy{BlockedPatterns: []string{"^ALL/"}}
+	if allowed, _, _ := ap.Validate([]string{"ALL", "USD"}); allowed {
+		t.Error("patterns should apply without CompilePatterns")
+	}
+
+	broken := &AccessPolicy{BlockedPatterns: []string{"("}}
+	if err := broken.CompilePatterns(); err == nil {
+		t.Error("expected compile error")
+	}
+	if allowed, _, _ := broken.Validate([]string{"EUR", "USD"}); allowed {
+		t.Error("an invalid pattern should block rather than allow everything")
+	}
+}
+
+var benchPatterns = []string{"^ALL/", "/ALL$", "^ALL/ALL", "(?:^|/)TEST(?:/|$)", `^\d{4}/ALL`}
+
+func benchmarkValidate(b *testing.B, compile bool) {
+	ap := &AccessPolicy{BlockedPatterns: benchPatterns}
+	if compile {
+		if err := ap.CompilePatterns(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	segments := []string{"EQUITY", "US", "AAPL", "20240102"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ap.Validate(segments)
+	}
+}
+
+// BenchmarkValidate_Precompiled is the per-resolve cost with patterns compiled at load
+func BenchmarkValidate_Precompiled(b *testing.B) { benchmarkValidate(b, true) }
+
+// BenchmarkValidate_CompileEachCall is the per-resolve cost of compiling on every request
+func BenchmarkValidate_CompileEachCall(b *testing.B) { benchmarkValidate(b, false) }
+
+func TestAccessPolicyCheckRoles(t *testing.T) {
+	open := &AccessPolicy{}
+	if allowed, _ := open.CheckRoles(nil); !allowed {
+		t.Error("a policy without allowed_roles should allow every caller")
+	}
+
+	ap := &AccessPolicy{AllowedRoles: []string{"trader", "risk"}}
+	if allowed, _ := ap.CheckRoles([]string{"ops", "risk"}); !allowed {
+		t.Error("a caller holding one of the roles should be allowed")
+	}
+	allowed, msg := ap.CheckRoles([]string{"ops"})
+	if allowed || msg == nil || *msg != "Access policy requires one of the roles: trader, risk" {
+		t.Errorf("expected a denial naming the roles, got %v %v", allowed, msg)
+	}
+
+	ap.DenialMessage = strPtr("Ask the desk for access")
+	if _, msg := ap.CheckRoles(nil); msg == nil || *msg != "Ask the desk for access" {
+		t.Errorf("expected the configured denial message, got %v", msg)
+	}
+}
+
+func TestAccessPolicyAllowedHours(t *testing.T) {
+	tests := []struct {
+		window *[2]int
+		hour   int
+		want   bool
+	}{
+		{nil, 3, true},
+		{&[2]int{8, 18}, 8, true},
+		{&[2]int{8, 18}, 17, true},
+		{&[2]int{8, 18}, 18, false},
+		{&[2]int{8, 18}, 7, false},
+		{&[2]int{22, 6}, 23, true},
+		{&[2]int{22, 6}, 0, true},
+		{&[2]int{22, 6}, 5, true},
+		{&[2]int{22, 6}, 6, false},
+		{&[2]int{22, 6}, 12, false},
+		{&[2]int{9, 9}, 3, true},
+	}
+	for _, tt := range tests {
+		ap := &AccessPolicy{AllowedHours: tt.window}
+		if got := ap.InAllowedHours(tt.hour); got != tt.want {
+			t.Errorf("window %v hour %d: got %v, want %v", tt.window, tt.hour, got, tt.want)
+		}
+	}
+
+	ap := &AccessPolicy{AllowedHours: &[2]int{22, 6}}
+	allowed, msg := ap.CheckHours(time.Date(2026, 3, 31, 12, 30, 0, 0, time.UTC))
+	want := "Access policy allows this data only between 22:00 and 06:00 UTC (now 12:00 UTC)"
+	if allowed || msg == nil || *msg != want {
+		t.Errorf("expected %q, got %v %v", want, allowed, msg)
+	}
+}
+
+func TestAccessPolicyEvaluateNamesRule(t *testing.T) {
+	block, warn := 50000, 500
+	ap := &AccessPolicy{RequiredSegments: []int{0}, MaxRowsBlock: &block, MaxRowsWarn: &warn}
+	tests := []struct {
+		segments []string
+		allowed  bool
+		rule     string
+	}{
+		{[]string{"ALL", "US"}, false, PolicyRuleRequiredSegments},
+		{[]string{"EQUITY", "US"}, true, ""},
+		{[]string{"EQUITY", "ALL"}, true, PolicyRuleMaxRowsWarn},
+		{[]string{"EQUITY", "ALL", "ALL"}, false, PolicyRuleMaxRowsBlock},
+	}
+	for _, tt := range tests {
+		e := ap.Evaluate(tt.segments)
+		if e.Allowed != tt.allowed || e.Rule != tt.rule {
+			t.Errorf("%v: expected allowed=%v rule=%q, got %+v", tt.segments, tt.allowed, tt.rule, e)
+		}
+	}
+}
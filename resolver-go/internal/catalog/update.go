@@ -0,0 +1,260 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// editableFields are the node fields PUT and PATCH /catalog/{path} change,
+// by their catalog key
+var editableFields = map[string]bool{
+	"display_name":   true,
+	"description":    true,
+	"tags":           true,
+	"ownership":      true,
+	"documentation":  true,
+	"classification": true,
+	"access_policy":  true,
+	"source_binding": true,
+}
+
+// NodeNotFoundError is an update of a path that is not registered
+type NodeNotFoundError struct {
+	Path string
+}
+
+func (e *NodeNotFoundError) Error() string {
+	return fmt.Sprintf("catalog path '%s' is not registered", e.Path)
+}
+
+// BindingChangeError is an edit that changes a node's source binding
+// contract without allowing it
+type BindingChangeError struct {
+	Path           string
+	OldFingerprint *string
+	NewFingerprint *string
+}
+
+func (e *BindingChangeError) Error() string {
+	return fmt.Sprintf("the edit changes the source binding contract of '%s'", e.Path)
+}
+
+// SecretBindingChangeError is an edit that changes a source binding
+// referencing secret:// values. Such bindings are only changed in the
+// catalog file: pointing one at another host would send it the secrets.
+type SecretBindingChangeError struct {
+	Path string
+}
+
+func (e *SecretBindingChangeError) Error() string {
+	return fmt.Sprintf("the source binding of '%s' references secrets and can only be changed in the catalog file", e.Path)
+}
+
+// NodeEdit is a change to the editable fields of a node, parsed and checked
+// as the loader checks a catalog entry
+type NodeEdit struct {
+	fields map[string]bool // Catalog keys the edit sets
+	node   *CatalogNode    // The edited values
+}
+
+// EditableFields returns the catalog keys a NodeEdit may set, sorted
+func EditableFields() []string {
+	keys := make([]string, 0, len(editableFields))
+	for k := range editableFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseNodeEdit parses a JSON object of editable fields of the node at path.
+// With partial (PATCH) the fields it omits are left unchanged; otherwise
+// (PUT) every editable field is set, and omitted ones are cleared.
+func ParseNodeEdit(path string, data []byte, partial bool) (*NodeEdit, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse node JSON: %s", describeJSONError(data, "$", err))
+	}
+	var unknown []string
+	for k := range raw {
+		if !editableFields[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s cannot be edited; editable fields are %s",
+			strings.Join(unknown, ", "), strings.Join(EditableFields(), ", "))
+	}
+
+	var nodeJSON CatalogNodeYAML
+	if err := json.Unmarshal(data, &nodeJSON); err != nil {
+		return nil, fmt.Errorf("parse node JSON: %s", describeJSONError(data, "$", err))
+	}
+	if err := validateNodeYAML(path, &nodeJSON); err != nil {
+		return nil, err
+	}
+	node := convertYAMLToNode(path, &nodeJSON)
+	if err := validateSourceConfigs([]*CatalogNode{node}); err != nil {
+		return nil, err
+	}
+
+	fields := editableFields
+	if partial {
+		fields = make(map[string]bool, len(raw))
+		for k := range raw {
+			fields[k] = true
+		}
+	}
+	return &NodeEdit{fields: fields, node: node}, nil
+}
+
+// Apply sets the fields of the edit on node, leaving node unchanged on error.
+// A source binding with the fingerprint of node's is kept as it is; with
+// allowBindingChange false, one that changes the fingerprint fails with
+// *BindingChangeError. A change to or from a binding that references
+// secret:// values fails with *SecretBindingChangeError either way. A new
+// static binding's data_file must be inside the directory of node's catalog
+// file, and is read from there.
+func (e *NodeEdit) Apply(node *CatalogNode, allowBindingChange bool) error {
+	oldFP, newFP := bindingFingerprint(node), bindingFingerprint(e.node)
+	bindingChanged := e.fields["source_binding"] && !equalStringPtr(oldFP, newFP)
+	if bindingChanged {
+		if referencesSecret(node.SourceBinding) || referencesSecret(e.node.SourceBinding) {
+			return &SecretBindingChangeError{Path: node.Path}
+		}
+		if !allowBindingChange {
+			return &BindingChangeError{Path: node.Path, OldFingerprint: oldFP, NewFingerprint: newFP}
+		}
+		if err := checkEditedDataFile(e.node.SourceBinding, node.SourceFile); err != nil {
+			return err
+		}
+		loaded := &CatalogNode{Path: node.Path, SourceFile: node.SourceFile, SourceBinding: e.node.SourceBinding}
+		if err := loadStaticData([]*CatalogNode{loaded}, os.ReadFile); err != nil {
+			return err
+		}
+	}
+	for field := range e.fields {
+		switch field {
+		case "display_name":
+			node.DisplayName = e.node.DisplayName
+		case "description":
+			node.Description = e.node.Description
+		case "tags":
+			node.Tags = e.node.Tags
+		case "ownership":
+			node.Ownership = e.node.Ownership
+		case "documentation":
+			node.Documentation = e.node.Documentation
+		case "classification":
+			node.Classification = e.node.Classification
+		case "access_policy":
+			node.AccessPolicy = e.node.AccessPolicy
+		case "source_binding":
+			if bindingChanged {
+				node.SourceBinding = e.node.SourceBinding
+			}
+		}
+	}
+	return nil
+}
+
+// referencesSecret reports whether any config value of sb, at any depth, is
+// a secret:// reference
+func referencesSecret(sb *SourceBinding) bool {
+	if sb == nil {
+		return false
+	}
+	var walk func(v interface{}) bool
+	walk = func(v interface{}) bool {
+		switch v := v.(type) {
+		case string:
+			return strings.HasPrefix(v, "secret://")
+		case map[string]interface{}:
+			for _, item := range v {
+				if walk(item) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				if walk(item) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(sb.Config)
+}
+
+// Update changes the node at path by applying mutate to a copy of it and
+// swapping the copy in, failing with *NodeNotFoundError when the path is not
+// registered. Updates hold the registry lock, so concurrent ones apply one
+// after another, each to the result of the last. mutate receives a shallow
+// copy: it must replace, not modify in place, the pointer, slice and map
+// fields it changes. An error from mutate leaves the node unchanged.
+//
+// UpdatedAt is set, and each changed field recorded as an "updated" audit
+// entry by actor with its old and new JSON values.
+func (r *Registry) Update(path, actor string, mutate func(*CatalogNode) error) (*CatalogNode, error) {
+	return r.update(path, mutate, func(_, _ *CatalogNode, change *NodeChange) {
+		for _, c := range change.Changes {
+			details := "field: " + c.Field
+			r.appendAudit(newAuditEntry(path, "updated", actor, auditValue(c.Old), auditValue(c.New), &details))
+		}
+	})
+}
+
+// update is Update with the audit entries of a change left to record, which
+// is called with the lock held
+func (r *Registry) update(path string, mutate func(*CatalogNode) error, record func(current, updated *CatalogNode, change *NodeChange)) (*CatalogNode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.load()
+	current, ok := old.nodes[path]
+	if !ok {
+		return nil, &NodeNotFoundError{Path: path}
+	}
+	updated := *current
+	if err := mutate(&updated); err != nil {
+		return nil, err
+	}
+	change := diffNode(current, &updated)
+	if change == nil {
+		return current, nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	updated.UpdatedAt = &now
+
+	next := old.clone()
+	next.put(&updated)
+	r.publish(next)
+
+	record(current, &updated, change)
+	kind := ChangeUpdated
+	if current.Status != updated.Status {
+		kind = ChangeStatusChanged
+	}
+	r.watchers.emit(ChangeEvent{Kind: kind, Path: path, OldStatus: current.Status, NewStatus: updated.Status})
+	return &updated, nil
+}
+
+// auditValue renders a field value for an audit entry as JSON, or nil when
+// the field is unset
+func auditValue(v interface{}) *string {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
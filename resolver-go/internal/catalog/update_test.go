@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestUpdateSerializesConcurrentEdits(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("prices/equity", "Equity Prices", "", NodeStatusActive, true))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := r.Update("prices/equity", "alice", func(node *CatalogNode) error {
+				node.Tags = append(append([]string{}, node.Tags...), fmt.Sprintf("tag%d", i))
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	node := r.Get("prices/equity")
+	if len(node.Tags) != 50 {
+		t.Errorf("expected every concurrent edit to apply, got %d tags", len(node.Tags))
+	}
+	if node.UpdatedAt == nil {
+		t.Error("expected UpdatedAt to be set")
+	}
+	if entries := r.AuditEntries("prices/equity", 0, nil); len(entries) != 51 {
+		t.Errorf("expected 50 updated entries after the registration, got %d", len(entries))
+	}
+}
+
+func TestUpdateRecordsChangedFields(t *testing.T) {
+	r := NewRegistry()
+	original := makeNode("prices/equity", "Equity Prices", "Typo'd", NodeStatusActive, true)
+	r.Register(original)
+
+	edit, err := ParseNodeEdit("prices/equity", []byte(`{"description": "Stock prices", "tags": ["equities"]}`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := r.Update("prices/equity", "alice", func(node *CatalogNode) error {
+		return edit.Apply(node, false)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Description != "Stock prices" || updated.DisplayName != "Equity Prices" || original.Description != "Typo'd" {
+		t.Errorf("expected a patched copy, got %+v (original %+v)", updated, original)
+	}
+
+	changes := make(map[string][2]string)
+	for _, e := range r.AuditEntries("prices/equity", 2, nil) {
+		if e.Action != "updated" || e.Actor != "alice" || e.NewValue == nil {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+		old := ""
+		if e.OldValue != nil {
+			old = *e.OldValue
+		}
+		changes[*e.Details] = [2]string{old, *e.NewValue}
+	}
+	if changes["field: description"] != [2]string{`"Typo'd"`, `"Stock prices"`} || changes["field: tags"] != [2]string{"", `["equities"]`} {
+		t.Errorf("unexpected per-field entries %v", changes)
+	}
+
+	if _, err := r.Update("prices/missing", "alice", func(*CatalogNode) error { return nil }); err == nil {
+		t.Error("expected an update of an unregistered path to fail")
+	}
+}
+
+func TestNodeEditGuardsBindingChanges(t *testing.T) {
+	r := NewRegistry()
+	node := makeNode("prices/fx", "FX", "", NodeStatusActive, true)
+	node.SourceBinding = &SourceBinding{SourceType: SourceTypeOracle, Config: map[string]interface{}{"dsn": "oracle://localhost/fx"}, ReadOnly: true}
+	r.Register(node)
+	apply := func(body string, partial, allow bool) error {
+		t.Helper()
+		edit, err := ParseNodeEdit("prices/fx", []byte(body), partial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = r.Update("prices/fx", "alice", func(n *CatalogNode) error { return edit.Apply(n, allow) })
+		return err
+	}
+
+	same := `{"display_name": "FX Rates", "source_binding": {"type": "oracle", "config": {"dsn": "oracle://localhost/fx"}}}`
+	if err := apply(same, false, false); err != nil {
+		t.Errorf("expected a PUT that keeps the binding to pass, got %v", err)
+	}
+	moved := `{"source_binding": {"type": "oracle", "config": {"dsn": "oracle://replica/fx"}}}`
+	if _, ok := apply(moved, true, false).(*BindingChangeError); !ok {
+		t.Error("expected a binding change to be refused")
+	}
+	// A PUT without the binding clears it, which is a change too
+	if _, ok := apply(`{"display_name": "FX Rates"}`, false, false).(*BindingChangeError); !ok {
+		t.Error("expected clearing the binding to be refused")
+	}
+	if err := apply(moved, true, true); err != nil {
+		t.Fatalf("expected an allowed binding change to pass, got %v", err)
+	}
+	if dsn := r.Get("prices/fx").SourceBinding.Config["dsn"]; dsn != "oracle://replica/fx" {
+		t.Errorf("expected the new binding, got %v", dsn)
+	}
+
+	if _, err := ParseNodeEdit("prices/fx", []byte(`{"status": "archived"}`), true); err == nil {
+		t.Error("expected status to be rejected as not editable")
+	}
+	if _, err := ParseNodeEdit("prices/fx", []byte(`{"source_binding": {"type": "oracle", "config": {}}}`), true); err == nil {
+		t.Error("expected an invalid source config to be rejected")
+	}
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Checks Validate adds to Lint, which only make sense for the served catalog
+const (
+	LintCheckExpiredSunset = "expired_sunset" // Deprecated node still served past its sunset deadline
+	LintCheckShadowedPath  = "shadowed_path"  // Path equal to another but for case or separator
+)
+
+// ValidationReport is the LintReport of the live catalog with the checks
+// Validate adds, its issues also grouped by severity
+type ValidationReport struct {
+	*LintReport
+	BySeverity map[string][]LintIssue `json:"by_severity"`
+	PathPrefix string                 `json:"path_prefix,omitempty"`
+}
+
+// Validate lints the registered nodes and adds the checks that apply to a
+// served catalog: deprecated nodes past their sunset deadline at now and
+// active leaves without complete ownership (warnings), and paths that only
+// differ in case or in '.' and '/' separators, which shadow each other for
+// anyone typing a moniker (errors). With pathPrefix the report only covers
+// the node at that path and beneath it; the checks still see the whole
+// catalog, so references out of the subtree resolve.
+func (r *Registry) Validate(pathPrefix string, now time.Time) *ValidationReport {
+	snap := r.load()
+	nodes := make([]*CatalogNode, 0, len(snap.nodes))
+	for _, p := range snap.sortedPaths() {
+		nodes = append(nodes, snap.nodes[p])
+	}
+	report := Lint(nodes)
+	add := func(issue LintIssue) {
+		issue.File, issue.Line = snap.nodes[issue.Path].SourceFile, snap.nodes[issue.Path].SourceLine
+		report.Issues = append(report.Issues, issue)
+	}
+
+	lintReg := NewRegistry()
+	lintReg.RegisterMany(nodes)
+	for _, node := range lintReg.ExpiredSunsets(now) {
+		add(LintIssue{Check: LintCheckExpiredSunset, Severity: SeverityWarning, Path: node.Path,
+			Message: fmt.Sprintf("'%s' is still served past its sunset deadline %s", node.Path, *node.SunsetDeadline)})
+	}
+
+	shadows := make(map[string][]string)
+	for _, node := range nodes {
+		key := strings.ToLower(strings.ReplaceAll(node.Path, ".", "/"))
+		shadows[key] = append(shadows[key], node.Path)
+
+		if !node.IsLeaf || node.Status != NodeStatusActive {
+			continue
+		}
+		ro := lintReg.ResolveOwnership(node.Path)
+		if ro.AccountableOwner == nil {
+			continue // Lint reports the missing owner
+		}
+		missing := make([]string, 0, 2)
+		if ro.DataSpecialist == nil {
+			missing = append(missing, "data_specialist")
+		}
+		if ro.SupportChannel == nil {
+			missing = append(missing, "support_channel")
+		}
+		if len(missing) > 0 {
+			add(LintIssue{Check: LintCheckOwnership, Kind: "incomplete_ownership", Severity: SeverityWarning, Path: node.Path,
+				Message: fmt.Sprintf("Active leaf '%s' has no %s, even after inheritance", node.Path, strings.Join(missing, " or "))})
+		}
+	}
+	for _, paths := range shadows {
+		for _, p := range paths[1:] {
+			add(LintIssue{Check: LintCheckShadowedPath, Severity: SeverityError, Path: p,
+				Message: fmt.Sprintf("'%s' differs from '%s' only in case or separators", p, paths[0])})
+		}
+	}
+
+	if pathPrefix != "" {
+		report.Issues = within(report.Issues, func(i LintIssue) string { return i.Path }, pathPrefix)
+		report.UnknownFields = within(report.UnknownFields, func(u UnknownField) string { return u.Path }, pathPrefix)
+		report.SourceConfig = within(report.SourceConfig, func(v SourceConfigViolation) string { return v.Path }, pathPrefix)
+		report.SuccessorIssues = within(report.SuccessorIssues, func(i SuccessorIssue) string { return i.Path }, pathPrefix)
+		report.ReferenceIssues = within(report.ReferenceIssues, func(i ReferenceIssue) string { return i.Path }, pathPrefix)
+		report.SunsetIssues = within(report.SunsetIssues, func(i SunsetIssue) string { return i.Path }, pathPrefix)
+		report.HierarchyIssues = within(report.HierarchyIssues, func(i HierarchyIssue) string { return i.Path }, pathPrefix)
+	}
+	report.finish()
+
+	bySeverity := map[string][]LintIssue{SeverityError: {}, SeverityWarning: {}}
+	for _, issue := range report.Issues {
+		bySeverity[issue.Severity] = append(bySeverity[issue.Severity], issue)
+	}
+	return &ValidationReport{LintReport: report, BySeverity: bySeverity, PathPrefix: pathPrefix}
+}
+
+// within keeps the items whose path is prefix or beneath it
+func within[T any](items []T, path func(T) string, prefix string) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		p := path(item)
+		under := p == prefix
+		for _, a := range ancestorPaths(p) {
+			under = under || a == prefix
+		}
+		if under {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
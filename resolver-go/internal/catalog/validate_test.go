@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateAddsServedCatalogChecks(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&CatalogNode{Path: "prices", Status: NodeStatusActive, Ownership: &Ownership{
+		AccountableOwner: strPtr("team-prices"), DataSpecialist: strPtr("jsmith"),
+	}})
+	r.Register(makeNode("prices/equity", "Equity", "", NodeStatusActive, true))
+	r.Register(makeNode("prices.equity", "Equity again", "", NodeStatusDraft, true))
+	old := makeNode("prices/old", "Old", "", NodeStatusDeprecated, true)
+	old.Successor, old.SunsetDeadline = strPtr("prices/equity"), strPtr("2026-01-01")
+	r.Register(old)
+
+	report := r.Validate("", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	kinds := make(map[string]string)
+	for _, issue := range report.Issues {
+		kinds[issue.Check+"/"+issue.Kind] = issue.Path
+	}
+	if kinds["shadowed_path/"] != "prices/equity" {
+		t.Errorf("expected prices/equity reported as shadowing prices.equity, got %v", kinds)
+	}
+	if kinds["expired_sunset/"] != "prices/old" {
+		t.Errorf("expected the expired sunset of prices/old, got %v", kinds)
+	}
+	if kinds["ownership/incomplete_ownership"] != "prices/equity" {
+		t.Errorf("expected prices/equity to lack a support channel, got %v", kinds)
+	}
+	if report.Valid || len(report.BySeverity[SeverityError]) != report.Errors {
+		t.Errorf("expected the shadowed path to invalidate the catalog, got %+v", report.LintReport)
+	}
+
+	// The successor outside the scope still resolves
+	scoped := r.Validate("prices/old", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if scoped.Count != 1 || scoped.Issues[0].Check != LintCheckExpiredSunset {
+		t.Errorf("expected only the expired sunset in scope, got %+v", scoped.Issues)
+	}
+}
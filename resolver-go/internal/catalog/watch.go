@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"sync"
+)
+
+// watchBufferSize is the per-subscriber event buffer. When it fills up,
+// further events are dropped and counted rather than blocking writers.
+const watchBufferSize = 256
+
+// ChangeKind identifies the kind of registry change
+type ChangeKind string
+
+const (
+	ChangeAdded         ChangeKind = "added"
+	ChangeUpdated       ChangeKind = "updated"
+	ChangeRemoved       ChangeKind = "removed"
+	ChangeStatusChanged ChangeKind = "status_changed"
+	ChangeBatch         ChangeKind = "batch" // AtomicReplace; individual changes in Changes
+)
+
+// ChangeEvent describes a change to the registry
+type ChangeEvent struct {
+	Kind      ChangeKind    `json:"kind"`
+	Path      string        `json:"path,omitempty"`
+	OldStatus NodeStatus    `json:"old_status,omitempty"`
+	NewStatus NodeStatus    `json:"new_status,omitempty"`
+	Changes   []ChangeEvent `json:"changes,omitempty"` // Only set for ChangeBatch
+
+	// Missed is the number of events dropped for this subscriber since the
+	// previous delivered event. Subscribers seeing Missed > 0 should resync.
+	Missed int `json:"missed,omitempty"`
+}
+
+type watcher struct {
+	ch     chan ChangeEvent
+	missed int
+}
+
+// watchers holds change subscribers. It has its own lock so emitting never
+// contends with subscribe/unsubscribe beyond a short critical section.
+type watchers struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]*watcher
+}
+
+// Watch subscribes to registry changes. The returned function unsubscribes
+// and closes the channel; it is safe to call more than once.
+func (r *Registry) Watch() (<-chan ChangeEvent, func()) {
+	r.watchers.mu.Lock()
+	defer r.watchers.mu.Unlock()
+
+	if r.watchers.subs == nil {
+		r.watchers.subs = make(map[uint64]*watcher)
+	}
+	id := r.watchers.nextID
+	r.watchers.nextID++
+
+	w := &watcher{ch: make(chan ChangeEvent, watchBufferSize)}
+	r.watchers.subs[id] = w
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			r.watchers.mu.Lock()
+			defer r.watchers.mu.Unlock()
+			delete(r.watchers.subs, id)
+			close(w.ch)
+		})
+	}
+
+	return w.ch, unsubscribe
+}
+
+// emit delivers an event to every subscriber without blocking
+func (ws *watchers) emit(event ChangeEvent) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for _, w := range ws.subs {
+		e := event
+		e.Missed = w.missed
+		select {
+		case w.ch <- e:
+			w.missed = 0
+		default:
+			w.missed++
+		}
+	}
+}
+
+// RemovedPaths returns the paths an event removes from the registry,
+// including those of a batch
+func (e ChangeEvent) RemovedPaths() []string {
+	var paths []string
+	if e.Kind == ChangeRemoved {
+		paths = append(paths, e.Path)
+	}
+	for _, c := range e.Changes {
+		paths = append(paths, c.RemovedPaths()...)
+	}
+	return paths
+}
@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"testing"
+)
+
+func receive(t *testing.T, ch <-chan ChangeEvent) ChangeEvent {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	default:
+		t.Fatal("expected an event, got none")
+	}
+	return ChangeEvent{}
+}
+
+func TestWatchRegisterAndDeregister(t *testing.T) {
+	r := NewRegistry()
+	events, unsubscribe := r.Watch()
+	defer unsubscribe()
+
+	r.Register(makeNode("prices", "Prices", "", NodeStatusActive, false))
+	r.Register(makeNode("prices", "Prices v2", "", NodeStatusActive, false))
+	r.Deregister("prices")
+
+	for _, kind := range []ChangeKind{ChangeAdded, ChangeUpdated, ChangeRemoved} {
+		e := receive(t, events)
+		if e.Kind != kind || e.Path != "prices" {
+			t.Errorf("expected %s on prices, got %+v", kind, e)
+		}
+	}
+	if r.Exists("prices") {
+		t.Error("expected prices to be deregistered")
+	}
+}
+
+func TestWatchAtomicReplaceIsBatch(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("a", "A", "", NodeStatusActive, true))
+	r.Register(makeNode("b", "B", "", NodeStatusActive, true))
+
+	events, unsubscribe := r.Watch()
+	defer unsubscribe()
+
+	r.AtomicReplace([]*CatalogNode{
+		makeNode("a", "A", "", NodeStatusDeprecated, true),
+		makeNode("c", "C", "", NodeStatusActive, true),
+	})
+
+	e := receive(t, events)
+	if e.Kind != ChangeBatch {
+		t.Fatalf("expected batch event, got %s", e.Kind)
+	}
+	kinds := make(map[string]ChangeEvent)
+	for _, c := range e.Changes {
+		kinds[c.Path] = c
+	}
+	if kinds["a"].Kind != ChangeStatusChanged || kinds["a"].NewStatus != NodeStatusDeprecated {
+		t.Errorf("expected status change on a, got %+v", kinds["a"])
+	}
+	if kinds["b"].Kind != ChangeRemoved {
+		t.Errorf("expected removal of b, got %+v", kinds["b"])
+	}
+	if kinds["c"].Kind != ChangeAdded {
+		t.Errorf("expected addition of c, got %+v", kinds["c"])
+	}
+	if removed := e.RemovedPaths(); len(removed) != 1 || removed[0] != "b" {
+		t.Errorf("expected b removed, got %v", removed)
+	}
+}
+
+func TestWatchDropsWhenFullAndFlags(t *testing.T) {
+	r := NewRegistry()
+	r.Register(makeNode("p", "P", "", NodeStatusActive, true))
+	events, unsubscribe := r.Watch()
+	defer unsubscribe()
+	flip := func(i int) {
+		status := NodeStatusDeprecated
+		if i%2 == 1 {
+			status = NodeStatusActive
+		}
+		if _, err := r.UpdateStatus("p", status, "bot", "flip"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Overfill without reading; writers must not block
+	for i := 0; i < watchBufferSize+5; i++ {
+		flip(i)
+	}
+
+	// Drain the buffer, then the next event carries the drop count
+	for i := 0; i < watchBufferSize; i++ {
+		<-events
+	}
+	flip(watchBufferSize + 5)
+	e := receive(t, events)
+	if e.Missed != 5 {
+		t.Errorf("expected Missed=5, got %d", e.Missed)
+	}
+}
+
+func TestWatchUnsubscribeTwice(t *testing.T) {
+	r := NewRegistry()
+	events, unsubscribe := r.Watch()
+
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed")
+	}
+
+	// Emitting after unsubscribe must not panic
+	r.Register(makeNode("a", "A", "", NodeStatusActive, true))
+}
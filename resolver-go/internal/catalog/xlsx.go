@@ -0,0 +1,473 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XLSXContentType is the media type of an Excel workbook
+const XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Limits on what a workbook may expand to when read, so that a small zip
+// bomb can't exhaust memory; a workbook exceeding one is rejected. They are
+// variables for tests.
+var (
+	xlsxMaxEntries       = 10000     // Files in the archive
+	xlsxMaxBytes   int64 = 256 << 20 // Uncompressed bytes of the parts read
+	xlsxMaxRows          = 1 << 20   // Rows of every worksheet together
+	xlsxMaxCells         = 10000000  // Cells of every row together, gaps included
+)
+
+// xlsxMaxColumn is the last column Excel allows, XFD
+const xlsxMaxColumn = 16384
+
+// XLSXRowError describes a worksheet row that could not be imported
+type XLSXRowError struct {
+	File    string `json:"file,omitempty"` // Set by LoadXLSXFile
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// XLSXImportError aggregates every rejected row of a workbook import
+type XLSXImportError struct {
+	Rows []XLSXRowError
+}
+
+func (e *XLSXImportError) Error() string {
+	lines := make([]string, len(e.Rows))
+	for i, row := range e.Rows {
+		lines[i] = fmt.Sprintf("  %s row %d: %s", row.Sheet, row.Row, row.Message)
+	}
+	return fmt.Sprintf("%d invalid worksheet rows:\n%s", len(e.Rows), strings.Join(lines, "\n"))
+}
+
+// LoadXLSX reads leaf nodes from an Excel workbook, one row per node. Each
+// worksheet whose first row has a path column is imported, using the CSV
+// column layout (see LoadCSV); other worksheets, such as notes, are skipped.
+// Rows are validated like YAML nodes, and every rejected row is reported with
+// its sheet name and row number in an *XLSXImportError.
+func LoadXLSX(r io.ReaderAt, size int64, defaults *CatalogNodeYAML) ([]*CatalogNode, error) {
+	if defaults != nil && defaults.SourceBinding != nil && defaults.SourceBinding.Template != "" {
+		return nil, fmt.Errorf("XLSX defaults cannot use source_binding.template; binding templates apply within the catalog file that defines them")
+	}
+	sheets, err := readXLSXSheets(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	type location struct {
+		sheet string
+		row   int
+	}
+	nodes := make([]*CatalogNode, 0)
+	rowErrors := make([]XLSXRowError, 0)
+	first := make(map[string]location)
+	imported := 0
+	for _, sheet := range sheets {
+		if len(sheet.rows) == 0 {
+			continue
+		}
+		header := sheet.rows[0].cells
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[strings.TrimSpace(name)] = i
+		}
+		if _, ok := columns[csvColumnPath]; !ok {
+			continue
+		}
+		if _, ok := columns[csvColumnSourceType]; !ok {
+			return nil, fmt.Errorf("worksheet %s is missing the %s column", sheet.name, csvColumnSourceType)
+		}
+		imported++
+
+		for _, row := range sheet.rows[1:] {
+			if isBlankRow(row.cells) {
+				continue
+			}
+			node, rowErr := csvRowToNode(row.cells, header, columns, defaults)
+			if rowErr != nil {
+				rowErrors = append(rowErrors, XLSXRowError{Sheet: sheet.name, Row: row.number, Path: rowErr.Path, Message: rowErr.Message})
+				continue
+			}
+			if prev, dup := first[node.Path]; dup {
+				rowErrors = append(rowErrors, XLSXRowError{Sheet: sheet.name, Row: row.number, Path: node.Path,
+					Message: fmt.Sprintf("duplicate catalog path '%s' (first defined at %s row %d)", node.Path, prev.sheet, prev.row)})
+				continue
+			}
+			first[node.Path] = location{sheet.name, row.number}
+			node.SourceLine = row.number
+			nodes = append(nodes, node)
+		}
+	}
+	if imported == 0 && len(sheets) > 0 {
+		return nil, fmt.Errorf("no worksheet has a %s column in its first row", csvColumnPath)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &XLSXImportError{Rows: rowErrors}
+	}
+	return nodes, nil
+}
+
+// LoadXLSXFile loads leaf nodes from an .xlsx file; see LoadXLSX
+func LoadXLSXFile(path string, defaults *CatalogNodeYAML) ([]*CatalogNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog file: %w", err)
+	}
+
+	nodes, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), defaults)
+	var xlsxErr *XLSXImportError
+	if errors.As(err, &xlsxErr) {
+		for i := range xlsxErr.Rows {
+			xlsxErr.Rows[i].File = path
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		node.SourceFile = path
+	}
+	return nodes, nil
+}
+
+// XLSXSheetNotFoundError is a worksheet name missing from a workbook
+type XLSXSheetNotFoundError struct {
+	Sheet  string
+	Sheets []string // Worksheets of the workbook, in tab order
+}
+
+func (e *XLSXSheetNotFoundError) Error() string {
+	return fmt.Sprintf("worksheet %s not found (workbook has %s)", e.Sheet, strings.Join(e.Sheets, ", "))
+}
+
+// ReadXLSXTable reads one worksheet as rows keyed by the cells of its header
+// row, headerRow being the 1-based row number. An empty sheet is the first
+// worksheet; otherwise the name is matched exactly, then case-insensitively.
+// Blank rows and columns with an empty header are skipped, and every value
+// is the cell text.
+func ReadXLSXTable(r io.ReaderAt, size int64, sheet string, headerRow int) ([]map[string]interface{}, error) {
+	sheets, err := readXLSXSheets(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("read XLSX: workbook has no worksheets")
+	}
+	if headerRow < 1 {
+		headerRow = 1
+	}
+
+	var found *xlsxSheet
+	if sheet == "" {
+		found = &sheets[0]
+	}
+	for i := range sheets {
+		if found == nil && sheets[i].name == sheet {
+			found = &sheets[i]
+		}
+	}
+	for i := range sheets {
+		if found == nil && strings.EqualFold(sheets[i].name, sheet) {
+			found = &sheets[i]
+		}
+	}
+	if found == nil {
+		names := make([]string, len(sheets))
+		for i, s := range sheets {
+			names[i] = s.name
+		}
+		return nil, &XLSXSheetNotFoundError{Sheet: sheet, Sheets: names}
+	}
+
+	var header []string
+	rows := make([]map[string]interface{}, 0)
+	for _, row := range found.rows {
+		switch {
+		case row.number < headerRow:
+			continue
+		case row.number == headerRow:
+			header = row.cells
+			continue
+		case header == nil:
+			return nil, fmt.Errorf("worksheet %s has no header row %d", found.name, headerRow)
+		case isBlankRow(row.cells):
+			continue
+		}
+		values := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			value := ""
+			if i < len(row.cells) {
+				value = row.cells[i]
+			}
+			values[name] = value
+		}
+		rows = append(rows, values)
+	}
+	if header == nil {
+		return nil, fmt.Errorf("worksheet %s has no header row %d", found.name, headerRow)
+	}
+	return rows, nil
+}
+
+// isBlankRow reports whether every cell of a row is empty; spreadsheets often
+// carry formatted but empty rows below the data
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// xlsxSheet is a worksheet as rows of cell text
+type xlsxSheet struct {
+	name string
+	rows []xlsxRow
+}
+
+// xlsxRow is a non-empty worksheet row. cells is indexed by column, with
+// missing cells left empty.
+type xlsxRow struct {
+	number int
+	cells  []string
+}
+
+// Workbook parts read by the importer. Only cell values are used; styles,
+// formulas and formatting are ignored.
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXSheets returns the worksheets of a workbook in tab order, failing
+// when the workbook exceeds the xlsxMax limits
+func readXLSXSheets(r io.ReaderAt, size int64) ([]xlsxSheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("read XLSX: not an Excel workbook: %w", err)
+	}
+	if len(zr.File) > xlsxMaxEntries {
+		return nil, fmt.Errorf("read XLSX: workbook has more than %d files", xlsxMaxEntries)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	parts := &xlsxParts{files: files, remaining: xlsxMaxBytes}
+	rows, total := 0, 0
+
+	var workbook xlsxWorkbook
+	if err := parts.read("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := parts.read("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.ID] = path.Join("xl", rel.Target)
+		}
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := parts.read("xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheets := make([]xlsxSheet, 0, len(workbook.Sheets))
+	for _, s := range workbook.Sheets {
+		target, ok := targets[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("read XLSX: worksheet %s has no part", s.Name)
+		}
+		var ws xlsxWorksheet
+		if err := parts.read(target, &ws); err != nil {
+			return nil, err
+		}
+
+		sheet := xlsxSheet{name: s.Name}
+		if rows += len(ws.Rows); rows > xlsxMaxRows {
+			return nil, fmt.Errorf("read XLSX: workbook has more than %d rows", xlsxMaxRows)
+		}
+		for i, row := range ws.Rows {
+			number := row.Number
+			if number == 0 {
+				number = i + 1
+			}
+			cells := make([]string, 0, len(row.Cells))
+			for j, c := range row.Cells {
+				col := j
+				if c.Ref != "" {
+					if col, err = xlsxColumn(c.Ref); err != nil {
+						return nil, fmt.Errorf("read XLSX: worksheet %s row %d: %w", s.Name, number, err)
+					}
+				}
+				var value string
+				switch c.Type {
+				case "s":
+					idx, err := strconv.Atoi(c.Value)
+					if err != nil || idx < 0 || idx >= len(shared.Items) {
+						return nil, fmt.Errorf("read XLSX: worksheet %s cell %s: bad shared string index %q", s.Name, c.Ref, c.Value)
+					}
+					value = shared.Items[idx].String()
+				case "inlineStr":
+					value = c.Inline.String()
+				case "b":
+					value = map[string]string{"0": "false", "1": "true"}[c.Value]
+				default:
+					value = c.Value
+				}
+				if grow := col + 1 - len(cells); grow > 0 {
+					if total += grow; total > xlsxMaxCells {
+						return nil, fmt.Errorf("read XLSX: workbook has more than %d cells", xlsxMaxCells)
+					}
+					cells = append(cells, make([]string, grow)...)
+				}
+				cells[col] = value
+			}
+			sheet.rows = append(sheet.rows, xlsxRow{number: number, cells: cells})
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// xlsxParts reads the XML parts of a workbook within xlsxMaxBytes
+// uncompressed bytes, whatever sizes the archive declares
+type xlsxParts struct {
+	files     map[string]*zip.File
+	remaining int64
+}
+
+// errXLSXTooLarge is a workbook expanding beyond xlsxMaxBytes
+var errXLSXTooLarge = errors.New("workbook expands beyond the size limit")
+
+// read decodes one XML part of the workbook
+func (p *xlsxParts) read(name string, v interface{}) error {
+	f, ok := p.files[name]
+	if !ok {
+		return fmt.Errorf("read XLSX: workbook has no %s", name)
+	}
+	if f.UncompressedSize64 > uint64(p.remaining) {
+		return fmt.Errorf("read XLSX: %s: %w of %d bytes", name, errXLSXTooLarge, xlsxMaxBytes)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("read XLSX: %s: %w", name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(&xlsxLimitReader{r: rc, parts: p}).Decode(v); err != nil {
+		if errors.Is(err, errXLSXTooLarge) {
+			return fmt.Errorf("read XLSX: %s: %w of %d bytes", name, err, xlsxMaxBytes)
+		}
+		return fmt.Errorf("read XLSX: %s: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxLimitReader reads a part, failing with errXLSXTooLarge once it has
+// used up what remains of the workbook's bytes
+type xlsxLimitReader struct {
+	r     io.Reader
+	parts *xlsxParts
+}
+
+func (l *xlsxLimitReader) Read(b []byte) (int, error) {
+	if l.parts.remaining <= 0 {
+		// Only a part ending exactly at the limit is within it
+		if n, err := l.r.Read(make([]byte, 1)); n == 0 && err == io.EOF {
+			return 0, io.EOF
+		}
+		return 0, errXLSXTooLarge
+	}
+	if int64(len(b)) > l.parts.remaining {
+		b = b[:l.parts.remaining]
+	}
+	n, err := l.r.Read(b)
+	l.parts.remaining -= int64(n)
+	return n, err
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as "C7"
+func xlsxColumn(ref string) (int, error) {
+	col := 0
+	for i, ch := range ref {
+		if ch >= 'A' && ch <= 'Z' {
+			if col = col*26 + int(ch-'A'+1); col > xlsxMaxColumn {
+				break
+			}
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return col - 1, nil
+	}
+	return 0, fmt.Errorf("bad cell reference %q", ref)
+}
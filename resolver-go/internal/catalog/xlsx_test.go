@@ -0,0 +1,302 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// xlsxTestSheet is a worksheet for buildXLSX; rows maps a row number to its
+// cells from column A
+type xlsxTestSheet struct {
+	name string
+	rows map[int][]string
+}
+
+// buildXLSX writes a minimal workbook. Cells go through the shared string
+// table, as Excel writes them.
+func buildXLSX(t *testing.T, sheets ...xlsxTestSheet) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name, content string) {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var shared []string
+	var workbook, rels strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, sheet.name, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+
+		var data strings.Builder
+		for row := 1; row <= 100; row++ {
+			cells, ok := sheet.rows[row]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&data, `<row r="%d">`, row)
+			for col, value := range cells {
+				if value == "" {
+					continue
+				}
+				fmt.Fprintf(&data, `<c r="%c%d" t="s"><v>%d</v></c>`, 'A'+col, row, len(shared))
+				shared = append(shared, value)
+			}
+			data.WriteString(`</row>`)
+		}
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+data.String()+`</sheetData></worksheet>`)
+	}
+
+	var sst strings.Builder
+	for _, s := range shared {
+		fmt.Fprintf(&sst, `<si><t>%s</t></si>`, s)
+	}
+	write("xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`+workbook.String()+`</sheets></workbook>`)
+	write("xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+rels.String()+`</Relationships>`)
+	write("xl/sharedStrings.xml", `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+sst.String()+`</sst>`)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+var xlsxHeader = []string{"path", "display_name", "description", "owner", "source_type", "table", "config.account", "config.database"}
+
+func TestLoadXLSX(t *testing.T) {
+	data := buildXLSX(t,
+		xlsxTestSheet{name: "Notes", rows: map[int][]string{1: {"Maintained by the refdata team"}}},
+		xlsxTestSheet{name: "Securities", rows: map[int][]string{
+			1: xlsxHeader,
+			2: {"refdata/securities/isin", "ISIN map", "ISIN to security id", "isin-owner@example.com", "snowflake", "ISIN_MAP", "acme", "REF"},
+			4: {"refdata/securities/cusip", "CUSIP map", "", "", "snowflake", "CUSIP_MAP", "acme", "REF"},
+		}},
+	)
+	defaults := &CatalogNodeYAML{
+		Classification: "internal",
+		Ownership:      &OwnershipYAML{AccountableOwner: strPtr("refdata@example.com"), SupportChannel: strPtr("#refdata")},
+	}
+	nodes, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(nodes))
+	}
+
+	isin := nodes[0]
+	if isin.Path != "refdata/securities/isin" || isin.DisplayName != "ISIN map" || !isin.IsLeaf || isin.SourceLine != 2 {
+		t.Errorf("unexpected node: %+v", isin)
+	}
+	if *isin.Ownership.AccountableOwner != "isin-owner@example.com" || *isin.Ownership.SupportChannel != "#refdata" {
+		t.Errorf("owner column should win over the defaults, got %+v", isin.Ownership)
+	}
+	sb := isin.SourceBinding
+	if sb.SourceType != SourceTypeSnowflake || sb.Config["table"] != "ISIN_MAP" || sb.Config["account"] != "acme" {
+		t.Errorf("unexpected binding: %+v", sb)
+	}
+
+	cusip := nodes[1]
+	if cusip.SourceLine != 4 || *cusip.Ownership.AccountableOwner != "refdata@example.com" {
+		t.Errorf("row without an owner should take the default, got line %d ownership %+v", cusip.SourceLine, cusip.Ownership)
+	}
+	if *defaults.Ownership.AccountableOwner != "refdata@example.com" {
+		t.Error("owner column modified the shared defaults")
+	}
+}
+
+func TestLoadXLSXReportsSheetAndRow(t *testing.T) {
+	data := buildXLSX(t,
+		xlsxTestSheet{name: "Rates", rows: map[int][]string{
+			1: xlsxHeader,
+			2: {"rates/swaps", "", "", "", "snowflake", "SWAPS", "acme", "RATES"},
+			3: {"rates/not ok", "", "", "", "snowflake", "X", "acme", "RATES"},
+		}},
+		xlsxTestSheet{name: "Credit", rows: map[int][]string{
+			1: xlsxHeader,
+			2: {"credit/cds", "", "", "", "snowflake", "CDS", "acme"},
+			3: {"rates/swaps", "", "", "", "snowflake", "SWAPS", "acme", "RATES"},
+		}},
+	)
+	_, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), nil)
+	var xlsxErr *XLSXImportError
+	if !errors.As(err, &xlsxErr) {
+		t.Fatalf("expected *XLSXImportError, got %v", err)
+	}
+	if len(xlsxErr.Rows) != 3 {
+		t.Fatalf("expected 3 rejected rows, got %v", xlsxErr.Rows)
+	}
+	want := []struct {
+		sheet string
+		row   int
+		text  string
+	}{
+		{"Rates", 3, "rates/not ok"},
+		{"Credit", 2, "database"},
+		{"Credit", 3, "first defined at Rates row 2"},
+	}
+	for i, w := range want {
+		got := xlsxErr.Rows[i]
+		if got.Sheet != w.sheet || got.Row != w.row || !strings.Contains(got.Message, w.text) {
+			t.Errorf("row error %d = %+v, want %s row %d mentioning %q", i, got, w.sheet, w.row, w.text)
+		}
+	}
+	if !strings.Contains(err.Error(), "Credit row 2:") {
+		t.Errorf("error should name sheet and row, got %q", err)
+	}
+}
+
+func TestLoadXLSXErrors(t *testing.T) {
+	if _, err := LoadXLSX(bytes.NewReader([]byte("path,source_type\n")), 17, nil); err == nil {
+		t.Error("expected error for a file that is not a workbook")
+	}
+
+	data := buildXLSX(t, xlsxTestSheet{name: "Sheet1", rows: map[int][]string{1: {"name", "type"}}})
+	if _, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), nil); err == nil || !strings.Contains(err.Error(), "path column") {
+		t.Errorf("expected missing path column error, got %v", err)
+	}
+
+	data = buildXLSX(t, xlsxTestSheet{name: "Sheet1", rows: map[int][]string{1: {"path", "display_name"}}})
+	if _, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), nil); err == nil || !strings.Contains(err.Error(), "Sheet1 is missing the source_type column") {
+		t.Errorf("expected missing source_type column error, got %v", err)
+	}
+}
+
+func TestLoadXLSXFileSetsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.xlsx")
+	data := buildXLSX(t, xlsxTestSheet{name: "Inventory", rows: map[int][]string{
+		1: xlsxHeader,
+		2: {"rates/swaps", "", "", "", "snowflake", "SWAPS", "acme", "RATES"},
+		3: {"rates/bonds", "", "", "", "snowflake", "BONDS", "acme"},
+	}})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadXLSXFile(path, nil)
+	var xlsxErr *XLSXImportError
+	if !errors.As(err, &xlsxErr) || xlsxErr.Rows[0].File != path {
+		t.Fatalf("expected row errors naming the file, got %v", err)
+	}
+
+	data = buildXLSX(t, xlsxTestSheet{name: "Inventory", rows: map[int][]string{
+		1: xlsxHeader,
+		2: {"rates/swaps", "", "", "", "snowflake", "SWAPS", "acme", "RATES"},
+	}})
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := LoadXLSXFile(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes[0].SourceFile != path {
+		t.Errorf("expected source file %s, got %q", path, nodes[0].SourceFile)
+	}
+}
+
+func TestReadXLSXTable(t *testing.T) {
+	data := buildXLSX(t,
+		xlsxTestSheet{name: "Notes", rows: map[int][]string{1: {"not data"}}},
+		xlsxTestSheet{name: "PoolData", rows: map[int][]string{
+			1: {"Pool report, generated nightly"},
+			2: {"POOL_ID", "", "COUPON"},
+			3: {"31418A", "ignored", "4.5"},
+			5: {"31418B", "", "5"},
+		}},
+	)
+
+	rows, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "pooldata", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["POOL_ID"] != "31418A" || rows[1]["COUPON"] != "5" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if _, ok := rows[0][""]; ok {
+		t.Error("expected columns without a header to be skipped")
+	}
+
+	if rows, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "", 1); err != nil || len(rows) != 0 {
+		t.Errorf("expected the first worksheet with no data rows, got %v (%v)", rows, err)
+	}
+
+	_, err = ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "Summary", 1)
+	var sheetErr *XLSXSheetNotFoundError
+	if !errors.As(err, &sheetErr) || !strings.Contains(err.Error(), "worksheet Summary not found (workbook has Notes, PoolData)") {
+		t.Errorf("expected a sheet not found error listing the sheets, got %v", err)
+	}
+
+	if _, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "Notes", 4); err == nil || !strings.Contains(err.Error(), "no header row 4") {
+		t.Errorf("expected a missing header row error, got %v", err)
+	}
+}
+
+func TestReadXLSXRejectsWorkbooksBeyondLimits(t *testing.T) {
+	// A megabyte of repeated text compresses to a few kilobytes
+	bomb := buildXLSX(t, xlsxTestSheet{name: "Sheet1", rows: map[int][]string{1: {"path"}, 2: {strings.Repeat("x", 1<<20)}}})
+	if len(bomb) > 64<<10 {
+		t.Fatalf("expected a small archive, got %d bytes", len(bomb))
+	}
+	data := buildXLSX(t, xlsxTestSheet{name: "Sheet1", rows: map[int][]string{
+		1: {"a", "b", "c"},
+		2: {"1", "2", "3"},
+		3: {"4", "5", "6"},
+	}})
+
+	limit := func(v *int, n int) {
+		old := *v
+		*v = n
+		t.Cleanup(func() { *v = old })
+	}
+	oldBytes := xlsxMaxBytes
+	xlsxMaxBytes = 256 << 10
+	t.Cleanup(func() { xlsxMaxBytes = oldBytes })
+
+	_, err := ReadXLSXTable(bytes.NewReader(bomb), int64(len(bomb)), "", 1)
+	if !errors.Is(err, errXLSXTooLarge) || !strings.Contains(err.Error(), "xl/sharedStrings.xml") {
+		t.Errorf("expected the workbook rejected for its uncompressed size, got %v", err)
+	}
+	if _, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "", 1); err != nil {
+		t.Fatalf("expected a workbook within the limits read, got %v", err)
+	}
+
+	limit(&xlsxMaxRows, 2)
+	if _, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "", 1); err == nil || !strings.Contains(err.Error(), "more than 2 rows") {
+		t.Errorf("expected the row limit enforced, got %v", err)
+	}
+	limit(&xlsxMaxRows, 3)
+	limit(&xlsxMaxCells, 8)
+	if _, err := LoadXLSX(bytes.NewReader(data), int64(len(data)), nil); err == nil || !strings.Contains(err.Error(), "more than 8 cells") {
+		t.Errorf("expected the cell limit enforced, got %v", err)
+	}
+	limit(&xlsxMaxEntries, 3)
+	if _, err := ReadXLSXTable(bytes.NewReader(data), int64(len(data)), "", 1); err == nil || !strings.Contains(err.Error(), "more than 3 files") {
+		t.Errorf("expected the entry limit enforced, got %v", err)
+	}
+}
+
+func TestXLSXColumnStopsAtXFD(t *testing.T) {
+	if col, err := xlsxColumn("XFD7"); err != nil || col != xlsxMaxColumn-1 {
+		t.Errorf("expected XFD as the last column, got %d (%v)", col, err)
+	}
+	if _, err := xlsxColumn("XFE7"); err == nil {
+		t.Error("expected a column beyond XFD refused")
+	}
+	if _, err := xlsxColumn("ZZZZZZZZZZZZZZ1"); err == nil {
+		t.Error("expected an overlong column refused")
+	}
+}
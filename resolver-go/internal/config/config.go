@@ -10,19 +10,26 @@ import (
 
 // Config represents the service configuration
 type Config struct {
-	ProjectName  string            `yaml:"project_name"`
-	Server       ServerConfig      `yaml:"server"`
-	Telemetry    TelemetryConfig   `yaml:"telemetry"`
-	Cache        CacheConfig       `yaml:"cache"`
-	Redis        RedisConfig       `yaml:"redis"`
-	Catalog      CatalogConfig     `yaml:"catalog"`
-	Auth         AuthConfig        `yaml:"auth"`
-	ConfigUI     ConfigUIConfig    `yaml:"config_ui"`
-	Deprecation  DeprecationConfig `yaml:"deprecation"`
-	Models       ModelsConfig      `yaml:"models"`
-	Requests     RequestsConfig    `yaml:"requests"`
-	Governance   GovernanceConfig  `yaml:"governance"`
-	SqlCatalog   SqlCatalogConfig  `yaml:"sql_catalog"`
+	ProjectName string            `yaml:"project_name"`
+	Server      ServerConfig      `yaml:"server"`
+	Telemetry   TelemetryConfig   `yaml:"telemetry"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Query       QueryConfig       `yaml:"query"`
+	Batch       BatchConfig       `yaml:"batch"`
+	Fetch       FetchConfig       `yaml:"fetch"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Redis       RedisConfig       `yaml:"redis"`
+	Catalog     CatalogConfig     `yaml:"catalog"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Audit       AuditConfig       `yaml:"audit"`
+	ConfigUI    ConfigUIConfig    `yaml:"config_ui"`
+	Deprecation DeprecationConfig `yaml:"deprecation"`
+	Models      ModelsConfig      `yaml:"models"`
+	Requests    RequestsConfig    `yaml:"requests"`
+	Governance  GovernanceConfig  `yaml:"governance"`
+	SqlCatalog  SqlCatalogConfig  `yaml:"sql_catalog"`
+	Health      HealthConfig      `yaml:"health"`
+	Secrets     SecretsConfig     `yaml:"secrets"`
 }
 
 // ServerConfig represents server configuration
@@ -31,29 +38,132 @@ type ServerConfig struct {
 	Port    int    `yaml:"port"`
 	Workers int    `yaml:"workers"`
 	Reload  bool   `yaml:"reload"`
+
+	// HTTP server timeouts; 0 keeps the defaults (read 30, read header 10,
+	// write 30, idle 120)
+	ReadTimeoutSeconds       float64 `yaml:"read_timeout_seconds"`
+	ReadHeaderTimeoutSeconds float64 `yaml:"read_header_timeout_seconds"`
+	WriteTimeoutSeconds      float64 `yaml:"write_timeout_seconds"`
+	IdleTimeoutSeconds       float64 `yaml:"idle_timeout_seconds"`
+
+	// On SIGTERM or SIGINT readiness fails at once; the server keeps accepting
+	// for shutdown_delay_seconds (default 0) so load balancers can stop
+	// routing to it, then waits up to shutdown_grace_seconds (default 30) for
+	// in-flight requests before closing their connections
+	ShutdownDelaySeconds float64 `yaml:"shutdown_delay_seconds"`
+	ShutdownGraceSeconds float64 `yaml:"shutdown_grace_seconds"`
+
+	// Request body limits by endpoint group
+	BodyLimits BodyLimitsConfig `yaml:"body_limits"`
+
+	// Origins browsers may call the API from ("*" for any); none by default
+	CORSOrigins []string `yaml:"cors_origins"`
+
+	// Addresses or CIDRs of reverse proxies whose X-Forwarded-For names the
+	// client, e.g. 10.0.0.0/8; none by default, so the remote address is
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// Answer errors in the format before the error envelope, a top-level
+	// "error" string with the details beside it. Kept for one release.
+	LegacyErrors bool `yaml:"legacy_errors"`
+}
+
+// BodyLimitsConfig caps request bodies, in bytes; 0 keeps the default. Larger
+// bodies are refused with a 413.
+type BodyLimitsConfig struct {
+	DefaultBytes   int64 `yaml:"default_bytes"`   // Catalog edits and admin requests (1 MiB)
+	BatchBytes     int64 `yaml:"batch_bytes"`     // Batch resolve and describe (1 MiB)
+	TelemetryBytes int64 `yaml:"telemetry_bytes"` // Access events (64 KiB)
+	ImportBytes    int64 `yaml:"import_bytes"`    // Catalog imports (32 MiB)
 }
 
 // TelemetryConfig represents telemetry configuration
 type TelemetryConfig struct {
-	Enabled             bool                   `yaml:"enabled"`
-	SinkType            string                 `yaml:"sink_type"`
-	SinkConfig          map[string]interface{} `yaml:"sink_config"`
-	BatchSize           int                    `yaml:"batch_size"`
-	FlushIntervalSeconds float64               `yaml:"flush_interval_seconds"`
-	MaxQueueSize        int                    `yaml:"max_queue_size"`
+	Enabled              bool                   `yaml:"enabled"`
+	SinkType             string                 `yaml:"sink_type"`
+	SinkConfig           map[string]interface{} `yaml:"sink_config"`
+	BatchSize            int                    `yaml:"batch_size"`
+	FlushIntervalSeconds float64                `yaml:"flush_interval_seconds"`
+	MaxQueueSize         int                    `yaml:"max_queue_size"`
 }
 
 // CacheConfig represents cache configuration
 type CacheConfig struct {
-	Enabled          bool `yaml:"enabled"`
-	MaxSize          int  `yaml:"max_size"`
-	DefaultTTLSeconds int `yaml:"default_ttl_seconds"`
+	Enabled           bool `yaml:"enabled"`
+	MaxSize           int  `yaml:"max_size"`
+	DefaultTTLSeconds int  `yaml:"default_ttl_seconds"`
+	ResolveTTLSeconds int  `yaml:"resolve_ttl_seconds"` // TTL of cached resolve results; 0 uses default_ttl_seconds
+	DataTTLSeconds    int  `yaml:"data_ttl_seconds"`    // TTL of fetched data for bindings whose cache sets no ttl_seconds; 0 uses default_ttl_seconds
+}
+
+// QueryConfig represents how resolved queries are returned
+type QueryConfig struct {
+	// RenderedQuery also returns SQL queries with values substituted into the
+	// text, for legacy clients; values that could break out of a literal are rejected
+	RenderedQuery bool `yaml:"rendered_query"`
+
+	// MaxExpansions bounds the fully-specified monikers an expand_all
+	// resolve may expand ALL segments into (default 1000)
+	MaxExpansions int `yaml:"max_expansions"`
+}
+
+// BatchConfig represents batch resolve settings
+type BatchConfig struct {
+	Concurrency int `yaml:"concurrency"` // Monikers resolved at once per batch (default 8)
+}
+
+// FetchConfig represents GET /fetch settings
+type FetchConfig struct {
+	// TimeoutSeconds bounds one adapter fetch (default 30); a sooner
+	// deadline or cancellation of the request still applies
+	TimeoutSeconds float64 `yaml:"timeout_seconds"`
+}
+
+// RateLimitConfig represents per-caller rate limiting of /resolve,
+// /resolve/batch, /describe/batch and /fetch
+type RateLimitConfig struct {
+	Enabled bool    `yaml:"enabled"`
+	Rate    float64 `yaml:"rate"`  // Requests per second per caller (default 50)
+	Burst   int     `yaml:"burst"` // Bucket size (default twice the rate)
+
+	// MaxCallers bounds the buckets kept; the least recently seen caller's
+	// bucket is evicted beyond it (default 10000)
+	MaxCallers int `yaml:"max_callers"`
+
+	// Overrides set other limits for URL path prefixes, e.g. /resolve/batch;
+	// the longest matching prefix applies
+	Overrides []RateLimitOverride `yaml:"overrides"`
+}
+
+// RateLimitOverride is the limit for requests under a URL path prefix. A
+// rate of 0 or less means unlimited.
+type RateLimitOverride struct {
+	PathPrefix string  `yaml:"path_prefix"`
+	Rate       float64 `yaml:"rate"`
+	Burst      int     `yaml:"burst"` // Default twice the rate
 }
 
 // CatalogConfig represents catalog configuration
 type CatalogConfig struct {
-	DefinitionFile        string `yaml:"definition_file"`
-	ReloadIntervalSeconds int    `yaml:"reload_interval_seconds"`
+	DefinitionFile        string   `yaml:"definition_file"`  // Catalog file, or directory of *.yaml files
+	DefinitionFiles       []string `yaml:"definition_files"` // Additional catalogs merged after definition_file
+	ConflictPolicy        string   `yaml:"conflict_policy"`  // error (default), first-wins, last-wins, merge-fields
+	ReloadIntervalSeconds int      `yaml:"reload_interval_seconds"`
+	Strict                bool     `yaml:"strict"`       // Fail catalog load on validation errors instead of warning
+	RemoteToken           string   `yaml:"remote_token"` // Bearer token sent when fetching http(s):// catalogs
+
+	// Env selects the overlay applied on top of the merged catalogs
+	Env      string            `yaml:"env"`
+	Overlays map[string]string `yaml:"overlays"` // Environment name -> overlay file
+
+	// Namespaces maps a moniker namespace (verified@, user@, ...) to the catalog
+	// consulted before the default one for monikers in it
+	Namespaces map[string]string `yaml:"namespaces"`
+
+	// TreeMaxDepth caps the depth parameter of GET /tree (default 10) and
+	// TreeMaxNodes the nodes one tree response may list (default 5000)
+	TreeMaxDepth int `yaml:"tree_max_depth"`
+	TreeMaxNodes int `yaml:"tree_max_nodes"`
 }
 
 // AuthConfig represents authentication configuration
@@ -61,6 +171,68 @@ type AuthConfig struct {
 	Enabled     bool     `yaml:"enabled"`
 	Enforce     bool     `yaml:"enforce"`
 	MethodOrder []string `yaml:"method_order"`
+
+	// Capabilities grants capabilities (e.g. reveal_secrets) to caller user IDs
+	Capabilities map[string][]string `yaml:"capabilities"`
+
+	// RolesHeader is the request header listing the caller's roles,
+	// comma-separated; empty means X-User-Roles
+	RolesHeader string `yaml:"roles_header"`
+
+	// AllowedHoursBypassRoles may resolve outside access_policy.allowed_hours, e.g. ops
+	AllowedHoursBypassRoles []string `yaml:"allowed_hours_bypass_roles"`
+
+	// AdminRoles maps admin route groups (catalog, status, ownership, quality,
+	// import, reload, cache, default) to the roles that may use them. Empty leaves the
+	// admin routes open; set, they refuse anonymous callers.
+	AdminRoles map[string][]string `yaml:"admin_roles"`
+
+	Okta    OktaConfig    `yaml:"okta"`
+	APIKeys APIKeysConfig `yaml:"api_keys"`
+}
+
+// OktaConfig represents JWT bearer authentication. Despite the name, any
+// OIDC provider works.
+type OktaConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Issuer   string `yaml:"issuer"`   // Checked against the iss claim when set
+	Audience string `yaml:"audience"` // Checked against the aud claim when set
+
+	// JWKSURL is where signing keys are fetched; empty tries
+	// <issuer>/.well-known/jwks.json, then <issuer>/v1/keys (Okta)
+	JWKSURL      string `yaml:"jwks_url"`
+	JWKSCacheTTL int    `yaml:"jwks_cache_ttl"` // Seconds (default 3600)
+
+	UserClaim   string `yaml:"user_claim"`   // Caller user ID (default sub)
+	NameClaim   string `yaml:"name_claim"`   // Caller username (default name)
+	RolesClaim  string `yaml:"roles_claim"`  // Caller roles (default roles)
+	GroupsClaim string `yaml:"groups_claim"` // Also read as roles when set
+
+	// Static keys instead of JWKS: TestSecret verifies HS256 tokens (local
+	// development only) and PublicKey, a PEM RSA or EC public key, verifies
+	// RS*/ES* tokens. Either may be a secret:// reference.
+	TestSecret string `yaml:"test_secret"`
+	PublicKey  string `yaml:"public_key"`
+}
+
+// APIKeysConfig represents API-key authentication. Keys are loaded from File
+// and from the environment variable Env, each a YAML or JSON list of
+// {id, name, scopes, key or key_sha256}; key material is never a flag.
+type APIKeysConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	File    string `yaml:"file"`
+	Env     string `yaml:"env"`
+}
+
+// AuditConfig represents access-decision audit logging
+type AuditConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	File       string `yaml:"file"`        // JSON-lines file events are appended to
+	BufferSize int    `yaml:"buffer_size"` // Events queued before dropping (default 1024)
+
+	// SampleAllowed is the fraction (0-1) of successful resolutions audited;
+	// denials, sunset blocks and confirmation thresholds are always audited
+	SampleAllowed float64 `yaml:"sample_allowed"`
 }
 
 // ConfigUIConfig represents config UI settings
@@ -89,6 +261,11 @@ type DeprecationConfig struct {
 	ValidatedReload      bool `yaml:"validated_reload"`
 	BlockBreakingReload  bool `yaml:"block_breaking_reload"`
 	DeprecationTelemetry bool `yaml:"deprecation_telemetry"`
+	SunsetWarningDays    int  `yaml:"sunset_warning_days"` // Window for "expiring soon" sunset reports (default 30)
+
+	// SunsetGrace keeps resolving deprecated nodes past their sunset deadline,
+	// with a warning, instead of failing with 410 Gone; for a transition period
+	SunsetGrace bool `yaml:"sunset_grace"`
 }
 
 // ModelsConfig represents business models configuration
@@ -110,6 +287,11 @@ type GovernanceConfig struct {
 	BurstCapacity           float64 `yaml:"burst_capacity"`
 	GlobalRequestsPerSecond float64 `yaml:"global_requests_per_second"`
 	GlobalBurstCapacity     float64 `yaml:"global_burst_capacity"`
+
+	// OwnerPattern is the regular expression owner and governance role
+	// fields must match when ownership is edited through the API
+	// (default: an identifier or email address)
+	OwnerPattern string `yaml:"owner_pattern"`
 }
 
 // SqlCatalogConfig represents SQL catalog import/browse configuration
@@ -119,6 +301,27 @@ type SqlCatalogConfig struct {
 	SourceDBPath string `yaml:"source_db_path"`
 }
 
+// HealthConfig represents the dependency probes of GET /health/ready
+type HealthConfig struct {
+	Probes []ProbeConfig `yaml:"probes"`
+}
+
+// ProbeConfig is a dependency that must be reachable for the resolver to be
+// ready
+type ProbeConfig struct {
+	Name           string  `yaml:"name"`
+	Type           string  `yaml:"type"`            // secret, http or tcp
+	Target         string  `yaml:"target"`          // secret:// reference, URL, or host:port
+	TimeoutSeconds float64 `yaml:"timeout_seconds"` // Default 2
+}
+
+// SecretsConfig represents where secret:// references are read from
+type SecretsConfig struct {
+	// FileDir holds the files read by secret://file/<name>; empty means
+	// /run/secrets. References outside it are refused.
+	FileDir string `yaml:"file_dir"`
+}
+
 // Load loads configuration from a YAML file
 func Load(configPath string) (*Config, error) {
 	// Default: ../config.yaml (relative to resolver-go/)
@@ -1,14 +1,29 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
 )
 
-// UpdateStatusHandler handles PUT /catalog/{path}/status
+// UpdateStatusHandler handles PUT /catalog/{path}/status. Transitions follow
+// the lifecycle of catalog.Registry.UpdateStatus; an illegal one is a 422
+// listing the allowed next statuses. override with a reason bypasses the
+// lifecycle and is recorded in the audit log.
 type UpdateStatusHandler struct {
 	catalog *catalog.Registry
 }
@@ -21,21 +36,25 @@ func NewUpdateStatusHandler(reg *catalog.Registry) *UpdateStatusHandler {
 // ServeHTTP implements http.Handler
 func (h *UpdateStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract path from URL
-	path := strings.TrimPrefix(r.URL.Path, "/catalog/")
-	path = strings.TrimSuffix(path, "/status")
+	path, err := pathFromRequest(r, "/catalog/", "/status")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
 		return
 	}
 
 	// Parse request body
 	var request struct {
-		Status string `json:"status"`
+		Status   string `json:"status"`
+		Override bool   `json:"override"`
+		Reason   string `json:"reason"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body", nil)
+	if !decodeJSONBody(w, r, &request, true) {
 		return
 	}
 
@@ -51,34 +70,347 @@ func (h *UpdateStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	newStatus, ok := validStatuses[request.Status]
 	if !ok {
-		writeError(w, http.StatusBadRequest, "Invalid status", map[string]interface{}{
-			"detail":         "Status must be one of: draft, pending_review, approved, active, deprecated, archived",
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid status", map[string]interface{}{
+			"detail":   "Status must be one of: draft, pending_review, approved, active, deprecated, archived",
 			"provided": request.Status,
 		})
 		return
 	}
 
-	// Get node
+	override := ""
+	if request.Override {
+		if override = strings.TrimSpace(request.Reason); override == "" {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing override reason", map[string]interface{}{
+				"detail": "An override must give a reason for the audit log",
+			})
+			return
+		}
+	}
+
+	caller := callerFromRequest(r, "")
+	change, err := h.catalog.UpdateStatus(path, newStatus, caller.UserID, override)
+	if err != nil {
+		switch e := err.(type) {
+		case *catalog.NodeNotFoundError:
+			writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+				"path": path,
+			})
+		case *catalog.StatusTransitionError:
+			writeError(w, http.StatusUnprocessableEntity, ErrInvalidStatus, "Illegal status transition", map[string]interface{}{
+				"detail":         e.Error(),
+				"path":           e.Path,
+				"current_status": e.From,
+				"allowed":        e.Allowed,
+			})
+		default:
+			writeError(w, http.StatusInternalServerError, ErrInternal, "Internal server error", map[string]interface{}{
+				"detail": err.Error(),
+			})
+		}
+		return
+	}
+
+	response := map[string]interface{}{
+		"path":       path,
+		"old_status": string(change.OldStatus),
+		"new_status": string(change.NewStatus),
+		"updated":    change.OldStatus != change.NewStatus,
+	}
+	if len(change.Warnings) > 0 {
+		response["warnings"] = change.Warnings
+	}
+	if change.Overridden {
+		response["overridden"] = true
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateNodeHandler handles POST /catalog, registering a node from a JSON
+// catalog.NodeRequest. New nodes are drafts stamped with the caller and time;
+// a node below an unregistered parent is refused unless virtual_parents=true.
+type CreateNodeHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewCreateNodeHandler creates a new create node handler
+func NewCreateNodeHandler(reg *catalog.Registry) *CreateNodeHandler {
+	return &CreateNodeHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *CreateNodeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request catalog.NodeRequest
+	if !decodeJSONBody(w, r, &request, true) {
+		return
+	}
+	if request.Status != "" && request.Status != string(catalog.NodeStatusDraft) {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid status", map[string]interface{}{
+			"detail":   "New nodes are drafts; change the status with PUT /catalog/{path}/status",
+			"provided": request.Status,
+		})
+		return
+	}
+
+	caller := callerFromRequest(r, "")
+	now := time.Now().UTC().Format(time.RFC3339)
+	request.Status = string(catalog.NodeStatusDraft)
+	request.CreatedAt = &now
+	request.CreatedBy = &caller.UserID
+
+	node, err := request.Node()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid node", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if err := h.catalog.Create(node, caller.UserID, r.URL.Query().Get("virtual_parents") == "true"); err != nil {
+		switch e := err.(type) {
+		case *catalog.NodeExistsError:
+			writeError(w, http.StatusConflict, ErrConflict, "Node already exists", map[string]interface{}{
+				"detail": e.Error(),
+				"path":   e.Path,
+			})
+		case *catalog.ParentNotFoundError:
+			writeError(w, http.StatusUnprocessableEntity, ErrValidationFailed, "Parent not found", map[string]interface{}{
+				"detail": e.Error() + "; register it first or pass virtual_parents=true",
+				"path":   e.Path,
+				"parent": e.Parent,
+			})
+		default:
+			writeError(w, http.StatusInternalServerError, ErrInternal, "Internal server error", map[string]interface{}{
+				"detail": err.Error(),
+			})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, node)
+}
+
+// UpdateNodeHandler handles PUT and PATCH /catalog/{path}, editing the
+// metadata of a registered node (see catalog.EditableFields). PUT replaces
+// every editable field and PATCH only those in the body. Edits that change the
+// source binding contract are refused unless allow_binding_change=true, and
+// those of a binding that references secret:// values are always refused.
+type UpdateNodeHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewUpdateNodeHandler creates a new update node handler
+func NewUpdateNodeHandler(reg *catalog.Registry) *UpdateNodeHandler {
+	return &UpdateNodeHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *UpdateNodeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/catalog/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return
+	}
+	edit, err := catalog.ParseNodeEdit(path, data, r.Method == http.MethodPatch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid node", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	caller := callerFromRequest(r, "")
+	allowBindingChange := r.URL.Query().Get("allow_binding_change") == "true"
+	node, err := h.catalog.Update(path, caller.UserID, func(node *catalog.CatalogNode) error {
+		return edit.Apply(node, allowBindingChange)
+	})
+	if err != nil {
+		switch e := err.(type) {
+		case *catalog.NodeNotFoundError:
+			writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+				"path": e.Path,
+			})
+		case *catalog.BindingChangeError:
+			details := map[string]interface{}{
+				"detail": e.Error() + "; pass allow_binding_change=true to change it",
+				"path":   e.Path,
+			}
+			if e.OldFingerprint != nil {
+				details["old_fingerprint"] = *e.OldFingerprint
+			}
+			if e.NewFingerprint != nil {
+				details["new_fingerprint"] = *e.NewFingerprint
+			}
+			writeError(w, http.StatusConflict, ErrConfirmationRequired, "Binding change not allowed", details)
+		case *catalog.SecretBindingChangeError:
+			writeError(w, http.StatusForbidden, ErrOperationNotAllowed, "Binding change not allowed", map[string]interface{}{
+				"detail": e.Error(),
+				"path":   e.Path,
+			})
+		default:
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid node", map[string]interface{}{
+				"detail": err.Error(),
+			})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, node)
+}
+
+// UpdateOwnershipHandler handles PUT /catalog/{path}/ownership. The body is
+// an Ownership object: fields set to null are cleared and fields left out are
+// kept. The response has the node's resolved ownership and the descendants
+// that inherit from it.
+type UpdateOwnershipHandler struct {
+	catalog *catalog.Registry
+	pattern *regexp.Regexp
+}
+
+// NewUpdateOwnershipHandler creates a new ownership handler. Owner fields must
+// match pattern, or catalog.DefaultOwnerPattern when it is nil.
+func NewUpdateOwnershipHandler(reg *catalog.Registry, pattern *regexp.Regexp) *UpdateOwnershipHandler {
+	if pattern == nil {
+		pattern = regexp.MustCompile(catalog.DefaultOwnerPattern)
+	}
+	return &UpdateOwnershipHandler{catalog: reg, pattern: pattern}
+}
+
+// ServeHTTP implements http.Handler
+func (h *UpdateOwnershipHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/catalog/", "/ownership")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return
+	}
+	edit, err := catalog.ParseOwnershipEdit(data, h.pattern)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid ownership", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	caller := callerFromRequest(r, "")
+	node, err := h.catalog.UpdateOwnership(path, caller.UserID, edit)
+	if err != nil {
+		if _, ok := err.(*catalog.NodeNotFoundError); ok {
+			writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+				"path": path,
+			})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrInternal, "Internal server error", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"path":               path,
+		"ownership":          node.Ownership,
+		"resolved_ownership": h.catalog.ResolveOwnership(path),
+		"inherited_by":       h.catalog.OwnershipInheritors(path),
+	})
+}
+
+// QualityHandler handles GET and PUT /catalog/{path}/quality. GET returns
+// the node's own data quality and the one it resolves to through its
+// ancestors. PUT takes a catalog.QualityEdit; once a DQ owner is set, on the
+// node or an ancestor, only that caller may make it.
+type QualityHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewQualityHandler creates a new data quality handler
+func NewQualityHandler(reg *catalog.Registry) *QualityHandler {
+	return &QualityHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *QualityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/catalog/", "/quality")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
 	node := h.catalog.Get(path)
 	if node == nil {
-		writeError(w, http.StatusNotFound, "Node not found", map[string]interface{}{
+		writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
 			"path": path,
 		})
 		return
 	}
 
-	// Update status (simplified - in production would validate transitions)
-	oldStatus := node.Status
-	node.Status = newStatus
+	if r.Method == http.MethodPut {
+		data, ok := readJSONBody(w, r)
+		if !ok {
+			return
+		}
+		edit, err := catalog.ParseQualityEdit(data)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid quality", map[string]interface{}{
+				"detail": err.Error(),
+			})
+			return
+		}
 
-	response := map[string]interface{}{
-		"path":       path,
-		"old_status": string(oldStatus),
-		"new_status": string(newStatus),
-		"updated":    true,
+		caller := callerFromRequest(r, "")
+		if dq := h.catalog.ResolveDataQuality(path); dq != nil && dq.DQOwner != nil && *dq.DQOwner != caller.UserID {
+			writeError(w, http.StatusForbidden, ErrAccessDenied, "Not the DQ owner", map[string]interface{}{
+				"detail":   fmt.Sprintf("Only %s, the DQ owner of '%s', may change its data quality", *dq.DQOwner, path),
+				"dq_owner": *dq.DQOwner,
+			})
+			return
+		}
+
+		node, err = h.catalog.UpdateQuality(path, caller.UserID, edit)
+		if err != nil {
+			switch err.(type) {
+			case *catalog.NodeNotFoundError:
+				writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+					"path": path,
+				})
+			case *catalog.QualityEditError:
+				writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid quality", map[string]interface{}{
+					"detail": err.Error(),
+				})
+			default:
+				writeError(w, http.StatusInternalServerError, ErrInternal, "Internal server error", map[string]interface{}{
+					"detail": err.Error(),
+				})
+			}
+			return
+		}
 	}
 
-	writeJSON(w, http.StatusOK, response)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"path":                  path,
+		"data_quality":          node.DataQuality,
+		"resolved_data_quality": h.catalog.ResolveDataQuality(path),
+	})
 }
 
 // AuditLogHandler handles GET /catalog/{path}/audit
@@ -93,66 +425,433 @@ func NewAuditLogHandler(reg *catalog.Registry) *AuditLogHandler {
 
 // ServeHTTP implements http.Handler
 func (h *AuditLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/catalog/")
-	path = strings.TrimSuffix(path, "/audit")
+	path, err := pathFromRequest(r, "/catalog/", "/audit")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 50
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	offset := 0
+	if o, err := strconv.Atoi(query.Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+
+	var since *time.Time
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		t, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid since parameter", map[string]interface{}{
+				"detail": "since must be an RFC 3339 timestamp",
+			})
+			return
+		}
+		since = &t
+	}
+
+	entries := h.catalog.AuditEntries(path, 0, since)
+	total := len(entries)
+
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page := entries[offset:end]
 
-	// For now, return empty audit log (would be implemented with actual audit trail)
 	response := map[string]interface{}{
 		"path":    path,
-		"entries": []interface{}{},
-		"count":   0,
-		"message": "Audit log not yet implemented",
+		"entries": page,
+		"count":   len(page),
+		"total":   total,
+	}
+	if end < total {
+		response["next_offset"] = end
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
-// FetchDataHandler handles GET /fetch/{path}
+// FetchDataHandler handles GET /fetch/{path}, returning the moniker's rows
+// from the adapter of its source type. The default JSON object carries the
+// rows with the columns and their schema metadata; ?format=ndjson streams
+// that header as one object then a row per line, and ?format=csv a header
+// row then the rows, with the row count and truncation in response headers.
 type FetchDataHandler struct {
-	catalog *catalog.Registry
+	service *service.MonikerService
+}
+
+// fetchHeader is the first line of a ?format=ndjson fetch
+type fetchHeader struct {
+	Path           string                 `json:"path"`
+	SourceType     string                 `json:"source_type"`
+	Columns        []string               `json:"columns"`
+	ColumnMetadata []catalog.ColumnSchema `json:"column_metadata,omitempty"`
+	RowCount       int                    `json:"row_count"`
+	Truncated      bool                   `json:"truncated,omitempty"`
 }
 
 // NewFetchDataHandler creates a new fetch handler
-func NewFetchDataHandler(reg *catalog.Registry) *FetchDataHandler {
-	return &FetchDataHandler{catalog: reg}
+func NewFetchDataHandler(svc *service.MonikerService) *FetchDataHandler {
+	return &FetchDataHandler{service: svc}
 }
 
 // ServeHTTP implements http.Handler
 func (h *FetchDataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/fetch/")
+	path, err := pathFromRequest(r, "/fetch/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "ndjson" && format != "csv" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid format", map[string]interface{}{
+			"detail": "format must be 'json', 'ndjson' or 'csv'",
+		})
 		return
 	}
 
-	// This endpoint would actually fetch data from the source
-	// For now, return a placeholder
-	writeError(w, http.StatusNotImplemented, "Data fetch not implemented", map[string]interface{}{
-		"detail": "Server-side data fetch requires adapter implementation",
-		"path":   path,
-	})
+	caller := callerFromRequest(r, h.service.RolesHeader())
+	result, err := h.service.Fetch(r.Context(), path, caller)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	switch format {
+	case "ndjson":
+		setFetchHeaders(w, result, "application/x-ndjson")
+		// Headers are sent; a failure now can only cut the stream short
+		enc := json.NewEncoder(w)
+		if err := enc.Encode(fetchHeader{
+			Path:           result.Path,
+			SourceType:     result.SourceType,
+			Columns:        result.Columns,
+			ColumnMetadata: result.ColumnMetadata,
+			RowCount:       result.RowCount,
+			Truncated:      result.Truncated,
+		}); err != nil {
+			return
+		}
+		for _, row := range result.Rows {
+			if err := enc.Encode(row); err != nil {
+				return
+			}
+		}
+	case "csv":
+		setFetchHeaders(w, result, "text/csv; charset=utf-8")
+		writeRowsCSV(w, result.Columns, result.Rows)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
 }
 
-// RefreshCacheHandler handles POST /cache/refresh/{path}
+// setFetchHeaders starts a streamed fetch response of contentType
+func setFetchHeaders(w http.ResponseWriter, result *adapters.DataResult, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Row-Count", strconv.Itoa(result.RowCount))
+	if result.Truncated {
+		w.Header().Set("X-Rows-Truncated", "true")
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeRowsCSV writes rows as CSV under a header row of columns. Nil cells
+// are empty.
+func writeRowsCSV(w io.Writer, columns []string, rows []map[string]interface{}) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, name := range columns {
+			record[i] = csvCell(row[name])
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// VersionsHandler handles GET /versions/{path}
+type VersionsHandler struct {
+	service *service.MonikerService
+}
+
+// NewVersionsHandler creates a new versions handler
+func NewVersionsHandler(svc *service.MonikerService) *VersionsHandler {
+	return &VersionsHandler{service: svc}
+}
+
+// ServeHTTP implements http.Handler
+func (h *VersionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/versions/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+
+	caller := callerFromRequest(r, h.service.RolesHeader())
+	result, err := h.service.Versions(r.Context(), path, caller)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// RefreshCacheHandler handles POST /cache/refresh/{path}, which drops the
+// cached resolve results, listed versions and fetched data of the path and
+// everything beneath it, and POST /cache/refresh, which empties the cache. warm=true
+// then caches again the bindings in scope whose cache sets
+// refresh_on_startup (see service.MonikerService.WarmPath).
 type RefreshCacheHandler struct {
-	catalog *catalog.Registry
+	service *service.MonikerService
 }
 
 // NewRefreshCacheHandler creates a new cache refresh handler
-func NewRefreshCacheHandler(reg *catalog.Registry) *RefreshCacheHandler {
-	return &RefreshCacheHandler{catalog: reg}
+func NewRefreshCacheHandler(svc *service.MonikerService) *RefreshCacheHandler {
+	return &RefreshCacheHandler{service: svc}
 }
 
 // ServeHTTP implements http.Handler
 func (h *RefreshCacheHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/cache/refresh/")
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed, "Method not allowed", map[string]interface{}{
+			"detail": "Use POST to refresh the cache",
+		})
+		return
+	}
+	warm := false
+	if s := r.URL.Query().Get("warm"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid warm", map[string]interface{}{
+				"detail": fmt.Sprintf("warm must be true or false, got %q", s),
+			})
+			return
+		}
+		warm = b
+	}
 
+	path, err := pathFromRequest(r, "/cache/refresh/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 	response := map[string]interface{}{
-		"path":    path,
-		"status":  "ok",
-		"message": "Cache refresh triggered (placeholder)",
+		"status": "ok",
+	}
+	if path == "" {
+		response["scope"] = "all"
+		response["invalidated"] = h.service.InvalidateAll()
+	} else {
+		response["scope"] = "path"
+		response["path"] = path
+		response["invalidated"] = h.service.InvalidatePath(path)
+	}
+	response["warmed"] = 0
+	if warm {
+		result := h.service.WarmPath(r.Context(), path)
+		response["warmed"] = len(result.Warmed)
+		response["warmed_paths"] = result.Warmed
+		if len(result.Failed) > 0 {
+			response["warm_failures"] = result.Failed
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ImportCatalogHandler handles POST /catalog/import. A YAML or JSON body is a
+// whole catalog and replaces the live one; a text/csv or Excel workbook body
+// holds leaf nodes (see catalog.LoadCSV and catalog.LoadXLSX) and is added to
+// it. Spreadsheet rows share the ownership and classification given as query
+// parameters. Imports are previewed unless dry_run=false, and only applied
+// when the resulting catalog passes validation (see catalog.Registry.Import);
+// the validation report and diff are returned either way.
+type ImportCatalogHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewImportCatalogHandler creates a new catalog import handler
+func NewImportCatalogHandler(reg *catalog.Registry) *ImportCatalogHandler {
+	return &ImportCatalogHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ImportCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") != "false"
+
+	data, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var nodes []*catalog.CatalogNode
+	switch mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType {
+	case "text/csv":
+		rows, err := catalog.LoadCSV(bytes.NewReader(data), csvImportDefaults(r.URL.Query()))
+		if err != nil {
+			details := map[string]interface{}{"detail": err.Error()}
+			var csvErr *catalog.CSVImportError
+			if errors.As(err, &csvErr) {
+				details["rows"] = csvErr.Rows
+			}
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid CSV import", details)
+			return
+		}
+		nodes, _, _ = catalog.Merge(catalog.ConflictLastWins, h.catalog.AllNodes(), rows)
+	case catalog.XLSXContentType:
+		rows, err := catalog.LoadXLSX(bytes.NewReader(data), int64(len(data)), csvImportDefaults(r.URL.Query()))
+		if err != nil {
+			details := map[string]interface{}{"detail": err.Error()}
+			var xlsxErr *catalog.XLSXImportError
+			if errors.As(err, &xlsxErr) {
+				details["rows"] = xlsxErr.Rows
+			}
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid XLSX import", details)
+			return
+		}
+		nodes, _, _ = catalog.Merge(catalog.ConflictLastWins, h.catalog.AllNodes(), rows)
+	case "", "application/json", "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml", "text/plain":
+		parse := catalog.ParseCatalog
+		if mediaType == "application/json" {
+			parse = catalog.ParseCatalogJSON
+		}
+		var err error
+		if nodes, err = parse(data); err != nil {
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid catalog", map[string]interface{}{
+				"detail":     err.Error(),
+				"validation": catalog.LintLoadError(err),
+			})
+			return
+		}
+	default:
+		writeUnsupportedMediaType(w, r, "A catalog import must be YAML, JSON, CSV or an Excel workbook",
+			"application/x-yaml", "application/json", "text/csv", catalog.XLSXContentType)
+		return
+	}
+
+	result := h.catalog.Import(nodes, callerFromRequest(r, "").UserID, dryRun)
+	response := map[string]interface{}{
+		"dry_run":    dryRun,
+		"applied":    result.Applied,
+		"digest":     result.Digest,
+		"summary":    result.Diff.Summary(),
+		"diff":       result.Diff,
+		"validation": result.Validation,
+	}
+	if !dryRun && !result.Applied {
+		response["detail"] = fmt.Sprintf("Catalog has %d validation errors; the live catalog is unchanged", result.Validation.Errors)
+		writeError(w, http.StatusUnprocessableEntity, ErrValidationFailed, "Catalog failed validation", response)
+		return
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
+
+// csvImportDefaults builds the defaults applied to every CSV or XLSX row from the
+// classification, accountable_owner, data_specialist and support_channel
+// query parameters
+func csvImportDefaults(query url.Values) *catalog.CatalogNodeYAML {
+	defaults := &catalog.CatalogNodeYAML{Classification: query.Get("classification")}
+	ownership := &catalog.OwnershipYAML{}
+	for param, field := range map[string]**string{
+		"accountable_owner": &ownership.AccountableOwner,
+		"data_specialist":   &ownership.DataSpecialist,
+		"support_channel":   &ownership.SupportChannel,
+	} {
+		if v := query.Get(param); v != "" {
+			*field = &v
+			defaults.Ownership = ownership
+		}
+	}
+	return defaults
+}
+
+// ReloadCatalogHandler handles POST /admin/reload
+type ReloadCatalogHandler struct {
+	reloader *catalog.ReloadManager
+}
+
+// NewReloadCatalogHandler creates a new manual reload handler
+func NewReloadCatalogHandler(m *catalog.ReloadManager) *ReloadCatalogHandler {
+	return &ReloadCatalogHandler{reloader: m}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ReloadCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed, "Method not allowed", map[string]interface{}{
+			"detail": "Use POST to trigger a catalog reload",
+		})
+		return
+	}
+
+	diff, err := h.reloader.Reload()
+	if err != nil {
+		// The live catalog is unchanged
+		writeError(w, http.StatusUnprocessableEntity, ErrValidationFailed, "Catalog reload failed", map[string]interface{}{
+			"detail": err.Error(),
+			"status": h.reloader.Status(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reloaded": !diff.IsEmpty(),
+		"summary":  diff.Summary(),
+		"diff":     diff,
+		"status":   h.reloader.Status(),
+	})
+}
+
+// ReloadStatusHandler handles GET /admin/reload/status
+type ReloadStatusHandler struct {
+	reloader *catalog.ReloadManager
+}
+
+// NewReloadStatusHandler creates a new reload status handler
+func NewReloadStatusHandler(m *catalog.ReloadManager) *ReloadStatusHandler {
+	return &ReloadStatusHandler{reloader: m}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ReloadStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.reloader.Status())
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/auth"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// TokenValidator validates bearer tokens; *auth.JWTValidator implements it
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (*service.CallerIdentity, error)
+}
+
+// AuthMiddleware establishes the caller of each request and stores it in the
+// request context, where handlers read it. With no validator (auth off) the
+// caller comes from the X-User-ID and roles headers. With one, a bearer token
+// is required when enforce is set, and otherwise requests without one are
+// anonymous; headers are never trusted. Invalid or expired tokens get 401.
+//
+// With API keys (see WithAPIKeys) a key in X-API-Key or Authorization: ApiKey
+// authenticates the caller as the key's ID, and the key must hold the scope
+// RequiredScope gives the route, or the request gets 403. Headers are not
+// trusted then either.
+type AuthMiddleware struct {
+	next        http.Handler
+	validator   TokenValidator
+	apiKeys     TokenValidator
+	enforce     bool
+	rolesHeader string
+}
+
+// NewAuthMiddleware wraps next. validator may be nil to use header identities.
+func NewAuthMiddleware(next http.Handler, validator TokenValidator, enforce bool, rolesHeader string) *AuthMiddleware {
+	return &AuthMiddleware{next: next, validator: validator, enforce: enforce, rolesHeader: rolesHeader}
+}
+
+// WithAPIKeys also accepts the API keys keys validates, e.g. an
+// *auth.APIKeySet, and returns m
+func (m *AuthMiddleware) WithAPIKeys(keys TokenValidator) *AuthMiddleware {
+	m.apiKeys = keys
+	return m
+}
+
+// ServeHTTP implements http.Handler
+func (m *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var caller *service.CallerIdentity
+	if r.Method == http.MethodOptions {
+		// CORS preflights carry no credentials, and routes only answer them
+		// with the methods they allow
+		caller = &service.CallerIdentity{UserID: service.AnonymousUser, Source: "anonymous"}
+	} else if key, ok := apiKey(r); ok && m.apiKeys != nil {
+		var err error
+		caller, err = m.apiKeys.Validate(r.Context(), key)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "ApiKey")
+			writeError(w, http.StatusUnauthorized, ErrUnauthenticated, "Invalid API key", map[string]interface{}{
+				"detail": "The API key is not recognized",
+			})
+			return
+		}
+		if scope := RequiredScope(r); !hasScope(caller, scope) {
+			writeError(w, http.StatusForbidden, ErrAccessDenied, "Insufficient scope", map[string]interface{}{
+				"detail":         fmt.Sprintf("API key %q lacks the %s scope this route requires", caller.UserID, scope),
+				"required_scope": scope,
+				"scopes":         caller.Scopes,
+			})
+			return
+		}
+	} else if m.validator == nil && m.apiKeys == nil {
+		caller = callerFromHeaders(r, m.rolesHeader)
+	} else if token, ok := bearerToken(r); ok && m.validator != nil {
+		var err error
+		caller, err = m.validator.Validate(r.Context(), token)
+		var tokenErr *auth.TokenError
+		if errors.As(err, &tokenErr) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, tokenErr.Reason))
+			writeError(w, http.StatusUnauthorized, ErrUnauthenticated, "Invalid token", map[string]interface{}{
+				"detail": tokenErr.Reason,
+			})
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, ErrUnavailable, "Authentication unavailable", map[string]interface{}{
+				"detail": err.Error(),
+			})
+			return
+		}
+	} else if m.enforce {
+		challenge, detail := m.Challenge()
+		w.Header().Set("WWW-Authenticate", challenge)
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated, "Authentication required", map[string]interface{}{
+			"detail": detail,
+		})
+		return
+	} else {
+		caller = &service.CallerIdentity{UserID: service.AnonymousUser, Source: "anonymous"}
+	}
+	m.next.ServeHTTP(w, r.WithContext(service.WithCaller(r.Context(), caller)))
+}
+
+// Challenge returns the WWW-Authenticate challenge of the schemes m accepts,
+// empty with header identities, and a detail telling callers how to
+// authenticate
+func (m *AuthMiddleware) Challenge() (challenge, detail string) {
+	challenges, detail := make([]string, 0, 2), "Send a bearer token in the Authorization header"
+	if m.validator != nil {
+		challenges = append(challenges, "Bearer")
+	}
+	if m.apiKeys != nil {
+		challenges = append(challenges, "ApiKey")
+		detail = "Send an API key in the X-API-Key header"
+		if m.validator != nil {
+			detail = "Send a bearer token in the Authorization header or an API key in the X-API-Key header"
+		}
+	}
+	if len(challenges) == 0 {
+		detail = "Send your user ID in the X-User-ID header"
+	}
+	return strings.Join(challenges, ", "), detail
+}
+
+// RequiredScope returns the API key scope a request needs: admin to change
+// the catalog (creating, editing, importing, reloading) or refresh the cache,
+// resolve to resolve, estimate or fetch monikers, and read for the rest
+func RequiredScope(r *http.Request) string {
+	path := r.URL.Path
+	readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+	switch {
+	case AdminRouteGroup(r) != "":
+		return auth.ScopeAdmin
+	case path == "/catalog" || strings.HasPrefix(path, "/catalog/"):
+		if !readOnly {
+			return auth.ScopeAdmin
+		}
+	case path == "/admin/reload/status":
+		return auth.ScopeRead
+	case strings.HasPrefix(path, "/admin/"):
+		return auth.ScopeAdmin
+	case strings.HasPrefix(path, "/resolve/"), strings.HasPrefix(path, "/fetch/"),
+		strings.HasPrefix(path, "/versions/"), strings.HasPrefix(path, "/estimate/"):
+		return auth.ScopeResolve
+	}
+	return auth.ScopeRead
+}
+
+func hasScope(caller *service.CallerIdentity, scope string) bool {
+	for _, s := range caller.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKey returns the key of an X-API-Key or Authorization: ApiKey header
+func apiKey(r *http.Request) (string, bool) {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key, true
+	}
+	scheme, key, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "ApiKey") {
+		return "", false
+	}
+	key = strings.TrimSpace(key)
+	return key, key != ""
+}
+
+// bearerToken returns the token of an Authorization: Bearer header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
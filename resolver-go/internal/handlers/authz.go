@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// Admin route groups, the keys of auth.admin_roles. A group not listed
+// there needs the roles of AdminGroupDefault.
+const (
+	AdminGroupCatalog   = "catalog"   // Creating and editing nodes
+	AdminGroupStatus    = "status"    // PUT /catalog/{path}/status
+	AdminGroupOwnership = "ownership" // PUT /catalog/{path}/ownership
+	AdminGroupQuality   = "quality"   // PUT /catalog/{path}/quality
+	AdminGroupImport    = "import"    // POST /catalog/import
+	AdminGroupReload    = "reload"    // POST /admin/reload
+	AdminGroupCache     = "cache"     // POST /cache/refresh and /cache/refresh/{path}
+	AdminGroupDefault   = "default"
+)
+
+// AdminGroups lists the valid keys of auth.admin_roles
+func AdminGroups() []string {
+	return []string{AdminGroupCatalog, AdminGroupStatus, AdminGroupOwnership, AdminGroupQuality,
+		AdminGroupImport, AdminGroupReload, AdminGroupCache, AdminGroupDefault}
+}
+
+// AdminRouteGroup returns the admin group of a request, or "" when it
+// changes nothing and any caller may make it
+func AdminRouteGroup(r *http.Request) string {
+	// Trailing slashes are dropped as the handlers drop them (pathFromRequest)
+	path := strings.TrimRight(r.URL.Path, "/")
+	readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+	switch {
+	case path == "/catalog/import":
+		return AdminGroupImport
+	case path == "/catalog" || strings.HasPrefix(path, "/catalog/"):
+		switch {
+		case readOnly:
+			return ""
+		case r.Method == http.MethodPut && strings.HasSuffix(path, "/status"):
+			return AdminGroupStatus
+		case r.Method == http.MethodPut && strings.HasSuffix(path, "/ownership"):
+			return AdminGroupOwnership
+		case r.Method == http.MethodPut && strings.HasSuffix(path, "/quality"):
+			return AdminGroupQuality
+		}
+		return AdminGroupCatalog
+	case path == "/admin/reload":
+		return AdminGroupReload
+	case path == "/cache/refresh" || strings.HasPrefix(path, "/cache/refresh/"):
+		return AdminGroupCache
+	}
+	return ""
+}
+
+// AdminMiddleware guards the admin routes with the roles auth.admin_roles
+// maps their groups to. It reads the caller AuthMiddleware stored, so it goes
+// inside it. Anonymous callers get 401 and callers holding none of the
+// group's roles 403, and every refusal is audited as admin_denied. A group
+// with no roles, listed or by default, only needs an authenticated caller.
+type AdminMiddleware struct {
+	next      http.Handler
+	roles     map[string][]string
+	service   *service.MonikerService
+	challenge string
+	detail    string
+}
+
+// NewAdminMiddleware wraps next, checking that roles only names known groups
+func NewAdminMiddleware(next http.Handler, roles map[string][]string, svc *service.MonikerService) (*AdminMiddleware, error) {
+	known := make(map[string]bool)
+	for _, group := range AdminGroups() {
+		known[group] = true
+	}
+	for group := range roles {
+		if !known[group] {
+			return nil, fmt.Errorf("auth.admin_roles: unknown route group %q (want one of %s)", group, strings.Join(AdminGroups(), ", "))
+		}
+	}
+	return &AdminMiddleware{next: next, roles: roles, service: svc, detail: "Admin routes need an authenticated caller"}, nil
+}
+
+// WithChallenge sets the WWW-Authenticate challenge and detail of a 401, as
+// AuthMiddleware.Challenge gives them, and returns m
+func (m *AdminMiddleware) WithChallenge(challenge, detail string) *AdminMiddleware {
+	m.challenge, m.detail = challenge, detail
+	return m
+}
+
+// ServeHTTP implements http.Handler
+func (m *AdminMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	group := AdminRouteGroup(r)
+	if group == "" {
+		m.next.ServeHTTP(w, r)
+		return
+	}
+	route := r.Method + " " + r.URL.Path
+	caller := service.CallerFromContext(r.Context())
+	if caller == nil || caller.UserID == "" || caller.UserID == service.AnonymousUser {
+		m.service.AuditAdminDenied(route, group, "not authenticated", caller)
+		if m.challenge != "" {
+			w.Header().Set("WWW-Authenticate", m.challenge)
+		}
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated, "Authentication required", map[string]interface{}{
+			"detail": m.detail,
+			"group":  group,
+		})
+		return
+	}
+
+	required := m.requiredRoles(group)
+	if len(required) > 0 && !holdsAnyRole(caller, required) {
+		m.service.AuditAdminDenied(route, group, "caller holds none of: "+strings.Join(required, ", "), caller)
+		writeError(w, http.StatusForbidden, ErrAccessDenied, "Insufficient role", map[string]interface{}{
+			"detail":         fmt.Sprintf("%s routes require one of the roles: %s", group, strings.Join(required, ", ")),
+			"group":          group,
+			"required_roles": required,
+		})
+		return
+	}
+	m.next.ServeHTTP(w, r)
+}
+
+// requiredRoles returns the roles of group, or of the default group when it
+// is not listed
+func (m *AdminMiddleware) requiredRoles(group string) []string {
+	roles, ok := m.roles[group]
+	if !ok {
+		roles = m.roles[AdminGroupDefault]
+	}
+	roles = append([]string(nil), roles...)
+	sort.Strings(roles)
+	return roles
+}
+
+func holdsAnyRole(caller *service.CallerIdentity, roles []string) bool {
+	for _, held := range caller.Roles {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
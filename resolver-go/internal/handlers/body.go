@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Default request body limits, in bytes
+const (
+	DefaultBodyBytes          = 1 << 20  // Catalog edits and admin requests
+	DefaultBatchBodyBytes     = 1 << 20  // Batch resolve and describe
+	DefaultTelemetryBodyBytes = 64 << 10 // One access event
+	DefaultImportBodyBytes    = 32 << 20 // Whole catalogs and spreadsheets
+)
+
+// BodyLimits caps the request bodies of each group of endpoints; a field
+// left at 0 takes its default
+type BodyLimits struct {
+	Default   int64
+	Batch     int64
+	Telemetry int64
+	Import    int64
+}
+
+// withDefaults returns l with the unset limits filled in
+func (l BodyLimits) withDefaults() BodyLimits {
+	if l.Default <= 0 {
+		l.Default = DefaultBodyBytes
+	}
+	if l.Batch <= 0 {
+		l.Batch = DefaultBatchBodyBytes
+	}
+	if l.Telemetry <= 0 {
+		l.Telemetry = DefaultTelemetryBodyBytes
+	}
+	if l.Import <= 0 {
+		l.Import = DefaultImportBodyBytes
+	}
+	return l
+}
+
+// limitBody caps the body of every request to next at limit bytes. A
+// Content-Length over the limit is refused before next runs; a body that
+// grows past it fails the read, which readBody answers with the same 413.
+func limitBody(limit int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			writeBodyTooLarge(w, limit)
+			return
+		}
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	writeError(w, http.StatusRequestEntityTooLarge, ErrPayloadTooLarge, "Request body too large", map[string]interface{}{
+		"detail":    fmt.Sprintf("Request bodies of this endpoint are limited to %d bytes", limit),
+		"max_bytes": limit,
+	})
+}
+
+// writeUnsupportedMediaType answers a request whose Content-Type the
+// endpoint doesn't take with a 415 listing the ones it does
+func writeUnsupportedMediaType(w http.ResponseWriter, r *http.Request, detail string, supported ...string) {
+	writeError(w, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType, "Unsupported media type", map[string]interface{}{
+		"detail":       detail,
+		"content_type": r.Header.Get("Content-Type"),
+		"supported":    supported,
+	})
+}
+
+// readBody reads the whole request body. A body over the route's limit is a
+// 413 and any other failure a 400, written to w.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeBodyTooLarge(w, tooLarge.Limit)
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid request body", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return nil, false
+	}
+	return data, true
+}
+
+// readJSONBody reads a request body that must be JSON: a Content-Type other
+// than application/json (or a +json type) is a 415 written to w. Requests
+// without a Content-Type are taken to be JSON.
+func readJSONBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			writeUnsupportedMediaType(w, r, "The request body must be JSON", "application/json")
+			return nil, false
+		}
+	}
+	return readBody(w, r)
+}
+
+// decodeJSONBody decodes a JSON request body into v, answering 413, 415 or
+// 400 on w when it can't. A strict decode refuses fields v doesn't have, so a
+// misspelled field is an error rather than silently ignored.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}, strict bool) bool {
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	err := dec.Decode(v)
+	if err == nil && dec.More() {
+		err = errors.New("unexpected data after the JSON value")
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid request body", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return false
+	}
+	return true
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/metrics"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/telemetry"
 )
 
 // CatalogListHandler handles GET /catalog
@@ -22,54 +28,61 @@ func NewCatalogListHandler(svc *service.MonikerService, reg *catalog.Registry) *
 	return &CatalogListHandler{service: svc, catalog: reg}
 }
 
-// ServeHTTP implements http.Handler
+// ServeHTTP implements http.Handler. Paths are listed in order; next_cursor
+// is the last path of the page and the next page starts strictly after it.
+// The response has an ETag; If-None-Match with it gets a 304 until the
+// catalog changes.
+//
+// As CSV (catalog.NodeRowColumns) or NDJSON (a catalog.NodeRow per line) the
+// nodes themselves are listed, every one after cursor unless limit is given;
+// X-Next-Cursor is set when more follow.
 func (h *CatalogListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	if notModified(w, r, catalogETag(h.catalog, r)) {
+		return
+	}
+
 	// Get query parameters
 	cursor := r.URL.Query().Get("cursor")
 	limitStr := r.URL.Query().Get("limit")
 	_ = r.URL.Query().Get("status") // statusFilter - TODO: implement filtering
 
 	limit := 100
+	if format != formatJSON {
+		limit = math.MaxInt
+	}
 	if limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
 			limit = l
 		}
 	}
 
-	// Get all paths (simplified - no real pagination yet)
-	allPaths := h.catalog.AllPaths()
-
-	// Sort and paginate
-	startIdx := 0
-	if cursor != "" {
-		for i, p := range allPaths {
-			if p > cursor {
-				startIdx = i
-				break
-			}
+	paths, total, more := h.catalog.PathsAfter(cursor, limit)
+	if format != formatJSON {
+		if more {
+			w.Header().Set("X-Next-Cursor", paths[len(paths)-1])
 		}
-	}
-
-	endIdx := startIdx + limit
-	if endIdx > len(allPaths) {
-		endIdx = len(allPaths)
-	}
-
-	paths := allPaths[startIdx:endIdx]
-
-	var nextCursor *string
-	if endIdx < len(allPaths) {
-		nc := allPaths[endIdx-1]
-		nextCursor = &nc
+		rows := h.catalog.NodeRows(paths)
+		if format == formatNDJSON {
+			writeNDJSON(w, rows)
+			return
+		}
+		startDownload(w, "catalog", time.Now())
+		// Headers are sent; a failure now can only cut the download short
+		catalog.WriteNodeRowsCSV(w, rows)
+		return
 	}
 
 	response := map[string]interface{}{
 		"paths": paths,
 		"count": len(paths),
-		"total": len(allPaths),
+		"total": total,
 	}
-	if nextCursor != nil {
-		response["next_cursor"] = *nextCursor
+	if more {
+		response["next_cursor"] = paths[len(paths)-1]
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -85,28 +98,119 @@ func NewSearchCatalogHandler(reg *catalog.Registry) *SearchCatalogHandler {
 	return &SearchCatalogHandler{catalog: reg}
 }
 
-// ServeHTTP implements http.Handler
+// ServeHTTP implements http.Handler. Results match q (if given) and every
+// filter: status, source_type, tag (repeatable; all must match),
+// classification, domain, is_leaf and min_quality_score. Without q at least
+// one filter is required, which browses the catalog by facet. As CSV the
+// results are catalog.NodeRowColumns rows; as NDJSON, a node per line.
 func (h *SearchCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		writeError(w, http.StatusBadRequest, "Missing query parameter", map[string]interface{}{
-			"detail": "Query parameter 'q' is required",
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	params := r.URL.Query()
+	query := params.Get("q")
+
+	opts := catalog.SearchOptions{
+		Limit:                50,
+		IncludeInheritedTags: params.Get("include_inherited_tags") == "true",
+		Tags:                 params["tag"],
+		Classification:       params.Get("classification"),
+		Domain:               params.Get("domain"),
+	}
+	if l, err := strconv.Atoi(params.Get("limit")); err == nil && l > 0 {
+		opts.Limit = l
+	}
+	filters := map[string]interface{}{}
+	if s := params.Get("status"); s != "" {
+		status := catalog.NodeStatus(s)
+		if !status.IsValid() {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid status", map[string]interface{}{
+				"detail": fmt.Sprintf("Unknown status %q", s),
+				"valid":  catalog.NodeStatuses(),
+			})
+			return
+		}
+		opts.Status = &status
+		filters["status"] = status
+	}
+	if s := params.Get("source_type"); s != "" {
+		sourceType := catalog.SourceType(s)
+		valid := catalog.SourceTypes()
+		known := false
+		for _, t := range valid {
+			known = known || t == sourceType
+		}
+		if !known {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid source_type", map[string]interface{}{
+				"detail": fmt.Sprintf("Unknown source type %q", s),
+				"valid":  valid,
+			})
+			return
+		}
+		opts.SourceType = &sourceType
+		filters["source_type"] = sourceType
+	}
+	if s := params.Get("is_leaf"); s != "" {
+		isLeaf, err := strconv.ParseBool(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid is_leaf", map[string]interface{}{
+				"detail": fmt.Sprintf("is_leaf must be true or false, got %q", s),
+				"valid":  []bool{true, false},
+			})
+			return
+		}
+		opts.IsLeaf = &isLeaf
+		filters["is_leaf"] = isLeaf
+	}
+	if s := params.Get("min_quality_score"); s != "" {
+		score, err := strconv.ParseFloat(s, 64)
+		if err != nil || score < 0 || score > 1 {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid min_quality_score", map[string]interface{}{
+				"detail": fmt.Sprintf("min_quality_score must be a number between 0 and 1, got %q", s),
+			})
+			return
+		}
+		opts.MinQualityScore = &score
+		filters["min_quality_score"] = score
+	}
+	if len(opts.Tags) > 0 {
+		filters["tag"] = opts.Tags
+	}
+	if opts.Classification != "" {
+		filters["classification"] = opts.Classification
+	}
+	if opts.Domain != "" {
+		filters["domain"] = opts.Domain
+	}
+
+	if query == "" && len(filters) == 0 {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing query parameter", map[string]interface{}{
+			"detail": "Query parameter 'q' or a filter is required",
 		})
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 50
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
+	results := h.catalog.SearchWithOptions(query, opts)
+	switch format {
+	case formatCSV:
+		paths := make([]string, len(results))
+		for i, node := range results {
+			paths[i] = node.Path
 		}
+		startDownload(w, "search", time.Now())
+		// Headers are sent; a failure now can only cut the download short
+		catalog.WriteNodeRowsCSV(w, h.catalog.NodeRows(paths))
+		return
+	case formatNDJSON:
+		writeNDJSON(w, results)
+		return
 	}
 
-	results := h.catalog.Search(query, nil, limit)
-
 	response := map[string]interface{}{
 		"query":   query,
+		"filters": filters,
+		"limit":   opts.Limit,
 		"results": results,
 		"count":   len(results),
 	}
@@ -117,11 +221,16 @@ func (h *SearchCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 // CatalogStatsHandler handles GET /catalog/stats
 type CatalogStatsHandler struct {
 	catalog *catalog.Registry
+	metrics *metrics.Registry
 }
 
-// NewCatalogStatsHandler creates a new stats handler
-func NewCatalogStatsHandler(reg *catalog.Registry) *CatalogStatsHandler {
-	return &CatalogStatsHandler{catalog: reg}
+// topResolvedPaths is how many path prefixes /catalog/stats lists
+const topResolvedPaths = 20
+
+// NewCatalogStatsHandler creates a new stats handler. When m is non-nil the
+// response also summarizes resolution metrics.
+func NewCatalogStatsHandler(reg *catalog.Registry, m *metrics.Registry) *CatalogStatsHandler {
+	return &CatalogStatsHandler{catalog: reg, metrics: m}
 }
 
 // ServeHTTP implements http.Handler
@@ -141,10 +250,311 @@ func (h *CatalogStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		"by_status":      counts,
 		"by_source_type": sourceTypeCounts,
 	}
+	if h.metrics != nil {
+		response["top_resolved"] = h.metrics.TopPaths(topResolvedPaths)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ValidateCatalogHandler handles GET /catalog/validate
+type ValidateCatalogHandler struct {
+	catalog *catalog.Registry
+	now     func() time.Time
+}
+
+// NewValidateCatalogHandler creates a new catalog validation handler
+func NewValidateCatalogHandler(reg *catalog.Registry) *ValidateCatalogHandler {
+	return &ValidateCatalogHandler{catalog: reg, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler. The response is the
+// catalog.ValidationReport of the live catalog, of the subtree at path_prefix
+// if given; warnings don't invalidate it. The status is 200 unless fail_level
+// is error or warning and the report has issues of that severity or worse,
+// which is a 409, so CI can key off it.
+func (h *ValidateCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	failLevel := r.URL.Query().Get("fail_level")
+	switch failLevel {
+	case "":
+		failLevel = "none"
+	case "none", catalog.SeverityError, catalog.SeverityWarning:
+	default:
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid fail_level", map[string]interface{}{
+			"detail": "fail_level must be 'none', 'error' or 'warning'",
+		})
+		return
+	}
+
+	report := h.catalog.Validate(strings.Trim(r.URL.Query().Get("path_prefix"), "/"), h.now().UTC())
+
+	status := http.StatusOK
+	if (failLevel == catalog.SeverityError && report.Errors > 0) ||
+		(failLevel == catalog.SeverityWarning && report.Count > 0) {
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, report)
+}
+
+// GovernanceReportHandler handles GET /catalog/governance-report. As CSV or
+// NDJSON the response is the nodes with gaps, one per row or line.
+type GovernanceReportHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewGovernanceReportHandler creates a new governance report handler
+func NewGovernanceReportHandler(reg *catalog.Registry) *GovernanceReportHandler {
+	return &GovernanceReportHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *GovernanceReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+	report := h.catalog.GovernanceReport()
+	domain := r.URL.Query().Get("domain")
+	if domain != "" {
+		report = report.FilterDomain(domain)
+	}
+	switch format {
+	case formatCSV:
+		startDownload(w, "governance-report", time.Now())
+		// Headers are sent; a failure now can only cut the download short
+		catalog.WriteGovernanceCSV(w, report.Nodes)
+		return
+	case formatNDJSON:
+		writeNDJSON(w, report.Nodes)
+		return
+	}
+
+	response := map[string]interface{}{
+		"counts": map[string]int{
+			"active_nodes":                 report.ActiveNodes,
+			"nodes_with_gaps":              len(report.Nodes),
+			"incomplete_ownership":         len(report.IncompleteOwnership),
+			"missing_governance_roles":     len(report.MissingGovernanceRoles),
+			"missing_classification":       len(report.MissingClassification),
+			"missing_schema":               len(report.MissingSchema),
+			"deprecated_without_successor": len(report.DeprecatedWithoutSuccessor),
+		},
+		"incomplete_ownership":         report.IncompleteOwnership,
+		"missing_governance_roles":     report.MissingGovernanceRoles,
+		"missing_classification":       report.MissingClassification,
+		"missing_schema":               report.MissingSchema,
+		"deprecated_without_successor": report.DeprecatedWithoutSuccessor,
+		"nodes":                        report.Nodes,
+	}
+	if domain != "" {
+		response["domain"] = domain
+	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
+// DeprecationsHandler handles GET /deprecations: every deprecated node with
+// its successor, sunset deadline and days remaining, by deadline with undated
+// nodes last, and the nodes expired or expiring within the window. domain and
+// owner narrow both; expiring_within, when given, also narrows the list to
+// deadlines within it. format=csv (or Accept: text/csv) downloads the list as
+// a spreadsheet and format=ndjson streams an entry per line.
+type DeprecationsHandler struct {
+	catalog       *catalog.Registry
+	defaultWindow time.Duration
+	now           func() time.Time
+}
+
+// NewDeprecationsHandler creates a new deprecations handler. defaultWindow is
+// used when the request does not set expiring_within.
+func NewDeprecationsHandler(reg *catalog.Registry, defaultWindow time.Duration) *DeprecationsHandler {
+	return &DeprecationsHandler{catalog: reg, defaultWindow: defaultWindow, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler
+func (h *DeprecationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	filter := catalog.DeprecationFilter{Domain: query.Get("domain"), Owner: query.Get("owner")}
+	window := h.defaultWindow
+	if s := query.Get("expiring_within"); s != "" {
+		d, err := parseWindow(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid expiring_within", map[string]interface{}{
+				"detail": err.Error(),
+			})
+			return
+		}
+		window = d
+		filter.Within = &d
+	}
+
+	now := h.now().UTC()
+	entries := h.catalog.DeprecationReport(now, filter)
+	switch format {
+	case formatCSV:
+		startDownload(w, "deprecations", now)
+		// Headers are sent; a failure now can only cut the download short
+		catalog.WriteDeprecationsCSV(w, entries)
+		return
+	case formatNDJSON:
+		writeNDJSON(w, entries)
+		return
+	}
+
+	// expired and expiring honour domain and owner, whatever the window
+	matching := make(map[string]bool)
+	for _, e := range h.catalog.DeprecationReport(now, catalog.DeprecationFilter{Domain: filter.Domain, Owner: filter.Owner}) {
+		matching[e.Path] = true
+	}
+	expired := sunsetEntries(h.catalog.ExpiredSunsets(now), matching)
+	expiring := sunsetEntries(h.catalog.ExpiringSunsets(now, window), matching)
+
+	response := map[string]interface{}{
+		"deprecations":    entries,
+		"count":           len(entries),
+		"expired":         expired,
+		"expiring":        expiring,
+		"expiring_within": formatWindow(window),
+		"invalid":         h.catalog.ValidateSunsets(),
+		"deprecated":      len(h.catalog.FindDeprecated()),
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// sunsetEntries describes the nodes whose paths are in matching
+func sunsetEntries(nodes []*catalog.CatalogNode, matching map[string]bool) []map[string]interface{} {
+	entries := make([]map[string]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		if !matching[node.Path] {
+			continue
+		}
+		entry := map[string]interface{}{
+			"path":            node.Path,
+			"display_name":    node.DisplayName,
+			"sunset_deadline": *node.SunsetDeadline,
+		}
+		if node.Successor != nil {
+			entry["successor"] = *node.Successor
+		}
+		if node.MigrationGuideURL != nil {
+			entry["migration_guide_url"] = *node.MigrationGuideURL
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseWindow parses a window such as "30d", "12h", or a bare number of days
+func parseWindow(s string) (time.Duration, error) {
+	days := strings.TrimSuffix(s, "d")
+	if n, err := strconv.Atoi(days); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("window must not be negative: %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("expected a window like '30d' or '12h', got %q", s)
+	}
+	return d, nil
+}
+
+func formatWindow(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+// ExportCatalogHandler handles GET /catalog/export. With ?path= only that
+// node is exported, with ?path_prefix= the node there and everything beneath
+// it, as served: after any environment overlay and runtime changes. The
+// output is a download named after the catalog digest and export time.
+type ExportCatalogHandler struct {
+	catalog *catalog.Registry
+	now     func() time.Time
+}
+
+// NewExportCatalogHandler creates a new catalog export handler
+func NewExportCatalogHandler(reg *catalog.Registry) *ExportCatalogHandler {
+	return &ExportCatalogHandler{catalog: reg, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ExportCatalogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "yaml"
+	}
+	style, err := catalog.ParseExportStyle(query.Get("style"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid style", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	var write func(io.Writer, []*catalog.CatalogNode, catalog.ExportStyle) error
+	var contentType, ext string
+	switch format {
+	case "yaml", "yml":
+		write, contentType, ext = catalog.WriteNodesYAML, "application/x-yaml", "yaml"
+	case "json":
+		write, contentType, ext = catalog.WriteNodesJSON, "application/json", "json"
+	default:
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid format", map[string]interface{}{
+			"detail": "format must be 'yaml' or 'json'",
+		})
+		return
+	}
+
+	prefix := strings.Trim(query.Get("path_prefix"), "/")
+	nodes, digest := h.catalog.ExportNodes(prefix)
+	if prefix != "" && len(nodes) == 0 {
+		writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+			"path_prefix": prefix,
+		})
+		return
+	}
+	if path := strings.Trim(query.Get("path"), "/"); path != "" {
+		node := h.catalog.Get(path)
+		if node == nil {
+			writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+				"path": path,
+			})
+			return
+		}
+		nodes = []*catalog.CatalogNode{node}
+	}
+
+	filename := fmt.Sprintf("catalog-%s-%s.%s", shortDigest(digest), h.now().UTC().Format("20060102T150405Z"), ext)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("X-Catalog-Digest", digest)
+	w.WriteHeader(http.StatusOK)
+	// Headers are sent; a failure now can only cut the download short
+	write(w, nodes, style)
+}
+
+// shortDigest abbreviates a catalog digest for use in file names
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+// maxBatchSize bounds the monikers or paths of a batch request
+const maxBatchSize = 100
+
 // BatchResolveHandler handles POST /resolve/batch
 type BatchResolveHandler struct {
 	service *service.MonikerService
@@ -160,59 +570,175 @@ func (h *BatchResolveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	var request struct {
 		Monikers []string `json:"monikers"`
 	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body", map[string]interface{}{
-			"detail": err.Error(),
-		})
+	if !decodeBatchRequest(w, r, &request, func() int { return len(request.Monikers) }, "moniker") {
 		return
 	}
 
-	if len(request.Monikers) == 0 {
-		writeError(w, http.StatusBadRequest, "Empty moniker list", nil)
-		return
+	// Get caller identity
+	caller := callerFromRequest(r, h.service.RolesHeader())
+
+	// Resolve all monikers; items not resolved before the client went away
+	// report the cancellation
+	start := time.Now()
+	items := h.service.ResolveBatch(r.Context(), request.Monikers, caller, service.ResolveOptions{})
+	results := make([]interface{}, len(items))
+	failed := 0
+	for i, item := range items {
+		if item.Err == nil {
+			results[i] = BatchResult{ResolveResult: item.Result, Status: BatchStatusOK}
+			continue
+		}
+		failed++
+		results[i] = batchFailure("moniker", item.Moniker, item.Err)
 	}
+	writeBatchResponse(w, results, failed, start)
+}
 
-	if len(request.Monikers) > 100 {
-		writeError(w, http.StatusBadRequest, "Too many monikers", map[string]interface{}{
-			"detail": "Maximum 100 monikers per batch request",
-			"count":  len(request.Monikers),
-		})
+// DescribeBatchHandler handles POST /describe/batch
+type DescribeBatchHandler struct {
+	service *service.MonikerService
+}
+
+// NewDescribeBatchHandler creates a new batch describe handler
+func NewDescribeBatchHandler(svc *service.MonikerService) *DescribeBatchHandler {
+	return &DescribeBatchHandler{service: svc}
+}
+
+// ServeHTTP implements http.Handler. Paths are described as GET /describe
+// would, all from one snapshot of the catalog, with the limits and response
+// of POST /resolve/batch; a path not in the catalog is a not_found item.
+func (h *DescribeBatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Paths []string `json:"paths"`
+	}
+	if !decodeBatchRequest(w, r, &request, func() int { return len(request.Paths) }, "path") {
 		return
 	}
 
-	// Get caller identity
-	caller := &service.CallerIdentity{
-		UserID: r.Header.Get("X-User-ID"),
-		Source: "api",
+	start := time.Now()
+	items := h.service.DescribeBatch(r.Context(), request.Paths)
+	results := make([]interface{}, len(items))
+	failed := 0
+	for i, item := range items {
+		if item.Err == nil {
+			results[i] = DescribeBatchResult{DescribeResult: item.Result, Status: BatchStatusOK}
+			continue
+		}
+		failed++
+		results[i] = batchFailure("path", item.Path, item.Err)
 	}
-	if caller.UserID == "" {
-		caller.UserID = "anonymous"
+	writeBatchResponse(w, results, failed, start)
+}
+
+// decodeBatchRequest decodes the body of a batch request into request and
+// checks it lists between 1 and maxBatchSize items, as count gives them.
+// Otherwise it answers 400 and returns false.
+func decodeBatchRequest(w http.ResponseWriter, r *http.Request, request interface{}, count func() int, item string) bool {
+	if !decodeJSONBody(w, r, request, true) {
+		return false
 	}
 
-	// Resolve all monikers (could parallelize with goroutines)
-	results := make([]interface{}, len(request.Monikers))
-	for i, monikerStr := range request.Monikers {
-		result, err := h.service.Resolve(r.Context(), monikerStr, caller)
-		if err != nil {
-			results[i] = map[string]interface{}{
-				"moniker": monikerStr,
-				"error":   err.Error(),
-			}
-		} else {
-			results[i] = result
-		}
+	if count() == 0 {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, fmt.Sprintf("Empty %s list", item), nil)
+		return false
+	}
+
+	if count() > maxBatchSize {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, fmt.Sprintf("Too many %ss", item), map[string]interface{}{
+			"detail": fmt.Sprintf("Maximum %d %ss per batch request", maxBatchSize, item),
+			"count":  count(),
+		})
+		return false
+	}
+	return true
+}
+
+// batchFailure is the item of a batch for a key (moniker or path) that
+// failed with err. It carries what a single request's error response would,
+// e.g. estimated_rows when denied or the successor when gone.
+func batchFailure(key, value string, err error) map[string]interface{} {
+	e := describeServiceError(err)
+	failure := map[string]interface{}{}
+	for k, v := range e.details {
+		failure[k] = v
 	}
+	failure[key] = value
+	failure["status"] = batchStatus(e.status)
+	failure["code"] = e.code
+	failure["error"] = err.Error()
+	failure["http_status"] = e.status
+	return failure
+}
 
+// writeBatchResponse answers a batch request with its results: a 200 when
+// every item succeeded and a 207 otherwise
+func writeBatchResponse(w http.ResponseWriter, results []interface{}, failed int, start time.Time) {
 	response := map[string]interface{}{
-		"results": results,
-		"count":   len(results),
+		"results":    results,
+		"count":      len(results),
+		"succeeded":  len(results) - failed,
+		"failed":     failed,
+		"partial":    failed > 0, // Some items failed or were not processed
+		"elapsed_ms": time.Since(start).Milliseconds(),
 	}
 
-	writeJSON(w, http.StatusOK, response)
+	status := http.StatusOK
+	if failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, status, response)
+}
+
+// Statuses of POST /resolve/batch and /describe/batch items
+const (
+	BatchStatusOK       = "ok"
+	BatchStatusNotFound = "not_found"
+	BatchStatusDenied   = "denied"
+	BatchStatusGone     = "gone"
+	BatchStatusError    = "error"
+)
+
+// BatchResult is an item of a POST /resolve/batch response. A resolved
+// moniker is its ResolveResult with status ok. A failed one is the moniker
+// with the error, code and details a single resolve would have answered
+// with, its HTTP status in http_status.
+type BatchResult struct {
+	*service.ResolveResult
+	Status     string `json:"status"`
+	Code       string `json:"code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+}
+
+// DescribeBatchResult is an item of a POST /describe/batch response: the
+// DescribeResult of a path with status ok, or like a failed BatchResult
+type DescribeBatchResult struct {
+	*service.DescribeResult
+	Status     string `json:"status"`
+	Code       string `json:"code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
 }
 
-// LineageHandler handles GET /lineage/{path}
+// batchStatus returns the item status of a failed resolve answered with the
+// HTTP status code
+func batchStatus(code int) string {
+	switch code {
+	case http.StatusNotFound:
+		return BatchStatusNotFound
+	case http.StatusForbidden:
+		return BatchStatusDenied
+	case http.StatusGone:
+		return BatchStatusGone
+	}
+	return BatchStatusError
+}
+
+// LineageHandler handles GET /lineage/{path}: the path's ownership and
+// hierarchy, and its lineage graph built from upstream dependencies, related
+// monikers, foreign keys and binding inputs. ?depth (default 1, capped at
+// catalog.MaxLineageDepth) is how many hops to follow each way. The response
+// has an ETag; If-None-Match with it gets a 304 until the catalog changes.
 type LineageHandler struct {
 	service *service.MonikerService
 	catalog *catalog.Registry
@@ -225,20 +751,45 @@ func NewLineageHandler(svc *service.MonikerService, reg *catalog.Registry) *Line
 
 // ServeHTTP implements http.Handler
 func (h *LineageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/lineage/")
+	path, err := pathFromRequest(r, "/lineage/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+
+	depth := catalog.DefaultLineageDepth
+	if s := r.URL.Query().Get("depth"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 1 {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid depth", map[string]interface{}{
+				"detail": fmt.Sprintf("depth must be a positive integer, got %q", s),
+			})
+			return
+		}
+		depth = d
+	}
+	if notModified(w, r, catalogETag(h.catalog, r)) {
 		return
 	}
 
 	// Get ownership with provenance
 	ownership := h.catalog.ResolveOwnership(path)
+	graph := h.catalog.Lineage(path, depth)
 
 	// Build lineage response
 	response := map[string]interface{}{
-		"path":      path,
-		"ownership": ownership,
-		"hierarchy": buildHierarchy(path),
+		"path":       path,
+		"ownership":  ownership,
+		"hierarchy":  buildHierarchy(path),
+		"depth":      graph.Depth,
+		"nodes":      graph.Nodes,
+		"upstream":   graph.Upstream,
+		"downstream": graph.Downstream,
+		"cycles":     graph.Cycles,
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -259,7 +810,10 @@ func buildHierarchy(path string) []string {
 	return hierarchy
 }
 
-// MetadataHandler handles GET /metadata/{path}
+// MetadataHandler handles GET /metadata/{path}. The response has an ETag;
+// If-None-Match with it gets a 304 until the catalog changes. HEAD answers
+// with the status and ETag GET would and the check headers, without
+// resolving the node's ownership, classification or tags.
 type MetadataHandler struct {
 	service *service.MonikerService
 	catalog *catalog.Registry
@@ -272,22 +826,41 @@ func NewMetadataHandler(svc *service.MonikerService, reg *catalog.Registry) *Met
 
 // ServeHTTP implements http.Handler
 func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/metadata/")
+	path, err := pathFromRequest(r, "/metadata/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
 		return
 	}
+	etag := catalogETag(h.catalog, r)
 
 	node := h.catalog.Get(path)
 	if node == nil {
-		writeError(w, http.StatusNotFound, "Node not found", map[string]interface{}{
+		writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
 			"path": path,
 		})
 		return
 	}
+	if notModified(w, r, etag) {
+		return
+	}
 
-	ownership := h.catalog.ResolveOwnership(path)
 	binding, bindingPath := h.catalog.FindSourceBinding(path)
+	if r.Method == http.MethodHead {
+		sourceType := ""
+		if binding != nil {
+			sourceType = string(binding.SourceType)
+		}
+		setCheckHeaders(w, bindingPath, sourceType, node.Status == catalog.NodeStatusDeprecated)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	ownership := h.catalog.ResolveOwnership(path)
+
+	classification, classificationSource := h.catalog.ResolveClassification(path)
 
 	response := map[string]interface{}{
 		"path":         path,
@@ -295,6 +868,18 @@ func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"ownership":    ownership,
 		"has_binding":  binding != nil,
 		"binding_path": bindingPath,
+		"classification": map[string]interface{}{
+			"value":      classification,
+			"defined_at": classificationSource,
+		},
+		"tags": h.catalog.ResolveTags(path),
+	}
+
+	if sla := h.catalog.ResolveSLA(path); sla != nil {
+		response["sla"] = sla
+	}
+	if dq := h.catalog.ResolveDataQuality(path); dq != nil {
+		response["data_quality"] = dq
 	}
 
 	if binding != nil {
@@ -304,120 +889,266 @@ func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, response)
 }
 
-// TreeHandler handles GET /tree/{path} and GET /tree
+// TreeHandler handles GET /tree/{path} and GET /tree. The subtree is listed
+// depth levels deep (default 1, at most maxDepth) within a budget of maxNodes;
+// include_counts=true adds descendant_count to every node and
+// include_archived=true lists archived branches. The response has an ETag;
+// If-None-Match with it gets a 304 until the catalog changes.
 type TreeHandler struct {
-	catalog *catalog.Registry
+	catalog  *catalog.Registry
+	maxDepth int
+	maxNodes int
 }
 
-// NewTreeHandler creates a new tree handler
-func NewTreeHandler(reg *catalog.Registry) *TreeHandler {
-	return &TreeHandler{catalog: reg}
+// NewTreeHandler creates a new tree handler. Limits of 0 use
+// catalog.DefaultTreeMaxDepth and catalog.DefaultTreeMaxNodes.
+func NewTreeHandler(reg *catalog.Registry, maxDepth, maxNodes int) *TreeHandler {
+	if maxDepth <= 0 {
+		maxDepth = catalog.DefaultTreeMaxDepth
+	}
+	if maxNodes <= 0 {
+		maxNodes = catalog.DefaultTreeMaxNodes
+	}
+	return &TreeHandler{catalog: reg, maxDepth: maxDepth, maxNodes: maxNodes}
 }
 
 // ServeHTTP implements http.Handler
 func (h *TreeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/tree/")
-	path = strings.TrimPrefix(path, "/")
+	path, err := pathFromRequest(r, "/tree/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 
-	// Build tree structure
-	node := h.catalog.Get(path)
-	children := h.catalog.Children(path)
-
-	childNodes := make([]map[string]interface{}, len(children))
-	for i, child := range children {
-		childNodes[i] = map[string]interface{}{
-			"path":         child.Path,
-			"display_name": child.DisplayName,
-			"is_leaf":      child.IsLeaf,
-			"status":       child.Status,
+	query := r.URL.Query()
+	depth := 1
+	if s := query.Get("depth"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 1 {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid depth", map[string]interface{}{
+				"detail": fmt.Sprintf("depth must be a positive integer, got %q", s),
+			})
+			return
 		}
+		depth = d
+	}
+	if depth > h.maxDepth {
+		depth = h.maxDepth
+	}
+	if notModified(w, r, catalogETag(h.catalog, r)) {
+		return
 	}
 
+	tree := h.catalog.Tree(path, catalog.TreeOptions{
+		Depth:           depth,
+		MaxNodes:        h.maxNodes,
+		IncludeArchived: query.Get("include_archived") == "true",
+		Counts:          query.Get("include_counts") == "true",
+	})
+
+	children := tree.Root.Children
+	if children == nil {
+		children = []*catalog.TreeNode{}
+	}
 	response := map[string]interface{}{
-		"path":     path,
-		"node":     node,
-		"children": childNodes,
-		"count":    len(children),
+		"path":       path,
+		"node":       h.catalog.Get(path),
+		"virtual":    tree.Root.Virtual,
+		"children":   children,
+		"count":      len(children),
+		"depth":      depth,
+		"node_count": tree.NodeCount,
+		"truncated":  tree.Truncated,
+	}
+	if tree.Root.DescendantCount != nil {
+		response["descendant_count"] = *tree.Root.DescendantCount
+	}
+	if tree.Truncated {
+		response["max_nodes"] = h.maxNodes
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
 // CacheStatusHandler handles GET /cache/status
-type CacheStatusHandler struct{}
+type CacheStatusHandler struct {
+	cache *cache.InMemory
+}
 
 // NewCacheStatusHandler creates a new cache status handler
-func NewCacheStatusHandler() *CacheStatusHandler {
-	return &CacheStatusHandler{}
+func NewCacheStatusHandler(c *cache.InMemory) *CacheStatusHandler {
+	return &CacheStatusHandler{cache: c}
 }
 
-// ServeHTTP implements http.Handler
+// ServeHTTP implements http.Handler. namespaces always lists the service's
+// cache namespaces, resolve results, listed versions and fetched data, and
+// any other seen.
 func (h *CacheStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stats := h.cache.Stats()
+	for _, ns := range service.CacheNamespaces {
+		if _, ok := stats.Namespaces[ns]; !ok {
+			stats.Namespaces[ns] = cache.NamespaceStats{}
+		}
+	}
+	var lastInvalidated interface{}
+	if stats.LastCleared != nil {
+		lastInvalidated = stats.LastCleared.UTC().Format(time.RFC3339)
+	}
 	response := map[string]interface{}{
-		"status":  "ok",
-		"backend": "in-memory",
-		"message": "Cache is operational",
+		"status":           "ok",
+		"backend":          "in-memory",
+		"message":          "Cache is operational",
+		"size":             stats.Size,
+		"hits":             stats.Hits,
+		"misses":           stats.Misses,
+		"hit_ratio":        stats.HitRatio,
+		"evictions":        stats.Evictions,
+		"expired_on_read":  stats.ExpiredOnRead,
+		"estimated_bytes":  stats.EstimatedBytes,
+		"ttl_seconds":      stats.DefaultTTL.Seconds(),
+		"last_invalidated": lastInvalidated,
+		"namespaces":       stats.Namespaces,
+		"cleanup": map[string]interface{}{
+			"running":          stats.CleanupInterval > 0,
+			"interval_seconds": stats.CleanupInterval.Seconds(),
+		},
 	}
 	writeJSON(w, http.StatusOK, response)
 }
 
-// TelemetryAccessHandler handles POST /telemetry/access
-type TelemetryAccessHandler struct{}
+// TelemetryAccessHandler handles POST /telemetry/access: an access event a
+// client reports after using a moniker. Events are checked against
+// telemetry.AccessEvent and written to the recorder's sink. An authenticated
+// request is recorded under its caller; otherwise the event's caller, or the
+// X-User-ID header, is used.
+type TelemetryAccessHandler struct {
+	recorder *telemetry.Recorder
+}
 
 // NewTelemetryAccessHandler creates a new telemetry handler
-func NewTelemetryAccessHandler() *TelemetryAccessHandler {
-	return &TelemetryAccessHandler{}
+func NewTelemetryAccessHandler(recorder *telemetry.Recorder) *TelemetryAccessHandler {
+	return &TelemetryAccessHandler{recorder: recorder}
 }
 
 // ServeHTTP implements http.Handler
 func (h *TelemetryAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Parse telemetry event (simplified - just acknowledge)
-	var event map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid telemetry event", nil)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed, "Method not allowed", map[string]interface{}{
+			"detail": "access events are reported with POST",
+		})
+		return
+	}
+	// Not strict: newer clients may report fields this version doesn't know
+	var event telemetry.AccessEvent
+	if !decodeJSONBody(w, r, &event, false) {
+		return
+	}
+	if caller := service.CallerFromContext(r.Context()); caller != nil {
+		event.Caller = caller.UserID
+	} else if event.Caller == "" {
+		event.Caller = r.Header.Get("X-User-ID")
+	}
+
+	if err := h.recorder.Record(&event); err != nil {
+		var invalid *telemetry.InvalidEventError
+		if errors.As(err, &invalid) {
+			details := map[string]interface{}{"detail": invalid.Error()}
+			if len(invalid.Missing) > 0 {
+				details["missing"] = invalid.Missing
+			}
+			if len(invalid.Invalid) > 0 {
+				details["invalid"] = invalid.Invalid
+			}
+			writeError(w, http.StatusBadRequest, ErrValidationFailed, "Invalid telemetry event", details)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrInternal, "Telemetry event not recorded", map[string]interface{}{
+			"detail": err.Error(),
+		})
 		return
 	}
 
-	// In production, this would emit to telemetry system
 	response := map[string]interface{}{
 		"status":  "accepted",
 		"message": "Telemetry event recorded",
+		"event":   event,
 	}
 	writeJSON(w, http.StatusAccepted, response)
 }
 
-// UIHandler handles GET /ui
-type UIHandler struct{}
+// TelemetryStatsHandler handles GET /telemetry/stats: the access events of
+// the window (default 24h) ending now, counted by path prefix, caller and
+// outcome. depth sets how many path segments events are grouped by (default
+// 1, the domain; 0 for full paths) and prefix narrows them to a subtree.
+type TelemetryStatsHandler struct {
+	recorder *telemetry.Recorder
+}
 
-// NewUIHandler creates a new UI handler
-func NewUIHandler() *UIHandler {
-	return &UIHandler{}
+// NewTelemetryStatsHandler creates a new telemetry stats handler
+func NewTelemetryStatsHandler(recorder *telemetry.Recorder) *TelemetryStatsHandler {
+	return &TelemetryStatsHandler{recorder: recorder}
 }
 
+// DefaultTelemetryWindow is the window of GET /telemetry/stats when the
+// request does not set one
+const DefaultTelemetryWindow = 24 * time.Hour
+
 // ServeHTTP implements http.Handler
-func (h *UIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	html := `<!DOCTYPE html>
-<html>
-<head>
-    <title>Moniker Catalog Browser</title>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 20px; }
-        h1 { color: #333; }
-        .info { background: #f0f0f0; padding: 10px; border-radius: 5px; }
-    </style>
-</head>
-<body>
-    <h1>Moniker Catalog Browser</h1>
-    <div class="info">
-        <p><strong>Go Resolver</strong> - High Performance Edition</p>
-        <p>Navigate to <code>/catalog</code> for catalog listing</p>
-        <p>Navigate to <code>/catalog/search?q=term</code> for search</p>
-        <p>Navigate to <code>/health</code> for service health</p>
-    </div>
-</body>
-</html>`
-
-	w.Header().Set("Content-Type", "text/html")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, html)
+func (h *TelemetryStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	window := DefaultTelemetryWindow
+	if s := query.Get("window"); s != "" {
+		d, err := parseWindow(s)
+		if err == nil && d == 0 {
+			err = fmt.Errorf("window must be positive: %q", s)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid window", map[string]interface{}{
+				"detail": err.Error(),
+			})
+			return
+		}
+		window = d
+	}
+	depth := 1
+	if s := query.Get("depth"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid depth", map[string]interface{}{
+				"detail": fmt.Sprintf("depth must be a non-negative integer, got %q", s),
+			})
+			return
+		}
+		depth = n
+	}
+
+	stats, err := h.recorder.Stats(window, depth, query.Get("prefix"))
+	if errors.Is(err, telemetry.ErrStatsUnavailable) {
+		writeError(w, http.StatusNotImplemented, ErrNotImplemented, "Telemetry stats unavailable", map[string]interface{}{
+			"detail": "enable telemetry with sink_type memory or file to keep access events",
+		})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrInternal, "Telemetry stats failed", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	response := map[string]interface{}{
+		"window":     formatWindow(window),
+		"depth":      depth,
+		"from":       stats.From,
+		"to":         stats.To,
+		"events":     stats.Events,
+		"by_path":    stats.ByPath,
+		"by_caller":  stats.ByCaller,
+		"by_outcome": stats.ByOutcome,
+	}
+	if prefix := query.Get("prefix"); prefix != "" {
+		response["prefix"] = prefix
+	}
+	writeJSON(w, http.StatusOK, response)
 }
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// DomainsHandler handles GET /domains: each domain the top-level nodes
+// declare, with its roots and their ownership, node counts by status and
+// source type, and whether any of its nodes are deprecated or lack an owner.
+// Nodes under no domain are grouped as catalog.UnassignedDomain. The response
+// has an ETag; If-None-Match with it gets a 304 until the catalog changes.
+type DomainsHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewDomainsHandler creates a new domains handler
+func NewDomainsHandler(reg *catalog.Registry) *DomainsHandler {
+	return &DomainsHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *DomainsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if notModified(w, r, catalogETag(h.catalog, r)) {
+		return
+	}
+	domains := h.catalog.Domains()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"domains": domains,
+		"count":   len(domains),
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrorCode is the stable, machine-readable code of an error response.
+// Clients should branch on it rather than on the message, which may change.
+type ErrorCode string
+
+// Error codes, grouped by the kind of failure
+const (
+	// The request is malformed
+	ErrInvalidRequest       ErrorCode = "INVALID_REQUEST"
+	ErrInvalidPath          ErrorCode = "INVALID_PATH"
+	ErrInvalidMoniker       ErrorCode = "INVALID_MONIKER"
+	ErrInvalidFetch         ErrorCode = "INVALID_FETCH"
+	ErrMethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrPayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrExpansionTooLarge    ErrorCode = "EXPANSION_TOO_LARGE"
+
+	// What the request names doesn't exist
+	ErrMonikerNotFound     ErrorCode = "MONIKER_NOT_FOUND"
+	ErrSubResourceNotFound ErrorCode = "SUB_RESOURCE_NOT_FOUND"
+	ErrNodeNotFound        ErrorCode = "NODE_NOT_FOUND"
+	ErrNotFound            ErrorCode = "NOT_FOUND"
+	ErrGone                ErrorCode = "GONE"
+	ErrSunset              ErrorCode = "SUNSET"
+
+	// The caller may not make the request
+	ErrUnauthenticated      ErrorCode = "UNAUTHENTICATED"
+	ErrAccessDenied         ErrorCode = "ACCESS_DENIED"
+	ErrOperationNotAllowed  ErrorCode = "OPERATION_NOT_ALLOWED"
+	ErrConfirmationRequired ErrorCode = "CONFIRMATION_REQUIRED"
+	ErrRateLimited          ErrorCode = "RATE_LIMITED"
+
+	// The change is refused
+	ErrValidationFailed ErrorCode = "VALIDATION_FAILED"
+	ErrInvalidStatus    ErrorCode = "INVALID_STATUS_TRANSITION"
+	ErrConflict         ErrorCode = "CONFLICT"
+
+	// The server failed
+	ErrNotImplemented  ErrorCode = "NOT_IMPLEMENTED"
+	ErrUpstreamFailed  ErrorCode = "UPSTREAM_FAILED"
+	ErrUpstreamTimeout ErrorCode = "UPSTREAM_TIMEOUT"
+	ErrUnavailable     ErrorCode = "UNAVAILABLE"
+	ErrInternal        ErrorCode = "INTERNAL_ERROR"
+)
+
+// errorCodes describes every ErrorCode, in the order the OpenAPI document
+// lists them
+var errorCodes = []struct {
+	Code        ErrorCode
+	Description string
+}{
+	{ErrInvalidRequest, "A query parameter or the request body is missing or malformed"},
+	{ErrInvalidPath, "The URL path has an empty, '.' or '..' segment"},
+	{ErrInvalidMoniker, "The moniker doesn't parse or its params, namespace or version don't resolve"},
+	{ErrInvalidFetch, "The source refused the fetch as given, e.g. an unknown filter"},
+	{ErrMethodNotAllowed, "The route doesn't serve the method; the Allow header lists those it does"},
+	{ErrPayloadTooLarge, "The request body is over the route's limit, given in max_bytes"},
+	{ErrUnsupportedMediaType, "The request body's Content-Type isn't one the route takes"},
+	{ErrExpansionTooLarge, "expand_all would expand into more monikers than allowed"},
+	{ErrMonikerNotFound, "No catalog node or binding matches the moniker"},
+	{ErrSubResourceNotFound, "The node has no such sub-resource"},
+	{ErrNodeNotFound, "No catalog node has the path"},
+	{ErrNotFound, "The owner, schema or other resource named doesn't exist"},
+	{ErrGone, "The node is archived; details name its successor when it has one"},
+	{ErrSunset, "The node is past its sunset deadline"},
+	{ErrUnauthenticated, "The request carries no valid credentials"},
+	{ErrAccessDenied, "The caller lacks the role, scope or ownership the request needs, or the access policy blocks it"},
+	{ErrOperationNotAllowed, "The source binding doesn't allow the operation"},
+	{ErrConfirmationRequired, "The change needs explicit confirmation, e.g. allow_binding_change=true"},
+	{ErrRateLimited, "The caller is over its rate limit; retry after Retry-After"},
+	{ErrValidationFailed, "The catalog, node or event sent fails validation"},
+	{ErrInvalidStatus, "The lifecycle doesn't allow the status change"},
+	{ErrConflict, "The node already exists"},
+	{ErrNotImplemented, "The source or sink doesn't support the request"},
+	{ErrUpstreamFailed, "The data source failed"},
+	{ErrUpstreamTimeout, "The data source didn't answer in time"},
+	{ErrUnavailable, "A dependency is unavailable or the request was cancelled; retry later"},
+	{ErrInternal, "The server failed unexpectedly"},
+}
+
+// ErrorBody is the error of an ErrorResponse
+type ErrorBody struct {
+	Code      ErrorCode              `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"` // X-Request-ID of the response
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// legacyErrors selects the error format before ErrorResponse: the message
+// in a top-level "error" string with the details beside it
+var legacyErrors atomic.Bool
+
+// UseLegacyErrors switches error responses to the format before
+// ErrorResponse, for clients not yet reading error.code. It will be removed
+// in the next release.
+func UseLegacyErrors(on bool) {
+	legacyErrors.Store(on)
+}
+
+// writeError answers with an ErrorResponse of status, code, message and
+// details. The request ID is the X-Request-ID RequestID set on w.
+func writeError(w http.ResponseWriter, status int, code ErrorCode, message string, details map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if legacyErrors.Load() {
+		response := map[string]interface{}{
+			"error": message,
+		}
+		for k, v := range details {
+			response[k] = v
+		}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorBody{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: w.Header().Get(RequestIDHeader),
+	}})
+}
+
+// RequestIDHeader carries the ID of a request, from the client or made up by
+// RequestID, and of its response
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs taken from clients
+const maxRequestIDLength = 128
+
+// RequestID gives every request an ID, echoed in the X-Request-ID response
+// header and in error responses: the client's X-Request-ID when it sends a
+// usable one, otherwise a random one.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validRequestID reports whether id is short and printable ASCII, so it can
+// be echoed in headers and logs as it is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// etagEpoch tells apart the generations of registries in different runs,
+// which all count from zero
+var etagEpoch = time.Now().UnixNano()
+
+// catalogETag returns the ETag of a response derived from reg alone: its
+// generation, which every change to the catalog bumps, with the request's
+// path and query, and the export format its Accept header asks for. Take it
+// before reading the catalog, so a change made while the response is built
+// only costs the client a full response next time.
+func catalogETag(reg *catalog.Registry, r *http.Request) string {
+	key := fmt.Sprintf("%d:%d:%s?%s", etagEpoch, reg.Generation(), r.URL.Path, r.URL.RawQuery)
+	if format := acceptFormat(r); format != formatJSON {
+		key += "#" + format
+	}
+	sum := sha256.Sum256([]byte(key))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// notModified sets etag on the response and reports whether the request's
+// If-None-Match already names it, in which case it has written a 304 with no
+// body. Clients are asked to revalidate every time they reuse a response.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Formats of the list endpoints that export: the JSON default, CSV for
+// spreadsheets and NDJSON (one JSON object per line) for streaming consumers
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// acceptFormat returns the export format the request's Accept header asks
+// for: the first of text/csv, application/x-ndjson and application/json it
+// lists, in the order listed, with anything else meaning JSON
+func acceptFormat(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return formatCSV
+		case "application/x-ndjson":
+			return formatNDJSON
+		case "application/json":
+			return formatJSON
+		}
+	}
+	return formatJSON
+}
+
+// exportFormat returns the format a list endpoint answers in: ?format when
+// given, otherwise the one the Accept header asks for. An unknown ?format is
+// a 400 written to w.
+func exportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	w.Header().Add("Vary", "Accept")
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+		return acceptFormat(r), true
+	case formatJSON, formatCSV, formatNDJSON:
+		return format, true
+	default:
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Invalid format", map[string]interface{}{
+			"detail": "format must be 'json', 'csv' or 'ndjson'",
+		})
+		return "", false
+	}
+}
+
+// startDownload sends the headers of a CSV download named after name and
+// the time now, e.g. catalog-20260102T150405Z.csv
+func startDownload(w http.ResponseWriter, name string, now time.Time) {
+	filename := fmt.Sprintf("%s-%s.csv", name, now.UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeNDJSON writes each of items as one line of JSON
+func writeNDJSON[T any](w http.ResponseWriter, items []T) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	// Headers are sent; a failure now can only cut the stream short
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// defaultProbeTimeout bounds a readiness probe without timeout_seconds
+const defaultProbeTimeout = 2 * time.Second
+
+// Probe checks a dependency the resolver needs to serve requests. While a
+// probe fails the resolver is not ready.
+type Probe struct {
+	Name    string
+	Timeout time.Duration // Default defaultProbeTimeout
+	Check   func(ctx context.Context) error
+}
+
+// ProbeResult is the outcome of one Probe
+type ProbeResult struct {
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+}
+
+// NewProbes builds the readiness probes of cfg. A secret probe resolves its
+// target secret:// reference through secrets, an http probe expects a status
+// below 400 from a GET of its target URL, and a tcp probe connects to its
+// target host:port.
+func NewProbes(cfg config.HealthConfig, secrets *service.SecretResolver) ([]Probe, error) {
+	probes := make([]Probe, 0, len(cfg.Probes))
+	names := make(map[string]bool)
+	for i, pc := range cfg.Probes {
+		if pc.Name == "" || pc.Target == "" {
+			return nil, fmt.Errorf("health probe %d: name and target are required", i)
+		}
+		if names[pc.Name] {
+			return nil, fmt.Errorf("health probe %q is defined twice", pc.Name)
+		}
+		names[pc.Name] = true
+
+		probe := Probe{Name: pc.Name, Timeout: time.Duration(pc.TimeoutSeconds * float64(time.Second))}
+		target := pc.Target
+		switch pc.Type {
+		case "secret":
+			if !service.IsSecretRef(target) {
+				return nil, fmt.Errorf("health probe %q: target must be a %s reference", pc.Name, service.SecretScheme)
+			}
+			probe.Check = func(ctx context.Context) error {
+				_, err := secrets.Resolve(target)
+				return err
+			}
+		case "http":
+			probe.Check = func(ctx context.Context) error {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+				if err != nil {
+					return err
+				}
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				if resp.StatusCode >= 400 {
+					return fmt.Errorf("GET %s: %s", target, resp.Status)
+				}
+				return nil
+			}
+		case "tcp":
+			probe.Check = func(ctx context.Context) error {
+				var dialer net.Dialer
+				conn, err := dialer.DialContext(ctx, "tcp", target)
+				if err != nil {
+					return err
+				}
+				return conn.Close()
+			}
+		default:
+			return nil, fmt.Errorf("health probe %q: unknown type %q (want secret, http or tcp)", pc.Name, pc.Type)
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+// runProbes runs probes concurrently, each within its timeout
+func runProbes(ctx context.Context, probes []Probe) []ProbeResult {
+	results := make([]ProbeResult, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func(i int, probe Probe) {
+			defer wg.Done()
+			timeout := probe.Timeout
+			if timeout <= 0 {
+				timeout = defaultProbeTimeout
+			}
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			done := make(chan error, 1)
+			go func() { done <- probe.Check(probeCtx) }()
+			var err error
+			select {
+			case err = <-done:
+			case <-probeCtx.Done():
+				err = fmt.Errorf("timed out after %s", timeout)
+			}
+			results[i] = ProbeResult{Name: probe.Name, OK: err == nil, ElapsedMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, probe)
+	}
+	wg.Wait()
+	return results
+}
+
+// LivenessHandler handles GET /health/live: the process is up and serving
+type LivenessHandler struct{}
+
+// NewLivenessHandler creates a new liveness handler
+func NewLivenessHandler() *LivenessHandler {
+	return &LivenessHandler{}
+}
+
+// ServeHTTP implements http.Handler
+func (h *LivenessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "alive"})
+}
+
+// ReadinessHandler handles GET /health/ready. The resolver is ready once a
+// catalog has been loaded and validated (at startup or by a reload) and while
+// it has nodes, every probe passes and it is not shutting down; otherwise the
+// response is a 503 with the same body, naming what failed in reasons.
+type ReadinessHandler struct {
+	catalog  *catalog.Registry
+	reloader *catalog.ReloadManager
+	cache    *cache.InMemory
+	probes   []Probe
+	draining func() bool
+}
+
+// NewReadinessHandler creates a new readiness handler
+func NewReadinessHandler(reg *catalog.Registry, m *catalog.ReloadManager, c *cache.InMemory, probes []Probe) *ReadinessHandler {
+	return &ReadinessHandler{catalog: reg, reloader: m, cache: c, probes: probes}
+}
+
+// WithDraining makes the resolver unready while draining reports true, as it
+// does once a shutdown has begun
+func (h *ReadinessHandler) WithDraining(draining func() bool) *ReadinessHandler {
+	h.draining = draining
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reasons := make([]string, 0)
+	if h.draining != nil && h.draining() {
+		reasons = append(reasons, "shutting down")
+	}
+	counts := h.catalog.Count()
+	reload := h.reloader.Status()
+
+	catalogInfo := map[string]interface{}{
+		"nodes":  counts,
+		"digest": h.catalog.Version(), // Empty once the catalog is edited through the API
+	}
+	if reload.LastReload == nil {
+		reasons = append(reasons, "catalog not loaded")
+	} else {
+		catalogInfo["loaded_at"] = reload.LastReload
+	}
+	if counts["total"] == 0 {
+		reasons = append(reasons, "catalog is empty")
+	}
+	if reload.LastError != "" {
+		catalogInfo["last_reload_error"] = reload.LastError
+	}
+
+	probes := runProbes(r.Context(), h.probes)
+	for _, p := range probes {
+		if !p.OK {
+			reasons = append(reasons, fmt.Sprintf("probe %s failed", p.Name))
+		}
+	}
+
+	stats := h.cache.Stats()
+	response := map[string]interface{}{
+		"status":  "ready",
+		"catalog": catalogInfo,
+		"cache": map[string]interface{}{
+			"size":   stats.Size,
+			"hits":   stats.Hits,
+			"misses": stats.Misses,
+		},
+		"probes": probes,
+	}
+	status := http.StatusOK
+	if len(reasons) > 0 {
+		status = http.StatusServiceUnavailable
+		response["status"] = "not_ready"
+		response["reasons"] = reasons
+	}
+	writeJSON(w, status, response)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight answer
+const corsMaxAge = "600"
+
+// CORSPolicy lists the origins browsers may call the API from. "*" allows
+// any origin. The zero policy sends no CORS headers, so browsers only allow
+// same-origin calls.
+type CORSPolicy struct {
+	Origins []string
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin to answer origin with,
+// or "" when it isn't allowed
+func (p CORSPolicy) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, allowed := range p.Origins {
+		if allowed == "*" {
+			return "*"
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// methodRouter serves a route with a handler per method. HEAD is served by
+// the GET handler, OPTIONS lists the methods (answering CORS preflights),
+// and any other method is a 405 with the Allow header set.
+type methodRouter struct {
+	handlers map[string]http.Handler
+	cors     CORSPolicy
+}
+
+// methods routes each of methods to h
+func methods(cors CORSPolicy, h http.Handler, methods ...string) *methodRouter {
+	m := &methodRouter{handlers: make(map[string]http.Handler, len(methods)), cors: cors}
+	for _, method := range methods {
+		m.handlers[method] = h
+	}
+	return m
+}
+
+// handle routes method to h as well, and returns m
+func (m *methodRouter) handle(method string, h http.Handler) *methodRouter {
+	m.handlers[method] = h
+	return m
+}
+
+// allowed returns the methods m serves, sorted, as the Allow header lists
+// them
+func (m *methodRouter) allowed() string {
+	allowed := []string{http.MethodOptions}
+	for method := range m.handlers {
+		allowed = append(allowed, method)
+	}
+	if _, ok := m.handlers[http.MethodGet]; ok {
+		if _, ok := m.handlers[http.MethodHead]; !ok {
+			allowed = append(allowed, http.MethodHead)
+		}
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ", ")
+}
+
+// ServeHTTP implements http.Handler
+func (m *methodRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := m.cors.allowOrigin(r.Header.Get("Origin"))
+	if origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	method := r.Method
+	if method == http.MethodHead {
+		if _, ok := m.handlers[method]; !ok {
+			method = http.MethodGet
+		}
+	}
+	if h, ok := m.handlers[method]; ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	allowed := m.allowed()
+	w.Header().Set("Allow", allowed)
+	if r.Method == http.MethodOptions {
+		if origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", allowed)
+			if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeError(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed, "Method not allowed", map[string]interface{}{
+		"detail":  r.Method + " is not allowed on " + r.URL.Path,
+		"allowed": strings.Split(allowed, ", "),
+	})
+}
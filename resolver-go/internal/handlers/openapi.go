@@ -0,0 +1,755 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/telemetry"
+)
+
+// APIVersion is the version of the HTTP API, reported by /health and the
+// OpenAPI document
+const APIVersion = "0.1.0-beta"
+
+// apiRoute documents one operation of the HTTP API. Body and Response are
+// sample values whose JSON shape is the schema: a struct is described from
+// its type and json tags, and a map[string]interface{} as an object with one
+// property per key, described from its value.
+type apiRoute struct {
+	Method       string
+	Path         string // OpenAPI path template, e.g. /resolve/{moniker}
+	Summary      string
+	Query        []apiParam
+	Body         interface{} // Request body; nil for none
+	BodyTypes    []string    // Request media types when not only application/json
+	Status       int         // Success status; 200 if 0
+	Response     interface{} // Success body; nil for free-form JSON
+	ResponseType string      // Success media type when not application/json
+	Errors       []int       // Error statuses, each with an ErrorResponse body
+	ETag         bool        // Honors If-None-Match with a 304
+	Headers      []apiParam  // Success response headers
+}
+
+// apiParam is a query parameter of an apiRoute
+type apiParam struct {
+	Name        string
+	Type        string // string, integer, number or boolean
+	Description string
+	Repeatable  bool
+}
+
+// apiRoutes describes every route Routes.Register serves
+func apiRoutes() []apiRoute {
+	pageOfPaths := map[string]interface{}{"paths": []string{}, "count": 0, "total": 0, "next_cursor": ""}
+	return []apiRoute{
+		{Method: "GET", Path: "/health", Summary: "Service health, catalog and telemetry counters"},
+		{Method: "GET", Path: "/health/live", Summary: "Liveness: the process is serving",
+			Response: map[string]interface{}{"status": ""}},
+		{Method: "GET", Path: "/health/ready", Summary: "Readiness: a catalog is loaded and every dependency probe passes",
+			Response: map[string]interface{}{
+				"status": "", "reasons": []string{}, "probes": []ProbeResult{},
+				"catalog": map[string]interface{}{
+					"nodes": map[string]int{}, "digest": "", "loaded_at": time.Time{}, "last_reload_error": "",
+				},
+				"cache": map[string]interface{}{"size": 0, "hits": 0, "misses": 0},
+			},
+			Errors: []int{503}},
+
+		// Resolution
+		{Method: "GET", Path: "/resolve/{moniker}", Summary: "Resolve a moniker to its source binding",
+			Query: resolveParams, Response: service.ResolveResult{}, Errors: []int{400, 403, 404, 410, 429}},
+		{Method: "HEAD", Path: "/resolve/{moniker}", Summary: "Check a moniker resolves, without building the result",
+			Query: checkParams, Headers: checkHeaders, Errors: []int{400, 403, 404, 410, 429}},
+		{Method: "GET", Path: "/exists/{moniker}", Summary: "Check a moniker resolves; 200 with the status GET /resolve would answer",
+			Query: checkParams, Response: ExistsResult{}, Errors: []int{400, 429}},
+		{Method: "GET", Path: "/resolve/", Summary: "Resolve a moniker given URL-encoded in ?moniker=",
+			Query:    append([]apiParam{{Name: "moniker", Type: "string", Description: "The whole moniker, its own ?params included"}}, resolveParams...),
+			Response: service.ResolveResult{}, Errors: []int{400, 403, 404, 410, 429}},
+		{Method: "POST", Path: "/resolve/batch", Summary: "Resolve up to 100 monikers; 207 with the same body when any fails",
+			Body: map[string]interface{}{"monikers": []string{}},
+			Response: map[string]interface{}{
+				"results": []BatchResult{}, "count": 0, "succeeded": 0, "failed": 0, "partial": false, "elapsed_ms": 0,
+			},
+			Errors: []int{400, 429}},
+		{Method: "POST", Path: "/describe/batch", Summary: "Describe up to 100 paths; 207 with the same body when any is not found",
+			Body: map[string]interface{}{"paths": []string{}},
+			Response: map[string]interface{}{
+				"results": []DescribeBatchResult{}, "count": 0, "succeeded": 0, "failed": 0, "partial": false, "elapsed_ms": 0,
+			},
+			Errors: []int{400, 429}},
+		{Method: "GET", Path: "/describe/{path}", Summary: "Describe a catalog node with its resolved ownership",
+			Response: service.DescribeResult{}, Errors: []int{400, 404}},
+		{Method: "GET", Path: "/estimate/{moniker}", Summary: "Estimate the cost of fetching a moniker",
+			Response: service.EstimateResult{}, Errors: []int{400, 404}},
+		{Method: "GET", Path: "/list/{path}", Summary: "List the children of a path",
+			Response: service.ListResult{}, Errors: []int{404}},
+		{Method: "GET", Path: "/lineage/{path}", Summary: "Ownership, hierarchy and lineage graph of a path",
+			Query: []apiParam{{Name: "depth", Type: "integer", Description: "Hops to follow upstream and downstream (default 1, at most 10)"}},
+			Response: map[string]interface{}{
+				"path": "", "ownership": catalog.ResolvedOwnership{}, "hierarchy": []string{},
+				"depth": 0, "nodes": []catalog.LineageNode{},
+				"upstream": []catalog.LineageEdge{}, "downstream": []catalog.LineageEdge{}, "cycles": [][]string{},
+			},
+			Errors: []int{400}},
+		{Method: "GET", Path: "/metadata/{path}", Summary: "Resolved metadata of a catalog node",
+			Response: map[string]interface{}{
+				"path": "", "node": catalog.CatalogNode{}, "ownership": catalog.ResolvedOwnership{},
+				"has_binding": false, "binding_path": "", "source_type": "", "tags": []string{},
+				"classification": map[string]interface{}{"value": "", "defined_at": ""},
+				"sla":            catalog.SLA{}, "data_quality": catalog.DataQuality{},
+			},
+			Errors: []int{400, 404}, ETag: true},
+		{Method: "HEAD", Path: "/metadata/{path}", Summary: "Check a catalog node exists, without resolving its metadata",
+			Headers: checkHeaders, Errors: []int{400, 404}, ETag: true},
+		{Method: "GET", Path: "/tree", Summary: "Nested tree of the catalog root", Query: treeParams,
+			Response: treeResponse, Errors: []int{400}, ETag: true},
+		{Method: "GET", Path: "/tree/{path}", Summary: "Nested tree below a path", Query: treeParams,
+			Response: treeResponse, Errors: []int{400}, ETag: true},
+
+		// Catalog
+		{Method: "GET", Path: "/catalog", Summary: "Page through catalog paths", Query: catalogListParams,
+			Response: pageOfPaths, ETag: true},
+		{Method: "POST", Path: "/catalog", Summary: "Create a draft catalog node",
+			Query: []apiParam{{Name: "virtual_parents", Type: "boolean", Description: "Allow a node below an unregistered parent"}},
+			Body:  catalog.NodeRequest{}, Status: http.StatusCreated, Response: catalog.CatalogNode{},
+			Errors: []int{400, 409, 422}},
+		{Method: "GET", Path: "/catalog/{path}", Summary: "Page through catalog paths", Query: catalogListParams,
+			Response: pageOfPaths, ETag: true},
+		{Method: "PUT", Path: "/catalog/{path}", Summary: "Replace the editable fields of a node", Query: editParams,
+			Body: catalog.CatalogNodeYAML{}, Response: catalog.CatalogNode{}, Errors: []int{400, 404, 409}},
+		{Method: "PATCH", Path: "/catalog/{path}", Summary: "Change some editable fields of a node", Query: editParams,
+			Body: catalog.CatalogNodeYAML{}, Response: catalog.CatalogNode{}, Errors: []int{400, 404, 409}},
+		{Method: "PUT", Path: "/catalog/{path}/status", Summary: "Move a node through its governance lifecycle",
+			Body: map[string]interface{}{"status": catalog.NodeStatus(""), "override": false, "reason": ""},
+			Response: map[string]interface{}{
+				"path": "", "old_status": catalog.NodeStatus(""), "new_status": catalog.NodeStatus(""),
+				"updated": false, "warnings": []string{}, "overridden": false,
+			},
+			Errors: []int{400, 404, 422}},
+		{Method: "PUT", Path: "/catalog/{path}/ownership", Summary: "Set or clear ownership fields of a node",
+			Body: catalog.Ownership{},
+			Response: map[string]interface{}{
+				"path": "", "ownership": catalog.Ownership{}, "resolved_ownership": catalog.ResolvedOwnership{},
+				"inherited_by": []string{},
+			},
+			Errors: []int{400, 404}},
+		{Method: "GET", Path: "/catalog/{path}/schema", Summary: "Columns of a node's schema, optionally diffed against another's",
+			Query: []apiParam{{Name: "compare_to", Type: "string", Description: "Path of a node whose schema to diff against"}},
+			Response: map[string]interface{}{
+				"path": "", "schema": catalog.DataSchema{}, "columns": []schemaColumn{},
+				"compare_to": "", "diff": catalog.SchemaDiff{}, "identical": false,
+			},
+			Errors: []int{404}},
+		{Method: "GET", Path: "/catalog/{path}/quality", Summary: "Own and resolved data quality of a node",
+			Response: qualityResponse, Errors: []int{404}},
+		{Method: "PUT", Path: "/catalog/{path}/quality", Summary: "Update a node's quality score, known issues and last validation",
+			Body: catalog.QualityEdit{}, Response: qualityResponse, Errors: []int{400, 403, 404}},
+		{Method: "GET", Path: "/catalog/{path}/audit", Summary: "Audit trail of a node, newest first",
+			Query: []apiParam{
+				{Name: "limit", Type: "integer", Description: "Entries per page (default 50, at most 1000)"},
+				{Name: "offset", Type: "integer", Description: "Entries to skip"},
+				{Name: "since", Type: "string", Description: "Only entries at or after this RFC 3339 timestamp"},
+			},
+			Response: map[string]interface{}{
+				"path": "", "entries": []catalog.AuditEntry{}, "count": 0, "total": 0, "next_offset": 0,
+			},
+			Errors: []int{400}},
+		{Method: "GET", Path: "/catalog/search", Summary: "Search the catalog by text and facet",
+			Query: []apiParam{
+				{Name: "q", Type: "string", Description: "Text to match; optional when a filter is given"},
+				{Name: "status", Type: "string", Description: "Node status"},
+				{Name: "source_type", Type: "string", Description: "Source type of the node's own binding"},
+				{Name: "tag", Type: "string", Description: "Tag every result carries", Repeatable: true},
+				{Name: "classification", Type: "string", Description: "Resolved classification"},
+				{Name: "domain", Type: "string", Description: "Resolved domain"},
+				{Name: "is_leaf", Type: "boolean", Description: "Only leaves, or only branches"},
+				{Name: "min_quality_score", Type: "number", Description: "Lowest resolved quality score, 0 to 1"},
+				{Name: "include_inherited_tags", Type: "boolean", Description: "Match tags inherited from ancestors"},
+				{Name: "limit", Type: "integer", Description: "Maximum results (default 50)"},
+				exportParam,
+			},
+			Errors: []int{400}},
+		{Method: "GET", Path: "/catalog/stats", Summary: "Node counts by status and source type"},
+		{Method: "GET", Path: "/catalog/validate", Summary: "Validate the served catalog",
+			Query: []apiParam{
+				{Name: "path_prefix", Type: "string", Description: "Only report issues at or below this path"},
+				{Name: "fail_level", Type: "string", Description: "none (default), error or warning: the severity that makes the response a 409"},
+			},
+			Response: catalog.ValidationReport{}, Errors: []int{400, 409}},
+		{Method: "POST", Path: "/catalog/import", Summary: "Validate a catalog and apply it when dry_run=false",
+			Query: []apiParam{
+				{Name: "dry_run", Type: "boolean", Description: "Only validate and diff (default true)"},
+				{Name: "classification", Type: "string", Description: "Default classification of CSV and XLSX rows"},
+				{Name: "accountable_owner", Type: "string", Description: "Default accountable owner of CSV and XLSX rows"},
+				{Name: "data_specialist", Type: "string", Description: "Default data specialist of CSV and XLSX rows"},
+				{Name: "support_channel", Type: "string", Description: "Default support channel of CSV and XLSX rows"},
+			},
+			BodyTypes: []string{"application/yaml", "application/json", "text/csv", catalog.XLSXContentType},
+			Response: map[string]interface{}{
+				"dry_run": false, "applied": false, "digest": "", "summary": "",
+				"diff": catalog.CatalogDiff{}, "validation": catalog.LintReport{},
+			},
+			Errors: []int{400, 422}},
+		{Method: "GET", Path: "/catalog/export", Summary: "Download the catalog as YAML or JSON",
+			Query: []apiParam{
+				{Name: "format", Type: "string", Description: "yaml (default) or json"},
+				{Name: "style", Type: "string", Description: "Export style"},
+				{Name: "path", Type: "string", Description: "Export only the node at this path"},
+				{Name: "path_prefix", Type: "string", Description: "Export only the subtree at this path"},
+			},
+			ResponseType: "application/yaml", Errors: []int{400, 404}},
+		{Method: "GET", Path: "/catalog/governance-report", Summary: "Governance gaps of active and deprecated nodes",
+			Query: []apiParam{
+				{Name: "domain", Type: "string", Description: "Only report nodes of this domain"},
+				exportParam,
+			},
+			Response: catalog.GovernanceReport{}},
+		{Method: "GET", Path: "/deprecations", Summary: "Deprecated nodes with successors and sunset timelines",
+			Query: []apiParam{
+				{Name: "expiring_within", Type: "string", Description: "Window such as 30d or 12h; also limits the list to deadlines within it"},
+				{Name: "domain", Type: "string", Description: "Only nodes of this domain"},
+				{Name: "owner", Type: "string", Description: "Only nodes this identity holds an owner role on"},
+				exportParam,
+			},
+			Response: map[string]interface{}{
+				"deprecations": []catalog.DeprecationEntry{}, "count": 0,
+				"expired": []map[string]interface{}{sunsetEntry}, "expiring": []map[string]interface{}{sunsetEntry},
+				"expiring_within": "", "invalid": []catalog.SunsetIssue{}, "deprecated": 0,
+			},
+			Errors: []int{400}},
+		{Method: "GET", Path: "/owners", Summary: "Directory of owners and the roles they hold", Query: []apiParam{
+			{Name: "cursor", Type: "string", Description: "Start after this owner ID (next_cursor of the previous page)"},
+			{Name: "limit", Type: "integer", Description: "Owners per page (default 100, at most 1000)"},
+		},
+			Response: map[string]interface{}{
+				"owners": []catalog.OwnerSummary{}, "count": 0, "total": 0, "next_cursor": "",
+			}},
+		{Method: "GET", Path: "/owners/{id}", Summary: "Nodes an owner holds roles on, with where each is defined", Query: listParams,
+			Response: map[string]interface{}{
+				"owner": catalog.OwnerSummary{}, "nodes": []catalog.OwnedNode{}, "count": 0, "total": 0, "next_cursor": "",
+			},
+			Errors: []int{404}},
+		{Method: "GET", Path: "/domains", Summary: "Domains with their roots, node counts and ownership gaps",
+			Response: map[string]interface{}{"domains": []catalog.DomainSummary{}, "count": 0}},
+
+		// Admin
+		{Method: "POST", Path: "/admin/reload", Summary: "Reload the catalog from its sources",
+			Response: map[string]interface{}{
+				"reloaded": false, "summary": "", "diff": catalog.CatalogDiff{}, "status": catalog.ReloadStatus{},
+			},
+			Errors: []int{405, 422}},
+		{Method: "GET", Path: "/admin/reload/status", Summary: "Outcome of the last catalog reload",
+			Response: catalog.ReloadStatus{}},
+
+		// Data
+		{Method: "GET", Path: "/fetch/{moniker}", Summary: "Fetch the rows of a moniker from its source",
+			Query: []apiParam{
+				{Name: "format", Type: "string", Description: "json (default), ndjson (a header object then a row per line) or csv"},
+			},
+			Response: adapters.DataResult{}, Errors: []int{400, 403, 404, 410, 429, 501, 502, 504}},
+		{Method: "GET", Path: "/versions/{moniker}", Summary: "Versions available for a moniker",
+			Response: service.VersionsResult{}, Errors: []int{400, 404, 429}},
+
+		// Cache and telemetry
+		{Method: "GET", Path: "/cache/status", Summary: "Cache size, hit counters and namespace breakdown",
+			Response: map[string]interface{}{
+				"status": "", "backend": "", "message": "", "size": 0, "hits": 0, "misses": 0, "hit_ratio": 0.0,
+				"evictions": 0, "expired_on_read": 0, "estimated_bytes": 0, "ttl_seconds": 0.0, "last_invalidated": "",
+				"namespaces": map[string]cache.NamespaceStats{},
+				"cleanup":    map[string]interface{}{"running": false, "interval_seconds": 0.0},
+			}},
+		{Method: "POST", Path: "/cache/refresh", Summary: "Empty the cache", Query: refreshParams,
+			Response: refreshResponse, Errors: []int{400}},
+		{Method: "POST", Path: "/cache/refresh/{path}", Summary: "Drop the cached results of a path and everything beneath it",
+			Query: refreshParams, Response: refreshResponse, Errors: []int{400}},
+		{Method: "POST", Path: "/telemetry/access", Summary: "Record a client access event",
+			Body: telemetry.AccessEvent{}, Status: http.StatusAccepted,
+			Response: map[string]interface{}{"status": "", "message": "", "event": telemetry.AccessEvent{}},
+			Errors:   []int{400, 500}},
+		{Method: "GET", Path: "/telemetry/stats", Summary: "Access events of a window by path prefix, caller and outcome",
+			Query: []apiParam{
+				{Name: "window", Type: "string", Description: "Window ending now, e.g. 1h or 7d (default 24h)"},
+				{Name: "depth", Type: "integer", Description: "Path segments events are grouped by (default 1; 0 for full paths)"},
+				{Name: "prefix", Type: "string", Description: "Count only events at or beneath this path"},
+			},
+			Response: map[string]interface{}{
+				"window": "", "depth": 0, "from": "", "to": "", "events": 0,
+				"by_path": []telemetry.Bucket{}, "by_caller": []telemetry.Bucket{}, "by_outcome": map[string]int{},
+			},
+			Errors: []int{400, 500, 501}},
+
+		// UI and documentation
+		{Method: "GET", Path: "/ui", Summary: "Catalog browser", ResponseType: "text/html"},
+		{Method: "GET", Path: "/ui/node/{path}", Summary: "Catalog browser page of a node", ResponseType: "text/html",
+			Errors: []int{404}},
+		{Method: "GET", Path: "/ui/search", Summary: "Catalog browser search results", ResponseType: "text/html",
+			Query: []apiParam{{Name: "q", Type: "string", Description: "Text matched against names, paths, descriptions and tags"}}},
+		{Method: "GET", Path: "/openapi.json", Summary: "This OpenAPI document"},
+		{Method: "GET", Path: "/docs", Summary: "Swagger UI for this document", ResponseType: "text/html"},
+	}
+}
+
+var (
+	resolveParams = []apiParam{
+		{Name: "explain", Type: "boolean", Description: "Attach a trace of how the binding was found, also to an access policy denial"},
+		{Name: "expand_all", Type: "boolean", Description: "List the monikers the moniker's ALL segments expand into"},
+		{Name: "format", Type: "string", Description: "json, the only response format"},
+		{Name: MonikerParamPrefix + "{name}", Type: "string", Description: "Moniker param name, overriding one in the moniker; other params are refused"},
+	}
+	checkParams = []apiParam{
+		{Name: MonikerParamPrefix + "{name}", Type: "string", Description: "Moniker param name, overriding one in the moniker; other params are refused"},
+	}
+	checkHeaders = []apiParam{
+		{Name: BindingPathHeader, Type: "string", Description: "Path of the node whose binding answers; absent when there is none"},
+		{Name: SourceTypeHeader, Type: "string", Description: "Source type of the binding"},
+		{Name: DeprecatedHeader, Type: "string", Description: "true when the node is deprecated; absent otherwise"},
+	}
+	qualityResponse = map[string]interface{}{
+		"path": "", "data_quality": catalog.DataQuality{}, "resolved_data_quality": catalog.ResolvedDataQuality{},
+	}
+	listParams = []apiParam{
+		{Name: "cursor", Type: "string", Description: "Start after this path (next_cursor of the previous page)"},
+		{Name: "limit", Type: "integer", Description: "Paths per page (default 100, at most 1000)"},
+	}
+	// exportParam selects the format of a list endpoint; Accept: text/csv or
+	// application/x-ndjson does the same
+	exportParam       = apiParam{Name: "format", Type: "string", Description: "json (default), csv (a download) or ndjson (an item per line); or send Accept: text/csv or application/x-ndjson"}
+	catalogListParams = []apiParam{
+		{Name: "cursor", Type: "string", Description: "Start after this path (next_cursor of the previous page)"},
+		{Name: "limit", Type: "integer", Description: "Paths per page (default 100, at most 1000; csv and ndjson list every path unless set)"},
+		exportParam,
+	}
+	refreshParams = []apiParam{
+		{Name: "warm", Type: "boolean", Description: "Cache again the bindings in scope whose cache sets refresh_on_startup"},
+	}
+	refreshResponse = map[string]interface{}{
+		"status": "", "scope": "", "path": "", "invalidated": 0, "warmed": 0,
+		"warmed_paths": []string{}, "warm_failures": map[string]string{},
+	}
+	editParams = []apiParam{
+		{Name: "allow_binding_change", Type: "boolean", Description: "Allow edits that change the source binding contract (never for bindings that reference secret://)"},
+	}
+	treeParams = []apiParam{
+		{Name: "depth", Type: "integer", Description: "Levels to list (default 1, capped by the server)"},
+		{Name: "include_counts", Type: "boolean", Description: "Add descendant_count to every node"},
+		{Name: "include_archived", Type: "boolean", Description: "List archived branches"},
+	}
+	treeResponse = map[string]interface{}{
+		"path": "", "node": catalog.CatalogNode{}, "virtual": false, "children": []catalog.TreeNode{},
+		"count": 0, "depth": 0, "node_count": 0, "truncated": false, "descendant_count": 0, "max_nodes": 0,
+	}
+	sunsetEntry = map[string]interface{}{
+		"path": "", "display_name": "", "sunset_deadline": "", "successor": "", "migration_guide_url": "",
+	}
+)
+
+// pathParamPattern matches the parameters of an OpenAPI path template
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// adminRoute reports whether AdminMiddleware guards route
+func adminRoute(route apiRoute) bool {
+	path := pathParamPattern.ReplaceAllString(route.Path, "x")
+	return AdminRouteGroup(&http.Request{Method: route.Method, URL: &url.URL{Path: path}}) != ""
+}
+
+// BuildOpenAPI builds the OpenAPI 3 document of the routes Routes.Register
+// serves. Schemas are derived from the Go types of the request and response
+// bodies; named structs become components.
+func BuildOpenAPI(title string) map[string]interface{} {
+	if title == "" {
+		title = "Moniker Service"
+	}
+	schemas := newSchemaSet()
+	errorRef := schemas.of(ErrorResponse{})
+	codes := make([]string, 0, len(errorCodes))
+	for _, c := range errorCodes {
+		codes = append(codes, fmt.Sprintf("- `%s`: %s", c.Code, c.Description))
+	}
+	schemas.components["ErrorBody"]["properties"].(map[string]interface{})["code"] = map[string]interface{}{
+		"type":        "string",
+		"enum":        enums[reflect.TypeOf(ErrorCode(""))](),
+		"description": "Stable code to branch on:\n" + strings.Join(codes, "\n"),
+	}
+
+	paths := map[string]interface{}{}
+	for _, route := range apiRoutes() {
+		op := map[string]interface{}{
+			"summary":     route.Summary,
+			"operationId": operationID(route.Method, route.Path),
+			"tags":        []string{operationTag(route.Path)},
+		}
+
+		var params []interface{}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true,
+				"description": "Catalog path or moniker; may contain '/'",
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, q := range route.Query {
+			schema := map[string]interface{}{"type": q.Type}
+			if q.Repeatable {
+				schema = map[string]interface{}{"type": "array", "items": schema}
+			}
+			params = append(params, map[string]interface{}{
+				"name": q.Name, "in": "query", "description": q.Description, "schema": schema,
+			})
+		}
+		if route.ETag {
+			params = append(params, map[string]interface{}{
+				"name": "If-None-Match", "in": "header", "description": "ETag of a previous response",
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if route.Body != nil {
+			content := map[string]interface{}{"application/json": map[string]interface{}{"schema": schemas.of(route.Body)}}
+			for _, mediaType := range route.BodyTypes {
+				if mediaType != "application/json" {
+					content[mediaType] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+				}
+			}
+			op["requestBody"] = map[string]interface{}{"required": true, "content": content}
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]interface{}{"description": http.StatusText(status)}
+		switch {
+		case route.Method == http.MethodHead:
+			// HEAD responses have no body
+		case route.ResponseType != "":
+			success["content"] = map[string]interface{}{route.ResponseType: map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}}
+		case route.Response != nil:
+			success["content"] = jsonContent(schemas.of(route.Response))
+		default:
+			success["content"] = jsonContent(map[string]interface{}{"type": "object"})
+		}
+		if len(route.Headers) > 0 {
+			headers := make(map[string]interface{}, len(route.Headers))
+			for _, h := range route.Headers {
+				headers[h.Name] = map[string]interface{}{
+					"description": h.Description, "schema": map[string]interface{}{"type": h.Type},
+				}
+			}
+			success["headers"] = headers
+		}
+		responses := map[string]interface{}{fmt.Sprint(status): success}
+		for _, code := range route.Errors {
+			response := map[string]interface{}{"description": http.StatusText(code)}
+			if route.Method != http.MethodHead {
+				response["content"] = jsonContent(errorRef)
+			}
+			responses[fmt.Sprint(code)] = response
+		}
+		if route.Body != nil || len(route.BodyTypes) > 0 {
+			// Every body is capped (see BodyLimits) and must be of a type the
+			// route takes
+			for _, code := range []int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType} {
+				responses[fmt.Sprint(code)] = map[string]interface{}{
+					"description": http.StatusText(code),
+					"content":     jsonContent(errorRef),
+				}
+			}
+		}
+		if adminRoute(route) {
+			for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+				responses[fmt.Sprint(code)] = map[string]interface{}{
+					"description": http.StatusText(code) + " (with auth.admin_roles set)",
+					"content":     jsonContent(errorRef),
+				}
+			}
+		}
+		if route.ETag {
+			responses["304"] = map[string]interface{}{"description": "The catalog has not changed since the response with that ETag"}
+		}
+		op["responses"] = responses
+
+		item, _ := paths[route.Path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": APIVersion,
+			"description": "Resolves monikers to their data sources and serves the catalog that defines them. " +
+				"Callers are identified by a bearer token, an API key or, behind a trusted gateway, the X-User-ID header.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]interface{}{
+					"type": "apiKey", "in": "header", "name": "X-API-Key",
+					"description": "Key scopes: read, resolve (resolve, estimate, fetch) and admin (catalog changes)",
+				},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{"bearerAuth": []string{}},
+			map[string]interface{}{"apiKeyAuth": []string{}},
+		},
+	}
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// operationID names an operation from its method and path, e.g.
+// GET /catalog/{path}/audit is getCatalogPathAudit
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.'
+	}) {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
+
+// operationTag groups an operation by the first segment of its path
+func operationTag(path string) string {
+	segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	return strings.TrimSuffix(segment, ".json")
+}
+
+// schemaSet derives JSON schemas from Go values, collecting named structs
+// as components
+type schemaSet struct {
+	components map[string]map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{components: map[string]map[string]interface{}{}, names: map[reflect.Type]string{}}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	// enums lists the values of string types with a fixed set of them
+	enums = map[reflect.Type]func() []string{
+		reflect.TypeOf(catalog.NodeStatus("")): func() []string {
+			var values []string
+			for _, s := range catalog.NodeStatuses() {
+				values = append(values, string(s))
+			}
+			return values
+		},
+		reflect.TypeOf(ErrorCode("")): func() []string {
+			var values []string
+			for _, c := range errorCodes {
+				values = append(values, string(c.Code))
+			}
+			return values
+		},
+		reflect.TypeOf(catalog.SourceType("")): func() []string {
+			var values []string
+			for _, t := range catalog.SourceTypes() {
+				values = append(values, string(t))
+			}
+			return values
+		},
+	}
+)
+
+// of returns the schema of v: an object with a property per key for a
+// map[string]interface{}, otherwise the schema of its type
+func (s *schemaSet) of(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+		properties := map[string]interface{}{}
+		for k, value := range m {
+			properties[k] = s.of(value)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	}
+	return s.typeSchema(reflect.TypeOf(v))
+}
+
+func (s *schemaSet) typeSchema(t reflect.Type) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return map[string]interface{}{} // Custom encoding: any JSON value
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		schema := map[string]interface{}{"type": "string"}
+		if values, ok := enums[t]; ok {
+			schema["enum"] = values()
+		}
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": s.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + s.component(t)}
+	}
+	return map[string]interface{}{} // interface{}: any JSON value
+}
+
+// component registers the named struct t as a component and returns its
+// name, qualified by package when another package has a type of that name
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	s.components[name] = map[string]interface{}{} // Placeholder for recursive types
+	for k, v := range s.structSchema(t) {
+		s.components[name][k] = v
+	}
+	return name
+}
+
+// structSchema describes the fields encoding/json writes for t. Fields
+// without omitempty are required; embedded structs without a json name are
+// inlined.
+func (s *schemaSet) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	s.addFields(t, properties, &required)
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (s *schemaSet) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.typeSchema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// OpenAPIHandler handles GET /openapi.json
+type OpenAPIHandler struct {
+	document []byte
+}
+
+// NewOpenAPIHandler creates a handler serving the OpenAPI document of the
+// resolver's routes, built once with title as the API title
+func NewOpenAPIHandler(title string) *OpenAPIHandler {
+	document, err := json.MarshalIndent(BuildOpenAPI(title), "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %v", err)) // Only maps of JSON values are marshaled
+	}
+	return &OpenAPIHandler{document: document}
+}
+
+// ServeHTTP implements http.Handler
+func (h *OpenAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.document)
+}
+
+// DocsHandler handles GET /docs, a Swagger UI page for /openapi.json
+type DocsHandler struct{}
+
+// NewDocsHandler creates a new API documentation handler
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// ServeHTTP implements http.Handler
+func (h *DocsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Moniker Resolver API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    </script>
+</body>
+</html>`
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, html)
+}
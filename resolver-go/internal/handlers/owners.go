@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// OwnersHandler handles GET /owners, the directory of every identity named
+// as accountable owner, ADOP, ADS or ADAL of a node after inheritance, and
+// GET /owners/{id}, the nodes one owner holds roles on with where each role
+// is defined. Both are paged in order with cursor and limit like GET
+// /catalog: /owners by owner ID and /owners/{id} by node path. Responses
+// have an ETag; If-None-Match with it gets a 304 until the catalog changes.
+type OwnersHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewOwnersHandler creates a new owner directory handler
+func NewOwnersHandler(reg *catalog.Registry) *OwnersHandler {
+	return &OwnersHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *OwnersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if notModified(w, r, catalogETag(h.catalog, r)) {
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/owners"), "/")
+	if id == "" {
+		owners, total, more := h.catalog.OwnersAfter(cursor, limit)
+		response := map[string]interface{}{
+			"owners": owners,
+			"count":  len(owners),
+			"total":  total,
+		}
+		if more {
+			response["next_cursor"] = owners[len(owners)-1].ID
+		}
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	owner := h.catalog.Owner(id)
+	if owner == nil {
+		writeError(w, http.StatusNotFound, ErrNotFound, "Owner not found", map[string]interface{}{
+			"detail": "No catalog node names " + id + " as an owner",
+			"id":     id,
+		})
+		return
+	}
+
+	// Page by path, keeping every role of a path on the same page
+	nodes := make([]catalog.OwnedNode, 0)
+	paths, more := 0, false
+	for i, node := range owner.Nodes {
+		if node.Path <= cursor {
+			continue
+		}
+		if i == 0 || owner.Nodes[i-1].Path != node.Path {
+			if paths == limit {
+				more = true
+				break
+			}
+			paths++
+		}
+		nodes = append(nodes, node)
+	}
+
+	response := map[string]interface{}{
+		"owner": owner.OwnerSummary,
+		"nodes": nodes,
+		"count": paths,
+		"total": owner.TotalNodes,
+	}
+	if more {
+		response["next_cursor"] = nodes[len(nodes)-1].Path
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// pathFromRequest returns the catalog path or moniker a request names after
+// prefix, and before suffix when one is given (e.g. "/status"). It works on
+// the escaped URL path, so a segment is decoded exactly once however the
+// client escaped it: prices%2Eequity and prices.equity are the same path.
+// Trailing slashes are dropped. A path with an empty segment (a double
+// slash), a "." or ".." segment or a bad escape is an error, which the
+// caller answers with writePathError. A moniker query, from a %3F in the
+// path, is kept as it is and not checked.
+func pathFromRequest(r *http.Request, prefix, suffix string) (string, error) {
+	escaped := strings.TrimRight(r.URL.EscapedPath(), "/")
+	rest, ok := strings.CutPrefix(escaped+"/", prefix)
+	if !ok {
+		return "", fmt.Errorf("path must start with %s", prefix)
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	if suffix != "" {
+		rest = strings.TrimSuffix(strings.TrimSuffix(rest, suffix), "/")
+	}
+	if rest == "" {
+		return "", nil
+	}
+
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return "", fmt.Errorf("segment %q is not percent-encoded correctly", segment)
+		}
+		segments[i] = decoded
+	}
+	path := strings.Join(segments, "/")
+
+	// Segments are checked once decoded, so an escaped slash or dot can't
+	// hide one
+	base, _, _ := strings.Cut(path, "?")
+	for _, segment := range strings.Split(base, "/") {
+		switch segment {
+		case "":
+			return "", fmt.Errorf("path %q has an empty segment", path)
+		case ".", "..":
+			return "", fmt.Errorf("path %q has a %q segment", path, segment)
+		}
+	}
+	return path, nil
+}
+
+// writePathError answers a request whose path pathFromRequest refused
+func writePathError(w http.ResponseWriter, err error) {
+	writeError(w, http.StatusBadRequest, ErrInvalidPath, "Invalid path", map[string]interface{}{
+		"detail": err.Error(),
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/ratelimit"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// RateLimitMiddleware throttles each caller with a token bucket. Callers
+// authenticated by a JWT or API key are keyed by user ID; others, whose
+// X-User-ID anyone could vary, by client IP. Throttled requests get 429 with
+// Retry-After.
+type RateLimitMiddleware struct {
+	next    http.Handler
+	limiter *ratelimit.Limiter
+	proxies TrustedProxies
+}
+
+// NewRateLimitMiddleware wraps next. The client IP of a request from one of
+// proxies is read from X-Forwarded-For.
+func NewRateLimitMiddleware(next http.Handler, limiter *ratelimit.Limiter, proxies TrustedProxies) *RateLimitMiddleware {
+	return &RateLimitMiddleware{next: next, limiter: limiter, proxies: proxies}
+}
+
+// ServeHTTP implements http.Handler
+func (m *RateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := rateLimitKey(r, m.proxies)
+	if ok, wait := m.limiter.Allow(key, r.URL.Path); !ok {
+		seconds := int(math.Ceil(wait.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeError(w, http.StatusTooManyRequests, ErrRateLimited, "Too many requests", map[string]interface{}{
+			"detail":              "Rate limit exceeded for " + key,
+			"retry_after_seconds": seconds,
+		})
+		return
+	}
+	m.next.ServeHTTP(w, r)
+}
+
+// rateLimitKey returns the user ID of a verified caller, otherwise
+// "ip:<client address>"
+func rateLimitKey(r *http.Request, proxies TrustedProxies) string {
+	if caller := service.CallerFromContext(r.Context()); caller != nil && caller.Verified() && caller.UserID != "" {
+		return caller.UserID
+	}
+	return "ip:" + proxies.ClientIP(r)
+}
+
+// TrustedProxies are the reverse proxies whose X-Forwarded-For is believed
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses addresses and CIDRs, as in server.trusted_proxies
+func ParseTrustedProxies(entries []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(entries))
+	for _, entry := range entries {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an address or CIDR", entry)
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+// contains reports whether host is the address of a trusted proxy
+func (p TrustedProxies) contains(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent r: its remote
+// address, or when that is a trusted proxy, the right-most X-Forwarded-For
+// address that isn't one. Addresses left of it were supplied by the client
+// and aren't believed.
+func (p TrustedProxies) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !p.contains(host) {
+		return host
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !p.contains(hop) {
+			return hop
+		}
+		host = hop
+	}
+	return host
+}
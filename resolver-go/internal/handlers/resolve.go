@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
 )
 
@@ -18,35 +24,168 @@ func NewResolveHandler(svc *service.MonikerService) *ResolveHandler {
 	return &ResolveHandler{service: svc}
 }
 
-// ServeHTTP implements http.Handler
+// MonikerParamPrefix marks the URL query params of GET /resolve that are
+// moniker params: ?m_format=json resolves the moniker with format=json
+const MonikerParamPrefix = "m_"
+
+// resolveQueryParams are the URL query params GET /resolve takes itself.
+// format names the response format, which is only ever json.
+var resolveQueryParams = map[string]bool{"moniker": true, "explain": true, "expand_all": true, "format": true}
+
+// ServeHTTP implements http.Handler. The moniker is the rest of the path,
+// percent-decoded once, or the whole moniker URL-encoded in ?moniker=, never
+// both. Its params are those after a ? in the moniker itself (%3F in the
+// path form) and the URL query params prefixed m_, which take precedence;
+// other URL query params are the handler's own, and unknown ones are
+// refused rather than dropped. HEAD answers with the status GET would and
+// the check headers, without building the payload.
 func (h *ResolveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Extract path from URL
-	path := strings.TrimPrefix(r.URL.Path, "/resolve/")
+	path, err := monikerFromRequest(r, "/resolve/")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidMoniker, "Invalid moniker", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing moniker path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing moniker path", nil)
 		return
 	}
 
-	// Get caller identity (simplified for now)
-	caller := &service.CallerIdentity{
-		UserID: r.Header.Get("X-User-ID"),
-		Source: "api",
-	}
-	if caller.UserID == "" {
-		caller.UserID = "anonymous"
+	caller := callerFromRequest(r, h.service.RolesHeader())
+	if r.Method == http.MethodHead {
+		result, err := h.service.Check(r.Context(), path, caller)
+		if err != nil {
+			handleServiceError(w, err)
+			return
+		}
+		setCheckHeaders(w, result.BindingPath, result.Source.SourceType, result.Deprecated())
+		if warning := result.WarningHeader(); warning != "" {
+			w.Header().Set("Warning", warning)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
 	}
 
-	// Resolve the moniker
-	result, err := h.service.Resolve(r.Context(), path, caller)
+	// Resolve the moniker; ?explain=true attaches a trace and
+	// ?expand_all=true the monikers its ALL segments expand into
+	opts := service.ResolveOptions{
+		Explain:   r.URL.Query().Get("explain") == "true",
+		ExpandAll: r.URL.Query().Get("expand_all") == "true",
+	}
+	result, err := h.service.ResolveWithOptions(r.Context(), path, caller, opts)
 	if err != nil {
 		handleServiceError(w, err)
 		return
 	}
+	if warning := result.WarningHeader(); warning != "" {
+		w.Header().Set("Warning", warning)
+	}
 
 	// Return result as JSON
 	writeJSON(w, http.StatusOK, result)
 }
 
+// Headers of HEAD /resolve and HEAD /metadata, which answer without a body
+const (
+	BindingPathHeader = "X-Binding-Path"
+	SourceTypeHeader  = "X-Source-Type"
+	DeprecatedHeader  = "X-Deprecated" // "true" when the node is deprecated, absent otherwise
+)
+
+// setCheckHeaders sets the headers of a HEAD response for a path with a
+// binding at bindingPath ("" for none) of sourceType
+func setCheckHeaders(w http.ResponseWriter, bindingPath, sourceType string, deprecated bool) {
+	if bindingPath != "" {
+		w.Header().Set(BindingPathHeader, bindingPath)
+		w.Header().Set(SourceTypeHeader, sourceType)
+	}
+	if deprecated {
+		w.Header().Set(DeprecatedHeader, "true")
+	}
+}
+
+// ExistsHandler handles GET /exists/{moniker}: HEAD /resolve as a small JSON
+// body, for clients behind proxies that mangle HEAD. It answers 200 whether
+// or not the moniker resolves; the status GET /resolve would answer with is
+// in the body.
+type ExistsHandler struct {
+	service *service.MonikerService
+	catalog *catalog.Registry
+}
+
+// ExistsResult is the body of GET /exists/{moniker}
+type ExistsResult struct {
+	Exists bool `json:"exists"`  // GET /resolve would succeed
+	Status int  `json:"status"`  // The status GET /resolve would answer with
+	IsLeaf bool `json:"is_leaf"` // The moniker resolves to a node without children
+}
+
+// NewExistsHandler creates a new exists handler
+func NewExistsHandler(svc *service.MonikerService, reg *catalog.Registry) *ExistsHandler {
+	return &ExistsHandler{service: svc, catalog: reg}
+}
+
+// ServeHTTP implements http.Handler. The moniker is given as for GET
+// /resolve; a malformed request is still a 400.
+func (h *ExistsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := monikerFromRequest(r, "/exists/")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrInvalidMoniker, "Invalid moniker", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing moniker path", nil)
+		return
+	}
+
+	caller := callerFromRequest(r, h.service.RolesHeader())
+	result, err := h.service.Check(r.Context(), path, caller)
+	if err != nil {
+		writeJSON(w, http.StatusOK, ExistsResult{Status: describeServiceError(err).status})
+		return
+	}
+	writeJSON(w, http.StatusOK, ExistsResult{
+		Exists: true,
+		Status: http.StatusOK,
+		IsLeaf: len(h.catalog.ChildrenPaths(result.Path)) == 0,
+	})
+}
+
+// EstimateHandler handles /estimate/{moniker} requests
+type EstimateHandler struct {
+	service *service.MonikerService
+}
+
+// NewEstimateHandler creates a new estimate handler
+func NewEstimateHandler(svc *service.MonikerService) *EstimateHandler {
+	return &EstimateHandler{service: svc}
+}
+
+// ServeHTTP implements http.Handler. A moniker the access policy would block
+// is still a 200; the decision is in the body.
+func (h *EstimateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/estimate/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing moniker path", nil)
+		return
+	}
+
+	caller := callerFromRequest(r, h.service.RolesHeader())
+	result, err := h.service.Estimate(r.Context(), path, caller)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 // DescribeHandler handles /describe/{path} requests
 type DescribeHandler struct {
 	service *service.MonikerService
@@ -59,9 +198,13 @@ func NewDescribeHandler(svc *service.MonikerService) *DescribeHandler {
 
 // ServeHTTP implements http.Handler
 func (h *DescribeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/describe/")
+	path, err := pathFromRequest(r, "/describe/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 	if path == "" {
-		writeError(w, http.StatusBadRequest, "Missing path", nil)
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
 		return
 	}
 
@@ -86,7 +229,11 @@ func NewListHandler(svc *service.MonikerService) *ListHandler {
 
 // ServeHTTP implements http.Handler
 func (h *ListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/list/")
+	path, err := pathFromRequest(r, "/list/", "")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
 	// Empty path means list root
 
 	result, err := h.service.List(r.Context(), path)
@@ -100,35 +247,149 @@ func (h *ListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
-func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+// monikerFromRequest returns the moniker a GET /resolve request names, as
+// ResolveHandler documents, with its m_ params merged into its query
+func monikerFromRequest(r *http.Request, prefix string) (string, error) {
+	path, err := pathFromRequest(r, prefix, "")
+	if err != nil {
+		return "", err
+	}
+	query := r.URL.Query()
+	moniker := path
+	if query.Has("moniker") {
+		if path != "" {
+			return "", fmt.Errorf("give the moniker in the path or in ?moniker=, not both")
+		}
+		moniker = query.Get("moniker")
+	}
+
+	params := url.Values{}
+	for key, values := range query {
+		name, isParam := strings.CutPrefix(key, MonikerParamPrefix)
+		switch {
+		case isParam && name == "":
+			return "", fmt.Errorf("query parameter %q names no moniker param", key)
+		case isParam:
+			params[name] = values[:1]
+		case !resolveQueryParams[key]:
+			return "", fmt.Errorf("unknown query parameter %q; moniker params take the %s prefix, e.g. %s%s", key, MonikerParamPrefix, MonikerParamPrefix, key)
+		}
+	}
+	if format := query.Get("format"); format != "" && format != formatJSON {
+		return "", fmt.Errorf("format must be 'json'; a moniker param named format takes the %s prefix, %sformat", MonikerParamPrefix, MonikerParamPrefix)
+	}
+	if len(params) == 0 || moniker == "" {
+		return moniker, nil
+	}
+
+	base, embedded, _ := strings.Cut(moniker, "?")
+	merged, err := url.ParseQuery(embedded)
+	if err != nil {
+		return "", fmt.Errorf("moniker query is not URL-encoded correctly: %v", err)
+	}
+	for name, values := range params {
+		merged[name] = values
+	}
+	return base + "?" + merged.Encode(), nil
 }
 
-func writeError(w http.ResponseWriter, status int, message string, details map[string]interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+// callerFromRequest returns the caller authenticated by AuthMiddleware, or
+// when there is none, the identity in the request headers
+func callerFromRequest(r *http.Request, rolesHeader string) *service.CallerIdentity {
+	if caller := service.CallerFromContext(r.Context()); caller != nil {
+		return caller
+	}
+	return callerFromHeaders(r, rolesHeader)
+}
 
-	response := map[string]interface{}{
-		"error": message,
+// callerFromHeaders builds the caller identity from request headers. Roles
+// are read, comma-separated, from rolesHeader, or service.DefaultRolesHeader
+// when it is empty.
+func callerFromHeaders(r *http.Request, rolesHeader string) *service.CallerIdentity {
+	caller := &service.CallerIdentity{
+		UserID: r.Header.Get("X-User-ID"),
+		Source: "api",
+	}
+	if caller.UserID == "" {
+		caller.UserID = service.AnonymousUser
+	}
+	if rolesHeader == "" {
+		rolesHeader = service.DefaultRolesHeader
 	}
-	if details != nil {
-		for k, v := range details {
-			response[k] = v
+	for _, role := range strings.Split(r.Header.Get(rolesHeader), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			caller.Roles = append(caller.Roles, role)
 		}
 	}
+	return caller
+}
+
+// sunsetDetails describes a sunset node for error responses, pointing the
+// caller at its replacement
+func sunsetDetails(e *service.SunsetError) map[string]interface{} {
+	details := map[string]interface{}{
+		"path":            e.Path,
+		"sunset_deadline": e.SunsetDeadline,
+	}
+	if e.Successor != nil {
+		details["successor"] = *e.Successor
+	}
+	if e.MigrationGuideURL != nil {
+		details["migration_guide_url"] = *e.MigrationGuideURL
+	}
+	return details
+}
 
-	json.NewEncoder(w).Encode(response)
+// goneDetails describes an archived node for error responses
+func goneDetails(e *service.GoneError) map[string]interface{} {
+	details := map[string]interface{}{
+		"path": e.Path,
+	}
+	if len(e.SuccessorChain) > 0 {
+		details["successor_chain"] = e.SuccessorChain
+		details["successor"] = e.SuccessorChain[len(e.SuccessorChain)-1]
+	}
+	if e.MigrationGuideURL != nil {
+		details["migration_guide_url"] = *e.MigrationGuideURL
+	}
+	return details
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
 }
 
 func handleServiceError(w http.ResponseWriter, err error) {
+	e := describeServiceError(err)
+	writeError(w, e.status, e.code, e.title, e.details)
+}
+
+// serviceError is how an error of the service is answered: the status and
+// title of the response, a machine-readable code and the response details
+type serviceError struct {
+	status  int
+	title   string
+	code    ErrorCode
+	details map[string]interface{}
+}
+
+// describeServiceError returns how err is answered
+func describeServiceError(err error) serviceError {
 	switch e := err.(type) {
 	case *service.NotFoundError:
-		writeError(w, http.StatusNotFound, "Not found", map[string]interface{}{
+		return serviceError{http.StatusNotFound, "Not found", ErrMonikerNotFound, map[string]interface{}{
 			"detail": e.Error(),
 			"path":   e.Path,
-		})
+		}}
+	case *service.SubResourceNotFoundError:
+		return serviceError{http.StatusNotFound, "Sub-resource not found", ErrSubResourceNotFound, map[string]interface{}{
+			"detail":       e.Error(),
+			"path":         e.Path,
+			"sub_resource": e.SubResource,
+			"available":    e.Available,
+		}}
 	case *service.AccessDeniedError:
 		details := map[string]interface{}{
 			"detail": e.Message,
@@ -136,14 +397,73 @@ func handleServiceError(w http.ResponseWriter, err error) {
 		if e.EstimatedRows != nil {
 			details["estimated_rows"] = *e.EstimatedRows
 		}
-		writeError(w, http.StatusForbidden, "Access denied", details)
+		if e.AllowedHours != nil {
+			details["allowed_hours"] = e.AllowedHours
+			details["current_hour_utc"] = e.CurrentHour
+		}
+		if e.Trace != nil {
+			details["trace"] = e.Trace
+		}
+		return serviceError{http.StatusForbidden, "Access denied", ErrAccessDenied, details}
+	case *service.ExpansionTooLargeError:
+		return serviceError{http.StatusRequestEntityTooLarge, "Expansion too large", ErrExpansionTooLarge, map[string]interface{}{
+			"detail":         e.Error(),
+			"max_expansions": e.Max,
+		}}
+	case *service.SunsetError:
+		details := sunsetDetails(e)
+		details["detail"] = e.Error()
+		return serviceError{http.StatusGone, "Sunset", ErrSunset, details}
+	case *service.GoneError:
+		details := goneDetails(e)
+		details["detail"] = e.Error()
+		return serviceError{http.StatusGone, "Gone", ErrGone, details}
 	case *service.ResolutionError:
-		writeError(w, http.StatusBadRequest, "Resolution error", map[string]interface{}{
+		return serviceError{http.StatusBadRequest, "Resolution error", ErrInvalidMoniker, map[string]interface{}{
 			"detail": e.Error(),
-		})
-	default:
-		writeError(w, http.StatusInternalServerError, "Internal server error", map[string]interface{}{
+		}}
+	case *adapters.RequestError:
+		return serviceError{http.StatusBadRequest, "Invalid fetch", ErrInvalidFetch, map[string]interface{}{
+			"detail": e.Error(),
+			"path":   e.Path,
+		}}
+	case *service.FetchNotSupportedError:
+		return serviceError{http.StatusNotImplemented, "Data fetch not implemented", ErrNotImplemented, map[string]interface{}{
+			"detail":      e.Error(),
+			"path":        e.Path,
+			"source_type": e.SourceType,
+		}}
+	case *service.VersionsNotSupportedError:
+		return serviceError{http.StatusNotImplemented, "Version listing not implemented", ErrNotImplemented, map[string]interface{}{
+			"detail":      e.Error(),
+			"path":        e.Path,
+			"source_type": e.SourceType,
+		}}
+	case *service.OperationNotAllowedError:
+		return serviceError{http.StatusForbidden, "Operation not allowed", ErrOperationNotAllowed, map[string]interface{}{
+			"detail":       e.Error(),
+			"operation":    e.Operation,
+			"binding_path": e.BindingPath,
+			"allowed":      e.Allowed,
+		}}
+	case *service.FetchTimeoutError:
+		return serviceError{http.StatusGatewayTimeout, "Fetch timed out", ErrUpstreamTimeout, map[string]interface{}{
+			"detail":          e.Error(),
+			"binding_path":    e.BindingPath,
+			"timeout_seconds": e.Timeout.Seconds(),
+		}}
+	case *service.FetchError:
+		return serviceError{http.StatusBadGateway, "Fetch failed", ErrUpstreamFailed, map[string]interface{}{
+			"detail":       e.Error(),
+			"binding_path": e.BindingPath,
+		}}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return serviceError{http.StatusServiceUnavailable, "Request cancelled", ErrUnavailable, map[string]interface{}{
 			"detail": err.Error(),
-		})
+		}}
 	}
+	return serviceError{http.StatusInternalServerError, "Internal server error", ErrInternal, map[string]interface{}{
+		"detail": err.Error(),
+	}}
 }
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/metrics"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/telemetry"
+)
+
+// Mux is an http.ServeMux that remembers the patterns registered on it, so
+// the routes served can be compared with the ones documented
+type Mux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+// NewMux creates an empty Mux
+func NewMux() *Mux {
+	return &Mux{ServeMux: http.NewServeMux()}
+}
+
+// Handle registers handler for pattern
+func (m *Mux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+// HandleFunc registers handler for pattern
+func (m *Mux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// Patterns returns the registered patterns in registration order
+func (m *Mux) Patterns() []string {
+	return append([]string(nil), m.patterns...)
+}
+
+// Routes holds what the resolver's HTTP routes are served from
+type Routes struct {
+	Title        string // API title in the OpenAPI document
+	Service      *service.MonikerService
+	Catalog      *catalog.Registry
+	Reloader     *catalog.ReloadManager
+	Cache        *cache.InMemory
+	Metrics      *metrics.Registry
+	OwnerPattern *regexp.Regexp // Accepted owner identifiers; catalog.DefaultOwnerPattern if nil
+	SunsetWindow time.Duration  // Default window of GET /deprecations
+	TreeMaxDepth int
+	TreeMaxNodes int
+
+	// Health serves /health, which the caller builds from its runtime state
+	Health http.Handler
+
+	// Probes are the dependencies GET /health/ready checks
+	Probes []Probe
+
+	// RateLimit wraps the resolve and fetch routes; nil leaves them unlimited
+	RateLimit func(http.Handler) http.Handler
+
+	// Telemetry records the access events clients report; nil discards them
+	Telemetry *telemetry.Recorder
+
+	// BodyLimits caps request bodies by endpoint group; zero fields take the
+	// defaults
+	BodyLimits BodyLimits
+
+	// CORS lists the origins browsers may call the API from
+	CORS CORSPolicy
+
+	// Draining reports a shutdown in progress, during which GET /health/ready
+	// fails; nil never drains
+	Draining func() bool
+}
+
+// Register registers every route on mux. Each one must also be described by
+// apiRoutes, which TestOpenAPICoversEveryRoute checks.
+func (rt *Routes) Register(mux *Mux) {
+	rateLimited := rt.RateLimit
+	if rateLimited == nil {
+		rateLimited = func(h http.Handler) http.Handler { return h }
+	}
+	ownerPattern := rt.OwnerPattern
+	if ownerPattern == nil {
+		ownerPattern = regexp.MustCompile(catalog.DefaultOwnerPattern)
+	}
+	svc, registry := rt.Service, rt.Catalog
+	limits := rt.BodyLimits.withDefaults()
+
+	// Resolution endpoints
+	resolveHandler := NewResolveHandler(svc)
+	existsHandler := NewExistsHandler(svc, registry)
+	describeHandler := NewDescribeHandler(svc)
+	estimateHandler := NewEstimateHandler(svc)
+	listHandler := NewListHandler(svc)
+	lineageHandler := NewLineageHandler(svc, registry)
+
+	// Catalog endpoints
+	catalogListHandler := NewCatalogListHandler(svc, registry)
+	searchHandler := NewSearchCatalogHandler(registry)
+	statsHandler := NewCatalogStatsHandler(registry, rt.Metrics)
+	validateHandler := NewValidateCatalogHandler(registry)
+	batchHandler := NewBatchResolveHandler(svc)
+	describeBatchHandler := NewDescribeBatchHandler(svc)
+	metadataHandler := NewMetadataHandler(svc, registry)
+	treeHandler := NewTreeHandler(registry, rt.TreeMaxDepth, rt.TreeMaxNodes)
+
+	// Admin endpoints
+	updateStatusHandler := NewUpdateStatusHandler(registry)
+	createNodeHandler := NewCreateNodeHandler(registry)
+	updateNodeHandler := NewUpdateNodeHandler(registry)
+	updateOwnershipHandler := NewUpdateOwnershipHandler(registry, ownerPattern)
+	qualityHandler := NewQualityHandler(registry)
+	schemaHandler := NewSchemaHandler(registry)
+	auditHandler := NewAuditLogHandler(registry)
+	fetchHandler := NewFetchDataHandler(svc)
+	versionsHandler := NewVersionsHandler(svc)
+	importHandler := NewImportCatalogHandler(registry)
+	exportHandler := NewExportCatalogHandler(registry)
+	governanceHandler := NewGovernanceReportHandler(registry)
+	deprecationsHandler := NewDeprecationsHandler(registry, rt.SunsetWindow)
+	ownersHandler := NewOwnersHandler(registry)
+	domainsHandler := NewDomainsHandler(registry)
+	reloadHandler := NewReloadCatalogHandler(rt.Reloader)
+	reloadStatusHandler := NewReloadStatusHandler(rt.Reloader)
+
+	// Cache endpoints
+	cacheStatusHandler := NewCacheStatusHandler(rt.Cache)
+	refreshCacheHandler := NewRefreshCacheHandler(svc)
+
+	// Telemetry endpoints
+	recorder := rt.Telemetry
+	if recorder == nil {
+		recorder = telemetry.NewRecorder(telemetry.Discard)
+	}
+	telemetryHandler := NewTelemetryAccessHandler(recorder)
+	telemetryStatsHandler := NewTelemetryStatsHandler(recorder)
+
+	// UI and API documentation endpoints
+	uiHandler := NewUIHandler(registry)
+	openAPIHandler := NewOpenAPIHandler(rt.Title)
+	docsHandler := NewDocsHandler()
+
+	// Every route answers the methods it serves, HEAD with GET, and OPTIONS;
+	// others are a 405
+	get := func(h http.Handler) *methodRouter { return methods(rt.CORS, h, http.MethodGet) }
+	post := func(h http.Handler) *methodRouter { return methods(rt.CORS, h, http.MethodPost) }
+
+	// Health checks
+	if rt.Health != nil {
+		mux.Handle("/health", get(rt.Health))
+	}
+	mux.Handle("/health/live", get(NewLivenessHandler()))
+	mux.Handle("/health/ready", get(NewReadinessHandler(registry, rt.Reloader, rt.Cache, rt.Probes).WithDraining(rt.Draining)))
+
+	// Register all routes
+	mux.Handle("/resolve/", get(rateLimited(resolveHandler)))
+	mux.Handle("/exists/", get(rateLimited(existsHandler)))
+	mux.Handle("/describe/", get(describeHandler))
+	mux.Handle("/estimate/", get(estimateHandler))
+	mux.Handle("/list/", get(listHandler))
+	mux.Handle("/lineage/", get(lineageHandler))
+
+	// Catalog routes
+	mux.Handle("/catalog/search", get(searchHandler))
+	mux.Handle("/catalog/stats", get(statsHandler))
+	mux.Handle("/catalog/validate", get(validateHandler))
+	mux.Handle("/catalog/import", post(limitBody(limits.Import, importHandler)))
+	mux.Handle("/catalog/export", get(exportHandler))
+	mux.Handle("/catalog/governance-report", get(governanceHandler))
+	mux.Handle("/deprecations", get(deprecationsHandler))
+	mux.Handle("/owners", get(ownersHandler))
+	mux.Handle("/owners/", get(ownersHandler))
+	mux.Handle("/domains", get(domainsHandler))
+	mux.Handle("/catalog", limitBody(limits.Default,
+		get(catalogListHandler).handle(http.MethodPost, createNodeHandler)))
+
+	// /catalog/{path} routes by its last segment
+	catalogSubroutes := []struct {
+		suffix string
+		router *methodRouter
+	}{
+		{"/status", methods(rt.CORS, updateStatusHandler, http.MethodPut)},
+		{"/ownership", methods(rt.CORS, updateOwnershipHandler, http.MethodPut)},
+		{"/quality", methods(rt.CORS, qualityHandler, http.MethodGet, http.MethodPut)},
+		{"/schema", get(schemaHandler)},
+		{"/audit", get(auditHandler)},
+	}
+	catalogNode := get(catalogListHandler).
+		handle(http.MethodPut, updateNodeHandler).
+		handle(http.MethodPatch, updateNodeHandler)
+	mux.Handle("/catalog/", limitBody(limits.Default, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimRight(r.URL.Path, "/")
+		for _, sub := range catalogSubroutes {
+			if strings.HasSuffix(path, sub.suffix) {
+				sub.router.ServeHTTP(w, r)
+				return
+			}
+		}
+		catalogNode.ServeHTTP(w, r)
+	})))
+
+	// Catalog reload
+	mux.Handle("/admin/reload", post(limitBody(limits.Default, reloadHandler)))
+	mux.Handle("/admin/reload/status", get(reloadStatusHandler))
+
+	// Batch resolve and describe
+	mux.Handle("/resolve/batch", post(rateLimited(limitBody(limits.Batch, batchHandler))))
+	mux.Handle("/describe/batch", post(rateLimited(limitBody(limits.Batch, describeBatchHandler))))
+
+	// Metadata and tree
+	mux.Handle("/metadata/", get(metadataHandler))
+	mux.Handle("/tree/", get(treeHandler))
+	mux.Handle("/tree", get(treeHandler))
+
+	// Fetch data
+	mux.Handle("/fetch/", get(rateLimited(fetchHandler)))
+	mux.Handle("/versions/", get(rateLimited(versionsHandler)))
+
+	// Cache
+	mux.Handle("/cache/status", get(cacheStatusHandler))
+	mux.Handle("/cache/refresh", post(limitBody(limits.Default, refreshCacheHandler)))
+	mux.Handle("/cache/refresh/", post(limitBody(limits.Default, refreshCacheHandler)))
+
+	// Telemetry
+	mux.Handle("/telemetry/access", post(limitBody(limits.Telemetry, telemetryHandler)))
+	mux.Handle("/telemetry/stats", get(telemetryStatsHandler))
+
+	// UI and API documentation
+	mux.Handle("/ui", get(uiHandler))
+	mux.Handle("/ui/node/", get(uiHandler))
+	mux.Handle("/ui/search", get(uiHandler))
+	mux.Handle("/openapi.json", get(openAPIHandler))
+	mux.Handle("/docs", get(docsHandler))
+}
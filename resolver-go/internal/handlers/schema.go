@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// SchemaHandler handles GET /catalog/{path}/schema: the node's schema with
+// each column's keys, and for foreign keys the schema URL of the node they
+// point at. ?compare_to=<path> adds the diff from this schema to that one,
+// e.g. from a deprecated node to its successor. A node without a schema is a
+// 404 naming the nearest ancestor that has one.
+type SchemaHandler struct {
+	catalog *catalog.Registry
+}
+
+// schemaColumn is a column of a GET /catalog/{path}/schema response
+type schemaColumn struct {
+	catalog.SchemaColumn
+	ForeignKeyHref string `json:"foreign_key_href,omitempty"`
+}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler(reg *catalog.Registry) *SchemaHandler {
+	return &SchemaHandler{catalog: reg}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SchemaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, err := pathFromRequest(r, "/catalog/", "/schema")
+	if err != nil {
+		writePathError(w, err)
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, ErrInvalidRequest, "Missing path", nil)
+		return
+	}
+	if notModified(w, r, catalogETag(h.catalog, r)) {
+		return
+	}
+
+	node, ok := h.schemaNode(w, path)
+	if !ok {
+		return
+	}
+	columns := make([]schemaColumn, 0, len(node.DataSchema.Columns))
+	for _, col := range h.catalog.SchemaColumns(path) {
+		column := schemaColumn{SchemaColumn: col}
+		if col.ForeignKeyPath != "" {
+			column.ForeignKeyHref = "/catalog/" + col.ForeignKeyPath + "/schema"
+		}
+		columns = append(columns, column)
+	}
+
+	schema := *node.DataSchema
+	schema.Columns = nil
+	response := map[string]interface{}{
+		"path":    path,
+		"schema":  schema,
+		"columns": columns,
+	}
+
+	if other := strings.Trim(r.URL.Query().Get("compare_to"), "/"); other != "" {
+		otherNode, ok := h.schemaNode(w, other)
+		if !ok {
+			return
+		}
+		diff := catalog.DiffSchemas(node.DataSchema, otherNode.DataSchema)
+		response["compare_to"] = other
+		response["diff"] = diff
+		response["identical"] = diff.Empty()
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// schemaNode returns the node at path if it has a schema, otherwise writing
+// a 404 that points at the nearest ancestor with one
+func (h *SchemaHandler) schemaNode(w http.ResponseWriter, path string) (*catalog.CatalogNode, bool) {
+	node := h.catalog.Get(path)
+	if node == nil {
+		writeError(w, http.StatusNotFound, ErrNodeNotFound, "Node not found", map[string]interface{}{
+			"path": path,
+		})
+		return nil, false
+	}
+	if node.DataSchema == nil {
+		details := map[string]interface{}{
+			"detail": fmt.Sprintf("'%s' has no schema", path),
+			"path":   path,
+		}
+		if parent := h.catalog.NearestSchemaAncestor(path); parent != "" {
+			details["hint"] = fmt.Sprintf("'%s' has a schema", parent)
+			details["schema_path"] = parent
+			details["schema_href"] = "/catalog/" + parent + "/schema"
+		}
+		writeError(w, http.StatusNotFound, ErrNotFound, "Schema not found", details)
+		return nil, false
+	}
+	return node, true
+}
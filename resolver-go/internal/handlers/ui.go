@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/service"
+)
+
+// uiMaxChildren is the number of children a tree level or a node page lists
+// before the rest are left to a "more" button
+const uiMaxChildren = 200
+
+// uiSearchLimit is the number of results GET /ui/search lists
+const uiSearchLimit = 100
+
+// UIHandler serves the catalog browser: GET /ui, GET /ui/node/{path} and
+// GET /ui/search. Pages are rendered on the server from the registry. The
+// tree opens along the path of the page; the levels below are fetched from
+// /tree as they are expanded, so a large catalog only renders what is shown.
+type UIHandler struct {
+	catalog *catalog.Registry
+}
+
+// NewUIHandler creates a new UI handler
+func NewUIHandler(reg *catalog.Registry) *UIHandler {
+	return &UIHandler{catalog: reg}
+}
+
+// uiPage is what every page of the browser renders around its content
+type uiPage struct {
+	Title string
+	Query string      // Search box text
+	Nav   *uiTreeItem // Tree root, opened along the page's path
+}
+
+// uiTreeItem is a node of the navigation tree. Children is set on the items
+// that are open; More counts the children left out of it.
+type uiTreeItem struct {
+	Path        string
+	Name        string
+	DisplayName string
+	Status      catalog.NodeStatus
+	Virtual     bool
+	ChildCount  int
+	Current     bool
+	Children    []*uiTreeItem
+	More        int
+}
+
+type uiCrumb struct {
+	Path string
+	Name string
+}
+
+// uiOwner is a resolved ownership field and the path that defines it
+type uiOwner struct {
+	Role   string
+	Value  string
+	Source string
+}
+
+// uiBinding summarizes the source binding a node resolves through, with its
+// config masked
+type uiBinding struct {
+	Path       string // Where the binding is defined
+	Inherited  bool
+	SourceType string
+	ReadOnly   bool
+	Operations []string
+	Query      string
+	Config     []uiConfigEntry
+}
+
+type uiConfigEntry struct {
+	Key   string
+	Value string
+}
+
+type uiIndexPage struct {
+	uiPage
+	Total  int
+	Counts []uiConfigEntry // Nodes by status
+}
+
+type uiNodePage struct {
+	uiPage
+	Path                 string
+	Crumbs               []uiCrumb
+	Node                 *catalog.CatalogNode // nil for a virtual path
+	Deprecated           bool
+	Ownership            []uiOwner
+	Classification       string
+	ClassificationSource string
+	Binding              *uiBinding
+	Schema               []catalog.SchemaColumn
+	SchemaAncestor       string // Nearest ancestor with a schema, when the node has none
+	Children             []*uiTreeItem
+	MoreChildren         int
+}
+
+type uiSearchPage struct {
+	uiPage
+	Results []*catalog.CatalogNode
+	Limit   int
+}
+
+// ServeHTTP implements http.Handler
+func (h *UIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch path := r.URL.Path; {
+	case path == "/ui":
+		h.index(w)
+	case path == "/ui/search":
+		h.search(w, r.URL.Query().Get("q"))
+	case strings.HasPrefix(path, "/ui/node/"):
+		if nodePath, err := pathFromRequest(r, "/ui/node/", ""); err == nil {
+			h.node(w, r, nodePath)
+		} else {
+			h.notFound(w, path)
+		}
+	default:
+		h.notFound(w, path)
+	}
+}
+
+func (h *UIHandler) index(w http.ResponseWriter) {
+	counts := h.catalog.Count()
+	page := &uiIndexPage{uiPage: uiPage{Title: "Catalog", Nav: h.nav("")}, Total: counts["total"]}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		if status != "total" {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		page.Counts = append(page.Counts, uiConfigEntry{Key: status, Value: fmt.Sprint(counts[status])})
+	}
+	renderUI(w, http.StatusOK, uiIndexTemplate, page)
+}
+
+func (h *UIHandler) search(w http.ResponseWriter, query string) {
+	page := &uiSearchPage{uiPage: uiPage{Title: "Search", Query: query, Nav: h.nav("")}, Limit: uiSearchLimit}
+	if query != "" {
+		page.Title = "Search: " + query
+		page.Results = h.catalog.SearchWithOptions(query, catalog.SearchOptions{Limit: uiSearchLimit})
+	}
+	renderUI(w, http.StatusOK, uiSearchTemplate, page)
+}
+
+func (h *UIHandler) node(w http.ResponseWriter, r *http.Request, path string) {
+	if path == "" {
+		http.Redirect(w, r, "/ui", http.StatusFound)
+		return
+	}
+	node := h.catalog.Get(path)
+	tree := h.catalog.Tree(path, catalog.TreeOptions{Depth: 1, IncludeArchived: true})
+	if node == nil && len(tree.Root.Children) == 0 {
+		h.notFound(w, r.URL.Path)
+		return
+	}
+
+	page := &uiNodePage{uiPage: uiPage{Title: path, Nav: h.nav(path)}, Path: path, Node: node}
+	if node != nil && node.DisplayName != "" {
+		page.Title = node.DisplayName
+	}
+	parent := ""
+	for _, p := range append(catalog.Ancestors(path), path) {
+		page.Crumbs = append(page.Crumbs, uiCrumb{Path: p, Name: segmentName(parent, p)})
+		parent = p
+	}
+	page.Deprecated = node != nil && node.Status == catalog.NodeStatusDeprecated
+	page.Ownership = ownershipRows(h.catalog.ResolveOwnership(path))
+	page.Classification, page.ClassificationSource = h.catalog.ResolveClassification(path)
+	if binding, bindingPath := h.catalog.FindSourceBinding(path); binding != nil {
+		page.Binding = bindingSummary(binding, bindingPath, path)
+	}
+	page.Schema = h.catalog.SchemaColumns(path)
+	if page.Schema == nil {
+		page.SchemaAncestor = h.catalog.NearestSchemaAncestor(path)
+	}
+	page.Children, page.MoreChildren = treeItems(path, tree.Root.Children, "")
+	renderUI(w, http.StatusOK, uiNodeTemplate, page)
+}
+
+func (h *UIHandler) notFound(w http.ResponseWriter, path string) {
+	page := &uiNodePage{uiPage: uiPage{Title: "Not found", Nav: h.nav("")}, Path: path}
+	renderUI(w, http.StatusNotFound, uiNotFoundTemplate, page)
+}
+
+// nav returns the navigation tree opened along path: the root's children,
+// and the children of every ancestor of path and of path itself
+func (h *UIHandler) nav(path string) *uiTreeItem {
+	levels := []string{""}
+	if path != "" {
+		levels = append(append(levels, catalog.Ancestors(path)...), path)
+	}
+	var open *uiTreeItem
+	for i := len(levels) - 1; i >= 0; i-- {
+		level := levels[i]
+		next := ""
+		if i+1 < len(levels) {
+			next = levels[i+1]
+		}
+		children := h.catalog.Tree(level, catalog.TreeOptions{Depth: 1}).Root.Children
+		items, more := treeItems(level, children, next)
+		for _, item := range items {
+			if item.Path == next && open != nil {
+				item.Children, item.More = open.Children, open.More
+			}
+			item.Current = item.Path == path
+		}
+		open = &uiTreeItem{Path: level, Children: items, More: more}
+	}
+	return open
+}
+
+// treeItems returns the first uiMaxChildren of children, and keep when it
+// comes later, with the number left out
+func treeItems(parent string, children []*catalog.TreeNode, keep string) ([]*uiTreeItem, int) {
+	items := make([]*uiTreeItem, 0, len(children))
+	for i, child := range children {
+		if i >= uiMaxChildren && child.Path != keep {
+			continue
+		}
+		items = append(items, &uiTreeItem{
+			Path:        child.Path,
+			Name:        segmentName(parent, child.Path),
+			DisplayName: child.DisplayName,
+			Status:      child.Status,
+			Virtual:     child.Virtual,
+			ChildCount:  child.ChildCount,
+		})
+	}
+	return items, len(children) - len(items)
+}
+
+// segmentName returns the part of path below parent
+func segmentName(parent, path string) string {
+	if parent == "" {
+		return path
+	}
+	return strings.TrimLeft(strings.TrimPrefix(path, parent), "/.")
+}
+
+// ownershipRows lists the ownership fields that resolve to a value
+func ownershipRows(o *catalog.ResolvedOwnership) []uiOwner {
+	if o == nil {
+		return nil
+	}
+	fields := []struct {
+		role          string
+		value, source *string
+	}{
+		{"Accountable owner", o.AccountableOwner, o.AccountableOwnerSource},
+		{"Data specialist", o.DataSpecialist, o.DataSpecialistSource},
+		{"Support channel", o.SupportChannel, o.SupportChannelSource},
+		{"ADOP", o.ADOP, o.ADOPSource},
+		{"ADOP name", o.ADOPName, o.ADOPNameSource},
+		{"ADS", o.ADS, o.ADSSource},
+		{"ADS name", o.ADSName, o.ADSNameSource},
+		{"ADAL", o.ADAL, o.ADALSource},
+		{"ADAL name", o.ADALName, o.ADALNameSource},
+		{"UI", o.UI, o.UISource},
+	}
+	var rows []uiOwner
+	for _, f := range fields {
+		if f.value == nil || *f.value == "" {
+			continue
+		}
+		row := uiOwner{Role: f.role, Value: *f.value}
+		if f.source != nil {
+			row.Source = *f.source
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// bindingSummary describes the binding defined at bindingPath that path
+// resolves through, masking secrets in its config
+func bindingSummary(binding *catalog.SourceBinding, bindingPath, path string) *uiBinding {
+	summary := &uiBinding{
+		Path:       bindingPath,
+		Inherited:  bindingPath != path,
+		SourceType: string(binding.SourceType),
+		ReadOnly:   binding.ReadOnly,
+		Operations: binding.AllowedOperations,
+	}
+	config := service.MaskedConfig(binding.Config)
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := config[k]
+		if s, ok := v.(string); ok {
+			if k == "query" {
+				summary.Query = s
+				continue
+			}
+			summary.Config = append(summary.Config, uiConfigEntry{Key: k, Value: s})
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			raw = []byte(fmt.Sprint(v))
+		}
+		summary.Config = append(summary.Config, uiConfigEntry{Key: k, Value: string(raw)})
+	}
+	return summary
+}
+
+// renderUI renders a page of the browser, or a 500 when its template fails
+func renderUI(w http.ResponseWriter, status int, page *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := page.ExecuteTemplate(&buf, "layout", data); err != nil {
+		writeError(w, http.StatusInternalServerError, ErrInternal, "Page rendering failed", map[string]interface{}{
+			"detail": err.Error(),
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
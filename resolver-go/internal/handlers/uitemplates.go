@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"html/template"
+	"strings"
+)
+
+// Pages of the catalog browser. Each defines "content" inside the shared
+// layout, which renders the search box, the navigation tree and the script
+// that expands it.
+var (
+	uiIndexTemplate    = uiTemplate(uiIndexContent)
+	uiNodeTemplate     = uiTemplate(uiNodeContent)
+	uiSearchTemplate   = uiTemplate(uiSearchContent)
+	uiNotFoundTemplate = uiTemplate(uiNotFoundContent)
+)
+
+var uiLayout = template.Must(template.New("layout").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(uiLayoutTemplate))
+
+func uiTemplate(content string) *template.Template {
+	return template.Must(template.Must(uiLayout.Clone()).Parse(content))
+}
+
+const uiLayoutTemplate = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - Moniker Catalog Browser</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 0; color: #222; }
+header { display: flex; align-items: center; gap: 24px; padding: 10px 20px; background: #263238; }
+header a.brand { color: #fff; font-weight: bold; text-decoration: none; }
+header form { position: relative; flex: 1; max-width: 480px; }
+header input { width: 100%; padding: 6px 8px; border: 0; border-radius: 4px; }
+#suggestions { position: absolute; z-index: 10; left: 0; right: 0; margin: 2px 0 0; padding: 0; list-style: none; background: #fff; border: 1px solid #ccc; border-radius: 4px; }
+#suggestions a { display: block; padding: 6px 8px; color: #222; text-decoration: none; }
+#suggestions a:hover { background: #eef; }
+.layout { display: flex; min-height: calc(100vh - 48px); }
+nav.tree { width: 320px; flex: none; overflow: auto; padding: 12px; border-right: 1px solid #ddd; background: #fafafa; font-size: 14px; }
+nav.tree ul { list-style: none; margin: 0; padding-left: 14px; }
+nav.tree > ul { padding-left: 0; }
+nav.tree li { margin: 2px 0; white-space: nowrap; }
+nav.tree li.current > a { font-weight: bold; }
+nav.tree button { border: 0; background: none; cursor: pointer; padding: 0 4px 0 0; width: 16px; }
+nav.tree .leaf { display: inline-block; width: 16px; }
+nav.tree a { color: #1a4f8b; text-decoration: none; }
+nav.tree a.status-deprecated, nav.tree a.status-archived { color: #999; text-decoration: line-through; }
+nav.tree button.more { width: auto; color: #1a4f8b; }
+main { flex: 1; padding: 16px 28px; max-width: 1100px; }
+.crumbs { color: #666; font-size: 14px; }
+.crumbs a { color: #1a4f8b; }
+.badge { display: inline-block; padding: 2px 8px; border-radius: 10px; font-size: 12px; font-weight: normal; vertical-align: middle; background: #e0e0e0; }
+.badge.status-active { background: #c8e6c9; }
+.badge.status-deprecated { background: #ffe0b2; }
+.badge.status-archived { background: #cfd8dc; }
+.badge.status-draft, .badge.status-pending_review, .badge.status-approved { background: #e1f5fe; }
+.banner { padding: 10px 14px; margin: 12px 0; border-left: 4px solid #f57c00; background: #fff3e0; }
+.muted { color: #777; }
+table { border-collapse: collapse; margin: 8px 0 16px; }
+th, td { text-align: left; padding: 4px 12px 4px 0; border-bottom: 1px solid #eee; vertical-align: top; }
+th { color: #555; font-weight: normal; }
+pre { background: #f5f5f5; padding: 10px; overflow: auto; }
+code { background: #f5f5f5; padding: 1px 4px; }
+</style>
+</head>
+<body>
+<header>
+<a class="brand" href="/ui">Moniker Catalog Browser</a>
+<form action="/ui/search" method="get" role="search">
+<input id="search" type="search" name="q" value="{{.Query}}" placeholder="Search by name, path, description or tag" autocomplete="off">
+<ul id="suggestions" hidden></ul>
+</form>
+</header>
+<div class="layout">
+<nav class="tree">{{with .Nav}}<ul data-path="{{.Path}}">{{template "items" .}}</ul>{{end}}</nav>
+<main>{{template "content" .}}</main>
+</div>
+<script>
+(function () {
+  function href(prefix, path) {
+    return path ? prefix + "/" + path.split("/").map(encodeURIComponent).join("/") : prefix;
+  }
+  function item(node, parent) {
+    var li = document.createElement("li");
+    li.dataset.path = node.path;
+    if (node.child_count > 0) {
+      var toggle = document.createElement("button");
+      toggle.className = "toggle";
+      toggle.dataset.path = node.path;
+      toggle.setAttribute("aria-expanded", "false");
+      toggle.textContent = "▸";
+      li.appendChild(toggle);
+    } else {
+      var leaf = document.createElement("span");
+      leaf.className = "leaf";
+      li.appendChild(leaf);
+    }
+    var a = document.createElement("a");
+    a.href = href("/ui/node", node.path);
+    a.className = "status-" + node.status;
+    a.title = node.display_name || "";
+    a.textContent = parent ? node.path.slice(parent.length).replace(/^[\/.]/, "") : node.path;
+    li.appendChild(a);
+    return li;
+  }
+  function children(path) {
+    return fetch(href("/tree", path), { headers: { Accept: "application/json" } })
+      .then(function (r) { if (!r.ok) { throw new Error(r.status); } return r.json(); })
+      .then(function (data) { return data.children || []; });
+  }
+  document.addEventListener("click", function (e) {
+    var button = e.target.closest("nav.tree button");
+    if (!button) { return; }
+    var path = button.dataset.path;
+    if (button.classList.contains("toggle")) {
+      var li = button.parentNode, list = li.querySelector(":scope > ul");
+      if (list) {
+        list.hidden = !list.hidden;
+        button.setAttribute("aria-expanded", String(!list.hidden));
+        button.textContent = list.hidden ? "▸" : "▾";
+        return;
+      }
+      button.disabled = true;
+      children(path).then(function (nodes) {
+        var ul = document.createElement("ul");
+        nodes.forEach(function (node) { ul.appendChild(item(node, path)); });
+        li.appendChild(ul);
+        button.setAttribute("aria-expanded", "true");
+        button.textContent = "▾";
+      }).finally(function () { button.disabled = false; });
+      return;
+    }
+    // "more": list every child, keeping the items already shown and opened
+    var ul = button.closest("ul");
+    button.disabled = true;
+    children(path).then(function (nodes) {
+      var shown = {};
+      Array.prototype.forEach.call(ul.children, function (li) { shown[li.dataset.path] = li; });
+      button.parentNode.remove();
+      nodes.forEach(function (node) { ul.appendChild(shown[node.path] || item(node, path)); });
+    }).catch(function () { button.disabled = false; });
+  });
+
+  var input = document.getElementById("search"), list = document.getElementById("suggestions"), timer;
+  input.addEventListener("input", function () {
+    clearTimeout(timer);
+    var q = input.value.trim();
+    if (q.length < 2) { list.hidden = true; return; }
+    timer = setTimeout(function () {
+      fetch("/catalog/search?limit=10&q=" + encodeURIComponent(q), { headers: { Accept: "application/json" } })
+        .then(function (r) { return r.ok ? r.json() : { results: [] }; })
+        .then(function (data) {
+          list.textContent = "";
+          (data.results || []).forEach(function (node) {
+            var li = document.createElement("li"), a = document.createElement("a");
+            a.href = href("/ui/node", node.path);
+            a.textContent = (node.display_name || node.path) + " — " + node.path;
+            li.appendChild(a);
+            list.appendChild(li);
+          });
+          list.hidden = list.children.length === 0;
+        });
+    }, 200);
+  });
+  document.addEventListener("click", function (e) {
+    if (!e.target.closest("header form")) { list.hidden = true; }
+  });
+})();
+</script>
+</body>
+</html>{{end}}
+
+{{define "items"}}{{range .Children}}{{template "item" .}}{{end}}{{if .More}}<li><button class="more" data-path="{{.Path}}">{{.More}} more&hellip;</button></li>{{end}}{{end}}
+
+{{define "item"}}<li data-path="{{.Path}}"{{if .Current}} class="current"{{end}}>
+{{- if .ChildCount}}<button class="toggle" data-path="{{.Path}}" aria-expanded="{{if .Children}}true{{else}}false{{end}}">{{if .Children}}&#9662;{{else}}&#9656;{{end}}</button>
+{{- else}}<span class="leaf"></span>{{end -}}
+<a href="/ui/node/{{.Path}}" class="status-{{.Status}}" title="{{.DisplayName}}">{{.Name}}</a>
+{{- if .Children}}<ul>{{template "items" .}}</ul>{{end}}</li>
+{{end}}`
+
+const uiIndexContent = `{{define "content"}}
+<h1>Catalog</h1>
+<p>{{.Total}} registered nodes. Open a branch on the left, or search by name, path, description or tag.</p>
+{{if .Counts}}<table>
+<tr><th>Status</th><th>Nodes</th></tr>
+{{range .Counts}}<tr><td><span class="badge status-{{.Key}}">{{.Key}}</span></td><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}`
+
+const uiNodeContent = `{{define "content"}}
+<p class="crumbs"><a href="/ui">catalog</a>{{range .Crumbs}} / <a href="/ui/node/{{.Path}}">{{.Name}}</a>{{end}}</p>
+{{with .Node}}
+<h1>{{if .DisplayName}}{{.DisplayName}}{{else}}{{$.Path}}{{end}} <span class="badge status-{{.Status}}">{{.Status}}</span></h1>
+{{else}}
+<h1>{{.Path}} <span class="badge">virtual</span></h1>
+<p class="muted">No node is registered at this path; it only groups the nodes beneath it.</p>
+{{end}}
+<p><code>moniker://{{.Path}}</code></p>
+
+{{if .Deprecated}}{{with .Node}}<div class="banner">
+<strong>Deprecated.</strong>
+{{with .DeprecationMessage}}{{.}}{{end}}
+{{with .Successor}}Use <a href="/ui/node/{{.}}">{{.}}</a> instead.{{end}}
+{{with .SunsetDeadline}}Sunset deadline: {{.}}.{{end}}
+{{with .MigrationGuideURL}}<a href="{{.}}">Migration guide</a>{{end}}
+</div>{{end}}{{end}}
+
+{{with .Node}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{with .Tags}}<p class="muted">Tags: {{join . ", "}}</p>{{end}}
+{{end}}
+
+<h2>Ownership</h2>
+{{if .Ownership}}<table>
+<tr><th>Role</th><th>Value</th><th>Defined at</th></tr>
+{{range .Ownership}}<tr><td>{{.Role}}</td><td>{{.Value}}</td><td>{{if eq .Source $.Path}}this node{{else if .Source}}<a href="/ui/node/{{.Source}}">{{.Source}}</a>{{end}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">No owner is defined here or above.</p>{{end}}
+<p>Classification: <strong>{{.Classification}}</strong>
+{{- if and .ClassificationSource (ne .ClassificationSource .Path)}} (from <a href="/ui/node/{{.ClassificationSource}}">{{.ClassificationSource}}</a>){{end}}</p>
+
+<h2>Source binding</h2>
+{{with .Binding}}
+<table>
+<tr><th>Type</th><td>{{.SourceType}}</td></tr>
+{{if .Inherited}}<tr><th>Defined at</th><td><a href="/ui/node/{{.Path}}">{{.Path}}</a></td></tr>{{end}}
+<tr><th>Read only</th><td>{{.ReadOnly}}</td></tr>
+{{with .Operations}}<tr><th>Operations</th><td>{{join . ", "}}</td></tr>{{end}}
+{{range .Config}}<tr><th>{{.Key}}</th><td><code>{{.Value}}</code></td></tr>
+{{end}}</table>
+{{with .Query}}<pre>{{.}}</pre>{{end}}
+{{else}}<p class="muted">None; this path does not resolve to data.</p>{{end}}
+
+<h2>Schema</h2>
+{{if .Schema}}<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Key</th><th>Description</th></tr>
+{{range .Schema}}<tr><td><code>{{.Name}}</code></td><td>{{.DataType}}</td><td>{{.Nullable}}</td>
+<td>{{if .PrimaryKey}}primary{{end}}{{if .ForeignKeyPath}}{{if .PrimaryKey}}, {{end}}{{if .ForeignKeyRegistered}}<a href="/ui/node/{{.ForeignKeyPath}}">{{.ForeignKeyPath}}</a>{{else}}{{.ForeignKeyPath}}{{end}}{{end}}</td>
+<td>{{.Description}}</td></tr>
+{{end}}</table>
+{{else if .SchemaAncestor}}<p class="muted">None here; <a href="/ui/node/{{.SchemaAncestor}}">{{.SchemaAncestor}}</a> declares one.</p>
+{{else}}<p class="muted">No schema is declared.</p>{{end}}
+
+{{if .Children}}<h2>Children</h2>
+<ul>{{range .Children}}<li><a href="/ui/node/{{.Path}}">{{.Name}}</a>{{if .DisplayName}} &mdash; {{.DisplayName}}{{end}}{{if .Status}} <span class="badge status-{{.Status}}">{{.Status}}</span>{{end}}</li>
+{{end}}{{if .MoreChildren}}<li class="muted">and {{.MoreChildren}} more in the tree</li>{{end}}</ul>{{end}}
+{{end}}`
+
+const uiSearchContent = `{{define "content"}}
+<h1>Search</h1>
+{{if not .Query}}<p class="muted">Enter a name, path, description or tag above.</p>
+{{else if not .Results}}<p>Nothing matches <strong>{{.Query}}</strong>.</p>
+{{else}}<p>{{len .Results}} matches for <strong>{{.Query}}</strong>{{if eq (len .Results) .Limit}} (the first {{.Limit}}){{end}}.</p>
+<table>
+<tr><th>Path</th><th>Name</th><th>Status</th><th>Description</th></tr>
+{{range .Results}}<tr><td><a href="/ui/node/{{.Path}}">{{.Path}}</a></td><td>{{.DisplayName}}</td><td><span class="badge status-{{.Status}}">{{.Status}}</span></td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}`
+
+const uiNotFoundContent = `{{define "content"}}
+<h1>Not found</h1>
+<p>Nothing is registered at <code>{{.Path}}</code>. Search for it above, or browse the tree.</p>
+{{end}}`
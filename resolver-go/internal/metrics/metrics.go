@@ -0,0 +1,189 @@
+// Package metrics keeps in-process resolution metrics: counters and latency
+// histograms per binding path prefix and source type. A Registry is created
+// by the caller and injected where it is used, so there is no global state.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the latency histogram
+// buckets. Durations above the last bound are only counted in the total.
+var LatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// PathSegments is how many leading path segments label a series, to bound
+// the number of series
+const PathSegments = 2
+
+// Path labels of resolutions that matched no binding. Requested paths are
+// never used as labels: callers choose them, so they are unbounded.
+const (
+	NotFoundPath = "not_found" // Parsed, but no binding matched
+	InvalidPath  = "invalid"   // The moniker didn't parse
+)
+
+// Observation is one resolution to record
+type Observation struct {
+	Path       string // Binding path matched, labelled by its first PathSegments segments; NotFoundPath or InvalidPath without one
+	SourceType string // Empty when resolution failed before a binding was found
+	Duration   time.Duration
+	ErrorType  string // Empty on success, e.g. not_found, access_denied
+	Redirected bool   // Followed a successor
+}
+
+// Labels identify a series
+type Labels struct {
+	PathPrefix string `json:"path_prefix"`
+	SourceType string `json:"source_type,omitempty"`
+}
+
+// Histogram is a cumulative latency histogram. Counts[i] is the number of
+// observations at or below LatencyBuckets[i].
+type Histogram struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []uint64  `json:"counts"`
+	Count   uint64    `json:"count"`
+	Sum     float64   `json:"sum"` // Seconds
+}
+
+// Series is the metrics of one label set
+type Series struct {
+	Labels
+	Total     uint64            `json:"total"`
+	Errors    map[string]uint64 `json:"errors,omitempty"` // By error type
+	Denials   uint64            `json:"denials"`
+	Redirects uint64            `json:"redirects"`
+	Latency   Histogram         `json:"latency"`
+}
+
+// PathCount is a path prefix and how often it was resolved
+type PathCount struct {
+	PathPrefix string  `json:"path_prefix"`
+	Total      uint64  `json:"total"`
+	Errors     uint64  `json:"errors"`
+	ErrorRate  float64 `json:"error_rate"`
+}
+
+// DeniedErrorType is the error type counted as a denial
+const DeniedErrorType = "access_denied"
+
+// Registry holds resolution metrics. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.Mutex
+	series map[Labels]*Series
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{series: make(map[Labels]*Series)}
+}
+
+// PathPrefix returns the first PathSegments segments of path
+func PathPrefix(path string) string {
+	segments := strings.SplitN(strings.Trim(path, "/"), "/", PathSegments+1)
+	if len(segments) > PathSegments {
+		segments = segments[:PathSegments]
+	}
+	return strings.Join(segments, "/")
+}
+
+// Observe records a resolution
+func (r *Registry) Observe(o Observation) {
+	labels := Labels{PathPrefix: PathPrefix(o.Path), SourceType: o.SourceType}
+	seconds := o.Duration.Seconds()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.series[labels]
+	if !ok {
+		s = &Series{
+			Labels:  labels,
+			Latency: Histogram{Buckets: LatencyBuckets, Counts: make([]uint64, len(LatencyBuckets))},
+		}
+		r.series[labels] = s
+	}
+	s.Total++
+	if o.ErrorType != "" {
+		if s.Errors == nil {
+			s.Errors = make(map[string]uint64)
+		}
+		s.Errors[o.ErrorType]++
+		if o.ErrorType == DeniedErrorType {
+			s.Denials++
+		}
+	}
+	if o.Redirected {
+		s.Redirects++
+	}
+	for i, bound := range LatencyBuckets {
+		if seconds <= bound {
+			s.Latency.Counts[i]++
+		}
+	}
+	s.Latency.Count++
+	s.Latency.Sum += seconds
+}
+
+// Snapshot returns a copy of every series, sorted by path prefix and source type
+func (r *Registry) Snapshot() []Series {
+	r.mu.Lock()
+	out := make([]Series, 0, len(r.series))
+	for _, s := range r.series {
+		c := *s
+		c.Latency.Counts = append([]uint64(nil), s.Latency.Counts...)
+		if s.Errors != nil {
+			c.Errors = make(map[string]uint64, len(s.Errors))
+			for k, v := range s.Errors {
+				c.Errors[k] = v
+			}
+		}
+		out = append(out, c)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PathPrefix != out[j].PathPrefix {
+			return out[i].PathPrefix < out[j].PathPrefix
+		}
+		return out[i].SourceType < out[j].SourceType
+	})
+	return out
+}
+
+// TopPaths returns the n most resolved path prefixes across source types,
+// most resolved first
+func (r *Registry) TopPaths(n int) []PathCount {
+	byPath := make(map[string]*PathCount)
+	for _, s := range r.Snapshot() {
+		pc, ok := byPath[s.PathPrefix]
+		if !ok {
+			pc = &PathCount{PathPrefix: s.PathPrefix}
+			byPath[s.PathPrefix] = pc
+		}
+		pc.Total += s.Total
+		for _, count := range s.Errors {
+			pc.Errors += count
+		}
+	}
+
+	out := make([]PathCount, 0, len(byPath))
+	for _, pc := range byPath {
+		if pc.Total > 0 {
+			pc.ErrorRate = float64(pc.Errors) / float64(pc.Total)
+		}
+		out = append(out, *pc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].PathPrefix < out[j].PathPrefix
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+// --- PathPrefix ---
+
+func TestPathPrefix(t *testing.T) {
+	tests := map[string]string{
+		"prices":                "prices",
+		"prices/equity":         "prices/equity",
+		"prices/equity/us/AAPL": "prices/equity",
+		"/prices/fx/":           "prices/fx",
+		"":                      "",
+	}
+	for path, want := range tests {
+		if got := PathPrefix(path); got != want {
+			t.Errorf("PathPrefix(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// --- Observe ---
+
+func TestObserveCountsAndBuckets(t *testing.T) {
+	r := NewRegistry()
+	r.Observe(Observation{Path: "prices/equity/us", SourceType: "snowflake", Duration: 2 * time.Millisecond})
+	r.Observe(Observation{Path: "prices/equity/eu", SourceType: "snowflake", Duration: 300 * time.Millisecond, Redirected: true})
+	r.Observe(Observation{Path: "prices/equity/asia", ErrorType: DeniedErrorType, Duration: time.Millisecond})
+
+	series := r.Snapshot()
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series, got %+v", series)
+	}
+	denied, ok := series[0], series[1]
+	if denied.SourceType != "" || denied.Denials != 1 || denied.Errors[DeniedErrorType] != 1 {
+		t.Errorf("unexpected denial series: %+v", denied)
+	}
+	if ok.PathPrefix != "prices/equity" || ok.Total != 2 || ok.Redirects != 1 || len(ok.Errors) != 0 {
+		t.Errorf("unexpected snowflake series: %+v", ok)
+	}
+
+	// Buckets are cumulative: 2ms falls in 0.0025 and above, 300ms in 0.5 and above
+	h := ok.Latency
+	for i, bound := range h.Buckets {
+		want := uint64(0)
+		if bound >= 0.0025 {
+			want++
+		}
+		if bound >= 0.5 {
+			want++
+		}
+		if h.Counts[i] != want {
+			t.Errorf("bucket %v: expected %d, got %d", bound, want, h.Counts[i])
+		}
+	}
+	if h.Count != 2 {
+		t.Errorf("expected count 2, got %d", h.Count)
+	}
+
+	// Snapshots are copies
+	series[1].Latency.Counts[0] = 99
+	if r.Snapshot()[1].Latency.Counts[0] == 99 {
+		t.Error("expected Snapshot to copy bucket counts")
+	}
+}
+
+// --- TopPaths ---
+
+func TestTopPaths(t *testing.T) {
+	r := NewRegistry()
+	for i := 0; i < 3; i++ {
+		r.Observe(Observation{Path: "prices/fx", SourceType: "oracle"})
+	}
+	r.Observe(Observation{Path: "prices/equity", SourceType: "snowflake"})
+	r.Observe(Observation{Path: "prices/equity/x", ErrorType: "not_found"})
+	r.Observe(Observation{Path: "reference/calendars", SourceType: "static"})
+
+	top := r.TopPaths(2)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 paths, got %+v", top)
+	}
+	if top[0].PathPrefix != "prices/fx" || top[0].Total != 3 {
+		t.Errorf("expected prices/fx first, got %+v", top[0])
+	}
+	if top[1].PathPrefix != "prices/equity" || top[1].Total != 2 || top[1].Errors != 1 || top[1].ErrorRate != 0.5 {
+		t.Errorf("expected prices/equity with a 50%% error rate, got %+v", top[1])
+	}
+}
@@ -27,8 +27,8 @@ var namespacePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]*$`)
 // Segment identity value pattern: alphanumeric, hyphens, underscores, dots
 var segmentIDValuePattern = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
 
-// date@VALUE patterns: absolute (YYYYMMDD), relative (3M, 1Y, 5D), symbolic (latest, previous)
-var dateParamPattern = regexp.MustCompile(`(?i)^(?:\d{8}|[1-9]\d*[YMWD]|latest|previous)$`)
+// date@VALUE patterns: absolute (YYYYMMDD), relative (3M, 1Y, 5D), symbolic (latest, previous, all)
+var dateParamPattern = regexp.MustCompile(`(?i)^(?:\d{8}|[1-9]\d*[YMWD]|latest|previous|all)$`)
 
 // filter@ prefix for shortlink expansion
 const filterPrefix = "filter@"
@@ -245,7 +245,7 @@ func ParseWithStore(monikerStr string, validate bool, store ShortlinkStore) (*Mo
 				return nil, &MonikerParseError{
 					Message: fmt.Sprintf("Invalid date parameter: '%s'. "+
 						"Must be YYYYMMDD, relative (e.g., 3M, 1Y, 5D), "+
-						"or symbolic (latest, previous).", dateValue),
+						"or symbolic (latest, previous, all).", dateValue),
 				}
 			}
 			dateParam = &dateValue
@@ -366,6 +366,16 @@ func TestParseDateParamPrevious(t *testing.T) {
 	}
 }
 
+func TestParseDateParamAll(t *testing.T) {
+	m, err := ParseMoniker("news/articles/date@all")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.DateParam == nil || *m.DateParam != "all" {
+		t.Errorf("expected date_param 'all', got %v", m.DateParam)
+	}
+}
+
 func TestParseDateParamRelative(t *testing.T) {
 	cases := []string{"3M", "1Y", "2W", "5D"}
 	for _, val := range cases {
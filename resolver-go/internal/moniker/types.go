@@ -2,6 +2,7 @@ package moniker
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -174,12 +175,13 @@ func (m *Moniker) String() string {
 
 	base := strings.Join(parts, "")
 
-	// Query params
+	// Query params, sorted so equal monikers render identically
 	if len(m.Params) > 0 {
 		var paramParts []string
 		for k, v := range m.Params {
 			paramParts = append(paramParts, fmt.Sprintf("%s=%s", k, v))
 		}
+		sort.Strings(paramParts)
 		return fmt.Sprintf("moniker://%s?%s", base, strings.Join(paramParts, "&"))
 	}
 
@@ -0,0 +1,139 @@
+// Package ratelimit throttles callers with token buckets. Buckets are kept
+// per caller and rule in a bounded LRU, so any number of distinct callers
+// costs at most MaxCallers buckets.
+package ratelimit
+
+import (
+	"container/list"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+)
+
+// Defaults applied when rate_limit leaves them unset
+const (
+	DefaultRate       = 50
+	DefaultMaxCallers = 10000
+)
+
+// rule is the limit of one URL path prefix; the default rule's prefix is ""
+type rule struct {
+	prefix string
+	rate   float64 // Tokens per second; 0 or less is unlimited
+	burst  float64
+}
+
+// bucketKey identifies a bucket: one per caller and rule
+type bucketKey struct {
+	caller string
+	prefix string
+}
+
+type bucket struct {
+	key    bucketKey
+	tokens float64
+	last   time.Time
+}
+
+// Limiter decides whether a caller may make a request. It is safe for
+// concurrent use.
+type Limiter struct {
+	rules      []rule // Longest prefix first; the default rule is last
+	maxBuckets int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*list.Element
+	lru     *list.List // Most recently used at the front
+}
+
+// New creates a limiter from the rate_limit config
+func New(cfg config.RateLimitConfig) *Limiter {
+	rate := cfg.Rate
+	if rate <= 0 {
+		rate = DefaultRate
+	}
+	rules := []rule{{rate: rate, burst: burstOf(rate, cfg.Burst)}}
+	for _, o := range cfg.Overrides {
+		rules = append(rules, rule{prefix: o.PathPrefix, rate: o.Rate, burst: burstOf(o.Rate, o.Burst)})
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+
+	maxBuckets := cfg.MaxCallers
+	if maxBuckets <= 0 {
+		maxBuckets = DefaultMaxCallers
+	}
+	return &Limiter{
+		rules:      rules,
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+		buckets:    make(map[bucketKey]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// burstOf returns the configured burst, or twice the rate (at least 1)
+func burstOf(rate float64, burst int) float64 {
+	if burst > 0 {
+		return float64(burst)
+	}
+	return math.Max(1, math.Ceil(2*rate))
+}
+
+// SetClock replaces the clock buckets refill by, for tests
+func (l *Limiter) SetClock(now func() time.Time) {
+	l.now = now
+}
+
+// Allow takes a token from the caller's bucket for path. When the bucket is
+// empty it returns false and how long until a token is available.
+func (l *Limiter) Allow(caller, path string) (bool, time.Duration) {
+	r := l.rules[len(l.rules)-1]
+	for _, candidate := range l.rules {
+		if strings.HasPrefix(path, candidate.prefix) {
+			r = candidate
+			break
+		}
+	}
+	if r.rate <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	key := bucketKey{caller: caller, prefix: r.prefix}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var b *bucket
+	if el, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(el)
+		b = el.Value.(*bucket)
+		b.tokens = math.Min(r.burst, b.tokens+now.Sub(b.last).Seconds()*r.rate)
+		b.last = now
+	} else {
+		b = &bucket{key: key, tokens: r.burst, last: now}
+		l.buckets[key] = l.lru.PushFront(b)
+		for l.lru.Len() > l.maxBuckets {
+			oldest := l.lru.Back()
+			l.lru.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / r.rate * float64(time.Second))
+	return false, wait
+}
+
+// Len returns the number of buckets held
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+)
+
+func newTestLimiter(cfg config.RateLimitConfig) (*Limiter, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(cfg)
+	l.SetClock(func() time.Time { return now })
+	return l, &now
+}
+
+func TestAllowRefillsBucket(t *testing.T) {
+	l, now := newTestLimiter(config.RateLimitConfig{Rate: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("alice", "/resolve/prices"); !ok {
+			t.Fatalf("request %d: expected the burst to be allowed", i+1)
+		}
+	}
+	ok, wait := l.Allow("alice", "/resolve/prices")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected a 500ms wait once the burst is spent, got ok=%t wait=%s", ok, wait)
+	}
+	// Other callers have their own bucket
+	if ok, _ := l.Allow("bob", "/resolve/prices"); !ok {
+		t.Error("expected bob to be unaffected by alice")
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("alice", "/resolve/prices"); !ok {
+		t.Error("expected a token after refilling")
+	}
+}
+
+func TestAllowUsesLongestPrefixOverride(t *testing.T) {
+	l, _ := newTestLimiter(config.RateLimitConfig{
+		Rate:  100,
+		Burst: 100,
+		Overrides: []config.RateLimitOverride{
+			{PathPrefix: "/resolve/", Rate: 10, Burst: 2},
+			{PathPrefix: "/resolve/batch", Rate: 1, Burst: 1},
+			{PathPrefix: "/fetch/internal", Rate: 0},
+		},
+	})
+
+	if ok, _ := l.Allow("alice", "/resolve/batch"); !ok {
+		t.Fatal("expected the first batch to be allowed")
+	}
+	if ok, _ := l.Allow("alice", "/resolve/batch"); ok {
+		t.Error("expected the batch override to allow one request")
+	}
+	// /resolve/ has its own bucket with burst 2
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("alice", "/resolve/prices"); !ok {
+			t.Errorf("request %d: expected the /resolve/ burst to be allowed", i+1)
+		}
+	}
+	if ok, _ := l.Allow("alice", "/resolve/prices"); ok {
+		t.Error("expected the /resolve/ override to throttle")
+	}
+	for i := 0; i < 500; i++ {
+		if ok, _ := l.Allow("alice", "/fetch/internal/x"); !ok {
+			t.Fatal("expected a zero-rate override to be unlimited")
+		}
+	}
+}
+
+func TestBucketsAreBounded(t *testing.T) {
+	l, _ := newTestLimiter(config.RateLimitConfig{Rate: 1, Burst: 1, MaxCallers: 3})
+
+	l.Allow("alice", "/resolve/x")
+	for i := 0; i < 100; i++ {
+		l.Allow(fmt.Sprintf("ip:10.0.0.%d", i), "/resolve/x")
+	}
+	if n := l.Len(); n != 3 {
+		t.Fatalf("expected 3 buckets, got %d", n)
+	}
+	// alice was evicted, so she starts again with a full bucket
+	if ok, _ := l.Allow("alice", "/resolve/x"); !ok {
+		t.Error("expected an evicted caller to get a fresh bucket")
+	}
+}
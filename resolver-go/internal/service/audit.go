@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// Decisions recorded in AccessEvent
+const (
+	DecisionDenied               = "denied"                // An access policy rule refused the moniker
+	DecisionGone                 = "gone"                  // Archived, or deprecated past its sunset deadline
+	DecisionConfirmationRequired = "confirmation_required" // Allowed, but above access_policy.require_confirmation_above
+	DecisionAllowed              = "allowed"               // Sampled per audit.sample_allowed
+	DecisionAdminDenied          = "admin_denied"          // An admin route refused a caller without the required role
+)
+
+// AccessEvent records one access decision made while resolving a moniker
+type AccessEvent struct {
+	Time          time.Time       `json:"time"`
+	Decision      string          `json:"decision"`
+	Rule          string          `json:"rule,omitempty"` // Policy rule, sunset_deadline or archived
+	Reason        string          `json:"reason"`
+	Caller        *CallerIdentity `json:"caller,omitempty"`
+	Moniker       string          `json:"moniker"`         // Canonical form
+	Route         string          `json:"route,omitempty"` // Method and path of a refused admin request
+	BindingPath   string          `json:"binding_path,omitempty"`
+	EstimatedRows *int            `json:"estimated_rows,omitempty"`
+}
+
+// AccessAuditor records access decisions. Audit is called on the resolve
+// path and must not block.
+type AccessAuditor interface {
+	Audit(event AccessEvent)
+}
+
+// NoOpAuditor discards every event; it is the default auditor
+type NoOpAuditor struct{}
+
+// Audit implements AccessAuditor
+func (NoOpAuditor) Audit(AccessEvent) {}
+
+// DefaultAuditBufferSize is the number of events a FileAuditor queues before
+// it starts dropping them
+const DefaultAuditBufferSize = 1024
+
+// FileAuditor appends events to a file as JSON lines. Events are queued on a
+// buffered channel and written by a background goroutine; when the queue is
+// full they are dropped and counted rather than slowing resolution down.
+type FileAuditor struct {
+	file    *os.File
+	events  chan AccessEvent
+	done    chan struct{}
+	dropped atomic.Int64
+	errors  atomic.Int64
+
+	mu     sync.RWMutex // Read-held while queueing so Close never closes events under a send
+	closed bool
+}
+
+// NewFileAuditor opens path for appending and starts the writer. buffer is
+// the queue size; 0 means DefaultAuditBufferSize.
+func NewFileAuditor(path string, buffer int) (*FileAuditor, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	if buffer <= 0 {
+		buffer = DefaultAuditBufferSize
+	}
+	a := &FileAuditor{file: f, events: make(chan AccessEvent, buffer), done: make(chan struct{})}
+	go a.run()
+	return a, nil
+}
+
+// Audit implements AccessAuditor
+func (a *FileAuditor) Audit(event AccessEvent) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.events <- event:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full
+// or the auditor was closed
+func (a *FileAuditor) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Errors returns the number of events that could not be written
+func (a *FileAuditor) Errors() int64 {
+	return a.errors.Load()
+}
+
+// Close writes the queued events and closes the file. Events audited after
+// Close are dropped and counted in Dropped; calling Close again returns the
+// error of closing an already closed file.
+func (a *FileAuditor) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.events)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return a.file.Close()
+}
+
+// run writes events until the queue is closed, flushing whenever it drains
+func (a *FileAuditor) run() {
+	defer close(a.done)
+	w := bufio.NewWriter(a.file)
+	enc := json.NewEncoder(w)
+	for event := range a.events {
+		if err := enc.Encode(event); err != nil {
+			a.errors.Add(1)
+		}
+		if len(a.events) == 0 {
+			if err := w.Flush(); err != nil {
+				a.errors.Add(1)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		a.errors.Add(1)
+	}
+}
+
+// SetAuditor replaces the auditor that records access decisions; nil
+// restores the no-op auditor
+func (s *MonikerService) SetAuditor(a AccessAuditor) {
+	if a == nil {
+		a = NoOpAuditor{}
+	}
+	s.auditor = a
+}
+
+// audit records a decision about m
+func (s *MonikerService) audit(decision, rule, reason string, m *moniker.Moniker, bindingPath string, caller *CallerIdentity, estimatedRows *int) {
+	if s.auditor == nil {
+		return
+	}
+	s.auditor.Audit(AccessEvent{
+		Time:          s.clock().UTC(),
+		Decision:      decision,
+		Rule:          rule,
+		Reason:        reason,
+		Caller:        caller,
+		Moniker:       m.String(),
+		BindingPath:   bindingPath,
+		EstimatedRows: estimatedRows,
+	})
+}
+
+// auditResolved records a successful resolution: confirmation_required when
+// the estimate is above the binding node's require_confirmation_above, which
+// is recorded but not enforced, otherwise allowed for the configured sample.
+// It runs for cached results too.
+func (s *MonikerService) auditResolved(m *moniker.Moniker, result *ResolveResult, caller *CallerIdentity) {
+	if s.auditor == nil {
+		return
+	}
+	if node := result.Node; node != nil && node.AccessPolicy != nil && node.AccessPolicy.RequireConfirmationAbove != nil {
+		threshold := *node.AccessPolicy.RequireConfirmationAbove
+		if rows := node.AccessPolicy.EstimateRows(m.Path.Segments); rows > threshold {
+			reason := fmt.Sprintf("Estimated %d rows exceeds require_confirmation_above of %d", rows, threshold)
+			s.audit(DecisionConfirmationRequired, "require_confirmation_above", reason, m, result.BindingPath, caller, &rows)
+			return
+		}
+	}
+	if rate := s.auditSampleRate(); rate > 0 && (rate >= 1 || rand.Float64() < rate) {
+		s.audit(DecisionAllowed, "", "Resolved", m, result.BindingPath, caller, nil)
+	}
+}
+
+// auditSampleRate returns the fraction of allowed resolutions audited
+func (s *MonikerService) auditSampleRate() float64 {
+	if s.config == nil {
+		return 0
+	}
+	return s.config.Audit.SampleAllowed
+}
+
+// auditGone records a sunset or archived node refusing m
+func (s *MonikerService) auditGone(err error, m *moniker.Moniker, caller *CallerIdentity) {
+	switch e := err.(type) {
+	case *SunsetError:
+		s.audit(DecisionGone, "sunset_deadline", e.Error(), m, e.Path, caller, nil)
+	case *GoneError:
+		s.audit(DecisionGone, string(catalog.NodeStatusArchived), e.Error(), m, e.Path, caller, nil)
+	}
+}
+
+// AuditAdminDenied records an admin request refused because the caller was
+// not authenticated or lacks the roles of the route's group
+func (s *MonikerService) AuditAdminDenied(route, group, reason string, caller *CallerIdentity) {
+	if s.auditor == nil {
+		return
+	}
+	s.auditor.Audit(AccessEvent{
+		Time:     s.clock().UTC(),
+		Decision: DecisionAdminDenied,
+		Rule:     group,
+		Reason:   reason,
+		Caller:   caller,
+		Route:    route,
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultBatchConcurrency is how many monikers of a batch are resolved at
+// once when batch.concurrency is not set
+const DefaultBatchConcurrency = 8
+
+// BatchItem is the outcome of resolving one moniker of a batch: Result, or
+// Err when it failed or was not resolved because ctx was cancelled
+type BatchItem struct {
+	Moniker string
+	Result  *ResolveResult
+	Err     error
+}
+
+// ResolveBatch resolves monikers on a bounded pool of workers and returns one
+// item per moniker, in input order. Repeated monikers are resolved once and
+// share the outcome. Once ctx is cancelled no further monikers are started;
+// their items carry ctx.Err().
+func (s *MonikerService) ResolveBatch(ctx context.Context, monikers []string, caller *CallerIdentity, opts ResolveOptions) []BatchItem {
+	unique := make([]string, 0, len(monikers))
+	index := make(map[string]int, len(monikers))
+	for _, m := range monikers {
+		if _, seen := index[m]; !seen {
+			index[m] = len(unique)
+			unique = append(unique, m)
+		}
+	}
+
+	outcomes := make([]BatchItem, len(unique))
+	workers := s.batchConcurrency()
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					outcomes[i] = BatchItem{Moniker: unique[i], Err: err}
+					continue
+				}
+				result, err := s.ResolveWithOptions(ctx, unique[i], caller, opts)
+				outcomes[i] = BatchItem{Moniker: unique[i], Result: result, Err: err}
+			}
+		}()
+	}
+
+feed:
+	for i := range unique {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			// Monikers from i on were never handed to a worker
+			for j := i; j < len(unique); j++ {
+				outcomes[j] = BatchItem{Moniker: unique[j], Err: ctx.Err()}
+			}
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	items := make([]BatchItem, len(monikers))
+	for i, m := range monikers {
+		items[i] = outcomes[index[m]]
+	}
+	return items
+}
+
+// DescribeBatchItem is the description of one path of a batch: Result, or
+// Err when the path is not in the catalog
+type DescribeBatchItem struct {
+	Path   string
+	Result *DescribeResult
+	Err    error
+}
+
+// DescribeBatch describes paths, in input order, all against the same
+// snapshot of the catalog. A path with no node, no children and no
+// binding to inherit is a *NotFoundError.
+func (s *MonikerService) DescribeBatch(ctx context.Context, paths []string) []DescribeBatchItem {
+	reg := s.catalog.Frozen()
+	items := make([]DescribeBatchItem, len(paths))
+	for i, path := range paths {
+		items[i].Path = path
+		if err := ctx.Err(); err != nil {
+			items[i].Err = err
+			continue
+		}
+		result := s.describeIn(reg, path)
+		if result.Node == nil && !result.HasSourceBinding && len(reg.ChildrenPaths(path)) == 0 {
+			items[i].Err = &NotFoundError{Path: path}
+			continue
+		}
+		items[i].Result = result
+	}
+	return items
+}
+
+// batchConcurrency returns the worker count of ResolveBatch
+func (s *MonikerService) batchConcurrency() int {
+	if s.config != nil && s.config.Batch.Concurrency > 0 {
+		return s.config.Batch.Concurrency
+	}
+	return DefaultBatchConcurrency
+}
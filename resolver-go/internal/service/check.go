@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+)
+
+// checkKey marks a resolve run by Check, which stops building the result
+// once the moniker is known to resolve
+type checkKey struct{}
+
+// checking reports whether ctx is a resolve run by Check
+func checking(ctx context.Context) bool {
+	checked, _ := ctx.Value(checkKey{}).(bool)
+	return checked
+}
+
+// Check reports whether a moniker resolves for caller, returning the error
+// Resolve would: not found, access denied, sunset, gone, a param, version
+// or sub-resource the binding refuses, or a query placeholder without a
+// value. It skips what only the payload needs, so the result has the
+// binding path, source type, node, redirect and warnings but no ownership,
+// connection, query or version listing, and the inputs of a derived binding
+// aren't resolved. Checks bypass the resolve cache and aren't recorded as
+// resolutions in metrics or the audit log; denials are audited as they are
+// for Resolve.
+func (s *MonikerService) Check(ctx context.Context, monikerStr string, caller *CallerIdentity) (*ResolveResult, error) {
+	m, err := parseMoniker(monikerStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(context.WithValue(ctx, checkKey{}, true), m, caller, nil)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// derivedCycleMessage starts the error for a cycle of derived inputs, which
+// is passed up unwrapped so it names the whole cycle once
+const derivedCycleMessage = "Derived input cycle"
+
+// DerivedPlan is how a derived source is computed: its inputs, each resolved
+// to its own source, and the expression or named transform that combines
+// them. Clients or the fetch layer execute it.
+type DerivedPlan struct {
+	Inputs     []DerivedInput `json:"inputs"`
+	Expression string         `json:"expression,omitempty"`
+	Transform  string         `json:"transform,omitempty"`
+}
+
+// DerivedInput is a resolved input of a derived source
+type DerivedInput struct {
+	Name        string          `json:"name"`
+	Moniker     string          `json:"moniker"`
+	Path        string          `json:"path"`
+	BindingPath string          `json:"binding_path"`
+	Source      *ResolvedSource `json:"source"`
+}
+
+// DerivedDependency is an input of a derived node, as shown by Describe
+type DerivedDependency struct {
+	Name    string `json:"name"`
+	Moniker string `json:"moniker"`
+	Path    string `json:"path,omitempty"` // Canonical path of the moniker, if it parses
+}
+
+// resolveDerived resolves every input of a derived binding into a plan.
+// derived holds the derived bindings already being resolved; meeting one
+// again is a cycle.
+func (s *MonikerService) resolveDerived(ctx context.Context, m *moniker.Moniker, bindingPath string, config map[string]interface{}, caller *CallerIdentity, derived []string) (*DerivedPlan, error) {
+	key := bindingPath
+	if m.Namespace != nil {
+		key = *m.Namespace + "@" + bindingPath
+	}
+	for i, k := range derived {
+		if k == key {
+			cycle := append(append([]string{}, derived[i:]...), key)
+			return nil, &ResolutionError{Message: fmt.Sprintf("%s: %s", derivedCycleMessage, strings.Join(cycle, " -> "))}
+		}
+	}
+	chain := append(derived[:len(derived):len(derived)], key)
+
+	plan := &DerivedPlan{Inputs: make([]DerivedInput, 0)}
+	plan.Expression, _ = config[catalog.DerivedExpressionKey].(string)
+	plan.Transform, _ = config[catalog.DerivedTransformKey].(string)
+
+	inputs := catalog.DerivedInputs(config)
+	for _, name := range sortedInputNames(inputs) {
+		ref := inputs[name]
+		input, err := moniker.ParseMoniker(ref)
+		if err != nil {
+			return nil, &ResolutionError{Message: fmt.Sprintf("Derived input '%s' of %s is not a valid moniker: %v", name, bindingPath, err)}
+		}
+		result, err := s.resolve(ctx, input, caller, chain)
+		if err != nil {
+			if _, denied := err.(*AccessDeniedError); denied {
+				return nil, err
+			}
+			if resErr, ok := err.(*ResolutionError); ok && strings.HasPrefix(resErr.Message, derivedCycleMessage) {
+				return nil, err
+			}
+			return nil, &ResolutionError{Message: fmt.Sprintf("Derived input '%s' (%s) of %s: %v", name, ref, bindingPath, err)}
+		}
+		plan.Inputs = append(plan.Inputs, DerivedInput{
+			Name:        name,
+			Moniker:     ref,
+			Path:        result.Path,
+			BindingPath: result.BindingPath,
+			Source:      result.Source,
+		})
+	}
+	return plan, nil
+}
+
+// derivedDependencies lists the inputs of a derived binding for Describe
+func derivedDependencies(config map[string]interface{}) []DerivedDependency {
+	inputs := catalog.DerivedInputs(config)
+	deps := make([]DerivedDependency, 0, len(inputs))
+	for _, name := range sortedInputNames(inputs) {
+		dep := DerivedDependency{Name: name, Moniker: inputs[name]}
+		if m, err := moniker.ParseMoniker(inputs[name]); err == nil {
+			dep.Path = m.CanonicalPath()
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+func sortedInputNames(inputs map[string]string) []string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
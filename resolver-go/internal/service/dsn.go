@@ -0,0 +1,71 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// dsnSecretKeys are connection keys holding credentials; they are only passed
+// to a DSNBuilder for callers with the reveal_secrets capability
+var dsnSecretKeys = map[string]bool{
+	"password":               true,
+	"private_key":            true,
+	"private_key_passphrase": true,
+	"token":                  true,
+	"api_key":                true,
+	"client_secret":          true,
+}
+
+// DSNBuilders holds an adapters.DSNBuilder per source type. Secret values
+// are removed from the connection map before a builder sees it unless the
+// caller may reveal them.
+type DSNBuilders struct {
+	mu       sync.RWMutex
+	builders map[catalog.SourceType]adapters.DSNBuilder
+}
+
+// NewDSNBuilders creates builders for snowflake, mssql, oracle and rest.
+// Other source types are registered with Register.
+func NewDSNBuilders() *DSNBuilders {
+	return &DSNBuilders{
+		builders: map[catalog.SourceType]adapters.DSNBuilder{
+			catalog.SourceTypeSnowflake: adapters.SnowflakeDSN,
+			catalog.SourceTypeMSSQL:     adapters.MSSQLDSN,
+			catalog.SourceTypeOracle:    adapters.OracleDSN,
+			catalog.SourceTypeREST:      adapters.RESTDSN,
+		},
+	}
+}
+
+// Register sets the builder for a source type, replacing any existing one;
+// nil removes it
+func (d *DSNBuilders) Register(sourceType catalog.SourceType, b adapters.DSNBuilder) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if b == nil {
+		delete(d.builders, sourceType)
+		return
+	}
+	d.builders[sourceType] = b
+}
+
+// build returns the DSN of a connection map, or "" when the source type has
+// no builder. Secrets are left out unless reveal is true.
+func (d *DSNBuilders) build(sourceType catalog.SourceType, conn map[string]interface{}, reveal bool) (string, error) {
+	d.mu.RLock()
+	b, ok := d.builders[sourceType]
+	d.mu.RUnlock()
+	if !ok {
+		return "", nil
+	}
+	input := make(map[string]interface{}, len(conn))
+	for k, v := range conn {
+		if !reveal && (dsnSecretKeys[k] || v == SecretMask) {
+			continue
+		}
+		input[k] = v
+	}
+	return b(input)
+}
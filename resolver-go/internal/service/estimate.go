@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// Estimate decisions: what resolving the moniker would do
+const (
+	EstimateAllowed              = "allowed"
+	EstimateWarned               = "warned"                // Allowed with a large query warning
+	EstimateBlocked              = "blocked"               // Refused by an access policy rule
+	EstimateConfirmationRequired = "confirmation_required" // Allowed, but above require_confirmation_above
+)
+
+// EstimateResult is the dry run of resolving a moniker: the rows its access
+// policy estimates and the decision the policy would make. Rule names the
+// policy rule that decided, and PolicyPath the node whose policy applied;
+// both are empty, as is EstimatedRows, when no policy applied.
+type EstimateResult struct {
+	Moniker        string  `json:"moniker"`
+	Path           string  `json:"path"`
+	BindingPath    string  `json:"binding_path"`
+	SourceType     string  `json:"source_type"`
+	Namespace      string  `json:"namespace,omitempty"`
+	RedirectedFrom *string `json:"redirected_from,omitempty"`
+	PolicyPath     string  `json:"policy_path,omitempty"`
+	EstimatedRows  *int    `json:"estimated_rows,omitempty"`
+	Decision       string  `json:"decision"`
+	Rule           string  `json:"rule,omitempty"`
+	Message        *string `json:"message,omitempty"`
+}
+
+// Estimate is a pre-flight check for Resolve and Fetch: it parses the
+// moniker, finds its binding the way Resolve does and evaluates the binding
+// node's access policy for the caller, without building the source, resolving
+// secrets or calling an adapter. A blocked moniker is a result, not an error;
+// unknown, archived and sunset monikers fail as they would resolving.
+// Estimates are not audited.
+func (s *MonikerService) Estimate(ctx context.Context, monikerStr string, caller *CallerIdentity) (*EstimateResult, error) {
+	m, err := parseMoniker(monikerStr)
+	if err != nil {
+		return nil, err
+	}
+	path := m.CanonicalPath()
+	reg, lookupPath, namespace, err := s.lookupCatalog(m, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	if node := reg.Get(lookupPath); node != nil && node.Status == catalog.NodeStatusArchived {
+		return nil, goneError(reg, node)
+	}
+	binding, bindingPath := reg.FindSourceBinding(lookupPath)
+	if binding == nil {
+		return nil, &NotFoundError{Path: path}
+	}
+	// user@ lookups are scoped to the caller; the result reports unscoped paths
+	scope := strings.TrimSuffix(lookupPath, path)
+	unscope := func(p string) string { return strings.TrimPrefix(p, scope) }
+	result := &EstimateResult{
+		Moniker:   m.String(),
+		Path:      path,
+		Namespace: namespace,
+		Decision:  EstimateAllowed,
+	}
+
+	// The policy of the node that applies, as Resolve picks it
+	decide := func(policyNode *catalog.CatalogNode) {
+		if policyNode == nil || policyNode.AccessPolicy == nil {
+			return
+		}
+		d := s.decidePolicy(ctx, policyNode.AccessPolicy, m, caller)
+		result.PolicyPath = unscope(policyNode.Path)
+		result.EstimatedRows = &d.EstimatedRows
+		result.Decision, result.Rule, result.Message = d.Decision, d.Rule, d.Message
+	}
+
+	// A deprecated node redirects to its successor, whose policy applies as
+	// if it were resolved directly
+	node := reg.Get(bindingPath)
+	if err := s.checkSunset(node); err != nil {
+		return nil, err
+	}
+	if node != nil && node.Status == catalog.NodeStatusDeprecated && node.Successor != nil {
+		chain, err := reg.SuccessorChain(bindingPath)
+		if issue, ok := err.(*catalog.SuccessorIssue); ok && issue.Kind == catalog.SuccessorIssueCycle {
+			return nil, &ResolutionError{Message: issue.Message}
+		}
+		if err == nil && len(chain) > 1 && len(chain)-1 <= maxSuccessorDepth {
+			successorPath := chain[len(chain)-1]
+			if successor, successorBindingPath := reg.FindSourceBinding(successorPath); successor != nil {
+				from := path
+				result.RedirectedFrom = &from
+				result.Path = unscope(successorPath)
+				result.BindingPath = unscope(successorBindingPath)
+				result.SourceType = string(successor.SourceType)
+				decide(reg.Get(successorPath))
+				return result, nil
+			}
+		}
+	}
+	result.BindingPath = unscope(bindingPath)
+	result.SourceType = string(binding.SourceType)
+	decide(node)
+	return result, nil
+}
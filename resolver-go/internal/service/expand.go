@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// DefaultMaxExpansions bounds the monikers an expand_all resolve may expand
+// into when query.max_expansions is unset
+const DefaultMaxExpansions = 1000
+
+// ExpansionTooLargeError is an expand_all resolve whose ALL segments expand
+// into more fully-specified monikers than query.max_expansions allows
+type ExpansionTooLargeError struct {
+	Moniker string
+	Max     int
+}
+
+func (e *ExpansionTooLargeError) Error() string {
+	return fmt.Sprintf("the ALL segments of %s expand into more than %d monikers; narrow the moniker", e.Moniker, e.Max)
+}
+
+// Expansion is the set of catalog children an ALL segment expanded into
+// below one parent path
+type Expansion struct {
+	Segment int      `json:"segment"` // Index of the ALL segment in the path
+	Parent  string   `json:"parent"`
+	Values  []string `json:"values"`
+}
+
+// ExpandedMoniker is one fully-specified moniker of an expansion and the
+// binding it resolves through
+type ExpandedMoniker struct {
+	Moniker     string `json:"moniker"`
+	Path        string `json:"path"`
+	BindingPath string `json:"binding_path,omitempty"`
+	SourceType  string `json:"source_type,omitempty"`
+
+	segments []string
+}
+
+// ExpandedResult is what the ALL segments of a moniker expanded into: the
+// children of each ALL segment per parent, and every combination as a moniker.
+// An ALL segment at a level without catalog children stays ALL.
+type ExpandedResult struct {
+	Expansions []Expansion       `json:"expansions"`
+	Monikers   []ExpandedMoniker `json:"monikers"`
+}
+
+// expansionKey carries the expansion of an expand_all resolve to resolveIn,
+// for the moniker it was expanded from
+type expansionKey struct{}
+
+type expansionValue struct {
+	moniker  *moniker.Moniker
+	expanded *ExpandedResult
+}
+
+// maxExpansions returns query.max_expansions, or DefaultMaxExpansions
+func (s *MonikerService) maxExpansions() int {
+	if s.config != nil && s.config.Query.MaxExpansions > 0 {
+		return s.config.Query.MaxExpansions
+	}
+	return DefaultMaxExpansions
+}
+
+// lookupCatalog returns the catalog m resolves in, the path to look it up at
+// there and the namespace whose catalog it is: the namespace catalog when it
+// has a binding for m, otherwise the default catalog
+func (s *MonikerService) lookupCatalog(m *moniker.Moniker, caller *CallerIdentity) (*catalog.Registry, string, string, error) {
+	path := m.CanonicalPath()
+	if m.Namespace != nil {
+		nsReg, nsPath, err := s.namespaceCatalog(*m.Namespace, path, caller)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if binding, _ := nsReg.FindSourceBinding(nsPath); binding != nil {
+			return nsReg, nsPath, *m.Namespace, nil
+		}
+	}
+	return s.catalog, path, "", nil
+}
+
+// expandAll replaces each ALL segment of m at a level with catalog children
+// by each child in turn, failing with ExpansionTooLargeError past
+// maxExpansions monikers
+func (s *MonikerService) expandAll(m *moniker.Moniker, caller *CallerIdentity) (*ExpandedResult, error) {
+	reg, lookupPath, _, err := s.lookupCatalog(m, caller)
+	if err != nil {
+		return nil, err
+	}
+	// user@ lookups are scoped to the caller; the result reports unscoped paths
+	scope := strings.TrimSuffix(lookupPath, m.CanonicalPath())
+	limit := s.maxExpansions()
+	result := &ExpandedResult{Expansions: make([]Expansion, 0), Monikers: make([]ExpandedMoniker, 0)}
+
+	segments := m.Path.Segments
+	var expand func(prefix []string) error
+	expand = func(prefix []string) error {
+		i := len(prefix)
+		if i == len(segments) {
+			if len(result.Monikers) >= limit {
+				return &ExpansionTooLargeError{Moniker: m.String(), Max: limit}
+			}
+			result.Monikers = append(result.Monikers, s.expandedMoniker(reg, scope, m, prefix))
+			return nil
+		}
+		values := []string{segments[i]}
+		if strings.EqualFold(segments[i], "ALL") {
+			parent := strings.Join(prefix, "/")
+			if children := childNames(reg, scope+parent); len(children) > 0 {
+				values = children
+				result.Expansions = append(result.Expansions, Expansion{Segment: i, Parent: parent, Values: children})
+			}
+		}
+		for _, v := range values {
+			next := append(prefix[:i:i], v)
+			if err := expand(next); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := expand(make([]string, 0, len(segments))); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// expandedMoniker is m with its path replaced by segments, and the binding
+// that path resolves through
+func (s *MonikerService) expandedMoniker(reg *catalog.Registry, scope string, m *moniker.Moniker, segments []string) ExpandedMoniker {
+	expanded := *m
+	expanded.Path = &moniker.MonikerPath{Segments: segments}
+	path := strings.Join(segments, "/")
+	em := ExpandedMoniker{Moniker: expanded.String(), Path: path, segments: segments}
+	if binding, bindingPath := reg.FindSourceBinding(scope + path); binding != nil {
+		em.BindingPath = strings.TrimPrefix(bindingPath, scope)
+		em.SourceType = string(binding.SourceType)
+	}
+	return em
+}
+
+// childNames returns the last segments of the children of path, sorted
+func childNames(reg *catalog.Registry, path string) []string {
+	paths := reg.ChildrenPaths(path)
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, p[strings.LastIndex(p, "/")+1:])
+	}
+	sort.Strings(names)
+	return names
+}
+
+// evaluatePolicy evaluates an access policy for m. When ctx carries the
+// expansion of an expand_all resolve of m, rows are estimated over the
+// expanded monikers rather than from the ALL segments.
+func evaluatePolicy(ctx context.Context, ap *catalog.AccessPolicy, m *moniker.Moniker) catalog.PolicyEvaluation {
+	if v, ok := ctx.Value(expansionKey{}).(expansionValue); ok && v.moniker == m {
+		expanded := make([][]string, len(v.expanded.Monikers))
+		for i, em := range v.expanded.Monikers {
+			expanded[i] = em.segments
+		}
+		return ap.EvaluateExpanded(m.Path.Segments, expanded)
+	}
+	return ap.Evaluate(m.Path.Segments)
+}
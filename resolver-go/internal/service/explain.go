@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// ResolveOptions adjust how a moniker is resolved
+type ResolveOptions struct {
+	// Explain attaches a Trace to the result. Explained results bypass the
+	// resolve cache in both directions.
+	Explain bool
+
+	// ExpandAll expands each ALL segment at a level with catalog children
+	// into those children, attaching the monikers to the result as Expanded;
+	// the access policy estimates rows over them. Expanded results bypass
+	// the resolve cache.
+	ExpandAll bool
+}
+
+// ResolveTrace explains how a moniker resolved to its binding
+type ResolveTrace struct {
+	Parse ParseTrace `json:"parse"`
+
+	// NamespaceLookup lists the paths checked in the namespace catalog of a
+	// namespaced moniker; NamespaceFallback is set when none had a binding
+	// and the default catalog was used
+	NamespaceLookup   []catalog.BindingCandidate `json:"namespace_lookup,omitempty"`
+	NamespaceFallback bool                       `json:"namespace_fallback,omitempty"`
+
+	// BindingLookup lists the paths checked for a binding, exact path first
+	BindingLookup []catalog.BindingCandidate `json:"binding_lookup"`
+
+	// SuccessorHops is the successor chain followed from a deprecated node,
+	// and SuccessorLookup the binding lookup at its end
+	SuccessorHops   []string                   `json:"successor_hops,omitempty"`
+	SuccessorLookup []catalog.BindingCandidate `json:"successor_lookup,omitempty"`
+
+	AccessPolicy *AccessPolicyTrace `json:"access_policy,omitempty"`
+
+	// OwnershipProvenance maps each resolved ownership field to the path
+	// that defined it
+	OwnershipProvenance map[string]string `json:"ownership_provenance,omitempty"`
+}
+
+// ParseTrace is the breakdown of the parsed moniker
+type ParseTrace struct {
+	Namespace       *string           `json:"namespace,omitempty"`
+	Path            string            `json:"path"`
+	SegmentID       *string           `json:"segment_id,omitempty"`
+	Version         *string           `json:"version,omitempty"` // date@ value
+	FilterShortlink *string           `json:"filter_shortlink,omitempty"`
+	Revision        *int              `json:"revision,omitempty"`
+	SubResource     *string           `json:"sub_resource,omitempty"`
+	Params          map[string]string `json:"params,omitempty"`
+}
+
+// AccessPolicyTrace lists the access policy rules evaluated for the binding
+// node, in the order they are checked. A denied resolve stops at the rule
+// that denied it.
+type AccessPolicyTrace struct {
+	Path          string        `json:"path"` // Node whose policy applied
+	EstimatedRows int           `json:"estimated_rows"`
+	Checks        []PolicyCheck `json:"checks"`
+}
+
+// PolicyCheck is one access policy rule and its outcome: passed, bypassed
+// (allowed_hours for a bypass role), warned (max_rows_warn) or denied
+type PolicyCheck struct {
+	Rule   string `json:"rule"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// traceKey carries the trace of an explained resolve to resolveIn, which
+// records each step as it takes it, for the moniker being explained
+type traceKey struct{}
+
+type traceValue struct {
+	moniker *moniker.Moniker
+	trace   *ResolveTrace
+}
+
+// traceFrom returns the trace ctx carries for m, or nil when m is not being
+// explained; the inputs of a derived binding are not traced
+func traceFrom(ctx context.Context, m *moniker.Moniker) *ResolveTrace {
+	if v, ok := ctx.Value(traceKey{}).(traceValue); ok && v.moniker == m {
+		return v.trace
+	}
+	return nil
+}
+
+// findSourceBinding is reg.FindSourceBinding, also returning the paths
+// checked when m is being explained
+func findSourceBinding(ctx context.Context, reg *catalog.Registry, m *moniker.Moniker, path string) (*catalog.SourceBinding, string, []catalog.BindingCandidate) {
+	if traceFrom(ctx, m) == nil {
+		binding, bindingPath := reg.FindSourceBinding(path)
+		return binding, bindingPath, nil
+	}
+	return reg.TraceSourceBinding(path)
+}
+
+// ResolveWithOptions is Resolve with options; see ResolveOptions. An
+// explained resolve refused by an access policy returns the trace up to the
+// denial on the *AccessDeniedError.
+func (s *MonikerService) ResolveWithOptions(ctx context.Context, monikerStr string, caller *CallerIdentity, opts ResolveOptions) (*ResolveResult, error) {
+	if !opts.Explain && !opts.ExpandAll {
+		return s.Resolve(ctx, monikerStr, caller)
+	}
+	start := time.Now()
+	m, err := parseMoniker(monikerStr)
+	var expanded *ExpandedResult
+	if err == nil && opts.ExpandAll {
+		if expanded, err = s.expandAll(m, caller); err == nil {
+			ctx = context.WithValue(ctx, expansionKey{}, expansionValue{moniker: m, expanded: expanded})
+		}
+	}
+	var trace *ResolveTrace
+	if err == nil && opts.Explain {
+		trace = newResolveTrace(m)
+		ctx = context.WithValue(ctx, traceKey{}, traceValue{moniker: m, trace: trace})
+	}
+	var result *ResolveResult
+	if err == nil {
+		result, err = s.resolve(ctx, m, caller, nil)
+	}
+	s.observeResolve(m, result, err, time.Since(start))
+	if err != nil {
+		if denied, ok := err.(*AccessDeniedError); ok && trace != nil {
+			denied.Trace = trace
+		}
+		return nil, err
+	}
+	if trace != nil {
+		trace.Parse.SubResource = result.SubResource
+		trace.OwnershipProvenance = ownershipProvenance(result.Ownership)
+		result.Trace = trace
+	}
+	result.Expanded = expanded
+	s.auditResolved(m, result, caller)
+	return result, nil
+}
+
+// newResolveTrace starts the trace of m with its parse breakdown
+func newResolveTrace(m *moniker.Moniker) *ResolveTrace {
+	trace := &ResolveTrace{
+		Parse: ParseTrace{
+			Namespace:       m.Namespace,
+			Path:            m.CanonicalPath(),
+			Version:         m.DateParam,
+			FilterShortlink: m.FilterShortlink,
+			Revision:        m.Revision,
+		},
+	}
+	if m.SegmentID != nil {
+		trace.Parse.SegmentID = &m.SegmentID.Value
+	}
+	if len(m.Params) > 0 {
+		trace.Parse.Params = m.Params
+	}
+	return trace
+}
+
+// traceAccessPolicy lists the rules of node's access policy as decidePolicy
+// checked them, up to the one that denied the resolve if any
+func traceAccessPolicy(node *catalog.CatalogNode, d policyDecision) *AccessPolicyTrace {
+	ap := node.AccessPolicy
+	trace := &AccessPolicyTrace{Path: node.Path, EstimatedRows: d.EstimatedRows, Checks: make([]PolicyCheck, 0)}
+	// check records a rule, returning false when it is the one that denied
+	check := func(rule, detail string) bool {
+		if d.Decision == EstimateBlocked && d.Rule == rule {
+			trace.Checks = append(trace.Checks, PolicyCheck{Rule: rule, Result: "denied", Detail: *d.Message})
+			return false
+		}
+		trace.Checks = append(trace.Checks, PolicyCheck{Rule: rule, Result: "passed", Detail: detail})
+		return true
+	}
+
+	if len(ap.AllowedRoles) > 0 && !check("allowed_roles", "caller holds one of: "+strings.Join(ap.AllowedRoles, ", ")) {
+		return trace
+	}
+	if ap.AllowedHours != nil {
+		detail := fmt.Sprintf("window %02d:00-%02d:00 UTC, now %02d:00 UTC", ap.AllowedHours[0], ap.AllowedHours[1], d.CurrentHour)
+		if d.HoursBypassed {
+			trace.Checks = append(trace.Checks, PolicyCheck{Rule: "allowed_hours", Result: "bypassed", Detail: detail})
+		} else if !check("allowed_hours", detail) {
+			return trace
+		}
+	}
+	if len(ap.BlockedPatterns) > 0 && !check(catalog.PolicyRuleBlockedPatterns, "") {
+		return trace
+	}
+	if len(ap.RequiredSegments) > 0 && !check(catalog.PolicyRuleRequiredSegments, "") {
+		return trace
+	}
+	if ap.MinFilters > 0 && !check(catalog.PolicyRuleMinFilters, fmt.Sprintf("at least %d", ap.MinFilters)) {
+		return trace
+	}
+	if ap.MaxRowsBlock != nil && !check(catalog.PolicyRuleMaxRowsBlock, fmt.Sprintf("limit %d", *ap.MaxRowsBlock)) {
+		return trace
+	}
+	if d.Warning != nil {
+		trace.Checks = append(trace.Checks, PolicyCheck{Rule: catalog.PolicyRuleMaxRowsWarn, Result: "warned", Detail: *d.Warning})
+	} else if ap.MaxRowsWarn != nil {
+		check(catalog.PolicyRuleMaxRowsWarn, fmt.Sprintf("threshold %d", *ap.MaxRowsWarn))
+	}
+	return trace
+}
+
+// ownershipProvenance maps each set ownership field to the path it was
+// inherited from, read from the *_source fields of the resolved ownership
+func ownershipProvenance(ownership *catalog.ResolvedOwnership) map[string]string {
+	if ownership == nil {
+		return nil
+	}
+	data, err := json.Marshal(ownership)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	provenance := make(map[string]string)
+	for k, v := range fields {
+		if source, ok := v.(string); ok && strings.HasSuffix(k, "_source") {
+			provenance[strings.TrimSuffix(k, "_source")] = source
+		}
+	}
+	return provenance
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// FetchNotSupportedError is a fetch of a source type with no adapter
+type FetchNotSupportedError struct {
+	Path       string
+	SourceType string
+}
+
+func (e *FetchNotSupportedError) Error() string {
+	return fmt.Sprintf("no data adapter for source type %s (binding of %s)", e.SourceType, e.Path)
+}
+
+// FetchError is an adapter failing to fetch a binding's data
+type FetchError struct {
+	BindingPath string
+	Err         error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch %s: %v", e.BindingPath, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// FetchTimeoutError is an adapter fetch cut off by fetch.timeout_seconds
+type FetchTimeoutError struct {
+	BindingPath string
+	Timeout     time.Duration
+}
+
+func (e *FetchTimeoutError) Error() string {
+	return fmt.Sprintf("fetch %s: no response from the source within %s", e.BindingPath, e.Timeout)
+}
+
+// dataCachePrefix namespaces fetched data in the shared cache
+const dataCachePrefix = "data:"
+
+// DefaultFetchTimeout bounds an adapter fetch without fetch.timeout_seconds
+const DefaultFetchTimeout = 30 * time.Second
+
+// fetchTimeout returns fetch.timeout_seconds, or DefaultFetchTimeout
+func (s *MonikerService) fetchTimeout() time.Duration {
+	if s.config != nil && s.config.Fetch.TimeoutSeconds > 0 {
+		return time.Duration(s.config.Fetch.TimeoutSeconds * float64(time.Second))
+	}
+	return DefaultFetchTimeout
+}
+
+// OperationNotAllowedError is a fetch whose query or request performs an
+// operation its binding does not allow
+type OperationNotAllowedError struct {
+	Operation   string
+	BindingPath string
+	Allowed     []string
+}
+
+func (e *OperationNotAllowedError) Error() string {
+	return fmt.Sprintf("operation %s is not allowed on %s (allowed: %s)",
+		e.Operation, e.BindingPath, strings.Join(e.Allowed, ", "))
+}
+
+// Adapters returns the data adapters, for registering adapters of
+// additional source types
+func (s *MonikerService) Adapters() *adapters.Registry {
+	return s.adapters
+}
+
+// Fetch resolves a moniker, with the same access checks as Resolve, and
+// fetches its data through the adapter of its source type. Secrets in the
+// binding config are resolved for the adapter whatever the caller's
+// capabilities; they never reach the caller. Invalid requests are returned as
+// *adapters.RequestError and adapter failures as *FetchError. Queries and
+// requests performing an operation outside the binding's allowed operations
+// (only reads for a read-only binding without any) are refused with
+// *OperationNotAllowedError before secrets are resolved, and a query left
+// holding {version_date} with *adapters.RequestError. When the binding's
+// cache is enabled the data is cached for its ttl_seconds, or
+// cache.data_ttl_seconds, unless the adapter caches it itself; the access
+// checks still run on every fetch.
+func (s *MonikerService) Fetch(ctx context.Context, monikerStr string, caller *CallerIdentity) (*adapters.DataResult, error) {
+	result, err := s.Resolve(ctx, monikerStr, caller)
+	if err != nil {
+		return nil, err
+	}
+	sourceType := catalog.SourceType(result.Source.SourceType)
+	if result.binding != nil {
+		query := ""
+		if result.Source.Query != nil {
+			query = *result.Source.Query
+		}
+		allowed := adapters.AllowedOperations(result.binding)
+		if op := adapters.Disallowed(adapters.Operations(sourceType, query, result.config), allowed); op != "" {
+			return nil, &OperationNotAllowedError{Operation: op, BindingPath: result.BindingPath, Allowed: allowed}
+		}
+		// Resolve leaves it for clients to fill; a fetch has no one to fill it
+		if hasVersionDate(query) {
+			return nil, &adapters.RequestError{Path: result.Path, Message: "moniker has no version for {version_date}"}
+		}
+	}
+	adapter, ok := s.adapters.Get(sourceType)
+	if !ok || result.binding == nil {
+		return nil, &FetchNotSupportedError{Path: result.BindingPath, SourceType: result.Source.SourceType}
+	}
+
+	req := &adapters.Request{
+		Path:         result.Path,
+		BindingPath:  result.BindingPath,
+		Segments:     segmentsBelowBinding(result.SubPath, result.SubResource),
+		Binding:      result.binding,
+		Schema:       result.schema,
+		MaxRows:      result.maxRows,
+		Placeholders: result.placeholders,
+	}
+	if result.Source.Version != nil {
+		req.Version = result.Source.Version.DateParam
+	}
+	if result.Source.Query != nil {
+		req.Query = *result.Source.Query
+		req.ParamStyle = result.Source.ParamStyle
+		for _, p := range result.Source.Params {
+			req.Params = append(req.Params, adapters.Param{Name: p.Name, Value: p.Value})
+		}
+	}
+
+	ttl := s.dataCacheTTL(result.binding.Cache)
+	if self, ok := adapter.(adapters.SelfCaching); ok && self.CachesData() {
+		ttl = 0
+	}
+	key := dataCacheKey(req, s.catalog.Generation())
+	if ttl > 0 {
+		if cached, ok := s.cache.Get(key); ok {
+			data := *cached.(*adapters.DataResult)
+			return &data, nil
+		}
+	}
+	if err := s.setAdapterConfig(req, result.config); err != nil {
+		return nil, err
+	}
+
+	timeout := s.fetchTimeout()
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	data, err := adapter.Fetch(fetchCtx, req)
+	if err != nil {
+		var reqErr *adapters.RequestError
+		if errors.As(err, &reqErr) {
+			return nil, reqErr
+		}
+		if ctx.Err() != nil {
+			// The caller went away or its own deadline passed first
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, context.DeadlineExceeded) || fetchCtx.Err() != nil {
+			return nil, &FetchTimeoutError{BindingPath: result.BindingPath, Timeout: timeout}
+		}
+		return nil, &FetchError{BindingPath: result.BindingPath, Err: err}
+	}
+	capRows(data, req.MaxRows)
+	data.ColumnMetadata = columnMetadata(req.Schema, data.Columns)
+	if ttl > 0 {
+		cached := *data
+		s.cache.SetWithTTL(key, &cached, ttl)
+	}
+	return data, nil
+}
+
+// dataCacheKey identifies the data of a fetch in the cache. The binding
+// itself identifies it across namespaces and reloads, with the generation of
+// the default catalog; the path leads, so InvalidatePath can find it. The
+// rest is what the adapter is asked for, max_rows_block included, as access
+// policies may set it per caller.
+func dataCacheKey(req *adapters.Request, generation uint64) string {
+	return fmt.Sprintf("%s%s:%d:%p:%s:%s:%q:%v:%v:%d", dataCachePrefix, req.Path, generation, req.Binding,
+		strings.Join(req.Segments, "/"), req.Version, req.Query, req.Params, req.Placeholders, req.MaxRows)
+}
+
+// dataCacheTTL returns how long fetched data is cached: the binding's
+// cache.ttl_seconds, or cache.data_ttl_seconds, or cache.default_ttl_seconds,
+// and 0 when the binding's cache is not enabled
+func (s *MonikerService) dataCacheTTL(cfg *catalog.QueryCacheConfig) time.Duration {
+	if s.cache == nil || cfg == nil || !cfg.Enabled {
+		return 0
+	}
+	if cfg.TTLSeconds > 0 {
+		return time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	if s.config == nil {
+		return 0
+	}
+	if ttl := s.config.Cache.DataTTLSeconds; ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return time.Duration(s.config.Cache.DefaultTTLSeconds) * time.Second
+}
+
+// capRows drops the rows beyond maxRows, whatever the adapter returned, so
+// access_policy.max_rows_block holds for adapters that do not apply it
+func capRows(data *adapters.DataResult, maxRows int) {
+	if maxRows > 0 && len(data.Rows) > maxRows {
+		data.Rows, data.Truncated = data.Rows[:maxRows], true
+	}
+	data.RowCount = len(data.Rows)
+}
+
+// columnMetadata returns the schema's description of each of columns it
+// declares, in columns order
+func columnMetadata(schema *catalog.DataSchema, columns []string) []catalog.ColumnSchema {
+	if schema == nil {
+		return nil
+	}
+	byName := make(map[string]catalog.ColumnSchema, len(schema.Columns))
+	for _, col := range schema.Columns {
+		byName[col.Name] = col
+	}
+	var metadata []catalog.ColumnSchema
+	for _, name := range columns {
+		if col, ok := byName[name]; ok {
+			metadata = append(metadata, col)
+		}
+	}
+	return metadata
+}
+
+// setAdapterConfig sets the config of an adapter request: the binding config
+// with its secret references resolved, whatever the caller's capabilities,
+// and the keys that held them
+func (s *MonikerService) setAdapterConfig(req *adapters.Request, config map[string]interface{}) error {
+	resolved, err := s.secrets.resolveSecrets("", config, true)
+	if err != nil {
+		return &FetchError{BindingPath: req.BindingPath, Err: err}
+	}
+	req.Config = resolved.(map[string]interface{})
+	req.SecretKeys = nil
+	for k, v := range config {
+		if IsSecretRef(v) {
+			req.SecretKeys = append(req.SecretKeys, k)
+		}
+	}
+	sort.Strings(req.SecretKeys)
+	return nil
+}
+
+// segmentsBelowBinding returns the path segments below the binding, without
+// the final segment when it selected a sub-resource
+func segmentsBelowBinding(subPath, subResource *string) []string {
+	if subPath == nil || *subPath == "" {
+		return nil
+	}
+	segments := strings.Split(*subPath, "/")
+	if subResource != nil {
+		segments = segments[:len(segments)-1]
+	}
+	return segments
+}
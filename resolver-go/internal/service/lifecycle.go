@@ -0,0 +1,45 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// sunsetPassed reports whether node is deprecated with a sunset deadline at
+// or before now. Malformed deadlines never pass; catalog validation reports
+// them.
+func sunsetPassed(node *catalog.CatalogNode, now time.Time) bool {
+	if node == nil || node.Status != catalog.NodeStatusDeprecated || node.SunsetDeadline == nil {
+		return false
+	}
+	deadline, err := catalog.ParseSunsetDeadline(*node.SunsetDeadline)
+	return err == nil && !now.Before(deadline)
+}
+
+// checkSunset returns a *SunsetError when node's sunset deadline has passed,
+// unless deprecation.sunset_grace is set
+func (s *MonikerService) checkSunset(node *catalog.CatalogNode) error {
+	if s.config != nil && s.config.Deprecation.SunsetGrace {
+		return nil
+	}
+	if !sunsetPassed(node, s.clock()) {
+		return nil
+	}
+	return &SunsetError{
+		Path:              node.Path,
+		SunsetDeadline:    *node.SunsetDeadline,
+		Successor:         node.Successor,
+		MigrationGuideURL: node.MigrationGuideURL,
+	}
+}
+
+// goneError describes an archived node, with the successors a caller should
+// move to
+func goneError(reg *catalog.Registry, node *catalog.CatalogNode) *GoneError {
+	e := &GoneError{Path: node.Path, MigrationGuideURL: node.MigrationGuideURL}
+	if chain, err := reg.SuccessorChain(node.Path); err == nil && len(chain) > 1 {
+		e.SuccessorChain = chain
+	}
+	return e
+}
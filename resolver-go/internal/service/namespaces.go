@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// UserNamespace holds per-user monikers such as user@analytics/views/watchlist.
+// Its catalog keys each node by the owning user's ID followed by the path
+// (alice/analytics/views/watchlist), so each caller only sees their own.
+const UserNamespace = "user"
+
+// AnonymousUser is the user ID of callers that did not identify themselves
+const AnonymousUser = "anonymous"
+
+// SetNamespace registers the overlay catalog of a namespace, replacing any
+// existing one. Monikers in the namespace resolve against it first and fall
+// back to the default catalog.
+func (s *MonikerService) SetNamespace(name string, reg *catalog.Registry) {
+	s.nsMu.Lock()
+	defer s.nsMu.Unlock()
+	s.namespaces[name] = reg
+}
+
+// Namespace returns the overlay catalog of a namespace, or nil if none is
+// registered
+func (s *MonikerService) Namespace(name string) *catalog.Registry {
+	s.nsMu.RLock()
+	defer s.nsMu.RUnlock()
+	return s.namespaces[name]
+}
+
+// namespaceCatalog returns the overlay catalog of a namespace and the path to
+// look up in it. user@ paths are scoped by the caller's user ID.
+func (s *MonikerService) namespaceCatalog(name, path string, caller *CallerIdentity) (*catalog.Registry, string, error) {
+	s.nsMu.RLock()
+	reg, ok := s.namespaces[name]
+	known := make([]string, 0, len(s.namespaces))
+	for ns := range s.namespaces {
+		known = append(known, ns)
+	}
+	s.nsMu.RUnlock()
+
+	if !ok {
+		sort.Strings(known)
+		if len(known) == 0 {
+			return nil, "", &ResolutionError{Message: fmt.Sprintf("Unknown namespace '%s' (no namespaces configured)", name)}
+		}
+		return nil, "", &ResolutionError{Message: fmt.Sprintf("Unknown namespace '%s' (configured: %v)", name, known)}
+	}
+	if name != UserNamespace {
+		return reg, path, nil
+	}
+	if caller == nil || caller.UserID == "" || caller.UserID == AnonymousUser {
+		return nil, "", &ResolutionError{Message: fmt.Sprintf("%s@ monikers require an identified caller", UserNamespace)}
+	}
+	return reg, caller.UserID + "/" + path, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// bindQueryParams returns the values for the {params.NAME} placeholders of
+// a binding config. When the config declares allowed_params, every moniker
+// param must be declared and every required one supplied; defaults fill the
+// rest. Without a declaration, moniker params are not used in queries.
+// FrequencyParam is exempt, as resolveVersion checks it.
+func bindQueryParams(path string, config map[string]interface{}, m *moniker.Moniker) (map[string]string, error) {
+	raw, ok := config[catalog.AllowedParamsKey]
+	if !ok {
+		return nil, nil
+	}
+	declared, _ := raw.(map[string]interface{})
+
+	unknown := make([]string, 0)
+	for name := range m.Params {
+		if _, ok := declared[name]; !ok && name != FrequencyParam {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &ResolutionError{Message: fmt.Sprintf("Unknown query params for %s: %s (allowed: %s)",
+			path, strings.Join(unknown, ", "), strings.Join(sortedKeys(declared), ", "))}
+	}
+
+	values := make(map[string]string, len(declared))
+	missing := make([]string, 0)
+	for name, def := range declared {
+		if value, ok := m.Params[name]; ok {
+			values[name] = value
+			continue
+		}
+		if def == nil {
+			missing = append(missing, name)
+			continue
+		}
+		values[name] = fmt.Sprint(def)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, &ResolutionError{Message: fmt.Sprintf("Missing required query params for %s: %s", path, strings.Join(missing, ", "))}
+	}
+	return values, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// QueryParam is a bind parameter of a resolved query, in the order the
+// query's markers consume them
+type QueryParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Param styles, named as in Python's DB-API
+const (
+	ParamStyleQmark    = "qmark"    // ? markers, bound by position
+	ParamStyleNamed    = "named"    // :name markers
+	ParamStylePyformat = "pyformat" // %(name)s markers
+)
+
+// paramStyles maps SQL source types to the bind marker style of their
+// drivers. Other source types have no bind parameters; their queries are
+// returned with values substituted.
+var paramStyles = map[catalog.SourceType]string{
+	catalog.SourceTypeMSSQL:     ParamStyleQmark,
+	catalog.SourceTypeOracle:    ParamStyleNamed,
+	catalog.SourceTypeSnowflake: ParamStylePyformat,
+}
+
+// queryPlaceholderPattern matches a query placeholder, with the single
+// quotes around it when it is written as a string literal
+var queryPlaceholderPattern = regexp.MustCompile(`'?\{(segments\[\d+\]|segment_id\[\d+\]|segment_id_value|segment_id_index|has_segment_id|lookback_start|lookback_end|version_date|frequency|params\.[^{}]*)\}'?`)
+
+// unsafeRenderedValues are substrings that could end a literal or statement
+// when a value is substituted into query text
+var unsafeRenderedValues = []string{"'", `"`, ";", `\`, "--", "/*"}
+
+// boundQuery is a query template with its placeholders resolved
+type boundQuery struct {
+	Query    string // Query with bind markers, or rendered when Style is ""
+	Style    string
+	Params   []QueryParam
+	Rendered string // Query with values substituted
+}
+
+// hasVersionDate reports whether a bound query still holds {version_date},
+// as bindQuery leaves it for a moniker without a date@ version
+func hasVersionDate(query string) bool {
+	for _, match := range queryPlaceholderPattern.FindAllStringSubmatch(query, -1) {
+		if match[1] == "version_date" {
+			return true
+		}
+	}
+	return false
+}
+
+// bindQuery resolves the placeholders of a query template for a moniker:
+// {segments[N]}, {segment_id_value}, {segment_id_index}, {has_segment_id},
+// {segment_id[N]}, {lookback_start}, {lookback_end}, {version_date},
+// {frequency} and {params.NAME}. {version_date} is only bound when the
+// moniker has a date@ version; without one it is left in the query as it is.
+// For SQL source types each placeholder
+// becomes a bind marker, dropping quotes written around it, and its value a
+// bind parameter. A placeholder with no value is an error rather than being
+// left in the query. Rendering checks every value with checkRenderedValue,
+// so it is only done when render is set or the source type has no bind
+// markers.
+func bindQuery(path string, sourceType catalog.SourceType, query string, m *moniker.Moniker, params map[string]string, version *VersionInfo, render bool) (*boundQuery, error) {
+	style := paramStyles[sourceType]
+	render = render || style == ""
+
+	bound := &boundQuery{Style: style}
+	seen := make(map[string]bool)
+	unresolved := make([]string, 0)
+	missing := make(map[string]bool)
+	var bindErr, renderErr error
+	var marked, rendered strings.Builder
+	inLiteral := false
+	last := 0
+	for _, loc := range queryPlaceholderPattern.FindAllStringSubmatchIndex(query, -1) {
+		token := query[loc[0]:loc[1]]
+		name := query[loc[2]:loc[3]]
+		text := query[last:loc[0]]
+		last = loc[1]
+		marked.WriteString(text)
+		inLiteral = inLiteral != (strings.Count(text, "'")%2 == 1)
+
+		value, ok := placeholderValue(name, m, params, version)
+		if !ok && name == "version_date" {
+			// Without date@ there is no version to bind; the query keeps
+			// {version_date} for the client to fill, as before it was bound
+			marked.WriteString(token)
+			rendered.WriteString(text + token)
+			if strings.HasPrefix(token, "'") != strings.HasSuffix(token, "'") {
+				inLiteral = !inLiteral
+			}
+			continue
+		}
+		if !ok {
+			if !missing[name] {
+				missing[name] = true
+				unresolved = append(unresolved, "{"+name+"}")
+			}
+			continue
+		}
+		if render && renderErr == nil {
+			renderErr = checkRenderedValue(name, value)
+		}
+		rendered.WriteString(text + strings.Replace(token, "{"+name+"}", value, 1))
+
+		// A placeholder binds only when it is a whole literal ('{x}') or
+		// outside literals; inside a larger literal the marker would be text
+		open, close := strings.HasPrefix(token, "'"), strings.HasSuffix(token, "'")
+		whole := open == close && !inLiteral
+		if open != close {
+			inLiteral = !inLiteral
+		}
+		if style == "" {
+			continue
+		}
+		if !whole {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("{%s} is part of a larger string literal; quote it on its own and concatenate, e.g. '{%s}' || '%%'", name, name)
+			}
+			continue
+		}
+
+		paramName := bindParamName(name)
+		switch style {
+		case ParamStyleQmark:
+			marked.WriteString("?")
+			bound.Params = append(bound.Params, QueryParam{Name: paramName, Value: value})
+		case ParamStyleNamed:
+			marked.WriteString(":" + paramName)
+		case ParamStylePyformat:
+			marked.WriteString("%(" + paramName + ")s")
+		}
+		if style != ParamStyleQmark && !seen[paramName] {
+			seen[paramName] = true
+			bound.Params = append(bound.Params, QueryParam{Name: paramName, Value: value})
+		}
+	}
+	marked.WriteString(query[last:])
+	rendered.WriteString(query[last:])
+
+	if len(unresolved) > 0 {
+		return nil, unresolvedError(path, unresolved)
+	}
+	if bindErr != nil {
+		return nil, &ResolutionError{Message: fmt.Sprintf("Query for %s cannot be parameterized: %v", path, bindErr)}
+	}
+	if renderErr != nil {
+		return nil, &ResolutionError{Message: fmt.Sprintf("Query for %s cannot be rendered: %v", path, renderErr)}
+	}
+
+	bound.Query = marked.String()
+	if style == ParamStylePyformat && len(bound.Params) > 0 {
+		// The driver formats the whole query, so literal % must be doubled
+		bound.Query = escapePyformat(bound.Query)
+	}
+	if render {
+		bound.Rendered = rendered.String()
+	}
+	if style == "" {
+		bound.Query = bound.Rendered
+	}
+	return bound, nil
+}
+
+// unresolvedError is the error for a query whose placeholders lack values
+func unresolvedError(path string, unresolved []string) error {
+	return &ResolutionError{Message: fmt.Sprintf("Query for %s has unresolved placeholders: %s", path, strings.Join(unresolved, ", "))}
+}
+
+// checkQuery returns the error bindQuery would for a placeholder without a
+// value or a value unsafe to render, without building the query. A
+// placeholder bindQuery can't parameterize where it is written isn't
+// checked.
+func checkQuery(path string, sourceType catalog.SourceType, query string, m *moniker.Moniker, params map[string]string, version *VersionInfo, render bool) error {
+	render = render || paramStyles[sourceType] == ""
+	unresolved := make([]string, 0)
+	missing := make(map[string]bool)
+	var renderErr error
+	for _, match := range queryPlaceholderPattern.FindAllStringSubmatch(query, -1) {
+		name := match[1]
+		value, ok := placeholderValue(name, m, params, version)
+		switch {
+		case !ok && name == "version_date":
+			// Left in the query, as bindQuery does
+		case !ok:
+			if !missing[name] {
+				missing[name] = true
+				unresolved = append(unresolved, "{"+name+"}")
+			}
+		case render && renderErr == nil:
+			renderErr = checkRenderedValue(name, value)
+		}
+	}
+	if len(unresolved) > 0 {
+		return unresolvedError(path, unresolved)
+	}
+	if renderErr != nil {
+		return &ResolutionError{Message: fmt.Sprintf("Query for %s cannot be rendered: %v", path, renderErr)}
+	}
+	return nil
+}
+
+// queryPlaceholders returns the value of each placeholder in a query
+// template, by placeholder name, for adapters that substitute values
+// themselves
+func queryPlaceholders(query string, m *moniker.Moniker, params map[string]string, version *VersionInfo) map[string]string {
+	values := make(map[string]string)
+	for _, match := range queryPlaceholderPattern.FindAllStringSubmatch(query, -1) {
+		if value, ok := placeholderValue(match[1], m, params, version); ok {
+			values[match[1]] = value
+		}
+	}
+	return values
+}
+
+// placeholderValue returns the value of a placeholder, or false if the
+// moniker does not supply one
+func placeholderValue(name string, m *moniker.Moniker, params map[string]string, version *VersionInfo) (string, bool) {
+	switch {
+	case name == "lookback_start" || name == "lookback_end":
+		if version == nil || version.DateParam == "" || version.Kind == VersionAll {
+			return "", false
+		}
+		if name == "lookback_start" {
+			return version.LookbackStart, true
+		}
+		return version.LookbackEnd, true
+	case name == "version_date":
+		// The version date@ selects: its date, the end of a lookback, or for
+		// date@latest the newest listed version when it was resolved
+		if version == nil || version.DateParam == "" || version.Kind == VersionAll {
+			return "", false
+		}
+		if version.Resolved != "" {
+			return version.Resolved, true
+		}
+		return version.LookbackEnd, true
+	case name == "frequency":
+		if version == nil || version.Frequency == "" {
+			return "", false
+		}
+		return version.Frequency, true
+	case strings.HasPrefix(name, "params."):
+		value, ok := params[strings.TrimPrefix(name, "params.")]
+		return value, ok
+	case strings.HasPrefix(name, "segments["):
+		i, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "segments["), "]"))
+		if i >= len(m.Path.Segments) {
+			return "", false
+		}
+		return m.Path.Segments[i], true
+	case strings.HasPrefix(name, "segment_id["):
+		i, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "segment_id["), "]"))
+		if m.SegmentID == nil || m.SegmentID.Index != i {
+			return "", true
+		}
+		return m.SegmentID.Value, true
+	case name == "segment_id_value":
+		if m.SegmentID == nil {
+			return "", true
+		}
+		return m.SegmentID.Value, true
+	case name == "segment_id_index":
+		if m.SegmentID == nil {
+			return "", true
+		}
+		return strconv.Itoa(m.SegmentID.Index), true
+	case name == "has_segment_id":
+		return strconv.FormatBool(m.SegmentID != nil), true
+	}
+	return "", false
+}
+
+// bindParamName returns the bind parameter name of a placeholder, e.g.
+// segment_1 for {segments[1]} and param_region for {params.region}
+func bindParamName(placeholder string) string {
+	if strings.HasPrefix(placeholder, "params.") {
+		return "param_" + strings.TrimPrefix(placeholder, "params.")
+	}
+	return strings.NewReplacer("segments[", "segment_", "segment_id[", "segment_id_", "]", "").Replace(placeholder)
+}
+
+// checkRenderedValue rejects a value that could end the literal or statement
+// it is substituted into
+func checkRenderedValue(placeholder, value string) error {
+	for _, unsafe := range unsafeRenderedValues {
+		if strings.Contains(value, unsafe) {
+			return fmt.Errorf("value of {%s} contains %q", placeholder, unsafe)
+		}
+	}
+	return nil
+}
+
+// escapePyformat doubles every % that is not part of a %(name)s marker
+func escapePyformat(query string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		if query[i] == '%' && !strings.HasPrefix(query[i:], "%(") {
+			b.WriteString("%%")
+			continue
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
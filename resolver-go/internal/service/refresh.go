@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// InvalidatePath drops the cached resolve results, listed versions and
+// fetched data of path and of every path beneath it, returning how many
+// entries it dropped
+func (s *MonikerService) InvalidatePath(path string) int {
+	path = strings.Trim(path, "/")
+	var prefixes []string
+	for _, ns := range []string{resolveCachePrefix, versionsCachePrefix, dataCachePrefix} {
+		prefixes = append(prefixes, ns+path+":", ns+path+"/")
+	}
+	return s.cache.DeletePrefix(prefixes...)
+}
+
+// InvalidateData drops all cached fetched data, returning how many entries
+// it dropped. A catalog reload calls it: the data of the replaced catalog is
+// never served again, and would otherwise stay until its TTL passes.
+func (s *MonikerService) InvalidateData() int {
+	return s.cache.DeletePrefix(dataCachePrefix)
+}
+
+// InvalidateAll empties the shared cache, returning how many entries it
+// dropped
+func (s *MonikerService) InvalidateAll() int {
+	return s.cache.Clear()
+}
+
+// WarmResult reports a cache warm: the binding paths whose data was cached
+// again, and why the others could not be
+type WarmResult struct {
+	Warmed []string          `json:"warmed"`
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+// WarmPath caches again the bindings at or beneath path ("" is the whole
+// catalog) whose cache config sets refresh_on_startup: their resolve result
+// for an anonymous caller and, when they declare versions and their adapter
+// lists them, their versions. Bindings are warmed in path order until ctx is
+// done.
+func (s *MonikerService) WarmPath(ctx context.Context, path string) *WarmResult {
+	path = strings.Trim(path, "/")
+	var paths []string
+	versioned := make(map[string]bool)
+	for _, node := range s.catalog.AllNodes() {
+		if path != "" && node.Path != path && !strings.HasPrefix(node.Path, path+"/") {
+			continue
+		}
+		if b := node.SourceBinding; b != nil && b.Cache != nil && b.Cache.RefreshOnStartup {
+			paths = append(paths, node.Path)
+			versioned[node.Path] = b.Config[catalog.VersionsQueryKey] != nil || b.Config[catalog.VersionColumnKey] != nil
+		}
+	}
+	sort.Strings(paths)
+
+	result := &WarmResult{Warmed: make([]string, 0, len(paths))}
+	fail := func(p string, err error) {
+		if result.Failed == nil {
+			result.Failed = make(map[string]string)
+		}
+		result.Failed[p] = err.Error()
+	}
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			fail(p, err)
+			continue
+		}
+		if _, err := s.Resolve(ctx, p, nil); err != nil {
+			fail(p, err)
+			continue
+		}
+		if versioned[p] {
+			var unsupported *VersionsNotSupportedError
+			if _, err := s.Versions(ctx, p, nil); err != nil && !errors.As(err, &unsupported) {
+				fail(p, err)
+				continue
+			}
+		}
+		result.Warmed = append(result.Warmed, p)
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretScheme prefixes a binding config value that refers to a secret rather
+// than holding it: secret://<provider>/<key>, e.g. secret://env/SNOWFLAKE_PASSWORD
+const SecretScheme = "secret://"
+
+// SecretMask replaces secret values for callers who may not see them
+const SecretMask = "***"
+
+// CapabilityRevealSecrets lets a caller see resolved secret values in the
+// connection map of a resolve result
+const CapabilityRevealSecrets = "reveal_secrets"
+
+// SecretProvider looks up secret values by key
+type SecretProvider interface {
+	Secret(key string) (string, error)
+}
+
+// EnvSecretProvider reads secrets from environment variables: the key is the
+// variable name
+type EnvSecretProvider struct{}
+
+// Secret implements SecretProvider
+func (EnvSecretProvider) Secret(key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("environment variable is not set")
+	}
+	return value, nil
+}
+
+// DefaultSecretFileDir is where the file provider reads secrets when
+// secrets.file_dir is not set, the mount point of Docker and Kubernetes secrets
+const DefaultSecretFileDir = "/run/secrets"
+
+// FileSecretProvider reads secrets from files under Dir, e.g. mounted
+// Kubernetes or Docker secrets. The key is a path relative to Dir; absolute
+// keys and keys that climb out of Dir are refused, as is every key when Dir
+// is empty. Trailing newlines are trimmed.
+type FileSecretProvider struct {
+	Dir string
+}
+
+// Secret implements SecretProvider
+func (p FileSecretProvider) Secret(key string) (string, error) {
+	if p.Dir == "" {
+		return "", fmt.Errorf("no directory is configured for secret files")
+	}
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("secret file must be a relative path inside the secrets directory")
+	}
+	path := filepath.Join(p.Dir, filepath.Clean(key))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("secret file does not exist")
+		}
+		return "", fmt.Errorf("read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// SecretResolver resolves secret:// references through the provider named
+// by the reference
+type SecretResolver struct {
+	mu        sync.RWMutex
+	providers map[string]SecretProvider
+}
+
+// NewSecretResolver creates a resolver with the env provider and a file
+// provider reading under fileDir. Other providers, such as a vault, are
+// registered with SetProvider.
+func NewSecretResolver(fileDir string) *SecretResolver {
+	return &SecretResolver{
+		providers: map[string]SecretProvider{
+			"env":  EnvSecretProvider{},
+			"file": FileSecretProvider{Dir: fileDir},
+		},
+	}
+}
+
+// SetProvider registers the provider for a name, replacing any existing one
+func (r *SecretResolver) SetProvider(name string, p SecretProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = p
+}
+
+// IsSecretRef reports whether a config value is a secret:// reference
+func IsSecretRef(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, SecretScheme)
+}
+
+// Resolve returns the value of a secret:// reference. Errors name the
+// reference, never a value.
+func (r *SecretResolver) Resolve(ref string) (string, error) {
+	name, key, ok := strings.Cut(strings.TrimPrefix(ref, SecretScheme), "/")
+	if !ok || name == "" || key == "" {
+		return "", fmt.Errorf("secret reference %q must be %s<provider>/<key>", ref, SecretScheme)
+	}
+	r.mu.RLock()
+	provider, ok := r.providers[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("secret %q: unknown provider %q", ref, name)
+	}
+	value, err := provider.Secret(key)
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %v", ref, err)
+	}
+	return value, nil
+}
+
+// resolveSecrets returns a copy of config with every secret reference,
+// including those nested in maps and lists, replaced by its value when
+// reveal is true and by SecretMask otherwise. field names the config key
+// for errors.
+func (r *SecretResolver) resolveSecrets(field string, v interface{}, reveal bool) (interface{}, error) {
+	switch value := v.(type) {
+	case string:
+		if !IsSecretRef(value) {
+			return value, nil
+		}
+		if !reveal {
+			return SecretMask, nil
+		}
+		secret, err := r.Resolve(value)
+		if err != nil {
+			return nil, fmt.Errorf("connection %s: %w", field, err)
+		}
+		return secret, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make(map[string]interface{}, len(value))
+		for _, k := range keys {
+			resolved, err := r.resolveSecrets(field+"."+k, value[k], reveal)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			resolved, err := r.resolveSecrets(fmt.Sprintf("%s[%d]", field, i), item, reveal)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// MaskedConfig returns a copy of a binding config fit to display: secret
+// references, including those nested in maps and lists, and the values of
+// credential keys such as password are replaced by SecretMask
+func MaskedConfig(config map[string]interface{}) map[string]interface{} {
+	masked, _ := maskConfig("", config).(map[string]interface{})
+	return masked
+}
+
+func maskConfig(key string, v interface{}) interface{} {
+	if dsnSecretKeys[key] && v != nil {
+		return SecretMask
+	}
+	switch value := v.(type) {
+	case string:
+		if IsSecretRef(value) {
+			return SecretMask
+		}
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			out[k] = maskConfig(k, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = maskConfig(key, item)
+		}
+		return out
+	}
+	return v
+}
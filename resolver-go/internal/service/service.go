@@ -3,128 +3,490 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/cache"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/metrics"
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
 )
 
 const maxSuccessorDepth = 5
 
+// resolveCachePrefix namespaces resolve results in the shared cache
+const resolveCachePrefix = "resolve:"
+
+// CacheNamespaces are the namespaces the service keeps in the shared cache:
+// resolve results, the versions adapters list and the data they fetch
+var CacheNamespaces = []string{
+	strings.TrimSuffix(resolveCachePrefix, ":"),
+	strings.TrimSuffix(versionsCachePrefix, ":"),
+	strings.TrimSuffix(dataCachePrefix, ":"),
+}
+
 // MonikerService provides moniker resolution
 type MonikerService struct {
-	catalog *catalog.Registry
-	cache   *cache.InMemory
-	config  *config.Config
+	catalog  *catalog.Registry
+	cache    *cache.InMemory
+	config   *config.Config
+	secrets  *SecretResolver
+	dsn      *DSNBuilders
+	adapters *adapters.Registry
+	auditor  AccessAuditor
+	metrics  *metrics.Registry // Nil when resolution metrics are off; see SetMetrics
+	now      func() time.Time  // See SetClock
+
+	nsMu       sync.RWMutex
+	namespaces map[string]*catalog.Registry // Overlay catalogs by namespace
 }
 
 // NewMonikerService creates a new moniker service
 func NewMonikerService(reg *catalog.Registry, cacheInst *cache.InMemory, cfg *config.Config) *MonikerService {
+	secretDir := DefaultSecretFileDir
+	if cfg != nil && cfg.Secrets.FileDir != "" {
+		secretDir = cfg.Secrets.FileDir
+	}
 	return &MonikerService{
-		catalog: reg,
-		cache:   cacheInst,
-		config:  cfg,
+		catalog:    reg,
+		cache:      cacheInst,
+		config:     cfg,
+		secrets:    NewSecretResolver(secretDir),
+		dsn:        NewDSNBuilders(),
+		adapters:   adapters.NewRegistry(),
+		auditor:    NoOpAuditor{},
+		namespaces: make(map[string]*catalog.Registry),
+	}
+}
+
+// bypassesAllowedHours reports whether a caller with roles may resolve
+// outside access_policy.allowed_hours, per auth.allowed_hours_bypass_roles
+func (s *MonikerService) bypassesAllowedHours(roles []string) bool {
+	if s.config == nil {
+		return false
+	}
+	for _, bypass := range s.config.Auth.AllowedHoursBypassRoles {
+		for _, role := range roles {
+			if role == bypass {
+				return true
+			}
+		}
 	}
+	return false
 }
 
-// Resolve resolves a moniker to its source binding
+// RolesHeader returns the request header carrying the caller's roles,
+// auth.roles_header or DefaultRolesHeader
+func (s *MonikerService) RolesHeader() string {
+	if s.config != nil && s.config.Auth.RolesHeader != "" {
+		return s.config.Auth.RolesHeader
+	}
+	return DefaultRolesHeader
+}
+
+// Secrets returns the resolver for secret:// references in binding config,
+// for registering additional providers
+func (s *MonikerService) Secrets() *SecretResolver {
+	return s.secrets
+}
+
+// DSNBuilders returns the connection URI builders, for registering builders
+// of additional source types
+func (s *MonikerService) DSNBuilders() *DSNBuilders {
+	return s.dsn
+}
+
+// hasCapability reports whether the caller holds a capability, either on its
+// identity or granted to its user ID by auth.capabilities in the config. A
+// user ID grant only counts for a verified identity (JWT or API key): the
+// X-User-ID header is caller-supplied and proves nothing.
+func (s *MonikerService) hasCapability(caller *CallerIdentity, capability string) bool {
+	if caller == nil {
+		return false
+	}
+	if caller.HasCapability(capability) {
+		return true
+	}
+	if s.config == nil || !caller.Verified() {
+		return false
+	}
+	for _, userID := range s.config.Auth.Capabilities[capability] {
+		if userID == caller.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve resolves a moniker to its source binding. When the cache is
+// enabled, results are cached by canonical moniker for
+// cache.resolve_ttl_seconds; errors are not cached, and any catalog change
+// invalidates every entry. Results with revealed secrets are never cached,
+// so a rotated secret is picked up on the next call.
 func (s *MonikerService) Resolve(ctx context.Context, monikerStr string, caller *CallerIdentity) (*ResolveResult, error) {
-	// Parse moniker
+	start := time.Now()
+	m, err := parseMoniker(monikerStr)
+	var result *ResolveResult
+	if err == nil {
+		result, err = s.resolveCached(ctx, m, caller)
+	}
+	s.observeResolve(m, result, err, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	s.auditResolved(m, result, caller)
+	return result, nil
+}
+
+// SetMetrics records every Resolve in reg; nil turns metrics off
+func (s *MonikerService) SetMetrics(reg *metrics.Registry) {
+	s.metrics = reg
+}
+
+// observeResolve records a resolution in the metrics registry, if any
+func (s *MonikerService) observeResolve(m *moniker.Moniker, result *ResolveResult, err error, elapsed time.Duration) {
+	if s.metrics == nil {
+		return
+	}
+	o := metrics.Observation{Path: metrics.InvalidPath, Duration: elapsed, ErrorType: errorType(err)}
+	switch {
+	case result != nil:
+		o.Path = result.BindingPath
+		o.SourceType = result.Source.SourceType
+		o.Redirected = result.RedirectedFrom != nil
+	case m != nil:
+		// A refused moniker is labelled by the binding that refused it
+		o.Path = metrics.NotFoundPath
+		if binding, bindingPath := s.catalog.FindSourceBinding(m.CanonicalPath()); binding != nil {
+			o.Path = bindingPath
+		}
+	}
+	s.metrics.Observe(o)
+}
+
+// errorType classifies a resolve error for metrics, "" for success
+func errorType(err error) string {
+	switch err.(type) {
+	case nil:
+		return ""
+	case *NotFoundError:
+		return "not_found"
+	case *SubResourceNotFoundError:
+		return "sub_resource_not_found"
+	case *AccessDeniedError:
+		return metrics.DeniedErrorType
+	case *SunsetError:
+		return "sunset"
+	case *GoneError:
+		return "gone"
+	case *ResolutionError:
+		return "resolution"
+	}
+	return "internal"
+}
+
+// resolveCached resolves m through the resolve cache, when it applies
+func (s *MonikerService) resolveCached(ctx context.Context, m *moniker.Moniker, caller *CallerIdentity) (*ResolveResult, error) {
+	if !s.resolveCacheEnabled() || s.hasCapability(caller, CapabilityRevealSecrets) {
+		return s.resolve(ctx, m, caller, nil)
+	}
+	// The key is built before resolving, so a result computed while the
+	// catalog changes is stored under the old generation and never served
+	key := s.resolveCacheKey(m, caller)
+	if cached, ok := s.cache.Get(key); ok {
+		return cached.(*ResolveResult), nil
+	}
+	result, err := s.resolve(ctx, m, caller, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.SetWithTTL(key, result, s.resolveCacheTTL())
+	return result, nil
+}
+
+// parseMoniker parses a moniker string, reporting syntax errors as
+// resolution errors
+func parseMoniker(monikerStr string) (*moniker.Moniker, error) {
 	m, err := moniker.ParseMoniker(monikerStr)
 	if err != nil {
 		return nil, &ResolutionError{Message: fmt.Sprintf("Invalid moniker: %v", err)}
 	}
+	return m, nil
+}
+
+// resolveCacheEnabled reports whether resolve results are cached
+func (s *MonikerService) resolveCacheEnabled() bool {
+	return s.cache != nil && s.config != nil && s.config.Cache.Enabled
+}
+
+// resolveCacheKey identifies a resolve result in the cache. It starts with
+// the moniker's path, so InvalidatePath can find it, and mixes in the
+// generation of every catalog the result can depend on, the caller's roles
+// (access policies may allow only some), for user@ monikers the caller, and
+// the current UTC hour, which date@ versions and allowed_hours depend on.
+func (s *MonikerService) resolveCacheKey(m *moniker.Moniker, caller *CallerIdentity) string {
+	key := fmt.Sprintf("%s%s:%d", resolveCachePrefix, m.CanonicalPath(), s.catalog.Generation())
+	if m.Namespace != nil {
+		if reg := s.Namespace(*m.Namespace); reg != nil {
+			key += fmt.Sprintf(".%d", reg.Generation())
+		}
+		if *m.Namespace == UserNamespace && caller != nil {
+			key += fmt.Sprintf(":%q", caller.UserID)
+		}
+	}
+	if caller != nil && len(caller.Roles) > 0 {
+		roles := append([]string{}, caller.Roles...)
+		sort.Strings(roles)
+		key += fmt.Sprintf(":roles=%q", strings.Join(roles, ","))
+	}
+	key += ":" + s.clock().UTC().Format("2006010215")
+	return key + ":" + m.String()
+}
 
-	// Get the path
+// resolveCacheTTL returns how long a resolve result is cached
+func (s *MonikerService) resolveCacheTTL() time.Duration {
+	if ttl := s.config.Cache.ResolveTTLSeconds; ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return time.Duration(s.config.Cache.DefaultTTLSeconds) * time.Second
+}
+
+// resolve resolves a parsed moniker, bypassing the cache. A namespaced
+// moniker is looked up in its namespace's catalog first and falls back to the
+// default catalog; the result names the namespace that supplied the binding.
+// derived lists the derived bindings whose inputs are being resolved, outermost
+// first, to detect cycles.
+func (s *MonikerService) resolve(ctx context.Context, m *moniker.Moniker, caller *CallerIdentity, derived []string) (*ResolveResult, error) {
 	path := m.CanonicalPath()
+	if m.Namespace == nil {
+		return s.resolveIn(ctx, s.catalog, m, path, caller, derived)
+	}
+
+	reg, lookupPath, err := s.namespaceCatalog(*m.Namespace, path, caller)
+	if err != nil {
+		return nil, err
+	}
+	binding, _, checked := findSourceBinding(ctx, reg, m, lookupPath)
+	if trace := traceFrom(ctx, m); trace != nil {
+		trace.NamespaceLookup = checked
+		trace.NamespaceFallback = binding == nil
+	}
+	if binding == nil {
+		return s.resolveIn(ctx, s.catalog, m, path, caller, derived)
+	}
+	result, err := s.resolveIn(ctx, reg, m, lookupPath, caller, derived)
+	if err != nil {
+		return nil, err
+	}
+	// Report user@ paths as the caller wrote them, without the user scope
+	if scope := strings.TrimSuffix(lookupPath, path); scope != "" {
+		unscope := func(p string) string { return strings.TrimPrefix(p, scope) }
+		result.Path = unscope(result.Path)
+		result.BindingPath = unscope(result.BindingPath)
+		if result.RedirectedFrom != nil {
+			from := unscope(*result.RedirectedFrom)
+			result.RedirectedFrom = &from
+		}
+		for i, p := range result.SuccessorChain {
+			result.SuccessorChain[i] = unscope(p)
+		}
+		for i := range result.Warnings {
+			result.Warnings[i].Path = unscope(result.Warnings[i].Path)
+		}
+	}
+	result.Namespace = *m.Namespace
+	return result, nil
+}
+
+// resolveIn resolves path against one catalog
+func (s *MonikerService) resolveIn(ctx context.Context, reg *catalog.Registry, m *moniker.Moniker, path string, caller *CallerIdentity, derived []string) (*ResolveResult, error) {
+	// An archived node is gone; an ancestor's binding must not answer for it
+	if node := reg.Get(path); node != nil && node.Status == catalog.NodeStatusArchived {
+		err := goneError(reg, node)
+		s.auditGone(err, m, caller)
+		return nil, err
+	}
 
 	// Find source binding (walk hierarchy if needed)
-	binding, bindingPath := s.catalog.FindSourceBinding(path)
+	binding, bindingPath, checked := findSourceBinding(ctx, reg, m, path)
+	trace := traceFrom(ctx, m)
+	if trace != nil {
+		trace.BindingLookup = checked
+	}
 	if binding == nil {
 		return nil, &NotFoundError{Path: path}
 	}
 
+	// A deprecated node stops resolving at its sunset deadline, whether it
+	// is served as-is or redirects to a successor
+	node := reg.Get(bindingPath)
+	if err := s.checkSunset(node); err != nil {
+		s.auditGone(err, m, caller)
+		return nil, err
+	}
+
 	// Check for successor redirect
-	node := s.catalog.Get(bindingPath)
 	if node != nil && node.Status == catalog.NodeStatusDeprecated && node.Successor != nil {
-		// Follow successor chain (with depth limit)
-		successorPath := *node.Successor
-		for depth := 0; depth < maxSuccessorDepth; depth++ {
-			successorNode := s.catalog.Get(successorPath)
-			if successorNode == nil {
-				break
+		chain, err := reg.SuccessorChain(bindingPath)
+		if err != nil {
+			if issue, ok := err.(*catalog.SuccessorIssue); ok && issue.Kind == catalog.SuccessorIssueCycle {
+				return nil, &ResolutionError{Message: issue.Message}
+			}
+			// Dangling successor: keep serving the deprecated node itself
+			chain = nil
+		}
+		if len(chain) > 1 && len(chain)-1 <= maxSuccessorDepth {
+			successorPath := chain[len(chain)-1]
+			successorNode := reg.Get(successorPath)
+			binding, bindingPath, checked = findSourceBinding(ctx, reg, m, successorPath)
+			if trace != nil {
+				trace.SuccessorHops, trace.SuccessorLookup = chain, checked
 			}
-			if successorNode.Status != catalog.NodeStatusDeprecated || successorNode.Successor == nil {
-				// Found non-deprecated successor
-				binding, bindingPath = s.catalog.FindSourceBinding(successorPath)
-				if binding != nil {
-					// Redirect successful
-					redirectFrom := path
-					path = successorPath
-					node = successorNode
-
-					result := s.buildResolveResult(m, path, binding, bindingPath, node)
-					result.RedirectedFrom = &redirectFrom
-					return result, nil
+			if binding != nil {
+				// Redirect successful
+				redirectFrom := path
+				deprecated := node
+				path = successorPath
+				node = successorNode
+
+				// The successor's access policy applies as if it were
+				// resolved directly
+				warnings, err := s.checkAccessPolicy(ctx, node, m, bindingPath, caller)
+				if err != nil {
+					return nil, err
+				}
+				result, err := s.buildResolveResult(ctx, reg, m, path, binding, bindingPath, node, caller, derived)
+				if err != nil {
+					return nil, err
 				}
-				break
+				result.RedirectedFrom = &redirectFrom
+				result.SuccessorChain = chain
+				result.Warnings = append([]ResolveWarning{*deprecationWarning(deprecated, s.clock())}, result.Warnings...)
+				result.Warnings = append(result.Warnings, warnings...)
+				return result, nil
 			}
-			successorPath = *successorNode.Successor
+			// Successor has no resolvable binding; fall back to the original node
+			binding, bindingPath = reg.FindSourceBinding(path)
 		}
 	}
 
 	// Validate access policy if present
-	if node != nil && node.AccessPolicy != nil {
-		segments := m.Path.Segments
-		allowed, message, estimatedRows := node.AccessPolicy.Validate(segments)
-		if !allowed {
-			return nil, &AccessDeniedError{
-				Message:       *message,
-				EstimatedRows: &estimatedRows,
-			}
-		}
+	warnings, err := s.checkAccessPolicy(ctx, node, m, bindingPath, caller)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build result
-	result := s.buildResolveResult(m, path, binding, bindingPath, node)
+	result, err := s.buildResolveResult(ctx, reg, m, path, binding, bindingPath, node, caller, derived)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(result.Warnings, warnings...)
 	return result, nil
 }
 
-func (s *MonikerService) buildResolveResult(m *moniker.Moniker, path string, binding *catalog.SourceBinding, bindingPath string, node *catalog.CatalogNode) *ResolveResult {
-	// Resolve ownership
-	ownership := s.catalog.ResolveOwnership(path)
-
-	// Build resolved source
-	source := &ResolvedSource{
-		SourceType: string(binding.SourceType),
-		Connection: make(map[string]interface{}),
-		Params:     make(map[string]interface{}),
-		ReadOnly:   binding.ReadOnly,
+// callerRoles returns the roles of caller, nil for an anonymous one
+func callerRoles(caller *CallerIdentity) []string {
+	if caller == nil {
+		return nil
 	}
+	return caller.Roles
+}
+
+// policyDecision is an access policy's decision for a caller resolving a
+// moniker. Resolve enforces it and Estimate reports it.
+type policyDecision struct {
+	Decision      string  // One of the Estimate decisions
+	Rule          string  // The rule behind any decision but allowed
+	Message       *string // Why the rule decided
+	Warning       *string // The max_rows_warn message of an allowed moniker, kept when confirmation is required
+	EstimatedRows int
+	CurrentHour   int  // UTC hour allowed_hours was checked at
+	HoursBypassed bool // Outside allowed_hours, but the caller's roles bypass them
+}
 
-	// Copy config to connection (excluding query)
-	for k, v := range binding.Config {
-		if k != "query" {
-			source.Connection[k] = v
+// decidePolicy evaluates ap for caller resolving m: the allowed roles, the
+// allowed hours unless the caller's roles bypass them, the row estimate, then
+// require_confirmation_above, which is reported but not enforced
+func (s *MonikerService) decidePolicy(ctx context.Context, ap *catalog.AccessPolicy, m *moniker.Moniker, caller *CallerIdentity) policyDecision {
+	eval := evaluatePolicy(ctx, ap, m)
+	d := policyDecision{Decision: EstimateAllowed, EstimatedRows: eval.EstimatedRows}
+	roles := callerRoles(caller)
+	if allowed, message := ap.CheckRoles(roles); !allowed {
+		d.Decision, d.Rule, d.Message = EstimateBlocked, "allowed_roles", message
+		return d
+	}
+	now := s.clock()
+	d.CurrentHour = now.UTC().Hour()
+	if allowed, message := ap.CheckHours(now); !allowed {
+		if !s.bypassesAllowedHours(roles) {
+			d.Decision, d.Rule, d.Message = EstimateBlocked, "allowed_hours", message
+			return d
 		}
+		d.HoursBypassed = true
+	}
+	if !eval.Allowed {
+		d.Decision, d.Rule, d.Message = EstimateBlocked, eval.Rule, eval.Message
+		return d
 	}
+	d.Warning = eval.Message
+	if threshold := ap.RequireConfirmationAbove; threshold != nil && eval.EstimatedRows > *threshold {
+		msg := fmt.Sprintf("Estimated %d rows exceeds require_confirmation_above of %d", eval.EstimatedRows, *threshold)
+		d.Decision, d.Rule, d.Message = EstimateConfirmationRequired, "require_confirmation_above", &msg
+		return d
+	}
+	if eval.Message != nil {
+		d.Decision, d.Rule, d.Message = EstimateWarned, eval.Rule, eval.Message
+	}
+	return d
+}
 
-	// Get query from config
-	if queryVal, ok := binding.Config["query"]; ok {
-		if queryStr, ok := queryVal.(string); ok {
-			// Simple placeholder substitution
-			formattedQuery := s.formatQuery(queryStr, m)
-			source.Query = &formattedQuery
+// checkAccessPolicy enforces node's access policy for caller resolving m,
+// as decidePolicy decides it, and records the checks in an explained
+// resolve's trace. Denials are audited against bindingPath. It returns the
+// warnings of an estimate over max_rows_warn.
+func (s *MonikerService) checkAccessPolicy(ctx context.Context, node *catalog.CatalogNode, m *moniker.Moniker, bindingPath string, caller *CallerIdentity) ([]ResolveWarning, error) {
+	if node == nil || node.AccessPolicy == nil {
+		return nil, nil
+	}
+	d := s.decidePolicy(ctx, node.AccessPolicy, m, caller)
+	if trace := traceFrom(ctx, m); trace != nil {
+		trace.AccessPolicy = traceAccessPolicy(node, d)
+	}
+	if d.Decision == EstimateBlocked {
+		denied := &AccessDeniedError{Message: *d.Message}
+		var estimatedRows *int
+		switch d.Rule {
+		case "allowed_roles":
+		case "allowed_hours":
+			hour := d.CurrentHour
+			denied.AllowedHours = node.AccessPolicy.AllowedHours
+			denied.CurrentHour = &hour
+		default:
+			estimatedRows = &d.EstimatedRows
+			denied.EstimatedRows = estimatedRows
 		}
+		s.audit(DecisionDenied, d.Rule, *d.Message, m, bindingPath, caller, estimatedRows)
+		return nil, denied
 	}
-
-	// Set schema if present
-	if binding.Schema != nil {
-		source.Schema = binding.Schema
+	if d.Warning != nil {
+		return []ResolveWarning{{Kind: WarningLargeQuery, Message: *d.Warning, EstimatedRows: &d.EstimatedRows}}, nil
 	}
+	return nil, nil
+}
 
+// buildResolveResult assembles the result for a binding. secret:// references
+// in the connection map are resolved for callers with CapabilityRevealSecrets
+// and masked for everyone else. When the binding defines sub-resources, the
+// final sub-path segment selects one and its entry overrides the config.
+// Derived bindings resolve their inputs into a plan. A deprecated node adds a
+// warning. For Check, the result stops once params and version are bound
+// and the query's placeholders are known to have values.
+func (s *MonikerService) buildResolveResult(ctx context.Context, reg *catalog.Registry, m *moniker.Moniker, path string, binding *catalog.SourceBinding, bindingPath string, node *catalog.CatalogNode, caller *CallerIdentity, derived []string) (*ResolveResult, error) {
 	// Calculate sub-path if binding is at ancestor
 	var subPath *string
 	if bindingPath != path {
@@ -135,67 +497,233 @@ func (s *MonikerService) buildResolveResult(m *moniker.Moniker, path string, bin
 		}
 	}
 
-	return &ResolveResult{
-		Moniker:     m.String(),
-		Path:        path,
-		Source:      source,
-		Ownership:   ownership,
-		Node:        node,
-		BindingPath: bindingPath,
-		SubPath:     subPath,
+	config := binding.Config
+	var subResource *string
+	if subPath != nil && binding.SubResources() != nil {
+		name := *subPath
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if matched, entry, ok := binding.SubResource(name); ok {
+			config = make(map[string]interface{}, len(binding.Config)+len(entry))
+			for k, v := range binding.Config {
+				config[k] = v
+			}
+			for k, v := range entry {
+				config[k] = v
+			}
+			subResource = &matched
+		} else if strings.Contains(name, ".") {
+			// Dotted final segments below a binding with sub-resources name one
+			return nil, &SubResourceNotFoundError{Path: path, SubResource: name, Available: binding.SubResourceNames()}
+		}
 	}
-}
 
-// formatQuery performs basic placeholder substitution
-func (s *MonikerService) formatQuery(query string, m *moniker.Moniker) string {
-	result := query
+	// Build resolved source
+	source := &ResolvedSource{
+		SourceType: string(binding.SourceType),
+		Connection: make(map[string]interface{}),
+		ReadOnly:   binding.ReadOnly,
+		Cache:      binding.Cache,
+	}
 
-	// Replace {segments[N]} placeholders
-	for i, seg := range m.Path.Segments {
-		placeholder := fmt.Sprintf("{segments[%d]}", i)
-		result = strings.ReplaceAll(result, placeholder, seg)
+	params, err := bindQueryParams(path, config, m)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.resolveVersion(path, config, m)
+	if err != nil {
+		return nil, err
+	}
+	source.Version = version
+	if checking(ctx) {
+		if query, ok := config["query"].(string); ok {
+			render := s.config != nil && s.config.Query.RenderedQuery
+			if err := checkQuery(path, binding.SourceType, query, m, params, version, render); err != nil {
+				return nil, err
+			}
+		}
+		result := &ResolveResult{
+			Moniker:        m.String(),
+			Path:           path,
+			Source:         source,
+			Node:           node,
+			BindingPath:    bindingPath,
+			SubPath:        subPath,
+			SubResource:    subResource,
+			CatalogVersion: reg.Version(),
+		}
+		if w := deprecationWarning(node, s.clock()); w != nil {
+			result.Warnings = append(result.Warnings, *w)
+		}
+		return result, nil
 	}
 
-	// Replace {segment_id_value} and {has_segment_id}
-	if m.SegmentID != nil {
-		result = strings.ReplaceAll(result, "{segment_id_value}", m.SegmentID.Value)
-		result = strings.ReplaceAll(result, "{segment_id_index}", fmt.Sprintf("%d", m.SegmentID.Index))
-		result = strings.ReplaceAll(result, "{has_segment_id}", "true")
-		// Replace {segment_id[N]} for the specific segment
-		placeholder := fmt.Sprintf("{segment_id[%d]}", m.SegmentID.Index)
-		result = strings.ReplaceAll(result, placeholder, m.SegmentID.Value)
-	} else {
-		result = strings.ReplaceAll(result, "{segment_id_value}", "")
-		result = strings.ReplaceAll(result, "{segment_id_index}", "")
-		result = strings.ReplaceAll(result, "{has_segment_id}", "false")
+	versionWarning := s.resolveLatest(ctx, version, &versionSource{
+		m:           m,
+		path:        path,
+		bindingPath: bindingPath,
+		segments:    segmentsBelowBinding(subPath, subResource),
+		binding:     binding,
+		config:      config,
+		params:      params,
+	})
+
+	if binding.SourceType == catalog.SourceTypeDerived {
+		plan, err := s.resolveDerived(ctx, m, bindingPath, config, caller, derived)
+		if err != nil {
+			return nil, err
+		}
+		source.Derived = plan
 	}
 
-	return result
+	// Copy config to connection (excluding query and the keys read above),
+	// in key order so a missing secret is always reported the same way
+	reveal := s.hasCapability(caller, CapabilityRevealSecrets)
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := config[k]
+		if k == "query" || k == catalog.VersionsQueryKey || k == catalog.SubResourcesKey || k == catalog.AllowedParamsKey || k == catalog.FrequenciesKey {
+			continue
+		}
+		if source.Derived != nil && (k == catalog.DerivedInputsKey || k == catalog.DerivedExpressionKey || k == catalog.DerivedTransformKey) {
+			continue
+		}
+		value, err := s.secrets.resolveSecrets(k, v, reveal)
+		if err != nil {
+			return nil, &ResolutionError{Message: err.Error()}
+		}
+		source.Connection[k] = value
+	}
+	dsn, dsnErr := s.dsn.build(binding.SourceType, source.Connection, reveal)
+	source.DSN = dsn
+
+	// Get query from config
+	var placeholders map[string]string
+	if queryVal, ok := config["query"]; ok {
+		if queryStr, ok := queryVal.(string); ok {
+			render := s.config != nil && s.config.Query.RenderedQuery
+			bound, err := bindQuery(path, binding.SourceType, queryStr, m, params, version, render)
+			if err != nil {
+				return nil, err
+			}
+			placeholders = queryPlaceholders(queryStr, m, params, version)
+			source.Query = &bound.Query
+			source.ParamStyle = bound.Style
+			source.Params = bound.Params
+			if render && bound.Style != "" {
+				source.RenderedQuery = &bound.Rendered
+			}
+		}
+	}
+
+	// Set schema if present
+	if binding.Schema != nil {
+		source.Schema = binding.Schema
+	}
+
+	// Resolve ownership
+	ownership := reg.ResolveOwnership(path)
+
+	result := &ResolveResult{
+		Moniker:        m.String(),
+		Path:           path,
+		Source:         source,
+		Ownership:      ownership,
+		Node:           node,
+		BindingPath:    bindingPath,
+		SubPath:        subPath,
+		SubResource:    subResource,
+		CatalogVersion: reg.Version(),
+		binding:        binding,
+		config:         config,
+		placeholders:   placeholders,
+	}
+	if node != nil && node.DataSchema != nil {
+		result.schema = node.DataSchema
+	}
+	if bindingNode := reg.Get(bindingPath); bindingNode != nil {
+		if result.schema == nil {
+			result.schema = bindingNode.DataSchema
+		}
+		if bindingNode.AccessPolicy != nil && bindingNode.AccessPolicy.MaxRowsBlock != nil {
+			result.maxRows = *bindingNode.AccessPolicy.MaxRowsBlock
+		}
+	}
+	if w := deprecationWarning(node, s.clock()); w != nil {
+		result.Warnings = append(result.Warnings, *w)
+	}
+	if dsnErr != nil {
+		result.Warnings = append(result.Warnings, ResolveWarning{Kind: WarningConnection, Message: dsnErr.Error(), Path: bindingPath})
+	}
+	if versionWarning != nil {
+		result.Warnings = append(result.Warnings, *versionWarning)
+	}
+	return result, nil
 }
 
 // Describe returns metadata about a path
 func (s *MonikerService) Describe(ctx context.Context, path string) (*DescribeResult, error) {
-	node := s.catalog.Get(path)
-	ownership := s.catalog.ResolveOwnership(path)
+	return s.describeIn(s.catalog, path), nil
+}
+
+// describeIn describes path as reg has it
+func (s *MonikerService) describeIn(reg *catalog.Registry, path string) *DescribeResult {
+	node := reg.Get(path)
+	ownership := reg.ResolveOwnership(path)
 
 	// Check if has source binding
-	binding, _ := s.catalog.FindSourceBinding(path)
+	binding, _ := reg.FindSourceBinding(path)
 	hasBinding := binding != nil
 
 	var sourceType *string
+	var dependencies []DerivedDependency
 	if binding != nil {
 		st := string(binding.SourceType)
 		sourceType = &st
+		if binding.SourceType == catalog.SourceTypeDerived {
+			dependencies = derivedDependencies(binding.Config)
+		}
 	}
 
-	return &DescribeResult{
+	result := &DescribeResult{
 		Node:             node,
 		Ownership:        ownership,
 		Moniker:          fmt.Sprintf("moniker://%s", path),
 		Path:             path,
 		HasSourceBinding: hasBinding,
 		SourceType:       sourceType,
-	}, nil
+		Dependencies:     dependencies,
+		Tags:             reg.ResolveTags(path),
+		SLA:              reg.ResolveSLA(path),
+		DataQuality:      reg.ResolveDataQuality(path),
+	}
+
+	classification, definedAt := reg.ResolveClassification(path)
+	result.Classification = classification
+	if definedAt != "" {
+		result.ClassificationSource = &definedAt
+	}
+
+	// Report where the successor chain eventually leads
+	if node != nil && node.Successor != nil {
+		chain, err := reg.SuccessorChain(path)
+		if len(chain) > 1 {
+			result.SuccessorChain = chain
+			target := chain[len(chain)-1]
+			result.EventualSuccessor = &target
+		}
+		if err != nil {
+			msg := err.Error()
+			result.SuccessorError = &msg
+		}
+	}
+
+	return result
 }
 
 // List returns children of a path
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
 )
 
@@ -8,32 +12,89 @@ import (
 type ResolvedSource struct {
 	SourceType string                 `json:"source_type"`
 	Connection map[string]interface{} `json:"connection"`
-	Query      *string                `json:"query,omitempty"`
-	Params     map[string]interface{} `json:"params,omitempty"`
-	Schema     map[string]interface{} `json:"schema,omitempty"`
-	ReadOnly   bool                   `json:"read_only"`
+	// DSN is the connection URI built from Connection for the source type;
+	// credentials are only included for callers who may reveal secrets
+	DSN        string       `json:"dsn,omitempty"`
+	Query      *string      `json:"query,omitempty"`
+	ParamStyle string       `json:"param_style,omitempty"` // Bind marker style of Query: qmark, named or pyformat
+	Params     []QueryParam `json:"params,omitempty"`      // Bind parameters of Query, in marker order
+	// RenderedQuery is Query with values substituted, when query.rendered_query is enabled
+	RenderedQuery *string `json:"rendered_query,omitempty"`
+
+	// Version is the date range and frequency the moniker asked for
+	Version *VersionInfo `json:"version,omitempty"`
+
+	// Derived is the computation plan of a derived source
+	Derived  *DerivedPlan           `json:"derived,omitempty"`
+	Schema   map[string]interface{} `json:"schema,omitempty"`
+	ReadOnly bool                   `json:"read_only"`
+
+	// Cache is the binding's query cache config; clients can use it to judge staleness
+	Cache *catalog.QueryCacheConfig `json:"cache,omitempty"`
 }
 
 // ResolveResult represents the full resolution result
 type ResolveResult struct {
-	Moniker        string                       `json:"moniker"`
-	Path           string                       `json:"path"`
-	Source         *ResolvedSource              `json:"source"`
-	Ownership      *catalog.ResolvedOwnership   `json:"ownership"`
-	Node           *catalog.CatalogNode         `json:"node,omitempty"`
-	BindingPath    string                       `json:"binding_path"`
-	SubPath        *string                      `json:"sub_path,omitempty"`
-	RedirectedFrom *string                      `json:"redirected_from,omitempty"`
+	Moniker        string                     `json:"moniker"`
+	Path           string                     `json:"path"`
+	Source         *ResolvedSource            `json:"source"`
+	Ownership      *catalog.ResolvedOwnership `json:"ownership"`
+	Node           *catalog.CatalogNode       `json:"node,omitempty"`
+	BindingPath    string                     `json:"binding_path"`
+	SubPath        *string                    `json:"sub_path,omitempty"`
+	SubResource    *string                    `json:"sub_resource,omitempty"` // Sub-resource entry applied to the binding
+	RedirectedFrom *string                    `json:"redirected_from,omitempty"`
+	SuccessorChain []string                   `json:"successor_chain,omitempty"`
+
+	// Warnings lists deprecations on the resolution path and access policy
+	// warnings; the result is still usable
+	Warnings []ResolveWarning `json:"warnings,omitempty"`
+
+	// Trace explains the resolution, when requested with ResolveOptions.Explain
+	Trace *ResolveTrace `json:"trace,omitempty"`
+
+	// Expanded lists the monikers the ALL segments expanded into, when
+	// requested with ResolveOptions.ExpandAll
+	Expanded *ExpandedResult `json:"expanded,omitempty"`
+
+	// CatalogVersion is the digest of the catalog the result was resolved against
+	CatalogVersion string `json:"catalog_version,omitempty"`
+
+	// Namespace is set when the binding came from a namespace's overlay
+	// catalog rather than the default catalog
+	Namespace string `json:"namespace,omitempty"`
+
+	// What Fetch needs beyond the response: the binding, its effective
+	// config (sub-resource applied, secrets unresolved), the node schema,
+	// the access policy's row cap and the query placeholder values
+	binding      *catalog.SourceBinding
+	config       map[string]interface{}
+	schema       *catalog.DataSchema
+	maxRows      int
+	placeholders map[string]string
 }
 
 // DescribeResult represents metadata about a path
 type DescribeResult struct {
-	Node             *catalog.CatalogNode       `json:"node,omitempty"`
-	Ownership        *catalog.ResolvedOwnership `json:"ownership"`
-	Moniker          string                     `json:"moniker"`
-	Path             string                     `json:"path"`
-	HasSourceBinding bool                       `json:"has_source_binding"`
-	SourceType       *string                    `json:"source_type,omitempty"`
+	Node              *catalog.CatalogNode       `json:"node,omitempty"`
+	Ownership         *catalog.ResolvedOwnership `json:"ownership"`
+	Moniker           string                     `json:"moniker"`
+	Path              string                     `json:"path"`
+	HasSourceBinding  bool                       `json:"has_source_binding"`
+	SourceType        *string                    `json:"source_type,omitempty"`
+	SuccessorChain    []string                   `json:"successor_chain,omitempty"`
+	EventualSuccessor *string                    `json:"eventual_successor,omitempty"`
+	SuccessorError    *string                    `json:"successor_error,omitempty"`
+	Dependencies      []DerivedDependency        `json:"dependencies,omitempty"` // Inputs of a derived source
+
+	// Effective classification and tags after hierarchical inheritance
+	Classification       string                  `json:"classification"`
+	ClassificationSource *string                 `json:"classification_source,omitempty"`
+	Tags                 []catalog.TagWithSource `json:"tags"`
+
+	// SLA and data quality merged through the hierarchy (nil when undefined everywhere)
+	SLA         *catalog.ResolvedSLA         `json:"sla,omitempty"`
+	DataQuality *catalog.ResolvedDataQuality `json:"data_quality,omitempty"`
 }
 
 // ListResult represents children of a path
@@ -49,6 +110,50 @@ type CallerIdentity struct {
 	UserID   string  `json:"user_id"`
 	Username *string `json:"username,omitempty"`
 	Source   string  `json:"source"` // "api_key", "jwt", "kerberos", etc.
+
+	// Capabilities granted by the authentication method, e.g. reveal_secrets
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Roles checked against access_policy.allowed_roles
+	Roles []string `json:"roles,omitempty"`
+
+	// Scopes of the API key the caller authenticated with (read, resolve,
+	// admin); nil for callers authenticated otherwise
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// callerKey is the context key of the authenticated caller
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying the authenticated caller
+func WithCaller(ctx context.Context, caller *CallerIdentity) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller stored by WithCaller, or nil
+func CallerFromContext(ctx context.Context) *CallerIdentity {
+	caller, _ := ctx.Value(callerKey{}).(*CallerIdentity)
+	return caller
+}
+
+// DefaultRolesHeader carries the caller's roles, comma-separated, unless
+// auth.roles_header names another header
+const DefaultRolesHeader = "X-User-Roles"
+
+// HasCapability reports whether the identity carries the named capability
+func (c *CallerIdentity) HasCapability(name string) bool {
+	for _, capability := range c.Capabilities {
+		if capability == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Verified reports whether the identity was proven by a JWT or API key,
+// rather than taken from request headers
+func (c *CallerIdentity) Verified() bool {
+	return c.Source == "jwt" || c.Source == "api_key"
 }
 
 // ResolutionError represents an error during resolution
@@ -69,10 +174,58 @@ func (e *NotFoundError) Error() string {
 	return "Path not found: " + e.Path
 }
 
+// SubResourceNotFoundError is returned when a moniker names a sub-resource
+// its binding does not define
+type SubResourceNotFoundError struct {
+	Path        string
+	SubResource string
+	Available   []string
+}
+
+func (e *SubResourceNotFoundError) Error() string {
+	return fmt.Sprintf("Sub-resource '%s' not found for %s (available: %s)", e.SubResource, e.Path, strings.Join(e.Available, ", "))
+}
+
+// SunsetError is returned when a moniker resolves to a deprecated node whose
+// sunset deadline has passed
+type SunsetError struct {
+	Path              string
+	SunsetDeadline    string
+	Successor         *string
+	MigrationGuideURL *string
+}
+
+func (e *SunsetError) Error() string {
+	msg := fmt.Sprintf("'%s' was sunset on %s", e.Path, e.SunsetDeadline)
+	if e.Successor != nil {
+		msg += fmt.Sprintf("; migrate to '%s'", *e.Successor)
+	}
+	return msg
+}
+
+// GoneError is returned when the requested path is an archived node. Its
+// ancestors' bindings are not used in its place.
+type GoneError struct {
+	Path              string
+	SuccessorChain    []string // From Path to the node that replaces it, if any
+	MigrationGuideURL *string
+}
+
+func (e *GoneError) Error() string {
+	msg := fmt.Sprintf("'%s' is archived", e.Path)
+	if len(e.SuccessorChain) > 1 {
+		msg += fmt.Sprintf("; use '%s'", e.SuccessorChain[len(e.SuccessorChain)-1])
+	}
+	return msg
+}
+
 // AccessDeniedError represents an access policy violation
 type AccessDeniedError struct {
 	Message       string
 	EstimatedRows *int
+	AllowedHours  *[2]int       // Set when denied by access_policy.allowed_hours
+	CurrentHour   *int          // UTC hour of the denial, with AllowedHours
+	Trace         *ResolveTrace // The steps up to the denial, for an explained resolve
 }
 
 func (e *AccessDeniedError) Error() string {
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/adapters"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// FrequencyParam is the moniker query param selecting a data frequency,
+// e.g. ?frequency=weekly. It is checked against the binding's frequencies
+// rather than its allowed_params.
+const FrequencyParam = "frequency"
+
+// versionDateLayout is the layout of date@ values and of the dates derived from them
+const versionDateLayout = "20060102"
+
+// Version kinds, by the form of the date@ value
+const (
+	VersionAbsolute = "absolute" // date@20260101
+	VersionLookback = "lookback" // date@3M, date@1Y, date@2W, date@5D
+	VersionLatest   = "latest"   // date@latest
+	VersionPrevious = "previous" // date@previous, the day before the as-of date
+	VersionAll      = "all"      // date@all, every version, with no date range
+)
+
+// VersionInfo describes the version a moniker asked for, for clients that
+// build their own queries. Dates are YYYYMMDD; relative versions are counted
+// back from AsOf. Resolved is the newest listed version date@latest was
+// substituted with, when the binding lists versions and caches them.
+type VersionInfo struct {
+	DateParam     string `json:"date_param,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	AsOf          string `json:"as_of"`
+	LookbackStart string `json:"lookback_start,omitempty"`
+	LookbackEnd   string `json:"lookback_end,omitempty"`
+	Resolved      string `json:"resolved,omitempty"`
+	Frequency     string `json:"frequency,omitempty"`
+}
+
+// SetClock replaces the clock that relative date@ versions are counted from
+// and access_policy.allowed_hours are checked against; the default is
+// time.Now. Intended for tests and for replaying a past day.
+func (s *MonikerService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// clock returns the current time from the service clock
+func (s *MonikerService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// asOf returns the as-of date, in UTC
+func (s *MonikerService) asOf() time.Time {
+	y, mo, d := s.clock().UTC().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+}
+
+// resolveVersion works out the date range of the moniker's date@ value and
+// the frequency it asks for. It returns nil when the moniker has neither and
+// the binding declares no frequencies.
+func (s *MonikerService) resolveVersion(path string, config map[string]interface{}, m *moniker.Moniker) (*VersionInfo, error) {
+	supported := catalog.SupportedFrequencies(config)
+	frequency, asked := m.Params[FrequencyParam]
+	if m.DateParam == nil && !asked && supported == nil {
+		return nil, nil
+	}
+
+	asOf := s.asOf()
+	version := &VersionInfo{AsOf: asOf.Format(versionDateLayout)}
+	if m.DateParam != nil && strings.EqualFold(*m.DateParam, VersionAll) {
+		version.DateParam = *m.DateParam
+		version.Kind = VersionAll
+	} else if m.DateParam != nil {
+		start, kind, err := versionStart(*m.DateParam, asOf)
+		if err != nil {
+			return nil, &ResolutionError{Message: fmt.Sprintf("Invalid date@%s: %v", *m.DateParam, err)}
+		}
+		end := asOf
+		if kind != VersionLookback {
+			end = start
+		}
+		version.DateParam = *m.DateParam
+		version.Kind = kind
+		version.LookbackStart = start.Format(versionDateLayout)
+		version.LookbackEnd = end.Format(versionDateLayout)
+	}
+
+	switch {
+	case asked && !catalog.IsFrequency(frequency):
+		return nil, &ResolutionError{Message: fmt.Sprintf("Unknown frequency '%s' (want one of %s)",
+			frequency, strings.Join(catalog.Frequencies, ", "))}
+	case asked && supported != nil && !containsString(supported, frequency):
+		return nil, &ResolutionError{Message: fmt.Sprintf("Frequency '%s' is not supported by %s (supported: %s)",
+			frequency, path, strings.Join(supported, ", "))}
+	case !asked && supported != nil:
+		frequency = supported[0]
+	}
+	version.Frequency = frequency
+	return version, nil
+}
+
+// versionStart returns the first day a date@ value covers and its kind
+func versionStart(value string, asOf time.Time) (time.Time, string, error) {
+	switch lower := strings.ToLower(value); lower {
+	case "latest":
+		return asOf, VersionLatest, nil
+	case "previous":
+		return asOf.AddDate(0, 0, -1), VersionPrevious, nil
+	}
+
+	unit := strings.ToUpper(value[len(value)-1:])
+	if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && strings.Contains("DWMY", unit) {
+		switch unit {
+		case "D":
+			return asOf.AddDate(0, 0, -n), VersionLookback, nil
+		case "W":
+			return asOf.AddDate(0, 0, -7*n), VersionLookback, nil
+		case "M":
+			return asOf.AddDate(0, -n, 0), VersionLookback, nil
+		default:
+			return asOf.AddDate(-n, 0, 0), VersionLookback, nil
+		}
+	}
+
+	date, err := time.Parse(versionDateLayout, value)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("not a calendar date")
+	}
+	return date, VersionAbsolute, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// versionsCachePrefix namespaces listed versions in the shared cache
+const versionsCachePrefix = "versions:"
+
+// VersionsNotSupportedError is a version listing of a binding whose adapter
+// cannot list versions
+type VersionsNotSupportedError struct {
+	Path       string
+	SourceType string
+}
+
+func (e *VersionsNotSupportedError) Error() string {
+	return fmt.Sprintf("source type %s cannot list versions (binding of %s)", e.SourceType, e.Path)
+}
+
+// VersionsResult lists the versions of a moniker's data, oldest first, with
+// the newest marked latest
+type VersionsResult struct {
+	Moniker     string          `json:"moniker"`
+	Path        string          `json:"path"`
+	BindingPath string          `json:"binding_path"`
+	Versions    []ListedVersion `json:"versions"`
+	Latest      string          `json:"latest,omitempty"`
+}
+
+// ListedVersion is one version of a binding's data
+type ListedVersion struct {
+	Version string `json:"version"`
+	Latest  bool   `json:"latest,omitempty"`
+}
+
+// versionSource is the binding, and the moniker selecting from it, whose
+// versions are listed
+type versionSource struct {
+	m           *moniker.Moniker
+	path        string
+	bindingPath string
+	segments    []string // Path segments below bindingPath
+	binding     *catalog.SourceBinding
+	config      map[string]interface{}
+	params      map[string]string
+}
+
+// Versions resolves a moniker, with the same access checks as Resolve, and
+// lists the versions of its binding's data through the adapter of its source
+// type: the rows of config.versions_query for SQL bindings, the values of
+// config.version_column for static and excel bindings. Versions that are
+// dates are YYYYMMDD.
+func (s *MonikerService) Versions(ctx context.Context, monikerStr string, caller *CallerIdentity) (*VersionsResult, error) {
+	result, err := s.Resolve(ctx, monikerStr, caller)
+	if err != nil {
+		return nil, err
+	}
+	if result.binding == nil {
+		return nil, &VersionsNotSupportedError{Path: result.BindingPath, SourceType: result.Source.SourceType}
+	}
+	m, err := parseMoniker(monikerStr)
+	if err != nil {
+		return nil, err
+	}
+	params, err := bindQueryParams(result.Path, result.config, m)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.listVersions(ctx, &versionSource{
+		m:           m,
+		path:        result.Path,
+		bindingPath: result.BindingPath,
+		segments:    segmentsBelowBinding(result.SubPath, result.SubResource),
+		binding:     result.binding,
+		config:      result.config,
+		params:      params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listed := &VersionsResult{
+		Moniker:     result.Moniker,
+		Path:        result.Path,
+		BindingPath: result.BindingPath,
+		Versions:    make([]ListedVersion, len(versions)),
+	}
+	for i, v := range versions {
+		listed.Versions[i] = ListedVersion{Version: v, Latest: i == len(versions)-1}
+	}
+	if len(versions) > 0 {
+		listed.Latest = versions[len(versions)-1]
+	}
+	return listed, nil
+}
+
+// listVersions lists the versions of a binding, oldest first, through its
+// adapter. They are cached for the binding's cache.ttl_seconds when its
+// cache is enabled. versions_query runs under the same allowed operations as
+// the binding's query.
+func (s *MonikerService) listVersions(ctx context.Context, src *versionSource) ([]string, error) {
+	adapter, _ := s.adapters.Get(src.binding.SourceType)
+	lister, ok := adapter.(adapters.VersionLister)
+	if !ok {
+		return nil, &VersionsNotSupportedError{Path: src.bindingPath, SourceType: string(src.binding.SourceType)}
+	}
+
+	req := &adapters.Request{
+		Path:        src.path,
+		BindingPath: src.bindingPath,
+		Segments:    src.segments,
+		Binding:     src.binding,
+	}
+	if query, ok := src.config[catalog.VersionsQueryKey].(string); ok {
+		bound, err := bindQuery(src.path, src.binding.SourceType, query, src.m, src.params, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		allowed := adapters.AllowedOperations(src.binding)
+		if op := adapters.Disallowed(adapters.Operations(src.binding.SourceType, bound.Query, src.config), allowed); op != "" {
+			return nil, &OperationNotAllowedError{Operation: op, BindingPath: src.bindingPath, Allowed: allowed}
+		}
+		req.Query, req.ParamStyle = bound.Query, bound.Style
+		for _, p := range bound.Params {
+			req.Params = append(req.Params, adapters.Param{Name: p.Name, Value: p.Value})
+		}
+	}
+
+	ttl := s.versionsCacheTTL(src.binding.Cache)
+	// The binding itself identifies it across namespaces and reloads; the
+	// path leads, so InvalidatePath can find it
+	key := fmt.Sprintf("%s%s:%d:%p:%s:%q:%v", versionsCachePrefix, src.path, s.catalog.Generation(),
+		src.binding, strings.Join(src.segments, "/"), req.Query, req.Params)
+	if ttl > 0 {
+		if cached, ok := s.cache.Get(key); ok {
+			return cached.([]string), nil
+		}
+	}
+
+	if err := s.setAdapterConfig(req, src.config); err != nil {
+		return nil, err
+	}
+	versions, err := lister.ListVersions(ctx, req)
+	if err != nil {
+		var reqErr *adapters.RequestError
+		if errors.As(err, &reqErr) {
+			return nil, reqErr
+		}
+		return nil, &FetchError{BindingPath: src.bindingPath, Err: err}
+	}
+	versions = adapters.SortVersions(versions)
+	if ttl > 0 {
+		s.cache.SetWithTTL(key, versions, ttl)
+	}
+	return versions, nil
+}
+
+// versionsCacheTTL returns how long listed versions are cached: the
+// binding's cache.ttl_seconds, or cache.default_ttl_seconds when it sets
+// none, and 0 when its cache is not enabled
+func (s *MonikerService) versionsCacheTTL(cfg *catalog.QueryCacheConfig) time.Duration {
+	if s.cache == nil || cfg == nil || !cfg.Enabled {
+		return 0
+	}
+	if cfg.TTLSeconds > 0 {
+		return time.Duration(cfg.TTLSeconds) * time.Second
+	}
+	if s.config != nil {
+		return time.Duration(s.config.Cache.DefaultTTLSeconds) * time.Second
+	}
+	return 0
+}
+
+// resolveLatest substitutes the newest listed version of a binding into a
+// date@latest version, when its versions can be cached. A failed listing
+// leaves the as-of date in place and returns a warning.
+func (s *MonikerService) resolveLatest(ctx context.Context, version *VersionInfo, src *versionSource) *ResolveWarning {
+	if version == nil || version.Kind != VersionLatest || s.versionsCacheTTL(src.binding.Cache) == 0 {
+		return nil
+	}
+	if _, ok := s.adapters.Get(src.binding.SourceType); !ok {
+		return nil
+	}
+	versions, err := s.listVersions(ctx, src)
+	if err == nil && len(versions) == 0 {
+		err = fmt.Errorf("no versions listed")
+	}
+	if err != nil {
+		if _, unsupported := err.(*VersionsNotSupportedError); unsupported {
+			return nil
+		}
+		return &ResolveWarning{
+			Kind:    WarningVersion,
+			Message: fmt.Sprintf("date@latest resolved to the as-of date %s: %v", version.AsOf, err),
+			Path:    src.bindingPath,
+		}
+	}
+	latest := versions[len(versions)-1]
+	version.Resolved = latest
+	if _, err := time.Parse(versionDateLayout, latest); err == nil {
+		version.LookbackStart, version.LookbackEnd = latest, latest
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/catalog"
+)
+
+// Kinds of ResolveWarning
+const (
+	WarningDeprecated = "deprecated"  // A node on the resolution path is deprecated
+	WarningLargeQuery = "large_query" // The access policy estimates more rows than max_rows_warn
+	WarningConnection = "connection"  // The binding config lacks keys needed to build the DSN
+	WarningVersion    = "version"     // date@latest could not be resolved to a listed version
+)
+
+// ResolveWarning is a condition the caller should act on that did not stop
+// the moniker resolving
+type ResolveWarning struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"` // Node the warning is about
+
+	// Deprecation fields, copied from the node
+	Successor         *string `json:"successor,omitempty"`
+	SunsetDeadline    *string `json:"sunset_deadline,omitempty"`
+	MigrationGuideURL *string `json:"migration_guide_url,omitempty"`
+
+	EstimatedRows *int `json:"estimated_rows,omitempty"` // Set on large_query warnings
+}
+
+// deprecationWarning returns the warning for resolving through node, or nil
+// when it is not deprecated. The message is the node's deprecation_message
+// when set.
+func deprecationWarning(node *catalog.CatalogNode, now time.Time) *ResolveWarning {
+	if node == nil || node.Status != catalog.NodeStatusDeprecated {
+		return nil
+	}
+	msg := fmt.Sprintf("'%s' is deprecated", node.Path)
+	if node.Successor != nil {
+		msg += fmt.Sprintf("; migrate to '%s'", *node.Successor)
+	}
+	if node.SunsetDeadline != nil {
+		if sunsetPassed(node, now) {
+			msg += fmt.Sprintf(" (sunset %s has passed)", *node.SunsetDeadline)
+		} else {
+			msg += fmt.Sprintf(" before %s", *node.SunsetDeadline)
+		}
+	}
+	if node.DeprecationMessage != nil {
+		msg = *node.DeprecationMessage
+	}
+	return &ResolveWarning{
+		Kind:              WarningDeprecated,
+		Message:           msg,
+		Path:              node.Path,
+		Successor:         node.Successor,
+		SunsetDeadline:    node.SunsetDeadline,
+		MigrationGuideURL: node.MigrationGuideURL,
+	}
+}
+
+// Deprecated reports whether the moniker resolved through a deprecated node,
+// whether it was served as it is or redirected to its successor
+func (r *ResolveResult) Deprecated() bool {
+	for _, w := range r.Warnings {
+		if w.Kind == WarningDeprecated {
+			return true
+		}
+	}
+	return false
+}
+
+// WarningHeader formats the deprecation warnings of a result as a Warning
+// header value (RFC 7234 warn-code 299), or "" when there are none
+func (r *ResolveResult) WarningHeader() string {
+	values := make([]string, 0, len(r.Warnings))
+	for _, w := range r.Warnings {
+		if w.Kind != WarningDeprecated {
+			continue
+		}
+		text := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(w.Message)
+		values = append(values, fmt.Sprintf(`299 - "%s"`, text))
+	}
+	return strings.Join(values, ", ")
+}
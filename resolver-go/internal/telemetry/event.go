@@ -0,0 +1,132 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/moniker"
+)
+
+// Operation is what a client did with a moniker
+type Operation string
+
+// Operations an AccessEvent may report
+const (
+	OperationResolve  Operation = "resolve"
+	OperationRead     Operation = "read" // Rows read from the source the moniker resolved to
+	OperationFetch    Operation = "fetch"
+	OperationList     Operation = "list"
+	OperationDescribe Operation = "describe"
+	OperationLineage  Operation = "lineage"
+)
+
+var operations = []Operation{OperationResolve, OperationRead, OperationFetch, OperationList, OperationDescribe, OperationLineage}
+
+// Outcome is how an access ended
+type Outcome string
+
+// Outcomes an AccessEvent may report
+const (
+	OutcomeSuccess      Outcome = "success"
+	OutcomeNotFound     Outcome = "not_found"
+	OutcomeError        Outcome = "error"
+	OutcomeUnauthorized Outcome = "unauthorized"
+	OutcomeRateLimited  Outcome = "rate_limited"
+)
+
+var outcomes = []Outcome{OutcomeSuccess, OutcomeNotFound, OutcomeError, OutcomeUnauthorized, OutcomeRateLimited}
+
+// AccessEvent is one access to a moniker reported by a client. Moniker,
+// Operation and Outcome are required; Time and Path are filled in by
+// Normalize.
+type AccessEvent struct {
+	Time       time.Time `json:"time,omitempty"`
+	Moniker    string    `json:"moniker"`
+	Path       string    `json:"path,omitempty"` // Canonical path of Moniker
+	Caller     string    `json:"caller,omitempty"`
+	ClientApp  string    `json:"client_app,omitempty"`
+	Operation  Operation `json:"operation"`
+	RowCount   *int64    `json:"row_count,omitempty"`
+	DurationMs *float64  `json:"duration_ms,omitempty"`
+	Outcome    Outcome   `json:"outcome"`
+	Error      string    `json:"error,omitempty"` // Error message of a failed access
+}
+
+// InvalidEventError lists what is wrong with an AccessEvent
+type InvalidEventError struct {
+	Missing []string          // Required fields that are empty
+	Invalid map[string]string // Field -> why its value was refused
+}
+
+func (e *InvalidEventError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(e.Missing, ", "))
+	}
+	fields := make([]string, 0, len(e.Invalid))
+	for field := range e.Invalid {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		problems = append(problems, fmt.Sprintf("%s: %s", field, e.Invalid[field]))
+	}
+	return "invalid access event: " + strings.Join(problems, "; ")
+}
+
+// Normalize checks e against the schema and fills in its derived fields: Path
+// from Moniker, Time with now when unset and Caller with anonymous when
+// unset. It returns an *InvalidEventError when e is refused.
+func (e *AccessEvent) Normalize(now time.Time) error {
+	invalid := &InvalidEventError{Invalid: make(map[string]string)}
+	e.Moniker = strings.TrimSpace(e.Moniker)
+	if e.Moniker == "" {
+		invalid.Missing = append(invalid.Missing, "moniker")
+	} else if m, err := moniker.ParseMoniker(e.Moniker); err != nil {
+		invalid.Invalid["moniker"] = err.Error()
+	} else {
+		e.Path = m.CanonicalPath()
+	}
+	if e.Operation == "" {
+		invalid.Missing = append(invalid.Missing, "operation")
+	} else if !contains(operations, e.Operation) {
+		invalid.Invalid["operation"] = fmt.Sprintf("unknown operation %q", e.Operation)
+	}
+	if e.Outcome == "" {
+		invalid.Missing = append(invalid.Missing, "outcome")
+	} else if !contains(outcomes, e.Outcome) {
+		invalid.Invalid["outcome"] = fmt.Sprintf("unknown outcome %q", e.Outcome)
+	}
+	if e.RowCount != nil && *e.RowCount < 0 {
+		invalid.Invalid["row_count"] = "must not be negative"
+	}
+	if e.DurationMs != nil && *e.DurationMs < 0 {
+		invalid.Invalid["duration_ms"] = "must not be negative"
+	}
+	if len(invalid.Missing) > 0 || len(invalid.Invalid) > 0 {
+		return invalid
+	}
+
+	if e.Time.IsZero() {
+		e.Time = now
+	}
+	e.Time = e.Time.UTC()
+	if e.Caller = strings.TrimSpace(e.Caller); e.Caller == "" {
+		e.Caller = AnonymousCaller
+	}
+	return nil
+}
+
+// AnonymousCaller is the caller of events that name none
+const AnonymousCaller = "anonymous"
+
+func contains[T comparable](values []T, v T) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink persists access events. Write is called on the request path, so it
+// should not block for long.
+type Sink interface {
+	Write(event AccessEvent) error
+	Close() error
+}
+
+// Reader is implemented by sinks that can read back the events they hold,
+// which GET /telemetry/stats aggregates
+type Reader interface {
+	// Events returns the events at or after since, oldest first
+	Events(since time.Time) ([]AccessEvent, error)
+}
+
+// Discard is a Sink that drops every event; it is used while telemetry is
+// disabled
+var Discard Sink = discard{}
+
+type discard struct{}
+
+func (discard) Write(AccessEvent) error { return nil }
+func (discard) Close() error            { return nil }
+
+// DefaultRingCapacity is the number of events a RingSink keeps when its
+// capacity is not set
+const DefaultRingCapacity = 10000
+
+// RingSink keeps the most recent events in memory, overwriting the oldest
+// once it holds its capacity
+type RingSink struct {
+	mu     sync.Mutex
+	events []AccessEvent
+	next   int // Index the next event is written to once events is full
+}
+
+// NewRingSink creates a ring holding up to capacity events; 0 means
+// DefaultRingCapacity
+func NewRingSink(capacity int) *RingSink {
+	if capacity <= 0 {
+		capacity = DefaultRingCapacity
+	}
+	return &RingSink{events: make([]AccessEvent, 0, capacity)}
+}
+
+// Write implements Sink
+func (s *RingSink) Write(event AccessEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) < cap(s.events) {
+		s.events = append(s.events, event)
+		return nil
+	}
+	s.events[s.next] = event
+	s.next = (s.next + 1) % len(s.events)
+	return nil
+}
+
+// Events implements Reader
+func (s *RingSink) Events(since time.Time) ([]AccessEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []AccessEvent
+	for i := range s.events {
+		if event := s.events[(s.next+i)%len(s.events)]; !event.Time.Before(since) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Len returns the number of events the ring holds
+func (s *RingSink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.events)
+}
+
+// Close implements Sink
+func (s *RingSink) Close() error { return nil }
+
+// ConsoleSink writes events to a writer, normally stdout, as JSON lines and
+// keeps the most recent ones in a RingSink to read back
+type ConsoleSink struct {
+	mu   sync.Mutex
+	w    io.Writer
+	ring *RingSink
+}
+
+// NewConsoleSink creates a sink writing to w and keeping up to capacity
+// events; 0 means DefaultRingCapacity
+func NewConsoleSink(w io.Writer, capacity int) *ConsoleSink {
+	return &ConsoleSink{w: w, ring: NewRingSink(capacity)}
+}
+
+// Write implements Sink
+func (s *ConsoleSink) Write(event AccessEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.ring.Write(event)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// Events implements Reader
+func (s *ConsoleSink) Events(since time.Time) ([]AccessEvent, error) {
+	return s.ring.Events(since)
+}
+
+// Close implements Sink
+func (s *ConsoleSink) Close() error { return nil }
+
+// JSONLSink appends events to a file as JSON lines and reads them back by
+// scanning it. Each event is flushed as it is written, so the file survives
+// a crash; lines that do not parse, e.g. one cut short by a crash, are
+// skipped when reading.
+type JSONLSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	w    *bufio.Writer
+}
+
+// NewJSONLSink opens path for appending, creating it when it does not exist
+func NewJSONLSink(path string) (*JSONLSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open telemetry file: %w", err)
+	}
+	return &JSONLSink{path: path, file: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Write implements Sink
+func (s *JSONLSink) Write(event AccessEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.w.Write(line)
+	s.w.WriteByte('\n')
+	return s.w.Flush()
+}
+
+// Events implements Reader
+func (s *JSONLSink) Events(since time.Time) ([]AccessEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("read telemetry file: %w", err)
+	}
+	defer f.Close()
+
+	var events []AccessEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		var event AccessEvent
+		if len(line) == 0 || json.Unmarshal(line, &event) != nil {
+			continue
+		}
+		if !event.Time.Before(since) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read telemetry file: %w", err)
+	}
+	return events, nil
+}
+
+// Close implements Sink
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flushErr := s.w.Flush()
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
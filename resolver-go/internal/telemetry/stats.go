@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats aggregates the access events of a window
+type Stats struct {
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Events    int             `json:"events"`
+	ByPath    []Bucket        `json:"by_path"` // Keyed by path prefix
+	ByCaller  []Bucket        `json:"by_caller"`
+	ByOutcome map[Outcome]int `json:"by_outcome"`
+}
+
+// Bucket aggregates the events sharing a key, most events first
+type Bucket struct {
+	Key           string          `json:"key"`
+	Events        int             `json:"events"`
+	Rows          int64           `json:"rows"` // Sum of the reported row counts
+	Outcomes      map[Outcome]int `json:"outcomes"`
+	AvgDurationMs *float64        `json:"avg_duration_ms,omitempty"` // Over the events reporting a duration
+
+	durationMs float64
+	timed      int
+}
+
+// StatsQuery selects the events Aggregate counts
+type StatsQuery struct {
+	From, To time.Time
+
+	// Depth is the number of path segments events are grouped by in ByPath;
+	// 0 groups by full path
+	Depth int
+
+	// Prefix, when set, counts only events at or beneath that path
+	Prefix string
+}
+
+// Aggregate counts the events in [q.From, q.To] by path prefix, caller and
+// outcome
+func Aggregate(events []AccessEvent, q StatsQuery) *Stats {
+	stats := &Stats{From: q.From, To: q.To, ByOutcome: make(map[Outcome]int)}
+	prefix := strings.Trim(q.Prefix, "/")
+	byPath := make(map[string]*Bucket)
+	byCaller := make(map[string]*Bucket)
+	for _, event := range events {
+		if event.Time.Before(q.From) || event.Time.After(q.To) {
+			continue
+		}
+		if prefix != "" && event.Path != prefix && !strings.HasPrefix(event.Path, prefix+"/") {
+			continue
+		}
+		stats.Events++
+		stats.ByOutcome[event.Outcome]++
+		add(byPath, pathPrefix(event.Path, q.Depth), event)
+		add(byCaller, event.Caller, event)
+	}
+	stats.ByPath = sortBuckets(byPath)
+	stats.ByCaller = sortBuckets(byCaller)
+	return stats
+}
+
+// pathPrefix returns the first depth segments of path, or all of them when
+// depth is 0
+func pathPrefix(path string, depth int) string {
+	if depth <= 0 {
+		return path
+	}
+	segments := strings.SplitN(path, "/", depth+1)
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	return strings.Join(segments, "/")
+}
+
+func add(buckets map[string]*Bucket, key string, event AccessEvent) {
+	b := buckets[key]
+	if b == nil {
+		b = &Bucket{Key: key, Outcomes: make(map[Outcome]int)}
+		buckets[key] = b
+	}
+	b.Events++
+	b.Outcomes[event.Outcome]++
+	if event.RowCount != nil {
+		b.Rows += *event.RowCount
+	}
+	if event.DurationMs != nil {
+		b.durationMs += *event.DurationMs
+		b.timed++
+	}
+}
+
+func sortBuckets(buckets map[string]*Bucket) []Bucket {
+	sorted := make([]Bucket, 0, len(buckets))
+	for _, b := range buckets {
+		if b.timed > 0 {
+			avg := b.durationMs / float64(b.timed)
+			b.AvgDurationMs = &avg
+		}
+		sorted = append(sorted, *b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Events != sorted[j].Events {
+			return sorted[i].Events > sorted[j].Events
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
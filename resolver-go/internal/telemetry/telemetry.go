@@ -1,6 +1,12 @@
 package telemetry
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
 	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
 )
 
@@ -24,12 +30,96 @@ func NewNoOpEmitter() Emitter {
 	return &noOpEmitter{}
 }
 
-// NewFromConfig creates an emitter from telemetry config.
-// Returns a no-op emitter if telemetry is disabled or config is nil.
-func NewFromConfig(cfg *config.TelemetryConfig) (Emitter, error) {
+// Sink types of TelemetryConfig.SinkType
+const (
+	SinkTypeMemory  = "memory"  // A RingSink of max_queue_size events; the default
+	SinkTypeFile    = "file"    // A JSONLSink at sink_config.path
+	SinkTypeConsole = "console" // A ConsoleSink on stdout keeping max_queue_size events
+)
+
+// ErrStatsUnavailable is returned by Recorder.Stats when its sink cannot
+// read events back
+var ErrStatsUnavailable = errors.New("the telemetry sink does not keep events")
+
+// Recorder validates access events and writes them to its sink. It is the
+// Emitter of a resolver with telemetry configured.
+type Recorder struct {
+	sink     Sink
+	now      func() time.Time
+	recorded atomic.Int64
+	errors   atomic.Int64
+}
+
+// NewRecorder creates a recorder writing to sink
+func NewRecorder(sink Sink) *Recorder {
+	return &Recorder{sink: sink, now: time.Now}
+}
+
+// Record normalizes event and writes it to the sink. It returns an
+// *InvalidEventError for an event that does not match the schema.
+func (r *Recorder) Record(event *AccessEvent) error {
+	if err := event.Normalize(r.now()); err != nil {
+		return err
+	}
+	if err := r.sink.Write(*event); err != nil {
+		r.errors.Add(1)
+		return fmt.Errorf("write access event: %w", err)
+	}
+	r.recorded.Add(1)
+	return nil
+}
+
+// Stats aggregates the events of the window ending now. It returns
+// ErrStatsUnavailable when the sink is not a Reader.
+func (r *Recorder) Stats(window time.Duration, depth int, prefix string) (*Stats, error) {
+	reader, ok := r.sink.(Reader)
+	if !ok {
+		return nil, ErrStatsUnavailable
+	}
+	to := r.now().UTC()
+	from := to.Add(-window)
+	events, err := reader.Events(from)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(events, StatsQuery{From: from, To: to, Depth: depth, Prefix: prefix}), nil
+}
+
+// Stop implements Emitter, closing the sink
+func (r *Recorder) Stop() {
+	if err := r.sink.Close(); err != nil {
+		r.errors.Add(1)
+	}
+}
+
+// GetStats implements Emitter. Events are written as they are recorded, so
+// none are dropped or queued.
+func (r *Recorder) GetStats() (emitted, dropped, errors, queueDepth int64) {
+	return r.recorded.Load(), 0, r.errors.Load(), 0
+}
+
+// NewFromConfig creates a recorder from telemetry config. Its sink discards
+// events if telemetry is disabled or config is nil.
+func NewFromConfig(cfg *config.TelemetryConfig) (*Recorder, error) {
 	if cfg == nil || !cfg.Enabled {
-		return NewNoOpEmitter(), nil
+		return NewRecorder(Discard), nil
+	}
+	switch cfg.SinkType {
+	case "", SinkTypeMemory:
+		return NewRecorder(NewRingSink(cfg.MaxQueueSize)), nil
+	case SinkTypeFile:
+		path, _ := cfg.SinkConfig["path"].(string)
+		if path == "" {
+			return nil, fmt.Errorf("sink_type file requires sink_config.path")
+		}
+		sink, err := NewJSONLSink(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewRecorder(sink), nil
+	case SinkTypeConsole:
+		return NewRecorder(NewConsoleSink(os.Stdout, cfg.MaxQueueSize)), nil
+	default:
+		return nil, fmt.Errorf("unsupported sink_type %q (expected %s, %s or %s)", cfg.SinkType, SinkTypeMemory, SinkTypeFile, SinkTypeConsole)
 	}
-	// Telemetry sinks not yet implemented — return no-op
-	return NewNoOpEmitter(), nil
 }
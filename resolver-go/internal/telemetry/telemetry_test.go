@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ganizanisitara/open-moniker/resolver-go/internal/config"
+)
+
+func event(path, caller string, outcome Outcome, at time.Time) AccessEvent {
+	return AccessEvent{Time: at, Moniker: path, Path: path, Caller: caller, Operation: OperationRead, Outcome: outcome}
+}
+
+func TestNormalizeReportsMissingAndInvalidFields(t *testing.T) {
+	e := &AccessEvent{Operation: "copy", RowCount: new(int64)}
+	*e.RowCount = -1
+	err := e.Normalize(time.Now())
+	var invalid *InvalidEventError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an InvalidEventError, got %v", err)
+	}
+	if len(invalid.Missing) != 2 || invalid.Missing[0] != "moniker" || invalid.Missing[1] != "outcome" {
+		t.Errorf("expected moniker and outcome missing, got %v", invalid.Missing)
+	}
+	if invalid.Invalid["operation"] == "" || invalid.Invalid["row_count"] == "" {
+		t.Errorf("expected operation and row_count invalid, got %v", invalid.Invalid)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	e = &AccessEvent{Moniker: "moniker://prices/equity/AAPL?format=json", Operation: OperationFetch, Outcome: OutcomeSuccess}
+	if err := e.Normalize(now); err != nil {
+		t.Fatal(err)
+	}
+	if e.Path != "prices/equity/AAPL" || !e.Time.Equal(now) || e.Caller != AnonymousCaller {
+		t.Errorf("expected derived fields filled in, got %+v", e)
+	}
+}
+
+func TestRingSinkKeepsNewestEvents(t *testing.T) {
+	s := NewRingSink(3)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		s.Write(event("prices", "u", OutcomeSuccess, start.Add(time.Duration(i)*time.Minute)))
+	}
+	events, _ := s.Events(start.Add(3 * time.Minute))
+	if s.Len() != 3 || len(events) != 2 || !events[0].Time.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the last two events oldest first, got %d held and %+v", s.Len(), events)
+	}
+}
+
+func TestConsoleSinkWritesLinesAndKeepsEvents(t *testing.T) {
+	var out bytes.Buffer
+	s := NewConsoleSink(&out, 0)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Write(event("prices/equity", "alice", OutcomeSuccess, start))
+	s.Write(event("prices/fx", "bob", OutcomeError, start.Add(time.Hour)))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var first AccessEvent
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil || first.Caller != "alice" {
+		t.Errorf("expected one JSON line per event, got %q", out.String())
+	}
+	if events, _ := s.Events(start.Add(time.Minute)); len(events) != 1 || events[0].Caller != "bob" {
+		t.Errorf("expected the events read back, got %+v", events)
+	}
+}
+
+func TestNewFromConfigSinkTypes(t *testing.T) {
+	for sinkType, ok := range map[string]bool{"": true, SinkTypeMemory: true, SinkTypeConsole: true, "zmq": false} {
+		r, err := NewFromConfig(&config.TelemetryConfig{Enabled: true, SinkType: sinkType})
+		if (err == nil) != ok {
+			t.Errorf("sink_type %q: expected ok=%v, got %v", sinkType, ok, err)
+			continue
+		}
+		if ok {
+			if _, isReader := r.sink.(Reader); !isReader {
+				t.Errorf("sink_type %q: expected a sink /telemetry/stats can read", sinkType)
+			}
+		}
+	}
+}
+
+func TestJSONLSinkReadsBackEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	s, err := NewJSONLSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Write(event("prices/equity", "alice", OutcomeSuccess, start))
+	s.Write(event("prices/fx", "bob", OutcomeError, start.Add(time.Hour)))
+	s.Close()
+
+	// A line cut short by a crash is skipped, and appending resumes after it
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("{\"time\": \"2026\n")
+	f.Close()
+	if s, err = NewJSONLSink(path); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Write(event("rates", "alice", OutcomeSuccess, start.Add(2*time.Hour)))
+
+	events, err := s.Events(start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Caller != "bob" || events[1].Path != "rates" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rows := int64(10)
+	e := event("prices/equity/AAPL", "alice", OutcomeSuccess, at)
+	e.RowCount = &rows
+	events := []AccessEvent{
+		e,
+		event("prices/fx", "bob", OutcomeError, at),
+		event("rates/curves", "alice", OutcomeSuccess, at),
+		event("prices/equity", "alice", OutcomeSuccess, at.Add(-48*time.Hour)), // Outside the window
+	}
+	stats := Aggregate(events, StatsQuery{From: at.Add(-time.Hour), To: at, Depth: 1})
+	if stats.Events != 3 || stats.ByOutcome[OutcomeSuccess] != 2 || stats.ByOutcome[OutcomeError] != 1 {
+		t.Fatalf("unexpected totals %+v", stats)
+	}
+	if len(stats.ByPath) != 2 || stats.ByPath[0].Key != "prices" || stats.ByPath[0].Events != 2 || stats.ByPath[0].Rows != 10 {
+		t.Errorf("expected prices first with 2 events and 10 rows, got %+v", stats.ByPath)
+	}
+	if stats.ByCaller[0].Key != "alice" || stats.ByCaller[0].Events != 2 {
+		t.Errorf("expected alice first, got %+v", stats.ByCaller)
+	}
+
+	stats = Aggregate(events, StatsQuery{From: at.Add(-time.Hour), To: at, Depth: 2, Prefix: "prices"})
+	if stats.Events != 2 || len(stats.ByPath) != 2 || stats.ByPath[0].Key != "prices/equity" {
+		t.Errorf("expected prices events by two segments, got %+v", stats.ByPath)
+	}
+}
@@ -1,7 +1,9 @@
 #!/bin/bash
-echo "=== Testing All 19 Routes ==="
+echo "=== Testing All 28 Routes ==="
 echo ""
 
+failed=0
+
 test_route() {
     local method=$1
     local url=$2
@@ -15,33 +17,48 @@ test_route() {
         status=$(curl -s -o /dev/null -w "%{http_code}" -X $method -H "Content-Type: application/json" -d "$data" "http://localhost:8053$url")
     fi
 
-    if [ $status -eq 200 ] || [ $status -eq 202 ] || [ $status -eq 501 ]; then
+    if [ $status -eq 200 ] || [ $status -eq 201 ] || [ $status -eq 202 ] || [ $status -eq 207 ] || [ $status -eq 501 ]; then
         echo "✅ $status"
     else
         echo "❌ $status"
+        failed=$((failed + 1))
     fi
 }
 
 test_route GET /health "" "1. GET /health"
-test_route GET /resolve/benchmarks.constituents/SP500/20260101 "" "2. GET /resolve/{path}"
+test_route GET /resolve/benchmarks/constituents/SP500/20260101 "" "2. GET /resolve/{path}"
 test_route GET /describe/benchmarks "" "3. GET /describe/{path}"
 test_route GET /list/benchmarks "" "4. GET /list/{path}"
-test_route GET /lineage/benchmarks.constituents "" "5. GET /lineage/{path}"
-test_route POST /telemetry/access '{"moniker":"test"}' "6. POST /telemetry/access"
+test_route GET /lineage/benchmarks/constituents "" "5. GET /lineage/{path}"
+test_route POST /telemetry/access '{"moniker":"benchmarks/constituents/SP500","operation":"read","outcome":"success"}' "6. POST /telemetry/access"
 test_route GET /catalog "" "7. GET /catalog"
 test_route GET /catalog/search?q=benchmark "" "8. GET /catalog/search"
 test_route GET /catalog/stats "" "9. GET /catalog/stats"
-test_route POST /resolve/batch '{"monikers":["benchmarks"]}' "10. POST /resolve/batch"
+test_route POST /resolve/batch '{"monikers":["benchmarks/constituents/SP500/20260101"]}' "10. POST /resolve/batch"
 test_route PUT /catalog/benchmarks/status '{"status":"active"}' "11. PUT /catalog/{path}/status"
 test_route GET /catalog/benchmarks/audit "" "12. GET /catalog/{path}/audit"
-test_route GET /fetch/benchmarks "" "13. GET /fetch/{path}"
+test_route GET /fetch/benchmarks/constituents/SP500/20260101 "" "13. GET /fetch/{path}"
 test_route GET /cache/status "" "14. GET /cache/status"
 test_route POST /cache/refresh/benchmarks "" "15. POST /cache/refresh/{path}"
 test_route GET /metadata/benchmarks "" "16. GET /metadata/{path}"
 test_route GET /tree/benchmarks "" "17. GET /tree/{path}"
 test_route GET /tree "" "18. GET /tree"
 test_route GET /ui "" "19. GET /ui"
+test_route GET /estimate/benchmarks/constituents/SP500/20260101 "" "20. GET /estimate/{moniker}"
+test_route GET /versions/benchmarks/constituents/SP500 "" "21. GET /versions/{path}"
+test_route POST /catalog "{\"path\":\"benchmarks/route_check_$$\",\"display_name\":\"Route check\"}" "22. POST /catalog"
+test_route PATCH /catalog/benchmarks '{"description":"Benchmark data"}' "23. PATCH /catalog/{path}"
+test_route PUT /catalog/benchmarks/ownership '{"support_channel":"#benchmarks"}' "24. PUT /catalog/{path}/ownership"
+test_route GET /openapi.json "" "25. GET /openapi.json"
+test_route GET /docs "" "26. GET /docs"
+test_route GET /health/live "" "27. GET /health/live"
+test_route GET /health/ready "" "28. GET /health/ready"
 
 echo ""
 echo "=== Summary ==="
-echo "All 19 routes implemented and responding"
+if [ $failed -eq 0 ]; then
+    echo "All 28 routes implemented and responding"
+else
+    echo "$failed of 28 routes failed"
+    exit 1
+fi